@@ -34,6 +34,7 @@ import (
 	"github.com/contiv/vpp/plugins/contivconf"
 	"github.com/contiv/vpp/plugins/controller"
 	controller_api "github.com/contiv/vpp/plugins/controller/api"
+	"github.com/contiv/vpp/plugins/crdstatus"
 	"github.com/contiv/vpp/plugins/devicemanager"
 	contivgrpc "github.com/contiv/vpp/plugins/grpc"
 	"github.com/contiv/vpp/plugins/idalloc"
@@ -54,6 +55,7 @@ import (
 	linux_nsplugin "go.ligato.io/vpp-agent/v3/plugins/linux/nsplugin"
 	rest_plugin "go.ligato.io/vpp-agent/v3/plugins/restapi"
 	"go.ligato.io/vpp-agent/v3/plugins/telemetry"
+	vpp_abfplugin "go.ligato.io/vpp-agent/v3/plugins/vpp/abfplugin"
 	vpp_aclplugin "go.ligato.io/vpp-agent/v3/plugins/vpp/aclplugin"
 	vpp_ifplugin "go.ligato.io/vpp-agent/v3/plugins/vpp/ifplugin"
 	vpp_l2plugin "go.ligato.io/vpp-agent/v3/plugins/vpp/l2plugin"
@@ -89,6 +91,7 @@ type ContivAgent struct {
 	VPPL3Plugin         *vpp_l3plugin.L3Plugin
 	VPPNATPlugin        *vpp_natplugin.NATPlugin
 	VPPACLPlugin        *vpp_aclplugin.ACLPlugin
+	VPPABFPlugin        *vpp_abfplugin.ABFPlugin
 	VPPSTNPlugin        *vpp_stnplugin.STNPlugin
 	VPPPuntPlugin       *vpp_puntplugin.PuntPlugin
 	VPPSRPlugin         *vpp_srplugin.SRPlugin
@@ -110,6 +113,7 @@ type ContivAgent struct {
 	SFC           *sfc.Plugin
 	DeviceManager *devicemanager.DeviceManager
 	BGPReflector  *bgpreflector.BGPReflector
+	CRDStatus     *crdstatus.CRDStatus
 }
 
 func (c *ContivAgent) String() string {
@@ -200,19 +204,20 @@ func main() {
 	statsCollector := &statscollector.DefaultPlugin
 	statsCollector.IPNet = ipNetPlugin
 
-	policyPlugin := policy.NewPlugin(policy.UseDeps(func(deps *policy.Deps) {
+	servicePlugin := service.NewPlugin(service.UseDeps(func(deps *service.Deps) {
 		deps.ContivConf = contivConf
 		deps.IPAM = ipamPlugin
 		deps.IPNet = ipNetPlugin
+		deps.NodeSync = nodeSyncPlugin
 		deps.PodManager = podManager
 	}))
 
-	servicePlugin := service.NewPlugin(service.UseDeps(func(deps *service.Deps) {
+	policyPlugin := policy.NewPlugin(policy.UseDeps(func(deps *policy.Deps) {
 		deps.ContivConf = contivConf
 		deps.IPAM = ipamPlugin
 		deps.IPNet = ipNetPlugin
-		deps.NodeSync = nodeSyncPlugin
 		deps.PodManager = podManager
+		deps.Service = servicePlugin
 	}))
 
 	sfcPlugin := sfc.NewPlugin(sfc.UseDeps(func(deps *sfc.Deps) {
@@ -226,6 +231,12 @@ func main() {
 
 	bgpReflector := bgpreflector.NewPlugin(bgpreflector.UseDeps(func(deps *bgpreflector.Deps) {
 		deps.ContivConf = contivConf
+		deps.IPAM = ipamPlugin
+		deps.IPNet = ipNetPlugin
+	}))
+
+	crdStatus := crdstatus.NewPlugin(crdstatus.UseDeps(func(deps *crdstatus.Deps) {
+		deps.DB = &etcd.DefaultPlugin
 	}))
 
 	controller := controller.NewPlugin(controller.UseDeps(func(deps *controller.Deps) {
@@ -244,6 +255,7 @@ func main() {
 			policyPlugin,
 			bgpReflector,
 			statsCollector,
+			crdStatus,
 		}
 		deps.ExtSources = []controller.ExternalConfigSource{
 			contivGRPC,
@@ -260,6 +272,7 @@ func main() {
 	contivGRPC.EventLoop = controller
 	deviceManager.EventLoop = controller
 	bgpReflector.EventLoop = controller
+	policyPlugin.EventLoop = controller
 	servicePlugin.ConfigRetriever = controller
 	sfcPlugin.ConfigRetriever = controller
 
@@ -280,6 +293,7 @@ func main() {
 		VPPL3Plugin:         &vpp_l3plugin.DefaultPlugin,
 		VPPNATPlugin:        &vpp_natplugin.DefaultPlugin,
 		VPPACLPlugin:        &vpp_aclplugin.DefaultPlugin,
+		VPPABFPlugin:        &vpp_abfplugin.DefaultPlugin,
 		VPPSTNPlugin:        &vpp_stnplugin.DefaultPlugin,
 		VPPPuntPlugin:       &vpp_puntplugin.DefaultPlugin,
 		VPPSRPlugin:         &vpp_srplugin.DefaultPlugin,
@@ -297,6 +311,7 @@ func main() {
 		Service:             servicePlugin,
 		SFC:                 sfcPlugin,
 		BGPReflector:        bgpReflector,
+		CRDStatus:           crdStatus,
 	}
 
 	a := agent.NewAgent(agent.AllPlugins(contivAgent), agent.StartTimeout(getStartupTimeout()))
@@ -23,9 +23,12 @@ import (
 	"github.com/golang/protobuf/proto"
 
 	customnetmodel "github.com/contiv/vpp/plugins/crd/handler/customnetwork/model"
+	egressgwmodel "github.com/contiv/vpp/plugins/crd/handler/egressgateway/model"
 	extifmodel "github.com/contiv/vpp/plugins/crd/handler/externalinterface/model"
+	hostpolicymodel "github.com/contiv/vpp/plugins/crd/handler/hostpolicy/model"
 	nodeconfig "github.com/contiv/vpp/plugins/crd/handler/nodeconfig/model"
 	sfcmodel "github.com/contiv/vpp/plugins/crd/handler/servicefunctionchain/model"
+	adminpolicymodel "github.com/contiv/vpp/plugins/ksr/model/adminpolicy"
 	epmodel "github.com/contiv/vpp/plugins/ksr/model/endpoints"
 	nsmodel "github.com/contiv/vpp/plugins/ksr/model/namespace"
 	nodemodel "github.com/contiv/vpp/plugins/ksr/model/node"
@@ -82,6 +85,11 @@ func GetDBResources() []*DBResource {
 			ProtoMessageName: proto.MessageName((*policymodel.Policy)(nil)),
 			KeyPrefix:        policymodel.KeyPrefix(),
 		},
+		{
+			Keyword:          adminpolicymodel.AdminPolicyKeyword,
+			ProtoMessageName: proto.MessageName((*adminpolicymodel.AdminPolicy)(nil)),
+			KeyPrefix:        adminpolicymodel.KeyPrefix(),
+		},
 		{
 			Keyword:          svcmodel.ServiceKeyword,
 			ProtoMessageName: proto.MessageName((*svcmodel.Service)(nil)),
@@ -107,6 +115,16 @@ func GetDBResources() []*DBResource {
 			ProtoMessageName: proto.MessageName((*sfcmodel.ServiceFunctionChain)(nil)),
 			KeyPrefix:        sfcmodel.KeyPrefix(),
 		},
+		{
+			Keyword:          egressgwmodel.Keyword,
+			ProtoMessageName: proto.MessageName((*egressgwmodel.EgressGateway)(nil)),
+			KeyPrefix:        egressgwmodel.KeyPrefix(),
+		},
+		{
+			Keyword:          hostpolicymodel.Keyword,
+			ProtoMessageName: proto.MessageName((*hostpolicymodel.HostPolicy)(nil)),
+			KeyPrefix:        hostpolicymodel.KeyPrefix(),
+		},
 		{
 			Keyword:          ipalloc.Keyword,
 			ProtoMessageName: proto.MessageName((*ipalloc.CustomIPAllocation)(nil)),
@@ -36,6 +36,9 @@ import (
 // maxPortNum is the maximum possible port number.
 const maxPortNum = uint32(^uint16(0))
 
+// sctpProtocolNumber is the IANA-assigned IP protocol number of SCTP.
+const sctpProtocolNumber = 132
+
 // ConnectionAction is one of DENY-SYN, DENY-SYN-ACK, ALLOW, FAILURE.
 type ConnectionAction int
 
@@ -561,7 +564,7 @@ func (mae *MockACLEngine) evalACL(acl *vpp_acl.ACL, srcIP, dstIP net.IP,
 		// check L4
 		switch protocol {
 		case renderer.TCP:
-			if ipRule.Udp != nil {
+			if ipRule.Udp != nil || ipRule.Ip.GetProtocol() != 0 {
 				// not matching
 				continue
 			}
@@ -593,7 +596,7 @@ func (mae *MockACLEngine) evalACL(acl *vpp_acl.ACL, srcIP, dstIP net.IP,
 			}
 
 		case renderer.UDP:
-			if ipRule.Tcp != nil {
+			if ipRule.Tcp != nil || ipRule.Ip.GetProtocol() != 0 {
 				// not matching
 				continue
 			}
@@ -624,11 +627,21 @@ func (mae *MockACLEngine) evalACL(acl *vpp_acl.ACL, srcIP, dstIP net.IP,
 				}
 			}
 
-		case renderer.OTHER:
+		case renderer.SCTP:
 			if ipRule.Tcp != nil || ipRule.Udp != nil {
 				// not matching
 				continue
 			}
+			if protocol := ipRule.Ip.GetProtocol(); protocol != 0 && protocol != sctpProtocolNumber {
+				// not matching
+				continue
+			}
+
+		case renderer.OTHER:
+			if ipRule.Tcp != nil || ipRule.Udp != nil || ipRule.Ip.GetProtocol() != 0 {
+				// not matching
+				continue
+			}
 		}
 
 		// Rule matches the packet!
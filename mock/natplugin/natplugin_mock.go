@@ -30,6 +30,10 @@ import (
 
 	"go.ligato.io/cn-infra/v2/datasync/syncbase"
 	"go.ligato.io/cn-infra/v2/logging"
+	vpp_abf "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/abf"
+	vpp_acl "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/acl"
+	vpp_interfaces "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/interfaces"
+	vpp_l3 "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/l3"
 	vpp_nat "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/nat"
 )
 
@@ -53,6 +57,15 @@ type MockNatPlugin struct {
 	nat44Dnat        map[string]*vpp_nat.DNat44 // label -> DNAT config
 	staticMappings   *StaticMappings
 	identityMappings *IdentityMappings
+
+	/* ACLs (used to enforce LB source ranges and to select egress traffic for ABF) */
+	acls map[string]*vpp_acl.ACL // ACL name -> ACL config
+
+	/* ABFs (used to steer egress traffic towards egress gateways) */
+	abfs map[string]*vpp_abf.ABF // ABF index -> ABF config
+
+	/* VRFs of egress gateways (address pools, loopbacks, routes, ...) */
+	egressGwVrfCfg map[string]proto.Message // key -> config
 }
 
 // NewMockNatPlugin is a constructor for MockNatPlugin.
@@ -81,6 +94,9 @@ func (mnt *MockNatPlugin) resetNat44Dnat() {
 func (mnt *MockNatPlugin) Reset() {
 	mnt.resetNat44Global()
 	mnt.resetNat44Dnat()
+	mnt.acls = make(map[string]*vpp_acl.ACL)
+	mnt.abfs = make(map[string]*vpp_abf.ABF)
+	mnt.egressGwVrfCfg = make(map[string]proto.Message)
 }
 
 // ApplyTxn applies transaction created by the service configurator.
@@ -133,6 +149,26 @@ func (mnt *MockNatPlugin) ApplyTxn(txn *localclient.Txn, latestRevs *syncbase.Pr
 				// shallow copy the configuration
 				mnt.nat44Dnat[dnatConfig.Label] = dnatConfig
 
+			} else if aclName, isACL := vpp_acl.ModelACL.ParseKey(key); isACL {
+				// put ACL config
+				acl, isACLConfig := value.(*vpp_acl.ACL)
+				if !isACLConfig {
+					return errors.New("failed to cast ACL config value")
+				}
+				mnt.acls[aclName] = acl
+
+			} else if abfIndex, isABF := vpp_abf.ModelABF.ParseKey(key); isABF {
+				// put ABF config
+				abf, isABFConfig := value.(*vpp_abf.ABF)
+				if !isABFConfig {
+					return errors.New("failed to cast ABF config value")
+				}
+				mnt.abfs[abfIndex] = abf
+
+			} else if isEgressGatewayVrfConfig(value) {
+				// put config of egress gateway VRF
+				mnt.egressGwVrfCfg[key] = value
+
 			} else {
 				return errors.New("non-NAT changed in txn")
 			}
@@ -238,6 +274,46 @@ func (mnt *MockNatPlugin) ApplyTxn(txn *localclient.Txn, latestRevs *syncbase.Pr
 				}
 			}
 
+		} else if aclName, isACL := vpp_acl.ModelACL.ParseKey(key); isACL {
+			if value != nil {
+				// put ACL config
+				acl, isACLConfig := value.(*vpp_acl.ACL)
+				if !isACLConfig {
+					return errors.New("failed to cast ACL config value")
+				}
+				mnt.acls[aclName] = acl
+			} else {
+				// remove ACL config
+				if _, hasACL := mnt.acls[aclName]; !hasACL {
+					return errors.New("attempt to remove ACL config which does not exist")
+				}
+				delete(mnt.acls, aclName)
+			}
+
+		} else if abfIndex, isABF := vpp_abf.ModelABF.ParseKey(key); isABF {
+			if value != nil {
+				// put ABF config
+				abf, isABFConfig := value.(*vpp_abf.ABF)
+				if !isABFConfig {
+					return errors.New("failed to cast ABF config value")
+				}
+				mnt.abfs[abfIndex] = abf
+			} else {
+				// remove ABF config
+				if _, hasABF := mnt.abfs[abfIndex]; !hasABF {
+					return errors.New("attempt to remove ABF config which does not exist")
+				}
+				delete(mnt.abfs, abfIndex)
+			}
+
+		} else if isEgressGatewayVrfConfig(value) {
+			// put config of egress gateway VRF
+			mnt.egressGwVrfCfg[key] = value
+
+		} else if _, hasCfg := mnt.egressGwVrfCfg[key]; hasCfg && value == nil {
+			// remove config of egress gateway VRF
+			delete(mnt.egressGwVrfCfg, key)
+
 		} else {
 			return errors.New("non-NAT changed in txn")
 		}
@@ -247,6 +323,17 @@ func (mnt *MockNatPlugin) ApplyTxn(txn *localclient.Txn, latestRevs *syncbase.Pr
 	return nil
 }
 
+// isEgressGatewayVrfConfig returns true if the given value is one of the configuration
+// items rendered for VRFs of egress gateways.
+func isEgressGatewayVrfConfig(value proto.Message) bool {
+	switch value.(type) {
+	case *vpp_nat.Nat44AddressPool, *vpp_interfaces.Interface, *vpp_l3.VrfTable,
+		*vpp_l3.Route, *vpp_l3.ARPEntry:
+		return true
+	}
+	return false
+}
+
 func (mnt *MockNatPlugin) putGlobalConfig(message proto.Message) error {
 	natGlobal, isNatGlobal := message.(*vpp_nat.Nat44Global)
 	if !isNatGlobal {
@@ -360,8 +447,11 @@ func (mnt *MockNatPlugin) dnatToStaticMappings(dnat *vpp_nat.DNat44) (*StaticMap
 			if local.LocalPort > uint32(^uint16(0)) {
 				return nil, errors.New("invalid local port number")
 			}
-			if (staticMapping.ExternalPort != 0 && (local.Probability == 0 || local.Probability > uint32(^uint8(0)))) ||
-				(staticMapping.ExternalPort == 0 && local.Probability != 0) {
+			// (single backend is configured with probability 0)
+			multipleLocals := len(staticMapping.LocalIps) > 1
+			if (staticMapping.ExternalPort != 0 && multipleLocals && local.Probability == 0) ||
+				local.Probability > uint32(^uint8(0)) ||
+				((staticMapping.ExternalPort == 0 || !multipleLocals) && local.Probability != 0) {
 				return nil, errors.New("invalid local probability")
 			}
 			sm.Locals = append(sm.Locals, &Local{
@@ -512,3 +602,45 @@ func (mnt *MockNatPlugin) NumOfIdentityMappings() int {
 func (mnt *MockNatPlugin) HasIdentityMapping(im *IdentityMapping) bool {
 	return mnt.identityMappings.Has(im)
 }
+
+// GetACL returns ACL with the given name or nil if it is not installed.
+func (mnt *MockNatPlugin) GetACL(name string) *vpp_acl.ACL {
+	return mnt.acls[name]
+}
+
+// GetABF returns ABF with the given index or nil if it is not installed.
+func (mnt *MockNatPlugin) GetABF(index uint32) *vpp_abf.ABF {
+	return mnt.abfs[fmt.Sprint(index)]
+}
+
+// GetNat44AddressPools returns NAT44 address pools configured for the given VRF.
+func (mnt *MockNatPlugin) GetNat44AddressPools(vrf uint32) (pools []*vpp_nat.Nat44AddressPool) {
+	for _, value := range mnt.egressGwVrfCfg {
+		if pool, isPool := value.(*vpp_nat.Nat44AddressPool); isPool && pool.VrfId == vrf {
+			pools = append(pools, pool)
+		}
+	}
+	return pools
+}
+
+// GetInterface returns configuration of the given (egress gateway) interface.
+func (mnt *MockNatPlugin) GetInterface(ifName string) *vpp_interfaces.Interface {
+	iface, _ := mnt.egressGwVrfCfg[vpp_interfaces.InterfaceKey(ifName)].(*vpp_interfaces.Interface)
+	return iface
+}
+
+// GetRoutes returns routes configured for the given (egress gateway) VRF.
+func (mnt *MockNatPlugin) GetRoutes(vrf uint32) (routes []*vpp_l3.Route) {
+	for _, value := range mnt.egressGwVrfCfg {
+		if route, isRoute := value.(*vpp_l3.Route); isRoute && route.VrfId == vrf {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// NumOfEgressGatewayVrfItems returns the number of configuration items rendered
+// for VRFs of egress gateways.
+func (mnt *MockNatPlugin) NumOfEgressGatewayVrfItems() int {
+	return len(mnt.egressGwVrfCfg)
+}
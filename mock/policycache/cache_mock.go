@@ -2,7 +2,10 @@ package policycache
 
 import (
 	controller "github.com/contiv/vpp/plugins/controller/api"
+	hostpolicymodel "github.com/contiv/vpp/plugins/crd/handler/hostpolicy/model"
+	adminpolicymodel "github.com/contiv/vpp/plugins/ksr/model/adminpolicy"
 	nsmodel "github.com/contiv/vpp/plugins/ksr/model/namespace"
+	nodemodel "github.com/contiv/vpp/plugins/ksr/model/node"
 	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
 	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
 	"github.com/contiv/vpp/plugins/policy/cache"
@@ -85,11 +88,57 @@ func (mpc *MockPolicyCache) LookupPoliciesByPod(pod podmodel.ID) (policies []pol
 	return nil
 }
 
+// LookupPoliciesByPeerPod is not implemented by the mock.
+func (mpc *MockPolicyCache) LookupPoliciesByPeerPod(pod *podmodel.Pod) (policies []policymodel.ID) {
+	return nil
+}
+
+// LookupPoliciesByPeerNamespace is not implemented by the mock.
+func (mpc *MockPolicyCache) LookupPoliciesByPeerNamespace(ns *nsmodel.Namespace) (policies []policymodel.ID) {
+	return nil
+}
+
 // ListAllPolicies is not implemented by the mock.
 func (mpc *MockPolicyCache) ListAllPolicies() (policies []policymodel.ID) {
 	return nil
 }
 
+// LookupPodsByAdminSelector is not implemented by the mock.
+func (mpc *MockPolicyCache) LookupPodsByAdminSelector(namespaces *adminpolicymodel.AdminPolicy_LabelSelector,
+	pods *adminpolicymodel.AdminPolicy_PodSelector) []podmodel.ID {
+	return nil
+}
+
+// LookupAdminPolicy is not implemented by the mock.
+func (mpc *MockPolicyCache) LookupAdminPolicy(name string) (found bool, data *adminpolicymodel.AdminPolicy) {
+	return false, nil
+}
+
+// LookupAdminPoliciesByPod is not implemented by the mock.
+func (mpc *MockPolicyCache) LookupAdminPoliciesByPod(pod podmodel.ID) (policies []string) {
+	return nil
+}
+
+// LookupAdminPoliciesByPeerPod is not implemented by the mock.
+func (mpc *MockPolicyCache) LookupAdminPoliciesByPeerPod(pod *podmodel.Pod) (policies []string) {
+	return nil
+}
+
+// LookupAdminPoliciesByPeerNamespace is not implemented by the mock.
+func (mpc *MockPolicyCache) LookupAdminPoliciesByPeerNamespace(ns *nsmodel.Namespace) (policies []string) {
+	return nil
+}
+
+// LookupAdminPoliciesBySubjectNamespace is not implemented by the mock.
+func (mpc *MockPolicyCache) LookupAdminPoliciesBySubjectNamespace(ns *nsmodel.Namespace) (policies []string) {
+	return nil
+}
+
+// ListAllAdminPolicies is not implemented by the mock.
+func (mpc *MockPolicyCache) ListAllAdminPolicies() (policies []string) {
+	return nil
+}
+
 // LookupNamespace is not implemented by the mock.
 func (mpc *MockPolicyCache) LookupNamespace(namespace nsmodel.ID) (found bool, data *nsmodel.Namespace) {
 	return false, data
@@ -99,3 +148,23 @@ func (mpc *MockPolicyCache) LookupNamespace(namespace nsmodel.ID) (found bool, d
 func (mpc *MockPolicyCache) ListAllNamespaces() (namespaces []nsmodel.ID) {
 	return nil
 }
+
+// LookupHostPolicy is not implemented by the mock.
+func (mpc *MockPolicyCache) LookupHostPolicy(name string) (found bool, data *hostpolicymodel.HostPolicy) {
+	return false, data
+}
+
+// LookupHostPoliciesByNode is not implemented by the mock.
+func (mpc *MockPolicyCache) LookupHostPoliciesByNode(node string) (policies []string) {
+	return nil
+}
+
+// ListAllHostPolicies is not implemented by the mock.
+func (mpc *MockPolicyCache) ListAllHostPolicies() (policies []string) {
+	return nil
+}
+
+// LookupNode is not implemented by the mock.
+func (mpc *MockPolicyCache) LookupNode(name string) (found bool, data *nodemodel.Node) {
+	return false, data
+}
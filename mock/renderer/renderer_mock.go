@@ -44,6 +44,10 @@ type MockRenderer struct {
 	name   string
 	Log    logging.Logger
 	config map[podmodel.ID]*PodConfig // Pod ID -> config
+	host   []*renderer.ContivRule     // host rules (nil = not isolated)
+
+	// protocols with L4 ports which cannot be matched
+	unmatchedPorts map[renderer.ProtocolType]struct{}
 }
 
 // MockRendererTxn is a mock implementation for the renderer's transaction.
@@ -52,6 +56,8 @@ type MockRendererTxn struct {
 	renderer *MockRenderer
 	resync   bool
 	config   map[podmodel.ID]*PodConfig // Pod ID -> config
+	host     []*renderer.ContivRule
+	withHost bool
 }
 
 // PodConfig stores configuration for a single pod.
@@ -80,6 +86,25 @@ func (mr *MockRenderer) NewTxn(resync bool) renderer.Txn {
 	}
 }
 
+// DisablePortMatching makes the mock renderer report L4 ports of the given
+// protocol as not possible to match.
+func (mr *MockRenderer) DisablePortMatching(protocol renderer.ProtocolType) {
+	mr.lock.Lock()
+	defer mr.lock.Unlock()
+	if mr.unmatchedPorts == nil {
+		mr.unmatchedPorts = make(map[renderer.ProtocolType]struct{})
+	}
+	mr.unmatchedPorts[protocol] = struct{}{}
+}
+
+// CanMatchPorts returns false if port matching was disabled for the protocol.
+func (mr *MockRenderer) CanMatchPorts(protocol renderer.ProtocolType) bool {
+	mr.lock.Lock()
+	defer mr.lock.Unlock()
+	_, unmatched := mr.unmatchedPorts[protocol]
+	return !unmatched
+}
+
 // GetPodIP returns the pod IP + masklen as provided by the configurator.
 func (mr *MockRenderer) GetPodIP(pod podmodel.ID) (ip string, masklen int) {
 	mr.Log.WithFields(logging.Fields{
@@ -118,7 +143,28 @@ func (mr *MockRenderer) TestTraffic(pod podmodel.ID, direction TrafficDirection,
 	} else {
 		rules = config.egress
 	}
+	return testRules(rules, srcIP, destIP, protocol, srcPort, destPort)
+}
+
+// IsHostIsolated returns true if the host rules were rendered.
+func (mr *MockRenderer) IsHostIsolated() bool {
+	mr.lock.Lock()
+	defer mr.lock.Unlock()
+	return mr.host != nil
+}
 
+// TestHostTraffic allows to simulate a traffic destined to the node and test
+// what the outcome would be with the rendered host rules.
+func (mr *MockRenderer) TestHostTraffic(srcIP *net.IP, destIP *net.IP, protocol renderer.ProtocolType,
+	srcPort uint16, destPort uint16) TrafficAction {
+	mr.lock.Lock()
+	defer mr.lock.Unlock()
+	return testRules(mr.host, srcIP, destIP, protocol, srcPort, destPort)
+}
+
+// testRules returns the action of the first rule matching the given traffic.
+func testRules(rules []*renderer.ContivRule, srcIP *net.IP, destIP *net.IP, protocol renderer.ProtocolType,
+	srcPort uint16, destPort uint16) TrafficAction {
 	for _, rule := range rules {
 		if len(rule.SrcNetwork.IP) > 0 && !rule.SrcNetwork.Contains(*srcIP) {
 			continue
@@ -133,8 +179,14 @@ func (mr *MockRenderer) TestTraffic(pod podmodel.ID, direction TrafficDirection,
 			if rule.SrcPort != 0 && rule.SrcPort != srcPort {
 				continue
 			}
-			if rule.DestPort != 0 && rule.DestPort != destPort {
-				continue
+			if rule.DestPort != 0 {
+				if rule.DestPortEnd > rule.DestPort {
+					if destPort < rule.DestPort || destPort > rule.DestPortEnd {
+						continue
+					}
+				} else if rule.DestPort != destPort {
+					continue
+				}
 			}
 		}
 		// Match!
@@ -166,6 +218,17 @@ func (mrt *MockRendererTxn) Render(pod podmodel.ID, podIP *net.IPNet, ingress []
 	return mrt
 }
 
+// RenderHost just stores host rules to be rendered.
+func (mrt *MockRendererTxn) RenderHost(rules []*renderer.ContivRule) renderer.Txn {
+	mrt.Log.WithFields(logging.Fields{
+		"renderer": mrt.renderer.name,
+		"rules":    rules,
+	}).Debug("Mock RendererTxn RenderHost()")
+	mrt.host = rules
+	mrt.withHost = true
+	return mrt
+}
+
 // Commit runs mock rendering. The configuration is just stored in-memory.
 func (mrt *MockRendererTxn) Commit() error {
 	mrt.Log.WithFields(logging.Fields{
@@ -181,5 +244,8 @@ func (mrt *MockRendererTxn) Commit() error {
 			mrt.renderer.config[ifName] = config
 		}
 	}
+	if mrt.withHost {
+		mrt.renderer.host = mrt.host
+	}
 	return nil
 }
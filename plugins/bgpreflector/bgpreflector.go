@@ -17,6 +17,7 @@ package bgpreflector
 import (
 	"fmt"
 	"net"
+	"sync"
 
 	"golang.org/x/sys/unix"
 
@@ -26,58 +27,104 @@ import (
 	"go.ligato.io/vpp-agent/v3/pkg/models"
 	vpp_l3 "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/l3"
 
+	"github.com/contiv/vpp/plugins/bgpreflector/config"
 	"github.com/contiv/vpp/plugins/contivconf"
 	controller "github.com/contiv/vpp/plugins/controller/api"
+	customnetmodel "github.com/contiv/vpp/plugins/crd/handler/customnetwork/model"
+	"github.com/contiv/vpp/plugins/ipam"
+	"github.com/contiv/vpp/plugins/ipnet"
 )
 
 const (
 	// protocol number for routes installed by bird
 	// from /etc/iproute2/rt_protos
 	birdRouteProtoNumber = 12
+
+	// protocol number for routes exported by bgpreflector to the BGP speaker
+	// (allows the speaker to select them, e.g. using "krt_source = 170" filter in bird)
+	exportRouteProtoNumber = 170
 )
 
 // BGPReflector plugin implements BGP route reflection from Linux host to VPP.
 type BGPReflector struct {
 	Deps
 
-	routeUpdateCh   chan netlink.RouteUpdate
+	config *config.Config
+
 	routeSubsDoneCh chan struct{}
+
+	// protocols of the routes watched for changes, accessed also from the route watcher
+	watchLock        sync.Mutex
+	watchedProtocols map[int]struct{}
+
+	customNetworks  map[string]*customnetmodel.CustomNetwork // network name -> network
+	bgpNetworks     map[string]*bgpNetwork                   // network name -> BGP network
+	routeTables     map[int]*routeTable                      // Linux table -> mapping into VRF
+	reflectedRoutes map[string]controller.KeyValuePairs      // route ID -> VPP routes
 }
 
 // Deps lists dependencies of the BGPReflector plugin.
 type Deps struct {
 	infra.PluginDeps
 	ContivConf contivconf.API
+	IPAM       ipam.API
+	IPNet      ipnet.API
 	EventLoop  controller.EventLoop
 }
 
-// Init is NOOP - the plugin is initialized during the first resync.
+// bgpNetwork holds BGP interconnection of a L3 custom network as resolved on this node.
+type bgpNetwork struct {
+	name       string
+	config     *customnetmodel.CustomNetwork_BGP
+	vrf        uint32     // VRF of the network on VPP
+	linuxTable int        // routing table used to exchange routes with the BGP speaker
+	podSubnet  *net.IPNet // pod subnet of the network on this node
+}
+
+// routeTable holds mapping of a Linux routing table into a VPP VRF as resolved on this node.
+type routeTable struct {
+	linuxTable int
+	protocols  []int
+	metric     int
+	vrf        uint32
+}
+
+// Init loads the plugin configuration.
 func (br *BGPReflector) Init() (err error) {
+	br.config = config.DefaultConfig()
+	_, err = br.Cfg.LoadValue(br.config)
+	if err != nil {
+		return err
+	}
+	for _, table := range br.config.RouteTables {
+		br.Log.Infof("BGP route table mapping: %+v", *table)
+	}
 	return nil
 }
 
 // HandlesEvent selects:
 //   - any Resync event
 //   - BGPRouteUpdate
+//   - KubeStateChange for custom networks
 func (br *BGPReflector) HandlesEvent(event controller.Event) bool {
-	// do not handle any events if external IPAM is not in use
-	if !br.ContivConf.GetIPAMConfig().UseExternalIPAM {
-		return false
-	}
-
 	if event.Method() != controller.Update {
 		return true
 	}
 	if _, isBGPRouteChange := event.(*BGPRouteUpdate); isBGPRouteChange {
 		return true
 	}
+	if ksChange, isKSChange := event.(*controller.KubeStateChange); isKSChange &&
+		ksChange.Resource == customnetmodel.Keyword {
+		return true
+	}
 
 	// unhandled event
 	return false
 }
 
-// Resync resynchronizes BGPReflector against the BGP routes in the Linux host.
-// A set of already allocated pod IPs is updated.
+// Resync resynchronizes BGPReflector against the routes in the Linux host.
+// All routes of the mapped routing tables are re-read and reflected into VPP, routes of custom
+// networks interconnected via BGP are exported to the BGP speaker.
 func (br *BGPReflector) Resync(event controller.Event, kubeStateData controller.KubeStateData,
 	resyncCount int, txn controller.ResyncOperations) (err error) {
 
@@ -88,7 +135,24 @@ func (br *BGPReflector) Resync(event controller.Event, kubeStateData controller.
 		}
 	}()
 
-	if resyncCount == 1 {
+	// resync custom networks
+	br.customNetworks = make(map[string]*customnetmodel.CustomNetwork)
+	br.bgpNetworks = make(map[string]*bgpNetwork)
+	for _, nwProto := range kubeStateData[customnetmodel.Keyword] {
+		nw := nwProto.(*customnetmodel.CustomNetwork)
+		br.customNetworks[nw.Name] = nw
+		bgpNw, err := br.resolveBGPNetwork(nw)
+		if err != nil {
+			br.Log.Warnf("Error by resolving BGP interconnection of the network %s: %v - skipping", nw.Name, err)
+			continue
+		}
+		if bgpNw != nil {
+			br.bgpNetworks[nw.Name] = bgpNw
+		}
+	}
+	br.resolveRouteTables()
+
+	if len(br.routeTables) > 0 || len(br.bgpNetworks) > 0 {
 		// initialize route watcher
 		err := br.watchRoutes()
 		if err != nil {
@@ -97,41 +161,117 @@ func (br *BGPReflector) Resync(event controller.Event, kubeStateData controller.
 		}
 	}
 
-	// dump routes
-	routes, err := netlink.RouteList(nil, netlink.FAMILY_V4)
+	// reflect routes of the mapped routing tables
+	err = br.reflectRoutes()
 	if err != nil {
-		err := fmt.Errorf("error by listing BGP routes: %v", err)
 		br.Log.Error(err)
 		return err
 	}
-
-	// reflect bird routes to VPP
-	for _, r := range routes {
-		if r.Protocol == birdRouteProtoNumber && isValidRoute(r.Dst, r.Gw) {
-			key, route := br.vppRoute(r.Dst, r.Gw)
-			txn.Put(key, route)
-		}
+	for key, value := range br.renderedConfig() {
+		txn.Put(key, value)
 	}
 
-	return
+	// export pod subnets of custom networks
+	err = br.syncExportedRoutes()
+	if err != nil {
+		br.Log.Error(err)
+	}
+	return err
 }
 
-// Update handles BGPRouteUpdate events.
+// Update handles BGPRouteUpdate events and changes of custom networks.
 func (br *BGPReflector) Update(event controller.Event, txn controller.UpdateOperations) (changeDescription string, err error) {
 
 	if bgpRouteUpdate, isBGPRouteUpdate := event.(*BGPRouteUpdate); isBGPRouteUpdate {
 		br.Log.Debugf("BGP route update: %v", bgpRouteUpdate)
 
-		// add / delete route on VPP
-		key, route := br.vppRoute(bgpRouteUpdate.DstNetwork, bgpRouteUpdate.GwAddr)
+		if _, mapped := br.routeTables[bgpRouteUpdate.Table]; !mapped {
+			return
+		}
+		err = br.updateReflectedRoute(bgpRouteUpdate.Table, bgpRouteUpdate.DstNetwork, txn)
+		if err != nil {
+			br.Log.Error(err)
+			return "", err
+		}
 		if bgpRouteUpdate.Type == RouteAdd {
-			txn.Put(key, route)
 			changeDescription = "BGP route Add"
 		} else {
-			txn.Delete(key)
 			changeDescription = "BGP route Delete"
 		}
 	}
+
+	if ksChange, isKSChange := event.(*controller.KubeStateChange); isKSChange &&
+		ksChange.Resource == customnetmodel.Keyword {
+
+		// the state is keyed by network name, not by the KSR key
+		var (
+			nwName string
+			newNw  *customnetmodel.CustomNetwork
+		)
+		if ksChange.NewValue != nil {
+			newNw = ksChange.NewValue.(*customnetmodel.CustomNetwork)
+			nwName = newNw.Name
+		} else {
+			nwName = ksChange.PrevValue.(*customnetmodel.CustomNetwork).Name
+		}
+		_, wasBGPNetwork := br.bgpNetworks[nwName]
+		isBGPNetwork := newNw != nil && newNw.Bgp != nil
+		if !wasBGPNetwork && !isBGPNetwork && !br.isMappedNetwork(nwName) {
+			// the network is not relevant for route reflection
+			if newNw != nil {
+				br.customNetworks[nwName] = newNw
+			} else {
+				delete(br.customNetworks, nwName)
+			}
+			return
+		}
+
+		// configuration for the previous state of the network
+		prevConfig := br.renderedConfig()
+
+		// configuration for the new state of the network
+		delete(br.bgpNetworks, nwName)
+		delete(br.customNetworks, nwName)
+		if newNw != nil {
+			br.customNetworks[nwName] = newNw
+			bgpNw, err := br.resolveBGPNetwork(newNw)
+			if err != nil {
+				// the network is skipped, as in Resync
+				br.Log.Warnf("Error by resolving BGP interconnection of the network %s: %v - skipping", nwName, err)
+			} else if bgpNw != nil {
+				br.bgpNetworks[nwName] = bgpNw
+			}
+		}
+		br.resolveRouteTables()
+		if len(br.routeTables) > 0 || len(br.bgpNetworks) > 0 {
+			err = br.watchRoutes()
+			if err != nil {
+				br.Log.Error(err)
+				return "", err
+			}
+		}
+		err = br.reflectRoutes()
+		if err != nil {
+			br.Log.Error(err)
+			return "", err
+		}
+		newConfig := br.renderedConfig()
+
+		for key := range prevConfig {
+			if _, exists := newConfig[key]; !exists {
+				txn.Delete(key)
+			}
+		}
+		for key, value := range newConfig {
+			txn.Put(key, value)
+		}
+		err = br.syncExportedRoutes()
+		if err != nil {
+			br.Log.Error(err)
+			return "", err
+		}
+		changeDescription = "BGP interconnection of custom network update"
+	}
 	return
 }
 
@@ -151,61 +291,451 @@ func (br *BGPReflector) Close() error {
 	return nil
 }
 
-// watchRoutes watches host's routing table for BGP routes and generates BGPRouteUpdate events upon each BGP route change.
+// watchRoutes watches host's routing tables for BGP routes and generates BGPRouteUpdate events upon each BGP route change.
 func (br *BGPReflector) watchRoutes() error {
-	br.routeUpdateCh = make(chan netlink.RouteUpdate)
-	br.routeSubsDoneCh = make(chan struct{})
-
-	if err := netlink.RouteSubscribe(br.routeUpdateCh, br.routeSubsDoneCh); err != nil {
-		return fmt.Errorf("unable to subscribe the route watcher")
-	}
-
-	go func() {
-		for {
-			select {
-			case r := <-br.routeUpdateCh:
-				if r.Protocol == birdRouteProtoNumber && isValidRoute(r.Dst, r.Gw) {
-					br.Log.Debugf("BGP route update: proto=%d %v", r.Protocol, r)
-					ev := &BGPRouteUpdate{
-						DstNetwork: r.Dst,
-						GwAddr:     r.Gw,
-					}
-					if r.Type == unix.RTM_NEWROUTE {
-						br.Log.Debugf("New BGP route: %v", r)
-						ev.Type = RouteAdd
-
-					}
-					if r.Type == unix.RTM_DELROUTE {
-						br.Log.Debugf("Deleted BGP route: %v", r)
-						ev.Type = RouteDelete
-					}
-					br.EventLoop.PushEvent(ev)
+	if br.routeSubsDoneCh != nil {
+		// already watching
+		return nil
+	}
+	routeUpdateCh := make(chan netlink.RouteUpdate)
+	routeSubsDoneCh := make(chan struct{})
+
+	if err := netlink.RouteSubscribe(routeUpdateCh, routeSubsDoneCh); err != nil {
+		return fmt.Errorf("unable to subscribe the route watcher: %v", err)
+	}
+	br.routeSubsDoneCh = routeSubsDoneCh
+
+	go br.processRouteUpdates(routeUpdateCh, routeSubsDoneCh)
+	return nil
+}
+
+// processRouteUpdates converts route updates received from netlink into BGPRouteUpdate events.
+func (br *BGPReflector) processRouteUpdates(routeUpdateCh chan netlink.RouteUpdate, routeSubsDoneCh chan struct{}) {
+	for {
+		select {
+		case r, ok := <-routeUpdateCh:
+			if !ok {
+				select {
+				case <-routeSubsDoneCh:
+					// closed by the plugin
+					return
+				default:
 				}
+				// subscription was interrupted (e.g. by an error of the netlink socket) - re-subscribe
+				// and run full resync to reflect the route changes that could have been missed
+				br.Log.Warn("Route subscription was interrupted, re-subscribing")
+				routeUpdateCh = make(chan netlink.RouteUpdate)
+				if err := netlink.RouteSubscribe(routeUpdateCh, routeSubsDoneCh); err != nil {
+					br.Log.Errorf("Unable to re-subscribe the route watcher: %v", err)
+					return
+				}
+				br.EventLoop.PushEvent(&controller.HealingResync{
+					Type:  controller.AfterError,
+					Error: fmt.Errorf("route subscription was interrupted"),
+				})
+				continue
+			}
+			if r.Dst == nil || !br.isWatchedProtocol(r.Protocol) {
+				continue
+			}
+			br.Log.Debugf("BGP route update: proto=%d %v", r.Protocol, r)
+			ev := &BGPRouteUpdate{
+				DstNetwork: r.Dst,
+				GwAddr:     r.Gw,
+				Table:      r.Table,
 			}
+			if r.Type == unix.RTM_NEWROUTE {
+				br.Log.Debugf("New BGP route: %v", r)
+				ev.Type = RouteAdd
+			}
+			if r.Type == unix.RTM_DELROUTE {
+				br.Log.Debugf("Deleted BGP route: %v", r)
+				ev.Type = RouteDelete
+			}
+			br.EventLoop.PushEvent(ev)
+
+		case <-routeSubsDoneCh:
+			return
 		}
-	}()
+	}
+}
 
+// isWatchedProtocol returns true if changes of routes with the given protocol number are watched.
+func (br *BGPReflector) isWatchedProtocol(protocol int) bool {
+	br.watchLock.Lock()
+	defer br.watchLock.Unlock()
+
+	_, watched := br.watchedProtocols[protocol]
+	return watched
+}
+
+// resolveRouteTables resolves mapping of Linux routing tables into VPP VRFs from the plugin configuration,
+// external IPAM setting and custom networks importing routes via BGP.
+func (br *BGPReflector) resolveRouteTables() {
+	br.routeTables = make(map[int]*routeTable)
+
+	for _, tableCfg := range br.config.RouteTables {
+		if tableCfg.LinuxTable <= 0 {
+			br.Log.Warnf("Invalid routing table %d in the route table mapping - skipping", tableCfg.LinuxTable)
+			continue
+		}
+		if _, mapped := br.routeTables[tableCfg.LinuxTable]; mapped {
+			br.Log.Warnf("Routing table %d is mapped more than once - skipping", tableCfg.LinuxTable)
+			continue
+		}
+		vrf, err := br.resolveVRF(tableCfg.VRF)
+		if err != nil {
+			br.Log.Warnf("Routing table %d can not be mapped: %v - skipping", tableCfg.LinuxTable, err)
+			continue
+		}
+		protocols := tableCfg.Protocols
+		if len(protocols) == 0 {
+			protocols = []int{birdRouteProtoNumber}
+		}
+		br.routeTables[tableCfg.LinuxTable] = &routeTable{
+			linuxTable: tableCfg.LinuxTable,
+			protocols:  protocols,
+			metric:     tableCfg.Metric,
+			vrf:        vrf,
+		}
+	}
+
+	// with external IPAM, routes of the main table are reflected into the main VRF by default
+	if br.ContivConf.GetIPAMConfig().UseExternalIPAM {
+		if _, mapped := br.routeTables[unix.RT_TABLE_MAIN]; !mapped {
+			br.routeTables[unix.RT_TABLE_MAIN] = &routeTable{
+				linuxTable: unix.RT_TABLE_MAIN,
+				protocols:  []int{birdRouteProtoNumber},
+				vrf:        br.ContivConf.GetRoutingConfig().MainVRFID,
+			}
+		}
+	}
+
+	// routes imported by custom networks interconnected via BGP
+	for _, bgpNw := range br.bgpNetworks {
+		if !bgpNw.config.ImportRoutes {
+			continue
+		}
+		if _, mapped := br.routeTables[bgpNw.linuxTable]; mapped {
+			br.Log.Warnf("Routing table %d of the network %s is already mapped in the configuration - "+
+				"routes will not be imported", bgpNw.linuxTable, bgpNw.name)
+			continue
+		}
+		br.routeTables[bgpNw.linuxTable] = &routeTable{
+			linuxTable: bgpNw.linuxTable,
+			protocols:  []int{birdRouteProtoNumber},
+			vrf:        bgpNw.vrf,
+		}
+	}
+
+	// update protocols of the watched routes
+	br.watchLock.Lock()
+	defer br.watchLock.Unlock()
+	br.watchedProtocols = make(map[int]struct{})
+	for _, table := range br.routeTables {
+		for _, protocol := range table.protocols {
+			br.watchedProtocols[protocol] = struct{}{}
+		}
+	}
+}
+
+// resolveVRF returns ID of the VRF referenced in the route table mapping.
+func (br *BGPReflector) resolveVRF(vrfName string) (uint32, error) {
+	switch vrfName {
+	case "", config.MainVRF:
+		return br.ContivConf.GetRoutingConfig().MainVRFID, nil
+	case config.PodVRF:
+		return br.ContivConf.GetRoutingConfig().PodVRFID, nil
+	}
+	nw, exists := br.customNetworks[vrfName]
+	if !exists || nw.Type != customnetmodel.CustomNetwork_L3 {
+		return 0, fmt.Errorf("%s is not a L3 custom network", vrfName)
+	}
+	return br.IPNet.GetOrAllocateVrfID(vrfName)
+}
+
+// isMappedNetwork returns true if the VRF of the given custom network is referenced
+// in the route table mapping.
+func (br *BGPReflector) isMappedNetwork(nwName string) bool {
+	for _, tableCfg := range br.config.RouteTables {
+		if tableCfg.VRF == nwName {
+			return true
+		}
+	}
+	return false
+}
+
+// reflectRoutes (re)reads routes of all mapped routing tables and rebuilds the set of reflected routes.
+func (br *BGPReflector) reflectRoutes() error {
+	br.reflectedRoutes = make(map[string]controller.KeyValuePairs)
+	if len(br.routeTables) == 0 {
+		return nil
+	}
+
+	// dump routes of all tables
+	routes, err := netlink.RouteListFiltered(netlink.FAMILY_ALL, &netlink.Route{
+		Table: unix.RT_TABLE_UNSPEC,
+	}, netlink.RT_FILTER_TABLE)
+	if err != nil {
+		return fmt.Errorf("error by listing BGP routes: %v", err)
+	}
+
+	// group routes by destination (IPv6 multipath routes may be listed as separate routes)
+	routesByID := make(map[string][]netlink.Route)
+	for _, r := range routes {
+		if _, mapped := br.routeTables[r.Table]; !mapped || r.Dst == nil {
+			continue
+		}
+		id := routeID(r.Table, r.Dst)
+		routesByID[id] = append(routesByID[id], r)
+	}
+	for id, routes := range routesByID {
+		vppConfig := br.vppRoutes(br.routeTables[routes[0].Table], routes)
+		if len(vppConfig) > 0 {
+			br.reflectedRoutes[id] = vppConfig
+		}
+	}
+	return nil
+}
+
+// updateReflectedRoute re-reads routes of the given routing table with the given destination
+// and updates the reflected routes accordingly.
+func (br *BGPReflector) updateReflectedRoute(linuxTable int, dst *net.IPNet, txn controller.UpdateOperations) error {
+	routes, err := netlink.RouteListFiltered(netlink.FAMILY_ALL, &netlink.Route{
+		Table: linuxTable,
+		Dst:   dst,
+	}, netlink.RT_FILTER_TABLE|netlink.RT_FILTER_DST)
+	if err != nil {
+		return fmt.Errorf("error by listing BGP routes for %v: %v", dst, err)
+	}
+
+	br.applyReflectedRoutes(routeID(linuxTable, dst), br.vppRoutes(br.routeTables[linuxTable], routes), txn)
 	return nil
 }
 
-// vppRoute returns VPP route from given destination network and gateway IP.
-func (br *BGPReflector) vppRoute(dst *net.IPNet, gw net.IP) (key string, config *vpp_l3.Route) {
+// applyReflectedRoutes replaces VPP routes reflected for the route with the given ID
+// with the new configuration, only the difference is added into the transaction.
+func (br *BGPReflector) applyReflectedRoutes(id string, newConfig controller.KeyValuePairs, txn controller.UpdateOperations) {
+	prevConfig := br.reflectedRoutes[id]
+	for key := range prevConfig {
+		if _, exists := newConfig[key]; !exists {
+			txn.Delete(key)
+		}
+	}
+	for key, value := range newConfig {
+		txn.Put(key, value)
+	}
+	if len(newConfig) > 0 {
+		br.reflectedRoutes[id] = newConfig
+	} else {
+		delete(br.reflectedRoutes, id)
+	}
+}
+
+// vppRoutes returns VPP routes reflecting the given Linux routes of a mapped routing table.
+// Each next hop of a multipath (ECMP) route is reflected as a separate VPP route.
+func (br *BGPReflector) vppRoutes(table *routeTable, routes []netlink.Route) controller.KeyValuePairs {
+	vppConfig := make(controller.KeyValuePairs)
+	for _, r := range routes {
+		if !table.matches(&r) {
+			continue
+		}
+		if len(r.MultiPath) > 0 {
+			for _, nh := range r.MultiPath {
+				if isValidNextHop(nh.Gw) {
+					key, route := br.vppRoute(table.vrf, r.Dst, nh.Gw, uint32(nh.Hops)+1)
+					vppConfig[key] = route
+				}
+			}
+		} else if isValidNextHop(r.Gw) {
+			key, route := br.vppRoute(table.vrf, r.Dst, r.Gw, 0)
+			vppConfig[key] = route
+		}
+	}
+	return vppConfig
+}
+
+// vppRoute returns VPP route from given VRF, destination network, gateway IP and weight.
+// In the main VRF, the outgoing interface is the VPP interface connected to the network of the gateway,
+// in other VRFs the gateway is resolved in the main VRF.
+func (br *BGPReflector) vppRoute(vrf uint32, dst *net.IPNet, gw net.IP, weight uint32) (key string, config *vpp_l3.Route) {
+	mainVRF := br.ContivConf.GetRoutingConfig().MainVRFID
 	route := &vpp_l3.Route{
-		DstNetwork:        dst.String(),
-		NextHopAddr:       gw.String(),
-		OutgoingInterface: br.ContivConf.GetMainInterfaceName(),
-		VrfId:             br.ContivConf.GetRoutingConfig().MainVRFID,
+		DstNetwork:  dst.String(),
+		NextHopAddr: gw.String(),
+		VrfId:       vrf,
+		Weight:      weight,
+	}
+	if vrf == mainVRF {
+		route.OutgoingInterface = br.nextHopInterface(gw)
+	} else {
+		route.Type = vpp_l3.Route_INTER_VRF
+		route.ViaVrfId = mainVRF
 	}
 	return models.Key(route), route
 }
 
-// isValidRoute returns true if the route is valid and should be reflected, false otherwise.
-func isValidRoute(dst *net.IPNet, gw net.IP) bool {
-	if dst == nil || gw == nil {
-		return false
+// nextHopInterface returns name of the VPP interface through which the given gateway is reachable.
+// The main interface is used unless the gateway is from the network of other VPP interface.
+func (br *BGPReflector) nextHopInterface(gw net.IP) string {
+	for _, otherIf := range br.ContivConf.GetOtherVPPInterfaces() {
+		for _, ip := range otherIf.IPs {
+			if ip.Network != nil && ip.Network.Contains(gw) {
+				return otherIf.InterfaceName
+			}
+		}
 	}
-	if gw.IsUnspecified() {
+	return br.ContivConf.GetMainInterfaceName()
+}
+
+// renderedConfig returns VPP configuration currently rendered by the plugin - reflected routes
+// and routes for the pod subnets exported by custom networks.
+func (br *BGPReflector) renderedConfig() controller.KeyValuePairs {
+	vppConfig := make(controller.KeyValuePairs)
+	for _, routes := range br.reflectedRoutes {
+		for key, value := range routes {
+			vppConfig[key] = value
+		}
+	}
+	for _, bgpNw := range br.bgpNetworks {
+		for key, value := range br.exportedRouteConfig(bgpNw) {
+			vppConfig[key] = value
+		}
+	}
+	return vppConfig
+}
+
+// matches returns true if the given Linux route should be reflected.
+func (table *routeTable) matches(r *netlink.Route) bool {
+	if table.metric != 0 && r.Priority != table.metric {
 		return false
 	}
-	return true
+	for _, protocol := range table.protocols {
+		if r.Protocol == protocol {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveBGPNetwork returns BGP interconnection of the given custom network as resolved on this node,
+// nil if the network is not interconnected via BGP.
+func (br *BGPReflector) resolveBGPNetwork(nw *customnetmodel.CustomNetwork) (*bgpNetwork, error) {
+	if nw.Bgp == nil || nw.Type != customnetmodel.CustomNetwork_L3 || nw.SubnetCIDR == "" {
+		return nil, nil
+	}
+	vrf, err := br.IPNet.GetOrAllocateVrfID(nw.Name)
+	if err != nil {
+		return nil, err
+	}
+	linuxTable := nw.Bgp.LinuxTable
+	if linuxTable == 0 {
+		linuxTable = vrf
+	}
+	if linuxTable >= unix.RT_TABLE_COMPAT && linuxTable <= unix.RT_TABLE_LOCAL {
+		return nil, fmt.Errorf("routing table %d is reserved and can not be used for the network %s",
+			linuxTable, nw.Name)
+	}
+	for _, otherNw := range br.bgpNetworks {
+		if otherNw.name != nw.Name && otherNw.linuxTable == int(linuxTable) {
+			return nil, fmt.Errorf("routing table %d of the network %s is already used by the network %s",
+				linuxTable, nw.Name, otherNw.name)
+		}
+	}
+	podSubnet := br.IPAM.PodSubnetThisNode(nw.Name)
+	if podSubnet == nil {
+		return nil, fmt.Errorf("pod subnet of the network %s is not known", nw.Name)
+	}
+	return &bgpNetwork{
+		name:       nw.Name,
+		config:     nw.Bgp,
+		vrf:        vrf,
+		linuxTable: int(linuxTable),
+		podSubnet:  podSubnet,
+	}, nil
+}
+
+// exportedRouteConfig returns VPP route from the main VRF to the VRF of a custom network
+// interconnected via BGP for the exported pod subnet.
+func (br *BGPReflector) exportedRouteConfig(bgpNw *bgpNetwork) controller.KeyValuePairs {
+	vppConfig := make(controller.KeyValuePairs)
+	if bgpNw.config.ExportPodSubnets {
+		// traffic destined to the exported pod subnet arrives into the main VRF
+		route := &vpp_l3.Route{
+			Type:        vpp_l3.Route_INTER_VRF,
+			DstNetwork:  bgpNw.podSubnet.String(),
+			VrfId:       br.ContivConf.GetRoutingConfig().MainVRFID,
+			ViaVrfId:    bgpNw.vrf,
+			NextHopAddr: anyAddrForAF(bgpNw.podSubnet.IP),
+		}
+		vppConfig[models.Key(route)] = route
+	}
+	return vppConfig
+}
+
+// syncExportedRoutes synchronizes routes exported to the BGP speaker with the pod subnets
+// of the custom networks interconnected via BGP. The routes are installed as blackhole routes
+// into the routing tables of the networks, the actual traffic is routed by VPP.
+func (br *BGPReflector) syncExportedRoutes() error {
+	expected := make(map[string]*netlink.Route)
+	for _, bgpNw := range br.bgpNetworks {
+		if bgpNw.config.ExportPodSubnets {
+			route := &netlink.Route{
+				Dst:      bgpNw.podSubnet,
+				Table:    bgpNw.linuxTable,
+				Protocol: exportRouteProtoNumber,
+				Type:     unix.RTN_BLACKHOLE,
+			}
+			expected[exportedRouteID(route)] = route
+		}
+	}
+
+	// remove obsolete routes
+	routes, err := netlink.RouteListFiltered(netlink.FAMILY_ALL, &netlink.Route{
+		Table:    unix.RT_TABLE_UNSPEC,
+		Protocol: exportRouteProtoNumber,
+	}, netlink.RT_FILTER_TABLE|netlink.RT_FILTER_PROTOCOL)
+	if err != nil {
+		return fmt.Errorf("error by listing exported routes: %v", err)
+	}
+	for _, r := range routes {
+		if _, isExpected := expected[exportedRouteID(&r)]; isExpected {
+			continue
+		}
+		if err := netlink.RouteDel(&r); err != nil {
+			br.Log.Warnf("Error by removing exported route %v: %v", r, err)
+		}
+	}
+
+	// add / update exported routes
+	for _, route := range expected {
+		if err := netlink.RouteReplace(route); err != nil {
+			return fmt.Errorf("error by exporting route %v: %v", route, err)
+		}
+	}
+	return nil
+}
+
+// exportedRouteID returns identifier of an exported route.
+func exportedRouteID(r *netlink.Route) string {
+	return routeID(r.Table, r.Dst)
+}
+
+// routeID returns identifier of a route from the given routing table and destination.
+func routeID(linuxTable int, dst *net.IPNet) string {
+	return fmt.Sprintf("%d/%s", linuxTable, dst)
+}
+
+// anyAddrForAF returns IP address identifying "any" node for address family
+// determined from given IP address.
+func anyAddrForAF(ip net.IP) string {
+	if ip.To4() == nil {
+		return net.IPv6zero.String()
+	}
+	return net.IPv4zero.String()
+}
+
+// isValidNextHop returns true if the route via the given gateway should be reflected, false otherwise.
+func isValidNextHop(gw net.IP) bool {
+	return gw != nil && !gw.IsUnspecified()
 }
@@ -31,10 +31,13 @@ func (t BGPRouteUpdateType) String() string {
 }
 
 // BGPRouteUpdate is triggered when a BGP route on the host changes.
+// All routes of the routing table with the same destination network are then
+// re-read and reflected (ECMP routes may consist of multiple Linux routes).
 type BGPRouteUpdate struct {
 	Type       BGPRouteUpdateType
 	DstNetwork *net.IPNet
 	GwAddr     net.IP
+	Table      int
 }
 
 // GetName returns name of the BGPRouteUpdate event.
@@ -47,8 +50,9 @@ func (ev *BGPRouteUpdate) String() string {
 	return fmt.Sprintf("%s\n"+
 		"* Type: %s\n"+
 		"* DstNetwork: %s\n"+
-		"* GW: %s",
-		ev.GetName(), ev.Type.String(), ev.DstNetwork.String(), ev.GwAddr.String())
+		"* GW: %s\n"+
+		"* Table: %d",
+		ev.GetName(), ev.Type.String(), ev.DstNetwork.String(), ev.GwAddr.String(), ev.Table)
 }
 
 // Method is Update.
@@ -0,0 +1,450 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgpreflector
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+	"github.com/vishvananda/netlink"
+	"go.ligato.io/cn-infra/v2/infra"
+	"go.ligato.io/cn-infra/v2/logging/logrus"
+	"golang.org/x/sys/unix"
+
+	"go.ligato.io/vpp-agent/v3/pkg/models"
+	vpp_l3 "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/l3"
+
+	"github.com/contiv/vpp/plugins/bgpreflector/config"
+	"github.com/contiv/vpp/plugins/contivconf"
+	contivconf_config "github.com/contiv/vpp/plugins/contivconf/config"
+	controller "github.com/contiv/vpp/plugins/controller/api"
+	customnetmodel "github.com/contiv/vpp/plugins/crd/handler/customnetwork/model"
+	"github.com/contiv/vpp/plugins/ipam"
+	"github.com/contiv/vpp/plugins/ipnet"
+)
+
+const (
+	mainIfName  = "GigabitEthernet0/8/0"
+	otherIfName = "GigabitEthernet0/9/0"
+	mainVRF     = 0
+	podVRF      = 1
+)
+
+// contivConfMock implements the subset of contivconf.API used by bgpreflector.
+type contivConfMock struct {
+	contivconf.API
+	useExternalIPAM bool
+}
+
+func (m *contivConfMock) GetIPAMConfig() *contivconf.IPAMConfig {
+	return &contivconf.IPAMConfig{UseExternalIPAM: m.useExternalIPAM}
+}
+
+func (m *contivConfMock) GetRoutingConfig() *contivconf_config.RoutingConfig {
+	return &contivconf_config.RoutingConfig{MainVRFID: mainVRF, PodVRFID: podVRF}
+}
+
+func (m *contivConfMock) GetMainInterfaceName() string {
+	return mainIfName
+}
+
+func (m *contivConfMock) GetOtherVPPInterfaces() contivconf.OtherInterfaces {
+	_, network, _ := net.ParseCIDR("192.168.50.0/24")
+	return contivconf.OtherInterfaces{
+		{
+			InterfaceName: otherIfName,
+			IPs: contivconf.IPsWithNetworks{
+				{Address: net.ParseIP("192.168.50.1"), Network: network},
+			},
+		},
+	}
+}
+
+// ipNetMock implements the subset of ipnet.API used by bgpreflector.
+type ipNetMock struct {
+	ipnet.API
+	vrfs map[string]uint32
+}
+
+func (m *ipNetMock) GetOrAllocateVrfID(networkName string) (vrf uint32, err error) {
+	vrf, exists := m.vrfs[networkName]
+	if !exists {
+		return 0, fmt.Errorf("no VRF for network %s", networkName)
+	}
+	return vrf, nil
+}
+
+// ipamMock implements the subset of ipam.API used by bgpreflector.
+type ipamMock struct {
+	ipam.API
+	podSubnets map[string]*net.IPNet
+}
+
+func (m *ipamMock) PodSubnetThisNode(network string) *net.IPNet {
+	return m.podSubnets[network]
+}
+
+// txnMock records operations of an update transaction.
+type txnMock struct {
+	values  controller.KeyValuePairs
+	deleted []string
+}
+
+func newTxnMock() *txnMock {
+	return &txnMock{values: make(controller.KeyValuePairs)}
+}
+
+func (txn *txnMock) Put(key string, value proto.Message) {
+	txn.values[key] = value
+}
+
+func (txn *txnMock) Get(key string) proto.Message {
+	return txn.values[key]
+}
+
+func (txn *txnMock) Delete(key string) {
+	txn.deleted = append(txn.deleted, key)
+}
+
+func newTestReflector(useExternalIPAM bool, cfg *config.Config) *BGPReflector {
+	return &BGPReflector{
+		Deps: Deps{
+			PluginDeps: infra.PluginDeps{Log: logrus.DefaultLogger()},
+			ContivConf: &contivConfMock{useExternalIPAM: useExternalIPAM},
+			IPNet:      &ipNetMock{vrfs: map[string]uint32{"net1": 10, "net2": 20}},
+			IPAM: &ipamMock{podSubnets: map[string]*net.IPNet{
+				"net1": ipNetwork("10.10.1.0/24"),
+				"net2": ipNetwork("10.20.1.0/24"),
+			}},
+		},
+		config: cfg,
+		customNetworks: map[string]*customnetmodel.CustomNetwork{
+			"net1":  {Name: "net1", Type: customnetmodel.CustomNetwork_L3},
+			"l2net": {Name: "l2net", Type: customnetmodel.CustomNetwork_L2},
+		},
+		bgpNetworks:     make(map[string]*bgpNetwork),
+		reflectedRoutes: make(map[string]controller.KeyValuePairs),
+	}
+}
+
+func ipNetwork(cidr string) *net.IPNet {
+	_, network, _ := net.ParseCIDR(cidr)
+	return network
+}
+
+func bgpCustomNetwork(name string, bgp *customnetmodel.CustomNetwork_BGP) *customnetmodel.CustomNetwork {
+	return &customnetmodel.CustomNetwork{
+		Name:       name,
+		Type:       customnetmodel.CustomNetwork_L3,
+		SubnetCIDR: "10.0.0.0/8",
+		Bgp:        bgp,
+	}
+}
+
+func TestResolveBGPNetwork(t *testing.T) {
+	RegisterTestingT(t)
+	br := newTestReflector(false, config.DefaultConfig())
+
+	// routing table defaults to the VRF ID
+	bgpNw, err := br.resolveBGPNetwork(bgpCustomNetwork("net1", &customnetmodel.CustomNetwork_BGP{ExportPodSubnets: true}))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(bgpNw).ToNot(BeNil())
+	Expect(bgpNw.name).To(Equal("net1"))
+	Expect(bgpNw.vrf).To(BeEquivalentTo(10))
+	Expect(bgpNw.linuxTable).To(Equal(10))
+	Expect(bgpNw.podSubnet).To(Equal(ipNetwork("10.10.1.0/24")))
+	br.bgpNetworks[bgpNw.name] = bgpNw
+
+	// routing table already used by another network
+	_, err = br.resolveBGPNetwork(bgpCustomNetwork("net2", &customnetmodel.CustomNetwork_BGP{LinuxTable: 10}))
+	Expect(err).To(HaveOccurred())
+
+	// reserved routing table
+	_, err = br.resolveBGPNetwork(bgpCustomNetwork("net2", &customnetmodel.CustomNetwork_BGP{LinuxTable: 254}))
+	Expect(err).To(HaveOccurred())
+
+	// explicit routing table
+	bgpNw, err = br.resolveBGPNetwork(bgpCustomNetwork("net2", &customnetmodel.CustomNetwork_BGP{LinuxTable: 120}))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(bgpNw.linuxTable).To(Equal(120))
+	Expect(bgpNw.vrf).To(BeEquivalentTo(20))
+
+	// not interconnected via BGP
+	bgpNw, err = br.resolveBGPNetwork(bgpCustomNetwork("net2", nil))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(bgpNw).To(BeNil())
+	l2Nw := bgpCustomNetwork("net2", &customnetmodel.CustomNetwork_BGP{})
+	l2Nw.Type = customnetmodel.CustomNetwork_L2
+	bgpNw, err = br.resolveBGPNetwork(l2Nw)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(bgpNw).To(BeNil())
+}
+
+func TestExportedRouteConfig(t *testing.T) {
+	RegisterTestingT(t)
+	br := newTestReflector(false, config.DefaultConfig())
+
+	bgpNw := &bgpNetwork{
+		name:       "net1",
+		config:     &customnetmodel.CustomNetwork_BGP{ExportPodSubnets: true},
+		vrf:        10,
+		linuxTable: 10,
+		podSubnet:  ipNetwork("10.10.1.0/24"),
+	}
+	expRoute := &vpp_l3.Route{
+		Type:        vpp_l3.Route_INTER_VRF,
+		DstNetwork:  "10.10.1.0/24",
+		VrfId:       mainVRF,
+		ViaVrfId:    10,
+		NextHopAddr: "0.0.0.0",
+	}
+	vppConfig := br.exportedRouteConfig(bgpNw)
+	Expect(vppConfig).To(HaveLen(1))
+	Expect(vppConfig).To(HaveKeyWithValue(models.Key(expRoute), expRoute))
+
+	bgpNw.config.ExportPodSubnets = false
+	Expect(br.exportedRouteConfig(bgpNw)).To(BeEmpty())
+}
+
+func TestResolveRouteTables(t *testing.T) {
+	RegisterTestingT(t)
+
+	br := newTestReflector(true, &config.Config{
+		RouteTables: []*config.RouteTable{
+			{LinuxTable: 100, VRF: config.PodVRF},
+			{LinuxTable: 200, Protocols: []int{12, 186}, Metric: 300, VRF: "net1"},
+			{LinuxTable: 0, VRF: config.MainVRF},   // invalid table
+			{LinuxTable: 100, VRF: config.MainVRF}, // mapped twice
+			{LinuxTable: 300, VRF: "unknown"},      // unknown network
+			{LinuxTable: 400, VRF: "l2net"},        // not a L3 network
+		},
+	})
+	br.bgpNetworks["net1"] = &bgpNetwork{
+		name:       "net1",
+		config:     &customnetmodel.CustomNetwork_BGP{ImportRoutes: true},
+		vrf:        10,
+		linuxTable: 150,
+	}
+	br.bgpNetworks["net2"] = &bgpNetwork{
+		name:       "net2",
+		config:     &customnetmodel.CustomNetwork_BGP{ImportRoutes: true},
+		vrf:        20,
+		linuxTable: 100, // already mapped in the configuration
+	}
+	br.resolveRouteTables()
+
+	Expect(br.routeTables).To(HaveLen(4))
+	Expect(br.routeTables[100]).To(Equal(&routeTable{
+		linuxTable: 100, protocols: []int{birdRouteProtoNumber}, vrf: podVRF}))
+	Expect(br.routeTables[200]).To(Equal(&routeTable{
+		linuxTable: 200, protocols: []int{12, 186}, metric: 300, vrf: 10}))
+	Expect(br.routeTables[unix.RT_TABLE_MAIN]).To(Equal(&routeTable{
+		linuxTable: unix.RT_TABLE_MAIN, protocols: []int{birdRouteProtoNumber}, vrf: mainVRF}))
+	Expect(br.routeTables[150]).To(Equal(&routeTable{
+		linuxTable: 150, protocols: []int{birdRouteProtoNumber}, vrf: 10}))
+
+	Expect(br.isWatchedProtocol(12)).To(BeTrue())
+	Expect(br.isWatchedProtocol(186)).To(BeTrue())
+	Expect(br.isWatchedProtocol(exportRouteProtoNumber)).To(BeFalse())
+
+	// main table mapped explicitly, without external IPAM
+	br = newTestReflector(false, &config.Config{
+		RouteTables: []*config.RouteTable{
+			{LinuxTable: unix.RT_TABLE_MAIN, Protocols: []int{186}, VRF: config.PodVRF},
+		},
+	})
+	br.resolveRouteTables()
+	Expect(br.routeTables).To(HaveLen(1))
+	Expect(br.routeTables[unix.RT_TABLE_MAIN].vrf).To(BeEquivalentTo(podVRF))
+
+	// nothing mapped
+	br = newTestReflector(false, config.DefaultConfig())
+	br.resolveRouteTables()
+	Expect(br.routeTables).To(BeEmpty())
+	Expect(br.isWatchedProtocol(12)).To(BeFalse())
+}
+
+func TestRouteTableMatches(t *testing.T) {
+	RegisterTestingT(t)
+
+	table := &routeTable{linuxTable: 100, protocols: []int{12, 186}}
+	Expect(table.matches(&netlink.Route{Protocol: 12})).To(BeTrue())
+	Expect(table.matches(&netlink.Route{Protocol: 186, Priority: 50})).To(BeTrue())
+	Expect(table.matches(&netlink.Route{Protocol: 4})).To(BeFalse())
+
+	table.metric = 300
+	Expect(table.matches(&netlink.Route{Protocol: 12, Priority: 300})).To(BeTrue())
+	Expect(table.matches(&netlink.Route{Protocol: 12, Priority: 50})).To(BeFalse())
+	Expect(table.matches(&netlink.Route{Protocol: 4, Priority: 300})).To(BeFalse())
+}
+
+func TestVPPRoutes(t *testing.T) {
+	RegisterTestingT(t)
+	br := newTestReflector(true, config.DefaultConfig())
+
+	dst := ipNetwork("10.20.0.0/16")
+	mainTable := &routeTable{linuxTable: unix.RT_TABLE_MAIN, protocols: []int{birdRouteProtoNumber}, vrf: mainVRF}
+
+	// ECMP route - each next hop reflected as a separate route
+	routes := []netlink.Route{
+		{
+			Dst:      dst,
+			Protocol: birdRouteProtoNumber,
+			MultiPath: []*netlink.NexthopInfo{
+				{Gw: net.ParseIP("10.0.0.1")},
+				{Gw: net.ParseIP("192.168.50.2"), Hops: 1},
+				{Gw: net.IPv4zero}, // invalid next hop
+			},
+		},
+		{
+			Dst:      dst,
+			Protocol: 4, // not reflected protocol
+			Gw:       net.ParseIP("10.0.0.5"),
+		},
+	}
+	vppConfig := br.vppRoutes(mainTable, routes)
+	Expect(vppConfig).To(HaveLen(2))
+	Expect(vppConfig).To(ContainElement(&vpp_l3.Route{
+		DstNetwork:        "10.20.0.0/16",
+		NextHopAddr:       "10.0.0.1",
+		OutgoingInterface: mainIfName,
+		VrfId:             mainVRF,
+		Weight:            1,
+	}))
+	// next hop from the network of other VPP interface
+	Expect(vppConfig).To(ContainElement(&vpp_l3.Route{
+		DstNetwork:        "10.20.0.0/16",
+		NextHopAddr:       "192.168.50.2",
+		OutgoingInterface: otherIfName,
+		VrfId:             mainVRF,
+		Weight:            2,
+	}))
+
+	// single path route into other VRF - next hop resolved in the main VRF
+	podTable := &routeTable{linuxTable: 100, protocols: []int{birdRouteProtoNumber}, vrf: podVRF}
+	vppConfig = br.vppRoutes(podTable, []netlink.Route{
+		{Dst: dst, Protocol: birdRouteProtoNumber, Gw: net.ParseIP("10.0.0.1")},
+	})
+	Expect(vppConfig).To(HaveLen(1))
+	Expect(vppConfig).To(ContainElement(&vpp_l3.Route{
+		Type:        vpp_l3.Route_INTER_VRF,
+		DstNetwork:  "10.20.0.0/16",
+		NextHopAddr: "10.0.0.1",
+		VrfId:       podVRF,
+		ViaVrfId:    mainVRF,
+	}))
+}
+
+func TestApplyReflectedRoutes(t *testing.T) {
+	RegisterTestingT(t)
+	br := newTestReflector(true, config.DefaultConfig())
+
+	dst := ipNetwork("10.20.0.0/16")
+	id := routeID(unix.RT_TABLE_MAIN, dst)
+	mainTable := &routeTable{linuxTable: unix.RT_TABLE_MAIN, protocols: []int{birdRouteProtoNumber}, vrf: mainVRF}
+	ecmpRoute := func(gws ...string) []netlink.Route {
+		route := netlink.Route{Dst: dst, Protocol: birdRouteProtoNumber}
+		for _, gw := range gws {
+			route.MultiPath = append(route.MultiPath, &netlink.NexthopInfo{Gw: net.ParseIP(gw)})
+		}
+		return []netlink.Route{route}
+	}
+
+	// add ECMP route with two next hops
+	txn := newTxnMock()
+	br.applyReflectedRoutes(id, br.vppRoutes(mainTable, ecmpRoute("10.0.0.1", "10.0.0.2")), txn)
+	Expect(txn.values).To(HaveLen(2))
+	Expect(txn.deleted).To(BeEmpty())
+	Expect(br.reflectedRoutes[id]).To(HaveLen(2))
+	prevKeys := make(map[string]string)
+	for key, value := range txn.values {
+		prevKeys[value.(*vpp_l3.Route).NextHopAddr] = key
+	}
+
+	// replace one of the next hops
+	txn = newTxnMock()
+	br.applyReflectedRoutes(id, br.vppRoutes(mainTable, ecmpRoute("10.0.0.1", "10.0.0.3")), txn)
+	Expect(txn.values).To(HaveLen(2))
+	Expect(txn.deleted).To(Equal([]string{prevKeys["10.0.0.2"]}))
+	Expect(br.reflectedRoutes[id]).To(HaveLen(2))
+
+	// delete the route
+	txn = newTxnMock()
+	br.applyReflectedRoutes(id, br.vppRoutes(mainTable, nil), txn)
+	Expect(txn.values).To(BeEmpty())
+	Expect(txn.deleted).To(HaveLen(2))
+	Expect(br.reflectedRoutes).ToNot(HaveKey(id))
+}
+
+func TestCustomNetworkChangeKeyedByName(t *testing.T) {
+	RegisterTestingT(t)
+	br := newTestReflector(false, &config.Config{
+		RouteTables: []*config.RouteTable{{LinuxTable: 100, VRF: "net1"}},
+	})
+	br.resolveRouteTables()
+
+	// network not relevant for the route reflection - only remembered under its name
+	net2 := &customnetmodel.CustomNetwork{Name: "net2", Type: customnetmodel.CustomNetwork_L3}
+	_, err := br.Update(&controller.KubeStateChange{
+		Key:      customnetmodel.Key("net2"),
+		Resource: customnetmodel.Keyword,
+		NewValue: net2,
+	}, newTxnMock())
+	Expect(err).ToNot(HaveOccurred())
+	Expect(br.customNetworks).To(HaveKey("net2"))
+	Expect(br.isMappedNetwork("net2")).To(BeFalse())
+	vrf, err := br.resolveVRF("net2")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(vrf).To(BeEquivalentTo(20))
+
+	_, err = br.Update(&controller.KubeStateChange{
+		Key:       customnetmodel.Key("net2"),
+		Resource:  customnetmodel.Keyword,
+		PrevValue: net2,
+	}, newTxnMock())
+	Expect(err).ToNot(HaveOccurred())
+	Expect(br.customNetworks).ToNot(HaveKey("net2"))
+	_, err = br.resolveVRF("net2")
+	Expect(err).To(HaveOccurred())
+}
+
+func TestBGPNetworkUpdateKeyedByName(t *testing.T) {
+	RegisterTestingT(t)
+	br := newTestReflector(false, config.DefaultConfig())
+
+	net1 := bgpCustomNetwork("net1", &customnetmodel.CustomNetwork_BGP{ExportPodSubnets: true})
+	bgpNw, err := br.resolveBGPNetwork(net1)
+	Expect(err).ToNot(HaveOccurred())
+	br.bgpNetworks["net1"] = bgpNw
+	exported := br.exportedRouteConfig(bgpNw)
+	Expect(exported).To(HaveLen(1))
+
+	// network is removed under its KSR key, the state is found by the network name
+	Expect(customnetmodel.Key("net1")).ToNot(Equal("net1"))
+	txn := newTxnMock()
+	_, err = br.Update(&controller.KubeStateChange{
+		Key:       customnetmodel.Key("net1"),
+		Resource:  customnetmodel.Keyword,
+		PrevValue: net1,
+	}, txn)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(br.bgpNetworks).To(BeEmpty())
+	for key := range exported {
+		Expect(txn.deleted).To(Equal([]string{key}))
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+const (
+	// MainVRF refers to the main VRF in the route table mapping.
+	MainVRF = "main"
+
+	// PodVRF refers to the pod VRF in the route table mapping.
+	PodVRF = "pod"
+)
+
+// Config holds the BGPReflector configuration.
+type Config struct {
+	// mapping of Linux routing tables into VPP VRFs,
+	// if external IPAM is in use and the main routing table is not mapped explicitly,
+	// routes of the main table are reflected into the main VRF
+	RouteTables []*RouteTable `json:"routeTables"`
+}
+
+// RouteTable selects routes of a Linux routing table to reflect into a VPP VRF.
+type RouteTable struct {
+	// Linux routing table (254 for the main table)
+	LinuxTable int `json:"linuxTable"`
+
+	// protocol numbers of the reflected routes (as in /etc/iproute2/rt_protos),
+	// only routes installed by bird (12) are reflected if not set
+	Protocols []int `json:"protocols"`
+
+	// if non-zero, only routes with the given metric are reflected - allows the BGP speaker
+	// to tag the routes, e.g. based on BGP communities (krt_metric in bird)
+	Metric int `json:"metric"`
+
+	// VRF the routes are reflected into: "main", "pod" or name of a L3 custom network
+	// ("main" if not set)
+	VRF string `json:"vrf"`
+}
+
+// DefaultConfig returns configuration for bgpreflector plugin with default values.
+func DefaultConfig() *Config {
+	return &Config{}
+}
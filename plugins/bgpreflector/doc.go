@@ -1,8 +1,31 @@
 // Package bgpreflector reflects BGP routes installed in the host system's network stack
 // (default network namespace) into VPP.
 //
-// For now it only reflects the routes installed by the Bird daemon (https://bird.network.cz/)
-// - routes with the protocol number 12, as defined in /etc/iproute2/rt_protos
+// Linux routing tables are mapped into VPP VRFs in the plugin configuration (bgpreflector.conf),
+// the target VRF is either the main VRF, the pod VRF or the VRF of a L3 custom network.
+// By default, only routes installed by the Bird daemon (https://bird.network.cz/) are reflected
+// - routes with the protocol number 12, as defined in /etc/iproute2/rt_protos. The mapping may
+// select other protocol numbers and routes with a specific metric, which allows the BGP speaker
+// to tag the routes (e.g. based on BGP communities).
 //
-// As of now, bgpreflector is only enabled if useExternalIPAM == true in Contiv IPAM config.
+// Routes of the main routing table are reflected into the main VRF if useExternalIPAM == true
+// in Contiv IPAM config, unless the main table is mapped explicitly.
+//
+// Each next hop of a multipath (ECMP) route is reflected as a separate VPP route, weighted
+// by the next hop weight. In the main VRF, the route is sent via the VPP interface connected
+// to the network of the next hop (the main interface by default), in other VRFs the next hop
+// is resolved in the main VRF. All routes of the mapped tables are re-read and reflected
+// on every resync, e.g. after the agent restart.
+//
+// Additionally, L3 custom networks with the "bgp" option are interconnected with external routers
+// via the BGP speaker running on the host. The routes are exchanged through a dedicated routing table
+// of the network (the VRF ID of the network by default):
+//   - with exportPodSubnets, the pod subnet of the network on this node is installed into the table
+//     as a blackhole route with the protocol number 170, to be announced by the speaker.
+//     The traffic destined to the subnet is routed by VPP from the main VRF into the VRF of the network.
+//   - with importRoutes, the routes installed into the table by the speaker are reflected into
+//     the VRF of the network, with next hops resolved in the main VRF.
+//
+// No BGP speaker is embedded in the agent, the speaker (e.g. Bird) has to be configured
+// to use the routing table of the network (for EVPN, with the VNI and VRF pinned in the network).
 package bgpreflector
@@ -16,6 +16,7 @@ package bgpreflector
 
 import (
 	"github.com/contiv/vpp/plugins/contivconf"
+	"go.ligato.io/cn-infra/v2/config"
 	"go.ligato.io/cn-infra/v2/logging"
 )
 
@@ -36,6 +37,9 @@ func NewPlugin(opts ...Option) *BGPReflector {
 	if p.Deps.Log == nil {
 		p.Deps.Log = logging.ForPlugin(p.String())
 	}
+	if p.Cfg == nil {
+		p.Cfg = config.ForPlugin(p.String())
+	}
 
 	return p
 }
@@ -116,6 +116,19 @@ type IPAMConfig struct {
 	VxlanCIDR                     string     `json:"vxlanCIDR,omitempty"`
 	DefaultGateway                string     `json:"defaultGateway,omitempty"`
 	SRv6                          SRv6Config `json:"srv6"`
+	DualStack                     DualStack  `json:"dualStack"`
+}
+
+// DualStack is part of IPAM configuration that enables dual-stack (IPv4 + IPv6) pod networking
+// and defines subnets of the secondary IP family (the family opposite to the one of podSubnetCIDR).
+type DualStack struct {
+	Enabled                       bool   `json:"enabled,omitempty"`
+	ServiceCIDR                   string `json:"serviceCIDR,omitempty"`
+	PodSubnetCIDR                 string `json:"podSubnetCIDR,omitempty"`
+	PodSubnetOneNodePrefixLen     uint8  `json:"podSubnetOneNodePrefixLen,omitempty"`
+	VPPHostSubnetCIDR             string `json:"vppHostSubnetCIDR,omitempty"`
+	VPPHostSubnetOneNodePrefixLen uint8  `json:"vppHostSubnetOneNodePrefixLen,omitempty"`
+	VxlanCIDR                     string `json:"vxlanCIDR,omitempty"`
 }
 
 // SRv6Config is part of IPAM configuration that configures SID prefixes of SRv6 components
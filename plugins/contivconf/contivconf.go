@@ -482,6 +482,12 @@ func (c *ContivConf) Init() (err error) {
 			c.ipamConfig.UseIPv6 = false
 		}
 	}
+	if c.config.IPAMConfig.DualStack.Enabled {
+		err = c.parseDualStackConfig()
+		if err != nil {
+			return err
+		}
+	}
 	// disable GSO for SRv6 - not yet supported by VPP
 	if c.ipamConfig.UseIPv6 && c.config.RoutingConfig.NodeToNodeTransport == SRv6Transport && c.config.EnableGSO {
 		c.Log.Warnf("GSO not supported for SRv6, disabling")
@@ -529,6 +535,51 @@ func (c *ContivConf) Init() (err error) {
 	return nil
 }
 
+// parseDualStackConfig parses subnets of the secondary IP family used in the dual-stack mode.
+func (c *ContivConf) parseDualStackConfig() (err error) {
+	dsConfig := c.config.IPAMConfig.DualStack
+	if c.ipamConfig.ContivCIDR != nil {
+		return fmt.Errorf("dual-stack is not supported together with ContivCIDR, use custom IPAM subnets")
+	}
+	if dsConfig.PodSubnetCIDR == "" || dsConfig.ServiceCIDR == "" || dsConfig.VPPHostSubnetCIDR == "" {
+		return fmt.Errorf("dual-stack requires podSubnetCIDR, serviceCIDR and vppHostSubnetCIDR " +
+			"of the secondary IP family")
+	}
+	c.ipamConfig.DualStack = true
+	c.ipamConfig.SecondarySubnets.PodSubnetOneNodePrefixLen = dsConfig.PodSubnetOneNodePrefixLen
+	c.ipamConfig.SecondarySubnets.VPPHostSubnetOneNodePrefixLen = dsConfig.VPPHostSubnetOneNodePrefixLen
+
+	_, c.ipamConfig.SecondaryServiceCIDR, err = net.ParseCIDR(dsConfig.ServiceCIDR)
+	if err != nil {
+		return fmt.Errorf("failed to parse dual-stack ServiceCIDR: %v", err)
+	}
+	_, c.ipamConfig.SecondarySubnets.PodSubnetCIDR, err = net.ParseCIDR(dsConfig.PodSubnetCIDR)
+	if err != nil {
+		return fmt.Errorf("failed to parse dual-stack PodSubnetCIDR: %v", err)
+	}
+	_, c.ipamConfig.SecondarySubnets.VPPHostSubnetCIDR, err = net.ParseCIDR(dsConfig.VPPHostSubnetCIDR)
+	if err != nil {
+		return fmt.Errorf("failed to parse dual-stack VPPHostSubnetCIDR: %v", err)
+	}
+	if dsConfig.VxlanCIDR != "" {
+		_, c.ipamConfig.SecondarySubnets.VxlanCIDR, err = net.ParseCIDR(dsConfig.VxlanCIDR)
+		if err != nil {
+			return fmt.Errorf("failed to parse dual-stack VxlanCIDR: %v", err)
+		}
+	} else if c.config.RoutingConfig.NodeToNodeTransport == VXLANTransport {
+		return fmt.Errorf("dual-stack with VXLAN node-to-node transport requires vxlanCIDR of the secondary IP family")
+	}
+
+	// all secondary subnets must be of the IP family opposite to the primary one
+	for _, subnet := range []string{dsConfig.ServiceCIDR, dsConfig.PodSubnetCIDR,
+		dsConfig.VPPHostSubnetCIDR, dsConfig.VxlanCIDR} {
+		if subnet != "" && isIPv6AddrString(subnet) == c.ipamConfig.UseIPv6 {
+			return fmt.Errorf("dual-stack subnet %s is not of the secondary IP family", subnet)
+		}
+	}
+	return nil
+}
+
 // HandlesEvent selects:
 //   - any Resync event
 //   - KubeStateChange for CRD node-specific config of this node
@@ -133,6 +133,18 @@ type IPAMConfig struct {
 
 	// SRv6 settings defining computation of SID/BSID for SRv6 locasids/policies
 	SRv6Settings
+
+	// DualStack is true if every pod should get one IP address of each IP family.
+	// The primary family is given by UseIPv6, the secondary family uses subnets
+	// from SecondaryServiceCIDR and SecondarySubnets.
+	DualStack bool
+
+	// Subnet used by services of the secondary IP family (dual-stack only).
+	SecondaryServiceCIDR *net.IPNet
+
+	// Subnets of the secondary IP family (dual-stack only).
+	// NodeInterconnectCIDR is not used - nodes are interconnected using the primary IP family.
+	SecondarySubnets CustomIPAMSubnets
 }
 
 // SRv6Settings hold all SID/BSID managment settings (SID/BSID is basically IPv6 address)
@@ -85,6 +85,18 @@ type EventHandler interface {
 	Revert(event Event) error
 }
 
+// EventResultHandler can be optionally implemented by an event handler which
+// needs to learn the final outcome of processing of events it handles,
+// i.e. including the commit of the transaction into vpp-agent.
+type EventResultHandler interface {
+	// EventFinalized is called by Controller when the processing of the given
+	// event, which the handler has processed, has finished. <err> is the overall
+	// processing error (nil if succeeded), which for failed commits
+	// is *kvscheduler.TransactionError. The method is called from the event loop
+	// and therefore should not block.
+	EventFinalized(event Event, err error)
+}
+
 // EventMethodType is either Resync or Update.
 type EventMethodType int
 
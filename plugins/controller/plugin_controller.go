@@ -145,7 +145,9 @@ type WithInternalData interface {
 //
 // Processing of a given event is finalized by calling the api.Event.Done(error)
 // method. The method can be used for example to deliver the return value back
-// to the sender of the event.
+// to the sender of the event. Handlers that need to learn the final outcome
+// of the event processing (including the transaction commit) may additionally
+// implement api.EventResultHandler.
 type Controller struct {
 	Deps
 
@@ -701,9 +703,13 @@ func (c *Controller) processEvent(qe *QueuedEvent) error {
 		abortErr bool
 	)
 	changes := make(map[string]string) // handler -> change description
+	var resultHandlers []api.EventResultHandler // executed handlers waiting for the outcome
 	for idx = 0; idx < len(eventHandlers); idx++ {
 		var err error
 		handler := eventHandlers[idx]
+		if resultHandler, withResult := handler.(api.EventResultHandler); withResult {
+			resultHandlers = append(resultHandlers, resultHandler)
+		}
 
 		// execute Update/Resync
 		var (
@@ -919,6 +925,9 @@ func (c *Controller) processEvent(qe *QueuedEvent) error {
 	}
 	event.Done(wasErr)
 	c.txn = nil
+	for _, resultHandler := range resultHandlers {
+		resultHandler.EventFinalized(event, wasErr)
+	}
 
 	// 12. if Healing/AfterError resync has failed -> report error to status check
 	if needsHealing && isHealing && healingAfterErr != nil {
@@ -354,12 +354,19 @@ func (ctc *ContivTelemetryCache) corelateMgmtIP(node *telemetrymodel.Node) {
 // all of the information has been retrieved. It also checks to make sure
 // that there are no duplicate addresses within the map.
 func (ctc *ContivTelemetryCache) populateNodeMaps(node *telemetrymodel.Node) {
-	ctc.Report.SetPrefix("NODE-MAP")
+	PopulateNodeMaps(node, ctc.VppCache, ctc.K8sCache, ctc.Report)
+}
 
-	k8snode, err := ctc.K8sCache.RetrieveK8sNode(node.Name)
+// PopulateNodeMaps correlates the given VPP node with the K8s data and fills
+// the secondary node indices and the node's pod map that the validators rely on.
+// Inconsistencies found are appended to the report.
+func PopulateNodeMaps(node *telemetrymodel.Node, vppCache api.VppCache, k8sCache api.K8sCache, report api.Report) {
+	report.SetPrefix("NODE-MAP")
+
+	k8snode, err := k8sCache.RetrieveK8sNode(node.Name)
 	if err != nil {
 		errString := fmt.Sprintf("VPP node %s present in Contiv, but not in K8s", node.Name)
-		ctc.Report.AppendToNodeReport(node.Name, errString)
+		report.AppendToNodeReport(node.Name, errString)
 	} else {
 		for _, adr := range k8snode.Addresses {
 			switch adr.Type {
@@ -367,19 +374,19 @@ func (ctc *ContivTelemetryCache) populateNodeMaps(node *telemetrymodel.Node) {
 				if adr.Address != node.Name {
 					errString := fmt.Sprintf("Inconsistent K8s host name for node %s, host name:,%s",
 						k8snode.Name, adr.Address)
-					ctc.Report.AppendToNodeReport(node.Name, errString)
+					report.AppendToNodeReport(node.Name, errString)
 				}
 			}
 		}
 	}
 
-	errReport := ctc.VppCache.SetSecondaryNodeIndices(node)
+	errReport := vppCache.SetSecondaryNodeIndices(node)
 	for _, r := range errReport {
-		ctc.Report.AppendToNodeReport(node.Name, r)
+		report.AppendToNodeReport(node.Name, r)
 	}
 
 	node.PodMap = make(map[string]*telemetrymodel.Pod, 0)
-	for _, pod := range ctc.K8sCache.RetrieveAllPods() {
+	for _, pod := range k8sCache.RetrieveAllPods() {
 		if pod.HostIPAddress == node.ManIPAddr {
 			node.PodMap[pod.Name] = pod
 		}
@@ -176,16 +176,21 @@ func (h *Handler) IsExclusiveKVDB() bool {
 
 // PublishCrdStatus updates the resource Status information.
 func (h *Handler) PublishCrdStatus(obj interface{}, opRetval error) error {
+	return h.PublishCrdRealization(obj, opRetval, nil)
+}
+
+// PublishCrdRealization updates the resource Status information, including
+// the realization status of the custom configuration on every node.
+func (h *Handler) PublishCrdRealization(obj interface{}, opRetval error, realization []kvdbreflector.NodeRealization) error {
 	customConfig, ok := obj.(*v1.CustomConfiguration)
 	if !ok {
 		return errors.New("failed to cast into CustomConfiguration struct")
 	}
 	customConfig = customConfig.DeepCopy()
-	if opRetval == nil {
-		customConfig.Status.Status = v1.StatusSuccess
-	} else {
-		customConfig.Status.Status = v1.StatusFailure
-		customConfig.Status.Message = opRetval.Error()
+	prevStatus := customConfig.Status
+	kvdbreflector.UpdateCrdStatus(&customConfig.Status, opRetval, realization)
+	if reflect.DeepEqual(prevStatus, customConfig.Status) {
+		return nil
 	}
 	_, err := h.CrdClient.ContivppV1().CustomConfigurations(customConfig.Namespace).Update(customConfig)
 	return err
@@ -20,6 +20,8 @@ package customnetwork
 
 import (
 	"errors"
+	"reflect"
+
 	"github.com/contiv/vpp/plugins/crd/handler/customnetwork/model"
 	"github.com/contiv/vpp/plugins/crd/handler/kvdbreflector"
 	"github.com/contiv/vpp/plugins/crd/pkg/apis/contivppio/v1"
@@ -73,16 +75,21 @@ func (h *Handler) IsExclusiveKVDB() bool {
 
 // PublishCrdStatus updates the resource Status information.
 func (h *Handler) PublishCrdStatus(obj interface{}, opRetval error) error {
+	return h.PublishCrdRealization(obj, opRetval, nil)
+}
+
+// PublishCrdRealization updates the resource Status information, including
+// the realization status of the custom network on every node.
+func (h *Handler) PublishCrdRealization(obj interface{}, opRetval error, realization []kvdbreflector.NodeRealization) error {
 	customNet, ok := obj.(*v1.CustomNetwork)
 	if !ok {
 		return errors.New("failed to cast into CustomNetwork struct")
 	}
 	customNet = customNet.DeepCopy()
-	if opRetval == nil {
-		customNet.Status.Status = v1.StatusSuccess
-	} else {
-		customNet.Status.Status = v1.StatusFailure
-		customNet.Status.Message = opRetval.Error()
+	prevStatus := customNet.Status
+	kvdbreflector.UpdateCrdStatus(&customNet.Status, opRetval, realization)
+	if reflect.DeepEqual(prevStatus, customNet.Status) {
+		return nil
 	}
 	_, err := h.CrdClient.ContivppV1().CustomNetworks(customNet.Namespace).Update(customNet)
 	return err
@@ -102,11 +109,31 @@ func (h *Handler) customNetworkToProto(customNetwork *v1.CustomNetwork) *model.C
 	}
 	customNetworkProto.SubnetCIDR = customNetwork.Spec.SubnetCIDR
 	customNetworkProto.SubnetOneNodePrefix = customNetwork.Spec.SubnetOneNodePrefixLen
+	customNetworkProto.Vni = customNetwork.Spec.VNI
+	customNetworkProto.Vrf = customNetwork.Spec.VRF
+	customNetworkProto.Gateway = customNetwork.Spec.Gateway
+	for _, ipRange := range customNetwork.Spec.ReservedRanges {
+		customNetworkProto.ReservedRanges = append(customNetworkProto.ReservedRanges,
+			&model.CustomNetwork_IPRange{
+				Start: ipRange.Start,
+				End:   ipRange.End,
+			})
+	}
+	if customNetwork.Spec.BGP != nil {
+		customNetworkProto.Bgp = &model.CustomNetwork_BGP{
+			LinuxTable:       customNetwork.Spec.BGP.LinuxTable,
+			ExportPodSubnets: customNetwork.Spec.BGP.ExportPodSubnets,
+			ImportRoutes:     customNetwork.Spec.BGP.ImportRoutes,
+		}
+	}
 	return customNetworkProto
 }
 
 // Validation generates OpenAPIV3 validator for custom network CRD
 func Validation() *apiextv1beta1.CustomResourceValidation {
+	// VNI is a 24-bit number, VRF 0 is the main VRF
+	minVNI, maxVNI := float64(1), float64(1<<24-1)
+	minVRF := float64(1)
 	validation := &apiextv1beta1.CustomResourceValidation{
 		OpenAPIV3Schema: &apiextv1beta1.JSONSchemaProps{
 			Required: []string{"spec"},
@@ -135,6 +162,56 @@ func Validation() *apiextv1beta1.CustomResourceValidation {
 						"subnetOneNodePrefix": {
 							Type: "integer",
 						},
+						"vni": {
+							Type:        "integer",
+							Description: "VXLAN VNI pinned for the network",
+							Minimum:     &minVNI,
+							Maximum:     &maxVNI,
+						},
+						"vrf": {
+							Type:        "integer",
+							Description: "VRF ID pinned for the L3 network",
+							Minimum:     &minVRF,
+						},
+						"gateway": {
+							Type:        "string",
+							Description: "IPv4 or IPv6 address of the pod gateway",
+							Pattern:     `^[0-9a-fA-F:\.]+$`,
+						},
+						"reservedRanges": {
+							Type: "array",
+							Items: &apiextv1beta1.JSONSchemaPropsOrArray{
+								Schema: &apiextv1beta1.JSONSchemaProps{
+									Type:     "object",
+									Required: []string{"start", "end"},
+									Properties: map[string]apiextv1beta1.JSONSchemaProps{
+										"start": {
+											Type:    "string",
+											Pattern: `^[0-9a-fA-F:\.]+$`,
+										},
+										"end": {
+											Type:    "string",
+											Pattern: `^[0-9a-fA-F:\.]+$`,
+										},
+									},
+								},
+							},
+						},
+						"bgp": {
+							Type: "object",
+							Properties: map[string]apiextv1beta1.JSONSchemaProps{
+								"linuxTable": {
+									Type:        "integer",
+									Description: "routing table of the host used to exchange routes with the BGP speaker",
+								},
+								"exportPodSubnets": {
+									Type: "boolean",
+								},
+								"importRoutes": {
+									Type: "boolean",
+								},
+							},
+						},
 					},
 				},
 			},
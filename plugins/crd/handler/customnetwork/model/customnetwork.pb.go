@@ -54,10 +54,21 @@ type CustomNetwork struct {
 	// subnet of the custom network
 	SubnetCIDR string `protobuf:"bytes,3,opt,name=subnetCIDR,proto3" json:"subnetCIDR,omitempty"`
 	// prefix length defining network subnet used for each node in the cluster
-	SubnetOneNodePrefix  uint32   `protobuf:"varint,4,opt,name=subnet_one_node_prefix,json=subnetOneNodePrefix,proto3" json:"subnet_one_node_prefix,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	SubnetOneNodePrefix uint32 `protobuf:"varint,4,opt,name=subnet_one_node_prefix,json=subnetOneNodePrefix,proto3" json:"subnet_one_node_prefix,omitempty"`
+	// VXLAN VNI pinned for the network (allocated automatically if zero)
+	Vni uint32 `protobuf:"varint,5,opt,name=vni,proto3" json:"vni,omitempty"`
+	// VRF ID pinned for the L3 network (allocated automatically if zero)
+	Vrf uint32 `protobuf:"varint,6,opt,name=vrf,proto3" json:"vrf,omitempty"`
+	// gateway IP address used for pods of the L3 network on every node
+	// (the first host address of the node subnet is used if empty)
+	Gateway string `protobuf:"bytes,7,opt,name=gateway,proto3" json:"gateway,omitempty"`
+	// address ranges of the network subnet excluded from the IP address allocation
+	ReservedRanges []*CustomNetwork_IPRange `protobuf:"bytes,8,rep,name=reserved_ranges,json=reservedRanges,proto3" json:"reserved_ranges,omitempty"`
+	// BGP interconnection of the L3 network (disabled if not set)
+	Bgp                  *CustomNetwork_BGP `protobuf:"bytes,9,opt,name=bgp,proto3" json:"bgp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *CustomNetwork) Reset()         { *m = CustomNetwork{} }
@@ -113,26 +124,183 @@ func (m *CustomNetwork) GetSubnetOneNodePrefix() uint32 {
 	return 0
 }
 
+func (m *CustomNetwork) GetVni() uint32 {
+	if m != nil {
+		return m.Vni
+	}
+	return 0
+}
+
+func (m *CustomNetwork) GetVrf() uint32 {
+	if m != nil {
+		return m.Vrf
+	}
+	return 0
+}
+
+func (m *CustomNetwork) GetGateway() string {
+	if m != nil {
+		return m.Gateway
+	}
+	return ""
+}
+
+func (m *CustomNetwork) GetReservedRanges() []*CustomNetwork_IPRange {
+	if m != nil {
+		return m.ReservedRanges
+	}
+	return nil
+}
+
+func (m *CustomNetwork) GetBgp() *CustomNetwork_BGP {
+	if m != nil {
+		return m.Bgp
+	}
+	return nil
+}
+
+// IPRange is an inclusive range of IP addresses.
+type CustomNetwork_IPRange struct {
+	Start                string   `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End                  string   `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CustomNetwork_IPRange) Reset()         { *m = CustomNetwork_IPRange{} }
+func (m *CustomNetwork_IPRange) String() string { return proto.CompactTextString(m) }
+func (*CustomNetwork_IPRange) ProtoMessage()    {}
+func (*CustomNetwork_IPRange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b52dd016a515b4b0, []int{0, 0}
+}
+
+func (m *CustomNetwork_IPRange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CustomNetwork_IPRange.Unmarshal(m, b)
+}
+func (m *CustomNetwork_IPRange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CustomNetwork_IPRange.Marshal(b, m, deterministic)
+}
+func (m *CustomNetwork_IPRange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CustomNetwork_IPRange.Merge(m, src)
+}
+func (m *CustomNetwork_IPRange) XXX_Size() int {
+	return xxx_messageInfo_CustomNetwork_IPRange.Size(m)
+}
+func (m *CustomNetwork_IPRange) XXX_DiscardUnknown() {
+	xxx_messageInfo_CustomNetwork_IPRange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CustomNetwork_IPRange proto.InternalMessageInfo
+
+func (m *CustomNetwork_IPRange) GetStart() string {
+	if m != nil {
+		return m.Start
+	}
+	return ""
+}
+
+func (m *CustomNetwork_IPRange) GetEnd() string {
+	if m != nil {
+		return m.End
+	}
+	return ""
+}
+
+// BGP defines interconnection of the L3 network with external routers via BGP
+// speaker running in the host network stack.
+type CustomNetwork_BGP struct {
+	// routing table of the host network stack used to exchange routes with the BGP speaker
+	// (VRF ID of the network is used if zero)
+	LinuxTable uint32 `protobuf:"varint,1,opt,name=linux_table,json=linuxTable,proto3" json:"linux_table,omitempty"`
+	// export per-node pod subnets of the network to the BGP speaker
+	ExportPodSubnets bool `protobuf:"varint,2,opt,name=export_pod_subnets,json=exportPodSubnets,proto3" json:"export_pod_subnets,omitempty"`
+	// import routes learned by the BGP speaker into the VRF of the network
+	ImportRoutes         bool     `protobuf:"varint,3,opt,name=import_routes,json=importRoutes,proto3" json:"import_routes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CustomNetwork_BGP) Reset()         { *m = CustomNetwork_BGP{} }
+func (m *CustomNetwork_BGP) String() string { return proto.CompactTextString(m) }
+func (*CustomNetwork_BGP) ProtoMessage()    {}
+func (*CustomNetwork_BGP) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b52dd016a515b4b0, []int{0, 1}
+}
+
+func (m *CustomNetwork_BGP) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CustomNetwork_BGP.Unmarshal(m, b)
+}
+func (m *CustomNetwork_BGP) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CustomNetwork_BGP.Marshal(b, m, deterministic)
+}
+func (m *CustomNetwork_BGP) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CustomNetwork_BGP.Merge(m, src)
+}
+func (m *CustomNetwork_BGP) XXX_Size() int {
+	return xxx_messageInfo_CustomNetwork_BGP.Size(m)
+}
+func (m *CustomNetwork_BGP) XXX_DiscardUnknown() {
+	xxx_messageInfo_CustomNetwork_BGP.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CustomNetwork_BGP proto.InternalMessageInfo
+
+func (m *CustomNetwork_BGP) GetLinuxTable() uint32 {
+	if m != nil {
+		return m.LinuxTable
+	}
+	return 0
+}
+
+func (m *CustomNetwork_BGP) GetExportPodSubnets() bool {
+	if m != nil {
+		return m.ExportPodSubnets
+	}
+	return false
+}
+
+func (m *CustomNetwork_BGP) GetImportRoutes() bool {
+	if m != nil {
+		return m.ImportRoutes
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterEnum("model.CustomNetwork_Type", CustomNetwork_Type_name, CustomNetwork_Type_value)
 	proto.RegisterType((*CustomNetwork)(nil), "model.CustomNetwork")
+	proto.RegisterType((*CustomNetwork_IPRange)(nil), "model.CustomNetwork.IPRange")
+	proto.RegisterType((*CustomNetwork_BGP)(nil), "model.CustomNetwork.BGP")
 }
 
 func init() { proto.RegisterFile("customnetwork.proto", fileDescriptor_b52dd016a515b4b0) }
 
 var fileDescriptor_b52dd016a515b4b0 = []byte{
-	// 195 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x4e, 0x2e, 0x2d, 0x2e,
-	0xc9, 0xcf, 0xcd, 0x4b, 0x2d, 0x29, 0xcf, 0x2f, 0xca, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17,
-	0x62, 0xcd, 0xcd, 0x4f, 0x49, 0xcd, 0x51, 0xda, 0xcf, 0xc8, 0xc5, 0xeb, 0x0c, 0x96, 0xf6, 0x83,
-	0x48, 0x0b, 0x09, 0x71, 0xb1, 0xe4, 0x25, 0xe6, 0xa6, 0x4a, 0x30, 0x2a, 0x30, 0x6a, 0x70, 0x06,
-	0x81, 0xd9, 0x42, 0xba, 0x5c, 0x2c, 0x25, 0x95, 0x05, 0xa9, 0x12, 0x4c, 0x0a, 0x8c, 0x1a, 0x7c,
-	0x46, 0x92, 0x7a, 0x60, 0xbd, 0x7a, 0x28, 0xfa, 0xf4, 0x42, 0x2a, 0x0b, 0x52, 0x83, 0xc0, 0xca,
-	0x84, 0xe4, 0xb8, 0xb8, 0x8a, 0x4b, 0x93, 0xf2, 0x52, 0x4b, 0x9c, 0x3d, 0x5d, 0x82, 0x24, 0x98,
-	0xc1, 0x06, 0x21, 0x89, 0x08, 0x19, 0x73, 0x89, 0x41, 0x78, 0xf1, 0xf9, 0x79, 0xa9, 0xf1, 0x79,
-	0xf9, 0x29, 0xa9, 0xf1, 0x05, 0x45, 0xa9, 0x69, 0x99, 0x15, 0x12, 0x2c, 0x0a, 0x8c, 0x1a, 0xbc,
-	0x41, 0xc2, 0x10, 0x59, 0xff, 0xbc, 0x54, 0xbf, 0xfc, 0x94, 0xd4, 0x00, 0xb0, 0x94, 0x92, 0x18,
-	0x17, 0x0b, 0xc8, 0x0a, 0x21, 0x36, 0x2e, 0x26, 0x1f, 0x23, 0x01, 0x06, 0x30, 0x6d, 0x2c, 0xc0,
-	0x98, 0xc4, 0x06, 0xf6, 0x8f, 0x31, 0x20, 0x00, 0x00, 0xff, 0xff, 0x3d, 0xa1, 0xbd, 0x06, 0xe6,
-	0x00, 0x00, 0x00,
+	// 378 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x6f, 0x6b, 0xd4, 0x40,
+	0x10, 0xc6, 0x4d, 0x93, 0xfb, 0x37, 0xe7, 0xd5, 0x63, 0x2a, 0x65, 0x2d, 0xa2, 0xa1, 0xbe, 0x09,
+	0xa2, 0x01, 0x73, 0xdf, 0xa0, 0x55, 0x4a, 0x41, 0x6a, 0x58, 0xfb, 0x3e, 0x24, 0x66, 0xee, 0x08,
+	0x5e, 0x76, 0xc3, 0xee, 0xa6, 0xbd, 0x7c, 0x19, 0x3f, 0xab, 0x64, 0xf6, 0x0a, 0x0a, 0x7d, 0x95,
+	0x99, 0xdf, 0xf3, 0xcc, 0x64, 0x77, 0x66, 0xe1, 0xec, 0x57, 0x6f, 0x9d, 0x6e, 0x15, 0xb9, 0x47,
+	0x6d, 0x7e, 0xa7, 0x9d, 0xd1, 0x4e, 0xe3, 0xa4, 0xd5, 0x35, 0xed, 0x2f, 0xff, 0x44, 0xb0, 0xba,
+	0x66, 0xf9, 0xce, 0xcb, 0x88, 0x10, 0xa9, 0xb2, 0x25, 0x11, 0xc4, 0x41, 0xb2, 0x90, 0x1c, 0xe3,
+	0x67, 0x88, 0xdc, 0xd0, 0x91, 0x38, 0x89, 0x83, 0xe4, 0x34, 0x7b, 0x93, 0x72, 0x6d, 0xfa, 0x5f,
+	0x5d, 0x7a, 0x3f, 0x74, 0x24, 0xd9, 0x86, 0xef, 0x00, 0x6c, 0x5f, 0x29, 0x72, 0xd7, 0xb7, 0x5f,
+	0xa5, 0x08, 0xb9, 0xd1, 0x3f, 0x04, 0x37, 0x70, 0xee, 0xb3, 0x42, 0x2b, 0x2a, 0x94, 0xae, 0xa9,
+	0xe8, 0x0c, 0x6d, 0x9b, 0x83, 0x88, 0xe2, 0x20, 0x59, 0xc9, 0x33, 0xaf, 0xfe, 0x50, 0x74, 0xa7,
+	0x6b, 0xca, 0x59, 0xc2, 0x35, 0x84, 0x0f, 0xaa, 0x11, 0x13, 0x76, 0x8c, 0x21, 0x13, 0xb3, 0x15,
+	0xd3, 0x23, 0x31, 0x5b, 0x14, 0x30, 0xdb, 0x95, 0x8e, 0x1e, 0xcb, 0x41, 0xcc, 0xf8, 0xaf, 0x4f,
+	0x29, 0x7e, 0x83, 0x57, 0x86, 0x2c, 0x99, 0x07, 0xaa, 0x0b, 0x53, 0xaa, 0x1d, 0x59, 0x31, 0x8f,
+	0xc3, 0x64, 0x99, 0xbd, 0x7d, 0xf6, 0x32, 0xb7, 0xb9, 0x1c, 0x4d, 0xf2, 0xf4, 0xa9, 0x88, 0x53,
+	0x8b, 0x1f, 0x21, 0xac, 0x76, 0x9d, 0x58, 0xc4, 0x41, 0xb2, 0xcc, 0xc4, 0xb3, 0xa5, 0x57, 0x37,
+	0xb9, 0x1c, 0x4d, 0x17, 0x5f, 0x60, 0x76, 0x6c, 0x83, 0xaf, 0x61, 0x62, 0x5d, 0x69, 0xdc, 0x71,
+	0xa8, 0x3e, 0x19, 0xcf, 0x4f, 0xaa, 0xe6, 0xa1, 0x2e, 0xe4, 0x18, 0x5e, 0x0c, 0x10, 0x5e, 0xdd,
+	0xe4, 0xf8, 0x1e, 0x96, 0xfb, 0x46, 0xf5, 0x87, 0xc2, 0x95, 0xd5, 0xde, 0x6f, 0x62, 0x25, 0x81,
+	0xd1, 0xfd, 0x48, 0xf0, 0x13, 0x20, 0x1d, 0x3a, 0x6d, 0x5c, 0xd1, 0xe9, 0xba, 0xf0, 0xd3, 0xb2,
+	0xdc, 0x68, 0x2e, 0xd7, 0x5e, 0xc9, 0x75, 0xfd, 0xd3, 0x73, 0xfc, 0x00, 0xab, 0xa6, 0x65, 0xb7,
+	0xd1, 0xbd, 0x23, 0xcb, 0x1b, 0x99, 0xcb, 0x97, 0x1e, 0x4a, 0x66, 0x97, 0xe7, 0x10, 0x8d, 0x1b,
+	0xc4, 0x29, 0x9c, 0x7c, 0xcf, 0xd6, 0x2f, 0xf8, 0xbb, 0x59, 0x07, 0xd5, 0x94, 0x9f, 0xcb, 0xe6,
+	0xef, 0x00, 0x27, 0x94, 0x8a, 0x69, 0x45, 0x02, 0x00, 0x00,
 }
@@ -0,0 +1,175 @@
+/*
+ * // Copyright (c) 2019 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+//go:generate protoc -I ./model --go_out=plugins=grpc:./model ./model/egressgateway.proto
+
+package egressgateway
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/contiv/vpp/plugins/crd/handler/egressgateway/model"
+	"github.com/contiv/vpp/plugins/crd/handler/kvdbreflector"
+	"github.com/contiv/vpp/plugins/crd/pkg/apis/contivppio/v1"
+	crdClientSet "github.com/contiv/vpp/plugins/crd/pkg/client/clientset/versioned"
+	apiextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+)
+
+// Handler implements the Handler interface for CRD<->KVDB Reflector.
+type Handler struct {
+	CrdClient *crdClientSet.Clientset
+}
+
+// CrdName returns name of the CRD.
+func (h *Handler) CrdName() string {
+	return "EgressGateway"
+}
+
+// CrdKeyPrefix returns the longest-common prefix under which the instances
+// of the given CRD are reflected into KVDB.
+func (h *Handler) CrdKeyPrefix() (prefix string, underKsrPrefix bool) {
+	return model.Keyword + "/", true
+}
+
+// IsCrdKeySuffix always returns true - the key prefix does not overlap with
+// other CRDs or KSR-reflected K8s data.
+func (h *Handler) IsCrdKeySuffix(keySuffix string) bool {
+	return true
+}
+
+// CrdObjectToKVData converts the K8s representation of EgressGateway into the
+// corresponding proto message representation.
+func (h *Handler) CrdObjectToKVData(obj interface{}) (data []kvdbreflector.KVData, err error) {
+	egressGw, ok := obj.(*v1.EgressGateway)
+	if !ok {
+		return nil, errors.New("failed to cast into EgressGateway struct")
+	}
+	if err = validateEgressIPs(egressGw.Spec.EgressIPs); err != nil {
+		return nil, err
+	}
+	data = []kvdbreflector.KVData{
+		{
+			ProtoMsg:  h.egressGatewayToProto(egressGw),
+			KeySuffix: egressGw.GetName(),
+		},
+	}
+	return
+}
+
+// IsExclusiveKVDB returns true - this is the only writer for EgressGateway KVs
+// in the database.
+func (h *Handler) IsExclusiveKVDB() bool {
+	return true
+}
+
+// PublishCrdStatus updates the resource Status information.
+func (h *Handler) PublishCrdStatus(obj interface{}, opRetval error) error {
+	egressGw, ok := obj.(*v1.EgressGateway)
+	if !ok {
+		return errors.New("failed to cast into EgressGateway struct")
+	}
+	egressGw = egressGw.DeepCopy()
+	if opRetval == nil {
+		egressGw.Status.Status = v1.StatusSuccess
+	} else {
+		egressGw.Status.Status = v1.StatusFailure
+		egressGw.Status.Message = opRetval.Error()
+	}
+	_, err := h.CrdClient.ContivppV1().EgressGateways(egressGw.Namespace).Update(egressGw)
+	return err
+}
+
+func (h *Handler) egressGatewayToProto(egressGw *v1.EgressGateway) *model.EgressGateway {
+	protoVal := &model.EgressGateway{
+		Name:       egressGw.Name,
+		Namespaces: egressGw.Spec.Namespaces,
+		Node:       egressGw.Spec.Node,
+	}
+	if len(egressGw.Spec.PodSelector) > 0 {
+		protoVal.PodSelector = make(map[string]string)
+		for label, value := range egressGw.Spec.PodSelector {
+			protoVal.PodSelector[label] = value
+		}
+	}
+	for _, egressIP := range egressGw.Spec.EgressIPs {
+		protoVal.EgressIps = append(protoVal.EgressIps, strings.TrimSpace(egressIP))
+	}
+	return protoVal
+}
+
+// validateEgressIPs checks that the egress IPs are valid IPv4 addresses or subnets.
+func validateEgressIPs(egressIPs []string) error {
+	if len(egressIPs) == 0 {
+		return errors.New("no egress IP defined")
+	}
+	for _, egressIP := range egressIPs {
+		egressIP = strings.TrimSpace(egressIP)
+		ip := net.ParseIP(egressIP)
+		if strings.Contains(egressIP, "/") {
+			ip, _, _ = net.ParseCIDR(egressIP)
+		}
+		if ip == nil || ip.To4() == nil {
+			return fmt.Errorf("invalid egress IP: %s (expected IPv4 address or subnet)", egressIP)
+		}
+	}
+	return nil
+}
+
+// Validation generates OpenAPIV3 validator for egress gateways CRD
+func Validation() *apiextv1beta1.CustomResourceValidation {
+	validation := &apiextv1beta1.CustomResourceValidation{
+		OpenAPIV3Schema: &apiextv1beta1.JSONSchemaProps{
+			Required: []string{"spec"},
+			Type:     "object",
+			Properties: map[string]apiextv1beta1.JSONSchemaProps{
+				"spec": {
+					Type:     "object",
+					Required: []string{"node", "egressIPs"},
+					Properties: map[string]apiextv1beta1.JSONSchemaProps{
+						"namespaces": {
+							Type: "array",
+							Items: &apiextv1beta1.JSONSchemaPropsOrArray{
+								Schema: &apiextv1beta1.JSONSchemaProps{
+									Type: "string",
+								},
+							},
+						},
+						"podSelector": {
+							Type: "object",
+						},
+						"node": {
+							Type: "string",
+						},
+						"egressIPs": {
+							Type: "array",
+							Items: &apiextv1beta1.JSONSchemaPropsOrArray{
+								Schema: &apiextv1beta1.JSONSchemaProps{
+									Type:        "string",
+									Description: "IPv4 address or subnet",
+									Pattern:     `^[0-9\.]+(/[0-9]+)?$`,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	return validation
+}
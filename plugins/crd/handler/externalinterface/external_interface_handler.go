@@ -20,6 +20,7 @@ package externalinterface
 
 import (
 	"errors"
+	"reflect"
 
 	"github.com/contiv/vpp/plugins/crd/handler/externalinterface/model"
 	"github.com/contiv/vpp/plugins/crd/handler/kvdbreflector"
@@ -74,16 +75,21 @@ func (h *Handler) IsExclusiveKVDB() bool {
 
 // PublishCrdStatus updates the resource Status information.
 func (h *Handler) PublishCrdStatus(obj interface{}, opRetval error) error {
+	return h.PublishCrdRealization(obj, opRetval, nil)
+}
+
+// PublishCrdRealization updates the resource Status information, including
+// the realization status of the external interface on every node.
+func (h *Handler) PublishCrdRealization(obj interface{}, opRetval error, realization []kvdbreflector.NodeRealization) error {
 	extIface, ok := obj.(*v1.ExternalInterface)
 	if !ok {
 		return errors.New("failed to cast into ExternalInterface struct")
 	}
 	extIface = extIface.DeepCopy()
-	if opRetval == nil {
-		extIface.Status.Status = v1.StatusSuccess
-	} else {
-		extIface.Status.Status = v1.StatusFailure
-		extIface.Status.Message = opRetval.Error()
+	prevStatus := extIface.Status
+	kvdbreflector.UpdateCrdStatus(&extIface.Status, opRetval, realization)
+	if reflect.DeepEqual(prevStatus, extIface.Status) {
+		return nil
 	}
 	_, err := h.CrdClient.ContivppV1().ExternalInterfaces(extIface.Namespace).Update(extIface)
 	return err
@@ -0,0 +1,213 @@
+/*
+ * // Copyright (c) 2019 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+//go:generate protoc -I ./model --go_out=plugins=grpc:./model ./model/hostpolicy.proto
+
+package hostpolicy
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/contiv/vpp/plugins/crd/handler/hostpolicy/model"
+	"github.com/contiv/vpp/plugins/crd/handler/kvdbreflector"
+	"github.com/contiv/vpp/plugins/crd/pkg/apis/contivppio/v1"
+	crdClientSet "github.com/contiv/vpp/plugins/crd/pkg/client/clientset/versioned"
+	apiextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+)
+
+// Handler implements the Handler interface for CRD<->KVDB Reflector.
+type Handler struct {
+	CrdClient *crdClientSet.Clientset
+}
+
+// CrdName returns name of the CRD.
+func (h *Handler) CrdName() string {
+	return "HostPolicy"
+}
+
+// CrdKeyPrefix returns the longest-common prefix under which the instances
+// of the given CRD are reflected into KVDB.
+func (h *Handler) CrdKeyPrefix() (prefix string, underKsrPrefix bool) {
+	return model.Keyword + "/", true
+}
+
+// IsCrdKeySuffix always returns true - the key prefix does not overlap with
+// other CRDs or KSR-reflected K8s data.
+func (h *Handler) IsCrdKeySuffix(keySuffix string) bool {
+	return true
+}
+
+// CrdObjectToKVData converts the K8s representation of HostPolicy into the
+// corresponding proto message representation.
+func (h *Handler) CrdObjectToKVData(obj interface{}) (data []kvdbreflector.KVData, err error) {
+	hostPolicy, ok := obj.(*v1.HostPolicy)
+	if !ok {
+		return nil, errors.New("failed to cast into HostPolicy struct")
+	}
+	protoVal, err := h.hostPolicyToProto(hostPolicy)
+	if err != nil {
+		return nil, err
+	}
+	data = []kvdbreflector.KVData{
+		{
+			ProtoMsg:  protoVal,
+			KeySuffix: hostPolicy.GetName(),
+		},
+	}
+	return
+}
+
+// IsExclusiveKVDB returns true - this is the only writer for HostPolicy KVs
+// in the database.
+func (h *Handler) IsExclusiveKVDB() bool {
+	return true
+}
+
+// PublishCrdStatus updates the resource Status information.
+func (h *Handler) PublishCrdStatus(obj interface{}, opRetval error) error {
+	hostPolicy, ok := obj.(*v1.HostPolicy)
+	if !ok {
+		return errors.New("failed to cast into HostPolicy struct")
+	}
+	hostPolicy = hostPolicy.DeepCopy()
+	if opRetval == nil {
+		hostPolicy.Status.Status = v1.StatusSuccess
+	} else {
+		hostPolicy.Status.Status = v1.StatusFailure
+		hostPolicy.Status.Message = opRetval.Error()
+	}
+	_, err := h.CrdClient.ContivppV1().HostPolicies(hostPolicy.Namespace).Update(hostPolicy)
+	return err
+}
+
+// hostPolicyToProto validates the host policy and converts it into the proto representation.
+func (h *Handler) hostPolicyToProto(hostPolicy *v1.HostPolicy) (*model.HostPolicy, error) {
+	protoVal := &model.HostPolicy{
+		Name: hostPolicy.Name,
+	}
+	if len(hostPolicy.Spec.NodeSelector) > 0 {
+		protoVal.NodeSelector = make(map[string]string)
+		for label, value := range hostPolicy.Spec.NodeSelector {
+			protoVal.NodeSelector[label] = value
+		}
+	}
+	for _, rule := range hostPolicy.Spec.Ingress {
+		protoRule := &model.HostPolicy_Rule{}
+		for _, from := range rule.From {
+			from = strings.TrimSpace(from)
+			if !strings.Contains(from, "/") {
+				ip := net.ParseIP(from)
+				if ip == nil {
+					return nil, fmt.Errorf("invalid source: %s (expected IP address or subnet)", from)
+				}
+				if ip.To4() != nil {
+					from += "/32"
+				} else {
+					from += "/128"
+				}
+			}
+			if _, _, err := net.ParseCIDR(from); err != nil {
+				return nil, fmt.Errorf("invalid source: %s (expected IP address or subnet)", from)
+			}
+			protoRule.From = append(protoRule.From, from)
+		}
+		for _, port := range rule.Ports {
+			protoPort := &model.HostPolicy_Port{
+				Port:    uint32(port.Port),
+				EndPort: uint32(port.EndPort),
+			}
+			switch strings.ToUpper(port.Protocol) {
+			case "", "TCP":
+				protoPort.Protocol = model.HostPolicy_Port_TCP
+			case "UDP":
+				protoPort.Protocol = model.HostPolicy_Port_UDP
+			case "SCTP":
+				protoPort.Protocol = model.HostPolicy_Port_SCTP
+			default:
+				return nil, fmt.Errorf("unsupported protocol: %s", port.Protocol)
+			}
+			if port.EndPort != 0 && (port.Port == 0 || port.EndPort < port.Port) {
+				return nil, fmt.Errorf("invalid port range: %d-%d", port.Port, port.EndPort)
+			}
+			protoRule.Ports = append(protoRule.Ports, protoPort)
+		}
+		protoVal.Ingress = append(protoVal.Ingress, protoRule)
+	}
+	return protoVal, nil
+}
+
+// Validation generates OpenAPIV3 validator for host policies CRD
+func Validation() *apiextv1beta1.CustomResourceValidation {
+	validation := &apiextv1beta1.CustomResourceValidation{
+		OpenAPIV3Schema: &apiextv1beta1.JSONSchemaProps{
+			Required: []string{"spec"},
+			Type:     "object",
+			Properties: map[string]apiextv1beta1.JSONSchemaProps{
+				"spec": {
+					Type: "object",
+					Properties: map[string]apiextv1beta1.JSONSchemaProps{
+						"nodeSelector": {
+							Type: "object",
+						},
+						"ingress": {
+							Type: "array",
+							Items: &apiextv1beta1.JSONSchemaPropsOrArray{
+								Schema: &apiextv1beta1.JSONSchemaProps{
+									Type: "object",
+									Properties: map[string]apiextv1beta1.JSONSchemaProps{
+										"from": {
+											Type: "array",
+											Items: &apiextv1beta1.JSONSchemaPropsOrArray{
+												Schema: &apiextv1beta1.JSONSchemaProps{
+													Type:        "string",
+													Description: "IP address or subnet",
+												},
+											},
+										},
+										"ports": {
+											Type: "array",
+											Items: &apiextv1beta1.JSONSchemaPropsOrArray{
+												Schema: &apiextv1beta1.JSONSchemaProps{
+													Type: "object",
+													Properties: map[string]apiextv1beta1.JSONSchemaProps{
+														"protocol": {
+															Type:    "string",
+															Pattern: `^(TCP|UDP|SCTP|tcp|udp|sctp)$`,
+														},
+														"port": {
+															Type: "integer",
+														},
+														"endPort": {
+															Type: "integer",
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	return validation
+}
@@ -0,0 +1,250 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: hostpolicy.proto
+
+package model
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type HostPolicy_Port_Protocol int32
+
+const (
+	HostPolicy_Port_TCP  HostPolicy_Port_Protocol = 0
+	HostPolicy_Port_UDP  HostPolicy_Port_Protocol = 1
+	HostPolicy_Port_SCTP HostPolicy_Port_Protocol = 2
+)
+
+var HostPolicy_Port_Protocol_name = map[int32]string{
+	0: "TCP",
+	1: "UDP",
+	2: "SCTP",
+}
+
+var HostPolicy_Port_Protocol_value = map[string]int32{
+	"TCP":  0,
+	"UDP":  1,
+	"SCTP": 2,
+}
+
+func (x HostPolicy_Port_Protocol) String() string {
+	return proto.EnumName(HostPolicy_Port_Protocol_name, int32(x))
+}
+
+func (HostPolicy_Port_Protocol) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_1e2a3b7e4c6b8d10, []int{0, 1, 0}
+}
+
+// HostPolicy restricts the traffic towards the network endpoints of the selected nodes.
+type HostPolicy struct {
+	// name of the host policy
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// labels of the selected nodes (empty = all nodes)
+	NodeSelector map[string]string `protobuf:"bytes,2,rep,name=node_selector,json=nodeSelector,proto3" json:"node_selector,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// rules allowing traffic towards the selected nodes
+	Ingress              []*HostPolicy_Rule `protobuf:"bytes,3,rep,name=ingress,proto3" json:"ingress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *HostPolicy) Reset()         { *m = HostPolicy{} }
+func (m *HostPolicy) String() string { return proto.CompactTextString(m) }
+func (*HostPolicy) ProtoMessage()    {}
+func (*HostPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1e2a3b7e4c6b8d10, []int{0}
+}
+
+func (m *HostPolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HostPolicy.Unmarshal(m, b)
+}
+func (m *HostPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HostPolicy.Marshal(b, m, deterministic)
+}
+func (m *HostPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HostPolicy.Merge(m, src)
+}
+func (m *HostPolicy) XXX_Size() int {
+	return xxx_messageInfo_HostPolicy.Size(m)
+}
+func (m *HostPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_HostPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HostPolicy proto.InternalMessageInfo
+
+func (m *HostPolicy) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *HostPolicy) GetNodeSelector() map[string]string {
+	if m != nil {
+		return m.NodeSelector
+	}
+	return nil
+}
+
+func (m *HostPolicy) GetIngress() []*HostPolicy_Rule {
+	if m != nil {
+		return m.Ingress
+	}
+	return nil
+}
+
+// Rule allows traffic from the given sources to the given ports.
+type HostPolicy_Rule struct {
+	// source subnets (empty = any source)
+	From []string `protobuf:"bytes,1,rep,name=from,proto3" json:"from,omitempty"`
+	// destination ports (empty = all ports)
+	Ports                []*HostPolicy_Port `protobuf:"bytes,2,rep,name=ports,proto3" json:"ports,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *HostPolicy_Rule) Reset()         { *m = HostPolicy_Rule{} }
+func (m *HostPolicy_Rule) String() string { return proto.CompactTextString(m) }
+func (*HostPolicy_Rule) ProtoMessage()    {}
+func (*HostPolicy_Rule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1e2a3b7e4c6b8d10, []int{0, 0}
+}
+
+func (m *HostPolicy_Rule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HostPolicy_Rule.Unmarshal(m, b)
+}
+func (m *HostPolicy_Rule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HostPolicy_Rule.Marshal(b, m, deterministic)
+}
+func (m *HostPolicy_Rule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HostPolicy_Rule.Merge(m, src)
+}
+func (m *HostPolicy_Rule) XXX_Size() int {
+	return xxx_messageInfo_HostPolicy_Rule.Size(m)
+}
+func (m *HostPolicy_Rule) XXX_DiscardUnknown() {
+	xxx_messageInfo_HostPolicy_Rule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HostPolicy_Rule proto.InternalMessageInfo
+
+func (m *HostPolicy_Rule) GetFrom() []string {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *HostPolicy_Rule) GetPorts() []*HostPolicy_Port {
+	if m != nil {
+		return m.Ports
+	}
+	return nil
+}
+
+// Port is a destination port or a range of ports.
+type HostPolicy_Port struct {
+	Protocol HostPolicy_Port_Protocol `protobuf:"varint,1,opt,name=protocol,proto3,enum=model.HostPolicy_Port_Protocol" json:"protocol,omitempty"`
+	// destination port number (0 = all ports)
+	Port uint32 `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	// end of the port range (0 = single port)
+	EndPort              uint32   `protobuf:"varint,3,opt,name=end_port,json=endPort,proto3" json:"end_port,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HostPolicy_Port) Reset()         { *m = HostPolicy_Port{} }
+func (m *HostPolicy_Port) String() string { return proto.CompactTextString(m) }
+func (*HostPolicy_Port) ProtoMessage()    {}
+func (*HostPolicy_Port) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1e2a3b7e4c6b8d10, []int{0, 1}
+}
+
+func (m *HostPolicy_Port) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HostPolicy_Port.Unmarshal(m, b)
+}
+func (m *HostPolicy_Port) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HostPolicy_Port.Marshal(b, m, deterministic)
+}
+func (m *HostPolicy_Port) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HostPolicy_Port.Merge(m, src)
+}
+func (m *HostPolicy_Port) XXX_Size() int {
+	return xxx_messageInfo_HostPolicy_Port.Size(m)
+}
+func (m *HostPolicy_Port) XXX_DiscardUnknown() {
+	xxx_messageInfo_HostPolicy_Port.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HostPolicy_Port proto.InternalMessageInfo
+
+func (m *HostPolicy_Port) GetProtocol() HostPolicy_Port_Protocol {
+	if m != nil {
+		return m.Protocol
+	}
+	return HostPolicy_Port_TCP
+}
+
+func (m *HostPolicy_Port) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *HostPolicy_Port) GetEndPort() uint32 {
+	if m != nil {
+		return m.EndPort
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("model.HostPolicy_Port_Protocol", HostPolicy_Port_Protocol_name, HostPolicy_Port_Protocol_value)
+	proto.RegisterType((*HostPolicy)(nil), "model.HostPolicy")
+	proto.RegisterMapType((map[string]string)(nil), "model.HostPolicy.NodeSelectorEntry")
+	proto.RegisterType((*HostPolicy_Rule)(nil), "model.HostPolicy.Rule")
+	proto.RegisterType((*HostPolicy_Port)(nil), "model.HostPolicy.Port")
+}
+
+func init() { proto.RegisterFile("hostpolicy.proto", fileDescriptor_1e2a3b7e4c6b8d10) }
+
+var fileDescriptor_1e2a3b7e4c6b8d10 = []byte{
+	// 313 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0x41, 0x4f, 0xfa, 0x40,
+	0x10, 0xc5, 0xff, 0xdb, 0x2d, 0x7f, 0xca, 0x28, 0xa6, 0x4e, 0x8c, 0xa9, 0x5c, 0x6c, 0x30, 0x31,
+	0x1c, 0x4c, 0x63, 0xf0, 0x62, 0xf4, 0xe0, 0x01, 0x4d, 0x38, 0x99, 0x66, 0xc1, 0x33, 0x41, 0x3a,
+	0x2a, 0xb1, 0xec, 0x90, 0xed, 0x62, 0xc2, 0x57, 0xf1, 0xf3, 0xf8, 0xc1, 0xcc, 0x2e, 0xa0, 0x26,
+	0xe8, 0xed, 0xcd, 0xeb, 0x9b, 0x5f, 0x5f, 0xa7, 0x10, 0xbf, 0x70, 0x65, 0xe7, 0x5c, 0x4e, 0x27,
+	0xcb, 0x6c, 0x6e, 0xd8, 0x32, 0xd6, 0x66, 0x5c, 0x50, 0xd9, 0xfe, 0x90, 0x00, 0x7d, 0xae, 0x6c,
+	0xee, 0x9f, 0x21, 0x42, 0xa8, 0xc7, 0x33, 0x4a, 0x44, 0x2a, 0x3a, 0x0d, 0xe5, 0x35, 0xf6, 0xa1,
+	0xa9, 0xb9, 0xa0, 0x51, 0x45, 0x25, 0x4d, 0x2c, 0x9b, 0x24, 0x48, 0x65, 0x67, 0xa7, 0x7b, 0x92,
+	0x79, 0x42, 0xf6, 0xbd, 0x9d, 0xdd, 0x73, 0x41, 0x83, 0x75, 0xea, 0x4e, 0x5b, 0xb3, 0x54, 0xbb,
+	0xfa, 0x87, 0x85, 0xe7, 0x50, 0x9f, 0xea, 0x67, 0x43, 0x55, 0x95, 0x48, 0xcf, 0x38, 0xdc, 0x66,
+	0xa8, 0x45, 0x49, 0x6a, 0x13, 0x6b, 0xf5, 0x21, 0x74, 0x86, 0xeb, 0xf5, 0x64, 0x78, 0x96, 0x88,
+	0x54, 0xba, 0x5e, 0x4e, 0xe3, 0x19, 0xd4, 0xe6, 0x6c, 0x6c, 0x95, 0x04, 0x7f, 0xb1, 0x72, 0x36,
+	0x56, 0xad, 0x42, 0xad, 0x77, 0x01, 0xa1, 0x9b, 0xf1, 0x1a, 0x22, 0x7f, 0x81, 0x09, 0x97, 0xfe,
+	0x33, 0xf7, 0xba, 0xc7, 0xbf, 0x6f, 0x66, 0xf9, 0x3a, 0xa6, 0xbe, 0x16, 0x5c, 0x0f, 0x87, 0x4b,
+	0x82, 0x54, 0x74, 0x9a, 0xca, 0x6b, 0x3c, 0x82, 0x88, 0x74, 0x31, 0xf2, 0xbe, 0xf4, 0x7e, 0x9d,
+	0x74, 0xe1, 0x08, 0xed, 0x53, 0x88, 0x36, 0x10, 0xac, 0x83, 0x1c, 0xf6, 0xf2, 0xf8, 0x9f, 0x13,
+	0x0f, 0xb7, 0x79, 0x2c, 0x30, 0x82, 0x70, 0xd0, 0x1b, 0xe6, 0x71, 0xd0, 0xba, 0x81, 0xfd, 0xad,
+	0xdb, 0x61, 0x0c, 0xf2, 0x95, 0x96, 0xeb, 0x5f, 0xe1, 0x24, 0x1e, 0x40, 0xed, 0x6d, 0x5c, 0x2e,
+	0xc8, 0xbf, 0xbe, 0xa1, 0x56, 0xc3, 0x55, 0x70, 0x29, 0x1e, 0xff, 0xfb, 0x86, 0x17, 0x9f, 0x03,
+	0x00, 0x5d, 0x77, 0x12, 0x7d, 0xe8, 0x01, 0x00, 0x00,
+}
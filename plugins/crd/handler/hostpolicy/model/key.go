@@ -0,0 +1,46 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+
+	"github.com/contiv/vpp/plugins/ksr/model/ksrkey"
+)
+
+// Keyword defines the keyword identifying host policy data.
+const Keyword = "host-policy"
+
+// KeyPrefix return prefix where all host policies are persisted.
+func KeyPrefix() string {
+	return ksrkey.KsrK8sPrefix + "/" + Keyword + "/"
+}
+
+// Key returns the key for a given host policy.
+func Key(name string) string {
+	return KeyPrefix() + name
+}
+
+// ParseKey parses host policy name from key.
+// Returns empty string if the key is not a host policy key.
+func ParseKey(key string) (name string) {
+	if strings.HasPrefix(key, KeyPrefix()) {
+		name = strings.TrimPrefix(key, KeyPrefix())
+		if name != "" && !strings.Contains(name, "/") {
+			return name
+		}
+	}
+	return ""
+}
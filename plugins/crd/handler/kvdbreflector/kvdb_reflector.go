@@ -47,6 +47,7 @@ type KvdbReflector struct {
 	dsSynced bool
 	dsMutex  sync.Mutex
 
+	keyPrefix  string
 	broker     keyval.BytesBroker
 	serializer keyval.Serializer
 
@@ -108,6 +109,10 @@ type Deps struct {
 	ServiceLabel servicelabel.ReaderAPI
 	Informer     k8sCache.SharedIndexInformer
 	Handler      Handler
+
+	// Realization is optional - if set and Handler implements RealizationHandler,
+	// per-node realization status is included in the published CRD status.
+	Realization *RealizationWatcher
 }
 
 // DsItems defines the structure holding items listed from the data store.
@@ -120,9 +125,14 @@ func (r *KvdbReflector) Init() error {
 		prefix = r.ServiceLabel.GetAgentPrefix() + ksrkey.KsrK8sPrefix +
 			"/" + prefix
 	}
+	r.keyPrefix = prefix
 	r.broker = r.Publish.NewBroker(prefix)
 	r.serializer = &keyval.SerializerJSON{}
 
+	if _, withRealization := r.Handler.(RealizationHandler); withRealization && r.Realization != nil {
+		r.Realization.Subscribe(r)
+	}
+
 	r.syncStopCh = make(chan bool, 1)
 	return nil
 }
@@ -155,11 +165,54 @@ func (r *KvdbReflector) ObjectCreated(obj interface{}) error {
 	return nil
 }
 
-// PublishStatus is just forwarded to the handler.
+// PublishStatus is forwarded to the handler, together with the per-node realization
+// status if supported by the handler.
 func (r *KvdbReflector) PublishStatus(obj interface{}, opRetval error) error {
+	if realizationHandler, withRealization := r.Handler.(RealizationHandler); withRealization && r.Realization != nil {
+		configKeys, _ := r.objectKeys(obj)
+		return realizationHandler.PublishCrdRealization(obj, opRetval, r.Realization.Summarize(configKeys))
+	}
 	return r.Handler.PublishCrdStatus(obj, opRetval)
 }
 
+// realizationChanged re-publishes status of all CRD instances affected
+// by changed realization statuses (nil = all keys).
+func (r *KvdbReflector) realizationChanged(configKeys map[string]struct{}) {
+	realizationHandler := r.Handler.(RealizationHandler)
+	for _, obj := range r.Informer.GetStore().List() {
+		objKeys, err := r.objectKeys(obj)
+		if err != nil {
+			continue
+		}
+		affected := configKeys == nil
+		for _, key := range objKeys {
+			if _, changed := configKeys[key]; changed {
+				affected = true
+				break
+			}
+		}
+		if !affected {
+			continue
+		}
+		err = realizationHandler.PublishCrdRealization(obj, nil, r.Realization.Summarize(objKeys))
+		if err != nil {
+			r.Log.Errorf("Failed to publish realization status for %s: %v", r.Handler.CrdName(), err)
+		}
+	}
+}
+
+// objectKeys returns full keys under which the given CRD instance is reflected into KVDB.
+func (r *KvdbReflector) objectKeys(obj interface{}) (keys []string, err error) {
+	kvdata, err := r.Handler.CrdObjectToKVData(obj)
+	if err != nil {
+		return nil, err
+	}
+	for _, kv := range kvdata {
+		keys = append(keys, r.keyPrefix+kv.KeySuffix)
+	}
+	return keys, nil
+}
+
 func (r *KvdbReflector) marshalData(kvdata KVData) ([]byte, error) {
 	if len(kvdata.MarshalledData) > 0 {
 		// already marshalled by the handler
@@ -0,0 +1,276 @@
+/*
+ * // Copyright (c) 2019 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+package kvdbreflector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.ligato.io/cn-infra/v2/datasync"
+	"go.ligato.io/cn-infra/v2/db/keyval"
+	"go.ligato.io/cn-infra/v2/logging"
+	"go.ligato.io/cn-infra/v2/servicelabel"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/contiv/vpp/plugins/crd/pkg/apis/contivppio/v1"
+	"github.com/contiv/vpp/plugins/crdstatus/model"
+	"github.com/contiv/vpp/plugins/ksr"
+)
+
+// RealizationHandler can be optionally implemented by Handler to include
+// the realization status of the reflected configuration on the individual
+// nodes in the Status information of the resource.
+type RealizationHandler interface {
+	// PublishCrdRealization should update the Status information associated
+	// with the resource, including the per-node realization status.
+	PublishCrdRealization(obj interface{}, opRetval error, realization []NodeRealization) error
+}
+
+// NodeRealization summarizes the realization status of a CRD instance on a single node.
+type NodeRealization struct {
+	Node      string
+	State     model.RealizationStatus_State
+	Error     string
+	Timestamp int64 // Unix time in seconds, 0 if no status was published yet
+}
+
+// RealizationWatcher collects realization statuses published by vswitches
+// into KVDB and notifies KvdbReflectors about the changes.
+type RealizationWatcher struct {
+	Log     logging.Logger
+	Publish keyval.KvBytesPlugin
+
+	sync.Mutex
+	serializer keyval.Serializer
+	statuses   map[string]map[string]*model.RealizationStatus // config key -> node -> status
+	nodes      map[string]int                                 // node -> number of published statuses
+	reflectors []*KvdbReflector
+}
+
+// Subscribe registers KvdbReflector to be notified about changes of the realization
+// statuses.
+func (w *RealizationWatcher) Subscribe(reflector *KvdbReflector) {
+	w.Lock()
+	defer w.Unlock()
+	w.reflectors = append(w.reflectors, reflector)
+}
+
+// Run loads the already published realization statuses and starts watching for changes.
+func (w *RealizationWatcher) Run(ctx <-chan struct{}) {
+	w.Lock()
+	w.serializer = &keyval.SerializerJSON{}
+	w.statuses = make(map[string]map[string]*model.RealizationStatus)
+	w.nodes = make(map[string]int)
+
+	// load statuses published so far
+	broker := w.Publish.NewBroker("")
+	kvi, err := broker.ListValues(model.KeyPrefix)
+	if err != nil {
+		w.Log.Errorf("Failed to list realization statuses: %v", err)
+	} else {
+		for {
+			kv, stop := kvi.GetNext()
+			if stop {
+				break
+			}
+			w.putStatus(kv.GetKey(), kv.GetValue())
+		}
+	}
+	w.Unlock()
+	w.notifyReflectors(nil)
+
+	// watch for changes
+	closeCh := make(chan string)
+	respCh := make(chan keyval.BytesWatchResp, 100)
+	err = w.Publish.NewWatcher("").Watch(
+		func(resp keyval.BytesWatchResp) { respCh <- resp }, closeCh, model.KeyPrefix)
+	if err != nil {
+		w.Log.Errorf("Failed to watch realization statuses: %v", err)
+		return
+	}
+	for {
+		select {
+		case resp := <-respCh:
+			configKey := w.processChange(resp)
+			if configKey != "" {
+				w.notifyReflectors(map[string]struct{}{configKey: {}})
+			}
+		case <-ctx:
+			close(closeCh)
+			return
+		}
+	}
+}
+
+// Summarize returns the realization status of configuration reflected under
+// the given keys, for every node expected to apply it.
+func (w *RealizationWatcher) Summarize(configKeys []string) (realization []NodeRealization) {
+	w.Lock()
+	defer w.Unlock()
+
+	ksrPrefix := servicelabel.GetDifferentAgentPrefix(ksr.MicroserviceLabel)
+	perNode := make(map[string]*NodeRealization)
+	for _, key := range configKeys {
+		for node := range w.nodes {
+			if !strings.HasPrefix(key, ksrPrefix) &&
+				!strings.HasPrefix(key, servicelabel.GetDifferentAgentPrefix(node)) {
+				// configuration for a different microservice
+				continue
+			}
+			nodeRealization, hasNode := perNode[node]
+			if !hasNode {
+				nodeRealization = &NodeRealization{Node: node, State: model.RealizationStatus_SUCCESS}
+				perNode[node] = nodeRealization
+			}
+			status, hasStatus := w.statuses[key][node]
+			if !hasStatus {
+				if nodeRealization.State == model.RealizationStatus_SUCCESS {
+					nodeRealization.State = model.RealizationStatus_PENDING
+				}
+				continue
+			}
+			if status.Timestamp > nodeRealization.Timestamp {
+				nodeRealization.Timestamp = status.Timestamp
+			}
+			switch status.State {
+			case model.RealizationStatus_ERROR:
+				if nodeRealization.State != model.RealizationStatus_ERROR {
+					nodeRealization.State = model.RealizationStatus_ERROR
+					nodeRealization.Error = status.Error
+				}
+			case model.RealizationStatus_PENDING:
+				if nodeRealization.State == model.RealizationStatus_SUCCESS {
+					nodeRealization.State = model.RealizationStatus_PENDING
+				}
+			}
+		}
+	}
+
+	for _, nodeRealization := range perNode {
+		realization = append(realization, *nodeRealization)
+	}
+	sort.Slice(realization, func(i, j int) bool {
+		return realization[i].Node < realization[j].Node
+	})
+	return realization
+}
+
+// processChange applies a change of a realization status.
+// Returns the key of the configuration whose status has changed.
+func (w *RealizationWatcher) processChange(resp keyval.BytesWatchResp) (configKey string) {
+	w.Lock()
+	defer w.Unlock()
+
+	node, configKey := model.ParseKey(resp.GetKey())
+	if node == "" {
+		return ""
+	}
+	if resp.GetChangeType() == datasync.Delete {
+		if _, hasStatus := w.statuses[configKey][node]; hasStatus {
+			delete(w.statuses[configKey], node)
+			if len(w.statuses[configKey]) == 0 {
+				delete(w.statuses, configKey)
+			}
+			w.nodes[node]--
+			if w.nodes[node] == 0 {
+				delete(w.nodes, node)
+			}
+		}
+		return configKey
+	}
+	w.putStatus(resp.GetKey(), resp.GetValue())
+	return configKey
+}
+
+// putStatus adds or updates realization status.
+// The method assumes that RealizationWatcher is in the locked state.
+func (w *RealizationWatcher) putStatus(key string, value []byte) {
+	node, configKey := model.ParseKey(key)
+	if node == "" {
+		return
+	}
+	status := &model.RealizationStatus{}
+	if err := w.serializer.Unmarshal(value, status); err != nil {
+		w.Log.Warnf("Failed to de-serialize realization status for key=%s: %v", key, err)
+		return
+	}
+	if _, hasKey := w.statuses[configKey]; !hasKey {
+		w.statuses[configKey] = make(map[string]*model.RealizationStatus)
+	}
+	if _, hasStatus := w.statuses[configKey][node]; !hasStatus {
+		w.nodes[node]++
+	}
+	w.statuses[configKey][node] = status
+}
+
+// notifyReflectors notifies the subscribed reflectors about changed realization
+// statuses (nil = all keys).
+func (w *RealizationWatcher) notifyReflectors(configKeys map[string]struct{}) {
+	w.Lock()
+	reflectors := w.reflectors
+	w.Unlock()
+	for _, reflector := range reflectors {
+		reflector.realizationChanged(configKeys)
+	}
+}
+
+// UpdateCrdStatus updates the Status information of a CRD instance based on the result
+// of the reflection into KVDB (opRetval) and the realization statuses of the nodes.
+// Every node is represented by one cause in the status details, where the Field
+// is the node name and Type is one of: Success, Pending, Error.
+func UpdateCrdStatus(status *meta_v1.Status, opRetval error, realization []NodeRealization) {
+	status.Status = v1.StatusSuccess
+	status.Message = ""
+	status.Details = nil
+
+	var failed, pending []string
+	if len(realization) > 0 {
+		status.Details = &meta_v1.StatusDetails{}
+	}
+	for _, nodeRealization := range realization {
+		cause := meta_v1.StatusCause{Field: nodeRealization.Node}
+		switch nodeRealization.State {
+		case model.RealizationStatus_SUCCESS:
+			cause.Type = "Success"
+			cause.Message = fmt.Sprintf("applied at %s",
+				time.Unix(nodeRealization.Timestamp, 0).UTC().Format(time.RFC3339))
+		case model.RealizationStatus_PENDING:
+			cause.Type = "Pending"
+			cause.Message = "waiting for the node to apply the configuration"
+			pending = append(pending, nodeRealization.Node)
+		case model.RealizationStatus_ERROR:
+			cause.Type = "Error"
+			cause.Message = nodeRealization.Error
+			failed = append(failed, nodeRealization.Node)
+		}
+		status.Details.Causes = append(status.Details.Causes, cause)
+	}
+
+	switch {
+	case opRetval != nil:
+		status.Status = v1.StatusFailure
+		status.Message = opRetval.Error()
+	case len(failed) > 0:
+		status.Status = v1.StatusFailure
+		status.Message = "failed to apply on node(s): " + strings.Join(failed, ", ")
+	case len(pending) > 0:
+		status.Message = "pending on node(s): " + strings.Join(pending, ", ")
+	}
+}
@@ -0,0 +1,178 @@
+/*
+ * // Copyright (c) 2019 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+package kvdbreflector
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.ligato.io/cn-infra/v2/datasync"
+	"go.ligato.io/cn-infra/v2/db/keyval"
+	"go.ligato.io/cn-infra/v2/logging/logrus"
+	"go.ligato.io/cn-infra/v2/servicelabel"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/contiv/vpp/plugins/crd/pkg/apis/contivppio/v1"
+	"github.com/contiv/vpp/plugins/crdstatus/model"
+	"github.com/contiv/vpp/plugins/ksr"
+)
+
+var (
+	ksrPrefix   = servicelabel.GetDifferentAgentPrefix(ksr.MicroserviceLabel)
+	net1Key     = ksrPrefix + "k8s/custom-network/net1"
+	net2Key     = ksrPrefix + "k8s/custom-network/net2"
+	node2IfKey  = servicelabel.GetDifferentAgentPrefix("node2") + "config/vpp/v2/interfaces/loop1"
+	jsonEncoder = &keyval.SerializerJSON{}
+)
+
+// watchResp is a change of a realization status received from KVDB.
+type watchResp struct {
+	key      string
+	value    []byte
+	changeTp datasync.Op
+}
+
+func (r *watchResp) GetKey() string             { return r.key }
+func (r *watchResp) GetValue() []byte           { return r.value }
+func (r *watchResp) GetPrevValue() []byte       { return nil }
+func (r *watchResp) GetChangeType() datasync.Op { return r.changeTp }
+func (r *watchResp) GetRevision() (rev int64)   { return 0 }
+
+func newTestWatcher() *RealizationWatcher {
+	return &RealizationWatcher{
+		Log:        logrus.DefaultLogger(),
+		serializer: jsonEncoder,
+		statuses:   make(map[string]map[string]*model.RealizationStatus),
+		nodes:      make(map[string]int),
+	}
+}
+
+// putStatus simulates status published by a node.
+func putStatus(w *RealizationWatcher, node, configKey string, state model.RealizationStatus_State,
+	errStr string, timestamp int64) string {
+
+	value, err := jsonEncoder.Marshal(&model.RealizationStatus{
+		Node:      node,
+		Key:       configKey,
+		State:     state,
+		Error:     errStr,
+		Timestamp: timestamp,
+	})
+	Expect(err).ToNot(HaveOccurred())
+	return w.processChange(&watchResp{key: model.Key(node, configKey), value: value, changeTp: datasync.Put})
+}
+
+// deleteStatus simulates status removed by a node.
+func deleteStatus(w *RealizationWatcher, node, configKey string) string {
+	return w.processChange(&watchResp{key: model.Key(node, configKey), changeTp: datasync.Delete})
+}
+
+func TestSummarizeRealization(t *testing.T) {
+	RegisterTestingT(t)
+	w := newTestWatcher()
+
+	// no nodes have published anything yet
+	Expect(w.Summarize([]string{net1Key})).To(BeEmpty())
+
+	Expect(putStatus(w, "node1", net1Key, model.RealizationStatus_SUCCESS, "", 100)).To(Equal(net1Key))
+	Expect(putStatus(w, "node2", net1Key, model.RealizationStatus_ERROR, "VRF exists", 200)).To(Equal(net1Key))
+	Expect(putStatus(w, "node3", net2Key, model.RealizationStatus_SUCCESS, "", 300)).To(Equal(net2Key))
+
+	// invalid keys are ignored
+	Expect(w.processChange(&watchResp{key: model.KeyPrefix + "node4", changeTp: datasync.Put})).To(BeEmpty())
+	Expect(w.processChange(&watchResp{key: "/other/prefix/node4/key", changeTp: datasync.Put})).To(BeEmpty())
+
+	// node3 has not applied net1 yet
+	Expect(w.Summarize([]string{net1Key})).To(Equal([]NodeRealization{
+		{Node: "node1", State: model.RealizationStatus_SUCCESS, Timestamp: 100},
+		{Node: "node2", State: model.RealizationStatus_ERROR, Error: "VRF exists", Timestamp: 200},
+		{Node: "node3", State: model.RealizationStatus_PENDING},
+	}))
+
+	// multiple keys of the same CRD instance are aggregated per node:
+	//  - error wins over pending, pending wins over success
+	//  - the latest timestamp is reported
+	putStatus(w, "node1", net2Key, model.RealizationStatus_SUCCESS, "", 150)
+	putStatus(w, "node2", net2Key, model.RealizationStatus_PENDING, "", 250)
+	Expect(w.Summarize([]string{net1Key, net2Key})).To(Equal([]NodeRealization{
+		{Node: "node1", State: model.RealizationStatus_SUCCESS, Timestamp: 150},
+		{Node: "node2", State: model.RealizationStatus_ERROR, Error: "VRF exists", Timestamp: 250},
+		{Node: "node3", State: model.RealizationStatus_PENDING, Timestamp: 300},
+	}))
+
+	// external configuration of a node is expected only from that node
+	putStatus(w, "node2", node2IfKey, model.RealizationStatus_SUCCESS, "", 400)
+	Expect(w.Summarize([]string{node2IfKey})).To(Equal([]NodeRealization{
+		{Node: "node2", State: model.RealizationStatus_SUCCESS, Timestamp: 400},
+	}))
+
+	// status updated by the node
+	putStatus(w, "node2", net1Key, model.RealizationStatus_SUCCESS, "", 500)
+	Expect(w.Summarize([]string{net1Key})[1]).To(Equal(
+		NodeRealization{Node: "node2", State: model.RealizationStatus_SUCCESS, Timestamp: 500}))
+
+	// node3 removes its only status - the node is no longer expected to apply anything
+	Expect(deleteStatus(w, "node3", net2Key)).To(Equal(net2Key))
+	Expect(w.nodes).ToNot(HaveKey("node3"))
+	Expect(w.statuses[net2Key]).ToNot(HaveKey("node3"))
+	Expect(w.Summarize([]string{net1Key})).To(HaveLen(2))
+
+	// removing unknown status is a no-op
+	Expect(deleteStatus(w, "node3", net2Key)).To(Equal(net2Key))
+	Expect(w.nodes).To(HaveLen(2))
+}
+
+func TestUpdateCrdStatus(t *testing.T) {
+	RegisterTestingT(t)
+
+	realization := []NodeRealization{
+		{Node: "node1", State: model.RealizationStatus_SUCCESS, Timestamp: 1571392800},
+		{Node: "node2", State: model.RealizationStatus_PENDING},
+	}
+
+	// pending on some nodes
+	status := &meta_v1.Status{}
+	UpdateCrdStatus(status, nil, realization)
+	Expect(status.Status).To(Equal(v1.StatusSuccess))
+	Expect(status.Message).To(Equal("pending on node(s): node2"))
+	Expect(status.Details.Causes).To(Equal([]meta_v1.StatusCause{
+		{Type: "Success", Field: "node1", Message: "applied at 2019-10-18T10:00:00Z"},
+		{Type: "Pending", Field: "node2", Message: "waiting for the node to apply the configuration"},
+	}))
+
+	// failed on some nodes
+	realization = append(realization,
+		NodeRealization{Node: "node3", State: model.RealizationStatus_ERROR, Error: "VRF exists"})
+	UpdateCrdStatus(status, nil, realization)
+	Expect(status.Status).To(Equal(v1.StatusFailure))
+	Expect(status.Message).To(Equal("failed to apply on node(s): node3"))
+	Expect(status.Details.Causes).To(HaveLen(3))
+	Expect(status.Details.Causes[2]).To(Equal(
+		meta_v1.StatusCause{Type: "Error", Field: "node3", Message: "VRF exists"}))
+
+	// failed to reflect into KVDB
+	UpdateCrdStatus(status, errors.New("etcd unavailable"), realization)
+	Expect(status.Status).To(Equal(v1.StatusFailure))
+	Expect(status.Message).To(Equal("etcd unavailable"))
+
+	// no nodes known - previous details are cleared
+	UpdateCrdStatus(status, nil, nil)
+	Expect(status.Status).To(Equal(v1.StatusSuccess))
+	Expect(status.Message).To(BeEmpty())
+	Expect(status.Details).To(BeNil())
+}
@@ -20,6 +20,8 @@ package servicefunctionchain
 
 import (
 	"errors"
+	"reflect"
+
 	"github.com/contiv/vpp/plugins/crd/handler/kvdbreflector"
 	"github.com/contiv/vpp/plugins/crd/handler/servicefunctionchain/model"
 	"github.com/contiv/vpp/plugins/crd/pkg/apis/contivppio/v1"
@@ -73,16 +75,21 @@ func (h *Handler) IsExclusiveKVDB() bool {
 
 // PublishCrdStatus updates the resource Status information.
 func (h *Handler) PublishCrdStatus(obj interface{}, opRetval error) error {
+	return h.PublishCrdRealization(obj, opRetval, nil)
+}
+
+// PublishCrdRealization updates the resource Status information, including
+// the realization status of the service function chain on every node.
+func (h *Handler) PublishCrdRealization(obj interface{}, opRetval error, realization []kvdbreflector.NodeRealization) error {
 	svc, ok := obj.(*v1.ServiceFunctionChain)
 	if !ok {
 		return errors.New("failed to cast into ServiceFunctionChain struct")
 	}
 	svc = svc.DeepCopy()
-	if opRetval == nil {
-		svc.Status.Status = v1.StatusSuccess
-	} else {
-		svc.Status.Status = v1.StatusFailure
-		svc.Status.Message = opRetval.Error()
+	prevStatus := svc.Status
+	kvdbreflector.UpdateCrdStatus(&svc.Status, opRetval, realization)
+	if reflect.DeepEqual(prevStatus, svc.Status) {
+		return nil
 	}
 	_, err := h.CrdClient.ContivppV1().ServiceFunctionChains(svc.Namespace).Update(svc)
 	return err
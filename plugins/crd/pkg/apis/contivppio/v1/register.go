@@ -47,8 +47,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		SchemeGroupVersion,
 		&CustomNetwork{},
 		&CustomNetworkList{},
+		&EgressGateway{},
+		&EgressGatewayList{},
 		&ExternalInterface{},
 		&ExternalInterfaceList{},
+		&HostPolicy{},
+		&HostPolicyList{},
 		&ServiceFunctionChain{},
 		&ServiceFunctionChainList{},
 		&CustomConfiguration{},
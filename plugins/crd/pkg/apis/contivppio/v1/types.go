@@ -45,6 +45,38 @@ type CustomNetworkSpec struct {
 	Type                   string `json:"type"`
 	SubnetCIDR             string `json:"subnetCIDR,omitempty"`
 	SubnetOneNodePrefixLen uint32 `json:"subnetOneNodePrefixLen"`
+	// VNI pins the VXLAN VNI of the network (allocated automatically if zero).
+	VNI uint32 `json:"vni,omitempty"`
+	// VRF pins the VRF ID of the L3 network (allocated automatically if zero),
+	// so that it can be stitched to a VRF of the external fabric.
+	VRF uint32 `json:"vrf,omitempty"`
+	// Gateway is the IP address used as the gateway for pods of the L3 network
+	// on every node (the first host address of the node subnet is used if empty).
+	Gateway string `json:"gateway,omitempty"`
+	// ReservedRanges are excluded from the IP address allocation, e.g. addresses
+	// assigned statically or managed by an external DHCP server.
+	ReservedRanges []IPRange `json:"reservedRanges,omitempty"`
+	// BGP enables interconnection of the L3 network with external routers via BGP.
+	BGP *CustomNetworkBGP `json:"bgp,omitempty"`
+}
+
+// CustomNetworkBGP defines interconnection of a L3 custom network with external routers
+// via BGP speaker running in the host network stack (e.g. BIRD or FRR). The routes are exchanged
+// with the speaker through a dedicated routing table of the host network stack.
+type CustomNetworkBGP struct {
+	// LinuxTable is the routing table of the host network stack used to exchange routes
+	// with the BGP speaker (VRF ID of the network is used if zero).
+	LinuxTable uint32 `json:"linuxTable,omitempty"`
+	// ExportPodSubnets enables export of per-node pod subnets of the network to the BGP speaker.
+	ExportPodSubnets bool `json:"exportPodSubnets,omitempty"`
+	// ImportRoutes enables import of the routes learned by the BGP speaker into the VRF of the network.
+	ImportRoutes bool `json:"importRoutes,omitempty"`
+}
+
+// IPRange is an inclusive range of IP addresses.
+type IPRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
 }
 
 // CustomNetworkList is a list of CustomNetwork resources
@@ -56,6 +88,45 @@ type CustomNetworkList struct {
 	Items []CustomNetwork `json:"items"`
 }
 
+// EgressGateway pins the cluster-outbound traffic of selected pods to dedicated
+// source IP addresses, which are S-NATed on a designated gateway node
+// (instead of the IP address of the node where the pod is deployed).
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type EgressGateway struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	meta_v1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object
+	meta_v1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the custom resource spec
+	Spec EgressGatewaySpec `json:"spec"`
+	// Status informs about the status of the resource.
+	Status meta_v1.Status `json:"status,omitempty"`
+}
+
+// EgressGatewaySpec is the spec for egress gateway configuration resource
+type EgressGatewaySpec struct {
+	// Namespaces of the selected pods (empty = all namespaces).
+	Namespaces []string `json:"namespaces,omitempty"`
+	// PodSelector selects pods by labels (empty = all pods from the namespaces).
+	PodSelector map[string]string `json:"podSelector,omitempty"`
+	// Node is the name of the node which S-NATs the traffic of the selected pods.
+	Node string `json:"node"`
+	// EgressIPs is a pool of source IP addresses (IPv4 addresses or subnets)
+	// assigned to the selected pods.
+	EgressIPs []string `json:"egressIPs"`
+}
+
+// EgressGatewayList is a list of EgressGateway resources
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type EgressGatewayList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata"`
+
+	Items []EgressGateway `json:"items"`
+}
+
 // ExternalInterface is used to store definition of an external interface defined via CRD.
 // It is a logical entity that may mean different physical interfaces on different nodes.
 // +genclient
@@ -96,6 +167,61 @@ type ExternalInterfaceList struct {
 	Items []ExternalInterface `json:"items"`
 }
 
+// HostPolicy restricts the traffic towards the network endpoints of the selected
+// nodes (NodePorts, kubelet, etcd, ...) to the traffic allowed by the policy rules.
+// A node selected by at least one HostPolicy is isolated, i.e. only the traffic
+// allowed by any of the selecting policies (or by the always-allowed vswitch
+// control traffic) may reach the node.
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type HostPolicy struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	meta_v1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object
+	meta_v1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the custom resource spec
+	Spec HostPolicySpec `json:"spec"`
+	// Status informs about the status of the resource.
+	Status meta_v1.Status `json:"status,omitempty"`
+}
+
+// HostPolicySpec is the spec for host policy resource
+type HostPolicySpec struct {
+	// NodeSelector selects nodes by labels (empty = all nodes).
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Ingress is a list of rules allowing traffic towards the selected nodes
+	// (empty = only the vswitch control traffic is allowed).
+	Ingress []HostPolicyRule `json:"ingress,omitempty"`
+}
+
+// HostPolicyRule allows traffic from the given sources to the given ports.
+type HostPolicyRule struct {
+	// From is a list of source subnets (CIDRs) or IP addresses (empty = any source).
+	From []string `json:"from,omitempty"`
+	// Ports is a list of destination ports (empty = all ports).
+	Ports []HostPolicyPort `json:"ports,omitempty"`
+}
+
+// HostPolicyPort is a destination port or a range of ports.
+type HostPolicyPort struct {
+	// Protocol is one of TCP (default), UDP or SCTP.
+	Protocol string `json:"protocol,omitempty"`
+	// Port is the destination port number (0 = all ports).
+	Port uint16 `json:"port,omitempty"`
+	// EndPort, if set, makes the rule match port range <Port, EndPort>.
+	EndPort uint16 `json:"endPort,omitempty"`
+}
+
+// HostPolicyList is a list of HostPolicy resources
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type HostPolicyList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata"`
+
+	Items []HostPolicy `json:"items"`
+}
+
 // ServiceFunctionChain define service function chain crd for contiv/vpp
 // +genclient
 // +genclient:noStatus
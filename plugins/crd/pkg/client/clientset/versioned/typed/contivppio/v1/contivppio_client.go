@@ -26,7 +26,9 @@ type ContivppV1Interface interface {
 	RESTClient() rest.Interface
 	CustomConfigurationsGetter
 	CustomNetworksGetter
+	EgressGatewaysGetter
 	ExternalInterfacesGetter
+	HostPoliciesGetter
 	ServiceFunctionChainsGetter
 }
 
@@ -43,10 +45,18 @@ func (c *ContivppV1Client) CustomNetworks(namespace string) CustomNetworkInterfa
 	return newCustomNetworks(c, namespace)
 }
 
+func (c *ContivppV1Client) EgressGateways(namespace string) EgressGatewayInterface {
+	return newEgressGateways(c, namespace)
+}
+
 func (c *ContivppV1Client) ExternalInterfaces(namespace string) ExternalInterfaceInterface {
 	return newExternalInterfaces(c, namespace)
 }
 
+func (c *ContivppV1Client) HostPolicies(namespace string) HostPolicyInterface {
+	return newHostPolicies(c, namespace)
+}
+
 func (c *ContivppV1Client) ServiceFunctionChains(namespace string) ServiceFunctionChainInterface {
 	return newServiceFunctionChains(c, namespace)
 }
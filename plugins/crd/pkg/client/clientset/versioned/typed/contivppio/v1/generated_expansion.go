@@ -20,6 +20,10 @@ type CustomConfigurationExpansion interface{}
 
 type CustomNetworkExpansion interface{}
 
+type EgressGatewayExpansion interface{}
+
 type ExternalInterfaceExpansion interface{}
 
+type HostPolicyExpansion interface{}
+
 type ServiceFunctionChainExpansion interface{}
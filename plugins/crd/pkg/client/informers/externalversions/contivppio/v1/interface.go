@@ -26,8 +26,12 @@ type Interface interface {
 	CustomConfigurations() CustomConfigurationInformer
 	// CustomNetworks returns a CustomNetworkInformer.
 	CustomNetworks() CustomNetworkInformer
+	// EgressGateways returns a EgressGatewayInformer.
+	EgressGateways() EgressGatewayInformer
 	// ExternalInterfaces returns a ExternalInterfaceInformer.
 	ExternalInterfaces() ExternalInterfaceInformer
+	// HostPolicies returns a HostPolicyInformer.
+	HostPolicies() HostPolicyInformer
 	// ServiceFunctionChains returns a ServiceFunctionChainInformer.
 	ServiceFunctionChains() ServiceFunctionChainInformer
 }
@@ -53,11 +57,21 @@ func (v *version) CustomNetworks() CustomNetworkInformer {
 	return &customNetworkInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// EgressGateways returns a EgressGatewayInformer.
+func (v *version) EgressGateways() EgressGatewayInformer {
+	return &egressGatewayInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // ExternalInterfaces returns a ExternalInterfaceInformer.
 func (v *version) ExternalInterfaces() ExternalInterfaceInformer {
 	return &externalInterfaceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// HostPolicies returns a HostPolicyInformer.
+func (v *version) HostPolicies() HostPolicyInformer {
+	return &hostPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // ServiceFunctionChains returns a ServiceFunctionChainInformer.
 func (v *version) ServiceFunctionChains() ServiceFunctionChainInformer {
 	return &serviceFunctionChainInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -57,8 +57,12 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Contivpp().V1().CustomConfigurations().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("customnetworks"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Contivpp().V1().CustomNetworks().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("egressgateways"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Contivpp().V1().EgressGateways().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("externalinterfaces"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Contivpp().V1().ExternalInterfaces().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("hostpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Contivpp().V1().HostPolicies().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("servicefunctionchains"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Contivpp().V1().ServiceFunctionChains().Informer()}, nil
 
@@ -32,6 +32,14 @@ type CustomNetworkListerExpansion interface{}
 // CustomNetworkNamespaceLister.
 type CustomNetworkNamespaceListerExpansion interface{}
 
+// EgressGatewayListerExpansion allows custom methods to be added to
+// EgressGatewayLister.
+type EgressGatewayListerExpansion interface{}
+
+// EgressGatewayNamespaceListerExpansion allows custom methods to be added to
+// EgressGatewayNamespaceLister.
+type EgressGatewayNamespaceListerExpansion interface{}
+
 // ExternalInterfaceListerExpansion allows custom methods to be added to
 // ExternalInterfaceLister.
 type ExternalInterfaceListerExpansion interface{}
@@ -40,6 +48,14 @@ type ExternalInterfaceListerExpansion interface{}
 // ExternalInterfaceNamespaceLister.
 type ExternalInterfaceNamespaceListerExpansion interface{}
 
+// HostPolicyListerExpansion allows custom methods to be added to
+// HostPolicyLister.
+type HostPolicyListerExpansion interface{}
+
+// HostPolicyNamespaceListerExpansion allows custom methods to be added to
+// HostPolicyNamespaceLister.
+type HostPolicyNamespaceListerExpansion interface{}
+
 // ServiceFunctionChainListerExpansion allows custom methods to be added to
 // ServiceFunctionChainLister.
 type ServiceFunctionChainListerExpansion interface{}
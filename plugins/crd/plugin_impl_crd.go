@@ -49,12 +49,15 @@ import (
 	"github.com/contiv/vpp/plugins/crd/controller"
 	"github.com/contiv/vpp/plugins/crd/handler/customconfiguration"
 	"github.com/contiv/vpp/plugins/crd/handler/customnetwork"
+	"github.com/contiv/vpp/plugins/crd/handler/egressgateway"
 	"github.com/contiv/vpp/plugins/crd/handler/externalinterface"
+	"github.com/contiv/vpp/plugins/crd/handler/hostpolicy"
 	"github.com/contiv/vpp/plugins/crd/handler/kvdbreflector"
 	"github.com/contiv/vpp/plugins/crd/handler/nodeconfig"
 	"github.com/contiv/vpp/plugins/crd/handler/servicefunctionchain"
 	"github.com/contiv/vpp/plugins/crd/handler/telemetry"
 	"github.com/contiv/vpp/plugins/crd/validator"
+	"github.com/contiv/vpp/plugins/crd/webhook"
 
 	"github.com/contiv/vpp/plugins/crd/pkg/apis/contivppio"
 	v1 "github.com/contiv/vpp/plugins/crd/pkg/apis/contivppio/v1"
@@ -67,6 +70,9 @@ import (
 
 const (
 	k8sResyncInterval = 10 * time.Minute
+
+	// default port of the validating admission webhook
+	defaultWebhookPort = 9443
 )
 
 // Plugin implements NodeConfig and TelemetryReport CRDs.
@@ -93,7 +99,11 @@ type Plugin struct {
 	customNetworkController        *controller.CrdController
 	externalInterfaceController    *controller.CrdController
 	serviceFunctionChainController *controller.CrdController
+	egressGatewayController        *controller.CrdController
+	hostPolicyController           *controller.CrdController
 	customConfigController         *controller.CrdController
+	realizationWatcher             *kvdbreflector.RealizationWatcher
+	webhook                        *webhook.Webhook
 	cache                          *cache.ContivTelemetryCache
 	processor                      api.ContivTelemetryProcessor
 	verbose                        bool
@@ -168,6 +178,8 @@ func (p *Plugin) Init() error {
 		return err
 	}
 
+	p.initializeWebhook()
+
 	// init and start CRD controllers only after connection with etcd has been established
 	p.Etcd.OnConnect(p.onEtcdConnect)
 	return nil
@@ -177,6 +189,11 @@ func (p *Plugin) Init() error {
 // by initializeTelemetry()).
 // Must be run *after* (first) connection with etcd has been established.
 func (p *Plugin) initializeCRDs() error {
+	p.realizationWatcher = &kvdbreflector.RealizationWatcher{
+		Log:     p.Log.NewLogger("realizationWatcher"),
+		Publish: p.Etcd.RawAccess(),
+	}
+
 	nodeConfigInformer := p.sharedFactory.Nodeconfig().V1().NodeConfigs().Informer()
 	p.nodeConfigController = &controller.CrdController{
 		Deps: controller.Deps{
@@ -218,6 +235,7 @@ func (p *Plugin) initializeCRDs() error {
 					Handler: &customnetwork.Handler{
 						CrdClient: p.crdClient,
 					},
+					Realization: p.realizationWatcher,
 				},
 			},
 		},
@@ -245,6 +263,7 @@ func (p *Plugin) initializeCRDs() error {
 					Handler: &externalinterface.Handler{
 						CrdClient: p.crdClient,
 					},
+					Realization: p.realizationWatcher,
 				},
 			},
 		},
@@ -257,6 +276,60 @@ func (p *Plugin) initializeCRDs() error {
 		},
 	}
 
+	egressGatewayInformer := p.sharedFactory.Contivpp().V1().EgressGateways().Informer()
+	p.egressGatewayController = &controller.CrdController{
+		Deps: controller.Deps{
+			Log:       p.Log.NewLogger("egressGatewayController"),
+			APIClient: p.apiclientset,
+			Informer:  egressGatewayInformer,
+			EventHandler: &kvdbreflector.KvdbReflector{
+				Deps: kvdbreflector.Deps{
+					Log:          p.Log.NewLogger("egressGatewayHandler"),
+					ServiceLabel: p.ServiceLabel,
+					Publish:      p.Etcd.RawAccess(),
+					Informer:     egressGatewayInformer,
+					Handler: &egressgateway.Handler{
+						CrdClient: p.crdClient,
+					},
+				},
+			},
+		},
+		Spec: controller.CrdSpec{
+			TypeName:   reflect.TypeOf(v1.EgressGateway{}).Name(),
+			Group:      contivppio.GroupName,
+			Version:    "v1",
+			Plural:     "egressgateways",
+			Validation: egressgateway.Validation(),
+		},
+	}
+
+	hostPolicyInformer := p.sharedFactory.Contivpp().V1().HostPolicies().Informer()
+	p.hostPolicyController = &controller.CrdController{
+		Deps: controller.Deps{
+			Log:       p.Log.NewLogger("hostPolicyController"),
+			APIClient: p.apiclientset,
+			Informer:  hostPolicyInformer,
+			EventHandler: &kvdbreflector.KvdbReflector{
+				Deps: kvdbreflector.Deps{
+					Log:          p.Log.NewLogger("hostPolicyHandler"),
+					ServiceLabel: p.ServiceLabel,
+					Publish:      p.Etcd.RawAccess(),
+					Informer:     hostPolicyInformer,
+					Handler: &hostpolicy.Handler{
+						CrdClient: p.crdClient,
+					},
+				},
+			},
+		},
+		Spec: controller.CrdSpec{
+			TypeName:   reflect.TypeOf(v1.HostPolicy{}).Name(),
+			Group:      contivppio.GroupName,
+			Version:    "v1",
+			Plural:     "hostpolicies",
+			Validation: hostpolicy.Validation(),
+		},
+	}
+
 	serviceFunctionChainInformer := p.sharedFactory.Contivpp().V1().ServiceFunctionChains().Informer()
 	p.serviceFunctionChainController = &controller.CrdController{
 		Deps: controller.Deps{
@@ -272,6 +345,7 @@ func (p *Plugin) initializeCRDs() error {
 					Handler: &servicefunctionchain.Handler{
 						CrdClient: p.crdClient,
 					},
+					Realization: p.realizationWatcher,
 				},
 			},
 		},
@@ -301,6 +375,7 @@ func (p *Plugin) initializeCRDs() error {
 						Log:       customConfigLog,
 						CrdClient: p.crdClient,
 					},
+					Realization: p.realizationWatcher,
 				},
 			},
 		},
@@ -317,6 +392,8 @@ func (p *Plugin) initializeCRDs() error {
 	p.customNetworkController.Init()
 	p.externalInterfaceController.Init()
 	p.serviceFunctionChainController.Init()
+	p.egressGatewayController.Init()
+	p.hostPolicyController.Init()
 	p.customConfigController.Init()
 
 	if p.verbose {
@@ -324,6 +401,8 @@ func (p *Plugin) initializeCRDs() error {
 		p.nodeConfigController.Log.SetLevel(logging.DebugLevel)
 		p.externalInterfaceController.Log.SetLevel(logging.DebugLevel)
 		p.serviceFunctionChainController.Log.SetLevel(logging.DebugLevel)
+		p.egressGatewayController.Log.SetLevel(logging.DebugLevel)
+		p.hostPolicyController.Log.SetLevel(logging.DebugLevel)
 		p.customConfigController.Log.SetLevel(logging.DebugLevel)
 		customConfigLog.SetLevel(logging.DebugLevel)
 	}
@@ -417,6 +496,35 @@ func (p *Plugin) initializeTelemetry() error {
 	return p.telemetryController.Init()
 }
 
+// initializeWebhook prepares the validating admission webhook for contivpp.io CRDs.
+// The webhook is enabled only if the certificate and the key for TLS are configured.
+func (p *Plugin) initializeWebhook() {
+	certFile := os.Getenv("CONTIV_CRD_WEBHOOK_CERT")
+	keyFile := os.Getenv("CONTIV_CRD_WEBHOOK_KEY")
+	if certFile == "" || keyFile == "" {
+		p.Log.Info("Validating admission webhook is disabled")
+		return
+	}
+	port := defaultWebhookPort
+	configuredPort, err := strconv.Atoi(os.Getenv("CONTIV_CRD_WEBHOOK_PORT"))
+	if err == nil {
+		port = configuredPort
+	}
+
+	p.webhook = &webhook.Webhook{
+		Deps: webhook.Deps{
+			Log:      p.Log.NewLogger("webhook"),
+			DB:       p.Etcd,
+			Address:  fmt.Sprintf(":%d", port),
+			CertFile: certFile,
+			KeyFile:  keyFile,
+		},
+	}
+	if p.verbose {
+		p.webhook.Log.SetLevel(logging.DebugLevel)
+	}
+}
+
 // AfterInit registers to the ResyncOrchestrator.
 func (p *Plugin) AfterInit() error {
 	if p.Resync != nil {
@@ -434,6 +542,10 @@ func (p *Plugin) onEtcdConnect() error {
 	if err != nil {
 		return err
 	}
+	if p.webhook != nil {
+		// the webhook reads only from KVDB, therefore it can be served by every instance
+		go p.webhook.Run(p.ctx.Done())
+	}
 	go func() {
 		p.Log.Info("Start campaign in crd leader election")
 		_, err := p.Etcd.CampaignInElection(p.ctx, electionPrefix)
@@ -447,7 +559,10 @@ func (p *Plugin) onEtcdConnect() error {
 		go p.customNetworkController.Run(p.ctx.Done())
 		go p.externalInterfaceController.Run(p.ctx.Done())
 		go p.serviceFunctionChainController.Run(p.ctx.Done())
+		go p.egressGatewayController.Run(p.ctx.Done())
+		go p.hostPolicyController.Run(p.ctx.Done())
 		go p.customConfigController.Run(p.ctx.Done())
+		go p.realizationWatcher.Run(p.ctx.Done())
 	}()
 	return nil
 }
@@ -0,0 +1,326 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+
+	customnetmodel "github.com/contiv/vpp/plugins/crd/handler/customnetwork/model"
+	extifmodel "github.com/contiv/vpp/plugins/crd/handler/externalinterface/model"
+	sfcmodel "github.com/contiv/vpp/plugins/crd/handler/servicefunctionchain/model"
+	"github.com/contiv/vpp/plugins/ipnet/customif"
+	nodemodel "github.com/contiv/vpp/plugins/ksr/model/node"
+	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
+)
+
+// minReservedLinuxTable is the lowest of the Linux routing tables reserved by the kernel
+// (compat, default, main, local).
+const minReservedLinuxTable = 252
+
+// validateCustomNetwork checks that the subnet of a L3 custom network is valid
+// and that it does not overlap with subnets of other custom networks.
+// The gateway and reserved ranges have to be from the subnet and pinned VNI / VRF
+// and Linux routing table used for BGP must not be used by other custom networks.
+func (wh *Webhook) validateCustomNetwork(customNet *customnetmodel.CustomNetwork) (violations []string, err error) {
+	otherNets, err := wh.listCustomNetworks()
+	if err != nil {
+		return nil, err
+	}
+	for _, otherNet := range otherNets {
+		if otherNet.Name == customNet.Name {
+			continue
+		}
+		if customNet.Vni != 0 && otherNet.Vni == customNet.Vni {
+			violations = append(violations, fmt.Sprintf("vni %d is already used by the custom network %s",
+				customNet.Vni, otherNet.Name))
+		}
+		if customNet.Vrf != 0 && otherNet.Vrf == customNet.Vrf {
+			violations = append(violations, fmt.Sprintf("vrf %d is already used by the custom network %s",
+				customNet.Vrf, otherNet.Name))
+		}
+		if customNet.Bgp != nil && customNet.Bgp.LinuxTable != 0 && otherNet.Bgp != nil &&
+			otherNet.Bgp.LinuxTable == customNet.Bgp.LinuxTable {
+			violations = append(violations, fmt.Sprintf("bgp linuxTable %d is already used by the custom network %s",
+				customNet.Bgp.LinuxTable, otherNet.Name))
+		}
+	}
+	if customNet.Bgp != nil && customNet.Bgp.LinuxTable >= minReservedLinuxTable {
+		violations = append(violations, fmt.Sprintf("bgp linuxTable %d is reserved", customNet.Bgp.LinuxTable))
+	}
+
+	if customNet.Type != customnetmodel.CustomNetwork_L3 || customNet.SubnetCIDR == "" {
+		if customNet.Vrf != 0 || customNet.Gateway != "" || len(customNet.ReservedRanges) > 0 || customNet.Bgp != nil {
+			violations = append(violations, "vrf, gateway, reservedRanges and bgp can be defined only for L3 networks with subnetCIDR")
+		}
+		return violations, nil
+	}
+	_, subnet, err := net.ParseCIDR(customNet.SubnetCIDR)
+	if err != nil {
+		return append(violations, fmt.Sprintf("invalid subnetCIDR %s: %v", customNet.SubnetCIDR, err)), nil
+	}
+	prefixLen, bits := subnet.Mask.Size()
+	if customNet.SubnetOneNodePrefix <= uint32(prefixLen) || customNet.SubnetOneNodePrefix > uint32(bits) {
+		violations = append(violations, fmt.Sprintf(
+			"subnetOneNodePrefixLen (%d) must be higher than the prefix length of subnetCIDR (%d) and at most %d",
+			customNet.SubnetOneNodePrefix, prefixLen, bits))
+	}
+	if customNet.Gateway != "" {
+		if gw := net.ParseIP(customNet.Gateway); gw == nil || !subnet.Contains(gw) {
+			violations = append(violations, fmt.Sprintf("gateway %s is not an IP address from subnetCIDR %s",
+				customNet.Gateway, customNet.SubnetCIDR))
+		}
+	}
+	for _, ipRange := range customNet.ReservedRanges {
+		start, end := net.ParseIP(ipRange.Start), net.ParseIP(ipRange.End)
+		if start == nil || end == nil || !subnet.Contains(start) || !subnet.Contains(end) ||
+			bytes.Compare(start.To16(), end.To16()) > 0 {
+			violations = append(violations, fmt.Sprintf("reserved range %s-%s is not a valid range from subnetCIDR %s",
+				ipRange.Start, ipRange.End, customNet.SubnetCIDR))
+		}
+	}
+
+	for _, otherNet := range otherNets {
+		if otherNet.Name == customNet.Name || otherNet.SubnetCIDR == "" {
+			continue
+		}
+		_, otherSubnet, err := net.ParseCIDR(otherNet.SubnetCIDR)
+		if err != nil {
+			continue
+		}
+		if subnet.Contains(otherSubnet.IP) || otherSubnet.Contains(subnet.IP) {
+			violations = append(violations, fmt.Sprintf("subnetCIDR %s overlaps with subnet %s of the custom network %s",
+				customNet.SubnetCIDR, otherNet.SubnetCIDR, otherNet.Name))
+		}
+	}
+	return violations, nil
+}
+
+// validateExternalInterface checks that the external interface references
+// existing network and nodes and that the IP addresses can be parsed.
+func (wh *Webhook) validateExternalInterface(extIf *extifmodel.ExternalInterface) (violations []string, err error) {
+	if extIf.Network != "" && extIf.Network != customif.DefaultNetwork && extIf.Network != customif.StubNetwork {
+		customNets, err := wh.listCustomNetworks()
+		if err != nil {
+			return nil, err
+		}
+		if _, known := customNets[extIf.Network]; !known {
+			violations = append(violations, fmt.Sprintf("unknown network %s", extIf.Network))
+		}
+	}
+
+	nodes, err := wh.listNodes()
+	if err != nil {
+		return nil, err
+	}
+	for _, nodeIf := range extIf.Nodes {
+		if _, known := nodes[nodeIf.Node]; !known {
+			violations = append(violations, fmt.Sprintf("unknown node %s", nodeIf.Node))
+		}
+		if nodeIf.Ip != "" {
+			if _, _, err := net.ParseCIDR(nodeIf.Ip); err != nil {
+				violations = append(violations, fmt.Sprintf("invalid IP %s of the interface on node %s: %v",
+					nodeIf.Ip, nodeIf.Node, err))
+			}
+		}
+	}
+	return violations, nil
+}
+
+// validateServiceFunctionChain checks that every pod service function selects
+// at least one pod and that the selected pods declare the referenced interfaces
+// in the custom-if annotation. For external interface service functions
+// it checks that the referenced external interface exists.
+func (wh *Webhook) validateServiceFunctionChain(sfc *sfcmodel.ServiceFunctionChain) (violations []string, err error) {
+	var (
+		pods   []*podmodel.Pod
+		extIfs map[string]*extifmodel.ExternalInterface
+	)
+	for _, sf := range sfc.Chain {
+		switch sf.Type {
+		case sfcmodel.ServiceFunctionChain_ServiceFunction_Pod:
+			if pods == nil {
+				if pods, err = wh.listPods(); err != nil {
+					return nil, err
+				}
+			}
+			violations = append(violations, validatePodServiceFunction(sf, pods)...)
+
+		case sfcmodel.ServiceFunctionChain_ServiceFunction_ExternalInterface:
+			if extIfs == nil {
+				if extIfs, err = wh.listExternalInterfaces(); err != nil {
+					return nil, err
+				}
+			}
+			// the same resolution of the interface name as in the SFC processor
+			extIfName := sf.Interface
+			if sf.Interface == "" && sf.InputInterface != "" {
+				extIfName = sf.InputInterface
+			}
+			if sf.Interface == "" && sf.OutputInterface != "" {
+				extIfName = sf.OutputInterface
+			}
+			if _, known := extIfs[extIfName]; !known {
+				violations = append(violations, fmt.Sprintf("service function %s: unknown external interface %s",
+					sf.Name, extIfName))
+			}
+		}
+	}
+	return violations, nil
+}
+
+// validatePodServiceFunction checks service function of the pod type against
+// the given list of pods.
+func validatePodServiceFunction(sf *sfcmodel.ServiceFunctionChain_ServiceFunction, pods []*podmodel.Pod) (violations []string) {
+	// if only "interface" is defined, it is used as both input and output interface
+	inputIf := sf.InputInterface
+	outputIf := sf.OutputInterface
+	if inputIf == "" {
+		inputIf = sf.Interface
+	}
+	if outputIf == "" {
+		outputIf = sf.Interface
+	}
+
+	var matched int
+	for _, pod := range pods {
+		if !podMatchesSelector(pod, sf.PodSelector) {
+			continue
+		}
+		matched++
+		declared := make(map[string]struct{})
+		for _, ifDefinition := range customif.FromAnnotations(pod.Annotations) {
+			iface, err := customif.Parse(ifDefinition)
+			if err != nil {
+				continue
+			}
+			declared[iface.Name] = struct{}{}
+		}
+		for _, ifName := range []string{inputIf, outputIf} {
+			if ifName == "" {
+				continue
+			}
+			if _, isDeclared := declared[ifName]; !isDeclared {
+				violations = append(violations, fmt.Sprintf(
+					"service function %s: interface %s is not declared in the %s annotation of the pod %s/%s",
+					sf.Name, ifName, customif.Annotation, pod.Namespace, pod.Name))
+			}
+			if inputIf == outputIf {
+				break
+			}
+		}
+	}
+	if matched == 0 {
+		violations = append(violations, fmt.Sprintf("service function %s: pod selector %s does not match any pod",
+			sf.Name, selectorToString(sf.PodSelector)))
+	}
+	return violations
+}
+
+// podMatchesSelector returns true if the pod matches provided label selector,
+// evaluated the same way as by the SFC processor.
+func podMatchesSelector(pod *podmodel.Pod, podSelector map[string]string) bool {
+	if len(pod.Labels) == 0 {
+		return false
+	}
+	for selKey, selVal := range podSelector {
+		if podVal, hasLabel := pod.Labels[selKey]; !hasLabel || podVal != selVal {
+			return false
+		}
+	}
+	return true
+}
+
+// selectorToString returns string representation of the label selector.
+func selectorToString(podSelector map[string]string) string {
+	var labels []string
+	for key, value := range podSelector {
+		labels = append(labels, key+"="+value)
+	}
+	sort.Strings(labels)
+	return "{" + strings.Join(labels, ", ") + "}"
+}
+
+// listCustomNetworks returns all custom networks reflected into KVDB.
+func (wh *Webhook) listCustomNetworks() (customNets map[string]*customnetmodel.CustomNetwork, err error) {
+	customNets = make(map[string]*customnetmodel.CustomNetwork)
+	err = wh.listKsrValues(customnetmodel.KeyPrefix(), func() proto.Message {
+		return &customnetmodel.CustomNetwork{}
+	}, func(msg proto.Message) {
+		customNet := msg.(*customnetmodel.CustomNetwork)
+		customNets[customNet.Name] = customNet
+	})
+	return customNets, err
+}
+
+// listExternalInterfaces returns all external interfaces reflected into KVDB.
+func (wh *Webhook) listExternalInterfaces() (extIfs map[string]*extifmodel.ExternalInterface, err error) {
+	extIfs = make(map[string]*extifmodel.ExternalInterface)
+	err = wh.listKsrValues(extifmodel.KeyPrefix(), func() proto.Message {
+		return &extifmodel.ExternalInterface{}
+	}, func(msg proto.Message) {
+		extIf := msg.(*extifmodel.ExternalInterface)
+		extIfs[extIf.Name] = extIf
+	})
+	return extIfs, err
+}
+
+// listNodes returns all K8s nodes reflected into KVDB by KSR.
+func (wh *Webhook) listNodes() (nodes map[string]*nodemodel.Node, err error) {
+	nodes = make(map[string]*nodemodel.Node)
+	err = wh.listKsrValues(nodemodel.KeyPrefix(), func() proto.Message {
+		return &nodemodel.Node{}
+	}, func(msg proto.Message) {
+		node := msg.(*nodemodel.Node)
+		nodes[node.Name] = node
+	})
+	return nodes, err
+}
+
+// listPods returns all K8s pods reflected into KVDB by KSR.
+func (wh *Webhook) listPods() (pods []*podmodel.Pod, err error) {
+	err = wh.listKsrValues(podmodel.KeyPrefix(), func() proto.Message {
+		return &podmodel.Pod{}
+	}, func(msg proto.Message) {
+		pods = append(pods, msg.(*podmodel.Pod))
+	})
+	return pods, err
+}
+
+// listKsrValues lists all values stored under the given prefix of the KSR key-space.
+func (wh *Webhook) listKsrValues(prefix string, newMsg func() proto.Message, cb func(msg proto.Message)) error {
+	it, err := wh.broker.ListValues(prefix)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %v", prefix, err)
+	}
+	for {
+		kv, stop := it.GetNext()
+		if stop {
+			break
+		}
+		msg := newMsg()
+		if err := kv.GetValue(msg); err != nil {
+			wh.Log.Warnf("Failed to de-serialize value under the key %s: %v", kv.GetKey(), err)
+			continue
+		}
+		cb(msg)
+	}
+	return nil
+}
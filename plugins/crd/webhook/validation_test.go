@@ -0,0 +1,74 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	sfcmodel "github.com/contiv/vpp/plugins/crd/handler/servicefunctionchain/model"
+	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
+)
+
+func TestValidatePodServiceFunction(t *testing.T) {
+	RegisterTestingT(t)
+
+	pods := []*podmodel.Pod{
+		{
+			Name:        "cnf1",
+			Namespace:   "default",
+			Labels:      map[string]string{"cnf": "cnf1"},
+			Annotations: map[string]string{"contivpp.io/custom-if": "memif1/memif/stub, memif2/memif/stub"},
+		},
+		{
+			Name:      "cnf2",
+			Namespace: "default",
+			Labels:    map[string]string{"cnf": "cnf2"},
+		},
+	}
+
+	// valid
+	sf := &sfcmodel.ServiceFunctionChain_ServiceFunction{
+		Name:            "cnf1",
+		PodSelector:     map[string]string{"cnf": "cnf1"},
+		InputInterface:  "memif1",
+		OutputInterface: "memif2",
+	}
+	Expect(validatePodServiceFunction(sf, pods)).To(BeEmpty())
+
+	// interface not declared in the annotation
+	sf.OutputInterface = "memif3"
+	violations := validatePodServiceFunction(sf, pods)
+	Expect(violations).To(HaveLen(1))
+	Expect(violations[0]).To(ContainSubstring("memif3"))
+
+	// pod without the annotation
+	sf = &sfcmodel.ServiceFunctionChain_ServiceFunction{
+		Name:        "cnf2",
+		PodSelector: map[string]string{"cnf": "cnf2"},
+		Interface:   "memif1",
+	}
+	Expect(validatePodServiceFunction(sf, pods)).To(HaveLen(1))
+
+	// selector matching nothing
+	sf = &sfcmodel.ServiceFunctionChain_ServiceFunction{
+		Name:        "cnf3",
+		PodSelector: map[string]string{"cnf": "cnf3"},
+	}
+	violations = validatePodServiceFunction(sf, pods)
+	Expect(violations).To(HaveLen(1))
+	Expect(violations[0]).To(ContainSubstring("{cnf=cnf3}"))
+}
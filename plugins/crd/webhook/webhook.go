@@ -0,0 +1,209 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"go.ligato.io/cn-infra/v2/db/keyval"
+	"go.ligato.io/cn-infra/v2/logging"
+	"go.ligato.io/cn-infra/v2/servicelabel"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/contiv/vpp/plugins/crd/handler/customnetwork"
+	customnetmodel "github.com/contiv/vpp/plugins/crd/handler/customnetwork/model"
+	"github.com/contiv/vpp/plugins/crd/handler/externalinterface"
+	extifmodel "github.com/contiv/vpp/plugins/crd/handler/externalinterface/model"
+	"github.com/contiv/vpp/plugins/crd/handler/kvdbreflector"
+	"github.com/contiv/vpp/plugins/crd/handler/servicefunctionchain"
+	sfcmodel "github.com/contiv/vpp/plugins/crd/handler/servicefunctionchain/model"
+	"github.com/contiv/vpp/plugins/crd/pkg/apis/contivppio/v1"
+	"github.com/contiv/vpp/plugins/ksr"
+)
+
+const (
+	// ValidatePath is the URL path at which the admission reviews are served.
+	ValidatePath = "/validate"
+)
+
+// Webhook implements ValidatingAdmissionWebhook for contivpp.io CRDs.
+// Instances of CustomNetwork, ExternalInterface and ServiceFunctionChain are converted
+// into the same proto models as reflected into KVDB and validated against
+// the cluster state reflected into KVDB (by KSR and contiv-crd), i.e. against
+// the same data that are used by the agents to render the configuration.
+type Webhook struct {
+	Deps
+
+	broker keyval.ProtoBroker
+	server *http.Server
+}
+
+// Deps lists dependencies of the Webhook.
+type Deps struct {
+	Log logging.Logger
+
+	// DB is used to read the cluster state reflected into KVDB.
+	DB keyval.KvProtoPlugin
+
+	// Address to listen on, certificate and private key used for TLS
+	// (the API server requires the webhook to be served over HTTPS).
+	Address  string
+	CertFile string
+	KeyFile  string
+}
+
+// Run starts serving admission reviews until the context is closed.
+func (wh *Webhook) Run(ctx <-chan struct{}) {
+	wh.broker = wh.DB.NewBroker(servicelabel.GetDifferentAgentPrefix(ksr.MicroserviceLabel))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(ValidatePath, wh.serveValidate)
+	wh.server = &http.Server{
+		Addr:    wh.Address,
+		Handler: mux,
+	}
+	go func() {
+		<-ctx
+		wh.server.Shutdown(context.Background())
+	}()
+
+	wh.Log.Infof("Serving validating admission webhook at %s%s", wh.Address, ValidatePath)
+	err := wh.server.ListenAndServeTLS(wh.CertFile, wh.KeyFile)
+	if err != nil && err != http.ErrServerClosed {
+		wh.Log.Errorf("Validating admission webhook failed: %v", err)
+	}
+}
+
+// serveValidate handles a single AdmissionReview request.
+func (wh *Webhook) serveValidate(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request: %v", err), http.StatusBadRequest)
+		return
+	}
+	review := &admissionv1beta1.AdmissionReview{}
+	if err := json.Unmarshal(body, review); err != nil || review.Request == nil {
+		http.Error(w, fmt.Sprintf("failed to decode admission review: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	review.Response = wh.review(review.Request)
+	review.Response.UID = review.Request.UID
+	review.Request = nil
+
+	resp, err := json.Marshal(review)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode admission review: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
+
+// review validates the object of the admission request.
+func (wh *Webhook) review(req *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	if req.Operation != admissionv1beta1.Create && req.Operation != admissionv1beta1.Update {
+		return &admissionv1beta1.AdmissionResponse{Allowed: true}
+	}
+
+	violations, err := wh.validate(req.Kind.Kind, req.Object.Raw)
+	if err != nil {
+		wh.Log.Errorf("Failed to validate %s %s: %v", req.Kind.Kind, req.Name, err)
+		return &admissionv1beta1.AdmissionResponse{
+			Result: &meta_v1.Status{
+				Status:  v1.StatusFailure,
+				Message: err.Error(),
+				Reason:  meta_v1.StatusReasonInternalError,
+				Code:    http.StatusInternalServerError,
+			},
+		}
+	}
+	if len(violations) > 0 {
+		wh.Log.Debugf("Rejected %s %s: %v", req.Kind.Kind, req.Name, violations)
+		causes := make([]meta_v1.StatusCause, 0, len(violations))
+		for _, violation := range violations {
+			causes = append(causes, meta_v1.StatusCause{
+				Type:    meta_v1.CauseTypeFieldValueInvalid,
+				Message: violation,
+			})
+		}
+		return &admissionv1beta1.AdmissionResponse{
+			Result: &meta_v1.Status{
+				Status:  v1.StatusFailure,
+				Message: fmt.Sprintf("invalid %s: %s", req.Kind.Kind, strings.Join(violations, "; ")),
+				Reason:  meta_v1.StatusReasonInvalid,
+				Code:    http.StatusUnprocessableEntity,
+				Details: &meta_v1.StatusDetails{
+					Name:   req.Name,
+					Kind:   req.Kind.Kind,
+					Causes: causes,
+				},
+			},
+		}
+	}
+	return &admissionv1beta1.AdmissionResponse{Allowed: true}
+}
+
+// validate decodes the object of the given kind, converts it into the proto
+// model reflected into KVDB and returns the list of semantic violations found.
+func (wh *Webhook) validate(kind string, raw []byte) (violations []string, err error) {
+	var (
+		obj     interface{}
+		handler interface {
+			CrdObjectToKVData(obj interface{}) (data []kvdbreflector.KVData, err error)
+		}
+	)
+	switch kind {
+	case "CustomNetwork":
+		obj, handler = &v1.CustomNetwork{}, &customnetwork.Handler{}
+	case "ExternalInterface":
+		obj, handler = &v1.ExternalInterface{}, &externalinterface.Handler{}
+	case "ServiceFunctionChain":
+		obj, handler = &v1.ServiceFunctionChain{}, &servicefunctionchain.Handler{}
+	default:
+		// not validated
+		return nil, nil
+	}
+	if err := json.Unmarshal(raw, obj); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", kind, err)
+	}
+	data, err := handler.CrdObjectToKVData(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, kvData := range data {
+		var kvViolations []string
+		switch msg := kvData.ProtoMsg.(type) {
+		case *customnetmodel.CustomNetwork:
+			kvViolations, err = wh.validateCustomNetwork(msg)
+		case *extifmodel.ExternalInterface:
+			kvViolations, err = wh.validateExternalInterface(msg)
+		case *sfcmodel.ServiceFunctionChain:
+			kvViolations, err = wh.validateServiceFunctionChain(msg)
+		}
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, kvViolations...)
+	}
+	return violations, nil
+}
@@ -0,0 +1,374 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:generate protoc -I ./model --go_out=plugins=grpc:./model ./model/crdstatus.proto
+
+package crdstatus
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"go.ligato.io/cn-infra/v2/db/keyval"
+	"go.ligato.io/cn-infra/v2/infra"
+	"go.ligato.io/cn-infra/v2/servicelabel"
+
+	scheduler "go.ligato.io/vpp-agent/v3/plugins/kvscheduler/api"
+
+	controller "github.com/contiv/vpp/plugins/controller/api"
+	customnetmodel "github.com/contiv/vpp/plugins/crd/handler/customnetwork/model"
+	extifmodel "github.com/contiv/vpp/plugins/crd/handler/externalinterface/model"
+	sfcmodel "github.com/contiv/vpp/plugins/crd/handler/servicefunctionchain/model"
+	"github.com/contiv/vpp/plugins/crdstatus/model"
+	"github.com/contiv/vpp/plugins/ksr"
+)
+
+const (
+	// label used by Controller for the external configuration read from KVDB
+	kvdbConfigSource = "db"
+)
+
+var (
+	// prefix under which all k8s resources are stored in DB
+	ksrPrefix = servicelabel.GetDifferentAgentPrefix(ksr.MicroserviceLabel)
+
+	// resources reflected from CRDs into the KSR key-space
+	crdResources = []string{
+		sfcmodel.Keyword,
+		extifmodel.Keyword,
+		customnetmodel.Keyword,
+	}
+)
+
+// CRDStatus plugin publishes the outcome of applying the CRD-reflected configuration
+// on this node into KVDB. Statuses are determined inside the event loop, but written
+// into KVDB asynchronously by a separate go routine, which batches the changes made
+// since the last write (only the latest status of every key is written).
+type CRDStatus struct {
+	Deps
+
+	thisNode    string
+	agentPrefix string
+	broker      keyval.ProtoBroker
+
+	// keys of the external configuration read from KVDB (relative to agent prefix)
+	extConfig map[string]struct{}
+
+	// config key (full path) -> last published status
+	published       map[string]*model.RealizationStatus
+	publishedLoaded bool
+
+	// statuses waiting to be written into KVDB
+	// (config key (full path) -> status, nil if the status should be removed)
+	pendingLock sync.Mutex
+	pending     map[string]*model.RealizationStatus
+	notify      chan struct{}
+	quit        chan struct{}
+	wg          sync.WaitGroup
+
+	// changes of the event being processed (config key (full path) -> false if removed)
+	event   controller.Event
+	changes map[string]bool
+}
+
+// Deps lists dependencies of the CRDStatus plugin.
+type Deps struct {
+	infra.PluginDeps
+	ServiceLabel servicelabel.ReaderAPI
+	DB           keyval.KvProtoPlugin
+}
+
+// Init initializes the internal state of the plugin.
+func (cs *CRDStatus) Init() error {
+	cs.thisNode = cs.ServiceLabel.GetAgentLabel()
+	cs.agentPrefix = cs.ServiceLabel.GetAgentPrefix()
+	cs.broker = cs.DB.NewBroker(model.NodeKeyPrefix(cs.thisNode))
+	cs.extConfig = make(map[string]struct{})
+	cs.published = make(map[string]*model.RealizationStatus)
+	cs.pending = make(map[string]*model.RealizationStatus)
+	cs.notify = make(chan struct{}, 1)
+	cs.quit = make(chan struct{})
+
+	cs.wg.Add(1)
+	go cs.publisher()
+	return nil
+}
+
+// HandlesEvent selects:
+//   - any Resync event
+//   - KubeStateChange for CRD-reflected resources
+//   - ExternalConfigChange coming from KVDB
+func (cs *CRDStatus) HandlesEvent(event controller.Event) bool {
+	if event.Method() != controller.Update {
+		return true
+	}
+	if ksChange, isKSChange := event.(*controller.KubeStateChange); isKSChange {
+		for _, resource := range crdResources {
+			if ksChange.Resource == resource {
+				return true
+			}
+		}
+		return false
+	}
+	if extChange, isExtChange := event.(*controller.ExternalConfigChange); isExtChange {
+		return extChange.Source == kvdbConfigSource
+	}
+
+	// unhandled event
+	return false
+}
+
+// Resync collects keys of all CRD-reflected resources and of the external
+// configuration, whose status will be published once the resync is finalized.
+func (cs *CRDStatus) Resync(event controller.Event, kubeStateData controller.KubeStateData,
+	resyncCount int, _ controller.ResyncOperations) error {
+
+	if dbResync, isDBResync := event.(*controller.DBResync); isDBResync {
+		if dbResync.Local {
+			// local DB does not contain external configuration and remote
+			// DB is not accessible anyway
+			return nil
+		}
+		cs.extConfig = make(map[string]struct{})
+		for key := range dbResync.ExternalConfig {
+			cs.extConfig[key] = struct{}{}
+		}
+	}
+
+	cs.event = event
+	cs.changes = make(map[string]bool)
+	for _, resource := range crdResources {
+		for key := range kubeStateData[resource] {
+			cs.changes[ksrPrefix+key] = true
+		}
+	}
+	for key := range cs.extConfig {
+		cs.changes[cs.agentPrefix+key] = true
+	}
+	return nil
+}
+
+// Update remembers keys changed by the event, whose status will be published
+// once the event is finalized.
+func (cs *CRDStatus) Update(event controller.Event, _ controller.UpdateOperations) (changeDescription string, err error) {
+	cs.event = event
+	cs.changes = make(map[string]bool)
+
+	switch ev := event.(type) {
+	case *controller.KubeStateChange:
+		cs.changes[ksrPrefix+ev.Key] = ev.NewValue != nil
+
+	case *controller.ExternalConfigChange:
+		for key, value := range ev.UpdatedKVs {
+			if value == nil {
+				delete(cs.extConfig, key)
+			} else {
+				cs.extConfig[key] = struct{}{}
+			}
+			cs.changes[cs.agentPrefix+key] = value != nil
+		}
+	}
+	return "", nil
+}
+
+// EventFinalized publishes realization status for every key changed by the event.
+// The statuses are only queued for the publisher, the event loop does not wait
+// for KVDB.
+func (cs *CRDStatus) EventFinalized(event controller.Event, err error) {
+	if event != cs.event {
+		// processing was aborted before the plugin got to handle the event
+		return
+	}
+	defer func() {
+		cs.event = nil
+		cs.changes = nil
+	}()
+
+	isResync := event.Method() != controller.Update
+	if isResync && !cs.publishedLoaded {
+		cs.loadPublished()
+	}
+
+	// split the error between the external configuration and the rest
+	var (
+		kvErrors    map[string]error // key relative to agent prefix -> error
+		internalErr = err
+	)
+	if txnErr, isTxnErr := err.(*scheduler.TransactionError); isTxnErr && txnErr.GetTxnInitError() == nil {
+		kvErrors = make(map[string]error)
+		internalErr = nil
+		for _, kvErr := range txnErr.GetKVErrors() {
+			if _, isExtConfig := cs.extConfig[kvErr.Key]; isExtConfig {
+				kvErrors[kvErr.Key] = kvErr.Error
+			} else {
+				internalErr = err
+			}
+		}
+	}
+
+	now := time.Now().Unix()
+	for key, present := range cs.changes {
+		if !present {
+			cs.unpublish(key)
+			continue
+		}
+		keyErr := internalErr
+		if extKey, isExtKey := cs.extConfigKey(key); isExtKey {
+			keyErr = err
+			if kvErrors != nil {
+				keyErr = kvErrors[extKey]
+			}
+		}
+		status := &model.RealizationStatus{
+			Node:      cs.thisNode,
+			Key:       key,
+			State:     model.RealizationStatus_SUCCESS,
+			Timestamp: now,
+		}
+		if keyErr != nil {
+			status.State = model.RealizationStatus_ERROR
+			status.Error = keyErr.Error()
+		}
+		cs.publish(status)
+	}
+
+	if isResync {
+		// remove statuses of configuration which no longer exists
+		for key := range cs.published {
+			if _, exists := cs.changes[key]; !exists {
+				cs.unpublish(key)
+			}
+		}
+	}
+
+	// wake up the publisher
+	select {
+	case cs.notify <- struct{}{}:
+	default:
+		// already notified
+	}
+}
+
+// Revert is NOOP - nothing is published until the event is finalized.
+func (cs *CRDStatus) Revert(event controller.Event) error {
+	return nil
+}
+
+// Close stops the publisher once the pending statuses are written.
+func (cs *CRDStatus) Close() error {
+	close(cs.quit)
+	cs.wg.Wait()
+	return nil
+}
+
+// extConfigKey returns key relative to the agent prefix if the given key belongs
+// to the external configuration.
+func (cs *CRDStatus) extConfigKey(key string) (extKey string, isExtKey bool) {
+	if !strings.HasPrefix(key, cs.agentPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(key, cs.agentPrefix), true
+}
+
+// publish queues status to be written into KVDB unless it has not changed since
+// the last time.
+func (cs *CRDStatus) publish(status *model.RealizationStatus) {
+	if prev, hasPrev := cs.published[status.Key]; hasPrev &&
+		prev.State == status.State && prev.Error == status.Error {
+		return
+	}
+	cs.published[status.Key] = status
+	cs.enqueue(status.Key, status, true)
+}
+
+// unpublish queues status to be removed from KVDB.
+func (cs *CRDStatus) unpublish(key string) {
+	if _, hasPrev := cs.published[key]; !hasPrev {
+		return
+	}
+	delete(cs.published, key)
+	cs.enqueue(key, nil, true)
+}
+
+// enqueue adds status (nil to remove) into the queue of the publisher.
+// With <override> set to false, status already queued for the key is preserved.
+func (cs *CRDStatus) enqueue(key string, status *model.RealizationStatus, override bool) {
+	cs.pendingLock.Lock()
+	defer cs.pendingLock.Unlock()
+	if _, queued := cs.pending[key]; queued && !override {
+		return
+	}
+	cs.pending[key] = status
+}
+
+// publisher writes queued statuses into KVDB whenever notified, until the plugin
+// is closed.
+func (cs *CRDStatus) publisher() {
+	defer cs.wg.Done()
+	for {
+		select {
+		case <-cs.notify:
+			cs.flush()
+		case <-cs.quit:
+			cs.flush()
+			return
+		}
+	}
+}
+
+// flush writes all queued statuses into KVDB. Statuses which failed to be written
+// are queued again (unless replaced in the meantime) and retried with the next batch.
+func (cs *CRDStatus) flush() {
+	cs.pendingLock.Lock()
+	pending := cs.pending
+	cs.pending = make(map[string]*model.RealizationStatus)
+	cs.pendingLock.Unlock()
+
+	for key, status := range pending {
+		if status == nil {
+			if _, err := cs.broker.Delete(model.KeySuffix(key)); err != nil {
+				cs.Log.Warnf("Failed to remove realization status for key %s: %v", key, err)
+				cs.enqueue(key, nil, false)
+			}
+			continue
+		}
+		if err := cs.broker.Put(model.KeySuffix(key), status); err != nil {
+			cs.Log.Warnf("Failed to publish realization status for key %s: %v", key, err)
+			cs.enqueue(key, status, false)
+		}
+	}
+}
+
+// loadPublished loads statuses published by the previous run of the agent.
+func (cs *CRDStatus) loadPublished() {
+	it, err := cs.broker.ListValues("")
+	if err != nil {
+		cs.Log.Warnf("Failed to list previously published realization statuses: %v", err)
+		return
+	}
+	for {
+		kv, stop := it.GetNext()
+		if stop {
+			break
+		}
+		status := &model.RealizationStatus{}
+		if err := kv.GetValue(status); err != nil {
+			cs.Log.Warnf("Failed to read realization status %s: %v", kv.GetKey(), err)
+			continue
+		}
+		cs.published[status.Key] = status
+	}
+	cs.publishedLoaded = true
+}
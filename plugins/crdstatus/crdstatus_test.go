@@ -0,0 +1,237 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crdstatus
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+	"go.ligato.io/cn-infra/v2/datasync"
+	"go.ligato.io/cn-infra/v2/logging/logrus"
+	scheduler "go.ligato.io/vpp-agent/v3/plugins/kvscheduler/api"
+
+	"github.com/contiv/vpp/mock/broker"
+	controller "github.com/contiv/vpp/plugins/controller/api"
+	customnetmodel "github.com/contiv/vpp/plugins/crd/handler/customnetwork/model"
+	"github.com/contiv/vpp/plugins/crdstatus/model"
+)
+
+const (
+	testNode        = "node1"
+	testAgentPrefix = "/vnf-agent/node1/"
+
+	loop1Key = "config/vpp/v2/interfaces/loop1"
+	loop2Key = "config/vpp/v2/interfaces/loop2"
+)
+
+// failingBroker is a broker which fails to write while <fail> is set.
+type failingBroker struct {
+	*broker.MockBroker
+	fail bool
+}
+
+func (fb *failingBroker) Put(key string, data proto.Message, opts ...datasync.PutOption) error {
+	if fb.fail {
+		return errors.New("etcd unavailable")
+	}
+	return fb.MockBroker.Put(key, data, opts...)
+}
+
+func newTestCRDStatus(kvdb *failingBroker) *CRDStatus {
+	cs := &CRDStatus{
+		thisNode:    testNode,
+		agentPrefix: testAgentPrefix,
+		broker:      kvdb,
+		extConfig:   make(map[string]struct{}),
+		published:   make(map[string]*model.RealizationStatus),
+		pending:     make(map[string]*model.RealizationStatus),
+		notify:      make(chan struct{}, 1),
+		quit:        make(chan struct{}),
+	}
+	cs.Log = logrus.DefaultLogger()
+	return cs
+}
+
+// publishedStatus returns status published in the broker for the given config key (full path).
+func publishedStatus(kvdb *failingBroker, configKey string) *model.RealizationStatus {
+	value, published := kvdb.Data[model.KeySuffix(configKey)]
+	if !published {
+		return nil
+	}
+	return value.(*model.RealizationStatus)
+}
+
+func externalConfigChange(keys ...string) *controller.ExternalConfigChange {
+	change := controller.NewExternalConfigChange(kvdbConfigSource, false)
+	change.UpdatedKVs = make(controller.KeyValuePairs)
+	for _, key := range keys {
+		change.UpdatedKVs[key] = &customnetmodel.CustomNetwork{}
+	}
+	return change
+}
+
+func customNetworkChange(name string, removed bool) *controller.KubeStateChange {
+	change := &controller.KubeStateChange{
+		Resource:  customnetmodel.Keyword,
+		Key:       customnetmodel.Key(name),
+		PrevValue: &customnetmodel.CustomNetwork{Name: name},
+		NewValue:  &customnetmodel.CustomNetwork{Name: name},
+	}
+	if removed {
+		change.NewValue = nil
+	}
+	return change
+}
+
+func TestTransactionErrorSplit(t *testing.T) {
+	RegisterTestingT(t)
+	kvdb := &failingBroker{MockBroker: &broker.MockBroker{}}
+	cs := newTestCRDStatus(kvdb)
+
+	// error of a single external configuration item
+	event := externalConfigChange(loop1Key, loop2Key)
+	_, err := cs.Update(event, nil)
+	Expect(err).ToNot(HaveOccurred())
+	txnErr := scheduler.NewTransactionError(nil, []scheduler.KeyWithError{
+		{Key: loop1Key, TxnOperation: scheduler.TxnOperation_CREATE, Error: errors.New("invalid IP")},
+	})
+	cs.EventFinalized(event, txnErr)
+	cs.flush()
+	Expect(publishedStatus(kvdb, testAgentPrefix+loop1Key).State).To(Equal(model.RealizationStatus_ERROR))
+	Expect(publishedStatus(kvdb, testAgentPrefix+loop1Key).Error).To(Equal("invalid IP"))
+	Expect(publishedStatus(kvdb, testAgentPrefix+loop2Key).State).To(Equal(model.RealizationStatus_SUCCESS))
+
+	// errors of external configuration items do not fail CRDs
+	netKey := ksrPrefix + customnetmodel.Key("net1")
+	event2 := customNetworkChange("net1", false)
+	_, err = cs.Update(event2, nil)
+	Expect(err).ToNot(HaveOccurred())
+	cs.EventFinalized(event2, txnErr)
+	cs.flush()
+	Expect(publishedStatus(kvdb, netKey).State).To(Equal(model.RealizationStatus_SUCCESS))
+
+	// errors of the configuration derived from CRDs (not external) fail CRDs
+	event3 := customNetworkChange("net1", false)
+	_, err = cs.Update(event3, nil)
+	Expect(err).ToNot(HaveOccurred())
+	cs.EventFinalized(event3, scheduler.NewTransactionError(nil, []scheduler.KeyWithError{
+		{Key: loop1Key, TxnOperation: scheduler.TxnOperation_CREATE, Error: errors.New("invalid IP")},
+		{Key: "config/vpp/v2/vrf-table/id/10/protocol/IPV4", TxnOperation: scheduler.TxnOperation_CREATE,
+			Error: errors.New("VRF exists")},
+	}))
+	cs.flush()
+	Expect(publishedStatus(kvdb, netKey).State).To(Equal(model.RealizationStatus_ERROR))
+
+	// transaction which failed as a whole fails every item
+	event4 := externalConfigChange(loop2Key)
+	_, err = cs.Update(event4, nil)
+	Expect(err).ToNot(HaveOccurred())
+	cs.EventFinalized(event4, scheduler.NewTransactionError(errors.New("invalid transaction"), nil))
+	cs.flush()
+	Expect(publishedStatus(kvdb, testAgentPrefix+loop2Key).State).To(Equal(model.RealizationStatus_ERROR))
+	Expect(publishedStatus(kvdb, testAgentPrefix+loop2Key).Error).To(ContainSubstring("invalid transaction"))
+
+	// event which was not handled by the plugin is ignored
+	cs.EventFinalized(externalConfigChange(loop1Key), nil)
+	cs.flush()
+	Expect(publishedStatus(kvdb, testAgentPrefix+loop1Key).State).To(Equal(model.RealizationStatus_ERROR))
+}
+
+func TestUnpublishOnResync(t *testing.T) {
+	RegisterTestingT(t)
+	kvdb := &failingBroker{MockBroker: &broker.MockBroker{}}
+	cs := newTestCRDStatus(kvdb)
+
+	// statuses published by the previous run of the agent
+	net1Key := ksrPrefix + customnetmodel.Key("net1")
+	net2Key := ksrPrefix + customnetmodel.Key("net2")
+	for _, key := range []string{net1Key, net2Key, testAgentPrefix + loop1Key} {
+		Expect(kvdb.Put(model.KeySuffix(key), &model.RealizationStatus{
+			Node: testNode, Key: key, State: model.RealizationStatus_SUCCESS})).To(Succeed())
+	}
+
+	// local resync is ignored (external configuration is not known)
+	localResync := controller.NewDBResync()
+	localResync.Local = true
+	Expect(cs.Resync(localResync, localResync.KubeState, 1, nil)).To(Succeed())
+	cs.EventFinalized(localResync, nil)
+	cs.flush()
+	Expect(kvdb.Data).To(HaveLen(3))
+
+	// net2 and loop1 no longer exist
+	resync := controller.NewDBResync()
+	resync.KubeState[customnetmodel.Keyword] = controller.KeyValuePairs{
+		customnetmodel.Key("net1"): &customnetmodel.CustomNetwork{Name: "net1"},
+	}
+	resync.ExternalConfig[loop2Key] = &customnetmodel.CustomNetwork{}
+	Expect(cs.Resync(resync, resync.KubeState, 1, nil)).To(Succeed())
+	cs.EventFinalized(resync, nil)
+	cs.flush()
+	Expect(kvdb.Data).To(HaveLen(2))
+	Expect(publishedStatus(kvdb, net1Key).State).To(Equal(model.RealizationStatus_SUCCESS))
+	Expect(publishedStatus(kvdb, testAgentPrefix+loop2Key).State).To(Equal(model.RealizationStatus_SUCCESS))
+	Expect(publishedStatus(kvdb, net2Key)).To(BeNil())
+	Expect(publishedStatus(kvdb, testAgentPrefix+loop1Key)).To(BeNil())
+
+	// removed by update
+	event := customNetworkChange("net1", true)
+	_, err := cs.Update(event, nil)
+	Expect(err).ToNot(HaveOccurred())
+	cs.EventFinalized(event, nil)
+	cs.flush()
+	Expect(publishedStatus(kvdb, net1Key)).To(BeNil())
+
+	// next resync with failed commit - everything gets error status
+	resync2 := controller.NewDBResync()
+	resync2.ExternalConfig[loop2Key] = &customnetmodel.CustomNetwork{}
+	Expect(cs.Resync(resync2, resync2.KubeState, 2, nil)).To(Succeed())
+	cs.EventFinalized(resync2, errors.New("vpp-agent not available"))
+	cs.flush()
+	Expect(kvdb.Data).To(HaveLen(1))
+	Expect(publishedStatus(kvdb, testAgentPrefix+loop2Key).State).To(Equal(model.RealizationStatus_ERROR))
+}
+
+func TestAsyncPublishing(t *testing.T) {
+	RegisterTestingT(t)
+	kvdb := &failingBroker{MockBroker: &broker.MockBroker{}, fail: true}
+	cs := newTestCRDStatus(kvdb)
+
+	// failed writes are kept in the queue
+	event := externalConfigChange(loop1Key)
+	_, err := cs.Update(event, nil)
+	Expect(err).ToNot(HaveOccurred())
+	cs.EventFinalized(event, nil)
+	cs.flush()
+	Expect(kvdb.Data).To(BeEmpty())
+	Expect(cs.pending).To(HaveKey(testAgentPrefix + loop1Key))
+
+	// unchanged status is not queued again, but the failed one is retried
+	kvdb.fail = false
+	event2 := externalConfigChange(loop1Key, loop2Key)
+	_, err = cs.Update(event2, nil)
+	Expect(err).ToNot(HaveOccurred())
+	cs.EventFinalized(event2, nil)
+
+	// the publisher writes the pending statuses before it is closed
+	cs.wg.Add(1)
+	go cs.publisher()
+	Expect(cs.Close()).To(Succeed())
+	Expect(cs.pending).To(BeEmpty())
+	Expect(kvdb.Data).To(HaveLen(2))
+	Expect(publishedStatus(kvdb, testAgentPrefix+loop1Key).State).To(Equal(model.RealizationStatus_SUCCESS))
+	Expect(publishedStatus(kvdb, testAgentPrefix+loop2Key).State).To(Equal(model.RealizationStatus_SUCCESS))
+}
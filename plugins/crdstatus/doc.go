@@ -0,0 +1,11 @@
+// Package crdstatus publishes the outcome of applying the configuration reflected
+// from CRDs on this node into KVDB.
+//
+// For every ServiceFunctionChain, ExternalInterface and CustomNetwork instance
+// and for every item of the external configuration (e.g. from CustomConfiguration)
+// the vswitch publishes RealizationStatus under the key prefix
+// /contiv-crd/status/<node-name>/ once the associated event has been fully
+// processed (including the vpp-agent transaction). The statuses are collected
+// by contiv-crd and aggregated into the Status of the CRD instances, broken
+// down by nodes.
+package crdstatus
@@ -0,0 +1,56 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import "strings"
+
+// KeyPrefix is a key prefix under which vswitches publish realization statuses
+// of the configuration reflected from CRDs into KVDB.
+// The prefix is deliberately outside of the KSR and agent prefixes watched
+// by the vswitches.
+const KeyPrefix = "/contiv-crd/status/"
+
+// NodeKeyPrefix returns the key prefix under which the given node publishes
+// realization statuses.
+func NodeKeyPrefix(node string) string {
+	return KeyPrefix + node + "/"
+}
+
+// Key returns the key under which the given node publishes realization status
+// of the configuration stored in KVDB under <configKey> (full path).
+func Key(node, configKey string) string {
+	return NodeKeyPrefix(node) + KeySuffix(configKey)
+}
+
+// KeySuffix returns suffix of the key identifying realization status
+// of the given configuration, relative to NodeKeyPrefix.
+func KeySuffix(configKey string) string {
+	return strings.TrimPrefix(configKey, "/")
+}
+
+// ParseKey parses node name and the (full) key of the applied configuration
+// from a key identifying realization status.
+// Returns empty strings if the key is not valid.
+func ParseKey(key string) (node, configKey string) {
+	if !strings.HasPrefix(key, KeyPrefix) {
+		return "", ""
+	}
+	suffix := strings.TrimPrefix(key, KeyPrefix)
+	idx := strings.Index(suffix, "/")
+	if idx <= 0 || idx == len(suffix)-1 {
+		return "", ""
+	}
+	return suffix[:idx], "/" + suffix[idx+1:]
+}
@@ -241,6 +241,59 @@ func (a *IDAllocator) GetOrAllocateID(poolName string, idLabel string) (id uint3
 	return
 }
 
+// AllocateID allocates the given (pinned) ID in given pool for given label.
+// If the label already has a different ID allocated, the allocation is changed.
+// Returns error if the ID is reserved or already allocated for another label.
+func (a *IDAllocator) AllocateID(poolName string, idLabel string, id uint32) (err error) {
+
+	pool := a.poolCache[poolName]
+	if pool == nil {
+		err = fmt.Errorf("ID pool %s does not exist", poolName)
+		a.Log.Error(err)
+		return
+	}
+	poolMeta := a.poolMeta[poolName]
+	if poolMeta == nil {
+		a.poolMeta[poolName] = a.buildPoolMetadata(pool)
+		poolMeta = a.poolMeta[poolName]
+	}
+
+	succeeded := false
+	for i := 0; i < maxIDAllocationAttempts; i++ {
+		var prevID uint32
+		prevID, succeeded, err = a.tryToAllocatePinnedID(pool, poolMeta, idLabel, id)
+		if err != nil {
+			break
+		}
+		if succeeded {
+			// successfully allocated the ID
+			if prevID != id {
+				delete(poolMeta.allocatedIDs, prevID)
+			}
+			poolMeta.allocatedIDs[id] = idLabel
+			break
+		} else {
+			// pool changed in db, re-read from db and retry
+			pool, err = a.dbReadPool(poolName)
+			if err != nil {
+				break
+			}
+			a.poolMeta[poolName] = a.buildPoolMetadata(pool)
+			poolMeta = a.poolMeta[poolName]
+		}
+	}
+	if !succeeded && err == nil {
+		err = fmt.Errorf("ID allocation for pool %s failed in %d attempts", pool.Name, maxIDAllocationAttempts)
+	}
+	if err != nil {
+		a.Log.Errorf("Error by allocating ID: %v", err)
+		return err
+	}
+
+	a.Log.Debugf("Pinned ID for label '%s' in pool %s: %d", idLabel, poolName, id)
+	return nil
+}
+
 // ReleaseID releases existing allocation for given pool and label.
 // NOOP if the pool or allocation does not exist.
 func (a *IDAllocator) ReleaseID(poolName string, idLabel string) (err error) {
@@ -335,6 +388,52 @@ func (a *IDAllocator) tryToAllocateID(pool *idallocation.AllocationPool, poolMet
 	return
 }
 
+// tryToAllocatePinnedID attempts to allocate the given ID for given pool and label.
+// Returns the ID previously allocated for the label (equal to <id> if there was none).
+func (a *IDAllocator) tryToAllocatePinnedID(pool *idallocation.AllocationPool, poolMeta *poolMetadata,
+	idLabel string, id uint32) (prevID uint32, succeeded bool, err error) {
+
+	// step 0, check if the ID is already allocated for the label
+	prevID = id
+	if alloc, exists := pool.IdAllocations[idLabel]; exists {
+		if alloc.Id == id {
+			return id, true, nil
+		}
+		prevID = alloc.Id
+	}
+
+	// step 1, check that the ID is available
+	if _, reserved := poolMeta.reservedIDs[id]; reserved {
+		err = fmt.Errorf("ID %d is reserved in pool %s", id, pool.Name)
+		return
+	}
+	if label, used := poolMeta.allocatedIDs[id]; used && label != idLabel {
+		err = fmt.Errorf("ID %d is already allocated in pool %s for '%s'", id, pool.Name, label)
+		return
+	}
+
+	// step 2, try to write into db
+	prevData, err := a.serializer.Marshal(pool)
+	if err != nil {
+		return prevID, false, err
+	}
+	pool.IdAllocations[idLabel] = &idallocation.AllocationPool_Allocation{
+		Id:    id,
+		Owner: a.ServiceLabel.GetAgentLabel(),
+	}
+	newData, err := a.serializer.Marshal(pool)
+	if err != nil {
+		return prevID, false, err
+	}
+	db, err := a.getDBBroker()
+	if err != nil {
+		a.Log.Error(err)
+		return prevID, false, err
+	}
+	succeeded, err = db.CompareAndSwap(idallocation.Key(pool.Name), prevData, newData)
+	return
+}
+
 // tryToReleaseID attempts to release an ID for given pool and label.
 func (a *IDAllocator) tryToReleaseID(pool *idallocation.AllocationPool, poolMeta *poolMetadata, idLabel string) (
 	succeeded bool, err error) {
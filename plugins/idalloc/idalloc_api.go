@@ -30,6 +30,13 @@ type API interface {
 	// not already allocated, allocates new available ID.
 	GetOrAllocateID(poolName string, idLabel string) (id uint32, err error)
 
+	// AllocateID allocates the given (pinned) ID in given pool for given label.
+	// If the label already has a different ID allocated, the allocation is changed.
+	// Returns error if the ID is reserved or already allocated for another label.
+	// The ID does not have to be from the pool range - this allows to use IDs
+	// that are never allocated automatically.
+	AllocateID(poolName string, idLabel string, id uint32) (err error)
+
 	// ReleaseID releases existing allocation for given pool and label.
 	// NOOP if the allocation does not exist.
 	ReleaseID(poolName string, idLabel string) (err error)
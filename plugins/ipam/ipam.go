@@ -99,6 +99,28 @@ type IPAM struct {
 	vxlanSubnet *net.IPNet
 	// IP subnet used to allocate ClusterIPs for a service
 	serviceCIDR *net.IPNet
+
+	/********** dual-stack related variables **********/
+	// subnets and addresses of the secondary IP family, nil if dual-stack is not enabled
+	secondary *secondaryFamilyInfo
+}
+
+// secondaryFamilyInfo holds subnets and addresses of the secondary IP family used in the dual-stack mode.
+type secondaryFamilyInfo struct {
+	// secondary pod network of the default pod network (one secondary address per pod)
+	podNetwork *podNetworkInfo
+	// IP subnet used across all nodes for VPP to host Linux stack interconnect
+	hostInterconnectSubnetAllNodes *net.IPNet
+	// IP subnet used by this node for VPP to host Linux stack interconnect
+	hostInterconnectSubnetThisNode *net.IPNet
+	// IP address for virtual ethernet's VPP-end on this node
+	hostInterconnectIPInVpp net.IP
+	// IP address for virtual ethernet's host(Linux)-end on this node
+	hostInterconnectIPInLinux net.IP
+	// IP subnet used for for inter-node VXLAN
+	vxlanSubnet *net.IPNet
+	// IP subnet used to allocate ClusterIPs for a service
+	serviceCIDR *net.IPNet
 }
 
 type podNetworkInfo struct {
@@ -109,10 +131,18 @@ type podNetworkInfo struct {
 	podSubnetThisNode *net.IPNet
 	// gateway IP address for PODs on this node (given by nodeID)
 	podSubnetGatewayIP net.IP
+	// address ranges excluded from the allocation
+	reservedRanges []*ipRange
 	// counter denoting last assigned pod IP address
 	lastPodIPAssigned int
 }
 
+// ipRange is an inclusive range of IP addresses.
+type ipRange struct {
+	start net.IP
+	end   net.IP
+}
+
 // podIPAllocation represents allocation of an IP address from the IPAM pool.
 // It holds the information about the pod and the interface to which this allocation belongs.
 type podIPAllocation struct {
@@ -125,6 +155,7 @@ type podIPAllocation struct {
 // podIPInfo holds the IP address allocation info related to a pod.
 type podIPInfo struct {
 	mainIP      net.IP            // IP address of the main interface
+	secondaryIP net.IP            // IP address of the main interface from the secondary IP family (dual-stack only)
 	customIfIPs map[string]net.IP // custom interface name + network to IP address map
 }
 
@@ -137,8 +168,30 @@ type extIfIPInfo struct {
 
 // String provides human-readable representation of podNetworkInfo
 func (i *podNetworkInfo) String() string {
-	return fmt.Sprintf("<podSubnetAllNodes=%v, podSubnetThisNode=%v, podSubnetGatewayIP=%v, lastPodIPAssigned=%d>",
-		i.podSubnetAllNodes, i.podSubnetThisNode, i.podSubnetGatewayIP, i.lastPodIPAssigned)
+	return fmt.Sprintf("<podSubnetAllNodes=%v, podSubnetThisNode=%v, podSubnetGatewayIP=%v, "+
+		"reservedRanges=%v, lastPodIPAssigned=%d>",
+		i.podSubnetAllNodes, i.podSubnetThisNode, i.podSubnetGatewayIP, i.reservedRanges, i.lastPodIPAssigned)
+}
+
+// isReserved returns true if the given IP address falls into one of the reserved ranges.
+func (i *podNetworkInfo) isReserved(ip net.IP) bool {
+	for _, r := range i.reservedRanges {
+		if r.contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// String provides human-readable representation of ipRange
+func (r *ipRange) String() string {
+	return r.start.String() + "-" + r.end.String()
+}
+
+// contains returns true if the given IP address is from the range.
+func (r *ipRange) contains(ip net.IP) bool {
+	ip = ip.To16()
+	return bytes.Compare(ip, r.start.To16()) >= 0 && bytes.Compare(ip, r.end.To16()) <= 0
 }
 
 // String provides human-readable representation of podIPAllocation
@@ -151,10 +204,21 @@ func (a *podIPAllocation) String() string {
 
 // String provides human-readable representation of podIPInfo
 func (i *podIPInfo) String() string {
-	if len(i.customIfIPs) == 0 {
+	if len(i.customIfIPs) == 0 && i.secondaryIP == nil {
 		return fmt.Sprintf("<IP=%s>", i.mainIP)
 	}
-	return fmt.Sprintf("<mainIP=%s, customIPs=%+v>", i.mainIP, i.customIfIPs)
+	if i.secondaryIP == nil {
+		return fmt.Sprintf("<mainIP=%s, customIPs=%+v>", i.mainIP, i.customIfIPs)
+	}
+	return fmt.Sprintf("<mainIP=%s, secondaryIP=%s, customIPs=%+v>", i.mainIP, i.secondaryIP, i.customIfIPs)
+}
+
+// String provides human-readable representation of secondaryFamilyInfo
+func (i *secondaryFamilyInfo) String() string {
+	return fmt.Sprintf("<podNetwork=%v, hostInterconnectSubnetAllNodes=%v, hostInterconnectSubnetThisNode=%v, "+
+		"hostInterconnectIPInVpp=%v, hostInterconnectIPInLinux=%v, vxlanSubnet=%v, serviceCIDR=%v>",
+		i.podNetwork, i.hostInterconnectSubnetAllNodes, i.hostInterconnectSubnetThisNode,
+		i.hostInterconnectIPInVpp, i.hostInterconnectIPInLinux, i.vxlanSubnet, i.serviceCIDR)
 }
 
 // String provides human-readable representation of extIfIPInfo
@@ -272,12 +336,18 @@ func (i *IPAM) Resync(event controller.Event, kubeStateData controller.KubeState
 	i.serviceCIDR = ipamConfig.ServiceCIDR
 	i.nodeInterconnectSubnet = subnets.NodeInterconnectCIDR
 	i.vxlanSubnet = subnets.VxlanCIDR
+	i.secondary = nil
+	if ipamConfig.DualStack {
+		if err := i.initializeSecondaryFamily(ipamConfig, nodeID); err != nil {
+			return err
+		}
+	}
 
 	// resync custom pod networks
 	for _, extIfProto := range kubeStateData[customnetmodel.Keyword] {
 		nw := extIfProto.(*customnetmodel.CustomNetwork)
 		if nw.Type == customnetmodel.CustomNetwork_L3 && nw.SubnetCIDR != "" && nw.SubnetOneNodePrefix > 0 {
-			err = i.initializeCustomPodNetwork(nw.Name, nw.SubnetCIDR, nw.SubnetOneNodePrefix)
+			err = i.initializeCustomPodNetwork(nw)
 			if err != nil {
 				i.Log.Warnf("Error by initializing pod network %s: %v - skipping", nw.Name, err)
 			}
@@ -317,6 +387,9 @@ func (i *IPAM) Resync(event controller.Event, kubeStateData controller.KubeState
 				// NOTE: ignoring pods outside of all pod subnets (across all nodes and networks)
 			}
 		}
+		if i.secondary != nil {
+			i.resyncSecondaryPodIP(podID, pod.IpAddresses)
+		}
 	}
 
 	// external interfaces
@@ -371,11 +444,11 @@ func (i *IPAM) Resync(event controller.Event, kubeStateData controller.KubeState
 	i.Log.Infof("IPAM state after startup RESYNC: "+
 		"podNetworks=%+v, excludedIPsfromNodeSubnet=%v, hostInterconnectSubnetAllNodes=%v, "+
 		"hostInterconnectSubnetThisNode=%v, hostInterconnectIPInVpp=%v, hostInterconnectIPInLinux=%v, "+
-		"nodeInterconnectSubnet=%v, vxlanSubnet=%v, serviceCIDR=%v, "+
+		"nodeInterconnectSubnet=%v, vxlanSubnet=%v, serviceCIDR=%v, secondary=%v, "+
 		"assignedPodIPs=%+v, podToIP=%v, remotePodToIP=%+v, extIfToIPNet=%+v",
 		i.podNetworks, i.excludedIPsfromNodeSubnet, i.hostInterconnectSubnetAllNodes,
 		i.hostInterconnectSubnetThisNode, i.hostInterconnectIPInVpp, i.hostInterconnectIPInLinux,
-		i.nodeInterconnectSubnet, i.vxlanSubnet, i.serviceCIDR,
+		i.nodeInterconnectSubnet, i.vxlanSubnet, i.serviceCIDR, i.secondary,
 		i.assignedPodIPs, i.podToIP, i.remotePodToIP, i.extIfToIPNet)
 	return
 }
@@ -435,27 +508,128 @@ func (i *IPAM) initializePodNetwork(kubeStateData controller.KubeStateData, conf
 }
 
 // initializeCustomPodNetwork initializes custom pod network -related variables.
-func (i *IPAM) initializeCustomPodNetwork(name string, subnetCIDR string, subnetOneNodePrefix uint32) (err error) {
+func (i *IPAM) initializeCustomPodNetwork(nw *customnetmodel.CustomNetwork) (err error) {
 	podNw := &podNetworkInfo{}
-	i.podNetworks[name] = podNw
+	i.podNetworks[nw.Name] = podNw
 
-	_, podNw.podSubnetAllNodes, err = net.ParseCIDR(subnetCIDR)
+	_, podNw.podSubnetAllNodes, err = net.ParseCIDR(nw.SubnetCIDR)
 	if err != nil {
-		i.Log.Errorf("unable to parse network %s subnet CIDR: %v: %v", name, subnetCIDR, err)
+		i.Log.Errorf("unable to parse network %s subnet CIDR: %v: %v", nw.Name, nw.SubnetCIDR, err)
 		return err
 	}
-	podNw.podSubnetThisNode, err = dissectSubnetForNode(podNw.podSubnetAllNodes, uint8(subnetOneNodePrefix),
+	podNw.podSubnetThisNode, err = dissectSubnetForNode(podNw.podSubnetAllNodes, uint8(nw.SubnetOneNodePrefix),
 		i.NodeSync.GetNodeID())
+	if err != nil {
+		return err
+	}
+
+	// explicit gateway is used on every node, otherwise the first IP of the node subnet is used
+	if nw.Gateway != "" {
+		podNw.podSubnetGatewayIP = net.ParseIP(nw.Gateway)
+		if podNw.podSubnetGatewayIP == nil || !podNw.podSubnetAllNodes.Contains(podNw.podSubnetGatewayIP) {
+			return fmt.Errorf("gateway %s of the network %s is not a valid IP address from the subnet %s",
+				nw.Gateway, nw.Name, nw.SubnetCIDR)
+		}
+	} else {
+		podNw.podSubnetGatewayIP, err = cidr.Host(podNw.podSubnetThisNode, podGatewaySeqID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, r := range nw.ReservedRanges {
+		reserved := &ipRange{start: net.ParseIP(r.Start), end: net.ParseIP(r.End)}
+		if reserved.start == nil || reserved.end == nil {
+			return fmt.Errorf("invalid reserved range %s-%s of the network %s", r.Start, r.End, nw.Name)
+		}
+		podNw.reservedRanges = append(podNw.reservedRanges, reserved)
+	}
+	podNw.lastPodIPAssigned = 1
+
+	i.Log.Infof("New L3 pod network %s: %v", nw.Name, podNw)
+	return nil
+}
+
+// initializeSecondaryFamily initializes subnets and addresses of the secondary IP family (dual-stack mode).
+func (i *IPAM) initializeSecondaryFamily(ipamConfig *contivconf.IPAMConfig, nodeID uint32) (err error) {
+	subnets := &ipamConfig.SecondarySubnets
+	secondary := &secondaryFamilyInfo{
+		podNetwork: &podNetworkInfo{
+			podSubnetAllNodes: subnets.PodSubnetCIDR,
+		},
+		hostInterconnectSubnetAllNodes: subnets.VPPHostSubnetCIDR,
+		vxlanSubnet:                    subnets.VxlanCIDR,
+		serviceCIDR:                    ipamConfig.SecondaryServiceCIDR,
+	}
+	podNw := secondary.podNetwork
+	podNw.podSubnetThisNode, err = dissectSubnetForNode(
+		podNw.podSubnetAllNodes, subnets.PodSubnetOneNodePrefixLen, nodeID)
+	if err != nil {
+		return err
+	}
 	podNw.podSubnetGatewayIP, err = cidr.Host(podNw.podSubnetThisNode, podGatewaySeqID)
 	if err != nil {
 		return err
 	}
 	podNw.lastPodIPAssigned = 1
 
-	i.Log.Infof("New L3 pod network %s: %v", name, podNw)
+	secondary.hostInterconnectSubnetThisNode, err = dissectSubnetForNode(
+		secondary.hostInterconnectSubnetAllNodes, subnets.VPPHostSubnetOneNodePrefixLen, nodeID)
+	if err != nil {
+		return err
+	}
+	secondary.hostInterconnectIPInVpp, err = cidr.Host(secondary.hostInterconnectSubnetThisNode,
+		hostInterconnectInVPPIPSeqID)
+	if err != nil {
+		return err
+	}
+	secondary.hostInterconnectIPInLinux, err = cidr.Host(secondary.hostInterconnectSubnetThisNode,
+		hostInterconnectInLinuxIPSeqID)
+	if err != nil {
+		return err
+	}
+
+	i.secondary = secondary
+	i.Log.Infof("Dual-stack enabled, secondary IP family: %v", secondary)
 	return nil
 }
 
+// resyncSecondaryPodIP registers secondary IP address of a pod found among the given pod IP addresses.
+func (i *IPAM) resyncSecondaryPodIP(podID podmodel.ID, podIPAddresses []string) {
+	podNw := i.secondary.podNetwork
+	for _, ipAddress := range podIPAddresses {
+		podIPAddress := net.ParseIP(ipAddress)
+		if podIPAddress == nil || !podNw.podSubnetAllNodes.Contains(podIPAddress) {
+			continue
+		}
+		if podNw.podSubnetThisNode.Contains(podIPAddress) { // local pod
+			i.assignedPodIPs[podIPAddress.String()] = &podIPAllocation{
+				pod:    podID,
+				mainIP: true,
+			}
+			if _, found := i.podToIP[podID]; !found {
+				i.podToIP[podID] = &podIPInfo{
+					customIfIPs: map[string]net.IP{},
+				}
+			}
+			i.podToIP[podID].secondaryIP = podIPAddress
+			addr := new(big.Int).SetBytes(podIPAddress)
+			diff := int(addr.Sub(addr, new(big.Int).SetBytes(podNw.podSubnetThisNode.IP)).Int64())
+			if podNw.lastPodIPAssigned < diff {
+				podNw.lastPodIPAssigned = diff
+			}
+		} else { // remote pod
+			if _, found := i.remotePodToIP[podID]; !found {
+				i.remotePodToIP[podID] = &podIPInfo{
+					customIfIPs: map[string]net.IP{},
+				}
+			}
+			i.remotePodToIP[podID].secondaryIP = podIPAddress
+		}
+		return
+	}
+}
+
 // initializeVPPHostNetwork initializes VPP-host interconnect-related variables.
 func (i *IPAM) initializeVPPHostNetwork(config *contivconf.CustomIPAMSubnets, nodeID uint32) (err error) {
 	i.hostInterconnectSubnetAllNodes = config.VPPHostSubnetCIDR
@@ -545,6 +719,9 @@ func (i *IPAM) Update(event controller.Event, txn controller.UpdateOperations) (
 					}
 					// NOTE: ignoring pods outside of default network's pod subnet
 				}
+				if i.secondary != nil {
+					i.resyncSecondaryPodIP(updatedPodID, newPod.IpAddresses)
+				}
 			}
 
 		case extifmodel.Keyword:
@@ -571,7 +748,7 @@ func (i *IPAM) Update(event controller.Event, txn controller.UpdateOperations) (
 			if ksChange.NewValue != nil {
 				nw := ksChange.NewValue.(*customnetmodel.CustomNetwork)
 				if nw.Type == customnetmodel.CustomNetwork_L3 && nw.SubnetCIDR != "" && nw.SubnetOneNodePrefix > 0 {
-					err = i.initializeCustomPodNetwork(nw.Name, nw.SubnetCIDR, nw.SubnetOneNodePrefix)
+					err = i.initializeCustomPodNetwork(nw)
 					if err != nil {
 						return "", err
 					}
@@ -634,12 +811,17 @@ func (i *IPAM) VxlanIPAddress(nodeID uint32) (net.IP, *net.IPNet, error) {
 	i.mutex.RLock()
 	defer i.mutex.RUnlock()
 
-	vxlanIP, err := i.computeVxlanIPAddress(nodeID)
+	return i.vxlanIPAddressFromSubnet(i.vxlanSubnet, nodeID)
+}
+
+// vxlanIPAddressFromSubnet computes IP address of the VXLAN interface from the given VXLAN subnet.
+func (i *IPAM) vxlanIPAddressFromSubnet(vxlanSubnet *net.IPNet, nodeID uint32) (net.IP, *net.IPNet, error) {
+	vxlanIP, err := i.computeVxlanIPAddress(vxlanSubnet, nodeID)
 	if err != nil {
 		return net.IP{}, nil, err
 	}
-	maskSize, _ := i.vxlanSubnet.Mask.Size()
-	mask := net.CIDRMask(maskSize, addrLenFromNet(i.vxlanSubnet))
+	maskSize, _ := vxlanSubnet.Mask.Size()
+	mask := net.CIDRMask(maskSize, addrLenFromNet(vxlanSubnet))
 	vxlanNetwork := &net.IPNet{
 		IP:   newIP(vxlanIP).Mask(mask),
 		Mask: mask,
@@ -785,43 +967,216 @@ func (i *IPAM) ServiceNetwork() *net.IPNet {
 	return newIPNet(i.serviceCIDR)
 }
 
+// DualStackEnabled returns true if pods are allocated one IP address of each IP family.
+func (i *IPAM) DualStackEnabled() bool {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	return i.secondary != nil
+}
+
+// SecondaryPodSubnetAllNodes returns POD subnet of the secondary IP family that is a base subnet
+// for all PODs of all nodes (dual-stack only, nil otherwise).
+func (i *IPAM) SecondaryPodSubnetAllNodes() *net.IPNet {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	if i.secondary == nil {
+		return nil
+	}
+	return newIPNet(i.secondary.podNetwork.podSubnetAllNodes)
+}
+
+// SecondaryPodSubnetThisNode returns POD network of the secondary IP family for the current node
+// (dual-stack only, nil otherwise).
+func (i *IPAM) SecondaryPodSubnetThisNode() *net.IPNet {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	if i.secondary == nil {
+		return nil
+	}
+	return newIPNet(i.secondary.podNetwork.podSubnetThisNode)
+}
+
+// SecondaryPodSubnetOtherNode returns the POD network of the secondary IP family of another node
+// identified by nodeID (dual-stack only).
+func (i *IPAM) SecondaryPodSubnetOtherNode(nodeID uint32) (*net.IPNet, error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	if i.secondary == nil {
+		return nil, errors.New("dual-stack is not enabled")
+	}
+	podNw := i.secondary.podNetwork
+	oneNodePrefixLen, _ := podNw.podSubnetThisNode.Mask.Size()
+	podSubnetOtherNode, err := dissectSubnetForNode(
+		podNw.podSubnetAllNodes, uint8(oneNodePrefixLen), nodeID)
+	if err != nil {
+		return nil, err
+	}
+	return newIPNet(podSubnetOtherNode), nil
+}
+
+// SecondaryPodGatewayIP returns gateway IP address of the secondary POD subnet of this node
+// (dual-stack only, nil otherwise).
+func (i *IPAM) SecondaryPodGatewayIP() net.IP {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	if i.secondary == nil {
+		return nil
+	}
+	return newIP(i.secondary.podNetwork.podSubnetGatewayIP)
+}
+
+// SecondaryServiceNetwork returns range allocated for services of the secondary IP family
+// (dual-stack only, nil otherwise).
+func (i *IPAM) SecondaryServiceNetwork() *net.IPNet {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	if i.secondary == nil {
+		return nil
+	}
+	return newIPNet(i.secondary.serviceCIDR)
+}
+
+// SecondaryHostInterconnectIPInVPP provides the IP address of the secondary IP family for the VPP-end
+// of the VPP-to-host interconnect (dual-stack only, nil otherwise).
+func (i *IPAM) SecondaryHostInterconnectIPInVPP() net.IP {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	if i.secondary == nil {
+		return nil
+	}
+	return newIP(i.secondary.hostInterconnectIPInVpp)
+}
+
+// SecondaryHostInterconnectIPInLinux provides the IP address of the secondary IP family for the
+// host(Linux)-end of the VPP-to-host interconnect (dual-stack only, nil otherwise).
+func (i *IPAM) SecondaryHostInterconnectIPInLinux() net.IP {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	if i.secondary == nil {
+		return nil
+	}
+	return newIP(i.secondary.hostInterconnectIPInLinux)
+}
+
+// SecondaryHostInterconnectSubnetThisNode returns vswitch network of the secondary IP family used
+// to connect VPP to its host Linux Stack on this node (dual-stack only, nil otherwise).
+func (i *IPAM) SecondaryHostInterconnectSubnetThisNode() *net.IPNet {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	if i.secondary == nil {
+		return nil
+	}
+	return newIPNet(i.secondary.hostInterconnectSubnetThisNode)
+}
+
+// SecondaryHostInterconnectSubnetAllNodes returns vswitch base subnet of the secondary IP family
+// used to connect VPP to its host Linux Stack on all nodes (dual-stack only, nil otherwise).
+func (i *IPAM) SecondaryHostInterconnectSubnetAllNodes() *net.IPNet {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	if i.secondary == nil {
+		return nil
+	}
+	return newIPNet(i.secondary.hostInterconnectSubnetAllNodes)
+}
+
+// SecondaryHostInterconnectSubnetOtherNode returns VPP-host network of the secondary IP family
+// of another node identified by nodeID (dual-stack only).
+func (i *IPAM) SecondaryHostInterconnectSubnetOtherNode(nodeID uint32) (*net.IPNet, error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	if i.secondary == nil {
+		return nil, errors.New("dual-stack is not enabled")
+	}
+	oneNodePrefixLen, _ := i.secondary.hostInterconnectSubnetThisNode.Mask.Size()
+	hostSubnetOtherNode, err := dissectSubnetForNode(
+		i.secondary.hostInterconnectSubnetAllNodes, uint8(oneNodePrefixLen), nodeID)
+	if err != nil {
+		return nil, err
+	}
+	return newIPNet(hostSubnetOtherNode), nil
+}
+
+// SecondaryVxlanIPAddress computes IP address of the secondary IP family of the VXLAN interface
+// based on the provided node ID (dual-stack only).
+func (i *IPAM) SecondaryVxlanIPAddress(nodeID uint32) (net.IP, *net.IPNet, error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	if i.secondary == nil || i.secondary.vxlanSubnet == nil {
+		return net.IP{}, nil, errors.New("dual-stack VXLAN subnet is not defined")
+	}
+	return i.vxlanIPAddressFromSubnet(i.secondary.vxlanSubnet, nodeID)
+}
+
 // AllocatePodIP tries to allocate IP address for the given pod.
-func (i *IPAM) AllocatePodIP(podID podmodel.ID, ipamType string, ipamData string) (net.IP, error) {
+// In the dual-stack mode, secondaryIP is allocated from the secondary IP family, otherwise it is nil.
+func (i *IPAM) AllocatePodIP(podID podmodel.ID, ipamType string, ipamData string) (
+	ip net.IP, secondaryIP net.IP, err error) {
 	i.mutex.Lock()
 	defer i.mutex.Unlock()
 
 	if i.ContivConf.GetIPAMConfig().UseExternalIPAM {
 		// allocate IP using external IPAM
-		return i.allocateExternalPodIP(podID, ipamType, ipamData)
+		ip, err = i.allocateExternalPodIP(podID, ipamType, ipamData)
+		return ip, nil, err
 	}
 
 	// check whether IP is already allocated
 	allocation, found := i.podToIP[podID]
-	if found && allocation.mainIP != nil {
-		return allocation.mainIP, nil
+	if found && allocation.mainIP != nil && (i.secondary == nil || allocation.secondaryIP != nil) {
+		return allocation.mainIP, allocation.secondaryIP, nil
+	}
+	if !found {
+		allocation = &podIPInfo{
+			customIfIPs: map[string]net.IP{},
+		}
+		i.podToIP[podID] = allocation
 	}
 
 	// allocate an IP
-	ip, err := i.allocateIP(i.podNetworks[defaultPodNetworkName])
-	if err != nil {
-		i.Log.Errorf("Unable to allocate main pod IP: %v", err)
-		return nil, err
+	var newMainIP bool
+	if allocation.mainIP == nil {
+		ip, err = i.allocateIP(i.podNetworks[defaultPodNetworkName])
+		if err != nil {
+			i.Log.Errorf("Unable to allocate main pod IP: %v", err)
+			if !found {
+				delete(i.podToIP, podID)
+			}
+			return nil, nil, err
+		}
+		// store the allocation internally
+		i.assignedPodIPs[ip.String()] = &podIPAllocation{
+			pod:    podID,
+			mainIP: true,
+		}
+		allocation.mainIP = ip
+		newMainIP = true
 	}
 
-	// store the allocation internally
-	i.assignedPodIPs[ip.String()] = &podIPAllocation{
-		pod:    podID,
-		mainIP: true,
-	}
-	if _, found := i.podToIP[podID]; !found {
-		i.podToIP[podID] = &podIPInfo{
-			customIfIPs: map[string]net.IP{},
+	// allocate an IP from the secondary IP family
+	if i.secondary != nil && allocation.secondaryIP == nil {
+		secondaryIP, err = i.allocateIP(i.secondary.podNetwork)
+		if err != nil {
+			i.Log.Errorf("Unable to allocate secondary pod IP: %v", err)
+			// roll back the main IP allocated above
+			if newMainIP {
+				delete(i.assignedPodIPs, allocation.mainIP.String())
+				allocation.mainIP = nil
+			}
+			if !found {
+				delete(i.podToIP, podID)
+			}
+			return nil, nil, err
+		}
+		i.assignedPodIPs[secondaryIP.String()] = &podIPAllocation{
+			pod:    podID,
+			mainIP: true,
 		}
+		allocation.secondaryIP = secondaryIP
 	}
-	i.podToIP[podID].mainIP = ip
 	i.logAssignedPodIPPool()
 
-	return ip, nil
+	return allocation.mainIP, allocation.secondaryIP, nil
 }
 
 // AllocatePodCustomIfIP tries to allocate custom IP address for the given interface of a given pod.
@@ -979,7 +1334,7 @@ func (i *IPAM) allocateIP(podNw *podNetworkInfo) (net.IP, error) {
 	}
 	maxSeqID := (1 << podBitSize) - 2
 	for j := last; j < maxSeqID; j++ {
-		ipForAssign, success := i.tryToAllocateIP(j, podNw.podSubnetThisNode)
+		ipForAssign, success := i.tryToAllocateIP(j, podNw)
 		if success {
 			podNw.lastPodIPAssigned = j
 			return ipForAssign, nil
@@ -988,7 +1343,7 @@ func (i *IPAM) allocateIP(podNw *podNetworkInfo) (net.IP, error) {
 
 	// iterate from the range start until lastPodIPAssigned
 	for j := 1; j < last; j++ { // zero ending IP is reserved for network => skip seqID=0
-		ipForAssign, success := i.tryToAllocateIP(j, podNw.podSubnetThisNode)
+		ipForAssign, success := i.tryToAllocateIP(j, podNw)
 		if success {
 			podNw.lastPodIPAssigned = j
 			return ipForAssign, nil
@@ -999,14 +1354,17 @@ func (i *IPAM) allocateIP(podNw *podNetworkInfo) (net.IP, error) {
 }
 
 // tryToAllocatePodIP checks whether the IP at the given index is available.
-func (i *IPAM) tryToAllocateIP(index int, networkPrefix *net.IPNet) (assignedIP net.IP, success bool) {
-	if index == podGatewaySeqID {
-		return nil, false // gateway IP address can't be assigned as pod
-	}
-	ip, err := cidr.Host(networkPrefix, index)
+func (i *IPAM) tryToAllocateIP(index int, podNw *podNetworkInfo) (assignedIP net.IP, success bool) {
+	ip, err := cidr.Host(podNw.podSubnetThisNode, index)
 	if err != nil {
 		return nil, false
 	}
+	if ip.Equal(podNw.podSubnetGatewayIP) {
+		return nil, false // gateway IP address can't be assigned as pod
+	}
+	if podNw.isReserved(ip) {
+		return nil, false // reserved IP addresses are managed outside of IPAM
+	}
 	if _, found := i.assignedPodIPs[ip.String()]; found {
 		return nil, false // ignore already assigned IP addresses
 	}
@@ -1031,6 +1389,29 @@ func (i *IPAM) GetPodIP(podID podmodel.ID) *net.IPNet {
 	return nil
 }
 
+// GetPodIPs returns all allocated IPs of the main pod interface, together with the mask.
+// In the dual-stack mode it returns the IP of the primary IP family followed by the IP of the secondary
+// family, otherwise it returns the same IP as GetPodIP.
+// Searches for both local and remote pods. Returns nil if the pod does not have allocated IP address.
+func (i *IPAM) GetPodIPs(podID podmodel.ID) (ips []*net.IPNet) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	allocation, found := i.getPodIPInfo(podID)
+	if !found {
+		return nil
+	}
+	if allocation.mainIP != nil {
+		addrLen := addrLenFromNet(i.podNetworks[defaultPodNetworkName].podSubnetAllNodes)
+		ips = append(ips, &net.IPNet{IP: allocation.mainIP, Mask: net.CIDRMask(addrLen, addrLen)})
+	}
+	if i.secondary != nil && allocation.secondaryIP != nil {
+		addrLen := addrLenFromNet(i.secondary.podNetwork.podSubnetAllNodes)
+		ips = append(ips, &net.IPNet{IP: allocation.secondaryIP, Mask: net.CIDRMask(addrLen, addrLen)})
+	}
+	return ips
+}
+
 // GetExternalInterfaceIP returns the allocated external interface IP.
 // Returns nil if the interface does not have allocated IP address.
 func (i *IPAM) GetExternalInterfaceIP(vppInterface string, nodeID uint32) *net.IPNet {
@@ -1098,6 +1479,10 @@ func (i *IPAM) ReleasePodIPs(podID podmodel.ID) error {
 	}
 
 	delete(i.assignedPodIPs, allocation.mainIP.String())
+	if allocation.secondaryIP != nil {
+		i.Log.Infof("Released secondary IP %v for pod ID %v", allocation.secondaryIP, podID)
+		delete(i.assignedPodIPs, allocation.secondaryIP.String())
+	}
 	for _, ip := range allocation.customIfIPs {
 		i.Log.Infof("Released custom interface IP %v for pod ID %v", ip, podID)
 		delete(i.assignedPodIPs, ip.String())
@@ -1144,6 +1529,22 @@ func (i *IPAM) GetIPAMConfigForJSON() *config.IPAMConfig {
 	s, _ = i.HostInterconnectSubnetThisNode().Mask.Size()
 	res.VPPHostSubnetOneNodePrefixLen = uint8(s)
 
+	if i.secondary != nil {
+		res.DualStack = config.DualStack{
+			Enabled:           true,
+			ServiceCIDR:       i.secondary.serviceCIDR.String(),
+			PodSubnetCIDR:     i.secondary.podNetwork.podSubnetAllNodes.String(),
+			VPPHostSubnetCIDR: i.secondary.hostInterconnectSubnetAllNodes.String(),
+		}
+		if i.secondary.vxlanSubnet != nil {
+			res.DualStack.VxlanCIDR = i.secondary.vxlanSubnet.String()
+		}
+		s, _ = i.secondary.podNetwork.podSubnetThisNode.Mask.Size()
+		res.DualStack.PodSubnetOneNodePrefixLen = uint8(s)
+		s, _ = i.secondary.hostInterconnectSubnetThisNode.Mask.Size()
+		res.DualStack.VPPHostSubnetOneNodePrefixLen = uint8(s)
+	}
+
 	return res
 }
 
@@ -1409,10 +1810,10 @@ func (i *IPAM) computeNodeIPAddress(nodeID uint32) (net.IP, error) {
 }
 
 // computeVxlanIPAddress computes IP address of the VXLAN interface based on the given node ID.
-func (i *IPAM) computeVxlanIPAddress(nodeID uint32) (net.IP, error) {
-	addrLen := addrLenFromNet(i.vxlanSubnet)
+func (i *IPAM) computeVxlanIPAddress(vxlanSubnet *net.IPNet, nodeID uint32) (net.IP, error) {
+	addrLen := addrLenFromNet(vxlanSubnet)
 
-	subnetPrefixLen, _ := i.vxlanSubnet.Mask.Size()
+	subnetPrefixLen, _ := vxlanSubnet.Mask.Size()
 	nodePartBitSize := addrLen - subnetPrefixLen
 	nodeIPPart, err := convertToNodeIPPart(nodeID, uint8(nodePartBitSize))
 	if err != nil {
@@ -1424,7 +1825,7 @@ func (i *IPAM) computeVxlanIPAddress(nodeID uint32) (net.IP, error) {
 	}
 
 	// combining it to get result IP address
-	computedIP, err := cidr.Host(i.vxlanSubnet, int(nodeIPPart))
+	computedIP, err := cidr.Host(vxlanSubnet, int(nodeIPPart))
 	if err != nil {
 		return nil, err
 	}
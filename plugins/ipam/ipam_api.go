@@ -77,13 +77,67 @@ type API interface {
 	// ServiceNetwork returns range allocated for services.
 	ServiceNetwork() *net.IPNet
 
+	// DualStackEnabled returns true if pods are allocated one IP address of each IP family.
+	DualStackEnabled() bool
+
+	// SecondaryPodSubnetAllNodes returns POD subnet of the secondary IP family that is a base subnet
+	// for all PODs of all nodes (dual-stack only, nil otherwise).
+	SecondaryPodSubnetAllNodes() *net.IPNet
+
+	// SecondaryPodSubnetThisNode returns POD network of the secondary IP family for the current node
+	// (dual-stack only, nil otherwise).
+	SecondaryPodSubnetThisNode() *net.IPNet
+
+	// SecondaryPodSubnetOtherNode returns the POD network of the secondary IP family of another node
+	// identified by nodeID (dual-stack only).
+	SecondaryPodSubnetOtherNode(nodeID uint32) (*net.IPNet, error)
+
+	// SecondaryPodGatewayIP returns gateway IP address of the secondary POD subnet of this node
+	// (dual-stack only, nil otherwise).
+	SecondaryPodGatewayIP() net.IP
+
+	// SecondaryServiceNetwork returns range allocated for services of the secondary IP family
+	// (dual-stack only, nil otherwise).
+	SecondaryServiceNetwork() *net.IPNet
+
+	// SecondaryHostInterconnectIPInVPP provides the IP address of the secondary IP family for the VPP-end
+	// of the VPP-to-host interconnect (dual-stack only, nil otherwise).
+	SecondaryHostInterconnectIPInVPP() net.IP
+
+	// SecondaryHostInterconnectIPInLinux provides the IP address of the secondary IP family for the
+	// host(Linux)-end of the VPP-to-host interconnect (dual-stack only, nil otherwise).
+	SecondaryHostInterconnectIPInLinux() net.IP
+
+	// SecondaryHostInterconnectSubnetThisNode returns vswitch network of the secondary IP family used
+	// to connect VPP to its host Linux Stack on this node (dual-stack only, nil otherwise).
+	SecondaryHostInterconnectSubnetThisNode() *net.IPNet
+
+	// SecondaryHostInterconnectSubnetAllNodes returns vswitch base subnet of the secondary IP family
+	// used to connect VPP to its host Linux Stack on all nodes (dual-stack only, nil otherwise).
+	SecondaryHostInterconnectSubnetAllNodes() *net.IPNet
+
+	// SecondaryHostInterconnectSubnetOtherNode returns VPP-host network of the secondary IP family
+	// of another node identified by nodeID (dual-stack only).
+	SecondaryHostInterconnectSubnetOtherNode(nodeID uint32) (*net.IPNet, error)
+
+	// SecondaryVxlanIPAddress computes IP address of the secondary IP family of the VXLAN interface
+	// based on the provided node ID (dual-stack only).
+	SecondaryVxlanIPAddress(nodeID uint32) (net.IP, *net.IPNet, error)
+
 	// AllocatePodIP tries to allocate IP address for the given pod.
-	AllocatePodIP(podID podmodel.ID, ipamType string, ipamData string) (net.IP, error)
+	// In the dual-stack mode, secondaryIP is allocated from the secondary IP family, otherwise it is nil.
+	AllocatePodIP(podID podmodel.ID, ipamType string, ipamData string) (ip net.IP, secondaryIP net.IP, err error)
 
 	// GetPodIP returns the allocated (main) pod IP, together with the mask. Searches for
 	// both local and remote pods. Returns nil if the pod does not have allocated IP address.
 	GetPodIP(podID podmodel.ID) *net.IPNet
 
+	// GetPodIPs returns all allocated IPs of the main pod interface, together with the mask.
+	// In the dual-stack mode it returns the IP of the primary IP family followed by the IP of the secondary
+	// family, otherwise it returns the same IP as GetPodIP.
+	// Searches for both local and remote pods. Returns nil if the pod does not have allocated IP address.
+	GetPodIPs(podID podmodel.ID) []*net.IPNet
+
 	// GetExternalInterfaceIP returns the allocated external interface IP.
 	// Returns nil if the interface does not have allocated IP address.
 	GetExternalInterfaceIP(vppInterface string, nodeID uint32) *net.IPNet
@@ -34,6 +34,7 @@ import (
 
 	"github.com/contiv/vpp/plugins/contivconf"
 	"github.com/contiv/vpp/plugins/contivconf/config"
+	customnetmodel "github.com/contiv/vpp/plugins/crd/handler/customnetwork/model"
 	nodeconfigcrd "github.com/contiv/vpp/plugins/crd/pkg/apis/nodeconfig/v1"
 	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
 	"github.com/contiv/vpp/plugins/nodesync"
@@ -270,7 +271,7 @@ func TestDynamicGettersIPv6(t *testing.T) {
 // TestBasicAllocateReleasePodAddress test simple happy path scenario for getting 1 pod address and releasing it
 func TestBasicAllocateReleasePodAddress(t *testing.T) {
 	i := setup(t, newDefaultConfig())
-	ip, err := i.AllocatePodIP(podID[0], "", "")
+	ip, _, err := i.AllocatePodIP(podID[0], "", "")
 	Expect(err).To(BeNil())
 	Expect(ip).NotTo(BeNil())
 	Expect(i.PodSubnetThisNode(defaultPodNetworkName).Contains(ip)).To(BeTrue(),
@@ -289,7 +290,7 @@ func TestBasicAllocateReleasePodAddressIPv6(t *testing.T) {
 	i := setup(t, customConfig)
 	Expect(i).NotTo(BeNil())
 
-	ip, err := i.AllocatePodIP(podID[0], "", "")
+	ip, _, err := i.AllocatePodIP(podID[0], "", "")
 	Expect(err).To(BeNil())
 	Expect(ip).NotTo(BeNil())
 	Expect(i.PodSubnetThisNode(defaultPodNetworkName).Contains(ip)).To(BeTrue(),
@@ -299,6 +300,102 @@ func TestBasicAllocateReleasePodAddressIPv6(t *testing.T) {
 	Expect(err).To(BeNil())
 }
 
+// TestAllocateReleasePodAddressDualStack tests allocation of one pod address of each IP family
+func TestAllocateReleasePodAddressDualStack(t *testing.T) {
+	customConfig := newDefaultConfig()
+	customConfig.IPAMConfig.DualStack = config.DualStack{
+		Enabled:                       true,
+		ServiceCIDR:                   "fd00:96::/112",
+		PodSubnetCIDR:                 "fe10:f00d::/90",
+		PodSubnetOneNodePrefixLen:     120,
+		VPPHostSubnetCIDR:             "fe20:f00d::/90",
+		VPPHostSubnetOneNodePrefixLen: 120,
+		VxlanCIDR:                     "fe30:f00d::/90",
+	}
+
+	i := setup(t, customConfig)
+	Expect(i).NotTo(BeNil())
+	Expect(i.DualStackEnabled()).To(BeTrue())
+	Expect(i.SecondaryServiceNetwork().String()).To(BeEquivalentTo("fd00:96::/112"))
+	Expect(i.SecondaryPodSubnetThisNode().String()).To(BeEquivalentTo("fe10:f00d::100/120"))
+	Expect(i.SecondaryPodGatewayIP().String()).To(BeEquivalentTo("fe10:f00d::101"))
+	Expect(i.SecondaryHostInterconnectIPInVPP().String()).To(BeEquivalentTo("fe20:f00d::101"))
+	Expect(i.SecondaryHostInterconnectIPInLinux().String()).To(BeEquivalentTo("fe20:f00d::102"))
+
+	ip, secondaryIP, err := i.AllocatePodIP(podID[0], "", "")
+	Expect(err).To(BeNil())
+	Expect(i.PodSubnetThisNode(defaultPodNetworkName).Contains(ip)).To(BeTrue(),
+		"Pod IP address is not from pod network")
+	Expect(i.SecondaryPodSubnetThisNode().Contains(secondaryIP)).To(BeTrue(),
+		"Secondary pod IP address is not from secondary pod network")
+
+	// repeated allocation returns the same addresses
+	repeated, repeatedSecondary, err := i.AllocatePodIP(podID[0], "", "")
+	Expect(err).To(BeNil())
+	Expect(repeated).To(BeEquivalentTo(ip))
+	Expect(repeatedSecondary).To(BeEquivalentTo(secondaryIP))
+
+	podIPs := i.GetPodIPs(podID[0])
+	Expect(podIPs).To(HaveLen(2))
+	Expect(podIPs[0].IP).To(BeEquivalentTo(ip))
+	Expect(podIPs[1].IP).To(BeEquivalentTo(secondaryIP))
+	foundPodID, found := i.GetPodFromIP(secondaryIP)
+	Expect(found).To(BeTrue())
+	Expect(foundPodID).To(BeEquivalentTo(podID[0]))
+
+	err = i.ReleasePodIPs(podID[0])
+	Expect(err).To(BeNil())
+	_, found = i.GetPodFromIP(secondaryIP)
+	Expect(found).To(BeFalse())
+}
+
+// TestAllocatePodAddressDualStackRollback tests that the main pod IP is released
+// when the secondary pod IP cannot be allocated
+func TestAllocatePodAddressDualStackRollback(t *testing.T) {
+	customConfig := newDefaultConfig()
+	customConfig.IPAMConfig.PodSubnetOneNodePrefixLen = 28 // 12 free IP addresses
+	customConfig.IPAMConfig.DualStack = config.DualStack{
+		Enabled:                       true,
+		ServiceCIDR:                   "fd00:96::/112",
+		PodSubnetCIDR:                 "fe10:f00d::/90",
+		PodSubnetOneNodePrefixLen:     125, // 4 free IP addresses
+		VPPHostSubnetCIDR:             "fe20:f00d::/90",
+		VPPHostSubnetOneNodePrefixLen: 120,
+		VxlanCIDR:                     "fe30:f00d::/90",
+	}
+
+	i := setup(t, customConfig)
+	Expect(i).NotTo(BeNil())
+
+	// exhaust the secondary pod subnet
+	for j := 0; j < 4; j++ {
+		_, secondaryIP, err := i.AllocatePodIP(podID[j], "", "")
+		Expect(err).To(BeNil())
+		Expect(secondaryIP).NotTo(BeNil())
+	}
+	Expect(i.assignedPodIPs).To(HaveLen(8))
+
+	// main IP is rolled back if the secondary IP cannot be allocated
+	extraPod := podmodel.ID{Namespace: "default", Name: "pod5"}
+	ip, secondaryIP, err := i.AllocatePodIP(extraPod, "", "")
+	Expect(err).NotTo(BeNil())
+	Expect(ip).To(BeNil())
+	Expect(secondaryIP).To(BeNil())
+	Expect(i.assignedPodIPs).To(HaveLen(8))
+	Expect(i.GetPodIPs(extraPod)).To(BeEmpty())
+
+	// allocation succeeds once a secondary IP is released
+	err = i.ReleasePodIPs(podID[0])
+	Expect(err).To(BeNil())
+	ip, secondaryIP, err = i.AllocatePodIP(extraPod, "", "")
+	Expect(err).To(BeNil())
+	Expect(ip).NotTo(BeNil())
+	Expect(secondaryIP).NotTo(BeNil())
+	foundPodID, found := i.GetPodFromIP(ip)
+	Expect(found).To(BeTrue())
+	Expect(foundPodID).To(BeEquivalentTo(extraPod))
+}
+
 // TestWideIPv6PodSubenet verifies pod IP allocation in cases
 // where more than 64 bits is reserved for pod Subnet on a node
 func TestWideIPv6PodSubenet(t *testing.T) {
@@ -309,7 +406,7 @@ func TestWideIPv6PodSubenet(t *testing.T) {
 	i := setup(t, customConfig)
 	Expect(i).NotTo(BeNil())
 
-	ip, err := i.AllocatePodIP(podID[0], "", "")
+	ip, _, err := i.AllocatePodIP(podID[0], "", "")
 	Expect(err).To(BeNil())
 	Expect(ip).NotTo(BeNil())
 	Expect(i.PodSubnetThisNode(defaultPodNetworkName).Contains(ip)).To(BeTrue(),
@@ -322,13 +419,13 @@ func TestWideIPv6PodSubenet(t *testing.T) {
 // TestAlreadyAllocated tests that repeated allocation for a given podID returns the same IP
 func TestAlreadyAllocatedAddress(t *testing.T) {
 	i := setup(t, newDefaultConfig())
-	ip, err := i.AllocatePodIP(podID[0], "", "")
+	ip, _, err := i.AllocatePodIP(podID[0], "", "")
 	Expect(err).To(BeNil())
 	Expect(ip).NotTo(BeNil())
 	Expect(i.PodSubnetThisNode(defaultPodNetworkName).Contains(ip)).To(BeTrue(),
 		"Pod IP address is not from pod network")
 
-	repeated, err := i.AllocatePodIP(podID[0], "", "")
+	repeated, _, err := i.AllocatePodIP(podID[0], "", "")
 	Expect(err).To(BeNil())
 	Expect(repeated).NotTo(BeNil())
 	Expect(bytes.Compare(repeated, ip)).To(BeZero())
@@ -340,14 +437,14 @@ func TestAlreadyAllocatedAddress(t *testing.T) {
 // TestAssigniningIncrementalIPs test whether released IPs are reused only once all the range is exhausted
 func TestAssigniningIncrementalIPs(t *testing.T) {
 	i := setup(t, newDefaultConfig())
-	ip, err := i.AllocatePodIP(podID[0], "", "")
+	ip, _, err := i.AllocatePodIP(podID[0], "", "")
 	Expect(err).To(BeNil())
 	Expect(ip).NotTo(BeNil())
 	Expect(ip.String()).To(BeEquivalentTo("1.2.128.10"))
 	Expect(i.PodSubnetThisNode(defaultPodNetworkName).Contains(ip)).To(BeTrue(),
 		"Pod IP address is not from pod network")
 
-	second, err := i.AllocatePodIP(podID[1], "", "")
+	second, _, err := i.AllocatePodIP(podID[1], "", "")
 	Expect(err).To(BeNil())
 	Expect(second).NotTo(BeNil())
 	Expect(second.String()).To(BeEquivalentTo("1.2.128.11"))
@@ -358,7 +455,7 @@ func TestAssigniningIncrementalIPs(t *testing.T) {
 	Expect(err).To(BeNil())
 
 	// check that second is not reused
-	third, err := i.AllocatePodIP(podID[2], "", "")
+	third, _, err := i.AllocatePodIP(podID[2], "", "")
 	Expect(err).To(BeNil())
 	Expect(third).NotTo(BeNil())
 	Expect(third.String()).To(BeEquivalentTo("1.2.128.12"))
@@ -366,14 +463,14 @@ func TestAssigniningIncrementalIPs(t *testing.T) {
 		"Pod IP address is not from pod network")
 
 	// exhaust the range
-	assigned, err := i.AllocatePodIP(podID[3], "", "")
+	assigned, _, err := i.AllocatePodIP(podID[3], "", "")
 	Expect(err).To(BeNil())
 	Expect(assigned).NotTo(BeNil())
 	Expect(i.PodSubnetThisNode(defaultPodNetworkName).Contains(assigned)).To(BeTrue(),
 		"Pod IP address is not from pod network")
 
 	// expect released IP to be reused
-	reused, err := i.AllocatePodIP(podID[1], "", "")
+	reused, _, err := i.AllocatePodIP(podID[1], "", "")
 	Expect(err).To(BeNil())
 	Expect(reused).NotTo(BeNil())
 	Expect(i.PodSubnetThisNode(defaultPodNetworkName).Contains(reused)).To(BeTrue(),
@@ -644,10 +741,56 @@ func TestExcludeGateway(t *testing.T) {
 
 }
 
+func TestCustomNetworkGatewayAndReservedRanges(t *testing.T) {
+	i := setup(t, newDefaultConfig())
+
+	// explicit (anycast) gateway outside of the node subnet + reserved range
+	err := i.initializeCustomPodNetwork(&customnetmodel.CustomNetwork{
+		Name:                "net1",
+		Type:                customnetmodel.CustomNetwork_L3,
+		SubnetCIDR:          "10.100.0.0/16",
+		SubnetOneNodePrefix: 24,
+		Gateway:             "10.100.0.1",
+		ReservedRanges: []*customnetmodel.CustomNetwork_IPRange{
+			{Start: "10.100.1.2", End: "10.100.1.4"},
+		},
+	})
+	Expect(err).To(BeNil())
+	Expect(*i.PodSubnetThisNode("net1")).To(BeEquivalentTo(network("10.100.1.0/24")))
+	Expect(i.PodGatewayIP("net1").String()).To(Equal("10.100.0.1"))
+
+	ip, err := i.allocateIP(i.podNetworks["net1"])
+	Expect(err).To(BeNil())
+	Expect(ip.String()).To(Equal("10.100.1.5"))
+
+	// IPv6 network with the gateway inside the node subnet
+	err = i.initializeCustomPodNetwork(&customnetmodel.CustomNetwork{
+		Name:                "net2",
+		Type:                customnetmodel.CustomNetwork_L3,
+		SubnetCIDR:          "fd00:100::/56",
+		SubnetOneNodePrefix: 64,
+		Gateway:             "fd00:100:0:1::2",
+	})
+	Expect(err).To(BeNil())
+	ip, err = i.allocateIP(i.podNetworks["net2"])
+	Expect(err).To(BeNil())
+	Expect(ip.String()).To(Equal("fd00:100:0:1::3"))
+
+	// gateway outside of the network subnet
+	err = i.initializeCustomPodNetwork(&customnetmodel.CustomNetwork{
+		Name:                "net3",
+		Type:                customnetmodel.CustomNetwork_L3,
+		SubnetCIDR:          "10.200.0.0/16",
+		SubnetOneNodePrefix: 24,
+		Gateway:             "10.100.0.1",
+	})
+	Expect(err).NotTo(BeNil())
+}
+
 func exhaustPodIPAddresses(i *IPAM, maxIPCount int) (allocatedIPs []string, allocatedPodIDS []podmodel.ID) {
 	for j := 1; j <= maxIPCount; j++ {
 		podID := podmodel.ID{Namespace: "default", Name: "pod" + strconv.Itoa(j)}
-		ip, _ := i.AllocatePodIP(podID, "", "")
+		ip, _, _ := i.AllocatePodIP(podID, "", "")
 		allocatedIPs = append(allocatedIPs, ip.To4().String())
 		allocatedPodIDS = append(allocatedPodIDS, podID)
 	}
@@ -669,7 +812,7 @@ func releaseAllPodAddresses(i *IPAM, ipCount int) {
 
 func assertCorrectIPExhaustion(i *IPAM, maxIPCount int) {
 	podID := podmodel.ID{Namespace: "default", Name: "pod" + strconv.Itoa(maxIPCount+1)}
-	_, err := i.AllocatePodIP(podID, "", "")
+	_, _, err := i.AllocatePodIP(podID, "", "")
 	Expect(err).NotTo(BeNil(), "Pool of free IP addresses should be empty, but IPAM allocation function didn't fail")
 }
 
@@ -677,7 +820,7 @@ func assertAllocationOfIPAddresses(i *IPAM, expectedIPs []string, network net.IP
 	freeIPsCount := len(expectedIPs)
 	for j := 1; j <= freeIPsCount; j++ {
 		podID := podmodel.ID{Namespace: "default", Name: "pod" + strconv.Itoa(j) + "-secondAllocation"}
-		ip, err := i.AllocatePodIP(podID, "", "")
+		ip, _, err := i.AllocatePodIP(podID, "", "")
 		Expect(err).To(BeNil(), "Can't successfully allocate %v. IP address", j)
 		assertAllocationOfIPAddress(ip, network)
 		Expect(expectedIPs).To(ContainElement(ip.String()), "Allocated IP is not from given IP slice")
@@ -688,7 +831,7 @@ func assertAllocationOfAllIPAddresses(i *IPAM, maxIPCount int, network net.IPNet
 	allocated := make(map[string]bool, maxIPCount)
 	for j := 1; j <= maxIPCount; j++ {
 		podID := podmodel.ID{Namespace: "default", Name: "pod" + strconv.Itoa(j)}
-		ip, err := i.AllocatePodIP(podID, "", "")
+		ip, _, err := i.AllocatePodIP(podID, "", "")
 		Expect(err).To(BeNil(),
 			"Can't successfully allocate %v. IP address out of %v possible IP addresses", j, maxIPCount)
 		Expect(allocated[ip.String()]).To(BeFalse(), "IP address %v is allocated second time", ip)
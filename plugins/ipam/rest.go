@@ -42,6 +42,7 @@ func (i *IPAM) ipamGetHandler(formatter *render.Render) http.HandlerFunc {
 			allocations.Pods = append(allocations.Pods, restapi.PodIPAllocation{
 				PodID:       k,
 				MainIP:      v.mainIP,
+				SecondaryIP: v.secondaryIP,
 				CustomIfIPs: v.customIfIPs,
 			})
 		}
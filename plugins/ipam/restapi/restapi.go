@@ -31,6 +31,7 @@ const (
 type PodIPAllocation struct {
 	PodID       pod.ID
 	MainIP      net.IP
+	SecondaryIP net.IP `json:",omitempty"`
 	CustomIfIPs map[string]net.IP
 }
 
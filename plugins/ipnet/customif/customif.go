@@ -0,0 +1,108 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customif
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// Annotation is the k8s pod annotation used to request custom pod interfaces.
+	Annotation = "contivpp.io/custom-if"
+
+	// Separator is used to split multiple interfaces in the annotation value.
+	Separator = ","
+
+	// MemifType is the type of custom memif interface.
+	MemifType = "memif"
+	// TapType is the type of custom TAP interface.
+	TapType = "tap"
+	// VethType is the type of custom veth interface.
+	VethType = "veth"
+
+	// DefaultNetwork is the network the custom interface is connected to
+	// if the network is not specified in the annotation.
+	DefaultNetwork = "default"
+
+	// StubNetwork is a special network name dedicated to "stub" custom
+	// interfaces - not connected to any VRF nor bridge domain.
+	StubNetwork = "stub"
+)
+
+// Interface holds information about a custom pod interface.
+type Interface struct {
+	Name    string
+	Type    string
+	Network string
+}
+
+// String returns the interface definition as used in the annotation.
+func (i *Interface) String() string {
+	return i.Name + "/" + i.Type + "/" + i.Network
+}
+
+// HasAnnotation returns true if provided annotations contain the custom-if annotation, false otherwise.
+func HasAnnotation(annotations map[string]string) bool {
+	for k := range annotations {
+		if strings.HasPrefix(k, Annotation) {
+			return true
+		}
+	}
+	return false
+}
+
+// FromAnnotations returns alphabetically ordered slice of custom interfaces defined in pod annotations.
+func FromAnnotations(annotations map[string]string) []string {
+	out := make([]string, 0)
+
+	for k, v := range annotations {
+		if strings.HasPrefix(k, Annotation) {
+			ifs := strings.Split(v, Separator)
+			for _, i := range ifs {
+				out = append(out, strings.TrimSpace(i))
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Parse parses custom interface definition (<name>/<type>[/<network>]) into individual parts.
+func Parse(ifDefinition string) (iface *Interface, err error) {
+	ifParts := strings.Split(ifDefinition, "/")
+	if len(ifParts) < 2 {
+		err = fmt.Errorf("invalid %s annotation value: %s", Annotation, ifDefinition)
+		return
+	}
+
+	iface = &Interface{
+		Name: ifParts[0],
+		Type: ifParts[1],
+	}
+
+	if len(ifParts) > 2 {
+		iface.Network = ifParts[2]
+	} else {
+		iface.Network = DefaultNetwork
+	}
+	return
+}
+
+// IsSupportedType returns true if the given custom interface type is supported.
+func IsSupportedType(ifType string) bool {
+	return ifType == MemifType || ifType == TapType || ifType == VethType
+}
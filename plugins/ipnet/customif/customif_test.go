@@ -0,0 +1,53 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customif
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestFromAnnotations(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(HasAnnotation(map[string]string{"contivpp.io/microservice-label": "vnf1"})).To(BeFalse())
+	Expect(FromAnnotations(nil)).To(BeEmpty())
+
+	annotations := map[string]string{
+		"contivpp.io/custom-if":          "memif2/memif/net1, memif1/memif",
+		"contivpp.io/microservice-label": "vnf1",
+	}
+	Expect(HasAnnotation(annotations)).To(BeTrue())
+	Expect(FromAnnotations(annotations)).To(Equal([]string{"memif1/memif", "memif2/memif/net1"}))
+}
+
+func TestParse(t *testing.T) {
+	RegisterTestingT(t)
+
+	iface, err := Parse("tap1/tap")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(iface).To(Equal(&Interface{Name: "tap1", Type: TapType, Network: DefaultNetwork}))
+
+	iface, err = Parse("memif1/memif/net1")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(iface).To(Equal(&Interface{Name: "memif1", Type: MemifType, Network: "net1"}))
+	Expect(iface.String()).To(Equal("memif1/memif/net1"))
+	Expect(IsSupportedType(iface.Type)).To(BeTrue())
+	Expect(IsSupportedType("vhost")).To(BeFalse())
+
+	_, err = Parse("memif1")
+	Expect(err).To(HaveOccurred())
+}
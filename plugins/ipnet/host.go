@@ -20,6 +20,8 @@ import (
 
 	"github.com/apparentlymart/go-cidr/cidr"
 
+	controller "github.com/contiv/vpp/plugins/controller/api"
+
 	"go.ligato.io/vpp-agent/v3/pkg/models"
 	"go.ligato.io/vpp-agent/v3/proto/ligato/linux/interfaces"
 	"go.ligato.io/vpp-agent/v3/proto/ligato/linux/l3"
@@ -123,6 +125,10 @@ func (n *IPNet) interconnectTapVPP() (key string, config *vpp_interfaces.Interfa
 		}
 	} else {
 		tap.IpAddresses = []string{n.IPAM.HostInterconnectIPInVPP().String() + "/" + strconv.Itoa(size)}
+		if n.IPAM.DualStackEnabled() {
+			tap.IpAddresses = append(tap.IpAddresses, ipNetToString(combineAddrWithNet(
+				n.IPAM.SecondaryHostInterconnectIPInVPP(), n.IPAM.SecondaryHostInterconnectSubnetThisNode())))
+		}
 	}
 	if interfaceCfg.TAPInterfaceVersion == 2 {
 		tap.GetTap().Version = 2
@@ -173,6 +179,10 @@ func (n *IPNet) interconnectTapHost() (key string, config *linux_interfaces.Inte
 		}
 	} else {
 		tap.IpAddresses = []string{n.IPAM.HostInterconnectIPInLinux().String() + "/" + strconv.Itoa(size)}
+		if n.IPAM.DualStackEnabled() {
+			tap.IpAddresses = append(tap.IpAddresses, ipNetToString(combineAddrWithNet(
+				n.IPAM.SecondaryHostInterconnectIPInLinux(), n.IPAM.SecondaryHostInterconnectSubnetThisNode())))
+		}
 	}
 	key = linux_interfaces.InterfaceKey(tap.Name)
 	return key, tap
@@ -202,6 +212,10 @@ func (n *IPNet) interconnectAfpacket() (key string, config *vpp_interfaces.Inter
 		}
 	} else {
 		afpacket.IpAddresses = []string{n.IPAM.HostInterconnectIPInVPP().String() + "/" + strconv.Itoa(size)}
+		if n.IPAM.DualStackEnabled() {
+			afpacket.IpAddresses = append(afpacket.IpAddresses, ipNetToString(combineAddrWithNet(
+				n.IPAM.SecondaryHostInterconnectIPInVPP(), n.IPAM.SecondaryHostInterconnectSubnetThisNode())))
+		}
 	}
 	if interfaceRxModeType(interfaceCfg.InterfaceRxMode) != vpp_interfaces.Interface_RxMode_DEFAULT {
 		afpacket.RxModes = []*vpp_interfaces.Interface_RxMode{
@@ -263,6 +277,10 @@ func (n *IPNet) interconnectVethHost() (key string, config *linux_interfaces.Int
 		}
 	} else {
 		veth.IpAddresses = []string{n.IPAM.HostInterconnectIPInLinux().String() + "/" + strconv.Itoa(size)}
+		if n.IPAM.DualStackEnabled() {
+			veth.IpAddresses = append(veth.IpAddresses, ipNetToString(combineAddrWithNet(
+				n.IPAM.SecondaryHostInterconnectIPInLinux(), n.IPAM.SecondaryHostInterconnectSubnetThisNode())))
+		}
 	}
 	if interfaceCfg.TCPChecksumOffloadDisabled {
 		veth.GetVeth().RxChecksumOffloading = linux_interfaces.VethLink_CHKSM_OFFLOAD_DISABLED
@@ -278,9 +296,17 @@ func (n *IPNet) routesToHost(nextHopIP net.IP) map[string]*vpp_l3.Route {
 
 	// generate a /32 (or /128 for ipv6) static route from VPP for each of the host's IPs
 	for _, ip := range n.hostIPs {
+		routeNextHop := nextHopIP
+		if isIPv6(ip) != isIPv6(nextHopIP) {
+			// dual-stack: route via host-end of the interconnect of the secondary IP family
+			if !n.IPAM.DualStackEnabled() || n.ContivConf.InSTNMode() {
+				continue
+			}
+			routeNextHop = n.IPAM.SecondaryHostInterconnectIPInLinux()
+		}
 		route := &vpp_l3.Route{
 			DstNetwork:        ip.String() + hostPrefixForAF(ip),
-			NextHopAddr:       nextHopIP.String(),
+			NextHopAddr:       routeNextHop.String(),
 			OutgoingInterface: n.hostInterconnectVPPIfName(),
 			VrfId:             n.ContivConf.GetRoutingConfig().MainVRFID,
 		}
@@ -294,26 +320,39 @@ func (n *IPNet) routesToHost(nextHopIP net.IP) map[string]*vpp_l3.Route {
 // routePODsFromHost returns configuration for route for the host stack to direct
 // traffic destined to pods via VPP.
 func (n *IPNet) routePODsFromHost(nextHopIP net.IP) (key string, config *linux_l3.Route) {
-	route := &linux_l3.Route{
-		OutgoingInterface: hostInterconnectVETH1LogicalName,
-		Scope:             linux_l3.Route_GLOBAL,
-		DstNetwork:        n.IPAM.PodSubnetAllNodes(DefaultPodNetworkName).String(),
-		GwAddr:            nextHopIP.String(),
-	}
-	if n.ContivConf.GetInterfaceConfig().UseTAPInterfaces {
-		route.OutgoingInterface = HostInterconnectTAPinLinuxLogicalName
-	}
-	key = models.Key(route)
-	return key, route
+	return n.routeFromHostToVPP(n.IPAM.PodSubnetAllNodes(DefaultPodNetworkName), nextHopIP)
 }
 
 // routeServicesFromHost returns configuration for route for the host stack to direct
 // traffic destined to services via VPP.
 func (n *IPNet) routeServicesFromHost(nextHopIP net.IP) (key string, config *linux_l3.Route) {
+	return n.routeFromHostToVPP(n.IPAM.ServiceNetwork(), nextHopIP)
+}
+
+// secondaryRoutesFromHost returns configuration for routes for the host stack to direct
+// traffic destined to pods and services of the secondary IP family via VPP (dual-stack only).
+func (n *IPNet) secondaryRoutesFromHost() (config controller.KeyValuePairs) {
+	config = make(controller.KeyValuePairs)
+	if !n.IPAM.DualStackEnabled() || n.ContivConf.InSTNMode() {
+		return config
+	}
+	nextHopIP := n.IPAM.SecondaryHostInterconnectIPInVPP()
+	key, route := n.routeFromHostToVPP(n.IPAM.SecondaryPodSubnetAllNodes(), nextHopIP)
+	config[key] = route
+	if n.ContivConf.GetRoutingConfig().RouteServiceCIDRToVPP {
+		key, route = n.routeFromHostToVPP(n.IPAM.SecondaryServiceNetwork(), nextHopIP)
+		config[key] = route
+	}
+	return config
+}
+
+// routeFromHostToVPP returns configuration for route for the host stack to direct
+// traffic destined to the given network via VPP.
+func (n *IPNet) routeFromHostToVPP(dstNetwork *net.IPNet, nextHopIP net.IP) (key string, config *linux_l3.Route) {
 	route := &linux_l3.Route{
 		OutgoingInterface: hostInterconnectVETH1LogicalName,
 		Scope:             linux_l3.Route_GLOBAL,
-		DstNetwork:        n.IPAM.ServiceNetwork().String(),
+		DstNetwork:        dstNetwork.String(),
 		GwAddr:            nextHopIP.String(),
 	}
 	if n.ContivConf.GetInterfaceConfig().UseTAPInterfaces {
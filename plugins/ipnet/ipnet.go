@@ -418,7 +418,8 @@ func (n *IPNet) GetVxlanBVIIfName() string {
 }
 
 // GetOrAllocateVxlanVNI returns the allocated VXLAN VNI number for the given network.
-// Allocates a new VNI if not already allocated.
+// Allocates a new VNI if not already allocated. The VNI pinned in the custom network
+// definition takes precedence over the automatic allocation.
 func (n *IPNet) GetOrAllocateVxlanVNI(networkName string) (vni uint32, err error) {
 
 	// allocate the pool if needed
@@ -434,6 +435,16 @@ func (n *IPNet) GetOrAllocateVxlanVNI(networkName string) (vni uint32, err error
 		n.vniPoolInitialized = true
 	}
 
+	// VNI pinned for the custom network
+	if nw := n.customNetworks[networkName]; nw != nil && nw.config != nil && nw.config.Vni != 0 {
+		err = n.IDAlloc.AllocateID(VxlanVniPoolName, networkName, nw.config.Vni)
+		if err != nil {
+			n.Log.Errorf("Pinned VNI %d of the network %s is not available: %v", nw.config.Vni, networkName, err)
+			return 0, err
+		}
+		return nw.config.Vni, nil
+	}
+
 	// get / allocate a VNI
 	vni, err = n.IDAlloc.GetOrAllocateID(VxlanVniPoolName, networkName)
 	if err != nil {
@@ -448,7 +459,8 @@ func (n *IPNet) ReleaseVxlanVNI(networkName string) (err error) {
 }
 
 // GetOrAllocateVrfID returns the allocated VRF ID number for the given network.
-// Allocates a new VRF ID if not already allocated.
+// Allocates a new VRF ID if not already allocated. The VRF ID pinned in the custom network
+// definition takes precedence over the automatic allocation.
 func (n *IPNet) GetOrAllocateVrfID(networkName string) (vrf uint32, err error) {
 	// default pod network does not need any allocation
 	if n.isDefaultPodNetwork(networkName) {
@@ -468,6 +480,23 @@ func (n *IPNet) GetOrAllocateVrfID(networkName string) (vrf uint32, err error) {
 		n.vrfPoolInitialized = true
 	}
 
+	// VRF pinned for the custom network
+	if nw := n.customNetworks[networkName]; nw != nil && nw.config != nil && nw.config.Vrf != 0 {
+		routingCfg := n.ContivConf.GetRoutingConfig()
+		if nw.config.Vrf == routingCfg.MainVRFID || nw.config.Vrf == routingCfg.PodVRFID {
+			err = fmt.Errorf("pinned VRF %d of the network %s collides with the main or pod VRF",
+				nw.config.Vrf, networkName)
+			n.Log.Error(err)
+			return 0, err
+		}
+		err = n.IDAlloc.AllocateID(vrfPoolName, networkName, nw.config.Vrf)
+		if err != nil {
+			n.Log.Errorf("Pinned VRF %d of the network %s is not available: %v", nw.config.Vrf, networkName, err)
+			return 0, err
+		}
+		return nw.config.Vrf, nil
+	}
+
 	// get / allocate a VRF
 	vrf, err = n.IDAlloc.GetOrAllocateID(vrfPoolName, networkName)
 	if err != nil {
@@ -31,7 +31,9 @@ import (
 	"go.ligato.io/cn-infra/v2/logging"
 	"go.ligato.io/cn-infra/v2/logging/logrus"
 
+	"go.ligato.io/vpp-agent/v3/pkg/models"
 	scheduler "go.ligato.io/vpp-agent/v3/plugins/kvscheduler/api"
+	linux_interfaces "go.ligato.io/vpp-agent/v3/proto/ligato/linux/interfaces"
 	vpp_interfaces "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/interfaces"
 	vpp_l3 "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/l3"
 	vpp_srv6 "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/srv6"
@@ -196,6 +198,78 @@ var (
 		},
 	}
 
+	dualStackVxlanConfig = &config.Config{
+		InterfaceConfig: config.InterfaceConfig{
+			UseTAPInterfaces:    true,
+			TAPInterfaceVersion: 2,
+		},
+		RoutingConfig: config.RoutingConfig{
+			NodeToNodeTransport:   "vxlan",
+			RouteServiceCIDRToVPP: true,
+		},
+		IPAMConfig: config.IPAMConfig{
+			NodeInterconnectCIDR:          "192.168.16.0/24",
+			PodSubnetCIDR:                 "10.1.0.0/16",
+			PodSubnetOneNodePrefixLen:     24,
+			VPPHostSubnetCIDR:             "172.30.0.0/16",
+			VPPHostSubnetOneNodePrefixLen: 24,
+			VxlanCIDR:                     "192.168.30.0/24",
+			ServiceCIDR:                   "10.96.0.0/12",
+			DualStack: config.DualStack{
+				Enabled:                       true,
+				ServiceCIDR:                   "fd00:96::/112",
+				PodSubnetCIDR:                 "fe10:f00d::/90",
+				PodSubnetOneNodePrefixLen:     120,
+				VPPHostSubnetCIDR:             "fe20:f00d::/90",
+				VPPHostSubnetOneNodePrefixLen: 120,
+				VxlanCIDR:                     "fe30:f00d::/90",
+			},
+		},
+		NodeConfig: []config.NodeConfig{
+			noDHCPNodeConfig,
+		},
+	}
+
+	dualStackSrv6Config = &config.Config{
+		InterfaceConfig: config.InterfaceConfig{
+			UseTAPInterfaces:    true,
+			TAPInterfaceVersion: 2,
+		},
+		RoutingConfig: config.RoutingConfig{
+			NodeToNodeTransport: "srv6",
+		},
+		IPAMConfig: config.IPAMConfig{
+			NodeInterconnectCIDR:          "e10:f00d::/90",
+			PodSubnetCIDR:                 "10.1.0.0/16",
+			PodSubnetOneNodePrefixLen:     24,
+			VPPHostSubnetCIDR:             "172.30.0.0/16",
+			VPPHostSubnetOneNodePrefixLen: 24,
+			VxlanCIDR:                     "192.168.30.0/24",
+			ServiceCIDR:                   "10.96.0.0/12",
+			DualStack: config.DualStack{
+				Enabled:                       true,
+				ServiceCIDR:                   "fd00:96::/112",
+				PodSubnetCIDR:                 "fe10:f00d::/90",
+				PodSubnetOneNodePrefixLen:     120,
+				VPPHostSubnetCIDR:             "fe20:f00d::/90",
+				VPPHostSubnetOneNodePrefixLen: 120,
+			},
+			SRv6: config.SRv6Config{
+				ServicePolicyBSIDSubnetCIDR:       "8fff::/16",
+				ServicePodLocalSIDSubnetCIDR:      "9300::/16",
+				ServiceHostLocalSIDSubnetCIDR:     "9300::/16",
+				ServiceNodeLocalSIDSubnetCIDR:     "9000::/16",
+				NodeToNodePodLocalSIDSubnetCIDR:   "9501::/16",
+				NodeToNodeHostLocalSIDSubnetCIDR:  "9500::/16",
+				NodeToNodePodPolicySIDSubnetCIDR:  "8501::/16",
+				NodeToNodeHostPolicySIDSubnetCIDR: "8500::/16",
+			},
+		},
+		NodeConfig: []config.NodeConfig{
+			noDHCPNodeConfig,
+		},
+	}
+
 	/*
 		configVethL2NoTCP = &contivconf.Config{
 			RoutingConfig: contivconf.RoutingConfig{
@@ -571,6 +645,149 @@ func TestDeletePodToPodDX6Tunnel(t *testing.T) {
 	assertIngress(false, expectedTunnelSetup.ingress, fixture.Srv6Handler, fixture.RouteHandler)
 }
 
+func TestDualStackVxlan(t *testing.T) {
+	RegisterTestingT(t)
+	fixture, plugin := newTestingFixtureWithConfig("TestDualStackVxlan", dualStackVxlanConfig)
+	emptyK8SResync(fixture.TxnTracker, fixture.Ipam, fixture.ContivConf, fixture.Fixture, plugin)
+	Expect(fixture.Ipam.DualStackEnabled()).To(BeTrue())
+	routingCfg := fixture.ContivConf.GetRoutingConfig()
+
+	// pod VRF of both IP families
+	Expect(plugin.vrfTablesForPods()).To(HaveLen(2))
+	Expect(plugin.vrfMainTables()).To(HaveLen(2))
+
+	// pod gateway and VXLAN BVI with addresses of both IP families
+	_, podGw := plugin.podGwLoopback(DefaultPodNetworkName, routingCfg.PodVRFID)
+	Expect(podGw.IpAddresses).To(HaveLen(2))
+	Expect(podGw.IpAddresses[1]).To(Equal("fe10:f00d::101/120"))
+	_, bvi, err := plugin.vxlanBVILoopback(DefaultPodNetworkName, routingCfg.PodVRFID)
+	Expect(err).ShouldNot(HaveOccurred())
+	secondaryVxlanIP, secondaryVxlanNet, err := fixture.Ipam.SecondaryVxlanIPAddress(node1ID)
+	Expect(err).ShouldNot(HaveOccurred())
+	Expect(bvi.IpAddresses).To(HaveLen(2))
+	Expect(bvi.IpAddresses[1]).To(Equal(combineAddrWithNet(secondaryVxlanIP, secondaryVxlanNet).String()))
+
+	// routes between pod and main VRF for the secondary IP family
+	podToMain := &vpp_l3.Route{
+		Type:        vpp_l3.Route_INTER_VRF,
+		DstNetwork:  "::/0",
+		VrfId:       routingCfg.PodVRFID,
+		ViaVrfId:    routingCfg.MainVRFID,
+		NextHopAddr: "::",
+	}
+	Expect(plugin.routesPodToMainVRF()).To(HaveKey(models.Key(podToMain)))
+	mainToPod := plugin.routesMainToPodVRF()
+	for _, dstNetwork := range []string{"fe10:f00d::/90", "fe20:f00d::/90", "fd00:96::/112"} {
+		route := &vpp_l3.Route{
+			Type:        vpp_l3.Route_INTER_VRF,
+			DstNetwork:  dstNetwork,
+			VrfId:       routingCfg.MainVRFID,
+			ViaVrfId:    routingCfg.PodVRFID,
+			NextHopAddr: "::",
+		}
+		Expect(mainToPod).To(HaveKey(models.Key(route)))
+	}
+
+	// host interconnect with addresses of both IP families
+	_, vppTap := plugin.interconnectTapVPP()
+	Expect(vppTap.IpAddresses).To(HaveLen(2))
+	Expect(vppTap.IpAddresses[1]).To(Equal("fe20:f00d::101/120"))
+	_, hostTap := plugin.interconnectTapHost()
+	Expect(hostTap.IpAddresses).To(HaveLen(2))
+	Expect(hostTap.IpAddresses[1]).To(Equal("fe20:f00d::102/120"))
+	hostRoutes := plugin.secondaryRoutesFromHost()
+	Expect(hostRoutes).To(HaveLen(2))
+	for _, dstNetwork := range []*net.IPNet{fixture.Ipam.SecondaryPodSubnetAllNodes(), fixture.Ipam.SecondaryServiceNetwork()} {
+		key, _ := plugin.routeFromHostToVPP(dstNetwork, net.ParseIP("fe20:f00d::101"))
+		Expect(hostRoutes).To(HaveKey(key))
+	}
+
+	// other node reachable via its VXLAN BVI address of the secondary IP family
+	addr, network, mgmt := addOtherNode(fixture.TxnTracker, fixture.Ipam, fixture.Fixture, plugin, node2ID, node2Name, node2MgmtIP)
+	node2 := &nodesync.Node{
+		Name:            node2Name,
+		ID:              node2ID,
+		VppIPAddresses:  contivconf.IPsWithNetworks{{Address: addr, Network: network}},
+		MgmtIPAddresses: []net.IP{mgmt},
+	}
+	node2VxlanIP, _, err := fixture.Ipam.SecondaryVxlanIPAddress(node2ID)
+	Expect(err).ShouldNot(HaveOccurred())
+	node2PodNet, err := fixture.Ipam.SecondaryPodSubnetOtherNode(node2ID)
+	Expect(err).ShouldNot(HaveOccurred())
+	node2HostNet, err := fixture.Ipam.SecondaryHostInterconnectSubnetOtherNode(node2ID)
+	Expect(err).ShouldNot(HaveOccurred())
+	node2Config, err := plugin.otherNodeConnectivityConfig(node2)
+	Expect(err).ShouldNot(HaveOccurred())
+	key, _ := plugin.routeToOtherNodeNetworks(DefaultPodNetworkName, node2PodNet, node2VxlanIP)
+	Expect(node2Config).To(HaveKey(key))
+	key, _ = plugin.routeToOtherNodeNetworks(DefaultPodNetworkName, node2HostNet, node2VxlanIP)
+	Expect(node2Config).To(HaveKey(key))
+	key, _ = plugin.vxlanArpEntry(DefaultPodNetworkName, node2ID, node2VxlanIP)
+	Expect(node2Config).To(HaveKey(key))
+
+	// pod with addresses of both IP families
+	pod := addLocalPod(fixture.TxnTracker, fixture.Fixture, plugin, pod1Name, pod1Namespace, pod1Container, pod1Ns)
+	podIPs := fixture.Ipam.GetPodIPs(pod.ID)
+	Expect(podIPs).To(HaveLen(2))
+	Expect(fixture.Ipam.SecondaryPodSubnetThisNode().Contains(podIPs[1].IP)).To(BeTrue())
+	podConfig := plugin.podConnectivityConfig(pod)
+	key, _ = plugin.vppToPodRoute(pod, podIPs[1], "", "", routingCfg.PodVRFID)
+	Expect(podConfig).To(HaveKey(key))
+	key, _ = plugin.podToVPPSecondaryDefaultRoute(pod)
+	Expect(podConfig).To(HaveKey(key))
+	key, _ = plugin.podToVPPSecondaryArpEntry(pod)
+	Expect(podConfig).To(HaveKey(key))
+	_, linuxTap := plugin.podLinuxTAP(pod, fixture.Ipam.GetPodIP(pod.ID), "", false)
+	Expect(podConfig[linux_interfaces.InterfaceKey(linuxTap.Name)].(*linux_interfaces.Interface).IpAddresses).
+		To(ContainElement(podIPs[1].String()))
+
+	deleteLocalPod(fixture.TxnTracker, fixture.Fixture, plugin, pod.ID)
+	deleteOtherNode(fixture.TxnTracker, fixture.Fixture, plugin, node2Name)
+}
+
+func TestDualStackSRv6(t *testing.T) {
+	RegisterTestingT(t)
+	fixture, plugin := newTestingFixtureWithConfig("TestDualStackSRv6", dualStackSrv6Config)
+	emptyK8SResync(fixture.TxnTracker, fixture.Ipam, fixture.ContivConf, fixture.Fixture, plugin)
+	routingCfg := fixture.ContivConf.GetRoutingConfig()
+
+	// localsids decapsulating secondary (IPv6) traffic into pod and main VRF
+	localsids := plugin.srv6SecondaryTunnelEgress()
+	Expect(localsids).To(HaveLen(2))
+	podSid := fixture.Ipam.SidForNodeToNodePodLocalsid(fixture.Ipam.SecondaryPodSubnetThisNode().IP)
+	hostSid := fixture.Ipam.SidForNodeToNodeHostLocalsid(fixture.Ipam.SecondaryHostInterconnectSubnetThisNode().IP)
+	key, podLocalsid := plugin.srv6TunnelEgress(podSid, routingCfg.PodVRFID, true)
+	Expect(localsids).To(HaveKey(key))
+	Expect(podLocalsid.GetEndFunctionDt6()).ToNot(BeNil())
+	key, _ = plugin.srv6TunnelEgress(hostSid, routingCfg.MainVRFID, true)
+	Expect(localsids).To(HaveKey(key))
+	for _, localsid := range localsids {
+		assertEgress(true, localsid.(*vpp_srv6.LocalSID), fixture.Srv6Handler)
+	}
+
+	// SRv6 tunnels towards the secondary networks of the other node
+	addr, network, _ := addOtherNode(fixture.TxnTracker, fixture.Ipam, fixture.Fixture, plugin, node2ID, node2Name, node2MgmtIP)
+	node2 := &nodesync.Node{
+		Name:           node2Name,
+		ID:             node2ID,
+		VppIPAddresses: contivconf.IPsWithNetworks{{Address: addr, Network: network}},
+	}
+	node2PodNet, err := fixture.Ipam.SecondaryPodSubnetOtherNode(node2ID)
+	Expect(err).ShouldNot(HaveOccurred())
+	node2HostNet, err := fixture.Ipam.SecondaryHostInterconnectSubnetOtherNode(node2ID)
+	Expect(err).ShouldNot(HaveOccurred())
+	secondaryConfig, err := plugin.secondaryConnectivityToOtherNode(node2, addr)
+	Expect(err).ShouldNot(HaveOccurred())
+	podSteering := getSteering(node2PodNet, fixture.Ipam.BsidForNodeToNodePodPolicy(node2PodNet.IP),
+		"lookupInPodVRF", routingCfg.MainVRFID)
+	Expect(secondaryConfig).To(HaveKey(models.Key(podSteering)))
+	hostSteering := getSteering(node2HostNet, fixture.Ipam.BsidForNodeToNodeHostPolicy(node2HostNet.IP),
+		"lookupInMainVRF", routingCfg.MainVRFID)
+	Expect(secondaryConfig).To(HaveKey(models.Key(hostSteering)))
+
+	deleteOtherNode(fixture.TxnTracker, fixture.Fixture, plugin, node2Name)
+}
+
 func (fixture *TunnelTestingFixture) ApplyTxn(txn *localclient.Txn, latestRevs *syncbase.PrevRevisions) error {
 	err := fixture.Srv6Handler.ApplyTxn(txn, latestRevs)
 	Expect(err).ShouldNot(HaveOccurred())
@@ -625,6 +842,17 @@ const (
 )
 
 func newTunnelTestingFixture(testName string, ipVer uint8, endFunction int) (*TunnelTestingFixture, *IPNet) {
+	if ipVer == 4 {
+		return newTestingFixtureWithConfig(testName, ipVer4Srv6NodeToNodeConfig)
+	}
+	if endFunction == DX6 {
+		ipVer6TunnelTestingContivConf.UseDX6ForSrv6NodetoNodeTransport = true
+	}
+	return newTestingFixtureWithConfig(testName, ipVer6TunnelTestingContivConf)
+}
+
+// newTestingFixtureWithConfig inits real ContivConf, IPAM and IPNet plugins for the given configuration.
+func newTestingFixtureWithConfig(testName string, contivConfig *config.Config) (*TunnelTestingFixture, *IPNet) {
 
 	fixture := newCommonFixture(testName)
 
@@ -643,22 +871,10 @@ func newTunnelTestingFixture(testName string, ipVer uint8, endFunction int) (*Tu
 			},
 			ServiceLabel: fixture.ServiceLabel,
 			UnitTestDeps: &contivconf.UnitTestDeps{
-				Config: ipVer4Srv6NodeToNodeConfig,
+				Config: contivConfig,
 			},
 		},
 	}
-	if ipVer == 4 {
-		data.ContivConf.UnitTestDeps = &contivconf.UnitTestDeps{
-			Config: ipVer4Srv6NodeToNodeConfig,
-		}
-	} else {
-		data.ContivConf.UnitTestDeps = &contivconf.UnitTestDeps{
-			Config: ipVer6TunnelTestingContivConf,
-		}
-		if endFunction == DX6 {
-			data.ContivConf.UnitTestDeps.Config.UseDX6ForSrv6NodetoNodeTransport = true
-		}
-	}
 
 	Expect(data.ContivConf.Init()).To(BeNil())
 	resyncEv, _ := data.Datasync.ResyncEvent()
@@ -732,7 +948,7 @@ func addRemotePod(fixture *TunnelTestingFixture, plugin *IPNet, podName string,
 	fmt.Println("Add remote pod --------------------------------------------------")
 
 	podID := k8sPod.ID{Name: podName, Namespace: podNamespace}
-	podIP, _ := fixture.Ipam.AllocatePodIP(podID, "", "")
+	podIP, _, _ := fixture.Ipam.AllocatePodIP(podID, "", "")
 	pod := &podmanager.Pod{
 		ID:        podID,
 		IPAddress: podIP.String(),
@@ -112,16 +112,20 @@ func (n *IPNet) otherNodeConnectivityConfig(node *nodesync.Node) (config control
 
 	// VXLANs for custom networks
 	for _, nw := range n.customNetworks {
+		if nw.config == nil {
+			// network not defined yet (only referenced by interfaces)
+			continue
+		}
 		// get the VNI of the VXLAN
 		vni, err := n.GetOrAllocateVxlanVNI(nw.config.Name)
 		if err != nil {
 			return config, err
 		}
-		if nw.config != nil && nw.config.Type == customnetmodel.CustomNetwork_L2 {
+		if nw.config.Type == customnetmodel.CustomNetwork_L2 {
 			vxlanCfg := n.vxlanToOtherNodeConfig(node, nw.config.Name, vni)
 			mergeConfiguration(config, vxlanCfg)
 		}
-		if nw.config != nil && nw.config.Type == customnetmodel.CustomNetwork_L3 &&
+		if nw.config.Type == customnetmodel.CustomNetwork_L3 &&
 			n.ContivConf.GetRoutingConfig().NodeToNodeTransport == contivconf.VXLANTransport {
 			vxlanCfg := n.vxlanToOtherNodeConfig(node, nw.config.Name, vni)
 			mergeConfiguration(config, vxlanCfg)
@@ -147,7 +151,12 @@ func (n *IPNet) otherNodeConnectivityConfig(node *nodesync.Node) (config control
 	// routes to pods in L3 custom networks
 	for _, nw := range n.customNetworks {
 		if nw.config != nil && nw.config.Type == customnetmodel.CustomNetwork_L3 {
-			podsCfg, err := n.connectivityToOtherNodePods(nw.config.Name, node.ID, nextHop)
+			nwNextHop, err := n.customNetworkNextHopIP(nw.config.Name, node, nextHop)
+			if err != nil {
+				n.Log.Error(err)
+				return config, err
+			}
+			podsCfg, err := n.connectivityToOtherNodePods(nw.config.Name, node.ID, nwNextHop)
 			if err != nil {
 				n.Log.Error(err)
 				return config, err
@@ -164,6 +173,16 @@ func (n *IPNet) otherNodeConnectivityConfig(node *nodesync.Node) (config control
 	}
 	mergeConfiguration(config, hostStackCfg)
 
+	// routes to pods and the host stack of the other node for the secondary IP family (dual-stack)
+	if n.IPAM.DualStackEnabled() {
+		secondaryCfg, err := n.secondaryConnectivityToOtherNode(node, nextHop)
+		if err != nil {
+			n.Log.Error(err)
+			return config, err
+		}
+		mergeConfiguration(config, secondaryCfg)
+	}
+
 	// route to management IPs of the other node
 	mgmIPConf, err := n.connectivityToOtherNodeManagementIPAddresses(node, nextHop)
 	if err != nil {
@@ -200,6 +219,17 @@ func (n *IPNet) vxlanToOtherNodeConfig(node *nodesync.Node, network string, vni
 	key, vxlanArp := n.vxlanArpEntry(network, node.ID, vxlanIP)
 	config[key] = vxlanArp
 
+	// ARP entry for the IP address of the secondary IP family on the opposite side (dual-stack)
+	if n.isDefaultPodNetwork(network) && n.IPAM.DualStackEnabled() {
+		secondaryVxlanIP, _, err := n.IPAM.SecondaryVxlanIPAddress(node.ID)
+		if err != nil {
+			n.Log.Error(err)
+			return config
+		}
+		key, vxlanArp := n.vxlanArpEntry(network, node.ID, secondaryVxlanIP)
+		config[key] = vxlanArp
+	}
+
 	// L2 FIB for the hardware address on the opposite side
 	key, vxlanFib := n.vxlanFibEntry(network, node.ID)
 	config[key] = vxlanFib
@@ -235,6 +265,38 @@ func (n *IPNet) otherNodeNextHopIP(node *nodesync.Node) (nextHop net.IP, err err
 	return nextHop, err
 }
 
+// customNetworkNextHopIP returns next hop address for routes towards pods of the given L3 custom
+// network on the other node. The nextHop is the next hop used for the primary IP family.
+// Custom networks from the secondary IP family (dual-stack only) are routed via the addresses
+// of the other node from the secondary IP family.
+func (n *IPNet) customNetworkNextHopIP(network string, node *nodesync.Node, nextHop net.IP) (net.IP, error) {
+	if !n.isSecondaryFamilyNetwork(network) {
+		return nextHop, nil
+	}
+	if !n.IPAM.DualStackEnabled() {
+		return nil, fmt.Errorf("subnet of the network %s is not from the IP family of the cluster "+
+			"(dual-stack is required)", network)
+	}
+
+	switch n.ContivConf.GetRoutingConfig().NodeToNodeTransport {
+	case contivconf.NoOverlayTransport:
+		// route directly via VPP IP address of the other node from the secondary IP family
+		for _, vppIP := range node.VppIPAddresses {
+			if isIPv6(vppIP.Address) != n.ContivConf.GetIPAMConfig().UseIPv6 {
+				return vppIP.Address, nil
+			}
+		}
+		return nil, fmt.Errorf("node %s has no VPP IP address of the secondary IP family "+
+			"required by the network %s", node.Name, network)
+	case contivconf.VXLANTransport:
+		// route via the VXLAN BVI IP address of the other node from the secondary IP family
+		secondaryNextHop, _, err := n.IPAM.SecondaryVxlanIPAddress(node.ID)
+		return secondaryNextHop, err
+	}
+	// SRv6: nextHop is used as the underlay next hop
+	return nextHop, nil
+}
+
 /*********************************** DHCP *************************************/
 
 var (
@@ -484,6 +546,7 @@ func (n *IPNet) vrfMainTables() map[string]*vpp_l3.VrfTable {
 	k, v := n.vrfTable(routingCfg.MainVRFID, vpp_l3.VrfTable_IPV4, "mainVRF")
 	tables[k] = v
 	if n.ContivConf.GetIPAMConfig().UseIPv6 ||
+		n.ContivConf.GetIPAMConfig().DualStack ||
 		n.ContivConf.GetRoutingConfig().UseSRv6ForServices ||
 		n.ContivConf.GetRoutingConfig().NodeToNodeTransport == contivconf.SRv6Transport {
 		k, v := n.vrfTable(routingCfg.MainVRFID, vpp_l3.VrfTable_IPV6, "mainVRF")
@@ -498,11 +561,12 @@ func (n *IPNet) vrfTablesForPods() map[string]*vpp_l3.VrfTable {
 	tables := make(map[string]*vpp_l3.VrfTable)
 	routingCfg := n.ContivConf.GetRoutingConfig()
 
-	if !n.ContivConf.GetIPAMConfig().UseIPv6 {
+	if !n.ContivConf.GetIPAMConfig().UseIPv6 || n.ContivConf.GetIPAMConfig().DualStack {
 		k, v := n.vrfTable(routingCfg.PodVRFID, vpp_l3.VrfTable_IPV4, "podVRF")
 		tables[k] = v
 	}
 	if n.ContivConf.GetIPAMConfig().UseIPv6 ||
+		n.ContivConf.GetIPAMConfig().DualStack ||
 		n.ContivConf.GetRoutingConfig().UseSRv6ForServices {
 		k, v := n.vrfTable(routingCfg.PodVRFID, vpp_l3.VrfTable_IPV6, "podVRF")
 		tables[k] = v
@@ -561,6 +625,31 @@ func (n *IPNet) routesPodToMainVRF() map[string]*vpp_l3.Route {
 		routes[r2Key] = r2
 	}
 
+	if n.IPAM.DualStackEnabled() {
+		// the same routes for the secondary IP family
+		r3 := &vpp_l3.Route{
+			Type:        vpp_l3.Route_INTER_VRF,
+			DstNetwork:  anyNetAddrForAF(n.IPAM.SecondaryPodGatewayIP()),
+			VrfId:       routingCfg.PodVRFID,
+			ViaVrfId:    routingCfg.MainVRFID,
+			NextHopAddr: anyAddrForAF(n.IPAM.SecondaryPodGatewayIP()),
+		}
+		r3Key := models.Key(r3)
+		routes[r3Key] = r3
+
+		if n.ContivConf.GetRoutingConfig().NodeToNodeTransport == contivconf.VXLANTransport {
+			r4 := &vpp_l3.Route{
+				Type:        vpp_l3.Route_INTER_VRF,
+				DstNetwork:  n.IPAM.SecondaryHostInterconnectSubnetThisNode().String(),
+				VrfId:       routingCfg.PodVRFID,
+				ViaVrfId:    routingCfg.MainVRFID,
+				NextHopAddr: anyAddrForAF(n.IPAM.SecondaryHostInterconnectSubnetThisNode().IP),
+			}
+			r4Key := models.Key(r4)
+			routes[r4Key] = r4
+		}
+	}
+
 	return routes
 }
 
@@ -617,6 +706,30 @@ func (n *IPNet) routesMainToPodVRF() map[string]*vpp_l3.Route {
 		routes[r1Key] = r1
 	}
 
+	if n.IPAM.DualStackEnabled() {
+		// the same routes for the secondary IP family
+		var secondaryNetworks []*net.IPNet
+		if n.ContivConf.GetRoutingConfig().NodeToNodeTransport == contivconf.VXLANTransport {
+			secondaryNetworks = append(secondaryNetworks, n.IPAM.SecondaryPodSubnetAllNodes(),
+				n.IPAM.SecondaryHostInterconnectSubnetAllNodes())
+		} else {
+			secondaryNetworks = append(secondaryNetworks, n.IPAM.SecondaryPodSubnetThisNode())
+		}
+		if isIPv6(n.IPAM.SecondaryServiceNetwork().IP) {
+			secondaryNetworks = append(secondaryNetworks, n.IPAM.SecondaryServiceNetwork())
+		}
+		for _, dstNetwork := range secondaryNetworks {
+			r := &vpp_l3.Route{
+				Type:        vpp_l3.Route_INTER_VRF,
+				DstNetwork:  dstNetwork.String(),
+				VrfId:       routingCfg.MainVRFID,
+				ViaVrfId:    routingCfg.PodVRFID,
+				NextHopAddr: anyAddrForAF(dstNetwork.IP),
+			}
+			routes[models.Key(r)] = r
+		}
+	}
+
 	return routes
 }
 
@@ -644,6 +757,22 @@ func (n *IPNet) dropRoutesIntoPodVRF() map[string]*vpp_l3.Route {
 		routes[r2Key] = r2
 	}
 
+	if n.IPAM.DualStackEnabled() {
+		// the same drop routes for the secondary IP family
+		var secondaryNetworks []*net.IPNet
+		if isIPv6(n.IPAM.SecondaryServiceNetwork().IP) {
+			secondaryNetworks = append(secondaryNetworks, n.IPAM.SecondaryServiceNetwork())
+		}
+		if n.ContivConf.GetRoutingConfig().NodeToNodeTransport == contivconf.VXLANTransport {
+			secondaryNetworks = append(secondaryNetworks, n.IPAM.SecondaryPodSubnetAllNodes(),
+				n.IPAM.SecondaryHostInterconnectSubnetAllNodes())
+		}
+		for _, dstNetwork := range secondaryNetworks {
+			r := n.dropRoute(routingCfg.PodVRFID, dstNetwork)
+			routes[models.Key(r)] = r
+		}
+	}
+
 	return routes
 }
 
@@ -660,13 +789,22 @@ func (n *IPNet) dropRoute(vrfID uint32, dstAddr *net.IPNet) *vpp_l3.Route {
 // podGwLoopback returns configuration of the loopback interface used in the POD VRF
 // to respond on POD gateway IP address.
 func (n *IPNet) podGwLoopback(network string, vrf uint32) (key string, config *vpp_interfaces.Interface) {
+	gwIP := ipNetToString(combineAddrWithNet(n.IPAM.PodGatewayIP(network), n.IPAM.PodSubnetThisNode(network)))
+	if !n.IPAM.PodSubnetThisNode(network).Contains(n.IPAM.PodGatewayIP(network)) {
+		// explicit gateway of a custom network shared by all nodes, use host prefix
+		// not to cover pod subnets of the other nodes
+		gwIP = n.IPAM.PodGatewayIP(network).String() + hostPrefixForAF(n.IPAM.PodGatewayIP(network))
+	}
 	lo := &vpp_interfaces.Interface{
-		Name:    n.podGwLoopbackInterfaceName(network),
-		Type:    vpp_interfaces.Interface_SOFTWARE_LOOPBACK,
-		Enabled: true,
-		IpAddresses: []string{ipNetToString(combineAddrWithNet(
-			n.IPAM.PodGatewayIP(network), n.IPAM.PodSubnetThisNode(network)))},
-		Vrf: vrf,
+		Name:        n.podGwLoopbackInterfaceName(network),
+		Type:        vpp_interfaces.Interface_SOFTWARE_LOOPBACK,
+		Enabled:     true,
+		IpAddresses: []string{gwIP},
+		Vrf:         vrf,
+	}
+	if n.isDefaultPodNetwork(network) && n.IPAM.DualStackEnabled() {
+		lo.IpAddresses = append(lo.IpAddresses, ipNetToString(combineAddrWithNet(
+			n.IPAM.SecondaryPodGatewayIP(), n.IPAM.SecondaryPodSubnetThisNode())))
 	}
 	key = vpp_interfaces.InterfaceKey(lo.Name)
 	return key, lo
@@ -746,7 +884,11 @@ func (n *IPNet) customNetworkConfig(nwConfig *customnetmodel.CustomNetwork, even
 		// VXLAN BD + BVI
 		key, bd := n.vxlanBridgeDomain(nwConfig.Name)
 		config[key] = bd
-		key, bvi, _ := n.vxlanBVILoopback(nwConfig.Name, vrfID)
+		key, bvi, err := n.vxlanBVILoopback(nwConfig.Name, vrfID)
+		if err != nil {
+			n.Log.Error(err)
+			return config, err
+		}
 		config[key] = bvi
 
 		// connectivity to the other nodes
@@ -763,6 +905,11 @@ func (n *IPNet) customNetworkConfig(nwConfig *customnetmodel.CustomNetwork, even
 				n.Log.Error(err)
 				return config, err
 			}
+			nextHop, err = n.customNetworkNextHopIP(nw.config.Name, node, nextHop)
+			if err != nil {
+				n.Log.Error(err)
+				return config, err
+			}
 			routesCfg, err := n.connectivityToOtherNodePods(nw.config.Name, node.ID, nextHop)
 			if err != nil {
 				n.Log.Error(err)
@@ -907,6 +1054,19 @@ func (n *IPNet) isStubNetwork(nwName string) bool {
 	return nwName == stubNetworkName
 }
 
+// isSecondaryFamilyNetwork returns true if provided network name is a L3 custom network
+// with the subnet from the other IP family than the one used for the cluster.
+func (n *IPNet) isSecondaryFamilyNetwork(nwName string) bool {
+	if n.isDefaultPodNetwork(nwName) {
+		return false
+	}
+	nw := n.customNetworks[nwName]
+	if nw == nil || nw.config == nil || nw.config.SubnetCIDR == "" {
+		return false
+	}
+	return isIPv6Str(nw.config.SubnetCIDR) != n.ContivConf.GetIPAMConfig().UseIPv6
+}
+
 // isL2Network returns true if provided network name is a layer 2 (switched) network.
 func (n *IPNet) isL2Network(nwName string) bool {
 	nw := n.customNetworks[nwName]
@@ -930,7 +1090,18 @@ func (n *IPNet) isL3Network(nwName string) bool {
 // vxlanBVILoopback returns configuration of the loopback interfaces acting as BVI
 // for the bridge domain with VXLAN interfaces.
 func (n *IPNet) vxlanBVILoopback(network string, vrf uint32) (key string, config *vpp_interfaces.Interface, err error) {
-	vxlanIP, vxlanIPNet, err := n.IPAM.VxlanIPAddress(n.NodeSync.GetNodeID())
+	var vxlanIP net.IP
+	var vxlanIPNet *net.IPNet
+	if n.isSecondaryFamilyNetwork(network) {
+		// custom network from the secondary IP family (dual-stack only)
+		if !n.IPAM.DualStackEnabled() {
+			return "", nil, fmt.Errorf("subnet of the network %s is not from the IP family of the cluster "+
+				"(dual-stack is required)", network)
+		}
+		vxlanIP, vxlanIPNet, err = n.IPAM.SecondaryVxlanIPAddress(n.NodeSync.GetNodeID())
+	} else {
+		vxlanIP, vxlanIPNet, err = n.IPAM.VxlanIPAddress(n.NodeSync.GetNodeID())
+	}
 	if err != nil {
 		return "", nil, err
 	}
@@ -942,6 +1113,13 @@ func (n *IPNet) vxlanBVILoopback(network string, vrf uint32) (key string, config
 		PhysAddress: hwAddrForNodeInterface(n.NodeSync.GetNodeID(), vxlanBVIHwAddrPrefix),
 		Vrf:         vrf,
 	}
+	if n.isDefaultPodNetwork(network) && n.IPAM.DualStackEnabled() {
+		secondaryIP, secondaryIPNet, err := n.IPAM.SecondaryVxlanIPAddress(n.NodeSync.GetNodeID())
+		if err != nil {
+			return "", nil, err
+		}
+		vxlan.IpAddresses = append(vxlan.IpAddresses, ipNetToString(combineAddrWithNet(secondaryIP, secondaryIPNet)))
+	}
 	key = vpp_interfaces.InterfaceKey(vxlan.Name)
 	return key, vxlan, nil
 }
@@ -1237,21 +1415,42 @@ func (n *IPNet) srv6DX6PodTunnelEgress(sid net.IP, outgoingInterface string, nex
 // srv6PodTunnelEgress creates LocalSID for receiving node-to-node communication encapsulated in SRv6. This node is
 // the receiving end that used this localSID to decapsulate the SRv6 traffic and forward it by pod VRF ipv6/ipv4 table lookup.
 func (n *IPNet) srv6PodTunnelEgress(sid net.IP) (key string, config *vpp_srv6.LocalSID) {
-	return n.srv6TunnelEgress(sid, n.ContivConf.GetRoutingConfig().PodVRFID)
+	return n.srv6TunnelEgress(sid, n.ContivConf.GetRoutingConfig().PodVRFID, n.ContivConf.GetIPAMConfig().UseIPv6)
 }
 
 // srv6PodTunnelEgress creates LocalSID for receiving node-to-node communication encapsulated in SRv6. This node is
 // the receiving end that used this localSID to decapsulate the SRv6 traffic and forward it by main VRF ipv6/ipv4 table lookup (host destination).
 func (n *IPNet) srv6HostTunnelEgress(sid net.IP) (key string, config *vpp_srv6.LocalSID) {
-	return n.srv6TunnelEgress(sid, n.ContivConf.GetRoutingConfig().MainVRFID)
+	return n.srv6TunnelEgress(sid, n.ContivConf.GetRoutingConfig().MainVRFID, n.ContivConf.GetIPAMConfig().UseIPv6)
+}
+
+// srv6SecondaryTunnelEgress creates LocalSIDs for receiving node-to-node communication of the secondary IP family
+// encapsulated in SRv6 (dual-stack only). SIDs are derived from the secondary pod and host subnets of this node
+// and the decapsulated traffic is forwarded by pod/main VRF lookup in the table of the secondary IP family.
+func (n *IPNet) srv6SecondaryTunnelEgress() (config controller.KeyValuePairs) {
+	config = make(controller.KeyValuePairs)
+	if !n.IPAM.DualStackEnabled() {
+		return config
+	}
+	routingCfg := n.ContivConf.GetRoutingConfig()
+	secondaryIPv6 := !n.ContivConf.GetIPAMConfig().UseIPv6
+
+	podSid := n.IPAM.SidForNodeToNodePodLocalsid(n.IPAM.SecondaryPodSubnetThisNode().IP)
+	key, podLocalsid := n.srv6TunnelEgress(podSid, routingCfg.PodVRFID, secondaryIPv6)
+	config[key] = podLocalsid
+
+	hostSid := n.IPAM.SidForNodeToNodeHostLocalsid(n.IPAM.SecondaryHostInterconnectSubnetThisNode().IP)
+	key, hostLocalsid := n.srv6TunnelEgress(hostSid, routingCfg.MainVRFID, secondaryIPv6)
+	config[key] = hostLocalsid
+	return config
 }
 
-func (n *IPNet) srv6TunnelEgress(sid net.IP, lookupVrfID uint32) (key string, config *vpp_srv6.LocalSID) {
+func (n *IPNet) srv6TunnelEgress(sid net.IP, lookupVrfID uint32, ipv6 bool) (key string, config *vpp_srv6.LocalSID) {
 	localSID := &vpp_srv6.LocalSID{
 		Sid:               sid.String(),
 		InstallationVrfId: n.ContivConf.GetRoutingConfig().MainVRFID,
 	}
-	if n.ContivConf.GetIPAMConfig().UseIPv6 {
+	if ipv6 {
 		localSID.EndFunction = &vpp_srv6.LocalSID_EndFunctionDt6{EndFunctionDt6: &vpp_srv6.LocalSID_EndDT6{
 			VrfId: lookupVrfID,
 		}}
@@ -1350,6 +1549,70 @@ func (n *IPNet) connectivityToOtherNodeHostStack(otherNodeID uint32, nextHopIP n
 	return config, nil
 }
 
+// secondaryConnectivityToOtherNode returns configuration that will route traffic of the secondary IP family
+// to pods and the host stack of another node (dual-stack only). The nextHopIP is the next hop
+// used for the primary IP family, which is used as the SRv6 underlay next hop.
+func (n *IPNet) secondaryConnectivityToOtherNode(node *nodesync.Node, nextHopIP net.IP) (config controller.KeyValuePairs, err error) {
+	config = make(controller.KeyValuePairs, 0)
+	podNetwork, err := n.IPAM.SecondaryPodSubnetOtherNode(node.ID)
+	if err != nil {
+		return config, fmt.Errorf("Failed to compute secondary pod network for node ID %v, error: %v ", node.ID, err)
+	}
+	hostNetwork, err := n.IPAM.SecondaryHostInterconnectSubnetOtherNode(node.ID)
+	if err != nil {
+		return config, fmt.Errorf("Can't compute secondary vswitch network for host ID %v, error: %v ", node.ID, err)
+	}
+
+	switch n.ContivConf.GetRoutingConfig().NodeToNodeTransport {
+	case contivconf.SRv6Transport:
+		// SRv6 tunnels ending with DT4/DT6 of the secondary IP family
+		podBsid := n.IPAM.BsidForNodeToNodePodPolicy(podNetwork.IP)
+		podSid := n.IPAM.SidForNodeToNodePodLocalsid(podNetwork.IP)
+		podTunnelConfig, err := n.srv6NodeToNodeTunnelIngress(nextHopIP, podNetwork, podBsid, podSid, "lookupInPodVRF")
+		if err != nil {
+			return config, fmt.Errorf("can't create configuration for node-to-node SRv6 tunnel for secondary Pod traffic due to: %v", err)
+		}
+		mergeConfiguration(config, podTunnelConfig)
+
+		hostBsid := n.IPAM.BsidForNodeToNodeHostPolicy(hostNetwork.IP)
+		hostSid := n.IPAM.SidForNodeToNodeHostLocalsid(hostNetwork.IP)
+		hostTunnelConfig, err := n.srv6NodeToNodeTunnelIngress(nextHopIP, hostNetwork, hostBsid, hostSid, "lookupInMainVRF")
+		if err != nil {
+			return config, fmt.Errorf("can't create configuration for node-to-node SRv6 tunnel for secondary Host traffic due to: %v", err)
+		}
+		mergeConfiguration(config, hostTunnelConfig)
+	case contivconf.NoOverlayTransport:
+		// route directly via VPP IP address of the other node from the secondary IP family
+		var secondaryNextHop net.IP
+		for _, vppIP := range node.VppIPAddresses {
+			if isIPv6(vppIP.Address) != n.ContivConf.GetIPAMConfig().UseIPv6 {
+				secondaryNextHop = vppIP.Address
+				break
+			}
+		}
+		if secondaryNextHop == nil {
+			n.Log.Warnf("Node %s has no VPP IP address of the secondary IP family, "+
+				"skipping secondary routes towards the node", node.Name)
+			return config, nil
+		}
+		key, route := n.routeToOtherNodeNetworks(DefaultPodNetworkName, podNetwork, secondaryNextHop)
+		config[key] = route
+		key, route = n.routeToOtherNodeNetworks(DefaultPodNetworkName, hostNetwork, secondaryNextHop)
+		config[key] = route
+	case contivconf.VXLANTransport:
+		// route via the VXLAN BVI IP address of the other node from the secondary IP family
+		secondaryNextHop, _, err := n.IPAM.SecondaryVxlanIPAddress(node.ID)
+		if err != nil {
+			return config, err
+		}
+		key, route := n.routeToOtherNodeNetworks(DefaultPodNetworkName, podNetwork, secondaryNextHop)
+		config[key] = route
+		key, route = n.routeToOtherNodeNetworks(DefaultPodNetworkName, hostNetwork, secondaryNextHop)
+		config[key] = route
+	}
+	return config, nil
+}
+
 // routeToOtherNodeNetworks is a helper function to build route for traffic destined to another node.
 func (n *IPNet) routeToOtherNodeNetworks(network string, destNetwork *net.IPNet, nextHopIP net.IP) (key string, config *vpp_l3.Route) {
 	route := &vpp_l3.Route{
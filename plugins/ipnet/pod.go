@@ -20,12 +20,12 @@ import (
 	"hash/fnv"
 	"net"
 	"os/exec"
-	"sort"
 	"strings"
 
 	"github.com/contiv/vpp/plugins/contivconf"
 	controller "github.com/contiv/vpp/plugins/controller/api"
 	"github.com/contiv/vpp/plugins/devicemanager"
+	"github.com/contiv/vpp/plugins/ipnet/customif"
 	"github.com/contiv/vpp/plugins/podmanager"
 	"github.com/golang/protobuf/proto"
 	"go.ligato.io/cn-infra/v2/db/keyval"
@@ -67,10 +67,10 @@ const (
 	podMemifLogicalNamePrefix = "memif-"
 
 	// special network name dedicated to "stub" custom interfaces - not connected to any VRF nor bridge domain.
-	stubNetworkName = "stub"
+	stubNetworkName = customif.StubNetwork
 
 	// DefaultPodNetworkName is the network name dedicated to the default pod network
-	DefaultPodNetworkName = "default"
+	DefaultPodNetworkName = customif.DefaultNetwork
 
 	// prefix attached to custom network names inside IP allocations exposed by Contiv for CNFs
 	ipAllocNetPrefix = "contiv-"
@@ -85,12 +85,10 @@ const (
 	contivAnnotationPrefix            = "contivpp.io/"
 	contivMicroserviceLabelAnnotation = contivAnnotationPrefix + "microservice-label"  // k8s annotation used to specify microservice label of a pod
 	contivServiceEndpointIfAnnotation = contivAnnotationPrefix + "service-endpoint-if" // k8s annotation used to specify k8s service endpoint interface
-	contivCustomIfAnnotation          = contivAnnotationPrefix + "custom-if"           // k8s annotation used to request custom pod interfaces
-	contivCustomIfSeparator           = ","                                            // separator used to split multiple interfaces in k8s annotation
 
-	memifIfType = "memif"
-	tapIfType   = "tap"
-	vethIfType  = "veth"
+	memifIfType = customif.MemifType
+	tapIfType   = customif.TapType
+	vethIfType  = customif.VethType
 )
 
 // podCustomIfInfo holds information about a custom pod interface
@@ -111,6 +109,7 @@ func (n *IPNet) podConnectivityConfig(pod *podmanager.LocalPod) (config controll
 	config[key] = linuxLoop
 
 	podIP := n.IPAM.GetPodIP(pod.ID)
+	secondaryPodIP := n.secondaryPodIP(pod)
 
 	// create VPP to POD interconnect interface
 	var vppInterfaceToPod string
@@ -119,11 +118,17 @@ func (n *IPNet) podConnectivityConfig(pod *podmanager.LocalPod) (config controll
 		key, vppTap := n.podVPPTap(pod, podIP, "", DefaultPodNetworkName)
 		config[key] = vppTap
 		key, linuxTap := n.podLinuxTAP(pod, podIP, "", false)
+		if secondaryPodIP != nil {
+			linuxTap.IpAddresses = append(linuxTap.IpAddresses, secondaryPodIP.String())
+		}
 		config[key] = linuxTap
 		vppInterfaceToPod = vppTap.Name
 	} else {
 		// VETH pair + AF_PACKET
 		key, veth1 := n.podVeth1(pod, podIP, "", false)
+		if secondaryPodIP != nil {
+			veth1.IpAddresses = append(veth1.IpAddresses, secondaryPodIP.String())
+		}
 		config[key] = veth1
 		key, veth2 := n.podVeth2(pod, "")
 		config[key] = veth2
@@ -152,6 +157,21 @@ func (n *IPNet) podConnectivityConfig(pod *podmanager.LocalPod) (config controll
 	key, vppRoute := n.vppToPodRoute(pod, podIP, "", "", n.ContivConf.GetRoutingConfig().PodVRFID)
 	config[key] = vppRoute
 
+	// dual-stack: connectivity for the IP address of the secondary IP family
+	if secondaryPodIP != nil {
+		key, podArp := n.podToVPPSecondaryArpEntry(pod)
+		config[key] = podArp
+		key, route := n.podToVPPSecondaryLinkRoute(pod)
+		config[key] = route
+		key, route = n.podToVPPSecondaryDefaultRoute(pod)
+		config[key] = route
+		key, vppArp := n.vppToPodArpEntry(pod, secondaryPodIP, "", "")
+		config[key] = vppArp
+		key, vppRoute := n.vppToPodRoute(pod, secondaryPodIP, "", "",
+			n.ContivConf.GetRoutingConfig().PodVRFID)
+		config[key] = vppRoute
+	}
+
 	// /32 (or /128 for ipv6) route from the host to POD (only in external IPAM case)
 	if n.ContivConf.GetIPAMConfig().UseExternalIPAM {
 		key, route := n.hostToPodRoute(pod)
@@ -519,49 +539,25 @@ func getContivServiceEndpointIf(annotations map[string]string) string {
 
 // hasContivCustomIfAnnotation returns true if provided annotations contain contiv custom-if annotation, false otherwise.
 func hasContivCustomIfAnnotation(annotations map[string]string) bool {
-	for k := range annotations {
-		if strings.HasPrefix(k, contivCustomIfAnnotation) {
-			return true
-		}
-	}
-	return false
+	return customif.HasAnnotation(annotations)
 }
 
 // getContivCustomIfs returns alphabetically ordered slice of custom interfaces defined in pod annotations.
 func getContivCustomIfs(annotations map[string]string) []string {
-	out := make([]string, 0)
-
-	for k, v := range annotations {
-		if strings.HasPrefix(k, contivCustomIfAnnotation) {
-			ifs := strings.Split(v, contivCustomIfSeparator)
-			for _, i := range ifs {
-				out = append(out, strings.TrimSpace(i))
-			}
-		}
-	}
-	sort.Strings(out)
-	return out
+	return customif.FromAnnotations(annotations)
 }
 
 // parseCustomIfInfo parses custom interface annotation into individual parts.
 func parseCustomIfInfo(ifAnnotation string) (ifInfo *podCustomIfInfo, err error) {
-	ifParts := strings.Split(ifAnnotation, "/")
-	if len(ifParts) < 2 {
-		err = fmt.Errorf("invalid %s annotation value: %s", contivCustomIfAnnotation, ifAnnotation)
-		return
-	}
-
-	ifInfo = &podCustomIfInfo{
-		ifName: ifParts[0],
-		ifType: ifParts[1],
-	}
-
-	if len(ifParts) > 2 {
-		ifInfo.ifNet = ifParts[2]
-	} else {
-		ifInfo.ifNet = DefaultPodNetworkName
+	iface, err := customif.Parse(ifAnnotation)
+	if err != nil {
+		return nil, err
 	}
-	return
+	return &podCustomIfInfo{
+		ifName: iface.Name,
+		ifType: iface.Type,
+		ifNet:  iface.Network,
+	}, nil
 }
 
 /******************************** loopback interface ********************************/
@@ -1041,6 +1037,48 @@ func (n *IPNet) podToVPPRoute(pod *podmanager.LocalPod, customIfName, customIfTy
 	return key, route
 }
 
+// podToVPPSecondaryArpEntry returns configuration for ARP entry resolving hardware address
+// for pod gateway IP of the secondary IP family (dual-stack only).
+func (n *IPNet) podToVPPSecondaryArpEntry(pod *podmanager.LocalPod) (key string, config *linux_l3.ARPEntry) {
+	_, linuxIfName, _ := n.podInterfaceName(pod, "", "")
+	arp := &linux_l3.ARPEntry{
+		Interface: linuxIfName,
+		IpAddress: n.IPAM.SecondaryPodGatewayIP().String(),
+		HwAddress: n.hwAddrForPod(pod, "", true),
+	}
+	key = linux_l3.ArpKey(arp.Interface, arp.IpAddress)
+	return key, arp
+}
+
+// podToVPPSecondaryLinkRoute returns configuration for route that puts pod's default GW
+// of the secondary IP family behind the interface connecting pod with VPP (dual-stack only).
+func (n *IPNet) podToVPPSecondaryLinkRoute(pod *podmanager.LocalPod) (key string, config *linux_l3.Route) {
+	_, linuxIfName, _ := n.podInterfaceName(pod, "", "")
+	gwIP := n.IPAM.SecondaryPodGatewayIP()
+	route := &linux_l3.Route{
+		OutgoingInterface: linuxIfName,
+		Scope:             linux_l3.Route_LINK,
+		DstNetwork:        gwIP.String() + hostPrefixForAF(gwIP),
+	}
+	key = linux_l3.RouteKey(route.DstNetwork, route.OutgoingInterface)
+	return key, route
+}
+
+// podToVPPSecondaryDefaultRoute returns configuration for the default route of the secondary
+// IP family of the given pod (dual-stack only).
+func (n *IPNet) podToVPPSecondaryDefaultRoute(pod *podmanager.LocalPod) (key string, config *linux_l3.Route) {
+	_, linuxIfName, _ := n.podInterfaceName(pod, "", "")
+	gwIP := n.IPAM.SecondaryPodGatewayIP()
+	route := &linux_l3.Route{
+		OutgoingInterface: linuxIfName,
+		DstNetwork:        anyNetAddrForAF(gwIP),
+		Scope:             linux_l3.Route_GLOBAL,
+		GwAddr:            gwIP.String(),
+	}
+	key = linux_l3.RouteKey(route.DstNetwork, route.OutgoingInterface)
+	return key, route
+}
+
 /*************************** VSwitch ARPs and routes ***************************/
 
 // vppToPodArpEntry return configuration for ARP entry used in VPP to resolve
@@ -1098,6 +1136,16 @@ func (n *IPNet) hostToPodRoute(pod *podmanager.LocalPod) (key string, config *li
 	return key, route
 }
 
+// secondaryPodIP returns IP address of the secondary IP family allocated to the given pod
+// (dual-stack only, nil otherwise).
+func (n *IPNet) secondaryPodIP(pod *podmanager.LocalPod) *net.IPNet {
+	podIPs := n.IPAM.GetPodIPs(pod.ID)
+	if len(podIPs) < 2 {
+		return nil
+	}
+	return podIPs[1]
+}
+
 /**************************** Address generators ******************************/
 
 // generateHwAddrForPod generates hardware address for Pod interface on the VPP
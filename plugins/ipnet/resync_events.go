@@ -186,6 +186,11 @@ func (n *IPNet) configureVswitchConnectivity(event controller.Event, txn control
 		hostSid := n.IPAM.SidForNodeToNodeHostLocalsid(n.nodeIP)
 		key, hostLocalsid := n.srv6HostTunnelEgress(hostSid)
 		txn.Put(key, hostLocalsid)
+
+		// create localsids for the secondary IP family (dual-stack)
+		for key, localsid := range n.srv6SecondaryTunnelEgress() {
+			txn.Put(key, localsid)
+		}
 	}
 	// create localsid as receiving end for SRv6 encapsulated communication between 2 nodes (for k8s service purposes)
 	if n.ContivConf.GetRoutingConfig().UseSRv6ForServices {
@@ -377,6 +382,11 @@ func (n *IPNet) configureVswitchHostConnectivity(txn controller.ResyncOperations
 		txn.Put(key, routeToServices)
 	}
 
+	// routes from the host to pods & services of the secondary IP family (dual-stack)
+	for key, route := range n.secondaryRoutesFromHost() {
+		txn.Put(key, route)
+	}
+
 	return nil
 }
 
@@ -261,7 +261,7 @@ func (n *IPNet) addPod(event *podmanager.AddPod, txn controller.UpdateOperations
 
 	// 1. try to allocate an IP address for this pod
 
-	_, err = n.IPAM.AllocatePodIP(pod.ID, event.IPAMType, event.IPAMData)
+	_, _, err = n.IPAM.AllocatePodIP(pod.ID, event.IPAMType, event.IPAMData)
 	if err != nil {
 		err = fmt.Errorf("failed to allocate new IP address for pod %v: %v", pod.ID, err)
 		n.Log.Error(err)
@@ -303,7 +303,7 @@ func (n *IPNet) addPod(event *podmanager.AddPod, txn controller.UpdateOperations
 		ipVersion = podmanager.IPv6
 	}
 
-	event.Interfaces = append(event.Interfaces, podmanager.PodInterface{
+	podIface := podmanager.PodInterface{
 		HostName: podInterfaceHostName,
 		IPAddresses: []*podmanager.IPWithGateway{
 			{
@@ -312,7 +312,7 @@ func (n *IPNet) addPod(event *podmanager.AddPod, txn controller.UpdateOperations
 				Gateway: n.IPAM.PodGatewayIP(DefaultPodNetworkName),
 			},
 		},
-	})
+	}
 	_, anyDstNet, _ := net.ParseCIDR(anyNetAddrForAF(n.IPAM.PodGatewayIP(DefaultPodNetworkName)))
 
 	event.Routes = append(event.Routes, podmanager.Route{
@@ -320,6 +320,26 @@ func (n *IPNet) addPod(event *podmanager.AddPod, txn controller.UpdateOperations
 		Gateway: n.IPAM.PodGatewayIP(DefaultPodNetworkName),
 	})
 
+	// dual-stack: add IP address & default route of the secondary IP family
+	if secondaryPodIP := n.secondaryPodIP(pod); secondaryPodIP != nil {
+		secondaryGwIP := n.IPAM.SecondaryPodGatewayIP()
+		secondaryVersion := podmanager.IPv4
+		if isIPv6(secondaryPodIP.IP) {
+			secondaryVersion = podmanager.IPv6
+		}
+		podIface.IPAddresses = append(podIface.IPAddresses, &podmanager.IPWithGateway{
+			Version: secondaryVersion,
+			Address: secondaryPodIP,
+			Gateway: secondaryGwIP,
+		})
+		_, anyDstNet, _ := net.ParseCIDR(anyNetAddrForAF(secondaryGwIP))
+		event.Routes = append(event.Routes, podmanager.Route{
+			Network: anyDstNet,
+			Gateway: secondaryGwIP,
+		})
+	}
+	event.Interfaces = append(event.Interfaces, podIface)
+
 	return "configure IP connectivity", nil
 }
 
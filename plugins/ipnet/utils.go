@@ -75,6 +75,9 @@ func (n *IPNet) getHostLinkIPs() (hostIPs []net.IP, err error) {
 			if n.ContivConf.GetIPAMConfig().UseIPv6 {
 				family = netlink.FAMILY_V6
 			}
+			if n.ContivConf.GetIPAMConfig().DualStack {
+				family = netlink.FAMILY_ALL
+			}
 			addrList, err := netlink.AddrList(l, family)
 			if err != nil {
 				n.Log.Error("Unable to list link IPs:", err)
@@ -82,7 +85,7 @@ func (n *IPNet) getHostLinkIPs() (hostIPs []net.IP, err error) {
 			}
 			// return all IPs
 			for _, addr := range addrList {
-				if family == netlink.FAMILY_V6 && addr.Scope == int(netlink.SCOPE_LINK) {
+				if isIPv6(addr.IP) && addr.Scope == int(netlink.SCOPE_LINK) {
 					// skip link-local IPv6 addresses
 					continue
 				}
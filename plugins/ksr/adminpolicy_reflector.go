@@ -0,0 +1,308 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ksr
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+
+	coreV1 "k8s.io/api/core/v1"
+	clientApiMetaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/contiv/vpp/plugins/ksr/model/adminpolicy"
+	anpV1alpha1 "github.com/contiv/vpp/plugins/ksr/pkg/apis/adminpolicy/v1alpha1"
+)
+
+// adminPolicyResource is the name of the (plural) resource of admin network policies.
+const adminPolicyResource = "adminnetworkpolicies"
+
+// AdminPolicyReflector subscribes to K8s cluster to watch for changes
+// in the configuration of k8s admin network policies.
+// Protobuf-modelled changes are published into the selected key-value store.
+type AdminPolicyReflector struct {
+	Reflector
+
+	// restClient is a REST client for the policy.networking.k8s.io API group,
+	// which is not part of the standard client-set.
+	restClient rest.Interface
+}
+
+// Init subscribes to K8s cluster to watch for changes in the configuration
+// of k8s admin network policies. The subscription does not become active until
+// Start() is called.
+func (apr *AdminPolicyReflector) Init(stopCh2 <-chan struct{}, wg *sync.WaitGroup) error {
+	adminPolicyReflectorFuncs := ReflectorFunctions{
+		EventHdlrFunc: cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				apr.addAdminPolicy(obj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				apr.deleteAdminPolicy(obj)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				apr.updateAdminPolicy(oldObj, newObj)
+			},
+		},
+		ProtoAllocFunc: func() proto.Message {
+			return &adminpolicy.AdminPolicy{}
+		},
+		K8s2NodeFunc: func(k8sObj interface{}) (interface{}, string, bool) {
+			k8sPolicy, ok := k8sObj.(*anpV1alpha1.AdminNetworkPolicy)
+			if !ok {
+				apr.Log.Errorf("admin policy syncDataStore: wrong object type %s, obj %+v",
+					reflect.TypeOf(k8sObj), k8sObj)
+				return nil, "", false
+			}
+			return apr.adminPolicyToProto(k8sPolicy), adminpolicy.Key(k8sPolicy.Name), true
+		},
+		K8sClntGetFunc: func(cs *kubernetes.Clientset) rest.Interface {
+			return apr.restClient
+		},
+	}
+
+	return apr.ksrInit(stopCh2, wg, adminpolicy.KeyPrefix(), adminPolicyResource,
+		&anpV1alpha1.AdminNetworkPolicy{}, adminPolicyReflectorFuncs)
+}
+
+// newAdminPolicyRESTClient builds REST client for the AdminNetworkPolicy API.
+func newAdminPolicyRESTClient(k8sClientConfig *rest.Config) (rest.Interface, error) {
+	scheme := runtime.NewScheme()
+	if err := anpV1alpha1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	config := *k8sClientConfig
+	config.GroupVersion = &anpV1alpha1.SchemeGroupVersion
+	config.APIPath = "/apis"
+	config.ContentType = runtime.ContentTypeJSON
+	config.NegotiatedSerializer = serializer.NewCodecFactory(scheme).WithoutConversion()
+	if config.UserAgent == "" {
+		config.UserAgent = rest.DefaultKubernetesUserAgent()
+	}
+	return rest.RESTClientFor(&config)
+}
+
+// addAdminPolicy adds state data of a newly created K8s admin policy into
+// the data store.
+func (apr *AdminPolicyReflector) addAdminPolicy(obj interface{}) {
+	apr.Log.WithField("adminPolicy", obj).Info("Admin policy added")
+
+	k8sPolicy, ok := obj.(*anpV1alpha1.AdminNetworkPolicy)
+	if !ok {
+		apr.Log.Warn("Failed to cast newly created admin policy object")
+		apr.stats.ArgErrors++
+		return
+	}
+
+	policyProto := apr.adminPolicyToProto(k8sPolicy)
+	key := adminpolicy.Key(k8sPolicy.GetName())
+	apr.ksrAdd(key, policyProto)
+}
+
+// deleteAdminPolicy deletes state data of a removed K8s admin policy from
+// the data store.
+func (apr *AdminPolicyReflector) deleteAdminPolicy(obj interface{}) {
+	apr.Log.WithField("adminPolicy", obj).Info("Admin policy removed")
+
+	k8sPolicy, ok := obj.(*anpV1alpha1.AdminNetworkPolicy)
+	if !ok {
+		apr.Log.Warn("Failed to cast removed admin policy object")
+		apr.stats.ArgErrors++
+		return
+	}
+
+	key := adminpolicy.Key(k8sPolicy.GetName())
+	apr.ksrDelete(key)
+}
+
+// updateAdminPolicy updates state data of a changed K8s admin policy in
+// the data store.
+func (apr *AdminPolicyReflector) updateAdminPolicy(oldObj, newObj interface{}) {
+	oldK8sPolicy, ok1 := oldObj.(*anpV1alpha1.AdminNetworkPolicy)
+	newK8sPolicy, ok2 := newObj.(*anpV1alpha1.AdminNetworkPolicy)
+	if !ok1 || !ok2 {
+		apr.Log.Warn("Failed to cast changed admin policy object")
+		apr.stats.ArgErrors++
+		return
+	}
+	apr.Log.WithFields(map[string]interface{}{"policy-old": oldK8sPolicy, "policy-new": newK8sPolicy}).
+		Info("Admin policy updated")
+
+	oldPolicyProto := apr.adminPolicyToProto(oldK8sPolicy)
+	newPolicyProto := apr.adminPolicyToProto(newK8sPolicy)
+	key := adminpolicy.Key(newK8sPolicy.GetName())
+	apr.ksrUpdate(key, oldPolicyProto, newPolicyProto)
+}
+
+// adminPolicyToProto converts admin policy state data from the k8s
+// representation into our protobuf-modelled data structure.
+func (apr *AdminPolicyReflector) adminPolicyToProto(k8sPolicy *anpV1alpha1.AdminNetworkPolicy) *adminpolicy.AdminPolicy {
+	policyProto := &adminpolicy.AdminPolicy{}
+
+	// Name & Priority
+	policyProto.Name = k8sPolicy.GetName()
+	policyProto.Priority = k8sPolicy.Spec.Priority
+
+	// Subject
+	subject := &adminpolicy.AdminPolicy_Subject{}
+	if k8sPolicy.Spec.Subject.Pods != nil {
+		subject.Pods = apr.namespacedPodToProto(k8sPolicy.Spec.Subject.Pods)
+	} else if k8sPolicy.Spec.Subject.Namespaces != nil {
+		subject.Namespaces = apr.labelSelectorToProto(k8sPolicy.Spec.Subject.Namespaces)
+	}
+	policyProto.Subject = subject
+
+	// Ingress rules
+	for _, ingress := range k8sPolicy.Spec.Ingress {
+		ruleProto := &adminpolicy.AdminPolicy_Rule{
+			Name:   ingress.Name,
+			Action: apr.actionToProto(ingress.Action),
+		}
+		for _, from := range ingress.From {
+			peerProto := &adminpolicy.AdminPolicy_Peer{}
+			if from.Pods != nil {
+				peerProto.Pods = apr.namespacedPodToProto(from.Pods)
+			} else if from.Namespaces != nil {
+				peerProto.Namespaces = apr.labelSelectorToProto(from.Namespaces)
+			}
+			ruleProto.Peers = append(ruleProto.Peers, peerProto)
+		}
+		if ingress.Ports != nil {
+			ruleProto.Ports = apr.portsToProto(*ingress.Ports)
+		}
+		policyProto.IngressRule = append(policyProto.IngressRule, ruleProto)
+	}
+
+	// Egress rules
+	for _, egress := range k8sPolicy.Spec.Egress {
+		ruleProto := &adminpolicy.AdminPolicy_Rule{
+			Name:   egress.Name,
+			Action: apr.actionToProto(egress.Action),
+		}
+		for _, to := range egress.To {
+			peerProto := &adminpolicy.AdminPolicy_Peer{}
+			if to.Pods != nil {
+				peerProto.Pods = apr.namespacedPodToProto(to.Pods)
+			} else if to.Namespaces != nil {
+				peerProto.Namespaces = apr.labelSelectorToProto(to.Namespaces)
+			} else {
+				for _, network := range to.Networks {
+					peerProto.Networks = append(peerProto.Networks, string(network))
+				}
+			}
+			ruleProto.Peers = append(ruleProto.Peers, peerProto)
+		}
+		if egress.Ports != nil {
+			ruleProto.Ports = apr.portsToProto(*egress.Ports)
+		}
+		policyProto.EgressRule = append(policyProto.EgressRule, ruleProto)
+	}
+	return policyProto
+}
+
+// actionToProto converts rule action from the k8s representation into
+// our protobuf-modelled data structure.
+func (apr *AdminPolicyReflector) actionToProto(action anpV1alpha1.AdminNetworkPolicyRuleAction) adminpolicy.AdminPolicy_Action {
+	switch action {
+	case anpV1alpha1.AdminNetworkPolicyRuleActionDeny:
+		return adminpolicy.AdminPolicy_DENY
+	case anpV1alpha1.AdminNetworkPolicyRuleActionPass:
+		return adminpolicy.AdminPolicy_PASS
+	}
+	return adminpolicy.AdminPolicy_ALLOW
+}
+
+// namespacedPodToProto converts namespaced pod selector from the k8s
+// representation into our protobuf-modelled data structure.
+func (apr *AdminPolicyReflector) namespacedPodToProto(selector *anpV1alpha1.NamespacedPod) *adminpolicy.AdminPolicy_PodSelector {
+	return &adminpolicy.AdminPolicy_PodSelector{
+		Namespaces: apr.labelSelectorToProto(&selector.NamespaceSelector),
+		Pods:       apr.labelSelectorToProto(&selector.PodSelector),
+	}
+}
+
+// labelSelectorToProto converts label selector from the k8s representation into
+// our protobuf-modelled data structure.
+func (apr *AdminPolicyReflector) labelSelectorToProto(selector *clientApiMetaV1.LabelSelector) *adminpolicy.AdminPolicy_LabelSelector {
+	selectorProto := &adminpolicy.AdminPolicy_LabelSelector{}
+	// MatchLabels
+	for key, val := range selector.MatchLabels {
+		selectorProto.MatchLabel = append(selectorProto.MatchLabel, &adminpolicy.AdminPolicy_Label{Key: key, Value: val})
+	}
+	// MatchExpressions
+	for _, expression := range selector.MatchExpressions {
+		expressionProto := &adminpolicy.AdminPolicy_LabelSelector_LabelExpression{}
+		// Key
+		expressionProto.Key = expression.Key
+		// Operator
+		switch expression.Operator {
+		case clientApiMetaV1.LabelSelectorOpIn:
+			expressionProto.Operator = adminpolicy.AdminPolicy_LabelSelector_LabelExpression_IN
+		case clientApiMetaV1.LabelSelectorOpNotIn:
+			expressionProto.Operator = adminpolicy.AdminPolicy_LabelSelector_LabelExpression_NOT_IN
+		case clientApiMetaV1.LabelSelectorOpExists:
+			expressionProto.Operator = adminpolicy.AdminPolicy_LabelSelector_LabelExpression_EXISTS
+		case clientApiMetaV1.LabelSelectorOpDoesNotExist:
+			expressionProto.Operator = adminpolicy.AdminPolicy_LabelSelector_LabelExpression_DOES_NOT_EXIST
+		}
+		// Values
+		expressionProto.Value = append(expressionProto.Value, expression.Values...)
+		selectorProto.MatchExpression = append(selectorProto.MatchExpression, expressionProto)
+	}
+
+	// Make sure that match labels are always stored in the same order to avoid
+	// unnecessary updates during resync.
+	sort.Slice(selectorProto.MatchLabel, func(i, j int) bool {
+		return selectorProto.MatchLabel[i].Key < selectorProto.MatchLabel[j].Key
+	})
+
+	return selectorProto
+}
+
+// portsToProto converts a list of ports from the k8s representation into
+// our protobuf-modelled data structure.
+func (apr *AdminPolicyReflector) portsToProto(ports []anpV1alpha1.AdminNetworkPolicyPort) (portsProto []*adminpolicy.AdminPolicy_Port) {
+	for _, port := range ports {
+		portProto := &adminpolicy.AdminPolicy_Port{}
+		var protocol coreV1.Protocol
+		if port.PortNumber != nil {
+			protocol = port.PortNumber.Protocol
+			portProto.Port = port.PortNumber.Port
+		} else if port.PortRange != nil {
+			protocol = port.PortRange.Protocol
+			portProto.Port = port.PortRange.Start
+			portProto.EndPort = port.PortRange.End
+		} else {
+			continue
+		}
+		switch protocol {
+		case coreV1.ProtocolUDP:
+			portProto.Protocol = adminpolicy.AdminPolicy_Port_UDP
+		case coreV1.ProtocolSCTP:
+			portProto.Protocol = adminpolicy.AdminPolicy_Port_SCTP
+		default:
+			portProto.Protocol = adminpolicy.AdminPolicy_Port_TCP
+		}
+		portsProto = append(portsProto, portProto)
+	}
+	return portsProto
+}
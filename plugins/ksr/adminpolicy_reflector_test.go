@@ -0,0 +1,478 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ksr
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/contiv/vpp/plugins/ksr/model/adminpolicy"
+	anpV1alpha1 "github.com/contiv/vpp/plugins/ksr/pkg/apis/adminpolicy/v1alpha1"
+	"go.ligato.io/cn-infra/v2/logging"
+)
+
+type AdminPolicyTestVars struct {
+	k8sListWatch         *mockK8sListWatch
+	mockKvBroker         *mockKeyProtoValBroker
+	adminPolicyReflector *AdminPolicyReflector
+	adminPolicyTestData  []anpV1alpha1.AdminNetworkPolicy
+	reflectorRegistry    ReflectorRegistry
+}
+
+var adminPolicyTestVars AdminPolicyTestVars
+
+func TestAdminPolicyReflector(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	adminPolicyTestVars.k8sListWatch = &mockK8sListWatch{}
+	adminPolicyTestVars.mockKvBroker = newMockKeyProtoValBroker()
+
+	adminPolicyTestVars.reflectorRegistry = ReflectorRegistry{
+		reflectors: make(map[string]*Reflector),
+		lock:       sync.RWMutex{},
+	}
+
+	adminPolicyTestVars.adminPolicyReflector = &AdminPolicyReflector{
+		Reflector: Reflector{
+			Log:               logging.ForPlugin("admin-policy-reflector"),
+			K8sClientset:      &kubernetes.Clientset{},
+			K8sListWatch:      adminPolicyTestVars.k8sListWatch,
+			Broker:            adminPolicyTestVars.mockKvBroker,
+			dsSynced:          false,
+			objType:           adminPolicyObjType,
+			ReflectorRegistry: &adminPolicyTestVars.reflectorRegistry,
+		},
+	}
+
+	dnsPorts := []anpV1alpha1.AdminNetworkPolicyPort{
+		{PortNumber: &anpV1alpha1.Port{Protocol: coreV1.ProtocolUDP, Port: 53}},
+		{PortNumber: &anpV1alpha1.Port{Protocol: coreV1.ProtocolTCP, Port: 53}},
+	}
+	highPorts := []anpV1alpha1.AdminNetworkPolicyPort{
+		{PortRange: &anpV1alpha1.PortRange{Start: 30000, End: 32767}},
+	}
+
+	adminPolicyTestVars.adminPolicyTestData = []anpV1alpha1.AdminNetworkPolicy{
+		// Test data 0: mocks a new object to be added or a "pre-existing"
+		// object that is updated during sync
+		{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:            "cluster-control",
+				SelfLink:        "/apis/policy.networking.k8s.io/v1alpha1/adminnetworkpolicies/cluster-control",
+				UID:             "a6a4a1f4-1e1c-4d2c-9d4e-2f1a7b6a9c01",
+				ResourceVersion: "112233",
+				Generation:      1,
+				CreationTimestamp: metaV1.Date(2019, 11, 14, 18, 53, 37, 0,
+					time.FixedZone("PST", -800)),
+			},
+			Spec: anpV1alpha1.AdminNetworkPolicySpec{
+				Priority: 10,
+				Subject: anpV1alpha1.AdminNetworkPolicySubject{
+					Namespaces: &metaV1.LabelSelector{
+						MatchExpressions: []metaV1.LabelSelectorRequirement{
+							{
+								Key:      "kubernetes.io/metadata.name",
+								Operator: metaV1.LabelSelectorOpNotIn,
+								Values:   []string{"kube-system"},
+							},
+						},
+					},
+				},
+				Ingress: []anpV1alpha1.AdminNetworkPolicyIngressRule{
+					{
+						Name:   "allow-monitoring",
+						Action: anpV1alpha1.AdminNetworkPolicyRuleActionAllow,
+						From: []anpV1alpha1.AdminNetworkPolicyIngressPeer{
+							{
+								Namespaces: &metaV1.LabelSelector{
+									MatchLabels: map[string]string{"role": "monitoring"},
+								},
+							},
+						},
+					},
+					{
+						Name:   "pass-tenants",
+						Action: anpV1alpha1.AdminNetworkPolicyRuleActionPass,
+						From: []anpV1alpha1.AdminNetworkPolicyIngressPeer{
+							{
+								Pods: &anpV1alpha1.NamespacedPod{
+									NamespaceSelector: metaV1.LabelSelector{
+										MatchLabels: map[string]string{"tenant": "blue"},
+									},
+									PodSelector: metaV1.LabelSelector{
+										MatchLabels: map[string]string{"app": "web", "tier": "front"},
+									},
+								},
+							},
+						},
+						Ports: &highPorts,
+					},
+				},
+				Egress: []anpV1alpha1.AdminNetworkPolicyEgressRule{
+					{
+						Name:   "allow-dns",
+						Action: anpV1alpha1.AdminNetworkPolicyRuleActionAllow,
+						To: []anpV1alpha1.AdminNetworkPolicyEgressPeer{
+							{
+								Networks: []anpV1alpha1.CIDR{"10.96.0.10/32"},
+							},
+						},
+						Ports: &dnsPorts,
+					},
+					{
+						Name:   "deny-metadata",
+						Action: anpV1alpha1.AdminNetworkPolicyRuleActionDeny,
+						To: []anpV1alpha1.AdminNetworkPolicyEgressPeer{
+							{
+								Networks: []anpV1alpha1.CIDR{"169.254.169.254/32"},
+							},
+						},
+					},
+				},
+			},
+		},
+		// Test data 1: mocks a pre-existing object in the data store that is
+		// updated during the mark-and-sweep synchronization test because its
+		// counterpart in the K8s cache has changed.
+		{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:            "isolate-sensitive",
+				SelfLink:        "/apis/policy.networking.k8s.io/v1alpha1/adminnetworkpolicies/isolate-sensitive",
+				UID:             "0c9b4f14-6f5b-4d61-8a4e-7b7a0d2f3e02",
+				ResourceVersion: "112234",
+				Generation:      1,
+				CreationTimestamp: metaV1.Date(2019, 11, 14, 18, 53, 37, 0,
+					time.FixedZone("PST", -800)),
+			},
+			Spec: anpV1alpha1.AdminNetworkPolicySpec{
+				Priority: 5,
+				Subject: anpV1alpha1.AdminNetworkPolicySubject{
+					Pods: &anpV1alpha1.NamespacedPod{
+						PodSelector: metaV1.LabelSelector{
+							MatchLabels: map[string]string{"sensitive": "true"},
+						},
+					},
+				},
+				Ingress: []anpV1alpha1.AdminNetworkPolicyIngressRule{
+					{
+						Action: anpV1alpha1.AdminNetworkPolicyRuleActionDeny,
+						From: []anpV1alpha1.AdminNetworkPolicyIngressPeer{
+							{
+								Namespaces: &metaV1.LabelSelector{},
+							},
+						},
+					},
+				},
+			},
+		},
+		// Test data 2: mocks a pre-existing "stale" object in the data store
+		// that is deleted during the mark-and-sweep synchronization test
+		// because its counterpart no longer exists in the K8s cache.
+		{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:            "deny-all-egress",
+				SelfLink:        "/apis/policy.networking.k8s.io/v1alpha1/adminnetworkpolicies/deny-all-egress",
+				UID:             "5d2a7c36-3c7e-4b8e-a1e4-9a4d3c2b1f03",
+				ResourceVersion: "112235",
+				Generation:      1,
+				CreationTimestamp: metaV1.Date(2019, 11, 14, 18, 53, 37, 0,
+					time.FixedZone("PST", -800)),
+			},
+			Spec: anpV1alpha1.AdminNetworkPolicySpec{
+				Priority: 999,
+				Subject: anpV1alpha1.AdminNetworkPolicySubject{
+					Namespaces: &metaV1.LabelSelector{},
+				},
+				Egress: []anpV1alpha1.AdminNetworkPolicyEgressRule{
+					{
+						Action: anpV1alpha1.AdminNetworkPolicyRuleActionDeny,
+						To: []anpV1alpha1.AdminNetworkPolicyEgressPeer{
+							{
+								Networks: []anpV1alpha1.CIDR{"0.0.0.0/0"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// The mock function returns two K8s mock admin policy instances:
+	// - a new admin policy instance to be added to the data store
+	// - a modified admin policy instance, where and existing instance in the
+	//   data store is to be updated
+	MockK8sCache.ListFunc = func() []interface{} {
+		return []interface{}{
+			// Updated value mock
+			&adminPolicyTestVars.adminPolicyTestData[0],
+			// New value mock
+			&adminPolicyTestVars.adminPolicyTestData[1],
+		}
+	}
+
+	// Pre-populate the mock data store with pre-existing data that is supposed
+	// to be updated during resync.
+	k8sPolicy1 := &adminPolicyTestVars.adminPolicyTestData[1]
+	protoPolicy1 := adminPolicyTestVars.adminPolicyReflector.adminPolicyToProto(k8sPolicy1)
+	checkAdminPolicyToProtoTranslation(t, protoPolicy1, k8sPolicy1)
+
+	protoPolicy1.Priority = 500
+	adminPolicyTestVars.mockKvBroker.Put(adminpolicy.Key(k8sPolicy1.GetName()), protoPolicy1)
+
+	// Pre-populate the mock data store with "stale" data that is supposed to
+	// be deleted during resync.
+	k8sPolicy2 := &adminPolicyTestVars.adminPolicyTestData[2]
+	protoPolicy2 := adminPolicyTestVars.adminPolicyReflector.adminPolicyToProto(k8sPolicy2)
+	checkAdminPolicyToProtoTranslation(t, protoPolicy2, k8sPolicy2)
+
+	adminPolicyTestVars.mockKvBroker.Put(adminpolicy.Key(k8sPolicy2.GetName()), protoPolicy2)
+
+	statsBefore := *adminPolicyTestVars.adminPolicyReflector.GetStats()
+
+	stopCh := make(chan struct{})
+	var wg sync.WaitGroup
+	err := adminPolicyTestVars.adminPolicyReflector.Init(stopCh, &wg)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	adminPolicyTestVars.adminPolicyReflector.startDataStoreResync()
+
+	// Wait for the initial sync to finish
+	for {
+		if adminPolicyTestVars.adminPolicyReflector.HasSynced() {
+			break
+		}
+		time.Sleep(time.Millisecond * 100)
+	}
+
+	statsAfter := *adminPolicyTestVars.adminPolicyReflector.GetStats()
+
+	gomega.Expect(adminPolicyTestVars.mockKvBroker.ds).Should(gomega.HaveLen(2))
+	gomega.Expect(statsBefore.Adds + 1).Should(gomega.BeNumerically("==", statsAfter.Adds))
+	gomega.Expect(statsBefore.Updates + 1).Should(gomega.BeNumerically("==", statsAfter.Updates))
+	gomega.Expect(statsBefore.Deletes + 1).Should(gomega.BeNumerically("==", statsAfter.Deletes))
+
+	adminPolicyTestVars.mockKvBroker.ClearDs()
+	t.Run("addDeleteAdminPolicy", testAddDeleteAdminPolicy)
+
+	adminPolicyTestVars.mockKvBroker.ClearDs()
+	t.Run("updateAdminPolicy", testUpdateAdminPolicy)
+}
+
+func testAddDeleteAdminPolicy(t *testing.T) {
+	// Test the admin policy add operation
+	for _, k8sPolicy := range adminPolicyTestVars.adminPolicyTestData {
+		// Take a snapshot of counters
+		adds := adminPolicyTestVars.adminPolicyReflector.GetStats().Adds
+		argErrs := adminPolicyTestVars.adminPolicyReflector.GetStats().ArgErrors
+
+		// Test add with wrong argument type
+		adminPolicyTestVars.k8sListWatch.Add(k8sPolicy)
+
+		gomega.Expect(argErrs + 1).To(gomega.Equal(adminPolicyTestVars.adminPolicyReflector.GetStats().ArgErrors))
+		gomega.Expect(adds).To(gomega.Equal(adminPolicyTestVars.adminPolicyReflector.GetStats().Adds))
+
+		// Test add where everything should be good
+		adminPolicyTestVars.k8sListWatch.Add(&k8sPolicy)
+
+		key := adminpolicy.Key(k8sPolicy.GetName())
+		protoPolicy := &adminpolicy.AdminPolicy{}
+		found, _, err := adminPolicyTestVars.mockKvBroker.GetValue(key, protoPolicy)
+
+		gomega.Expect(found).To(gomega.BeTrue())
+		gomega.Expect(err).To(gomega.BeNil())
+		gomega.Expect(adds + 1).To(gomega.Equal(adminPolicyTestVars.adminPolicyReflector.GetStats().Adds))
+
+		checkAdminPolicyToProtoTranslation(t, protoPolicy, &k8sPolicy)
+	}
+
+	// Test the admin policy delete operation
+	for _, k8sPolicy := range adminPolicyTestVars.adminPolicyTestData {
+		// Take a snapshot of counters
+		dels := adminPolicyTestVars.adminPolicyReflector.GetStats().Deletes
+		argErrs := adminPolicyTestVars.adminPolicyReflector.GetStats().ArgErrors
+
+		// Test delete with wrong argument type
+		adminPolicyTestVars.k8sListWatch.Delete(k8sPolicy)
+
+		gomega.Expect(argErrs + 1).To(gomega.Equal(adminPolicyTestVars.adminPolicyReflector.GetStats().ArgErrors))
+		gomega.Expect(dels).To(gomega.Equal(adminPolicyTestVars.adminPolicyReflector.GetStats().Deletes))
+
+		// Test delete where everything should be good
+		adminPolicyTestVars.k8sListWatch.Delete(&k8sPolicy)
+		gomega.Expect(dels + 1).To(gomega.Equal(adminPolicyTestVars.adminPolicyReflector.GetStats().Deletes))
+
+		key := adminpolicy.Key(k8sPolicy.GetName())
+		protoPolicy := &adminpolicy.AdminPolicy{}
+		found, _, err := adminPolicyTestVars.mockKvBroker.GetValue(key, protoPolicy)
+		gomega.Expect(found).To(gomega.BeFalse())
+		gomega.Ω(err).Should(gomega.Succeed())
+	}
+}
+
+func testUpdateAdminPolicy(t *testing.T) {
+	// Prepare test data
+	k8sPolicyOld := &adminPolicyTestVars.adminPolicyTestData[1]
+	k8sPolicyNew := k8sPolicyOld.DeepCopy()
+
+	// Take a snapshot of counters
+	upds := adminPolicyTestVars.adminPolicyReflector.GetStats().Updates
+	argErrs := adminPolicyTestVars.adminPolicyReflector.GetStats().ArgErrors
+
+	// Test update with wrong argument type
+	adminPolicyTestVars.k8sListWatch.Update(*k8sPolicyOld, *k8sPolicyNew)
+
+	gomega.Expect(argErrs + 1).To(gomega.Equal(adminPolicyTestVars.adminPolicyReflector.GetStats().ArgErrors))
+	gomega.Expect(upds).To(gomega.Equal(adminPolicyTestVars.adminPolicyReflector.GetStats().Updates))
+
+	// Ensure that there is no update if old and new values are the same
+	adminPolicyTestVars.k8sListWatch.Update(k8sPolicyOld, k8sPolicyNew)
+	gomega.Expect(upds).To(gomega.Equal(adminPolicyTestVars.adminPolicyReflector.GetStats().Updates))
+
+	// Test update where everything should be good
+	k8sPolicyNew.Spec.Priority = 1
+	k8sPolicyNew.Spec.Ingress[0].Action = anpV1alpha1.AdminNetworkPolicyRuleActionPass
+
+	adminPolicyTestVars.k8sListWatch.Update(k8sPolicyOld, k8sPolicyNew)
+	gomega.Expect(upds + 1).To(gomega.Equal(adminPolicyTestVars.adminPolicyReflector.GetStats().Updates))
+
+	key := adminpolicy.Key(k8sPolicyOld.GetName())
+	protoPolicyNew := &adminpolicy.AdminPolicy{}
+	found, _, err := adminPolicyTestVars.mockKvBroker.GetValue(key, protoPolicyNew)
+
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Ω(err).Should(gomega.Succeed())
+
+	checkAdminPolicyToProtoTranslation(t, protoPolicyNew, k8sPolicyNew)
+}
+
+// checkAdminPolicyToProtoTranslation checks whether the translation of K8s
+// admin network policy into the Contiv-VPP protobuf format is correct.
+func checkAdminPolicyToProtoTranslation(t *testing.T, protoAnp *adminpolicy.AdminPolicy,
+	k8sAnp *anpV1alpha1.AdminNetworkPolicy) {
+
+	gomega.Expect(protoAnp.Name).To(gomega.Equal(k8sAnp.GetName()))
+	gomega.Expect(protoAnp.Priority).To(gomega.Equal(k8sAnp.Spec.Priority))
+
+	// Check subject
+	gomega.Expect(protoAnp.Subject).ToNot(gomega.BeNil())
+	if k8sAnp.Spec.Subject.Pods != nil {
+		checkAdminPodSelector(protoAnp.Subject.Pods, k8sAnp.Spec.Subject.Pods)
+		gomega.Expect(protoAnp.Subject.Namespaces).To(gomega.BeNil())
+	} else {
+		checkAdminLabelSelector(protoAnp.Subject.Namespaces, k8sAnp.Spec.Subject.Namespaces)
+		gomega.Expect(protoAnp.Subject.Pods).To(gomega.BeNil())
+	}
+
+	// Check ingress rules
+	gomega.Expect(protoAnp.IngressRule).To(gomega.HaveLen(len(k8sAnp.Spec.Ingress)))
+	for i, rule := range protoAnp.IngressRule {
+		k8sRule := k8sAnp.Spec.Ingress[i]
+		gomega.Expect(rule.Name).To(gomega.Equal(k8sRule.Name))
+		gomega.Expect(rule.Action.String()).To(gomega.Equal(strings.ToUpper(string(k8sRule.Action))))
+		checkAdminRulePorts(rule.Ports, k8sRule.Ports)
+		gomega.Expect(rule.Peers).To(gomega.HaveLen(len(k8sRule.From)))
+		for j, peer := range rule.Peers {
+			if k8sRule.From[j].Pods != nil {
+				checkAdminPodSelector(peer.Pods, k8sRule.From[j].Pods)
+			} else {
+				checkAdminLabelSelector(peer.Namespaces, k8sRule.From[j].Namespaces)
+			}
+		}
+	}
+
+	// Check egress rules
+	gomega.Expect(protoAnp.EgressRule).To(gomega.HaveLen(len(k8sAnp.Spec.Egress)))
+	for i, rule := range protoAnp.EgressRule {
+		k8sRule := k8sAnp.Spec.Egress[i]
+		gomega.Expect(rule.Name).To(gomega.Equal(k8sRule.Name))
+		gomega.Expect(rule.Action.String()).To(gomega.Equal(strings.ToUpper(string(k8sRule.Action))))
+		checkAdminRulePorts(rule.Ports, k8sRule.Ports)
+		gomega.Expect(rule.Peers).To(gomega.HaveLen(len(k8sRule.To)))
+		for j, peer := range rule.Peers {
+			switch {
+			case k8sRule.To[j].Pods != nil:
+				checkAdminPodSelector(peer.Pods, k8sRule.To[j].Pods)
+			case k8sRule.To[j].Namespaces != nil:
+				checkAdminLabelSelector(peer.Namespaces, k8sRule.To[j].Namespaces)
+			default:
+				gomega.Expect(peer.Networks).To(gomega.HaveLen(len(k8sRule.To[j].Networks)))
+				for k, network := range peer.Networks {
+					gomega.Expect(network).To(gomega.BeEquivalentTo(k8sRule.To[j].Networks[k]))
+				}
+			}
+		}
+	}
+}
+
+// checkAdminPodSelector checks whether the translation of namespaced pod
+// selector into the Contiv-VPP protobuf format is correct.
+func checkAdminPodSelector(protoSel *adminpolicy.AdminPolicy_PodSelector, k8sSel *anpV1alpha1.NamespacedPod) {
+	gomega.Expect(protoSel).ToNot(gomega.BeNil())
+	checkAdminLabelSelector(protoSel.Namespaces, &k8sSel.NamespaceSelector)
+	checkAdminLabelSelector(protoSel.Pods, &k8sSel.PodSelector)
+}
+
+// checkAdminLabelSelector checks whether the translation of K8s label selector
+// into the Contiv-VPP protobuf format is correct.
+func checkAdminLabelSelector(protoLbl *adminpolicy.AdminPolicy_LabelSelector, k8sLbl *metaV1.LabelSelector) {
+	gomega.Expect(protoLbl).ToNot(gomega.BeNil())
+	gomega.Expect(len(protoLbl.MatchLabel)).To(gomega.Equal(len(k8sLbl.MatchLabels)))
+	for _, lbl := range protoLbl.MatchLabel {
+		gomega.Expect(lbl.Value).To(gomega.Equal(k8sLbl.MatchLabels[lbl.Key]))
+	}
+
+	gomega.Expect(len(protoLbl.MatchExpression)).To(gomega.Equal(len(k8sLbl.MatchExpressions)))
+	for i, expr := range protoLbl.MatchExpression {
+		k8sExpr := k8sLbl.MatchExpressions[i]
+		gomega.Expect(expr.Key).To(gomega.Equal(k8sExpr.Key))
+		gomega.Expect(expr.Value).To(gomega.BeEquivalentTo(k8sExpr.Values))
+	}
+}
+
+// checkAdminRulePorts checks whether the translation of K8s admin policy rule
+// ports into the Contiv-VPP protobuf format is correct.
+func checkAdminRulePorts(protoPorts []*adminpolicy.AdminPolicy_Port, k8sPorts *[]anpV1alpha1.AdminNetworkPolicyPort) {
+	if k8sPorts == nil {
+		gomega.Expect(protoPorts).To(gomega.BeEmpty())
+		return
+	}
+	gomega.Expect(protoPorts).To(gomega.HaveLen(len(*k8sPorts)))
+	for j, protoPort := range protoPorts {
+		k8sPort := (*k8sPorts)[j]
+		var protocol coreV1.Protocol
+		if k8sPort.PortNumber != nil {
+			protocol = k8sPort.PortNumber.Protocol
+			gomega.Expect(protoPort.Port).To(gomega.Equal(k8sPort.PortNumber.Port))
+			gomega.Expect(protoPort.EndPort).To(gomega.BeZero())
+		} else {
+			protocol = k8sPort.PortRange.Protocol
+			gomega.Expect(protoPort.Port).To(gomega.Equal(k8sPort.PortRange.Start))
+			gomega.Expect(protoPort.EndPort).To(gomega.Equal(k8sPort.PortRange.End))
+		}
+		if protocol == "" {
+			gomega.Expect(protoPort.Protocol).To(gomega.Equal(adminpolicy.AdminPolicy_Port_TCP))
+		} else {
+			gomega.Expect(protoPort.Protocol.String()).To(gomega.BeEquivalentTo(protocol))
+		}
+	}
+}
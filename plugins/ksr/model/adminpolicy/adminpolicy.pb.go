@@ -0,0 +1,707 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: adminpolicy.proto
+
+// Package adminpolicy defines data model for Kubernetes Admin Network Policy.
+
+package adminpolicy
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Action to take for the traffic matched by a rule.
+type AdminPolicy_Action int32
+
+const (
+	// Allow the traffic regardless of the namespaced policies.
+	AdminPolicy_ALLOW AdminPolicy_Action = 0
+	// Deny the traffic regardless of the namespaced policies.
+	AdminPolicy_DENY AdminPolicy_Action = 1
+	// Skip the remaining admin policy rules and let the namespaced policies
+	// decide.
+	AdminPolicy_PASS AdminPolicy_Action = 2
+)
+
+var AdminPolicy_Action_name = map[int32]string{
+	0: "ALLOW",
+	1: "DENY",
+	2: "PASS",
+}
+
+var AdminPolicy_Action_value = map[string]int32{
+	"ALLOW": 0,
+	"DENY":  1,
+	"PASS":  2,
+}
+
+func (x AdminPolicy_Action) String() string {
+	return proto.EnumName(AdminPolicy_Action_name, int32(x))
+}
+
+func (AdminPolicy_Action) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_2160b3193e13f5a7, []int{0, 0}
+}
+
+// Operator represents a key's relationship to a set of values.
+type AdminPolicy_LabelSelector_LabelExpression_Operator int32
+
+const (
+	AdminPolicy_LabelSelector_LabelExpression_IN             AdminPolicy_LabelSelector_LabelExpression_Operator = 0
+	AdminPolicy_LabelSelector_LabelExpression_NOT_IN         AdminPolicy_LabelSelector_LabelExpression_Operator = 1
+	AdminPolicy_LabelSelector_LabelExpression_EXISTS         AdminPolicy_LabelSelector_LabelExpression_Operator = 2
+	AdminPolicy_LabelSelector_LabelExpression_DOES_NOT_EXIST AdminPolicy_LabelSelector_LabelExpression_Operator = 3
+)
+
+var AdminPolicy_LabelSelector_LabelExpression_Operator_name = map[int32]string{
+	0: "IN",
+	1: "NOT_IN",
+	2: "EXISTS",
+	3: "DOES_NOT_EXIST",
+}
+
+var AdminPolicy_LabelSelector_LabelExpression_Operator_value = map[string]int32{
+	"IN":             0,
+	"NOT_IN":         1,
+	"EXISTS":         2,
+	"DOES_NOT_EXIST": 3,
+}
+
+func (x AdminPolicy_LabelSelector_LabelExpression_Operator) String() string {
+	return proto.EnumName(AdminPolicy_LabelSelector_LabelExpression_Operator_name, int32(x))
+}
+
+func (AdminPolicy_LabelSelector_LabelExpression_Operator) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_2160b3193e13f5a7, []int{0, 1, 0, 0}
+}
+
+// The protocol (TCP, UDP or SCTP) which traffic must match.
+type AdminPolicy_Port_Protocol int32
+
+const (
+	AdminPolicy_Port_TCP  AdminPolicy_Port_Protocol = 0
+	AdminPolicy_Port_UDP  AdminPolicy_Port_Protocol = 1
+	AdminPolicy_Port_SCTP AdminPolicy_Port_Protocol = 2
+)
+
+var AdminPolicy_Port_Protocol_name = map[int32]string{
+	0: "TCP",
+	1: "UDP",
+	2: "SCTP",
+}
+
+var AdminPolicy_Port_Protocol_value = map[string]int32{
+	"TCP":  0,
+	"UDP":  1,
+	"SCTP": 2,
+}
+
+func (x AdminPolicy_Port_Protocol) String() string {
+	return proto.EnumName(AdminPolicy_Port_Protocol_name, int32(x))
+}
+
+func (AdminPolicy_Port_Protocol) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_2160b3193e13f5a7, []int{0, 5, 0}
+}
+
+// AdminPolicy is a cluster-scoped network policy with explicit actions,
+// evaluated before namespaced network policies.
+type AdminPolicy struct {
+	// Name of the admin policy unique within the cluster.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Priority of the policy from the range <0, 1000>.
+	// Policies with lower values are evaluated first.
+	Priority int32                `protobuf:"varint,2,opt,name=priority,proto3" json:"priority,omitempty"`
+	Subject  *AdminPolicy_Subject `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
+	// List of ingress rules applied to the selected pods, evaluated in the given order.
+	IngressRule []*AdminPolicy_Rule `protobuf:"bytes,4,rep,name=ingress_rule,json=ingressRule,proto3" json:"ingress_rule,omitempty"`
+	// List of egress rules applied to the selected pods, evaluated in the given order.
+	EgressRule           []*AdminPolicy_Rule `protobuf:"bytes,5,rep,name=egress_rule,json=egressRule,proto3" json:"egress_rule,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *AdminPolicy) Reset()         { *m = AdminPolicy{} }
+func (m *AdminPolicy) String() string { return proto.CompactTextString(m) }
+func (*AdminPolicy) ProtoMessage()    {}
+func (*AdminPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2160b3193e13f5a7, []int{0}
+}
+
+func (m *AdminPolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdminPolicy.Unmarshal(m, b)
+}
+func (m *AdminPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdminPolicy.Marshal(b, m, deterministic)
+}
+func (m *AdminPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdminPolicy.Merge(m, src)
+}
+func (m *AdminPolicy) XXX_Size() int {
+	return xxx_messageInfo_AdminPolicy.Size(m)
+}
+func (m *AdminPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdminPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdminPolicy proto.InternalMessageInfo
+
+func (m *AdminPolicy) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AdminPolicy) GetPriority() int32 {
+	if m != nil {
+		return m.Priority
+	}
+	return 0
+}
+
+func (m *AdminPolicy) GetSubject() *AdminPolicy_Subject {
+	if m != nil {
+		return m.Subject
+	}
+	return nil
+}
+
+func (m *AdminPolicy) GetIngressRule() []*AdminPolicy_Rule {
+	if m != nil {
+		return m.IngressRule
+	}
+	return nil
+}
+
+func (m *AdminPolicy) GetEgressRule() []*AdminPolicy_Rule {
+	if m != nil {
+		return m.EgressRule
+	}
+	return nil
+}
+
+// Label is a key/value pair attached to an object.
+type AdminPolicy_Label struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AdminPolicy_Label) Reset()         { *m = AdminPolicy_Label{} }
+func (m *AdminPolicy_Label) String() string { return proto.CompactTextString(m) }
+func (*AdminPolicy_Label) ProtoMessage()    {}
+func (*AdminPolicy_Label) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2160b3193e13f5a7, []int{0, 0}
+}
+
+func (m *AdminPolicy_Label) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdminPolicy_Label.Unmarshal(m, b)
+}
+func (m *AdminPolicy_Label) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdminPolicy_Label.Marshal(b, m, deterministic)
+}
+func (m *AdminPolicy_Label) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdminPolicy_Label.Merge(m, src)
+}
+func (m *AdminPolicy_Label) XXX_Size() int {
+	return xxx_messageInfo_AdminPolicy_Label.Size(m)
+}
+func (m *AdminPolicy_Label) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdminPolicy_Label.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdminPolicy_Label proto.InternalMessageInfo
+
+func (m *AdminPolicy_Label) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *AdminPolicy_Label) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+// A label selector is a label query over a set of resources.
+// The result of match_label-s and match_expression-s are ANDed.
+// An empty label selector matches all objects.
+type AdminPolicy_LabelSelector struct {
+	// A list of labels that a resource needs to have attached in order to get
+	// selected.
+	MatchLabel []*AdminPolicy_Label `protobuf:"bytes,1,rep,name=match_label,json=matchLabel,proto3" json:"match_label,omitempty"`
+	// A list of key-value expressions applied to labels.
+	MatchExpression      []*AdminPolicy_LabelSelector_LabelExpression `protobuf:"bytes,2,rep,name=match_expression,json=matchExpression,proto3" json:"match_expression,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                                     `json:"-"`
+	XXX_unrecognized     []byte                                       `json:"-"`
+	XXX_sizecache        int32                                        `json:"-"`
+}
+
+func (m *AdminPolicy_LabelSelector) Reset()         { *m = AdminPolicy_LabelSelector{} }
+func (m *AdminPolicy_LabelSelector) String() string { return proto.CompactTextString(m) }
+func (*AdminPolicy_LabelSelector) ProtoMessage()    {}
+func (*AdminPolicy_LabelSelector) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2160b3193e13f5a7, []int{0, 1}
+}
+
+func (m *AdminPolicy_LabelSelector) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdminPolicy_LabelSelector.Unmarshal(m, b)
+}
+func (m *AdminPolicy_LabelSelector) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdminPolicy_LabelSelector.Marshal(b, m, deterministic)
+}
+func (m *AdminPolicy_LabelSelector) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdminPolicy_LabelSelector.Merge(m, src)
+}
+func (m *AdminPolicy_LabelSelector) XXX_Size() int {
+	return xxx_messageInfo_AdminPolicy_LabelSelector.Size(m)
+}
+func (m *AdminPolicy_LabelSelector) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdminPolicy_LabelSelector.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdminPolicy_LabelSelector proto.InternalMessageInfo
+
+func (m *AdminPolicy_LabelSelector) GetMatchLabel() []*AdminPolicy_Label {
+	if m != nil {
+		return m.MatchLabel
+	}
+	return nil
+}
+
+func (m *AdminPolicy_LabelSelector) GetMatchExpression() []*AdminPolicy_LabelSelector_LabelExpression {
+	if m != nil {
+		return m.MatchExpression
+	}
+	return nil
+}
+
+// An expression that contains values, a label key, and an operator that
+// relates the key and values.
+type AdminPolicy_LabelSelector_LabelExpression struct {
+	// Key is the label key that the expression applies to.
+	Key      string                                             `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Operator AdminPolicy_LabelSelector_LabelExpression_Operator `protobuf:"varint,2,opt,name=operator,proto3,enum=adminpolicy.AdminPolicy_LabelSelector_LabelExpression_Operator" json:"operator,omitempty"`
+	// An array of string values.
+	Value                []string `protobuf:"bytes,3,rep,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AdminPolicy_LabelSelector_LabelExpression) Reset() {
+	*m = AdminPolicy_LabelSelector_LabelExpression{}
+}
+func (m *AdminPolicy_LabelSelector_LabelExpression) String() string {
+	return proto.CompactTextString(m)
+}
+func (*AdminPolicy_LabelSelector_LabelExpression) ProtoMessage() {}
+func (*AdminPolicy_LabelSelector_LabelExpression) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2160b3193e13f5a7, []int{0, 1, 0}
+}
+
+func (m *AdminPolicy_LabelSelector_LabelExpression) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdminPolicy_LabelSelector_LabelExpression.Unmarshal(m, b)
+}
+func (m *AdminPolicy_LabelSelector_LabelExpression) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdminPolicy_LabelSelector_LabelExpression.Marshal(b, m, deterministic)
+}
+func (m *AdminPolicy_LabelSelector_LabelExpression) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdminPolicy_LabelSelector_LabelExpression.Merge(m, src)
+}
+func (m *AdminPolicy_LabelSelector_LabelExpression) XXX_Size() int {
+	return xxx_messageInfo_AdminPolicy_LabelSelector_LabelExpression.Size(m)
+}
+func (m *AdminPolicy_LabelSelector_LabelExpression) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdminPolicy_LabelSelector_LabelExpression.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdminPolicy_LabelSelector_LabelExpression proto.InternalMessageInfo
+
+func (m *AdminPolicy_LabelSelector_LabelExpression) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *AdminPolicy_LabelSelector_LabelExpression) GetOperator() AdminPolicy_LabelSelector_LabelExpression_Operator {
+	if m != nil {
+		return m.Operator
+	}
+	return AdminPolicy_LabelSelector_LabelExpression_IN
+}
+
+func (m *AdminPolicy_LabelSelector_LabelExpression) GetValue() []string {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+// PodSelector selects pods by labels inside namespaces selected by labels.
+type AdminPolicy_PodSelector struct {
+	Namespaces           *AdminPolicy_LabelSelector `protobuf:"bytes,1,opt,name=namespaces,proto3" json:"namespaces,omitempty"`
+	Pods                 *AdminPolicy_LabelSelector `protobuf:"bytes,2,opt,name=pods,proto3" json:"pods,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *AdminPolicy_PodSelector) Reset()         { *m = AdminPolicy_PodSelector{} }
+func (m *AdminPolicy_PodSelector) String() string { return proto.CompactTextString(m) }
+func (*AdminPolicy_PodSelector) ProtoMessage()    {}
+func (*AdminPolicy_PodSelector) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2160b3193e13f5a7, []int{0, 2}
+}
+
+func (m *AdminPolicy_PodSelector) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdminPolicy_PodSelector.Unmarshal(m, b)
+}
+func (m *AdminPolicy_PodSelector) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdminPolicy_PodSelector.Marshal(b, m, deterministic)
+}
+func (m *AdminPolicy_PodSelector) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdminPolicy_PodSelector.Merge(m, src)
+}
+func (m *AdminPolicy_PodSelector) XXX_Size() int {
+	return xxx_messageInfo_AdminPolicy_PodSelector.Size(m)
+}
+func (m *AdminPolicy_PodSelector) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdminPolicy_PodSelector.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdminPolicy_PodSelector proto.InternalMessageInfo
+
+func (m *AdminPolicy_PodSelector) GetNamespaces() *AdminPolicy_LabelSelector {
+	if m != nil {
+		return m.Namespaces
+	}
+	return nil
+}
+
+func (m *AdminPolicy_PodSelector) GetPods() *AdminPolicy_LabelSelector {
+	if m != nil {
+		return m.Pods
+	}
+	return nil
+}
+
+// Subject selects pods the policy applies to.
+// Exactly one of the fields is specified.
+type AdminPolicy_Subject struct {
+	// All pods from the selected namespaces.
+	Namespaces *AdminPolicy_LabelSelector `protobuf:"bytes,1,opt,name=namespaces,proto3" json:"namespaces,omitempty"`
+	// Pods selected by labels in the selected namespaces.
+	Pods                 *AdminPolicy_PodSelector `protobuf:"bytes,2,opt,name=pods,proto3" json:"pods,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *AdminPolicy_Subject) Reset()         { *m = AdminPolicy_Subject{} }
+func (m *AdminPolicy_Subject) String() string { return proto.CompactTextString(m) }
+func (*AdminPolicy_Subject) ProtoMessage()    {}
+func (*AdminPolicy_Subject) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2160b3193e13f5a7, []int{0, 3}
+}
+
+func (m *AdminPolicy_Subject) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdminPolicy_Subject.Unmarshal(m, b)
+}
+func (m *AdminPolicy_Subject) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdminPolicy_Subject.Marshal(b, m, deterministic)
+}
+func (m *AdminPolicy_Subject) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdminPolicy_Subject.Merge(m, src)
+}
+func (m *AdminPolicy_Subject) XXX_Size() int {
+	return xxx_messageInfo_AdminPolicy_Subject.Size(m)
+}
+func (m *AdminPolicy_Subject) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdminPolicy_Subject.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdminPolicy_Subject proto.InternalMessageInfo
+
+func (m *AdminPolicy_Subject) GetNamespaces() *AdminPolicy_LabelSelector {
+	if m != nil {
+		return m.Namespaces
+	}
+	return nil
+}
+
+func (m *AdminPolicy_Subject) GetPods() *AdminPolicy_PodSelector {
+	if m != nil {
+		return m.Pods
+	}
+	return nil
+}
+
+// Peer selects a source (ingress) or a destination (egress) of the traffic.
+// Exactly one of the fields is specified.
+type AdminPolicy_Peer struct {
+	// All pods from the selected namespaces.
+	Namespaces *AdminPolicy_LabelSelector `protobuf:"bytes,1,opt,name=namespaces,proto3" json:"namespaces,omitempty"`
+	// Pods selected by labels in the selected namespaces.
+	Pods *AdminPolicy_PodSelector `protobuf:"bytes,2,opt,name=pods,proto3" json:"pods,omitempty"`
+	// IP networks in the CIDR notation (egress only).
+	Networks             []string `protobuf:"bytes,3,rep,name=networks,proto3" json:"networks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AdminPolicy_Peer) Reset()         { *m = AdminPolicy_Peer{} }
+func (m *AdminPolicy_Peer) String() string { return proto.CompactTextString(m) }
+func (*AdminPolicy_Peer) ProtoMessage()    {}
+func (*AdminPolicy_Peer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2160b3193e13f5a7, []int{0, 4}
+}
+
+func (m *AdminPolicy_Peer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdminPolicy_Peer.Unmarshal(m, b)
+}
+func (m *AdminPolicy_Peer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdminPolicy_Peer.Marshal(b, m, deterministic)
+}
+func (m *AdminPolicy_Peer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdminPolicy_Peer.Merge(m, src)
+}
+func (m *AdminPolicy_Peer) XXX_Size() int {
+	return xxx_messageInfo_AdminPolicy_Peer.Size(m)
+}
+func (m *AdminPolicy_Peer) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdminPolicy_Peer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdminPolicy_Peer proto.InternalMessageInfo
+
+func (m *AdminPolicy_Peer) GetNamespaces() *AdminPolicy_LabelSelector {
+	if m != nil {
+		return m.Namespaces
+	}
+	return nil
+}
+
+func (m *AdminPolicy_Peer) GetPods() *AdminPolicy_PodSelector {
+	if m != nil {
+		return m.Pods
+	}
+	return nil
+}
+
+func (m *AdminPolicy_Peer) GetNetworks() []string {
+	if m != nil {
+		return m.Networks
+	}
+	return nil
+}
+
+// A port selector.
+type AdminPolicy_Port struct {
+	Protocol AdminPolicy_Port_Protocol `protobuf:"varint,1,opt,name=protocol,proto3,enum=adminpolicy.AdminPolicy_Port_Protocol" json:"protocol,omitempty"`
+	// Port number from the range: 0 < x < 65536.
+	Port int32 `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	// If set, indicates that the range of ports from port to end_port, inclusive,
+	// is selected.
+	EndPort              int32    `protobuf:"varint,3,opt,name=end_port,json=endPort,proto3" json:"end_port,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AdminPolicy_Port) Reset()         { *m = AdminPolicy_Port{} }
+func (m *AdminPolicy_Port) String() string { return proto.CompactTextString(m) }
+func (*AdminPolicy_Port) ProtoMessage()    {}
+func (*AdminPolicy_Port) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2160b3193e13f5a7, []int{0, 5}
+}
+
+func (m *AdminPolicy_Port) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdminPolicy_Port.Unmarshal(m, b)
+}
+func (m *AdminPolicy_Port) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdminPolicy_Port.Marshal(b, m, deterministic)
+}
+func (m *AdminPolicy_Port) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdminPolicy_Port.Merge(m, src)
+}
+func (m *AdminPolicy_Port) XXX_Size() int {
+	return xxx_messageInfo_AdminPolicy_Port.Size(m)
+}
+func (m *AdminPolicy_Port) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdminPolicy_Port.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdminPolicy_Port proto.InternalMessageInfo
+
+func (m *AdminPolicy_Port) GetProtocol() AdminPolicy_Port_Protocol {
+	if m != nil {
+		return m.Protocol
+	}
+	return AdminPolicy_Port_TCP
+}
+
+func (m *AdminPolicy_Port) GetPort() int32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *AdminPolicy_Port) GetEndPort() int32 {
+	if m != nil {
+		return m.EndPort
+	}
+	return 0
+}
+
+// Rule matches traffic if and only if the traffic matches both port-s
+// AND peer-s.
+type AdminPolicy_Rule struct {
+	// Name of the rule (optional).
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Action to take for the matched traffic.
+	Action AdminPolicy_Action `protobuf:"varint,2,opt,name=action,proto3,enum=adminpolicy.AdminPolicy_Action" json:"action,omitempty"`
+	// List of peers (combined with logical OR).
+	Peers []*AdminPolicy_Peer `protobuf:"bytes,3,rep,name=peers,proto3" json:"peers,omitempty"`
+	// List of destination ports (combined with logical OR).
+	// If the array is empty, the rule matches all ports.
+	Ports                []*AdminPolicy_Port `protobuf:"bytes,4,rep,name=ports,proto3" json:"ports,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *AdminPolicy_Rule) Reset()         { *m = AdminPolicy_Rule{} }
+func (m *AdminPolicy_Rule) String() string { return proto.CompactTextString(m) }
+func (*AdminPolicy_Rule) ProtoMessage()    {}
+func (*AdminPolicy_Rule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2160b3193e13f5a7, []int{0, 6}
+}
+
+func (m *AdminPolicy_Rule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdminPolicy_Rule.Unmarshal(m, b)
+}
+func (m *AdminPolicy_Rule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdminPolicy_Rule.Marshal(b, m, deterministic)
+}
+func (m *AdminPolicy_Rule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdminPolicy_Rule.Merge(m, src)
+}
+func (m *AdminPolicy_Rule) XXX_Size() int {
+	return xxx_messageInfo_AdminPolicy_Rule.Size(m)
+}
+func (m *AdminPolicy_Rule) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdminPolicy_Rule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdminPolicy_Rule proto.InternalMessageInfo
+
+func (m *AdminPolicy_Rule) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AdminPolicy_Rule) GetAction() AdminPolicy_Action {
+	if m != nil {
+		return m.Action
+	}
+	return AdminPolicy_ALLOW
+}
+
+func (m *AdminPolicy_Rule) GetPeers() []*AdminPolicy_Peer {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+func (m *AdminPolicy_Rule) GetPorts() []*AdminPolicy_Port {
+	if m != nil {
+		return m.Ports
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("adminpolicy.AdminPolicy_Action", AdminPolicy_Action_name, AdminPolicy_Action_value)
+	proto.RegisterEnum("adminpolicy.AdminPolicy_LabelSelector_LabelExpression_Operator", AdminPolicy_LabelSelector_LabelExpression_Operator_name, AdminPolicy_LabelSelector_LabelExpression_Operator_value)
+	proto.RegisterEnum("adminpolicy.AdminPolicy_Port_Protocol", AdminPolicy_Port_Protocol_name, AdminPolicy_Port_Protocol_value)
+	proto.RegisterType((*AdminPolicy)(nil), "adminpolicy.AdminPolicy")
+	proto.RegisterType((*AdminPolicy_Label)(nil), "adminpolicy.AdminPolicy.Label")
+	proto.RegisterType((*AdminPolicy_LabelSelector)(nil), "adminpolicy.AdminPolicy.LabelSelector")
+	proto.RegisterType((*AdminPolicy_LabelSelector_LabelExpression)(nil), "adminpolicy.AdminPolicy.LabelSelector.LabelExpression")
+	proto.RegisterType((*AdminPolicy_PodSelector)(nil), "adminpolicy.AdminPolicy.PodSelector")
+	proto.RegisterType((*AdminPolicy_Subject)(nil), "adminpolicy.AdminPolicy.Subject")
+	proto.RegisterType((*AdminPolicy_Peer)(nil), "adminpolicy.AdminPolicy.Peer")
+	proto.RegisterType((*AdminPolicy_Port)(nil), "adminpolicy.AdminPolicy.Port")
+	proto.RegisterType((*AdminPolicy_Rule)(nil), "adminpolicy.AdminPolicy.Rule")
+}
+
+func init() { proto.RegisterFile("adminpolicy.proto", fileDescriptor_2160b3193e13f5a7) }
+
+var fileDescriptor_2160b3193e13f5a7 = []byte{
+	// 621 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x54, 0xdd, 0x6e, 0xd3, 0x4c,
+	0x10, 0xed, 0xfa, 0x27, 0x71, 0xc7, 0xfd, 0x5a, 0x7f, 0x2b, 0x2e, 0x8c, 0x25, 0xc0, 0x8a, 0x50,
+	0xc9, 0x55, 0x90, 0x52, 0x09, 0x50, 0x2f, 0x5a, 0x42, 0x1b, 0xa4, 0x4a, 0x55, 0x62, 0xad, 0x83,
+	0x00, 0x71, 0x11, 0xb9, 0xce, 0x0a, 0x4c, 0x5d, 0xaf, 0xb5, 0xde, 0x00, 0x7d, 0x06, 0xb8, 0xe9,
+	0x23, 0x20, 0xf1, 0x1a, 0xbc, 0x06, 0xcf, 0x83, 0x76, 0xed, 0xb8, 0x06, 0x35, 0xa1, 0x20, 0x24,
+	0xee, 0x66, 0xc6, 0x73, 0xce, 0x9e, 0x33, 0xbb, 0x1e, 0xf8, 0x3f, 0x9a, 0x9d, 0x25, 0x59, 0xce,
+	0xd2, 0x24, 0x3e, 0xef, 0xe5, 0x9c, 0x09, 0x86, 0xed, 0x46, 0xa9, 0x73, 0xb1, 0x01, 0xf6, 0x40,
+	0xe6, 0x81, 0xca, 0x31, 0x06, 0x23, 0x8b, 0xce, 0xa8, 0x8b, 0x7c, 0xd4, 0x5d, 0x27, 0x2a, 0xc6,
+	0x1e, 0x58, 0x39, 0x4f, 0x18, 0x4f, 0xc4, 0xb9, 0xab, 0xf9, 0xa8, 0x6b, 0x92, 0x3a, 0xc7, 0xbb,
+	0xd0, 0x2e, 0xe6, 0x27, 0x6f, 0x69, 0x2c, 0x5c, 0xdd, 0x47, 0x5d, 0xbb, 0xef, 0xf7, 0x9a, 0x27,
+	0x36, 0xa8, 0x7b, 0x61, 0xd9, 0x47, 0x16, 0x00, 0xfc, 0x18, 0x36, 0x92, 0xec, 0x35, 0xa7, 0x45,
+	0x31, 0xe5, 0xf3, 0x94, 0xba, 0x86, 0xaf, 0x77, 0xed, 0xfe, 0xad, 0xa5, 0x04, 0x64, 0x9e, 0x52,
+	0x62, 0x57, 0x10, 0x99, 0xe0, 0x3d, 0xb0, 0x69, 0x83, 0xc0, 0xbc, 0x0e, 0x01, 0xd0, 0x1a, 0xef,
+	0xdd, 0x07, 0xf3, 0x38, 0x3a, 0xa1, 0x29, 0x76, 0x40, 0x3f, 0xa5, 0xe7, 0x95, 0x6b, 0x19, 0xe2,
+	0x1b, 0x60, 0xbe, 0x8b, 0xd2, 0x39, 0x55, 0x8e, 0xd7, 0x49, 0x99, 0x78, 0x9f, 0x74, 0xf8, 0x4f,
+	0x21, 0x42, 0x9a, 0xd2, 0x58, 0x30, 0x8e, 0xf7, 0xc1, 0x3e, 0x8b, 0x44, 0xfc, 0x66, 0x9a, 0xca,
+	0xb2, 0x8b, 0x94, 0x84, 0xdb, 0x4b, 0x25, 0x28, 0x30, 0x01, 0x05, 0x29, 0x8f, 0x8e, 0xc0, 0x29,
+	0x09, 0xe8, 0x87, 0x5c, 0x0a, 0x4b, 0x58, 0xe6, 0x6a, 0x8a, 0xe5, 0xc1, 0x6a, 0x96, 0x85, 0x84,
+	0x32, 0x1b, 0xd6, 0x68, 0xb2, 0xa5, 0xf8, 0x2e, 0x0b, 0xde, 0x37, 0x04, 0x5b, 0x3f, 0x35, 0x5d,
+	0xe1, 0xf8, 0x15, 0x58, 0x2c, 0xa7, 0x3c, 0x12, 0x8c, 0x2b, 0xd3, 0x9b, 0xfd, 0xfd, 0x3f, 0x13,
+	0xd0, 0x1b, 0x57, 0x34, 0xa4, 0x26, 0xbc, 0x1c, 0xa7, 0xee, 0xeb, 0xf5, 0x38, 0x3b, 0x7b, 0x60,
+	0x2d, 0x7a, 0x71, 0x0b, 0xb4, 0xa3, 0x91, 0xb3, 0x86, 0x01, 0x5a, 0xa3, 0xf1, 0x64, 0x7a, 0x34,
+	0x72, 0x90, 0x8c, 0x87, 0x2f, 0x8e, 0xc2, 0x49, 0xe8, 0x68, 0x18, 0xc3, 0xe6, 0xe1, 0x78, 0x18,
+	0x4e, 0xe5, 0x47, 0x55, 0x74, 0x74, 0xef, 0x02, 0x81, 0x1d, 0xb0, 0x59, 0x7d, 0x19, 0x4f, 0x01,
+	0xe4, 0x8b, 0x2d, 0xf2, 0x28, 0xa6, 0x85, 0xf2, 0x66, 0xf7, 0xb7, 0xaf, 0x67, 0x82, 0x34, 0x90,
+	0x78, 0x17, 0x8c, 0x9c, 0xcd, 0x0a, 0x57, 0xfb, 0x2d, 0x06, 0x85, 0xf1, 0x3e, 0x22, 0x68, 0x57,
+	0x4f, 0xfd, 0xaf, 0xe9, 0x79, 0xf4, 0x83, 0x9e, 0xbb, 0x4b, 0x19, 0x1a, 0xb3, 0xa8, 0xd4, 0x7c,
+	0x41, 0x60, 0x04, 0x94, 0xf2, 0x7f, 0x2f, 0x45, 0xae, 0x91, 0x8c, 0x8a, 0xf7, 0x8c, 0x9f, 0x16,
+	0xd5, 0x2b, 0xa8, 0x73, 0xef, 0xb3, 0x94, 0xc9, 0xb8, 0xc0, 0x4f, 0xe4, 0xae, 0x61, 0x82, 0xc5,
+	0x2c, 0x55, 0x22, 0x37, 0x57, 0x88, 0x94, 0x80, 0x5e, 0x50, 0x75, 0x93, 0x1a, 0x27, 0x77, 0x58,
+	0xce, 0xb8, 0xa8, 0x76, 0x95, 0x8a, 0xf1, 0x4d, 0xb0, 0x68, 0x36, 0x9b, 0xaa, 0xba, 0xae, 0xea,
+	0x6d, 0x9a, 0xcd, 0x24, 0x43, 0x67, 0x1b, 0xac, 0x05, 0x09, 0x6e, 0x83, 0x3e, 0x39, 0x08, 0x9c,
+	0x35, 0x19, 0x3c, 0x3b, 0x0c, 0x1c, 0x84, 0x2d, 0x30, 0xc2, 0x83, 0x49, 0xe0, 0x68, 0xde, 0x57,
+	0x04, 0x86, 0xda, 0x3a, 0x57, 0xed, 0xc8, 0x87, 0xd0, 0x8a, 0x62, 0x51, 0xfe, 0xbb, 0x52, 0xf5,
+	0x9d, 0xa5, 0xaa, 0x07, 0xaa, 0x8d, 0x54, 0xed, 0x78, 0x07, 0xcc, 0x9c, 0x52, 0x5e, 0x8e, 0x64,
+	0xd5, 0xf2, 0x92, 0xb7, 0x48, 0xca, 0x5e, 0x05, 0x62, 0x5c, 0x14, 0xbf, 0x5c, 0x99, 0xd2, 0x20,
+	0x29, 0x7b, 0x3b, 0xf7, 0xa0, 0x55, 0x9e, 0x8d, 0xd7, 0xc1, 0x1c, 0x1c, 0x1f, 0x8f, 0x9f, 0x3b,
+	0x6b, 0xd2, 0xde, 0xe1, 0x70, 0xf4, 0xb2, 0x34, 0x1a, 0x0c, 0xc2, 0xd0, 0xd1, 0x4e, 0x5a, 0x6a,
+	0x92, 0x3b, 0xdf, 0x07, 0x00, 0x6b, 0xec, 0xbc, 0x7b, 0x3c, 0x06, 0x00, 0x00,
+}
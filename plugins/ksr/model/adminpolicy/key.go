@@ -0,0 +1,49 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adminpolicy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/contiv/vpp/plugins/ksr/model/ksrkey"
+)
+
+const (
+	// AdminPolicyKeyword defines the keyword identifying Admin Network policy data.
+	AdminPolicyKeyword = "adminpolicy"
+)
+
+// KeyPrefix returns the key prefix identifying all K8s admin policies in the
+// data store.
+func KeyPrefix() string {
+	return ksrkey.KeyPrefix(AdminPolicyKeyword)
+}
+
+// ParseAdminPolicyFromKey parses admin policy name from the associated
+// data-store key.
+func ParseAdminPolicyFromKey(key string) (name string, err error) {
+	keywords := strings.Split(key, "/")
+	if len(keywords) == 3 && keywords[0] == ksrkey.KsrK8sPrefix && keywords[1] == AdminPolicyKeyword {
+		return keywords[2], nil
+	}
+	return "", fmt.Errorf("invalid format of the key %s", key)
+}
+
+// Key returns the key under which a given K8s admin policy is stored in the
+// data store.
+func Key(name string) string {
+	return KeyPrefix() + name
+}
@@ -81,10 +81,13 @@ type Node struct {
 	// Set of ids/uuids to uniquely identify the node.
 	// More info: https://kubernetes.io/docs/concepts/nodes/node/#info
 	// +optional
-	NodeInfo             *NodeSystemInfo `protobuf:"bytes,5,opt,name=node_info,json=nodeInfo,proto3" json:"node_info,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	NodeInfo *NodeSystemInfo `protobuf:"bytes,5,opt,name=node_info,json=nodeInfo,proto3" json:"node_info,omitempty"`
+	// Labels of the node.
+	// +optional
+	Labels               map[string]string `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *Node) Reset()         { *m = Node{} }
@@ -147,6 +150,13 @@ func (m *Node) GetNodeInfo() *NodeSystemInfo {
 	return nil
 }
 
+func (m *Node) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
 // NodeAddress contains information for the node's address.
 type NodeAddress struct {
 	// Node address type, one of Hostname, ExternalIP or InternalIP.
@@ -328,6 +338,7 @@ func (m *NodeSystemInfo) GetArchitecture() string {
 func init() {
 	proto.RegisterEnum("node.NodeAddress_AddressType", NodeAddress_AddressType_name, NodeAddress_AddressType_value)
 	proto.RegisterType((*Node)(nil), "node.Node")
+	proto.RegisterMapType((map[string]string)(nil), "node.Node.LabelsEntry")
 	proto.RegisterType((*NodeAddress)(nil), "node.NodeAddress")
 	proto.RegisterType((*NodeSystemInfo)(nil), "node.NodeSystemInfo")
 }
@@ -335,36 +346,39 @@ func init() {
 func init() { proto.RegisterFile("node.proto", fileDescriptor_0c843d59d2d938e7) }
 
 var fileDescriptor_0c843d59d2d938e7 = []byte{
-	// 483 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x93, 0xc1, 0x6e, 0xd3, 0x4c,
-	0x10, 0x80, 0x7f, 0x37, 0x6e, 0x1c, 0x8f, 0xfb, 0x27, 0x66, 0x40, 0xea, 0xf6, 0x50, 0x11, 0x59,
-	0x42, 0x44, 0x1c, 0x82, 0x1a, 0x6e, 0xdc, 0x2a, 0x8c, 0xc4, 0x0a, 0x29, 0x54, 0x2e, 0xe1, 0x6a,
-	0x39, 0xf1, 0xb4, 0xb5, 0x12, 0xef, 0x5a, 0xeb, 0x4d, 0x69, 0x5e, 0x80, 0x03, 0x8f, 0xc4, 0xeb,
-	0xf0, 0x22, 0x68, 0xd7, 0x76, 0x52, 0xe8, 0xc9, 0x3b, 0xdf, 0x7c, 0x33, 0xeb, 0xdd, 0xb1, 0x01,
-	0x84, 0xcc, 0x69, 0x5a, 0x29, 0xa9, 0x25, 0xba, 0x66, 0x1d, 0xfd, 0x72, 0xc0, 0x9d, 0xcb, 0x9c,
-	0x10, 0xc1, 0x15, 0x59, 0x49, 0xcc, 0x19, 0x3b, 0x13, 0x3f, 0xb1, 0x6b, 0x3c, 0x83, 0x41, 0x25,
-	0xf3, 0xf4, 0x03, 0x8f, 0x13, 0x76, 0x64, 0xb9, 0x57, 0xc9, 0xdc, 0x84, 0xf8, 0x12, 0x82, 0x4a,
-	0xc9, 0xfb, 0x22, 0x27, 0x95, 0xf2, 0x98, 0xf5, 0x6c, 0x16, 0x3a, 0xc4, 0x63, 0x7c, 0x0b, 0x7e,
-	0x96, 0xe7, 0x8a, 0xea, 0x9a, 0x6a, 0xe6, 0x8e, 0x7b, 0x93, 0x60, 0xf6, 0x6c, 0x6a, 0xb7, 0x37,
-	0xdb, 0x5d, 0x36, 0xa9, 0xe4, 0xe0, 0xe0, 0x05, 0xf8, 0x26, 0x9d, 0x16, 0xe2, 0x46, 0xb2, 0xe3,
-	0xb1, 0x33, 0x09, 0x66, 0x2f, 0x0e, 0x05, 0xd7, 0xbb, 0x5a, 0x53, 0xc9, 0xc5, 0x8d, 0x4c, 0x06,
-	0x06, 0x9a, 0x55, 0xf4, 0xdb, 0x81, 0xe0, 0x51, 0x37, 0xbc, 0x00, 0x57, 0xef, 0xaa, 0xe6, 0x0c,
-	0xc3, 0xd9, 0xf9, 0x93, 0xed, 0xa6, 0xed, 0xf3, 0xeb, 0xae, 0xa2, 0xc4, 0xaa, 0xc8, 0xc0, 0x6b,
-	0x5f, 0xa1, 0x3b, 0x61, 0x1b, 0x46, 0x3f, 0x1c, 0x08, 0x1e, 0xf9, 0xf8, 0x1c, 0x46, 0xa6, 0xd5,
-	0x42, 0xac, 0x85, 0xfc, 0x2e, 0x4c, 0x26, 0xfc, 0x0f, 0x43, 0x38, 0x31, 0xf0, 0x93, 0xac, 0xf5,
-	0x3c, 0x2b, 0x29, 0x74, 0x10, 0x61, 0x68, 0xc8, 0xc7, 0x07, 0x4d, 0x4a, 0x64, 0x1b, 0x7e, 0x15,
-	0x1e, 0x75, 0x8c, 0x8b, 0x3d, 0xeb, 0x75, 0xed, 0x3a, 0x2f, 0x9e, 0x5f, 0x87, 0x6e, 0x07, 0x3b,
-	0xd1, 0xc0, 0xe3, 0xe8, 0x67, 0xaf, 0x29, 0x3f, 0x5c, 0x01, 0x9e, 0x03, 0x94, 0xd9, 0xea, 0xae,
-	0x10, 0x64, 0x2e, 0xbf, 0x19, 0x99, 0xdf, 0x12, 0x1e, 0x9b, 0xe1, 0xd4, 0x56, 0x4e, 0x17, 0x0b,
-	0x1e, 0xb7, 0x07, 0x83, 0x06, 0x19, 0x82, 0xa7, 0xe0, 0x2d, 0xa5, 0xd4, 0x87, 0xc9, 0xf5, 0x4d,
-	0xc8, 0x63, 0x7c, 0x05, 0xc3, 0x35, 0x29, 0x41, 0x9b, 0xf4, 0x9e, 0x54, 0x5d, 0x48, 0xc1, 0x5c,
-	0x9b, 0xff, 0xbf, 0xa1, 0xdf, 0x1a, 0x68, 0x3e, 0x0c, 0x59, 0xa7, 0x45, 0x99, 0xdd, 0x92, 0x1d,
-	0x95, 0x9f, 0x78, 0xb2, 0xe6, 0x26, 0xc4, 0xf7, 0x70, 0xb6, 0x92, 0x42, 0x67, 0x85, 0x20, 0x95,
-	0xaa, 0xad, 0xd0, 0x45, 0x49, 0xfb, 0x66, 0x7d, 0xeb, 0x9e, 0xee, 0x85, 0xa4, 0xc9, 0x77, 0x6d,
-	0x5f, 0xc3, 0x68, 0xbd, 0x5d, 0xd2, 0x86, 0xf4, 0xbe, 0xc2, 0xb3, 0x15, 0xc3, 0x16, 0x77, 0xe2,
-	0x1b, 0x08, 0x3f, 0x6f, 0x97, 0x74, 0xa5, 0xe4, 0xc3, 0xae, 0x65, 0x6c, 0x60, 0xcd, 0x27, 0x1c,
-	0x27, 0x30, 0xfa, 0x52, 0x91, 0xca, 0x74, 0x21, 0x6e, 0x9b, 0x2b, 0x64, 0xbe, 0x55, 0xff, 0xc5,
-	0x18, 0xc1, 0xc9, 0xa5, 0x5a, 0xdd, 0x15, 0x9a, 0x56, 0x7a, 0xab, 0x88, 0x81, 0xd5, 0xfe, 0x62,
-	0xcb, 0xbe, 0xfd, 0x79, 0xde, 0xfd, 0x09, 0x00, 0x00, 0xff, 0xff, 0x51, 0x7c, 0x50, 0x68, 0x4a,
-	0x03, 0x00, 0x00,
+	// 532 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x93, 0xc1, 0x6e, 0xd3, 0x40,
+	0x10, 0x86, 0x71, 0xe2, 0x26, 0xf1, 0xb8, 0x24, 0x66, 0xa8, 0xa8, 0x8b, 0x54, 0x11, 0x45, 0x42,
+	0x44, 0x1c, 0x82, 0x1a, 0x2e, 0xd0, 0x5b, 0x85, 0x2b, 0x61, 0x81, 0x42, 0xe5, 0x12, 0xae, 0x96,
+	0x13, 0x4f, 0x5b, 0x2b, 0xf6, 0xae, 0xb5, 0xde, 0x84, 0xfa, 0x05, 0x38, 0xf0, 0x1e, 0x3c, 0x19,
+	0x2f, 0x82, 0x76, 0x6d, 0x27, 0x29, 0x3d, 0x79, 0xe6, 0xfb, 0xff, 0x9d, 0xf5, 0x8c, 0xc7, 0x00,
+	0x8c, 0xc7, 0x34, 0xc9, 0x05, 0x97, 0x1c, 0x4d, 0x15, 0x8f, 0xfe, 0xb4, 0xc0, 0x9c, 0xf1, 0x98,
+	0x10, 0xc1, 0x64, 0x51, 0x46, 0xae, 0x31, 0x34, 0xc6, 0x56, 0xa0, 0x63, 0x3c, 0x81, 0x5e, 0xce,
+	0xe3, 0xf0, 0x93, 0xef, 0x05, 0x6e, 0x4b, 0xf3, 0x6e, 0xce, 0x63, 0x95, 0xe2, 0x2b, 0xb0, 0x73,
+	0xc1, 0x37, 0x49, 0x4c, 0x22, 0xf4, 0x3d, 0xb7, 0xad, 0x55, 0x68, 0x90, 0xef, 0xe1, 0x3b, 0xb0,
+	0xa2, 0x38, 0x16, 0x54, 0x14, 0x54, 0xb8, 0xe6, 0xb0, 0x3d, 0xb6, 0xa7, 0xcf, 0x26, 0xfa, 0x7a,
+	0x75, 0xdd, 0x45, 0x25, 0x05, 0x3b, 0x0f, 0x9e, 0x81, 0xa5, 0xe4, 0x30, 0x61, 0x37, 0xdc, 0x3d,
+	0x18, 0x1a, 0x63, 0x7b, 0x7a, 0xb4, 0x3b, 0x70, 0x5d, 0x16, 0x92, 0x32, 0x9f, 0xdd, 0xf0, 0xa0,
+	0xa7, 0xa0, 0x8a, 0x70, 0x02, 0x9d, 0x34, 0x5a, 0x50, 0x5a, 0xb8, 0x1d, 0x7d, 0xc1, 0x8b, 0x9d,
+	0x7f, 0xf2, 0x55, 0x0b, 0x97, 0x4c, 0x8a, 0x32, 0xa8, 0x5d, 0x2f, 0x3f, 0x82, 0xbd, 0x87, 0xd1,
+	0x81, 0xf6, 0x8a, 0xca, 0xba, 0x63, 0x15, 0xe2, 0x11, 0x1c, 0x6c, 0xa2, 0x74, 0x4d, 0x75, 0xb7,
+	0x55, 0x72, 0xde, 0xfa, 0x60, 0x8c, 0xfe, 0x1a, 0x60, 0xef, 0xbd, 0x38, 0x9e, 0x81, 0x29, 0xcb,
+	0xbc, 0x1a, 0x57, 0x7f, 0x7a, 0xfa, 0xa8, 0xb3, 0x49, 0xfd, 0xfc, 0x5e, 0xe6, 0x14, 0x68, 0x2b,
+	0xba, 0xd0, 0xad, 0xbb, 0x6d, 0x86, 0x59, 0xa7, 0xa3, 0x5f, 0x06, 0xd8, 0x7b, 0x7e, 0x7c, 0x0e,
+	0x03, 0x55, 0x6a, 0xce, 0x56, 0x8c, 0xff, 0x64, 0x4a, 0x71, 0x9e, 0xa0, 0x03, 0x87, 0x0a, 0x7e,
+	0xe6, 0x85, 0x9c, 0x45, 0x19, 0x39, 0x06, 0x22, 0xf4, 0x15, 0xb9, 0xbc, 0x97, 0x24, 0x58, 0x94,
+	0xfa, 0x57, 0x4e, 0xab, 0x61, 0x3e, 0xdb, 0xb2, 0x76, 0x53, 0xae, 0xf1, 0x79, 0xb3, 0x6b, 0xc7,
+	0x6c, 0xa0, 0xcf, 0x76, 0xf0, 0x60, 0xf4, 0xbb, 0x0d, 0xfd, 0x87, 0xd3, 0xc6, 0x53, 0x80, 0x2c,
+	0x5a, 0xde, 0x25, 0x8c, 0xd4, 0x77, 0xae, 0x66, 0x65, 0xd5, 0xc4, 0xf7, 0xd4, 0x1e, 0x14, 0xda,
+	0x1c, 0xce, 0xe7, 0xbe, 0x57, 0x37, 0x06, 0x15, 0x52, 0x04, 0x8f, 0xa1, 0xbb, 0xe0, 0x5c, 0xee,
+	0x96, 0xa4, 0xa3, 0x52, 0xdf, 0xc3, 0xd7, 0xd0, 0x5f, 0x91, 0x60, 0x94, 0x86, 0x1b, 0x12, 0x45,
+	0xc2, 0x99, 0x6b, 0x6a, 0xfd, 0x69, 0x45, 0x7f, 0x54, 0x50, 0xed, 0x20, 0x2f, 0xc2, 0x24, 0x8b,
+	0x6e, 0x49, 0x6f, 0x85, 0x15, 0x74, 0x79, 0xe1, 0xab, 0x14, 0xcf, 0xe1, 0x64, 0xc9, 0x99, 0x8c,
+	0x12, 0x46, 0x22, 0x14, 0x6b, 0x26, 0x93, 0x8c, 0xb6, 0xc5, 0x3a, 0xda, 0x7b, 0xbc, 0x35, 0x04,
+	0x95, 0xde, 0x94, 0x7d, 0x03, 0x83, 0xd5, 0x7a, 0x41, 0x29, 0xc9, 0xed, 0x89, 0xae, 0x3e, 0xd1,
+	0xaf, 0x71, 0x63, 0x7c, 0x0b, 0xce, 0x97, 0xf5, 0x82, 0xae, 0x04, 0xbf, 0x2f, 0x6b, 0xe6, 0xf6,
+	0xb4, 0xf3, 0x11, 0xc7, 0x31, 0x0c, 0xbe, 0xe5, 0x24, 0x22, 0x99, 0xb0, 0xdb, 0x6a, 0x84, 0xae,
+	0xa5, 0xad, 0xff, 0x63, 0x1c, 0xc1, 0xe1, 0x85, 0x58, 0xde, 0x25, 0x92, 0x96, 0x72, 0x2d, 0xc8,
+	0x05, 0x6d, 0x7b, 0xc0, 0x16, 0x1d, 0xfd, 0x9f, 0xbe, 0xff, 0x37, 0x00, 0x3d, 0x9d, 0xec, 0xfa,
+	0xb5, 0x03, 0x00, 0x00,
 }
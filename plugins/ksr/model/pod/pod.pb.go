@@ -87,10 +87,14 @@ type Pod struct {
 	// and services.
 	// More info: http://kubernetes.io/docs/user-guide/labels
 	// +optional
-	Labels               map[string]string `protobuf:"bytes,8,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	Labels map[string]string `protobuf:"bytes,8,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// All IP addresses allocated to the pod, one per IP family in dual-stack
+	// clusters. The first entry always equals ip_address.
+	// +optional
+	IpAddresses          []string `protobuf:"bytes,9,rep,name=ip_addresses,json=ipAddresses,proto3" json:"ip_addresses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Pod) Reset()         { *m = Pod{} }
@@ -174,6 +178,13 @@ func (m *Pod) GetLabels() map[string]string {
 	return nil
 }
 
+func (m *Pod) GetIpAddresses() []string {
+	if m != nil {
+		return m.IpAddresses
+	}
+	return nil
+}
+
 // Label is a key/value pair attached to an object (pod in this case).
 // Labels are used to organize and to select subsets of objects.
 type Pod_Label struct {
@@ -382,30 +393,31 @@ func init() {
 func init() { proto.RegisterFile("pod.proto", fileDescriptor_106fb77aeb685f33) }
 
 var fileDescriptor_106fb77aeb685f33 = []byte{
-	// 389 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0xdb, 0x4a, 0xeb, 0x40,
-	0x14, 0x3d, 0xb9, 0x36, 0xd9, 0xa5, 0x3d, 0x61, 0x28, 0x9c, 0x39, 0xb1, 0x42, 0x29, 0x2a, 0x05,
-	0x25, 0x4a, 0x7d, 0xf1, 0x86, 0x50, 0xaa, 0x0f, 0x82, 0x0f, 0x21, 0xe8, 0x73, 0x99, 0x36, 0x01,
-	0x83, 0x31, 0x13, 0x92, 0x51, 0xe8, 0xe7, 0xf8, 0x09, 0xfe, 0x92, 0x5f, 0x22, 0xb3, 0x93, 0xa6,
-	0x55, 0x2b, 0xd4, 0xa7, 0xec, 0x59, 0x7b, 0xad, 0x7d, 0x59, 0x3b, 0x60, 0x67, 0x3c, 0xf4, 0xb2,
-	0x9c, 0x0b, 0x4e, 0xb4, 0x8c, 0x87, 0xfd, 0x57, 0x13, 0x34, 0x9f, 0x87, 0x84, 0x80, 0x9e, 0xb2,
-	0xa7, 0x88, 0x2a, 0x3d, 0x65, 0x60, 0x07, 0x18, 0x93, 0x2e, 0xd8, 0xf2, 0x5b, 0x64, 0x6c, 0x16,
-	0x51, 0x15, 0x13, 0x4b, 0x80, 0xec, 0x80, 0x91, 0xb0, 0x69, 0x94, 0x50, 0xad, 0xa7, 0x0d, 0x9a,
-	0xc3, 0xb6, 0x27, 0x2b, 0xfb, 0x3c, 0xf4, 0x6e, 0x25, 0x1a, 0x94, 0x49, 0xb2, 0x0d, 0x10, 0x67,
-	0x13, 0x16, 0x86, 0x79, 0x54, 0x14, 0x54, 0x2f, 0x8b, 0xc4, 0xd9, 0xa8, 0x04, 0xc8, 0x1e, 0xfc,
-	0x7d, 0xe0, 0x85, 0x98, 0xac, 0x70, 0x0c, 0xe4, 0xb4, 0x24, 0x7c, 0x53, 0xf3, 0x8e, 0xc0, 0x9e,
-	0xf1, 0x54, 0xb0, 0x38, 0x8d, 0x72, 0x6a, 0x62, 0x43, 0x52, 0x37, 0x1c, 0x2f, 0x32, 0xc1, 0x92,
-	0x44, 0xce, 0xa1, 0xc9, 0xd2, 0x94, 0x0b, 0x26, 0x62, 0x9e, 0x16, 0xb4, 0x81, 0x9a, 0xff, 0xb5,
-	0x66, 0xb4, 0xcc, 0x5d, 0xa7, 0x22, 0x9f, 0x07, 0xab, 0x6c, 0x72, 0x00, 0x26, 0x8e, 0x5f, 0x50,
-	0x0b, 0x75, 0x9d, 0xcf, 0xcb, 0x55, 0x92, 0x8a, 0xe3, 0x1e, 0x82, 0x81, 0x30, 0x71, 0x40, 0x7b,
-	0x8c, 0xe6, 0x95, 0x87, 0x32, 0x24, 0x1d, 0x30, 0x5e, 0x58, 0xf2, 0xbc, 0xb0, 0xaf, 0x7c, 0xb8,
-	0x6f, 0x2a, 0xd8, 0xf5, 0xd0, 0x6b, 0xad, 0xdf, 0x07, 0x3d, 0xe3, 0xb9, 0xa0, 0x2a, 0xb6, 0xff,
-	0xf7, 0x7d, 0x55, 0xcf, 0xe7, 0xb9, 0x08, 0x90, 0xe4, 0xbe, 0x2b, 0xa0, 0xcb, 0xe7, 0xda, 0x4a,
-	0x5b, 0x60, 0xa3, 0xc3, 0x55, 0x39, 0x65, 0x60, 0x04, 0x96, 0x04, 0x50, 0xb0, 0x0b, 0xed, 0xda,
-	0xb1, 0x92, 0xa1, 0x21, 0xa3, 0x55, 0xa3, 0x48, 0xbb, 0x00, 0x0b, 0x7f, 0x99, 0x19, 0x4f, 0xf0,
-	0x84, 0xed, 0x61, 0xef, 0x87, 0x89, 0x3c, 0xbf, 0xe2, 0x05, 0xb5, 0x62, 0xd3, 0x1b, 0xf7, 0xbb,
-	0x60, 0x2d, 0xd4, 0xa4, 0x01, 0xda, 0xdd, 0xd8, 0x77, 0xfe, 0xc8, 0xe0, 0xfe, 0xca, 0x77, 0x14,
-	0xf7, 0x12, 0x9c, 0xaf, 0x37, 0xdb, 0xd4, 0xef, 0x33, 0xf5, 0x44, 0x71, 0x4f, 0xa1, 0xb9, 0x72,
-	0xbb, 0xdf, 0x48, 0xa7, 0x26, 0xae, 0x72, 0xfc, 0x11, 0x00, 0x00, 0xff, 0xff, 0x99, 0x4e, 0x14,
-	0x2b, 0x3c, 0x03, 0x00, 0x00,
+	// 406 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x92, 0xcd, 0x8a, 0xd5, 0x30,
+	0x14, 0xc7, 0xed, 0x4d, 0xdb, 0x69, 0x4e, 0x9d, 0x6b, 0x09, 0x03, 0xc6, 0x3a, 0x42, 0x1d, 0x54,
+	0x0a, 0x4a, 0x95, 0x71, 0xe3, 0x17, 0xc2, 0x30, 0xba, 0x10, 0x5c, 0x94, 0xa0, 0xeb, 0x21, 0x73,
+	0x1b, 0xb0, 0x58, 0x9b, 0xd2, 0x44, 0x61, 0x1e, 0xcb, 0x77, 0xf0, 0x49, 0x7c, 0x12, 0xc9, 0x69,
+	0x6f, 0x7a, 0xd5, 0x11, 0xc6, 0x55, 0xd3, 0xff, 0xf9, 0xfd, 0x4f, 0xce, 0x47, 0x80, 0x0e, 0xba,
+	0xa9, 0x86, 0x51, 0x5b, 0xcd, 0xc8, 0xa0, 0x9b, 0xa3, 0x1f, 0x31, 0x90, 0x5a, 0x37, 0x8c, 0x41,
+	0xd8, 0xcb, 0x2f, 0x8a, 0x07, 0x45, 0x50, 0x52, 0x81, 0x67, 0x76, 0x08, 0xd4, 0x7d, 0xcd, 0x20,
+	0x37, 0x8a, 0xaf, 0x30, 0xb0, 0x08, 0xec, 0x1e, 0x44, 0x9d, 0x3c, 0x57, 0x1d, 0x27, 0x05, 0x29,
+	0xd3, 0xe3, 0x75, 0xe5, 0x32, 0xd7, 0xba, 0xa9, 0xde, 0x3b, 0x55, 0x4c, 0x41, 0x76, 0x07, 0xa0,
+	0x1d, 0xce, 0x64, 0xd3, 0x8c, 0xca, 0x18, 0x1e, 0x4e, 0x49, 0xda, 0xe1, 0x64, 0x12, 0xd8, 0x03,
+	0xb8, 0xf1, 0x49, 0x1b, 0x7b, 0xb6, 0xc3, 0x44, 0xc8, 0xec, 0x3b, 0xf9, 0x9d, 0xe7, 0x9e, 0x00,
+	0xdd, 0xe8, 0xde, 0xca, 0xb6, 0x57, 0x23, 0x8f, 0xf1, 0x42, 0xe6, 0x2f, 0x3c, 0xdd, 0x46, 0xc4,
+	0x02, 0xb1, 0x97, 0x90, 0xca, 0xbe, 0xd7, 0x56, 0xda, 0x56, 0xf7, 0x86, 0xef, 0xa1, 0xe7, 0x96,
+	0xf7, 0x9c, 0x2c, 0xb1, 0xb7, 0xbd, 0x1d, 0x2f, 0xc4, 0x2e, 0xcd, 0x1e, 0x41, 0x8c, 0xe5, 0x1b,
+	0x9e, 0xa0, 0xef, 0xe0, 0xf7, 0xe6, 0x66, 0xcb, 0xcc, 0xb0, 0xbb, 0x70, 0x7d, 0xa9, 0x5f, 0x19,
+	0x4e, 0x0b, 0x52, 0x52, 0x91, 0xfa, 0x2e, 0x95, 0xc9, 0x1f, 0x43, 0x84, 0x4e, 0x96, 0x01, 0xf9,
+	0xac, 0x2e, 0xe6, 0x31, 0xbb, 0x23, 0x3b, 0x80, 0xe8, 0x9b, 0xec, 0xbe, 0x6e, 0x27, 0x3c, 0xfd,
+	0xe4, 0xdf, 0x57, 0x40, 0x7d, 0x5f, 0x97, 0x6e, 0xe7, 0x21, 0x84, 0x83, 0x1e, 0x2d, 0x5f, 0x61,
+	0x85, 0x37, 0xff, 0x9e, 0x46, 0x55, 0xeb, 0xd1, 0x0a, 0x84, 0xf2, 0x9f, 0x01, 0x84, 0xee, 0xf7,
+	0xd2, 0x4c, 0xb7, 0x81, 0xe2, 0x12, 0xe6, 0x74, 0x41, 0x19, 0x89, 0xc4, 0x09, 0x68, 0xb8, 0x0f,
+	0x6b, 0x3f, 0xd4, 0x89, 0x20, 0x48, 0xec, 0x7b, 0x15, 0xb1, 0x57, 0x90, 0xe0, 0xab, 0xda, 0xe8,
+	0x0e, 0xb7, 0xbc, 0x3e, 0x2e, 0xfe, 0x51, 0x51, 0x55, 0xcf, 0x9c, 0xf0, 0x8e, 0xab, 0x3e, 0x83,
+	0xa3, 0x43, 0x48, 0xb6, 0x6e, 0xb6, 0x07, 0xe4, 0xc3, 0x69, 0x9d, 0x5d, 0x73, 0x87, 0x8f, 0x6f,
+	0xea, 0x2c, 0xc8, 0x5f, 0x43, 0xf6, 0xe7, 0x5a, 0xaf, 0x3a, 0xef, 0x17, 0xab, 0x67, 0x41, 0xfe,
+	0x1c, 0xd2, 0x9d, 0xf5, 0xfe, 0x8f, 0xf5, 0x3c, 0xc6, 0x56, 0x9e, 0xfe, 0x1a, 0x00, 0xa0, 0xa3,
+	0x2d, 0xbc, 0x5f, 0x03, 0x00, 0x00,
 }
@@ -103,18 +103,21 @@ func (Policy_LabelSelector_LabelExpression_Operator) EnumDescriptor() ([]byte, [
 type Policy_Port_Protocol int32
 
 const (
-	Policy_Port_TCP Policy_Port_Protocol = 0
-	Policy_Port_UDP Policy_Port_Protocol = 1
+	Policy_Port_TCP  Policy_Port_Protocol = 0
+	Policy_Port_UDP  Policy_Port_Protocol = 1
+	Policy_Port_SCTP Policy_Port_Protocol = 2
 )
 
 var Policy_Port_Protocol_name = map[int32]string{
 	0: "TCP",
 	1: "UDP",
+	2: "SCTP",
 }
 
 var Policy_Port_Protocol_value = map[string]int32{
-	"TCP": 0,
-	"UDP": 1,
+	"TCP":  0,
+	"UDP":  1,
+	"SCTP": 2,
 }
 
 func (x Policy_Port_Protocol) String() string {
@@ -450,10 +453,15 @@ type Policy_Port struct {
 	// If present, only traffic on the specified protocol AND port
 	// will be matched.
 	// +optional
-	Port                 *Policy_Port_PortNameOrNumber `protobuf:"bytes,1,opt,name=port,proto3" json:"port,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
-	XXX_unrecognized     []byte                        `json:"-"`
-	XXX_sizecache        int32                         `json:"-"`
+	Port *Policy_Port_PortNameOrNumber `protobuf:"bytes,1,opt,name=port,proto3" json:"port,omitempty"`
+	// If set, indicates that the range of ports from port to end_port, inclusive,
+	// should be allowed by the policy. Can only be used with a numerical port
+	// and must be equal or greater than port.
+	// +optional
+	EndPort              int32    `protobuf:"varint,4,opt,name=end_port,json=endPort,proto3" json:"end_port,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Policy_Port) Reset()         { *m = Policy_Port{} }
@@ -495,6 +503,13 @@ func (m *Policy_Port) GetPort() *Policy_Port_PortNameOrNumber {
 	return nil
 }
 
+func (m *Policy_Port) GetEndPort() int32 {
+	if m != nil {
+		return m.EndPort
+	}
+	return 0
+}
+
 // Numerical or named port.
 type Policy_Port_PortNameOrNumber struct {
 	Type Policy_Port_PortNameOrNumber_Type `protobuf:"varint,1,opt,name=type,proto3,enum=policy.Policy_Port_PortNameOrNumber_Type" json:"type,omitempty"`
@@ -563,11 +578,17 @@ type Policy_Peer struct {
 	// This matches all pods in all namespaces selected by this label selector.
 	// If present but empty, this selector selects all namespaces.
 	// +optional
-	Namespaces           *Policy_LabelSelector `protobuf:"bytes,2,opt,name=namespaces,proto3" json:"namespaces,omitempty"`
-	IpBlock              *Policy_Peer_IPBlock  `protobuf:"bytes,3,opt,name=ip_block,json=ipBlock,proto3" json:"ip_block,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+	Namespaces *Policy_LabelSelector `protobuf:"bytes,2,opt,name=namespaces,proto3" json:"namespaces,omitempty"`
+	IpBlock    *Policy_Peer_IPBlock  `protobuf:"bytes,3,opt,name=ip_block,json=ipBlock,proto3" json:"ip_block,omitempty"`
+	// DNS names of the destinations (egress only). The names are resolved
+	// periodically by the vswitch and the resolved addresses expire with the TTL
+	// of the DNS records. Wildcards are not supported.
+	// Filled from the "contivpp.io/egress-fqdn" annotation of the policy.
+	// +optional
+	Fqdn                 []string `protobuf:"bytes,4,rep,name=fqdn,proto3" json:"fqdn,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Policy_Peer) Reset()         { *m = Policy_Peer{} }
@@ -616,6 +637,13 @@ func (m *Policy_Peer) GetIpBlock() *Policy_Peer_IPBlock {
 	return nil
 }
 
+func (m *Policy_Peer) GetFqdn() []string {
+	if m != nil {
+		return m.Fqdn
+	}
+	return nil
+}
+
 // IPBlock describes a particular CIDR (Ex. "192.168.1.1/24") that is allowed
 // to/from the pods selected for this network policy. The except entries
 // describe CIDRs that should not be included within this rule.
@@ -820,49 +848,51 @@ func init() {
 func init() { proto.RegisterFile("policy.proto", fileDescriptor_ac3b897852294d6a) }
 
 var fileDescriptor_ac3b897852294d6a = []byte{
-	// 703 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xdd, 0x6e, 0xd3, 0x4a,
-	0x10, 0x8e, 0x1d, 0xe7, 0xa7, 0xe3, 0x9e, 0xd6, 0xda, 0x56, 0x55, 0x8e, 0x4f, 0x2e, 0xa2, 0x1c,
-	0x24, 0x02, 0x42, 0x06, 0x05, 0x15, 0x55, 0x88, 0x22, 0xb5, 0xc4, 0xa0, 0xa0, 0xd6, 0x31, 0x9b,
-	0x54, 0x20, 0x6e, 0x2c, 0xc7, 0x5d, 0xc0, 0xaa, 0x93, 0xb5, 0x36, 0x0e, 0x6a, 0x9e, 0xa5, 0xef,
-	0xc1, 0x53, 0xf0, 0x1a, 0xdc, 0xf2, 0x0c, 0x68, 0xc7, 0x8e, 0x5d, 0x42, 0x54, 0x0a, 0x57, 0x9e,
-	0x99, 0xfd, 0xbe, 0xd9, 0x99, 0xcf, 0x33, 0x0b, 0x9b, 0x31, 0x8f, 0xc2, 0x60, 0x61, 0xc5, 0x82,
-	0x27, 0x9c, 0x54, 0x53, 0xaf, 0x7d, 0xb5, 0x09, 0x55, 0x17, 0x4d, 0x42, 0x40, 0x9b, 0xfa, 0x13,
-	0xd6, 0x50, 0x5a, 0x4a, 0x67, 0x83, 0xa2, 0x4d, 0x9a, 0xb0, 0x21, 0xbf, 0xb3, 0xd8, 0x0f, 0x58,
-	0x43, 0xc5, 0x83, 0x22, 0x40, 0xee, 0x43, 0x25, 0xf2, 0xc7, 0x2c, 0x6a, 0x94, 0x5b, 0xe5, 0x8e,
-	0xde, 0xdd, 0xb5, 0xb2, 0x2b, 0xd2, 0x84, 0xd6, 0x89, 0x3c, 0xa3, 0x29, 0x84, 0x3c, 0x02, 0x2d,
-	0xe6, 0xe7, 0xb3, 0x86, 0xd6, 0x52, 0x3a, 0x7a, 0xb7, 0xb9, 0x0e, 0x3a, 0x64, 0x11, 0x0b, 0x12,
-	0x2e, 0x28, 0x22, 0xc9, 0x53, 0xd0, 0x53, 0x90, 0x97, 0x2c, 0x62, 0xd6, 0xa8, 0xb4, 0x94, 0xce,
-	0x56, 0xf7, 0xdf, 0x15, 0x62, 0xfa, 0x19, 0x2d, 0x62, 0x46, 0x21, 0xce, 0x6d, 0x72, 0x08, 0x9b,
-	0xe1, 0xf4, 0xa3, 0x60, 0xb3, 0x99, 0x27, 0xe6, 0x11, 0x6b, 0x54, 0xb1, 0x40, 0x73, 0x85, 0xdc,
-	0x4f, 0x21, 0x74, 0x1e, 0x31, 0xaa, 0x87, 0x85, 0x23, 0xaf, 0x66, 0xd7, 0xd8, 0x35, 0x64, 0xaf,
-	0x5e, 0x6d, 0x17, 0x64, 0x60, 0xb9, 0x6d, 0x3e, 0x84, 0x0a, 0x76, 0x43, 0x0c, 0x28, 0x5f, 0xb0,
-	0x45, 0x26, 0xa7, 0x34, 0xc9, 0x2e, 0x54, 0x3e, 0xfb, 0xd1, 0x7c, 0xa9, 0x64, 0xea, 0x98, 0xdf,
-	0x55, 0xf8, 0xe7, 0xa7, 0xfe, 0xc9, 0x3e, 0xe8, 0x13, 0x3f, 0x09, 0x3e, 0x79, 0xa9, 0xba, 0xca,
-	0x0d, 0xea, 0x02, 0x02, 0xd3, 0x0b, 0xdf, 0x82, 0x91, 0xd2, 0xd8, 0x65, 0x2c, 0xcb, 0x09, 0xf9,
-	0xb4, 0xa1, 0x22, 0xf7, 0xc1, 0x4d, 0x72, 0xa7, 0x9e, 0x9d, 0x73, 0xe8, 0x36, 0x66, 0x29, 0x02,
-	0xe6, 0x57, 0x05, 0xb6, 0x57, 0x40, 0x6b, 0xba, 0x7b, 0x03, 0x75, 0x1e, 0x33, 0xe1, 0x27, 0x5c,
-	0x60, 0x83, 0x5b, 0xdd, 0xfd, 0x3f, 0xb9, 0xd6, 0x1a, 0x64, 0x64, 0x9a, 0xa7, 0x29, 0x04, 0x93,
-	0x03, 0xb6, 0x14, 0xac, 0xfd, 0x1c, 0xea, 0x4b, 0x2c, 0xa9, 0x82, 0xda, 0x77, 0x8c, 0x12, 0x01,
-	0xa8, 0x3a, 0x83, 0x91, 0xd7, 0x77, 0x0c, 0x45, 0xda, 0xf6, 0xbb, 0xfe, 0x70, 0x34, 0x34, 0x54,
-	0x42, 0x60, 0xab, 0x37, 0xb0, 0x87, 0x9e, 0x3c, 0xc4, 0xa0, 0x51, 0x36, 0xbf, 0xa8, 0xa0, 0xb9,
-	0x5c, 0x24, 0xe4, 0x00, 0xea, 0xb8, 0x0d, 0x01, 0x97, 0x23, 0x2c, 0x2b, 0x6e, 0xfe, 0x32, 0x5e,
-	0x22, 0xb1, 0xdc, 0x0c, 0x43, 0x73, 0x34, 0x39, 0x90, 0xd3, 0x2c, 0x12, 0x6c, 0x5f, 0xef, 0xde,
-	0x59, 0xcb, 0xe2, 0x22, 0x71, 0xfc, 0x09, 0x1b, 0x08, 0x67, 0x3e, 0x19, 0x33, 0x9c, 0x6a, 0x91,
-	0x98, 0x57, 0x0a, 0x18, 0xab, 0x47, 0xe4, 0x10, 0x34, 0x9c, 0x71, 0x05, 0x8b, 0xb8, 0x77, 0x9b,
-	0x74, 0x16, 0xce, 0x3c, 0xd2, 0xc8, 0x1e, 0x54, 0xa7, 0x18, 0x44, 0xdd, 0x2b, 0x34, 0xf3, 0xf2,
-	0x8d, 0x2e, 0x17, 0x1b, 0xdd, 0x6e, 0x82, 0x86, 0x1b, 0x22, 0x05, 0x3b, 0x3b, 0x3d, 0xb6, 0xa9,
-	0x51, 0x22, 0x75, 0xd0, 0x9c, 0xa3, 0x53, 0xdb, 0x50, 0xda, 0x4d, 0xa8, 0x2f, 0xbb, 0x25, 0x35,
-	0x28, 0x8f, 0x5e, 0xb8, 0x46, 0x49, 0x1a, 0x67, 0x3d, 0xd7, 0x50, 0xcc, 0x6f, 0x0a, 0x68, 0x2e,
-	0x63, 0x22, 0x5f, 0x66, 0xe5, 0xd6, 0xcb, 0xfc, 0x0c, 0x20, 0x7f, 0x37, 0x66, 0x58, 0xe6, 0xef,
-	0x78, 0xd7, 0xf0, 0xe4, 0x09, 0xd4, 0xc3, 0xd8, 0x1b, 0x47, 0x3c, 0xb8, 0xc0, 0x66, 0xf4, 0xee,
-	0x7f, 0xab, 0x1a, 0x31, 0x26, 0xac, 0xbe, 0x7b, 0x2c, 0x21, 0xb4, 0x16, 0xc6, 0x68, 0x98, 0xfb,
-	0x50, 0xcb, 0x62, 0x52, 0x8b, 0x20, 0x3c, 0x17, 0xcb, 0xd7, 0x4d, 0xda, 0x52, 0x37, 0x76, 0x19,
-	0xb0, 0x38, 0xc1, 0x35, 0xd9, 0xa0, 0x99, 0x67, 0x7a, 0xa0, 0x5f, 0x7b, 0x1a, 0xc8, 0xdd, 0xfc,
-	0x67, 0xcb, 0x5d, 0xda, 0x59, 0xf3, 0x77, 0xd2, 0x7f, 0x2b, 0x81, 0x1f, 0x04, 0x9f, 0x64, 0x4b,
-	0xb7, 0xb3, 0xa6, 0x44, 0x8a, 0x00, 0xf3, 0x3d, 0x80, 0xfd, 0x17, 0xf9, 0xff, 0x07, 0x35, 0xe1,
-	0x37, 0x65, 0x57, 0x13, 0xde, 0x7e, 0x0d, 0x50, 0x3c, 0x8a, 0x44, 0x87, 0x5a, 0xcf, 0x7e, 0x79,
-	0x74, 0x76, 0x32, 0x32, 0x4a, 0xd2, 0xe9, 0x3b, 0xaf, 0xa8, 0x3d, 0x1c, 0x66, 0x5b, 0x92, 0xda,
-	0x2a, 0xd9, 0x03, 0x92, 0x1d, 0x78, 0x47, 0x4e, 0xcf, 0xcb, 0xe2, 0xe5, 0x71, 0x15, 0x07, 0xfe,
-	0xf1, 0x8f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x40, 0x91, 0x36, 0x48, 0x3c, 0x06, 0x00, 0x00,
+	// 732 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0x8d, 0x1d, 0xe7, 0xa3, 0xe3, 0xd2, 0x5a, 0x4b, 0x55, 0xb9, 0x26, 0x87, 0x28, 0x20, 0x1a,
+	0x10, 0x0a, 0x28, 0xa8, 0xa8, 0x42, 0x14, 0xa9, 0x6d, 0x0c, 0x0a, 0x6a, 0x1d, 0xb3, 0x49, 0x05,
+	0xe2, 0x62, 0x25, 0xce, 0x16, 0xa2, 0x3a, 0x59, 0xb3, 0x71, 0x50, 0xf3, 0x5b, 0xf8, 0x4b, 0x1c,
+	0xb9, 0xf1, 0x1f, 0xb8, 0x73, 0x43, 0x3b, 0x76, 0xec, 0x12, 0xa2, 0xaa, 0x70, 0xf2, 0xcc, 0xee,
+	0x7b, 0x3b, 0x3b, 0xcf, 0xf3, 0x16, 0xd6, 0x43, 0x1e, 0x8c, 0xfc, 0x79, 0x23, 0x14, 0x3c, 0xe2,
+	0xa4, 0x18, 0x67, 0xb5, 0xef, 0xeb, 0x50, 0x74, 0x31, 0x24, 0x04, 0xb4, 0x49, 0x7f, 0xcc, 0x4c,
+	0xa5, 0xaa, 0xd4, 0xd7, 0x28, 0xc6, 0xa4, 0x02, 0x6b, 0xf2, 0x3b, 0x0d, 0xfb, 0x3e, 0x33, 0x55,
+	0xdc, 0xc8, 0x16, 0xc8, 0x43, 0x28, 0x04, 0xfd, 0x01, 0x0b, 0xcc, 0x7c, 0x35, 0x5f, 0xd7, 0x9b,
+	0x5b, 0x8d, 0xa4, 0x44, 0x7c, 0x60, 0xe3, 0x44, 0xee, 0xd1, 0x18, 0x42, 0x9e, 0x80, 0x16, 0xf2,
+	0xe1, 0xd4, 0xd4, 0xaa, 0x4a, 0x5d, 0x6f, 0x56, 0x56, 0x41, 0xbb, 0x2c, 0x60, 0x7e, 0xc4, 0x05,
+	0x45, 0x24, 0x79, 0x0e, 0x7a, 0x0c, 0xf2, 0xa2, 0x79, 0xc8, 0xcc, 0x42, 0x55, 0xa9, 0x6f, 0x34,
+	0x77, 0x96, 0x88, 0xf1, 0xa7, 0x37, 0x0f, 0x19, 0x85, 0x30, 0x8d, 0xc9, 0x01, 0xac, 0x8f, 0x26,
+	0x1f, 0x05, 0x9b, 0x4e, 0x3d, 0x31, 0x0b, 0x98, 0x59, 0xc4, 0x0b, 0x5a, 0x4b, 0xe4, 0x76, 0x0c,
+	0xa1, 0xb3, 0x80, 0x51, 0x7d, 0x94, 0x25, 0xb2, 0x34, 0xbb, 0xc2, 0x2e, 0x21, 0x7b, 0xb9, 0xb4,
+	0x9d, 0x91, 0x81, 0xa5, 0xb1, 0xf5, 0x18, 0x0a, 0xd8, 0x0d, 0x31, 0x20, 0x7f, 0xc1, 0xe6, 0x89,
+	0x9c, 0x32, 0x24, 0x5b, 0x50, 0xf8, 0xd2, 0x0f, 0x66, 0x0b, 0x25, 0xe3, 0xc4, 0xfa, 0xa9, 0xc2,
+	0xad, 0x3f, 0xfa, 0x27, 0x7b, 0xa0, 0x8f, 0xfb, 0x91, 0xff, 0xc9, 0x8b, 0xd5, 0x55, 0xae, 0x51,
+	0x17, 0x10, 0x18, 0x17, 0x7c, 0x07, 0x46, 0x4c, 0x63, 0x97, 0xa1, 0xbc, 0xce, 0x88, 0x4f, 0x4c,
+	0x15, 0xb9, 0x8f, 0xae, 0x93, 0x3b, 0xce, 0xec, 0x94, 0x43, 0x37, 0xf1, 0x94, 0x6c, 0xc1, 0xfa,
+	0xa6, 0xc0, 0xe6, 0x12, 0x68, 0x45, 0x77, 0x6f, 0xa1, 0xcc, 0x43, 0x26, 0xfa, 0x11, 0x17, 0xd8,
+	0xe0, 0x46, 0x73, 0xef, 0x5f, 0xca, 0x36, 0x3a, 0x09, 0x99, 0xa6, 0xc7, 0x64, 0x82, 0xc9, 0x01,
+	0x5b, 0x08, 0x56, 0x7b, 0x09, 0xe5, 0x05, 0x96, 0x14, 0x41, 0x6d, 0x3b, 0x46, 0x8e, 0x00, 0x14,
+	0x9d, 0x4e, 0xcf, 0x6b, 0x3b, 0x86, 0x22, 0x63, 0xfb, 0x7d, 0xbb, 0xdb, 0xeb, 0x1a, 0x2a, 0x21,
+	0xb0, 0xd1, 0xea, 0xd8, 0x5d, 0x4f, 0x6e, 0xe2, 0xa2, 0x91, 0xb7, 0x7e, 0xa8, 0xa0, 0xb9, 0x5c,
+	0x44, 0x64, 0x1f, 0xca, 0xe8, 0x06, 0x9f, 0xcb, 0x11, 0x96, 0x37, 0xae, 0xfc, 0x35, 0x5e, 0x22,
+	0x6a, 0xb8, 0x09, 0x86, 0xa6, 0x68, 0xb2, 0x2f, 0xa7, 0x59, 0x44, 0xd8, 0xbe, 0xde, 0xbc, 0xb7,
+	0x92, 0xc5, 0x45, 0xe4, 0xf4, 0xc7, 0xac, 0x23, 0x9c, 0xd9, 0x78, 0xc0, 0x70, 0xaa, 0x45, 0x44,
+	0x76, 0xa0, 0xcc, 0x26, 0x43, 0x0f, 0xd9, 0xd2, 0x0b, 0x05, 0x5a, 0x62, 0x93, 0xa1, 0x04, 0x5b,
+	0x5f, 0x15, 0x30, 0x96, 0x59, 0xe4, 0x00, 0x34, 0x1c, 0x7f, 0x05, 0xef, 0xf7, 0xe0, 0x26, 0x95,
+	0x1a, 0x68, 0x07, 0xa4, 0x91, 0x6d, 0x28, 0x4e, 0x70, 0x11, 0x7f, 0x49, 0x81, 0x26, 0x59, 0x6a,
+	0xf6, 0x7c, 0x66, 0xf6, 0x5a, 0x05, 0x34, 0x34, 0x8f, 0xd4, 0xf2, 0xec, 0xf4, 0xc8, 0xa6, 0x46,
+	0x8e, 0x94, 0x41, 0x73, 0x0e, 0x4f, 0x6d, 0x43, 0xa9, 0xdd, 0x87, 0xf2, 0x42, 0x08, 0x52, 0x82,
+	0x7c, 0xef, 0xd8, 0x35, 0x72, 0x32, 0x38, 0x6b, 0xb9, 0x86, 0x22, 0x71, 0xdd, 0xe3, 0x9e, 0x6b,
+	0xa8, 0xd6, 0x2f, 0x05, 0x34, 0x97, 0x31, 0x91, 0x3a, 0x5e, 0xb9, 0xb1, 0xe3, 0x5f, 0x00, 0xa4,
+	0x8f, 0xcb, 0xd4, 0x54, 0x6f, 0xc0, 0xbb, 0x82, 0x27, 0xcf, 0xa0, 0x3c, 0x0a, 0xbd, 0x41, 0xc0,
+	0xfd, 0x0b, 0x6c, 0x4b, 0x6f, 0xde, 0x59, 0x56, 0x8b, 0x31, 0xd1, 0x68, 0xbb, 0x47, 0x12, 0x42,
+	0x4b, 0xa3, 0x10, 0x03, 0x29, 0xc5, 0xf9, 0xe7, 0xe1, 0xc4, 0xd4, 0x70, 0xc6, 0x30, 0xb6, 0xf6,
+	0xa0, 0xd4, 0x76, 0xd3, 0x6d, 0x7f, 0x34, 0x14, 0x8b, 0x67, 0x51, 0xc6, 0x52, 0x55, 0x76, 0xe9,
+	0xb3, 0x30, 0x42, 0x7f, 0xad, 0xd1, 0x24, 0xb3, 0x3c, 0xd0, 0xaf, 0xbc, 0x29, 0x64, 0x37, 0x9d,
+	0x12, 0x69, 0xc2, 0xdb, 0x2b, 0xfe, 0x5d, 0x32, 0x14, 0xbb, 0xa0, 0x9d, 0x0b, 0x3e, 0x36, 0xd5,
+	0xd5, 0x40, 0x26, 0xa7, 0x47, 0x02, 0xac, 0x0f, 0x00, 0xf6, 0x7f, 0x9c, 0x7f, 0x17, 0xd4, 0x88,
+	0x5f, 0x77, 0xba, 0x1a, 0xf1, 0xda, 0x1b, 0x80, 0xec, 0x35, 0x25, 0x3a, 0x94, 0x5a, 0xf6, 0xab,
+	0xc3, 0xb3, 0x93, 0x9e, 0x91, 0x93, 0x49, 0xdb, 0x79, 0x4d, 0xed, 0x6e, 0x37, 0xb1, 0x57, 0x1c,
+	0xab, 0x64, 0x1b, 0x48, 0xb2, 0xe1, 0x1d, 0x3a, 0x2d, 0x2f, 0x59, 0xcf, 0x0f, 0x8a, 0xe8, 0x94,
+	0xa7, 0xbf, 0x07, 0x00, 0x54, 0x61, 0xb8, 0x0d, 0x75, 0x06, 0x00, 0x00,
 }
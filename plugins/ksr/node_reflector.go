@@ -134,10 +134,23 @@ func (nr *NodeReflector) nodeToProto(k8sNode *coreV1.Node) *node.Node {
 	nodeProto.Provider_ID = k8sNode.Spec.ProviderID
 	nodeProto.Addresses = getNodeAddresses(k8sNode.Status.Addresses)
 	nodeProto.NodeInfo = getNodeInfo(k8sNode.Status.NodeInfo)
+	nodeProto.Labels = getNodeLabels(k8sNode.Labels)
 
 	return nodeProto
 }
 
+// getNodeLabels returns a copy of the node labels.
+func getNodeLabels(k8sLabels map[string]string) map[string]string {
+	if len(k8sLabels) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(k8sLabels))
+	for key, value := range k8sLabels {
+		labels[key] = value
+	}
+	return labels
+}
+
 // getNodeAddresses converts node addresses from the k8s representation
 // into the corresponding contiv protobuf-modelled data format.
 func getNodeAddresses(k8sAddrs []coreV1.NodeAddress) []*node.NodeAddress {
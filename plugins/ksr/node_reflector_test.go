@@ -74,6 +74,11 @@ func TestNodeReflector(t *testing.T) {
 				Generation:      1,
 				CreationTimestamp: metaV1.Date(2018, 01, 14, 18, 53, 37, 0,
 					time.FixedZone("PST", -800)),
+				Labels: map[string]string{
+					"topology.kubernetes.io/zone":              "zone-a",
+					"failure-domain.beta.kubernetes.io/region": "region-1",
+					"kubernetes.io/os":                         "linux",
+				},
 			},
 			Spec: coreV1.NodeSpec{
 				PodCIDR:    "10.20.30.40/24",
@@ -304,6 +309,8 @@ func testUpdateNode(t *testing.T) {
 	gomega.Ω(err).Should(gomega.Succeed())
 
 	protoNode1 := nodeTestVars.nodeReflector.nodeToProto(k8sNodeOld)
+	checkNodeToProtoTranslation(t, protoNode1, k8sNodeOld)
+	gomega.Expect(protoNode1.Labels).To(gomega.HaveLen(3))
 	nodeTestVars.mockKvBroker.Put(node.Key(k8sNodeOld.GetName()), protoNode1)
 
 	// Take a snapshot of counters
@@ -345,6 +352,10 @@ func checkNodeToProtoTranslation(t *testing.T, protoNode *node.Node, k8sNode *co
 	gomega.Expect(protoNode.Pod_CIDR).To(gomega.Equal(k8sNode.Spec.PodCIDR))
 	gomega.Expect(protoNode.Provider_ID).To(gomega.Equal(k8sNode.Spec.ProviderID))
 
+	for key, value := range k8sNode.Labels {
+		gomega.Expect(protoNode.Labels).To(gomega.HaveKeyWithValue(key, value))
+	}
+
 	gomega.Expect(protoNode.NodeInfo.Architecture).To(gomega.Equal(k8sNode.Status.NodeInfo.Architecture))
 	gomega.Expect(protoNode.NodeInfo.Boot_ID).To(gomega.Equal(k8sNode.Status.NodeInfo.BootID))
 	gomega.Expect(protoNode.NodeInfo.ContainerRuntimeVersion).
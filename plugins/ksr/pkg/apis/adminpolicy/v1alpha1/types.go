@@ -0,0 +1,167 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AdminNetworkPolicy is a cluster-scoped network policy defined by cluster
+// administrators. Admin network policies are evaluated before namespaced
+// NetworkPolicies and cannot be overridden by them.
+type AdminNetworkPolicy struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	meta_v1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object
+	meta_v1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the specification of the admin network policy.
+	Spec AdminNetworkPolicySpec `json:"spec"`
+}
+
+// AdminNetworkPolicySpec defines the desired state of AdminNetworkPolicy.
+type AdminNetworkPolicySpec struct {
+	// Priority is a value from 0 to 1000. Policies with lower priority values
+	// are evaluated first.
+	Priority int32 `json:"priority"`
+	// Subject defines the pods to which this policy applies.
+	Subject AdminNetworkPolicySubject `json:"subject"`
+	// Ingress is the list of ingress rules evaluated in the order as defined.
+	Ingress []AdminNetworkPolicyIngressRule `json:"ingress,omitempty"`
+	// Egress is the list of egress rules evaluated in the order as defined.
+	Egress []AdminNetworkPolicyEgressRule `json:"egress,omitempty"`
+}
+
+// AdminNetworkPolicySubject selects pods either by namespaces or by namespaces
+// and pod labels. Exactly one field must be set.
+type AdminNetworkPolicySubject struct {
+	// Namespaces selects all pods in the selected namespaces.
+	Namespaces *meta_v1.LabelSelector `json:"namespaces,omitempty"`
+	// Pods selects pods by labels in the selected namespaces.
+	Pods *NamespacedPod `json:"pods,omitempty"`
+}
+
+// NamespacedPod selects pods by labels in the namespaces selected by labels.
+type NamespacedPod struct {
+	// NamespaceSelector selects namespaces (empty selector selects all).
+	NamespaceSelector meta_v1.LabelSelector `json:"namespaceSelector"`
+	// PodSelector selects pods in the selected namespaces (empty selector selects all).
+	PodSelector meta_v1.LabelSelector `json:"podSelector"`
+}
+
+// AdminNetworkPolicyRuleAction is the action applied to the traffic matched
+// by a rule.
+type AdminNetworkPolicyRuleAction string
+
+const (
+	// AdminNetworkPolicyRuleActionAllow allows the matched traffic regardless
+	// of namespaced policies.
+	AdminNetworkPolicyRuleActionAllow AdminNetworkPolicyRuleAction = "Allow"
+	// AdminNetworkPolicyRuleActionDeny blocks the matched traffic regardless
+	// of namespaced policies.
+	AdminNetworkPolicyRuleActionDeny AdminNetworkPolicyRuleAction = "Deny"
+	// AdminNetworkPolicyRuleActionPass skips the remaining admin policy rules
+	// and delegates the decision to namespaced policies.
+	AdminNetworkPolicyRuleActionPass AdminNetworkPolicyRuleAction = "Pass"
+)
+
+// AdminNetworkPolicyIngressRule describes an action to take on the traffic
+// coming from the selected peers to the subject pods.
+type AdminNetworkPolicyIngressRule struct {
+	// Name is an optional identifier of the rule.
+	Name string `json:"name,omitempty"`
+	// Action to take for the matched traffic.
+	Action AdminNetworkPolicyRuleAction `json:"action"`
+	// From lists the sources of the traffic (combined with logical OR).
+	From []AdminNetworkPolicyIngressPeer `json:"from"`
+	// Ports restricts the rule to the given destination ports (all ports if nil).
+	Ports *[]AdminNetworkPolicyPort `json:"ports,omitempty"`
+}
+
+// AdminNetworkPolicyEgressRule describes an action to take on the traffic
+// sent from the subject pods to the selected peers.
+type AdminNetworkPolicyEgressRule struct {
+	// Name is an optional identifier of the rule.
+	Name string `json:"name,omitempty"`
+	// Action to take for the matched traffic.
+	Action AdminNetworkPolicyRuleAction `json:"action"`
+	// To lists the destinations of the traffic (combined with logical OR).
+	To []AdminNetworkPolicyEgressPeer `json:"to"`
+	// Ports restricts the rule to the given destination ports (all ports if nil).
+	Ports *[]AdminNetworkPolicyPort `json:"ports,omitempty"`
+}
+
+// AdminNetworkPolicyIngressPeer selects sources of the ingress traffic.
+// Exactly one field must be set.
+type AdminNetworkPolicyIngressPeer struct {
+	// Namespaces selects all pods in the selected namespaces.
+	Namespaces *meta_v1.LabelSelector `json:"namespaces,omitempty"`
+	// Pods selects pods by labels in the selected namespaces.
+	Pods *NamespacedPod `json:"pods,omitempty"`
+}
+
+// AdminNetworkPolicyEgressPeer selects destinations of the egress traffic.
+// Exactly one field must be set.
+type AdminNetworkPolicyEgressPeer struct {
+	// Namespaces selects all pods in the selected namespaces.
+	Namespaces *meta_v1.LabelSelector `json:"namespaces,omitempty"`
+	// Pods selects pods by labels in the selected namespaces.
+	Pods *NamespacedPod `json:"pods,omitempty"`
+	// Networks selects destinations by CIDRs.
+	Networks []CIDR `json:"networks,omitempty"`
+}
+
+// CIDR is an IP network in the CIDR notation (e.g. "10.0.0.0/8").
+type CIDR string
+
+// AdminNetworkPolicyPort selects destination port(s). Exactly one field must be set.
+type AdminNetworkPolicyPort struct {
+	// PortNumber selects a single port.
+	PortNumber *Port `json:"portNumber,omitempty"`
+	// PortRange selects a range of ports.
+	PortRange *PortRange `json:"portRange,omitempty"`
+}
+
+// Port selects a single port of the given protocol.
+type Port struct {
+	// Protocol is TCP (default), UDP or SCTP.
+	Protocol core_v1.Protocol `json:"protocol"`
+	// Port is the port number.
+	Port int32 `json:"port"`
+}
+
+// PortRange selects an inclusive range of ports of the given protocol.
+type PortRange struct {
+	// Protocol is TCP (default), UDP or SCTP.
+	Protocol core_v1.Protocol `json:"protocol,omitempty"`
+	// Start is the first port of the range.
+	Start int32 `json:"start"`
+	// End is the last port of the range.
+	End int32 `json:"end"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AdminNetworkPolicyList is a list of AdminNetworkPolicy resources.
+type AdminNetworkPolicyList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata"`
+
+	Items []AdminNetworkPolicy `json:"items"`
+}
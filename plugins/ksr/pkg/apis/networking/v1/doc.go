@@ -0,0 +1,21 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +k8s:deepcopy-gen=package
+// +groupName=networking.k8s.io
+
+// Package v1 defines the Kubernetes NetworkPolicy API (networking.k8s.io/v1)
+// including the endPort field of NetworkPolicyPort, which is not available
+// in the version of the K8s client library used by Contiv-VPP.
+package v1
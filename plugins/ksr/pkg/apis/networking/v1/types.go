@@ -0,0 +1,90 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	core_v1 "k8s.io/api/core/v1"
+	networking_v1 "k8s.io/api/networking/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NetworkPolicy describes what network traffic is allowed for a set of pods.
+// The type follows the K8s NetworkPolicy, only the ports are extended with
+// the endPort field.
+type NetworkPolicy struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	meta_v1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object
+	meta_v1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the specification of the desired behavior for this NetworkPolicy.
+	Spec NetworkPolicySpec `json:"spec,omitempty"`
+}
+
+// NetworkPolicySpec provides the specification of a NetworkPolicy.
+type NetworkPolicySpec struct {
+	// PodSelector selects the pods to which this policy applies.
+	PodSelector meta_v1.LabelSelector `json:"podSelector"`
+	// Ingress is a list of ingress rules to be applied to the selected pods.
+	Ingress []NetworkPolicyIngressRule `json:"ingress,omitempty"`
+	// Egress is a list of egress rules to be applied to the selected pods.
+	Egress []NetworkPolicyEgressRule `json:"egress,omitempty"`
+	// PolicyTypes lists the rule types that the policy relates to.
+	PolicyTypes []networking_v1.PolicyType `json:"policyTypes,omitempty"`
+}
+
+// NetworkPolicyIngressRule describes a particular set of traffic that is allowed
+// to the pods matched by the PodSelector of the policy.
+type NetworkPolicyIngressRule struct {
+	// Ports is a list of ports which should be made accessible (all ports if empty).
+	Ports []NetworkPolicyPort `json:"ports,omitempty"`
+	// From is a list of sources which should be able to access the pods
+	// (all sources if empty).
+	From []networking_v1.NetworkPolicyPeer `json:"from,omitempty"`
+}
+
+// NetworkPolicyEgressRule describes a particular set of traffic that is allowed
+// out of the pods matched by the PodSelector of the policy.
+type NetworkPolicyEgressRule struct {
+	// Ports is a list of destination ports for outgoing traffic (all ports if empty).
+	Ports []NetworkPolicyPort `json:"ports,omitempty"`
+	// To is a list of destinations for outgoing traffic (all destinations if empty).
+	To []networking_v1.NetworkPolicyPeer `json:"to,omitempty"`
+}
+
+// NetworkPolicyPort describes a port (or a range of ports) to allow traffic on.
+type NetworkPolicyPort struct {
+	// Protocol (TCP, UDP or SCTP) which traffic must match (TCP if not set).
+	Protocol *core_v1.Protocol `json:"protocol,omitempty"`
+	// Port on the given protocol, either a numerical or a named port
+	// (all ports if not set).
+	Port *intstr.IntOrString `json:"port,omitempty"`
+	// EndPort indicates that the range of ports from Port to EndPort (inclusive)
+	// should be allowed. It cannot be set if Port is not set or is a named port.
+	EndPort *int32 `json:"endPort,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NetworkPolicyList is a list of NetworkPolicy resources.
+type NetworkPolicyList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata"`
+
+	Items []NetworkPolicy `json:"items"`
+}
@@ -15,6 +15,7 @@
 //go:generate protoc -I ./model/pod --go_out=plugins=grpc:./model/pod ./model/pod/pod.proto
 //go:generate protoc -I ./model/namespace --go_out=plugins=grpc:./model/namespace ./model/namespace/namespace.proto
 //go:generate protoc -I ./model/policy --go_out=plugins=grpc:./model/policy ./model/policy/policy.proto
+//go:generate protoc -I ./model/adminpolicy --go_out=plugins=grpc:./model/adminpolicy ./model/adminpolicy/adminpolicy.proto
 //go:generate protoc -I ./model/service --go_out=plugins=grpc:./model/service ./model/service/service.proto
 //go:generate protoc -I ./model/endpoints --go_out=plugins=grpc:./model/endpoints ./model/endpoints/endpoints.proto
 //go:generate protoc -I ./model/node --go_out=plugins=grpc:./model/node ./model/node/node.proto
@@ -30,7 +31,9 @@ import (
 	"time"
 
 	"github.com/contiv/vpp/plugins/ksr/model/ksrapi"
+	anpV1alpha1 "github.com/contiv/vpp/plugins/ksr/pkg/apis/adminpolicy/v1alpha1"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -60,13 +63,14 @@ type Plugin struct {
 	k8sClientConfig *rest.Config
 	k8sClientset    *kubernetes.Clientset
 
-	nsReflector        *NamespaceReflector
-	podReflector       *PodReflector
-	policyReflector    *PolicyReflector
-	serviceReflector   *ServiceReflector
-	endpointsReflector *EndpointsReflector
-	nodeReflector      *NodeReflector
-	sfcPodReflector    *SfcPodReflector
+	nsReflector          *NamespaceReflector
+	podReflector         *PodReflector
+	policyReflector      *PolicyReflector
+	adminPolicyReflector *AdminPolicyReflector
+	serviceReflector     *ServiceReflector
+	endpointsReflector   *EndpointsReflector
+	nodeReflector        *NodeReflector
+	sfcPodReflector      *SfcPodReflector
 
 	reflectorRegistry *ReflectorRegistry
 
@@ -104,14 +108,15 @@ type Deps struct {
 
 // Reflector object types
 const (
-	namespaceObjType = "Namespace"
-	podObjType       = "Pod"
-	policyObjType    = "NetworkPolicy"
-	endpointsObjType = "Endpoints"
-	serviceObjType   = "Service"
-	nodeObjType      = "Node"
-	sfcPodObjType    = "SfcPod"
-	electionPrefix   = "/contiv-ksr/election"
+	namespaceObjType   = "Namespace"
+	podObjType         = "Pod"
+	policyObjType      = "NetworkPolicy"
+	adminPolicyObjType = "AdminNetworkPolicy"
+	endpointsObjType   = "Endpoints"
+	serviceObjType     = "Service"
+	nodeObjType        = "Node"
+	sfcPodObjType      = "SfcPod"
+	electionPrefix     = "/contiv-ksr/election"
 )
 
 // Init builds K8s client-set based on the supplied kubeconfig and initializes
@@ -178,8 +183,13 @@ func (plugin *Plugin) Init() error {
 		return err
 	}
 
+	policyClient, err := newPolicyRESTClient(plugin.k8sClientConfig)
+	if err != nil {
+		return fmt.Errorf("failed to build network policy client: %s", err)
+	}
 	plugin.policyReflector = &PolicyReflector{
-		Reflector: plugin.newReflector("-policy", policyObjType, broker),
+		Reflector:  plugin.newReflector("-policy", policyObjType, broker),
+		restClient: policyClient,
 	}
 	//plugin.policyReflector.Log.SetLevel(logging.DebugLevel)
 	err = plugin.policyReflector.Init(plugin.stopCh, &plugin.wg)
@@ -188,6 +198,32 @@ func (plugin *Plugin) Init() error {
 		return err
 	}
 
+	// admin network policies are reflected only if their API is served by the cluster,
+	// otherwise the reflector would never sync and block the data store resync
+	hasAdminPolicyAPI, err := plugin.hasAPIResource(anpV1alpha1.SchemeGroupVersion.String(), adminPolicyResource)
+	if err != nil {
+		return fmt.Errorf("failed to discover admin network policy API: %s", err)
+	}
+	if hasAdminPolicyAPI {
+		adminPolicyClient, err := newAdminPolicyRESTClient(plugin.k8sClientConfig)
+		if err != nil {
+			return fmt.Errorf("failed to build admin network policy client: %s", err)
+		}
+		plugin.adminPolicyReflector = &AdminPolicyReflector{
+			Reflector:  plugin.newReflector("-adminPolicy", adminPolicyObjType, broker),
+			restClient: adminPolicyClient,
+		}
+		//plugin.adminPolicyReflector.Log.SetLevel(logging.DebugLevel)
+		err = plugin.adminPolicyReflector.Init(plugin.stopCh, &plugin.wg)
+		if err != nil {
+			plugin.Log.WithField("rwErr", err).Error("Failed to initialize Admin Policy reflector")
+			return err
+		}
+	} else {
+		plugin.Log.Warnf("Resource %s/%s is not served by the cluster (CRD not installed?), "+
+			"admin network policies will not be reflected", anpV1alpha1.SchemeGroupVersion, adminPolicyResource)
+	}
+
 	plugin.serviceReflector = &ServiceReflector{
 		Reflector: plugin.newReflector("-service", serviceObjType, broker),
 	}
@@ -270,6 +306,9 @@ func (plugin *Plugin) Close() error {
 	plugin.cancelFunc()
 	safeclose.CloseAll(plugin.nsReflector, plugin.podReflector, plugin.policyReflector,
 		plugin.serviceReflector, plugin.endpointsReflector)
+	if plugin.adminPolicyReflector != nil {
+		safeclose.CloseAll(plugin.adminPolicyReflector)
+	}
 	plugin.wg.Wait()
 	return nil
 }
@@ -294,6 +333,24 @@ func (plugin *Plugin) monitorEtcdStatus(closeCh chan struct{}) {
 	}
 }
 
+// hasAPIResource returns true if the given resource of the given API group version
+// is served by the k8s API server.
+func (plugin *Plugin) hasAPIResource(groupVersion, resource string) (bool, error) {
+	resources, err := plugin.k8sClientset.Discovery().ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, apiResource := range resources.APIResources {
+		if apiResource.Name == resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // newReflector returns a new instance of KSR Reflector
 func (plugin *Plugin) newReflector(logName string, objType string, broker KeyProtoValBroker) Reflector {
 	return Reflector{
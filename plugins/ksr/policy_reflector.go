@@ -15,21 +15,37 @@
 package ksr
 
 import (
+	"fmt"
 	"reflect"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/golang/protobuf/proto"
+	"go.ligato.io/cn-infra/v2/logging"
 
 	coreV1 "k8s.io/api/core/v1"
 	networkingV1 "k8s.io/api/networking/v1"
 	clientApiMetaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/contiv/vpp/plugins/ksr/model/policy"
+	npV1 "github.com/contiv/vpp/plugins/ksr/pkg/apis/networking/v1"
+)
+
+const (
+	// egressFQDNAnnotation is a k8s network policy annotation used to allow egress
+	// traffic of the selected pods towards DNS names, as a comma-separated list
+	// of names, each optionally followed by ":<port>[-<end-port>][/<protocol>]"
+	// (e.g. "api.example.com:443,ntp.example.com:123/UDP,files.example.com").
+	// Applied only to policies which apply to egress.
+	egressFQDNAnnotation = contivAnnotationPrefix + "/egress-fqdn"
 )
 
 // PolicyReflector subscribes to K8s cluster to watch for changes
@@ -37,6 +53,11 @@ import (
 // Protobuf-modelled changes are published into the selected key-value store.
 type PolicyReflector struct {
 	Reflector
+
+	// restClient is a REST client for the networking.k8s.io/v1 API decoding
+	// network policies including the endPort field of the policy ports,
+	// which is not known to the standard client-set.
+	restClient rest.Interface
 }
 
 // Init subscribes to K8s cluster to watch for changes in the configuration
@@ -59,7 +80,7 @@ func (pr *PolicyReflector) Init(stopCh2 <-chan struct{}, wg *sync.WaitGroup) err
 			return &policy.Policy{}
 		},
 		K8s2NodeFunc: func(k8sObj interface{}) (interface{}, string, bool) {
-			k8sPolicy, ok := k8sObj.(*networkingV1.NetworkPolicy)
+			k8sPolicy, ok := k8sObj.(*npV1.NetworkPolicy)
 			if !ok {
 				pr.Log.Errorf("service syncDataStore: wrong object type %s, obj %+v",
 					reflect.TypeOf(k8sObj), k8sObj)
@@ -68,13 +89,29 @@ func (pr *PolicyReflector) Init(stopCh2 <-chan struct{}, wg *sync.WaitGroup) err
 			return pr.policyToProto(k8sPolicy), policy.Key(k8sPolicy.Name, k8sPolicy.Namespace), true
 		},
 		K8sClntGetFunc: func(cs *kubernetes.Clientset) rest.Interface {
-			// Use NetworkingV1 API client for policies
-			return cs.NetworkingV1().RESTClient()
+			return pr.restClient
 		},
 	}
 
 	return pr.ksrInit(stopCh2, wg, policy.KeyPrefix(), "networkpolicies",
-		&networkingV1.NetworkPolicy{}, policyReflectorFuncs)
+		&npV1.NetworkPolicy{}, policyReflectorFuncs)
+}
+
+// newPolicyRESTClient builds REST client for the NetworkPolicy API.
+func newPolicyRESTClient(k8sClientConfig *rest.Config) (rest.Interface, error) {
+	scheme := runtime.NewScheme()
+	if err := npV1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	config := *k8sClientConfig
+	config.GroupVersion = &npV1.SchemeGroupVersion
+	config.APIPath = "/apis"
+	config.ContentType = runtime.ContentTypeJSON
+	config.NegotiatedSerializer = serializer.NewCodecFactory(scheme).WithoutConversion()
+	if config.UserAgent == "" {
+		config.UserAgent = rest.DefaultKubernetesUserAgent()
+	}
+	return rest.RESTClientFor(&config)
 }
 
 // addPolicy adds state data of a newly created K8s pod into the data
@@ -82,7 +119,7 @@ func (pr *PolicyReflector) Init(stopCh2 <-chan struct{}, wg *sync.WaitGroup) err
 func (pr *PolicyReflector) addPolicy(obj interface{}) {
 	pr.Log.WithField("policy", obj).Info("Policy added")
 
-	k8sPolicy, ok := obj.(*networkingV1.NetworkPolicy)
+	k8sPolicy, ok := obj.(*npV1.NetworkPolicy)
 	if !ok {
 		pr.Log.Warn("Failed to cast newly created policy object")
 		pr.stats.ArgErrors++
@@ -99,7 +136,7 @@ func (pr *PolicyReflector) addPolicy(obj interface{}) {
 func (pr *PolicyReflector) deletePolicy(obj interface{}) {
 	pr.Log.WithField("policy", obj).Info("Policy updated")
 
-	k8sPolicy, ok := obj.(*networkingV1.NetworkPolicy)
+	k8sPolicy, ok := obj.(*npV1.NetworkPolicy)
 	if !ok {
 		pr.Log.Warn("Failed to cast newly created service object")
 		pr.stats.ArgErrors++
@@ -113,8 +150,8 @@ func (pr *PolicyReflector) deletePolicy(obj interface{}) {
 // updatePolicy updates state data of a changes K8s network policy in the data
 // store.
 func (pr *PolicyReflector) updatePolicy(oldObj, newObj interface{}) {
-	oldK8sPolicy, ok1 := oldObj.(*networkingV1.NetworkPolicy)
-	newK8sPolicy, ok2 := newObj.(*networkingV1.NetworkPolicy)
+	oldK8sPolicy, ok1 := oldObj.(*npV1.NetworkPolicy)
+	newK8sPolicy, ok2 := newObj.(*npV1.NetworkPolicy)
 	if !ok1 || !ok2 {
 		pr.Log.Warn("Failed to cast changed service object")
 		pr.stats.ArgErrors++
@@ -129,9 +166,17 @@ func (pr *PolicyReflector) updatePolicy(oldObj, newObj interface{}) {
 	pr.ksrUpdate(key, oldPolicyProto, newPolicyProto)
 }
 
+// PolicyToProto converts K8s network policy into the protobuf-modelled data
+// structure, the same way the policy is reflected into the data store.
+// It is used to evaluate policies not yet applied in the cluster.
+func PolicyToProto(k8sPolicy *npV1.NetworkPolicy, log logging.Logger) *policy.Policy {
+	pr := &PolicyReflector{Reflector: Reflector{Log: log}}
+	return pr.policyToProto(k8sPolicy)
+}
+
 // policyToProto converts pod state data from the k8s representation into
 // our protobuf-modelled data structure.
-func (pr *PolicyReflector) policyToProto(k8sPolicy *networkingV1.NetworkPolicy) *policy.Policy {
+func (pr *PolicyReflector) policyToProto(k8sPolicy *npV1.NetworkPolicy) *policy.Policy {
 	policyProto := &policy.Policy{}
 
 	// Name
@@ -207,9 +252,114 @@ func (pr *PolicyReflector) policyToProto(k8sPolicy *networkingV1.NetworkPolicy)
 			policyProto.EgressRule = append(policyProto.EgressRule, egressProto)
 		}
 	}
+
+	// Egress rules towards DNS names
+	if fqdnRules := pr.fqdnRulesToProto(k8sPolicy); len(fqdnRules) > 0 {
+		if appliesToEgress(policyProto) {
+			policyProto.EgressRule = append(policyProto.EgressRule, fqdnRules...)
+		} else {
+			// do not turn the policy into egress-isolating (would block DNS etc.)
+			pr.Log.Warnf("Ignoring the %s annotation of the policy %s/%s, which does not apply "+
+				"to egress (add Egress into policyTypes)", egressFQDNAnnotation,
+				k8sPolicy.GetNamespace(), k8sPolicy.GetName())
+		}
+	}
 	return policyProto
 }
 
+// appliesToEgress returns true if the policy isolates egress of the selected pods.
+// Policy without explicit types applies to egress only if it has egress rules.
+func appliesToEgress(policyProto *policy.Policy) bool {
+	switch policyProto.PolicyType {
+	case policy.Policy_EGRESS, policy.Policy_INGRESS_AND_EGRESS:
+		return true
+	case policy.Policy_DEFAULT:
+		return len(policyProto.EgressRule) > 0
+	}
+	return false
+}
+
+// fqdnRulesToProto converts the egress-fqdn annotation of the k8s policy into
+// egress rules of our protobuf-modelled data structure, one rule per DNS name.
+// Invalid items of the annotation are skipped.
+func (pr *PolicyReflector) fqdnRulesToProto(k8sPolicy *npV1.NetworkPolicy) (rulesProto []*policy.Policy_EgressRule) {
+	annotation, hasAnnotation := k8sPolicy.GetAnnotations()[egressFQDNAnnotation]
+	if !hasAnnotation {
+		return nil
+	}
+	for _, item := range strings.Split(annotation, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		ruleProto, err := fqdnRuleToProto(item)
+		if err != nil {
+			pr.Log.Warnf("Skipping item %q of the %s annotation of the policy %s/%s: %v",
+				item, egressFQDNAnnotation, k8sPolicy.GetNamespace(), k8sPolicy.GetName(), err)
+			continue
+		}
+		rulesProto = append(rulesProto, ruleProto)
+	}
+	return rulesProto
+}
+
+// fqdnRuleToProto parses a single item of the egress-fqdn annotation
+// ("<name>[:<port>[-<end-port>]][/<protocol>]").
+func fqdnRuleToProto(item string) (*policy.Policy_EgressRule, error) {
+	var portProto *policy.Policy_Port
+	name := item
+	if idx := strings.Index(name, "/"); idx != -1 {
+		portProto = &policy.Policy_Port{
+			// all ports of the protocol unless the port number is given
+			Port: &policy.Policy_Port_PortNameOrNumber{Type: policy.Policy_Port_PortNameOrNumber_NUMBER},
+		}
+		switch strings.ToUpper(name[idx+1:]) {
+		case "TCP":
+			portProto.Protocol = policy.Policy_Port_TCP
+		case "UDP":
+			portProto.Protocol = policy.Policy_Port_UDP
+		case "SCTP":
+			portProto.Protocol = policy.Policy_Port_SCTP
+		default:
+			return nil, fmt.Errorf("unsupported protocol %q", name[idx+1:])
+		}
+		name = name[:idx]
+	}
+	if idx := strings.Index(name, ":"); idx != -1 {
+		if portProto == nil {
+			portProto = &policy.Policy_Port{}
+		}
+		ports := strings.SplitN(name[idx+1:], "-", 2)
+		port, err := strconv.ParseUint(ports[0], 10, 16)
+		if err != nil || port == 0 {
+			return nil, fmt.Errorf("invalid port %q", ports[0])
+		}
+		portProto.Port = &policy.Policy_Port_PortNameOrNumber{
+			Type:   policy.Policy_Port_PortNameOrNumber_NUMBER,
+			Number: int32(port),
+		}
+		if len(ports) == 2 {
+			endPort, err := strconv.ParseUint(ports[1], 10, 16)
+			if err != nil || endPort < port {
+				return nil, fmt.Errorf("invalid end port %q", ports[1])
+			}
+			portProto.EndPort = int32(endPort)
+		}
+		name = name[:idx]
+	}
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	if name == "" || strings.Contains(name, "*") {
+		return nil, fmt.Errorf("invalid DNS name %q (wildcards are not supported)", name)
+	}
+	ruleProto := &policy.Policy_EgressRule{
+		To: []*policy.Policy_Peer{{Fqdn: []string{name}}},
+	}
+	if portProto != nil {
+		ruleProto.Port = []*policy.Policy_Port{portProto}
+	}
+	return ruleProto, nil
+}
+
 // labelSelectorToProto converts label selector from the k8s representation into
 // our protobuf-modelled data structure.
 func (pr *PolicyReflector) labelSelectorToProto(selector *clientApiMetaV1.LabelSelector) *policy.Policy_LabelSelector {
@@ -260,7 +410,7 @@ func (pr *PolicyReflector) labelSelectorToProto(selector *clientApiMetaV1.LabelS
 
 // portsToProto converts a list of ports from the k8s representation into
 // our protobuf-modelled data structure.
-func (pr *PolicyReflector) portsToProto(ports []networkingV1.NetworkPolicyPort) (portsProto []*policy.Policy_Port) {
+func (pr *PolicyReflector) portsToProto(ports []npV1.NetworkPolicyPort) (portsProto []*policy.Policy_Port) {
 	for _, port := range ports {
 		portProto := &policy.Policy_Port{}
 		// Protocol
@@ -270,6 +420,8 @@ func (pr *PolicyReflector) portsToProto(ports []networkingV1.NetworkPolicyPort)
 				portProto.Protocol = policy.Policy_Port_TCP
 			case coreV1.ProtocolUDP:
 				portProto.Protocol = policy.Policy_Port_UDP
+			case coreV1.ProtocolSCTP:
+				portProto.Protocol = policy.Policy_Port_SCTP
 			}
 		}
 		// Port number/name
@@ -284,6 +436,10 @@ func (pr *PolicyReflector) portsToProto(ports []networkingV1.NetworkPolicyPort)
 				portProto.Port.Name = port.Port.StrVal
 			}
 		}
+		// End of the port range
+		if port.EndPort != nil {
+			portProto.EndPort = *port.EndPort
+		}
 		// append port
 		portsProto = append(portsProto, portProto)
 	}
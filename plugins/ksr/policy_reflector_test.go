@@ -24,6 +24,7 @@ import (
 	"github.com/contiv/vpp/plugins/ksr/model/ksrapi"
 	"go.ligato.io/cn-infra/v2/health/statuscheck/model/status"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/onsi/gomega"
 
 	coreV1 "k8s.io/api/core/v1"
@@ -33,6 +34,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/contiv/vpp/plugins/ksr/model/policy"
+	npV1 "github.com/contiv/vpp/plugins/ksr/pkg/apis/networking/v1"
 	"go.ligato.io/cn-infra/v2/logging"
 )
 
@@ -40,7 +42,7 @@ type PolicyTestVars struct {
 	k8sListWatch      *mockK8sListWatch
 	mockKvBroker      *mockKeyProtoValBroker
 	policyReflector   *PolicyReflector
-	policyTestData    []networkingV1.NetworkPolicy
+	policyTestData    []npV1.NetworkPolicy
 	reflectorRegistry ReflectorRegistry
 }
 
@@ -71,7 +73,7 @@ func TestPolicyReflector(t *testing.T) {
 
 	var pprotTCP coreV1.Protocol = "TCP"
 
-	policyTestVars.policyTestData = []networkingV1.NetworkPolicy{
+	policyTestVars.policyTestData = []npV1.NetworkPolicy{
 		// Test data 0: mocks a new object to be added or a "pre-existing"
 		// object that is updated during sync
 		{
@@ -85,14 +87,14 @@ func TestPolicyReflector(t *testing.T) {
 				CreationTimestamp: metaV1.Date(2018, 01, 14, 18, 53, 37, 0,
 					time.FixedZone("PST", -800)),
 			},
-			Spec: networkingV1.NetworkPolicySpec{
+			Spec: npV1.NetworkPolicySpec{
 				PodSelector: metaV1.LabelSelector{
 					MatchLabels:      map[string]string{"role": "db"},
 					MatchExpressions: []metaV1.LabelSelectorRequirement{},
 				},
-				Ingress: []networkingV1.NetworkPolicyIngressRule{
+				Ingress: []npV1.NetworkPolicyIngressRule{
 					{
-						Ports: []networkingV1.NetworkPolicyPort{
+						Ports: []npV1.NetworkPolicyPort{
 							{
 								Protocol: &pprotTCP,
 								Port: &intstr.IntOrString{
@@ -126,9 +128,9 @@ func TestPolicyReflector(t *testing.T) {
 						},
 					},
 				},
-				Egress: []networkingV1.NetworkPolicyEgressRule{
+				Egress: []npV1.NetworkPolicyEgressRule{
 					{
-						Ports: []networkingV1.NetworkPolicyPort{
+						Ports: []npV1.NetworkPolicyPort{
 							{
 								Protocol: &pprotTCP,
 								Port: &intstr.IntOrString{
@@ -166,12 +168,12 @@ func TestPolicyReflector(t *testing.T) {
 				CreationTimestamp: metaV1.Date(2018, 01, 14, 18, 53, 37, 0,
 					time.FixedZone("PST", -800)),
 			},
-			Spec: networkingV1.NetworkPolicySpec{
+			Spec: npV1.NetworkPolicySpec{
 				PodSelector: metaV1.LabelSelector{
 					MatchLabels:      map[string]string{"run": "nginx"},
 					MatchExpressions: []metaV1.LabelSelectorRequirement{},
 				},
-				Ingress: []networkingV1.NetworkPolicyIngressRule{
+				Ingress: []npV1.NetworkPolicyIngressRule{
 					{
 						From: []networkingV1.NetworkPolicyPeer{
 							{
@@ -183,9 +185,9 @@ func TestPolicyReflector(t *testing.T) {
 						},
 					},
 				},
-				Egress: []networkingV1.NetworkPolicyEgressRule{
+				Egress: []npV1.NetworkPolicyEgressRule{
 					{
-						Ports: []networkingV1.NetworkPolicyPort{
+						Ports: []npV1.NetworkPolicyPort{
 							{
 								Protocol: &pprotTCP,
 								Port: &intstr.IntOrString{
@@ -230,12 +232,12 @@ func TestPolicyReflector(t *testing.T) {
 				CreationTimestamp: metaV1.Date(2018, 01, 14, 18, 53, 37, 0,
 					time.FixedZone("PST", -800)),
 			},
-			Spec: networkingV1.NetworkPolicySpec{
+			Spec: npV1.NetworkPolicySpec{
 				PodSelector: metaV1.LabelSelector{
 					MatchLabels:      map[string]string{"app": "bookstore", "role": "db"},
 					MatchExpressions: []metaV1.LabelSelectorRequirement{},
 				},
-				Ingress: []networkingV1.NetworkPolicyIngressRule{
+				Ingress: []npV1.NetworkPolicyIngressRule{
 					{
 						From: []networkingV1.NetworkPolicyPeer{
 							{
@@ -413,7 +415,7 @@ func testUpdatePolicy(t *testing.T) {
 	k8sPolicyOld := &policyTestVars.policyTestData[0]
 	tmpBuf, err := json.Marshal(k8sPolicyOld)
 	gomega.Ω(err).Should(gomega.Succeed())
-	k8sPolicyNew := &networkingV1.NetworkPolicy{}
+	k8sPolicyNew := &npV1.NetworkPolicy{}
 	err = json.Unmarshal(tmpBuf, k8sPolicyNew)
 	gomega.Ω(err).Should(gomega.Succeed())
 
@@ -432,8 +434,8 @@ func testUpdatePolicy(t *testing.T) {
 	gomega.Expect(upds).To(gomega.Equal(policyTestVars.policyReflector.GetStats().Updates))
 
 	// Test update where everything should be good
-	k8sPolicyNew.Spec.Egress = append(k8sPolicyNew.Spec.Egress, networkingV1.NetworkPolicyEgressRule{
-		Ports: []networkingV1.NetworkPolicyPort{
+	k8sPolicyNew.Spec.Egress = append(k8sPolicyNew.Spec.Egress, npV1.NetworkPolicyEgressRule{
+		Ports: []npV1.NetworkPolicyPort{
 			{
 				Port: &intstr.IntOrString{
 					Type:   intstr.String,
@@ -597,7 +599,7 @@ func testResyncPolicyUpdateFail(t *testing.T) {
 	k8sPolicyOld := &policyTestVars.policyTestData[0]
 	tmpBuf, err := json.Marshal(k8sPolicyOld)
 	gomega.Ω(err).Should(gomega.Succeed())
-	k8sPolicyNew := &networkingV1.NetworkPolicy{}
+	k8sPolicyNew := &npV1.NetworkPolicy{}
 	err = json.Unmarshal(tmpBuf, k8sPolicyNew)
 	gomega.Ω(err).Should(gomega.Succeed())
 
@@ -612,8 +614,8 @@ func testResyncPolicyUpdateFail(t *testing.T) {
 	gomega.Expect(sSnap.Adds + 3).To(gomega.Equal(policyTestVars.policyReflector.GetStats().Adds))
 
 	// Test update where everything should be good
-	k8sPolicyNew.Spec.Egress = append(k8sPolicyNew.Spec.Egress, networkingV1.NetworkPolicyEgressRule{
-		Ports: []networkingV1.NetworkPolicyPort{
+	k8sPolicyNew.Spec.Egress = append(k8sPolicyNew.Spec.Egress, npV1.NetworkPolicyEgressRule{
+		Ports: []npV1.NetworkPolicyPort{
 			{
 				Port: &intstr.IntOrString{
 					Type:   intstr.String,
@@ -896,7 +898,7 @@ func testResyncPolicyTransientDsError(t *testing.T) {
 
 // checkPolicyToProtoTranslation checks whether the translation of K8s policy
 // into the Contiv-VPP protobuf format is correct.
-func checkPolicyToProtoTranslation(t *testing.T, protoNp *policy.Policy, k8sNp *networkingV1.NetworkPolicy) {
+func checkPolicyToProtoTranslation(t *testing.T, protoNp *policy.Policy, k8sNp *npV1.NetworkPolicy) {
 
 	gomega.Expect(protoNp.Name).To(gomega.Equal(k8sNp.GetName()))
 	gomega.Expect(protoNp.Namespace).To(gomega.Equal(k8sNp.GetNamespace()))
@@ -951,7 +953,7 @@ func checkLabelSelector(protoLbl *policy.Policy_LabelSelector, k8sLbl *metaV1.La
 
 // checkRulePorts checks whether the translation of K8s policy rules ports
 // into the Contiv-VPP protobuf format is correct.
-func checkRulePorts(protoPorts []*policy.Policy_Port, k8sPorts []networkingV1.NetworkPolicyPort) {
+func checkRulePorts(protoPorts []*policy.Policy_Port, k8sPorts []npV1.NetworkPolicyPort) {
 
 	gomega.Expect(len(protoPorts)).To(gomega.Equal(len(k8sPorts)))
 
@@ -1047,3 +1049,159 @@ loop:
 	}
 	return true
 }
+
+func TestPolicyEgressFQDNAnnotation(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	policyReflector := &PolicyReflector{
+		Reflector: Reflector{
+			Log: logging.ForPlugin("policy-reflector"),
+		},
+	}
+	k8sPolicy := &npV1.NetworkPolicy{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "allow-saas",
+			Namespace: "default",
+			Annotations: map[string]string{
+				egressFQDNAnnotation: "API.example.com.:443, ntp.example.com:123/udp,files.example.com," +
+					"*.example.com,db.example.com:5432-5433/TCP,bad.example.com:http,sctp.example.com/SCTP",
+			},
+		},
+		Spec: npV1.NetworkPolicySpec{
+			PodSelector: metaV1.LabelSelector{
+				MatchLabels: map[string]string{"role": "client"},
+			},
+			PolicyTypes: []networkingV1.PolicyType{networkingV1.PolicyTypeIngress, networkingV1.PolicyTypeEgress},
+		},
+	}
+
+	protoPolicy := policyReflector.policyToProto(k8sPolicy)
+	gomega.Expect(protoPolicy.PolicyType).To(gomega.Equal(policy.Policy_INGRESS_AND_EGRESS))
+	gomega.Expect(protoPolicy.EgressRule).To(gomega.HaveLen(5))
+
+	anyPortOf := func(protocol policy.Policy_Port_Protocol) *policy.Policy_Port {
+		return &policy.Policy_Port{
+			Protocol: protocol,
+			Port:     &policy.Policy_Port_PortNameOrNumber{Type: policy.Policy_Port_PortNameOrNumber_NUMBER},
+		}
+	}
+	expRules := []*policy.Policy_EgressRule{
+		{
+			To: []*policy.Policy_Peer{{Fqdn: []string{"api.example.com"}}},
+			Port: []*policy.Policy_Port{{
+				Port: &policy.Policy_Port_PortNameOrNumber{Type: policy.Policy_Port_PortNameOrNumber_NUMBER, Number: 443},
+			}},
+		},
+		{
+			To: []*policy.Policy_Peer{{Fqdn: []string{"ntp.example.com"}}},
+			Port: []*policy.Policy_Port{{
+				Protocol: policy.Policy_Port_UDP,
+				Port:     &policy.Policy_Port_PortNameOrNumber{Type: policy.Policy_Port_PortNameOrNumber_NUMBER, Number: 123},
+			}},
+		},
+		{
+			To: []*policy.Policy_Peer{{Fqdn: []string{"files.example.com"}}},
+		},
+		{
+			To: []*policy.Policy_Peer{{Fqdn: []string{"db.example.com"}}},
+			Port: []*policy.Policy_Port{{
+				Port:    &policy.Policy_Port_PortNameOrNumber{Type: policy.Policy_Port_PortNameOrNumber_NUMBER, Number: 5432},
+				EndPort: 5433,
+			}},
+		},
+		{
+			To:   []*policy.Policy_Peer{{Fqdn: []string{"sctp.example.com"}}},
+			Port: []*policy.Policy_Port{anyPortOf(policy.Policy_Port_SCTP)},
+		},
+	}
+	for i, rule := range protoPolicy.EgressRule {
+		gomega.Expect(proto.Equal(rule, expRules[i])).To(gomega.BeTrue(), "rule %d: %v", i, rule)
+	}
+}
+
+func TestPolicyEgressFQDNAnnotationWithoutEgress(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	policyReflector := &PolicyReflector{
+		Reflector: Reflector{
+			Log: logging.ForPlugin("policy-reflector"),
+		},
+	}
+	k8sPolicy := &npV1.NetworkPolicy{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      "allow-saas",
+			Namespace: "default",
+			Annotations: map[string]string{
+				egressFQDNAnnotation: "api.example.com:443",
+			},
+		},
+		Spec: npV1.NetworkPolicySpec{
+			PodSelector: metaV1.LabelSelector{
+				MatchLabels: map[string]string{"role": "client"},
+			},
+		},
+	}
+
+	// ingress-only policy (implicitly) - the annotation is ignored
+	protoPolicy := policyReflector.policyToProto(k8sPolicy)
+	gomega.Expect(protoPolicy.PolicyType).To(gomega.Equal(policy.Policy_DEFAULT))
+	gomega.Expect(protoPolicy.EgressRule).To(gomega.BeEmpty())
+
+	// ingress-only policy (explicitly) - the annotation is ignored
+	k8sPolicy.Spec.PolicyTypes = []networkingV1.PolicyType{networkingV1.PolicyTypeIngress}
+	protoPolicy = policyReflector.policyToProto(k8sPolicy)
+	gomega.Expect(protoPolicy.PolicyType).To(gomega.Equal(policy.Policy_INGRESS))
+	gomega.Expect(protoPolicy.EgressRule).To(gomega.BeEmpty())
+
+	// egress policy - the annotation is applied
+	k8sPolicy.Spec.PolicyTypes = []networkingV1.PolicyType{networkingV1.PolicyTypeEgress}
+	protoPolicy = policyReflector.policyToProto(k8sPolicy)
+	gomega.Expect(protoPolicy.PolicyType).To(gomega.Equal(policy.Policy_EGRESS))
+	gomega.Expect(protoPolicy.EgressRule).To(gomega.HaveLen(1))
+	gomega.Expect(protoPolicy.EgressRule[0].To[0].Fqdn).To(gomega.Equal([]string{"api.example.com"}))
+}
+
+func TestPolicyEndPort(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	policyReflector := &PolicyReflector{
+		Reflector: Reflector{
+			Log: logging.ForPlugin("policy-reflector"),
+		},
+	}
+
+	// endPort is decoded together with the rest of the policy
+	k8sPolicy := &npV1.NetworkPolicy{}
+	err := json.Unmarshal([]byte(`{
+		"kind": "NetworkPolicy",
+		"metadata": {"name": "allow-ranges", "namespace": "default"},
+		"spec": {
+			"podSelector": {"matchLabels": {"role": "server"}},
+			"ingress": [{
+				"ports": [
+					{"port": 32000, "endPort": 32767},
+					{"port": 5060},
+					{"protocol": "UDP", "port": 5060, "endPort": 5070},
+					{"protocol": "SCTP", "port": 3868, "endPort": 3869},
+					{"port": "http"}
+				]
+			}],
+			"policyTypes": ["Ingress"]
+		}
+	}`), k8sPolicy)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	protoPolicy := policyReflector.policyToProto(k8sPolicy)
+	gomega.Expect(protoPolicy.IngressRule).To(gomega.HaveLen(1))
+	ports := protoPolicy.IngressRule[0].Port
+	gomega.Expect(ports).To(gomega.HaveLen(5))
+	gomega.Expect(ports[0].Port.Number).To(gomega.BeEquivalentTo(32000))
+	gomega.Expect(ports[0].EndPort).To(gomega.BeEquivalentTo(32767))
+	gomega.Expect(ports[1].EndPort).To(gomega.BeEquivalentTo(0))
+	gomega.Expect(ports[2].Protocol).To(gomega.Equal(policy.Policy_Port_UDP))
+	gomega.Expect(ports[2].EndPort).To(gomega.BeEquivalentTo(5070))
+	gomega.Expect(ports[3].Protocol).To(gomega.Equal(policy.Policy_Port_SCTP))
+	gomega.Expect(ports[3].EndPort).To(gomega.BeEquivalentTo(3869))
+	gomega.Expect(ports[4].Port.Name).To(gomega.Equal("http"))
+	gomega.Expect(ports[4].EndPort).To(gomega.BeEquivalentTo(0))
+}
@@ -18,16 +18,21 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/contiv/vpp/plugins/netctl/cmdimpl"
 	"github.com/contiv/vpp/plugins/netctl/remote"
+	"github.com/contiv/vpp/plugins/policy/restapi"
 	"github.com/spf13/cobra"
 	"go.ligato.io/cn-infra/v2/db/keyval/etcd"
 )
 
 var (
-	etcdConfig string
-	httpConfig string
+	etcdConfig   string
+	httpConfig   string
+	outputFormat string
 )
 
 func getClient() (client *remote.HTTPClient) {
@@ -130,12 +135,121 @@ var cmdPodInfo = &cobra.Command{
 	},
 }
 
+var cmdPolicies = &cobra.Command{
+	Use: "policies [nodename]",
+	Short: "Display network policies applied to the pods of the given node, the rendered rule tables " +
+		"and the VPP ACLs attached to the pod interfaces. If node is omitted, policies of all nodes are shown.",
+	Example: "netctl policies k8s-master\nnetctl policies",
+	Args:    cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			cmdimpl.PrintAllPolicies(getClient(), getDb())
+		} else {
+			cmdimpl.PrintPoliciesPerNode(getClient(), getDb(), args[0])
+		}
+	},
+}
+
+var cmdServices = &cobra.Command{
+	Use: "services [nodename] [namespace/name]",
+	Short: "Display services as rendered on the given node - frontends, backends and the resulting " +
+		"NAT44 static mappings or SRv6 policies and localsids. If node is omitted, services of all nodes are shown.",
+	Example: "netctl services k8s-master\nnetctl services k8s-master default/web\nnetctl services default/web",
+	Args:    cobra.MaximumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		switch {
+		case len(args) == 0:
+			cmdimpl.PrintAllServices(getClient(), getDb(), "")
+		case len(args) == 1 && strings.Contains(args[0], "/"):
+			cmdimpl.PrintAllServices(getClient(), getDb(), args[0])
+		case len(args) == 1:
+			cmdimpl.PrintServicesPerNode(getClient(), getDb(), args[0], "")
+		default:
+			cmdimpl.PrintServicesPerNode(getClient(), getDb(), args[0], args[1])
+		}
+	},
+}
+
+var cmdDiagnose = &cobra.Command{
+	Use:   "diagnose",
+	Short: "Validate the network state of all nodes and display the problems found.",
+	Long: "Validate the ARP, L2 FIB, VXLAN and routing state of all nodes collected directly from the vswitch\n" +
+		"agents and display the problems found, ordered by severity, with suggested remediations.\n" +
+		"Exits with non-zero status if the cluster is not healthy.",
+	Example: "netctl diagnose\nnetctl diagnose -o json",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !cmdimpl.DiagnoseCmd(getClient(), getDb()) {
+			os.Exit(1)
+		}
+	},
+}
+
+var (
+	traceWait  time.Duration
+	traceCount int
+)
+
+var cmdTrace = &cobra.Command{
+	Use: "trace <src-pod> <dst-pod> [port]",
+	Short: "Trace packets sent from the source to the destination pod (given as namespace/name) through VPP " +
+		"on the nodes of both pods and display the hop-by-hop path of the packets.",
+	Example: "netctl trace default/web default/db 5432\nnetctl trace default/web default/db --wait 30s",
+	Args:    cobra.RangeArgs(2, 3),
+	Run: func(cmd *cobra.Command, args []string) {
+		var port uint16
+		if len(args) == 3 {
+			p, err := strconv.ParseUint(args[2], 10, 16)
+			if err != nil {
+				fmt.Printf("Invalid port %s\n", args[2])
+				return
+			}
+			port = uint16(p)
+		}
+		cmdimpl.TraceCmd(getClient(), getDb(), args[0], args[1], port, traceWait, traceCount)
+	},
+}
+
+var (
+	policyCheckNode      string
+	policyCheckCandidate string
+	policyCheckReq       restapi.PolicyCheckRequest
+)
+
+var cmdPolicy = &cobra.Command{
+	Use:   "policy",
+	Short: "Network policy tools.",
+}
+
+var cmdPolicyCheck = &cobra.Command{
+	Use: "check --from <pod|ip> --to <pod|ip> --port <port> [-f candidate.yaml]",
+	Short: "Evaluate whether the traffic would be allowed by the current network policies, " +
+		"optionally combined with candidate policies not yet applied. Pods are given as namespace/name.",
+	Example: "netctl policy check --from default/web --to default/db --port 5432\n" +
+		"netctl policy check -f db-policy.yaml --from default/web --to 10.1.1.2 --protocol UDP --port 53",
+	Args: cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		if policyCheckReq.Source == "" || policyCheckReq.Destination == "" {
+			fmt.Println("Both --from and --to have to be specified")
+			return
+		}
+		cmdimpl.PolicyCheckCmd(getClient(), getDb(), policyCheckNode, policyCheckCandidate, &policyCheckReq)
+	},
+}
+
 //Execute will execute the command netctlcd
 func Execute() {
-	var rootCmd = &cobra.Command{Use: "netctl"}
+	var rootCmd = &cobra.Command{
+		Use: "netctl",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return cmdimpl.SetOutputFormat(outputFormat)
+		},
+	}
 
 	rootCmd.PersistentFlags().StringVar(&etcdConfig, "etcd-config", "", "path to etcd.conf config file")
 	rootCmd.PersistentFlags().StringVar(&httpConfig, "http-client-config", "", "path to http.client.conf config file")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "",
+		"output format: json, yaml or wide (tables with additional columns)")
 
 	rootCmd.AddCommand(cmdNodes)
 	rootCmd.AddCommand(cmdVppDump)
@@ -143,6 +257,22 @@ func Execute() {
 
 	rootCmd.AddCommand(cmdNodeIPam)
 	rootCmd.AddCommand(cmdPodInfo)
+	rootCmd.AddCommand(cmdPolicies)
+	rootCmd.AddCommand(cmdServices)
+	rootCmd.AddCommand(cmdDiagnose)
+
+	cmdTrace.Flags().DurationVar(&traceWait, "wait", 10*time.Second, "how long to wait for the traffic to be traced")
+	cmdTrace.Flags().IntVar(&traceCount, "count", 50, "maximum number of packets traced on each input node")
+	rootCmd.AddCommand(cmdTrace)
+
+	cmdPolicyCheck.Flags().StringVar(&policyCheckReq.Source, "from", "", "source pod (namespace/name) or IP address")
+	cmdPolicyCheck.Flags().StringVar(&policyCheckReq.Destination, "to", "", "destination pod (namespace/name) or IP address")
+	cmdPolicyCheck.Flags().StringVar(&policyCheckReq.Protocol, "protocol", "TCP", "L4 protocol (TCP, UDP or SCTP)")
+	cmdPolicyCheck.Flags().Uint16Var(&policyCheckReq.Port, "port", 0, "destination port")
+	cmdPolicyCheck.Flags().StringVarP(&policyCheckCandidate, "filename", "f", "", "YAML file with candidate network policies")
+	cmdPolicyCheck.Flags().StringVar(&policyCheckNode, "node", "", "node evaluating the policies (any node if omitted)")
+	cmdPolicy.AddCommand(cmdPolicyCheck)
+	rootCmd.AddCommand(cmdPolicy)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -17,5 +17,9 @@ package cmdimpl
 const (
 	kvschedulerDumpCmd = "scheduler/dump"
 	getIpamDataCmd     = "contiv/v1/ipam"
+	policyCheckCmd     = "contiv/v1/policy/check"
+	policyPodsCmd      = "contiv/v1/policy/pods"
+	servicesCmd        = "contiv/v1/services"
+	vppCommandCmd      = "vpp/command"
 	timeLayout         = "Mon Jan _2 15:04:05 2006"
 )
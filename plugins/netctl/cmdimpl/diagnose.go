@@ -0,0 +1,411 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdimpl
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/golang/protobuf/jsonpb"
+
+	"go.ligato.io/cn-infra/v2/db/keyval/etcd"
+	"go.ligato.io/cn-infra/v2/health/statuscheck/model/status"
+	"go.ligato.io/cn-infra/v2/logging"
+	"go.ligato.io/cn-infra/v2/logging/logrus"
+	"go.ligato.io/cn-infra/v2/servicelabel"
+
+	linuxifdescr "go.ligato.io/vpp-agent/v3/plugins/linux/ifplugin/descriptor"
+	vppifdescr "go.ligato.io/vpp-agent/v3/plugins/vpp/ifplugin/descriptor"
+	vppl2descr "go.ligato.io/vpp-agent/v3/plugins/vpp/l2plugin/descriptor"
+	vppl3descr "go.ligato.io/vpp-agent/v3/plugins/vpp/l3plugin/descriptor"
+
+	"github.com/contiv/vpp/plugins/crd/api"
+	"github.com/contiv/vpp/plugins/crd/cache"
+	"github.com/contiv/vpp/plugins/crd/cache/telemetrymodel"
+	"github.com/contiv/vpp/plugins/crd/datastore"
+	"github.com/contiv/vpp/plugins/crd/validator"
+	"github.com/contiv/vpp/plugins/ipnet/restapi"
+	"github.com/contiv/vpp/plugins/ksr"
+	"github.com/contiv/vpp/plugins/ksr/model/node"
+	"github.com/contiv/vpp/plugins/ksr/model/pod"
+	"github.com/contiv/vpp/plugins/netctl/remote"
+	"github.com/contiv/vpp/plugins/nodesync/vppnode"
+)
+
+// Severity of a problem found by the diagnosis.
+type Severity int
+
+const (
+	// SeverityCritical marks problems breaking the connectivity of the whole node.
+	SeverityCritical Severity = iota
+	// SeverityMajor marks problems breaking the connectivity of some pods or nodes.
+	SeverityMajor
+	// SeverityMinor marks problems that do not necessarily break the connectivity
+	// but prevent the full validation of the node.
+	SeverityMinor
+)
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityCritical:
+		return "critical"
+	case SeverityMajor:
+		return "major"
+	}
+	return "minor"
+}
+
+// MarshalJSON encodes severity as its name.
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// Problem is a single problem found by the diagnosis.
+type Problem struct {
+	Severity    Severity `json:"severity"`
+	Node        string   `json:"node"`
+	Check       string   `json:"check"`
+	Message     string   `json:"message"`
+	Remediation string   `json:"remediation"`
+}
+
+// Diagnosis is the result of the cluster diagnosis.
+type Diagnosis struct {
+	Healthy  bool       `json:"healthy"`
+	Problems []*Problem `json:"problems"`
+}
+
+// validationSummaryRegex matches the error count summary added by the validators.
+var validationSummaryRegex = regexp.MustCompile(`^\d+ errors? found$`)
+
+// remediationRule assigns severity and remediation to the validation messages
+// of the given check containing the given text (empty matches any).
+type remediationRule struct {
+	check       string
+	text        string
+	severity    Severity
+	remediation string
+}
+
+// remediationRules are evaluated in the order of definition, the first matching
+// rule applies.
+var remediationRules = []remediationRule{
+	{check: "HTTP", severity: SeverityCritical,
+		remediation: "vswitch agent of the node is not reachable - check that the contiv-vswitch pod is running " +
+			"and its REST API is accessible from this host"},
+	{text: "validation skipped", severity: SeverityMinor,
+		remediation: "state of the node could not be read completely - re-run the diagnosis and check the " +
+			"contiv-vswitch logs if the problem persists"},
+	{text: "not validated", severity: SeverityMinor,
+		remediation: "state of the node could not be read completely - re-run the diagnosis and check the " +
+			"contiv-vswitch logs if the problem persists"},
+	{text: "internal error", severity: SeverityMinor,
+		remediation: "inconsistent data received from the node - re-run the diagnosis"},
+	{check: "NODE-MAP", severity: SeverityCritical,
+		remediation: "node is not consistently registered in K8s and Contiv - check the node status in K8s " +
+			"(kubectl get nodes) and the contiv-ksr logs"},
+	{check: "K8S-NODE", severity: SeverityCritical,
+		remediation: "node is not consistently registered in K8s and Contiv - check the node status in K8s " +
+			"(kubectl get nodes), the contiv-ksr logs and remove stale nodes from the cluster"},
+	{check: "IP-ARP", text: "missing ARP entry", severity: SeverityMajor,
+		remediation: "remote node is not resolved by ARP - check the physical interconnect between the nodes " +
+			"(netctl vppcli NODE sh ip neighbors)"},
+	{check: "IP-ARP", severity: SeverityMajor,
+		remediation: "invalid ARP entries - check for duplicate IP/MAC addresses in the node interconnect network"},
+	{check: "VXLAN-BD", severity: SeverityMajor,
+		remediation: "VXLAN overlay is inconsistent between the nodes - restart contiv-vswitch on the node " +
+			"to trigger resync and check that all nodes use the same configuration"},
+	{check: "L2-FIB", severity: SeverityMajor,
+		remediation: "L2 FIB of the VXLAN overlay is inconsistent - restart contiv-vswitch on the node " +
+			"to trigger resync"},
+	{check: "L3-FIB", severity: SeverityMajor,
+		remediation: "VPP routes are missing or invalid - inspect them (netctl vppdump NODE " +
+			vppl3descr.RouteDescriptorName + ") and restart contiv-vswitch on the node to trigger resync"},
+	{check: "K8S-POD", text: "tap interface", severity: SeverityMajor,
+		remediation: "pod interface is missing or dangling - check the contiv-cni logs on the node and " +
+			"re-create the pod"},
+	{check: "K8S-POD", severity: SeverityMajor,
+		remediation: "pod data is inconsistent between K8s and Contiv - re-create the pod and check " +
+			"the contiv-ksr logs"},
+	{severity: SeverityMajor,
+		remediation: "inspect the state of the node with netctl vppdump/vppcli and restart contiv-vswitch " +
+			"on the node if it does not converge"},
+}
+
+// DiagnoseCmd collects the state of all nodes directly from the vswitch agents,
+// validates it using the telemetry validators and prints the problems found,
+// ordered by severity, with suggested remediations. Returns false if the
+// cluster is not healthy, i.e. a critical or major problem was found.
+func DiagnoseCmd(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd) bool {
+	log := logrus.NewLogger("netctl-diagnose")
+	log.SetLevel(logging.ErrorLevel)
+
+	vppCache := datastore.NewVppDataStore()
+	k8sCache := datastore.NewK8sDataStore()
+	report := datastore.NewSimpleReport(log)
+
+	loadDiagnoseK8sState(db, vppCache, k8sCache, report)
+	for _, vppNode := range vppCache.RetrieveAllNodes() {
+		collectDiagnoseNodeState(client, vppNode, vppCache, k8sCache, report)
+	}
+	for _, vppNode := range vppCache.RetrieveAllNodes() {
+		cache.PopulateNodeMaps(vppNode, vppCache, k8sCache, report)
+	}
+
+	v := &validator.Validator{
+		Deps: validator.Deps{
+			Log:   log,
+			L2Log: log,
+			L3Log: log,
+		},
+		VppCache: vppCache,
+		K8sCache: k8sCache,
+		Report:   report,
+	}
+	v.Validate()
+
+	diagnosis := newDiagnosis(report.RetrieveReport())
+	if !printStructured(diagnosis) {
+		printDiagnosis(diagnosis)
+	}
+	return diagnosis.Healthy
+}
+
+// loadDiagnoseK8sState loads Contiv and K8s nodes and pods from the database.
+func loadDiagnoseK8sState(db *etcd.BytesConnectionEtcd, vppCache api.VppCache, k8sCache api.K8sCache,
+	report api.Report) {
+
+	ksrPrefix := servicelabel.GetDifferentAgentPrefix(ksr.MicroserviceLabel)
+	report.SetPrefix("ETCD")
+
+	itr, err := db.ListValues(ksrPrefix + vppnode.KeyPrefix)
+	if err != nil {
+		report.AppendToNodeReport(api.GlobalMsg, fmt.Sprintf("failed to read Contiv nodes: %v", err))
+		return
+	}
+	for {
+		kv, stop := itr.GetNext()
+		if stop {
+			break
+		}
+		vn := &vppnode.VppNode{}
+		if err = jsonpb.UnmarshalString(string(kv.GetValue()), vn); err != nil {
+			report.AppendToNodeReport(api.GlobalMsg, fmt.Sprintf("failed to decode node %s: %v", kv.GetKey(), err))
+			continue
+		}
+		vppIPs := append(vn.IpAddresses, vn.IpAddress)
+		if err := vppCache.CreateNode(vn.Id, vn.Name, vppIPs[0]); err != nil {
+			report.AppendToNodeReport(vn.Name, fmt.Sprintf("failed to add vpp node: %v", err))
+		}
+	}
+
+	itr, err = db.ListValues(ksrPrefix + node.KeyPrefix())
+	if err != nil {
+		report.AppendToNodeReport(api.GlobalMsg, fmt.Sprintf("failed to read K8s nodes: %v", err))
+		return
+	}
+	for {
+		kv, stop := itr.GetNext()
+		if stop {
+			break
+		}
+		k8sNode := &node.Node{}
+		if err = jsonpb.UnmarshalString(string(kv.GetValue()), k8sNode); err != nil {
+			report.AppendToNodeReport(api.GlobalMsg, fmt.Sprintf("failed to decode node %s: %v", kv.GetKey(), err))
+			continue
+		}
+		k8sCache.CreateK8sNode(k8sNode.Name, k8sNode.Pod_CIDR, k8sNode.Provider_ID,
+			k8sNode.Addresses, k8sNode.NodeInfo)
+	}
+
+	itr, err = db.ListValues(ksrPrefix + pod.KeyPrefix())
+	if err != nil {
+		report.AppendToNodeReport(api.GlobalMsg, fmt.Sprintf("failed to read pods: %v", err))
+		return
+	}
+	for {
+		kv, stop := itr.GetNext()
+		if stop {
+			break
+		}
+		k8sPod := &pod.Pod{}
+		if err = jsonpb.UnmarshalString(string(kv.GetValue()), k8sPod); err != nil {
+			report.AppendToNodeReport(api.GlobalMsg, fmt.Sprintf("failed to decode pod %s: %v", kv.GetKey(), err))
+			continue
+		}
+		k8sCache.CreatePod(k8sPod.Name, k8sPod.Namespace, k8sPod.Label, k8sPod.IpAddress,
+			k8sPod.HostIpAddress, k8sPod.Container)
+	}
+}
+
+// collectDiagnoseNodeState reads the state of the given node from its vswitch agent.
+func collectDiagnoseNodeState(client *remote.HTTPClient, vppNode *telemetrymodel.Node,
+	vppCache api.VppCache, k8sCache api.K8sCache, report api.Report) {
+
+	if k8sNode, err := k8sCache.RetrieveK8sNode(vppNode.Name); err == nil {
+		for _, adr := range k8sNode.Addresses {
+			if adr.Type == node.NodeAddress_NodeInternalIP {
+				vppNode.ManIPAddr = adr.Address
+				break
+			}
+		}
+	}
+	report.SetPrefix("HTTP")
+	if vppNode.ManIPAddr == "" {
+		report.AppendToNodeReport(vppNode.Name, "management IP address of the node is not known")
+		return
+	}
+
+	get := func(cmd string, data interface{}) bool {
+		b, err := getNodeInfo(client, vppNode.ManIPAddr, cmd)
+		if err == nil {
+			err = json.Unmarshal(b, data)
+		}
+		if err != nil {
+			report.AppendToNodeReport(vppNode.Name, fmt.Sprintf("failed to read %s: %v", cmd, err))
+			return false
+		}
+		return true
+	}
+
+	var errs []error
+	liveness := &status.AgentStatus{}
+	if get("liveness", liveness) {
+		errs = append(errs, vppCache.SetNodeLiveness(vppNode.Name, liveness))
+	}
+	interfaces := make(telemetrymodel.NodeInterfaces, 0)
+	if get(vppDumpCommand(vppifdescr.InterfaceDescriptorName), &interfaces) {
+		errs = append(errs, vppCache.SetNodeInterfaces(vppNode.Name, interfaces))
+	}
+	bds := make(telemetrymodel.NodeBridgeDomains, 0)
+	if get(vppDumpCommand(vppl2descr.BridgeDomainDescriptorName), &bds) {
+		errs = append(errs, vppCache.SetNodeBridgeDomain(vppNode.Name, bds))
+	}
+	l2fibs := make(telemetrymodel.NodeL2FibTable, 0)
+	if get(vppDumpCommand(vppl2descr.FIBDescriptorName), &l2fibs) {
+		errs = append(errs, vppCache.SetNodeL2Fibs(vppNode.Name, l2fibs))
+	}
+	arps := make(telemetrymodel.NodeIPArpTable, 0)
+	if get(vppDumpCommand(vppl3descr.ArpDescriptorName), &arps) {
+		errs = append(errs, vppCache.SetNodeIPARPs(vppNode.Name, arps))
+	}
+	routes := make(telemetrymodel.NodeStaticRoutes, 0)
+	if get(vppDumpCommand(vppl3descr.RouteDescriptorName), &routes) {
+		errs = append(errs, vppCache.SetNodeStaticRoutes(vppNode.Name, routes))
+	}
+	ipam := restapi.NodeIPAMInfo{}
+	if get(getIpamDataCmd, &ipam) {
+		errs = append(errs, vppCache.SetNodeIPam(vppNode.Name, ipam))
+	}
+	linuxIfs := make(telemetrymodel.LinuxInterfaces, 0)
+	if get(vppDumpCommand(linuxifdescr.InterfaceDescriptorName), &linuxIfs) {
+		errs = append(errs, vppCache.SetLinuxInterfaces(vppNode.Name, linuxIfs))
+	}
+	for _, err := range errs {
+		if err != nil {
+			report.AppendToNodeReport(vppNode.Name, err.Error())
+		}
+	}
+}
+
+// newDiagnosis builds the diagnosis from the validation report, assigning
+// severity and remediation to each reported problem.
+func newDiagnosis(reports telemetrymodel.Reports) *Diagnosis {
+	diagnosis := &Diagnosis{Healthy: true, Problems: make([]*Problem, 0)}
+	for nodeName, messages := range reports {
+		for _, msg := range messages {
+			check, text := "", msg
+			if idx := strings.Index(msg, ": "); idx >= 0 {
+				check, text = msg[:idx], msg[idx+2:]
+			}
+			if isValidationSummary(text) {
+				continue
+			}
+			problem := &Problem{Node: nodeName, Check: check, Message: text}
+			for _, rule := range remediationRules {
+				if (rule.check == "" || rule.check == check) && strings.Contains(text, rule.text) {
+					problem.Severity = rule.severity
+					problem.Remediation = rule.remediation
+					break
+				}
+			}
+			if problem.Severity != SeverityMinor {
+				diagnosis.Healthy = false
+			}
+			diagnosis.Problems = append(diagnosis.Problems, problem)
+		}
+	}
+	sort.SliceStable(diagnosis.Problems, func(i, j int) bool {
+		pi, pj := diagnosis.Problems[i], diagnosis.Problems[j]
+		if pi.Severity != pj.Severity {
+			return pi.Severity < pj.Severity
+		}
+		if pi.Node != pj.Node {
+			return pi.Node < pj.Node
+		}
+		if pi.Check != pj.Check {
+			return pi.Check < pj.Check
+		}
+		return pi.Message < pj.Message
+	})
+	return diagnosis
+}
+
+// isValidationSummary returns true for the informational summaries added
+// by the validators into the report.
+func isValidationSummary(text string) bool {
+	return text == "validation OK" || validationSummaryRegex.MatchString(text) ||
+		strings.HasPrefix(text, "Rte report")
+}
+
+// printDiagnosis prints the problems in a table followed by the list of
+// suggested remediations.
+func printDiagnosis(diagnosis *Diagnosis) {
+	if len(diagnosis.Problems) == 0 {
+		fmt.Println("No problems found, the cluster is healthy.")
+		return
+	}
+
+	var remediations []string
+	remediationIdx := make(map[string]int)
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "SEVERITY\tNODE\tCHECK\tPROBLEM\tREMEDIATION\n")
+	for _, problem := range diagnosis.Problems {
+		idx, known := remediationIdx[problem.Remediation]
+		if !known {
+			remediations = append(remediations, problem.Remediation)
+			idx = len(remediations)
+			remediationIdx[problem.Remediation] = idx
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t[%d]\n", problem.Severity, problem.Node, problem.Check, problem.Message, idx)
+	}
+	w.Flush()
+
+	fmt.Println("\nSuggested remediations:")
+	for i, remediation := range remediations {
+		fmt.Printf("[%d] %s\n", i+1, remediation)
+	}
+	if diagnosis.Healthy {
+		fmt.Println("\nThe cluster is healthy, only minor problems were found.")
+	} else {
+		fmt.Println("\nThe cluster is NOT healthy.")
+	}
+}
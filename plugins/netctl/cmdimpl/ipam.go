@@ -32,6 +32,20 @@ import (
 	vppifdescr "go.ligato.io/vpp-agent/v3/plugins/vpp/ifplugin/descriptor"
 )
 
+// NodeIPAM contains IPAM information of a single node.
+type NodeIPAM struct {
+	ID                   uint32 `json:"id"`
+	Name                 string `json:"name"`
+	VppIP                string `json:"vppIP"`
+	BVIIP                string `json:"bviIP"`
+	PodCIDR              string `json:"podCIDR"`
+	VppHostCIDR          string `json:"vppHostCIDR"`
+	PodClusterCIDR       string `json:"podClusterCIDR"`
+	ServiceCIDR          string `json:"serviceCIDR,omitempty"`
+	NodeInterconnectCIDR string `json:"nodeInterconnectCIDR,omitempty"`
+	VxlanCIDR            string `json:"vxlanCIDR,omitempty"`
+}
+
 // PrintAllIpams prints IPAM information for all nodes
 func PrintAllIpams(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd) {
 	nodes := make([]string, 0)
@@ -40,34 +54,38 @@ func PrintAllIpams(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd) {
 	}
 	sort.Strings(nodes)
 
-	w := getTabWriterAndPrintHeader()
+	ipams := make([]*NodeIPAM, 0, len(nodes))
 	for _, n := range nodes {
-		nodeIpamCmd(client, db, w, n)
+		if ipam := getNodeIPAM(client, db, n); ipam != nil {
+			ipams = append(ipams, ipam)
+		}
 	}
-	w.Flush()
+	printIPAMs(ipams)
 }
 
 // NodeIPamCmd prints out the ipam information of a specific node
 func NodeIPamCmd(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd, nodeName string) {
-	w := getTabWriterAndPrintHeader()
-	nodeIpamCmd(client, db, w, nodeName)
-	w.Flush()
+	ipams := make([]*NodeIPAM, 0, 1)
+	if ipam := getNodeIPAM(client, db, nodeName); ipam != nil {
+		ipams = append(ipams, ipam)
+	}
+	printIPAMs(ipams)
 }
 
-func nodeIpamCmd(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd, w *tabwriter.Writer, nodeName string) {
+func getNodeIPAM(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd, nodeName string) *NodeIPAM {
 	ip := resolveNodeOrIP(db, nodeName)
 
 	b, err := getNodeInfo(client, ip, getIpamDataCmd)
 	if err != nil {
-		fmt.Println(err)
-		return
+		printError("%v\n", err)
+		return nil
 	}
 
 	ipam := restapi.NodeIPAMInfo{}
 	err = json.Unmarshal(b, &ipam)
 	if err != nil {
-		fmt.Println(err)
-		return
+		printError("%v\n", err)
+		return nil
 	}
 
 	bviIP := "Not Available"
@@ -83,18 +101,45 @@ func nodeIpamCmd(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd, w *tab
 		}
 	}
 
-	fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
-		ipam.NodeID,
-		ipam.NodeName,
-		ipam.NodeIP,
-		bviIP,
-		ipam.PodSubnetThisNode,
-		ipam.VppHostNetwork,
-		ipam.Config.PodSubnetCIDR)
+	nodeIPAM := &NodeIPAM{
+		ID:          ipam.NodeID,
+		Name:        ipam.NodeName,
+		VppIP:       ipam.NodeIP,
+		BVIIP:       bviIP,
+		PodCIDR:     ipam.PodSubnetThisNode,
+		VppHostCIDR: ipam.VppHostNetwork,
+	}
+	if ipam.Config != nil {
+		nodeIPAM.PodClusterCIDR = ipam.Config.PodSubnetCIDR
+		nodeIPAM.ServiceCIDR = ipam.Config.ServiceCIDR
+		nodeIPAM.NodeInterconnectCIDR = ipam.Config.NodeInterconnectCIDR
+		nodeIPAM.VxlanCIDR = ipam.Config.VxlanCIDR
+	}
+	return nodeIPAM
 }
 
-func getTabWriterAndPrintHeader() *tabwriter.Writer {
+func printIPAMs(ipams []*NodeIPAM) {
+	if printStructured(ipams) {
+		return
+	}
+
 	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
-	fmt.Fprintf(w, "ID\tNODE-NAME\tVPP-IP\tBVI-IP\tPOD-CIDR\tVPP-2-HOST-CIDR\tPOD-CLUSTER-CIDR\n")
-	return w
+	if Output == OutputWide {
+		fmt.Fprintf(w, "ID\tNODE-NAME\tVPP-IP\tBVI-IP\tPOD-CIDR\tVPP-2-HOST-CIDR\tPOD-CLUSTER-CIDR\t"+
+			"SERVICE-CIDR\tNODE-INTERCONNECT-CIDR\tVXLAN-CIDR\n")
+	} else {
+		fmt.Fprintf(w, "ID\tNODE-NAME\tVPP-IP\tBVI-IP\tPOD-CIDR\tVPP-2-HOST-CIDR\tPOD-CLUSTER-CIDR\n")
+	}
+	for _, ipam := range ipams {
+		if Output == OutputWide {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				ipam.ID, ipam.Name, ipam.VppIP, ipam.BVIIP, ipam.PodCIDR, ipam.VppHostCIDR,
+				ipam.PodClusterCIDR, ipam.ServiceCIDR, ipam.NodeInterconnectCIDR, ipam.VxlanCIDR)
+		} else {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				ipam.ID, ipam.Name, ipam.VppIP, ipam.BVIIP, ipam.PodCIDR, ipam.VppHostCIDR,
+				ipam.PodClusterCIDR)
+		}
+	}
+	w.Flush()
 }
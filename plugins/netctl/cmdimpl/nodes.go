@@ -33,51 +33,98 @@ import (
 	"github.com/contiv/vpp/plugins/netctl/remote"
 )
 
+// NodeInfo contains the status of a single node of the Contiv cluster.
+type NodeInfo struct {
+	ID           uint32 `json:"id"`
+	Name         string `json:"name"`
+	VppIP        string `json:"vppIP"`
+	HostIP       string `json:"hostIP"`
+	StartTime    string `json:"startTime,omitempty"`
+	LastChange   string `json:"lastChange,omitempty"`
+	State        string `json:"state,omitempty"`
+	BuildVersion string `json:"buildVersion,omitempty"`
+	BuildDate    string `json:"buildDate,omitempty"`
+	CommitHash   string `json:"commitHash,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
 // PrintNodes will print out all of the cmdimpl in a network in a table format.
 func PrintNodes(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd) {
-	nodes := make([]string, 0)
-	for k := range getClusterNodeInfo(db) {
-		nodes = append(nodes, k)
+	nodes := getNodes(client, db)
+	if printStructured(nodes) {
+		return
 	}
-	sort.Strings(nodes)
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
-	fmt.Fprintf(w, "ID\tNODE-NAME\tVPP-IP\tHOST-IP\tSTART-TIME\tSTATE\tBUILD-VERSION\tBUILD-DATE\n")
+	if Output == OutputWide {
+		fmt.Fprintf(w, "ID\tNODE-NAME\tVPP-IP\tHOST-IP\tSTART-TIME\tLAST-CHANGE\tSTATE\tBUILD-VERSION\tBUILD-DATE\tCOMMIT\n")
+	} else {
+		fmt.Fprintf(w, "ID\tNODE-NAME\tVPP-IP\tHOST-IP\tSTART-TIME\tSTATE\tBUILD-VERSION\tBUILD-DATE\n")
+	}
+
+	for _, node := range nodes {
+		if node.Error != "" {
+			continue
+		}
+		if Output == OutputWide {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				node.ID, node.Name, node.VppIP, node.HostIP, node.StartTime, node.LastChange,
+				node.State, node.BuildVersion, node.BuildDate, node.CommitHash)
+		} else {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				node.ID, node.Name, node.VppIP, node.HostIP, node.StartTime,
+				node.State, node.BuildVersion, node.BuildDate)
+		}
+	}
+
+	w.Flush()
+}
+
+// getNodes returns the status of all nodes of the Contiv cluster, sorted by name.
+func getNodes(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd) []*NodeInfo {
+	names := make([]string, 0)
+	for k := range getClusterNodeInfo(db) {
+		names = append(names, k)
+	}
+	sort.Strings(names)
 
-	for _, n := range nodes {
+	nodes := make([]*NodeInfo, 0, len(names))
+	for _, n := range names {
 		nodeInfo := nodeInfo[n]
+		node := &NodeInfo{
+			ID:     nodeInfo.id,
+			Name:   nodeInfo.name,
+			VppIP:  strings.Split(nodeInfo.vppIPAddress, "/")[0],
+			HostIP: nodeInfo.mgmtIPAddress,
+		}
+		nodes = append(nodes, node)
+
 		// Get liveness data which contains image version / build date
 		bytes, err := getNodeInfo(client, nodeInfo.mgmtIPAddress, "liveness")
 		if err != nil {
-			fmt.Printf("Could not get liveness data for node '%s'\n", nodeInfo.name)
+			printError("Could not get liveness data for node '%s'\n", nodeInfo.name)
+			node.Error = err.Error()
 			continue
 		}
 
 		// Reformat the image build date to the common format
-		buildDate := "Not Available"
-		buildVersion := "Not Available"
+		node.BuildDate = "Not Available"
+		node.BuildVersion = "Not Available"
 		var liveness status.AgentStatus
 		if err = json.Unmarshal(bytes, &liveness); err == nil {
-			buildVersion = liveness.BuildVersion
-			buildDate = liveness.BuildDate
-			bd, err1 := time.Parse("2006-01-02T15:04Z07:00", buildDate)
+			node.BuildVersion = liveness.BuildVersion
+			node.BuildDate = liveness.BuildDate
+			node.CommitHash = liveness.CommitHash
+			bd, err1 := time.Parse("2006-01-02T15:04Z07:00", node.BuildDate)
 			if err1 == nil {
-				buildDate = bd.Format(timeLayout)
+				node.BuildDate = bd.Format(timeLayout)
 			}
 		}
-
-		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-			nodeInfo.id,
-			nodeInfo.name,
-			strings.Split(nodeInfo.vppIPAddress, "/")[0],
-			nodeInfo.mgmtIPAddress,
-			time.Unix(int64(liveness.StartTime), 0).Format(timeLayout),
-			status.OperationalState_name[int32(liveness.State)],
-			buildVersion,
-			buildDate)
+		node.StartTime = time.Unix(int64(liveness.StartTime), 0).Format(timeLayout)
+		node.LastChange = time.Unix(int64(liveness.LastChange), 0).Format(timeLayout)
+		node.State = status.OperationalState_name[int32(liveness.State)]
 	}
-
-	w.Flush()
+	return nodes
 }
 
 // getNodeInfo will make an http request for the given command and return an indented slice of bytes.
@@ -85,13 +132,13 @@ func getNodeInfo(client *remote.HTTPClient, base string, cmd string) ([]byte, er
 	res, err := client.Get(base, cmd)
 	if err != nil {
 		err := fmt.Errorf("getNodeInfo: url: %s Get Error: %s", cmd, err.Error())
-		fmt.Printf("http get error: %s ", err.Error())
+		printError("http get error: %s\n", err.Error())
 		return nil, err
 	}
 	defer res.Body.Close()
 	if res.StatusCode < 200 || res.StatusCode > 299 {
 		err := fmt.Errorf("getNodeInfo: url: %s HTTP res.Status: %s", cmd, res.Status)
-		fmt.Printf("http get error: %s ", err.Error())
+		printError("http get error: %s\n", err.Error())
 		return nil, err
 	}
 
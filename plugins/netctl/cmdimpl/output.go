@@ -0,0 +1,85 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdimpl
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// OutputFormat selects how the results of netctl commands are rendered.
+type OutputFormat string
+
+const (
+	// OutputText renders results as human-readable tables (default).
+	OutputText OutputFormat = ""
+	// OutputWide renders results as human-readable tables with additional columns.
+	OutputWide OutputFormat = "wide"
+	// OutputJSON renders results as JSON documents.
+	OutputJSON OutputFormat = "json"
+	// OutputYAML renders results as YAML documents.
+	OutputYAML OutputFormat = "yaml"
+)
+
+// Output is the output format selected for the executed command.
+var Output = OutputText
+
+// SetOutputFormat validates and selects the output format for the executed command.
+func SetOutputFormat(format string) error {
+	switch OutputFormat(format) {
+	case OutputText, OutputWide, OutputJSON, OutputYAML:
+		Output = OutputFormat(format)
+		return nil
+	}
+	return fmt.Errorf("unsupported output format '%s', expected one of: json, yaml, wide", format)
+}
+
+// structuredOutput returns true if the results are rendered as JSON or YAML documents.
+func structuredOutput() bool {
+	return Output == OutputJSON || Output == OutputYAML
+}
+
+// printStructured prints the result as JSON or YAML document if one of these
+// formats was selected. Returns false if the result should be rendered as text.
+func printStructured(result interface{}) bool {
+	var (
+		b   []byte
+		err error
+	)
+	switch Output {
+	case OutputJSON:
+		b, err = json.MarshalIndent(result, "", "  ")
+	case OutputYAML:
+		b, err = yaml.Marshal(result)
+	default:
+		return false
+	}
+	if err != nil {
+		printError("Failed to render the output: %v\n", err)
+		return true
+	}
+	fmt.Println(strings.TrimSuffix(string(b), "\n"))
+	return true
+}
+
+// printError prints error message to the standard error output, keeping
+// the standard output machine-readable.
+func printError(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format, args...)
+}
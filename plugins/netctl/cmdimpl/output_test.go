@@ -0,0 +1,106 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdimpl
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// captureStdout returns everything printed to the standard output by <print>.
+func captureStdout(print func()) string {
+	r, w, err := os.Pipe()
+	Expect(err).ToNot(HaveOccurred())
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	print()
+	Expect(w.Close()).To(Succeed())
+	out, err := ioutil.ReadAll(r)
+	Expect(err).ToNot(HaveOccurred())
+	return string(out)
+}
+
+func TestSetOutputFormat(t *testing.T) {
+	RegisterTestingT(t)
+	defer func() { Output = OutputText }()
+
+	tests := []struct {
+		format   string
+		expected OutputFormat
+		valid    bool
+	}{
+		{format: "", expected: OutputText, valid: true},
+		{format: "wide", expected: OutputWide, valid: true},
+		{format: "json", expected: OutputJSON, valid: true},
+		{format: "yaml", expected: OutputYAML, valid: true},
+		{format: "xml", expected: OutputYAML /* unchanged */, valid: false},
+		{format: "JSON", expected: OutputYAML /* unchanged */, valid: false},
+	}
+	for _, test := range tests {
+		err := SetOutputFormat(test.format)
+		if test.valid {
+			Expect(err).ToNot(HaveOccurred(), test.format)
+		} else {
+			Expect(err).To(MatchError(ContainSubstring("unsupported output format '%s'", test.format)))
+		}
+		Expect(Output).To(Equal(test.expected), test.format)
+	}
+}
+
+func TestPrintStructured(t *testing.T) {
+	RegisterTestingT(t)
+	defer func() { Output = OutputText }()
+
+	result := struct {
+		Node  string   `json:"node"`
+		Peers []string `json:"peers"`
+	}{
+		Node:  "k8s-master",
+		Peers: []string{"k8s-worker1"},
+	}
+
+	tests := []struct {
+		output     OutputFormat
+		structured bool
+		expected   string
+	}{
+		{output: OutputText, structured: false, expected: ""},
+		{output: OutputWide, structured: false, expected: ""},
+		{output: OutputJSON, structured: true,
+			expected: "{\n  \"node\": \"k8s-master\",\n  \"peers\": [\n    \"k8s-worker1\"\n  ]\n}\n"},
+		{output: OutputYAML, structured: true,
+			expected: "node: k8s-master\npeers:\n- k8s-worker1\n"},
+	}
+	for _, test := range tests {
+		Output = test.output
+		Expect(structuredOutput()).To(Equal(test.structured), string(test.output))
+		var printed bool
+		out := captureStdout(func() { printed = printStructured(result) })
+		Expect(printed).To(Equal(test.structured), string(test.output))
+		Expect(out).To(Equal(test.expected), string(test.output))
+	}
+
+	// a result which cannot be encoded is reported to stderr only
+	Output = OutputJSON
+	var printed bool
+	out := captureStdout(func() { printed = printStructured(make(chan int)) })
+	Expect(printed).To(BeTrue())
+	Expect(out).To(BeEmpty())
+}
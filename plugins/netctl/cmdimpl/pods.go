@@ -51,23 +51,65 @@ type podGetter struct {
 	pods    []*pod.Pod
 }
 
+// PodInfo contains network information of a single pod.
+type PodInfo struct {
+	Name        string   `json:"name"`
+	Namespace   string   `json:"namespace"`
+	Node        string   `json:"node"`
+	HostIP      string   `json:"hostIP"`
+	IP          string   `json:"ip"`
+	IPs         []string `json:"ips,omitempty"`
+	HostNetwork bool     `json:"hostNetwork,omitempty"`
+	IfIndex     uint32   `json:"ifIndex,omitempty"`
+	IfName      string   `json:"ifName,omitempty"`
+	IfTag       string   `json:"ifTag,omitempty"`
+}
+
 // PrintAllPods will print out all of the non local pods in a network in
 // a table format.
 func PrintAllPods(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd) {
-	w := getWriter("HOST-NAME")
 	pg := newPodGetter(client, db)
-	pg.printAllPods(w)
+	nodes := pg.getNodes()
+	pods := make([]*PodInfo, 0)
+	for _, n := range nodes {
+		pods = append(pods, pg.getPodsPerNode(n.hostIP, n.name)...)
+	}
 	pg.db.Close()
+	if printStructured(pods) {
+		return
+	}
+
+	w := getWriter("HOST-NAME")
+	for _, n := range nodes {
+		nodeID := fmt.Sprintf("%s (%s):", n.name, n.hostIP)
+		fmt.Fprintf(w, "%s\t\t\t\t\t\t\t\n", nodeID)
+		fmt.Fprintf(w, "%s\t\t\t\t\t\t\t\n", strings.Repeat("-", len(nodeID)))
+
+		printPods(w, pods, n.hostIP)
+		fmt.Fprintln(w, "\t\t\t\t\t\t\t\t")
+	}
 	w.Flush()
 }
 
 //PrintPodsPerNode will print out all of the non-local pods for a certain
 // pods along with their tap interface ip address
 func PrintPodsPerNode(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd, input string) {
-	w := getWriter("")
 	pg := newPodGetter(client, db)
-	pg.printPodsPerNode(w, input, "")
+	hostIP := resolveNodeOrIP(pg.db, input)
+	nodeName := input
+	for name, node := range getClusterNodeInfo(pg.db) {
+		if node.mgmtIPAddress == hostIP {
+			nodeName = name
+		}
+	}
+	pods := pg.getPodsPerNode(hostIP, nodeName)
 	pg.db.Close()
+	if printStructured(pods) {
+		return
+	}
+
+	w := getWriter("")
+	printPods(w, pods, hostIP)
 	w.Flush()
 }
 
@@ -83,7 +125,7 @@ func newPodGetter(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd) *podG
 	pg.pods = make([]*pod.Pod, 0)
 	itr, err := pg.db.ListValues(ksrPrefix + pod.KeyPrefix())
 	if err != nil {
-		fmt.Printf("Failed to get pods from etcd, error %s\n", err)
+		printError("Failed to get pods from etcd, error %s\n", err)
 		os.Exit(2)
 	}
 
@@ -95,7 +137,7 @@ func newPodGetter(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd) *podG
 		buf := kv.GetValue()
 		podInfo := &pod.Pod{}
 		if err = jsonpb.UnmarshalString(string(buf), podInfo); err != nil {
-			fmt.Printf("Failed to unmarshall pod, error %s\n", err)
+			printError("Failed to unmarshall pod, error %s\n", err)
 			continue
 		}
 		pg.pods = append(pg.pods, podInfo)
@@ -104,18 +146,24 @@ func newPodGetter(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd) *podG
 	return pg
 }
 
-func (pg *podGetter) printAllPods(w *tabwriter.Writer) {
+// podNode identifies a node hosting pods.
+type podNode struct {
+	name   string
+	hostIP string
+}
+
+// getNodes returns all nodes of the cluster as reflected by KSR.
+func (pg *podGetter) getNodes() (nodes []*podNode) {
 	ksrPrefix := servicelabel.GetDifferentAgentPrefix(ksr.MicroserviceLabel)
 	itr, err := pg.db.ListValues(ksrPrefix + node.KeyPrefix())
 	if err != nil {
-		fmt.Printf("Error getting values")
-		return
+		printError("Error getting values\n")
+		return nil
 	}
 
 	for {
 		kv, stop := itr.GetNext()
 		if stop {
-			fmt.Println()
 			break
 		}
 		buf := kv.GetValue()
@@ -129,42 +177,66 @@ func (pg *podGetter) printAllPods(w *tabwriter.Writer) {
 				break
 			}
 		}
-		nodeID := fmt.Sprintf("%s (%s):", nodeInfo.Name, mgmtAddr)
-		fmt.Fprintf(w, "%s\t\t\t\t\t\t\t\n", nodeID)
-		fmt.Fprintf(w, "%s\t\t\t\t\t\t\t\n", strings.Repeat("-", len(nodeID)))
-
-		pg.printPodsPerNode(w, mgmtAddr, nodeInfo.Name)
-		fmt.Fprintln(w, "\t\t\t\t\t\t\t\t")
+		nodes = append(nodes, &podNode{name: nodeInfo.Name, hostIP: mgmtAddr})
 	}
+	return nodes
 }
 
-func (pg *podGetter) printPodsPerNode(w *tabwriter.Writer, nodeNameOrIP string, nodeName string) {
-	hostIP := resolveNodeOrIP(pg.db, nodeNameOrIP)
-
-	fmt.Fprintf(w, "POD-NAME\tNAMESPACE\tPOD-IP\tIF-IDX\tIF-NAME\n")
-
+// getPodsPerNode returns network information of pods deployed on the node with
+// the given host IP address.
+func (pg *podGetter) getPodsPerNode(hostIP string, nodeName string) []*PodInfo {
+	pods := make([]*PodInfo, 0)
 	for _, podInfo := range pg.pods {
 		if podInfo.HostIpAddress != hostIP {
 			continue
-		} else if podInfo.IpAddress == hostIP {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
-				podInfo.Name,
-				podInfo.Namespace,
-				podInfo.IpAddress,
-				"", "", "")
+		}
+		p := &PodInfo{
+			Name:      podInfo.Name,
+			Namespace: podInfo.Namespace,
+			Node:      nodeName,
+			HostIP:    hostIP,
+			IP:        podInfo.IpAddress,
+			IPs:       podInfo.IpAddresses,
+		}
+		if podInfo.IpAddress == hostIP {
+			p.HostNetwork = true
+		} else {
+			p.IfIndex, p.IfName, p.IfTag = pg.getTapInterfaceForPod(podInfo)
+		}
+		pods = append(pods, p)
+	}
+	return pods
+}
+
+// printPods prints pods deployed on the node with the given host IP address.
+func printPods(w *tabwriter.Writer, pods []*PodInfo, hostIP string) {
+	if Output == OutputWide {
+		fmt.Fprintf(w, "POD-NAME\tNAMESPACE\tPOD-IP\tIF-IDX\tIF-NAME\tIF-TAG\tPOD-IPS\n")
+	} else {
+		fmt.Fprintf(w, "POD-NAME\tNAMESPACE\tPOD-IP\tIF-IDX\tIF-NAME\n")
+	}
+
+	for _, p := range pods {
+		if p.HostIP != hostIP {
+			continue
+		}
+		ifIndex := ""
+		if !p.HostNetwork {
+			ifIndex = fmt.Sprintf("%d", p.IfIndex)
+		}
+		if Output == OutputWide {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				p.Name, p.Namespace, p.IP, ifIndex, p.IfName, p.IfTag, strings.Join(p.IPs, ","))
 		} else {
-			ifIndex, name := pg.getTapInterfaceForPod(podInfo)
-			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n",
-				podInfo.Name,
-				podInfo.Namespace,
-				podInfo.IpAddress,
-				ifIndex,
-				name)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				p.Name, p.Namespace, p.IP, ifIndex, p.IfName)
 		}
 	}
 }
 
-func (pg *podGetter) getTapInterfaceForPod(podInfo *pod.Pod) (uint32, string) {
+// getTapInterfaceForPod returns index, internal name and logical name of the VPP
+// interface connecting the given pod.
+func (pg *podGetter) getTapInterfaceForPod(podInfo *pod.Pod) (uint32, string, string) {
 	// when we see a pod from a given node for the first time, retrieve its
 	// IPAM and interface info
 	if pg.ndCache[podInfo.HostIpAddress] == nil {
@@ -172,25 +244,25 @@ func (pg *podGetter) getTapInterfaceForPod(podInfo *pod.Pod) (uint32, string) {
 		// Get ipam data for the node where the pod is hosted
 		b, err := getNodeInfo(pg.client, podInfo.HostIpAddress, getIpamDataCmd)
 		if err != nil {
-			fmt.Printf("Host '%s', Pod '%s' - failed to get ipam, err %s\n",
+			printError("Host '%s', Pod '%s' - failed to get ipam, err %s\n",
 				podInfo.HostIpAddress, podInfo.Name, err)
-			return 0, "N/A"
+			return 0, "N/A", ""
 		}
 
 		ipam := &restapi.NodeIPAMInfo{}
 		if err := json.Unmarshal(b, ipam); err != nil {
-			fmt.Printf("Host '%s', Pod '%s' - failed to decode ipam, err %s\n",
+			printError("Host '%s', Pod '%s' - failed to decode ipam, err %s\n",
 				podInfo.HostIpAddress, podInfo.Name, err)
-			return 0, "N/A"
+			return 0, "N/A", ""
 		}
 
 		// Get interfaces data for the node where the pod is hosted
 		b, err = getNodeInfo(pg.client, podInfo.HostIpAddress, resturl.Interface)
 		intfs := make(map[uint32]*vppif.InterfaceDetails, 0)
 		if err := json.Unmarshal(b, &intfs); err != nil {
-			fmt.Printf("Host '%s', Pod '%s' - failed to get pod's interface, err %s\n",
+			printError("Host '%s', Pod '%s' - failed to get pod's interface, err %s\n",
 				podInfo.HostIpAddress, podInfo.Name, err)
-			return 0, "N/A"
+			return 0, "N/A", ""
 		}
 
 		// Get arp entries
@@ -198,9 +270,9 @@ func (pg *podGetter) getTapInterfaceForPod(podInfo *pod.Pod) (uint32, string) {
 		b, err = getNodeInfo(pg.client, podInfo.HostIpAddress, arpDumpCmd)
 		arps := telemetrymodel.NodeIPArpTable{}
 		if err := json.Unmarshal(b, &arps); err != nil {
-			fmt.Printf("Host '%s' - failed to get arps, err %s\n",
+			printError("Host '%s' - failed to get arps, err %s\n",
 				podInfo.HostIpAddress, err)
-			return 0, "N/A"
+			return 0, "N/A", ""
 		}
 
 		pg.ndCache[podInfo.HostIpAddress] = &nodeData{
@@ -216,13 +288,13 @@ func (pg *podGetter) getTapInterfaceForPod(podInfo *pod.Pod) (uint32, string) {
 
 	podNetwork, podIPMask, err := getIPAddressAndMask(pg.ndCache[podInfo.HostIpAddress].ipam.PodSubnetThisNode)
 	if err != nil {
-		fmt.Printf("Host '%s', Pod '%s' - invalid PodSubnetThisNode address %s, err %s\n",
+		printError("Host '%s', Pod '%s' - invalid PodSubnetThisNode address %s, err %s\n",
 			podInfo.HostIpAddress, podInfo.Name, pg.ndCache[podInfo.HostIpAddress].ipam.PodSubnetThisNode, err)
 		// Do not return - we can still continue if this error happens
 	}
 
 	if podMask != podIPMask {
-		fmt.Printf("Host '%s', Pod '%s' - vppHostSubnetOneNodePrefixLen mismatch: "+
+		printError("Host '%s', Pod '%s' - vppHostSubnetOneNodePrefixLen mismatch: "+
 			"PodSubnetThisNode '%s', podSubnetOneNodePrefixLen '%d'\n",
 			podInfo.HostIpAddress, podInfo.Name,
 			pg.ndCache[podInfo.HostIpAddress].ipam.PodSubnetThisNode,
@@ -232,13 +304,13 @@ func (pg *podGetter) getTapInterfaceForPod(podInfo *pod.Pod) (uint32, string) {
 
 	podAddr, err := ip2uint32(podInfo.IpAddress)
 	if err != nil {
-		fmt.Printf("Host '%s', Pod '%s' - invalid podInfo.IpAddress %s, err %s",
+		printError("Host '%s', Pod '%s' - invalid podInfo.IpAddress %s, err %s",
 			podInfo.HostIpAddress, podInfo.Name, podInfo.IpAddress, err)
-		return 0, "N/A"
+		return 0, "N/A", ""
 	}
 
 	if podAddr&^podMask != podNetwork {
-		fmt.Printf("Host '%s', Pod '%s' - pod IP address %s not from PodSubnetThisNode subnet %s\n",
+		printError("Host '%s', Pod '%s' - pod IP address %s not from PodSubnetThisNode subnet %s\n",
 			podInfo.HostIpAddress, podInfo.Name, podInfo.IpAddress,
 			pg.ndCache[podInfo.HostIpAddress].ipam.PodSubnetThisNode)
 		// Do not return - we can still continue if this error happens
@@ -256,16 +328,16 @@ func (pg *podGetter) getTapInterfaceForPod(podInfo *pod.Pod) (uint32, string) {
 	}
 
 	if !found {
-		return 0, "N/A"
+		return 0, "N/A", ""
 	}
 
 	for _, intf := range pg.ndCache[podInfo.HostIpAddress].ifcs {
 		if intf.Interface.Name == ifName {
-			return intf.Meta.SwIfIndex, intf.Meta.InternalName
+			return intf.Meta.SwIfIndex, intf.Meta.InternalName, intf.Interface.Name
 		}
 	}
 
-	return 0, "N/A"
+	return 0, "N/A", ""
 }
 
 func getWriter(hostName string) *tabwriter.Writer {
@@ -0,0 +1,283 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdimpl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ghodss/yaml"
+	"go.ligato.io/cn-infra/v2/db/keyval/etcd"
+	"go.ligato.io/cn-infra/v2/logging/logrus"
+
+	"github.com/contiv/vpp/plugins/ksr"
+	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
+	npV1 "github.com/contiv/vpp/plugins/ksr/pkg/apis/networking/v1"
+	"github.com/contiv/vpp/plugins/netctl/remote"
+	"github.com/contiv/vpp/plugins/policy/restapi"
+)
+
+// PolicyCheckCmd asks the given node (or any node if empty) whether the traffic
+// would be allowed by the current network policies combined with the candidate
+// policies read from the given YAML file (optional), and prints the verdict.
+func PolicyCheckCmd(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd, nodeName, candidateFile string,
+	req *restapi.PolicyCheckRequest) {
+
+	if candidateFile != "" {
+		candidates, err := readCandidatePolicies(candidateFile)
+		if err != nil {
+			fmt.Printf("Failed to read candidate policies from %s: %v\n", candidateFile, err)
+			return
+		}
+		req.Candidates = candidates
+	}
+
+	if nodeName == "" {
+		nodes := make([]string, 0)
+		for k := range getClusterNodeInfo(db) {
+			nodes = append(nodes, k)
+		}
+		if len(nodes) == 0 {
+			fmt.Println("No node found in Contiv cluster")
+			return
+		}
+		sort.Strings(nodes)
+		nodeName = nodes[0]
+	}
+	ip := resolveNodeOrIP(db, nodeName)
+	if ip == "" {
+		fmt.Printf("Unknown node %s\n", nodeName)
+		return
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	res, err := client.Post(ip, policyCheckCmd, string(body))
+	if err != nil {
+		fmt.Printf("policy check: url: %s Post Error: %s\n", policyCheckCmd, err.Error())
+		return
+	}
+	defer res.Body.Close()
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		var errMsg string
+		if json.Unmarshal(b, &errMsg) != nil {
+			errMsg = res.Status
+		}
+		fmt.Printf("Policy check failed: %s\n", errMsg)
+		return
+	}
+	resp := &restapi.PolicyCheckResponse{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if printStructured(resp) {
+		return
+	}
+	printPolicyCheck(req, resp)
+}
+
+// readCandidatePolicies reads K8s network policies from a (multi-document)
+// YAML file and converts them into the protobuf model.
+func readCandidatePolicies(file string) (policies []*policymodel.Policy, err error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	log := logrus.NewLogger("policy-check")
+	for _, doc := range strings.Split(string(data), "\n---") {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		k8sPolicy := &npV1.NetworkPolicy{}
+		if err := yaml.Unmarshal([]byte(doc), k8sPolicy); err != nil {
+			return nil, err
+		}
+		if k8sPolicy.Kind != "" && k8sPolicy.Kind != "NetworkPolicy" {
+			return nil, fmt.Errorf("unexpected kind %s", k8sPolicy.Kind)
+		}
+		if k8sPolicy.Namespace == "" {
+			k8sPolicy.Namespace = "default"
+		}
+		policies = append(policies, ksr.PolicyToProto(k8sPolicy, log))
+	}
+	return policies, nil
+}
+
+// printPolicyCheck prints the verdict of the policy check.
+func printPolicyCheck(req *restapi.PolicyCheckRequest, resp *restapi.PolicyCheckResponse) {
+	verdict := func(allowed bool) string {
+		if allowed {
+			return "ALLOWED"
+		}
+		return "DENIED"
+	}
+	protocol := req.Protocol
+	if protocol == "" {
+		protocol = "TCP"
+	}
+	fmt.Printf("%s -> %s (%s:%d): %s\n\n", req.Source, req.Destination, strings.ToUpper(protocol), req.Port,
+		verdict(resp.Allowed))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "DIRECTION\tPOD\tVERDICT\tRULE\tPOLICIES\n")
+	for _, direction := range []struct {
+		name    string
+		verdict *restapi.PolicyCheckVerdict
+	}{
+		{name: "egress", verdict: resp.Egress},
+		{name: "ingress", verdict: resp.Ingress},
+	} {
+		v := direction.verdict
+		if v == nil {
+			continue
+		}
+		rule := v.Rule
+		if !v.Isolated {
+			rule = "<not isolated>"
+		} else if rule == "" {
+			rule = "<no rule matched>"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			direction.name, v.Pod, verdict(v.Allowed), rule, strings.Join(v.Policies, ","))
+	}
+	w.Flush()
+}
+
+// PrintAllPolicies prints policies applied to the pods of all nodes together
+// with the rendered rule tables.
+func PrintAllPolicies(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd) {
+	nodes := make([]string, 0)
+	for k := range getClusterNodeInfo(db) {
+		nodes = append(nodes, k)
+	}
+	sort.Strings(nodes)
+
+	var names []string
+	allPolicies := make([]*restapi.NodePodPolicies, 0, len(nodes))
+	for _, n := range nodes {
+		if podPolicies := getNodePodPolicies(client, db, n); podPolicies != nil {
+			names = append(names, n)
+			allPolicies = append(allPolicies, podPolicies)
+		}
+	}
+	if printStructured(allPolicies) {
+		return
+	}
+	for i, podPolicies := range allPolicies {
+		printNodePodPolicies(names[i], podPolicies)
+	}
+}
+
+// PrintPoliciesPerNode prints policies applied to the pods of the given node
+// together with the rendered rule tables and the indexes of the VPP ACLs
+// attached to the pod interfaces.
+func PrintPoliciesPerNode(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd, nodeName string) {
+	podPolicies := getNodePodPolicies(client, db, nodeName)
+	if podPolicies == nil || printStructured(podPolicies) {
+		return
+	}
+	printNodePodPolicies(nodeName, podPolicies)
+}
+
+// getNodePodPolicies reads policies applied to the pods of the given node.
+func getNodePodPolicies(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd, nodeName string) *restapi.NodePodPolicies {
+	ip := resolveNodeOrIP(db, nodeName)
+	if ip == "" {
+		printError("Unknown node %s\n", nodeName)
+		return nil
+	}
+
+	b, err := getNodeInfo(client, ip, policyPodsCmd)
+	if err != nil {
+		printError("%v\n", err)
+		return nil
+	}
+	podPolicies := &restapi.NodePodPolicies{}
+	if err := json.Unmarshal(b, podPolicies); err != nil {
+		printError("%v\n", err)
+		return nil
+	}
+	return podPolicies
+}
+
+// printNodePodPolicies prints policies and rule tables of the pods of the given node.
+func printNodePodPolicies(nodeName string, podPolicies *restapi.NodePodPolicies) {
+	fmt.Printf("%s:\n", nodeName)
+	fmt.Printf("%s\n", strings.Repeat("=", len(nodeName)+1))
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "POD\tINTERFACE\tPOLICIES\tACL-INDEXES\n")
+	for _, pod := range podPolicies.Pods {
+		policies := strings.Join(pod.Policies, ",")
+		if policies == "" {
+			policies = "<not isolated>"
+		}
+		var aclIndexes []string
+		for _, table := range pod.Tables {
+			if table.Attached {
+				aclIndexes = append(aclIndexes, tableIndex(table))
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", pod.Pod, pod.Interface, policies, strings.Join(aclIndexes, ","))
+	}
+	w.Flush()
+
+	for _, pod := range podPolicies.Pods {
+		if len(pod.Tables) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s:\n", pod.Pod)
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintf(w, "TABLE\tINDEX\tDIRECTION\tATTACHED\tRULES\n")
+		for _, table := range pod.Tables {
+			direction := "ingress"
+			if table.Egress {
+				direction = "egress"
+			}
+			for i, rule := range table.Rules {
+				if i == 0 {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n",
+						table.Name, tableIndex(table), direction, table.Attached, rule)
+				} else {
+					fmt.Fprintf(w, "\t\t\t\t%s\n", rule)
+				}
+			}
+		}
+		w.Flush()
+	}
+	fmt.Println()
+}
+
+// tableIndex returns the index of the rule table as a string.
+func tableIndex(table *restapi.RuleTable) string {
+	if table.Index < 0 {
+		return "N/A"
+	}
+	return strconv.Itoa(table.Index)
+}
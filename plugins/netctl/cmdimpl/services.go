@@ -0,0 +1,172 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdimpl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"go.ligato.io/cn-infra/v2/db/keyval/etcd"
+
+	"github.com/contiv/vpp/plugins/netctl/remote"
+	"github.com/contiv/vpp/plugins/service/restapi"
+)
+
+// PrintAllServices prints services as rendered on all nodes.
+// The output can be limited to a single service given as namespace/name.
+func PrintAllServices(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd, service string) {
+	nodes := make([]string, 0)
+	for k := range getClusterNodeInfo(db) {
+		nodes = append(nodes, k)
+	}
+	sort.Strings(nodes)
+
+	var names []string
+	allServices := make([]*restapi.NodeServices, 0, len(nodes))
+	for _, n := range nodes {
+		if services := getNodeServices(client, db, n, service); services != nil {
+			names = append(names, n)
+			allServices = append(allServices, services)
+		}
+	}
+	if printStructured(allServices) {
+		return
+	}
+	for i, services := range allServices {
+		printNodeServices(names[i], services)
+	}
+}
+
+// PrintServicesPerNode prints services as rendered on the given node - frontends,
+// backends and the resulting configuration (NAT44 static mappings or SRv6
+// policies and localsids).
+// The output can be limited to a single service given as namespace/name.
+func PrintServicesPerNode(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd, nodeName, service string) {
+	services := getNodeServices(client, db, nodeName, service)
+	if services == nil || printStructured(services) {
+		return
+	}
+	printNodeServices(nodeName, services)
+}
+
+// getNodeServices reads services as rendered on the given node.
+func getNodeServices(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd, nodeName, service string) *restapi.NodeServices {
+	ip := resolveNodeOrIP(db, nodeName)
+	if ip == "" {
+		printError("Unknown node %s\n", nodeName)
+		return nil
+	}
+
+	cmd := servicesCmd
+	if service != "" {
+		svcID := strings.SplitN(service, "/", 2)
+		if len(svcID) != 2 {
+			printError("Invalid service %s, expected namespace/name\n", service)
+			return nil
+		}
+		query := url.Values{}
+		query.Set(restapi.ServiceNamespaceParam, svcID[0])
+		query.Set(restapi.ServiceNameParam, svcID[1])
+		cmd += "?" + query.Encode()
+	}
+	b, err := getNodeInfo(client, ip, cmd)
+	if err != nil {
+		printError("%v\n", err)
+		return nil
+	}
+	services := &restapi.NodeServices{}
+	if err := json.Unmarshal(b, services); err != nil {
+		printError("%v\n", err)
+		return nil
+	}
+	return services
+}
+
+// printNodeServices prints services as rendered on the given node.
+func printNodeServices(nodeName string, services *restapi.NodeServices) {
+	fmt.Printf("%s:\n", nodeName)
+	fmt.Printf("%s\n", strings.Repeat("=", len(nodeName)+1))
+	fmt.Printf("Frontend interfaces: %s\n", strings.Join(services.FrontendIfs, ", "))
+	fmt.Printf("Backend interfaces: %s\n\n", strings.Join(services.BackendIfs, ", "))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "SERVICE\tTRAFFIC-POLICY\tLB-MODE\tFRONTENDS\tNODE-PORT\tBACKENDS\n")
+	for _, svc := range services.Services {
+		serviceIPs := append(append([]string{}, svc.ClusterIPs...), svc.ExternalIPs...)
+		first := true
+		for _, port := range svc.Ports {
+			var frontends []string
+			for _, serviceIP := range serviceIPs {
+				frontends = append(frontends, fmt.Sprintf("%s:%d/%s", serviceIP, port.Port, port.Protocol))
+			}
+			nodePort := "-"
+			if port.NodePort != 0 {
+				nodePort = fmt.Sprintf("%d", port.NodePort)
+			}
+			var backends []string
+			for _, backend := range port.Backends {
+				backends = append(backends, backendString(backend))
+			}
+			if first {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", svc.ID, svc.TrafficPolicy, svc.LBMode,
+					strings.Join(frontends, ","), nodePort, strings.Join(backends, ","))
+				first = false
+			} else {
+				fmt.Fprintf(w, "\t\t\t%s\t%s\t%s\n",
+					strings.Join(frontends, ","), nodePort, strings.Join(backends, ","))
+			}
+		}
+	}
+	w.Flush()
+
+	for _, svc := range services.Services {
+		if len(svc.Errors) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s errors:\n", svc.ID)
+		for _, svcErr := range svc.Errors {
+			fmt.Printf("  %s\n", svcErr)
+		}
+	}
+
+	for _, svc := range services.Services {
+		if len(svc.Config) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s rendered configuration:\n", svc.ID)
+		for _, config := range svc.Config {
+			fmt.Printf("  %s\n    %s\n", config.Key, config.Value)
+		}
+	}
+	fmt.Println()
+}
+
+// backendString returns a human-readable representation of a service backend.
+func backendString(backend *restapi.ServiceBackend) string {
+	var flags []string
+	if backend.Local {
+		flags = append(flags, "local")
+	}
+	if backend.HostNetwork {
+		flags = append(flags, "host-network")
+	}
+	flags = append(flags, fmt.Sprintf("weight=%d", backend.Weight))
+	return fmt.Sprintf("%s:%d(%s)", backend.IP, backend.Port, strings.Join(flags, " "))
+}
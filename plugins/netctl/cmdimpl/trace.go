@@ -0,0 +1,322 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdimpl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+
+	"go.ligato.io/cn-infra/v2/db/keyval/etcd"
+	"go.ligato.io/cn-infra/v2/servicelabel"
+
+	"github.com/contiv/vpp/plugins/ksr"
+	"github.com/contiv/vpp/plugins/ksr/model/pod"
+	"github.com/contiv/vpp/plugins/netctl/remote"
+)
+
+// traceInputNodes lists VPP graph nodes where the packet tracing is started
+// - pod TAPs (TAP v2 and TAP v1) and uplink interfaces.
+var traceInputNodes = []string{"virtio-input", "tapcli-rx", "dpdk-input", "af-packet-input"}
+
+// traceHopRegex matches the first line of a graph node inside a VPP packet trace,
+// e.g. "00:01:33:812204: ip4-lookup".
+var traceHopRegex = regexp.MustCompile(`^\d+:\d{2}:\d{2}:\d{6}: (\S+)$`)
+
+// aclVerdictRegex matches the ACL action and the matched rule inside the trace
+// of acl-plugin graph nodes.
+var aclVerdictRegex = regexp.MustCompile(`action: (\d+)(?:, match: acl (\d+) rule (\d+))?`)
+
+// fibIndexRegex matches the index of the FIB used for the lookup inside the trace
+// of ip4-lookup/ip6-lookup graph nodes.
+var fibIndexRegex = regexp.MustCompile(`^fib (\d+)`)
+
+// tracePod is a pod participating in the traced communication.
+type tracePod struct {
+	id       string
+	ip       string
+	hostIP   string
+	nodeName string
+}
+
+// tracedPacket is a single packet captured by the VPP packet tracer.
+type tracedPacket struct {
+	hops []*traceHop
+}
+
+// traceHop is a single VPP graph node traversed by a traced packet.
+type traceHop struct {
+	vppNode string
+	details []string
+}
+
+// TraceCmd traces packets sent from the source to the destination pod (optionally
+// limited to the given destination port). Packet tracing is enabled on the nodes
+// of both pods, then it waits for the given duration for the matching packets
+// to be sent and finally collects VPP traces from both nodes and prints
+// the hop-by-hop path of the first matching packet found on each node.
+func TraceCmd(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd, srcPod, dstPod string,
+	port uint16, wait time.Duration, count int) {
+
+	src, err := getTracePod(db, srcPod)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	dst, err := getTracePod(db, dstPod)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	nodes := []*tracePod{src}
+	if dst.hostIP != src.hostIP {
+		nodes = append(nodes, dst)
+	}
+
+	// start tracing on all the involved nodes
+	for _, node := range nodes {
+		if _, err := vppCliOutput(client, node.hostIP, "clear trace"); err != nil {
+			fmt.Printf("Failed to clear packet trace on node %s: %v\n", node.nodeName, err)
+			return
+		}
+		for _, input := range traceInputNodes {
+			cliCmd := fmt.Sprintf("trace add %s %d", input, count)
+			if _, err := vppCliOutput(client, node.hostIP, cliCmd); err != nil {
+				fmt.Printf("Failed to enable packet trace on node %s: %v\n", node.nodeName, err)
+				return
+			}
+		}
+	}
+
+	flow := fmt.Sprintf("%s (%s) -> %s (%s)", src.id, src.ip, dst.id, dst.ip)
+	if port != 0 {
+		flow += fmt.Sprintf(" port %d", port)
+	}
+	fmt.Printf("Tracing %s for %v, generate the traffic now...\n\n", flow, wait)
+	time.Sleep(wait)
+
+	// collect and render the traces
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "NODE\tSTEP\tVPP-NODE\tDETAIL\n")
+	var found bool
+	for _, node := range nodes {
+		trace, err := vppCliOutput(client, node.hostIP, fmt.Sprintf("show trace max %d", count))
+		if err != nil {
+			fmt.Printf("Failed to read packet trace from node %s: %v\n", node.nodeName, err)
+			continue
+		}
+		vppCliOutput(client, node.hostIP, "clear trace")
+
+		var matching []*tracedPacket
+		for _, packet := range parseVppTrace(trace) {
+			if packet.matches(src.ip, dst.ip, port) {
+				matching = append(matching, packet)
+			}
+		}
+		if len(matching) == 0 {
+			fmt.Fprintf(w, "%s\t\t\tno matching packet captured\n", node.nodeName)
+			continue
+		}
+		found = true
+		for _, hop := range matching[0].hops {
+			step, detail := hop.describe()
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", node.nodeName, step, hop.vppNode, detail)
+		}
+		if len(matching) > 1 {
+			fmt.Fprintf(w, "%s\t\t\t(%d more matching packets captured)\n", node.nodeName, len(matching)-1)
+		}
+	}
+	w.Flush()
+	if !found {
+		fmt.Println("\nNo matching packets were captured, make sure the traffic was generated while tracing.")
+	}
+}
+
+// getTracePod reads pod given as namespace/name from the database.
+func getTracePod(db *etcd.BytesConnectionEtcd, podID string) (*tracePod, error) {
+	id := strings.SplitN(podID, "/", 2)
+	if len(id) != 2 {
+		return nil, fmt.Errorf("invalid pod %s, expected namespace/name", podID)
+	}
+	ksrPrefix := servicelabel.GetDifferentAgentPrefix(ksr.MicroserviceLabel)
+	data, found, _, err := db.GetValue(ksrPrefix + pod.Key(id[1], id[0]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pod %s: %v", podID, err)
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown pod %s", podID)
+	}
+	podInfo := &pod.Pod{}
+	if err = jsonpb.UnmarshalString(string(data), podInfo); err != nil {
+		return nil, fmt.Errorf("failed to decode pod %s: %v", podID, err)
+	}
+	if podInfo.IpAddress == "" || podInfo.HostIpAddress == "" {
+		return nil, fmt.Errorf("pod %s is not running", podID)
+	}
+
+	tp := &tracePod{
+		id:       podID,
+		ip:       podInfo.IpAddress,
+		hostIP:   podInfo.HostIpAddress,
+		nodeName: podInfo.HostIpAddress,
+	}
+	for name, node := range getClusterNodeInfo(db) {
+		if node.mgmtIPAddress == podInfo.HostIpAddress {
+			tp.nodeName = name
+		}
+	}
+	return tp, nil
+}
+
+// vppCliOutput executes the given VPP debug CLI command on the node with the given
+// management IP address and returns the command output.
+func vppCliOutput(client *remote.HTTPClient, ipAdr string, cliCmd string) (string, error) {
+	body := fmt.Sprintf("{\"vppclicommand\":\"%s\"}", cliCmd)
+	res, err := client.Post(ipAdr, vppCommandCmd, body)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return "", fmt.Errorf("vppCliOutput: HTTP res.Status: %s", res.Status)
+	}
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+	var output string
+	if err := json.Unmarshal(b, &output); err != nil {
+		return strings.Replace(string(b), "\\n", "\n", -1), nil
+	}
+	return output, nil
+}
+
+// parseVppTrace parses the output of the "show trace" VPP CLI command.
+func parseVppTrace(trace string) (packets []*tracedPacket) {
+	var packet *tracedPacket
+	for _, line := range strings.Split(trace, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "Packet "):
+			packet = &tracedPacket{}
+			packets = append(packets, packet)
+		case packet == nil || line == "" || strings.HasPrefix(line, "---"):
+			continue
+		default:
+			if match := traceHopRegex.FindStringSubmatch(line); match != nil {
+				packet.hops = append(packet.hops, &traceHop{vppNode: match[1]})
+			} else if len(packet.hops) > 0 {
+				hop := packet.hops[len(packet.hops)-1]
+				hop.details = append(hop.details, line)
+			}
+		}
+	}
+	return packets
+}
+
+// matches returns true if the packet (or the packet encapsulated inside) was sent
+// from srcIP to dstIP (and to the given destination port if not zero).
+func (p *tracedPacket) matches(srcIP, dstIP string, port uint16) bool {
+	var ipMatch, portMatch bool
+	portMatch = port == 0
+	for _, hop := range p.hops {
+		for _, line := range hop.details {
+			fields := strings.Fields(line)
+			for i := 1; i < len(fields)-1; i++ {
+				if fields[i] != "->" {
+					continue
+				}
+				if fields[i-1] == srcIP && fields[i+1] == dstIP {
+					ipMatch = true
+				}
+				if (fields[0] == "TCP:" || fields[0] == "UDP:") &&
+					fields[i+1] == strconv.Itoa(int(port)) {
+					portMatch = true
+				}
+			}
+		}
+	}
+	return ipMatch && portMatch
+}
+
+// describe returns the step of the packet path represented by the hop together
+// with the most relevant detail from the trace.
+func (h *traceHop) describe() (step, detail string) {
+	if len(h.details) > 0 {
+		detail = h.details[0]
+	}
+	switch {
+	case h.vppNode == "virtio-input" || h.vppNode == "tapcli-rx":
+		step = "pod TAP (rx)"
+	case strings.HasPrefix(h.vppNode, "tap") &&
+		(strings.HasSuffix(h.vppNode, "-tx") || strings.HasSuffix(h.vppNode, "-output")):
+		step = "pod TAP (tx)"
+	case h.vppNode == "dpdk-input" || h.vppNode == "af-packet-input":
+		step = "uplink (rx)"
+	case h.vppNode == "ip4-lookup" || h.vppNode == "ip6-lookup":
+		step = "VRF lookup"
+		for _, line := range h.details {
+			if match := fibIndexRegex.FindStringSubmatch(line); match != nil {
+				detail = "fib " + match[1]
+				break
+			}
+		}
+	case strings.HasPrefix(h.vppNode, "vxlan") && strings.HasSuffix(h.vppNode, "-encap"):
+		step = "VXLAN encap"
+	case strings.HasPrefix(h.vppNode, "vxlan") && strings.HasSuffix(h.vppNode, "-input"):
+		step = "VXLAN decap"
+	case strings.HasPrefix(h.vppNode, "sr-pl-rewrite"):
+		step = "SRv6 encap"
+	case strings.HasPrefix(h.vppNode, "sr-localsid"):
+		step = "SRv6 localsid"
+	case strings.HasPrefix(h.vppNode, "nat44-"):
+		step = "NAT"
+	case strings.HasPrefix(h.vppNode, "acl-plugin-"):
+		step = "ACL"
+		for _, line := range h.details {
+			if match := aclVerdictRegex.FindStringSubmatch(line); match != nil {
+				detail = aclVerdict(match[1])
+				if match[2] != "" {
+					detail += fmt.Sprintf(" (acl %s rule %s)", match[2], match[3])
+				}
+				break
+			}
+		}
+	case h.vppNode == "error-drop" || h.vppNode == "drop":
+		step = "DROP"
+	}
+	return step, detail
+}
+
+// aclVerdict translates action of the VPP ACL plugin into a human-readable verdict.
+func aclVerdict(action string) string {
+	switch action {
+	case "0":
+		return "deny"
+	case "1":
+		return "permit"
+	case "2":
+		return "permit+reflect"
+	}
+	return "action " + action
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdimpl
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// showTrace is an output of "show trace" captured on a node with VXLAN overlay:
+//   - packet 1: TCP SYN sent from a local pod to a pod on another node,
+//     permitted by the policy ACL and encapsulated into VXLAN,
+//   - packet 2: UDP packet received from another node over VXLAN,
+//     denied by the policy ACL of the destination pod.
+const showTrace = `------------------- Start of thread 0 vpp_main -------------------
+Packet 1
+
+00:01:33:812123: virtio-input
+  virtio: hw_if_index 5 next-index 4 vring 0 len 74
+    hdr: flags 0x00 gso_type 0x00 hdr_len 0 gso_size 0 csum_start 0 csum_offset 0 num_buffers 1
+00:01:33:812150: ethernet-input
+  frame: flags 0x1, hw-if-index 5, sw-if-index 5
+  IP4: 02:fe:fc:07:21:82 -> 00:00:00:aa:aa:aa
+00:01:33:812160: ip4-input
+  TCP: 10.1.1.3 -> 10.1.2.4
+    tos 0x00, ttl 64, length 60, checksum 0x1c1d
+    fragment id 0x6b6d, flags DONT_FRAGMENT
+  TCP: 44518 -> 8080
+    seq. 0x93c6b5a4 ack 0x00000000
+    flags 0x02 SYN, tcp header: 40 bytes
+    window 64240, checksum 0x1643
+00:01:33:812170: acl-plugin-in-ip4-fa
+  acl-plugin: lc_index: 0, sw_if_index 5, next index 1, action: 2, match: acl 3 rule 1 trace_bits 00000000
+   lc_index 0 l3 ip4 10.1.1.3 -> 10.1.2.4 l4 lsb_of_sw_if_index 5 proto 6 l4_is_input 1 l4_slow_path 0 l4_flags 0x02 port 44518 -> 8080 tcp flags (valid) 02 rsvd 0
+00:01:33:812180: ip4-lookup
+  fib 1 dpo-idx 7 flow hash: 0x00000000
+  TCP: 10.1.1.3 -> 10.1.2.4
+    tos 0x00, ttl 64, length 60, checksum 0x1c1d
+    fragment id 0x6b6d, flags DONT_FRAGMENT
+  TCP: 44518 -> 8080
+    seq. 0x93c6b5a4 ack 0x00000000
+    flags 0x02 SYN, tcp header: 40 bytes
+    window 64240, checksum 0x1643
+00:01:33:812190: ip4-rewrite
+  tx_sw_if_index 3 dpo-idx 7 : ipv4 via 192.168.30.2 vxlanBVI: mtu:1450 next:5 1a2b3c4d5e02 1a2b3c4d5e01 flow hash: 0x00000000
+00:01:33:812200: vxlan4-encap
+  VXLAN encap to vxlan_tunnel0 vni 10
+00:01:33:812210: GigabitEthernet0/8/0-output
+  GigabitEthernet0/8/0
+  IP4: 08:00:27:4b:5c:6d -> 08:00:27:1a:2b:3c
+  UDP: 192.168.16.1 -> 192.168.16.2
+    tos 0x00, ttl 254, length 110, checksum 0x2a7c
+    fragment id 0x0000
+  UDP: 24513 -> 4789
+    length 90, checksum 0x0000
+
+Packet 2
+
+00:01:34:000100: dpdk-input
+  GigabitEthernet0/8/0 rx queue 0
+  buffer 0x9b2c1: current data 0, length 124, buffer-pool 0, ref-count 1, totlen-nifb 0, trace handle 0x1
+  IP4: 08:00:27:1a:2b:3c -> 08:00:27:4b:5c:6d
+  UDP: 192.168.16.2 -> 192.168.16.1
+    tos 0x00, ttl 254, length 110, checksum 0x2a7c
+    fragment id 0x0000
+  UDP: 31257 -> 4789
+    length 90, checksum 0x0000
+00:01:34:000110: vxlan4-input
+  VXLAN decap from vxlan_tunnel0 vni 10 next 1 error 0
+00:01:34:000120: ip4-input
+  UDP: 10.1.2.4 -> 10.1.1.3
+    tos 0x00, ttl 64, length 60, checksum 0x1c2e
+    fragment id 0x1a2b
+  UDP: 53 -> 40000
+    length 40, checksum 0x5f21
+00:01:34:000130: acl-plugin-out-ip4-fa
+  acl-plugin: lc_index: 1, sw_if_index 6, next index 0, action: 0, match: acl 4 rule 0 trace_bits 00000000
+00:01:34:000140: error-drop
+  rx:tap1
+00:01:34:000150: drop
+  acl-plugin-out-ip4-fa: ACL deny packets
+`
+
+// hopNames returns names of VPP graph nodes traversed by the packet.
+func hopNames(packet *tracedPacket) (names []string) {
+	for _, hop := range packet.hops {
+		names = append(names, hop.vppNode)
+	}
+	return names
+}
+
+// findHop returns the first hop of the packet traversing the given VPP graph node.
+func findHop(packet *tracedPacket, vppNode string) *traceHop {
+	for _, hop := range packet.hops {
+		if hop.vppNode == vppNode {
+			return hop
+		}
+	}
+	return nil
+}
+
+func TestParseVppTrace(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(parseVppTrace("")).To(BeEmpty())
+	Expect(parseVppTrace("No packets in trace buffer\n")).To(BeEmpty())
+
+	packets := parseVppTrace(showTrace)
+	Expect(packets).To(HaveLen(2))
+	Expect(hopNames(packets[0])).To(Equal([]string{
+		"virtio-input", "ethernet-input", "ip4-input", "acl-plugin-in-ip4-fa",
+		"ip4-lookup", "ip4-rewrite", "vxlan4-encap", "GigabitEthernet0/8/0-output",
+	}))
+	Expect(hopNames(packets[1])).To(Equal([]string{
+		"dpdk-input", "vxlan4-input", "ip4-input", "acl-plugin-out-ip4-fa", "error-drop", "drop",
+	}))
+
+	// details are trimmed and assigned to the preceding hop
+	Expect(packets[0].hops[0].details).To(Equal([]string{
+		"virtio: hw_if_index 5 next-index 4 vring 0 len 74",
+		"hdr: flags 0x00 gso_type 0x00 hdr_len 0 gso_size 0 csum_start 0 csum_offset 0 num_buffers 1",
+	}))
+	Expect(packets[0].hops[6].details).To(Equal([]string{"VXLAN encap to vxlan_tunnel0 vni 10"}))
+	Expect(packets[1].hops[5].details).To(Equal([]string{"acl-plugin-out-ip4-fa: ACL deny packets"}))
+}
+
+func TestTracedPacketMatches(t *testing.T) {
+	RegisterTestingT(t)
+
+	packets := parseVppTrace(showTrace)
+	Expect(packets).To(HaveLen(2))
+
+	tests := []struct {
+		name     string
+		packet   *tracedPacket
+		srcIP    string
+		dstIP    string
+		port     uint16
+		expected bool
+	}{
+		{name: "pod to pod", packet: packets[0], srcIP: "10.1.1.3", dstIP: "10.1.2.4", expected: true},
+		{name: "pod to pod port", packet: packets[0], srcIP: "10.1.1.3", dstIP: "10.1.2.4", port: 8080, expected: true},
+		{name: "source port", packet: packets[0], srcIP: "10.1.1.3", dstIP: "10.1.2.4", port: 44518, expected: false},
+		{name: "other port", packet: packets[0], srcIP: "10.1.1.3", dstIP: "10.1.2.4", port: 80, expected: false},
+		{name: "reverse direction", packet: packets[0], srcIP: "10.1.2.4", dstIP: "10.1.1.3", expected: false},
+		{name: "other pod", packet: packets[0], srcIP: "10.1.1.3", dstIP: "10.1.2.5", expected: false},
+		{name: "VXLAN outer header", packet: packets[0], srcIP: "192.168.16.1", dstIP: "192.168.16.2", expected: true},
+		{name: "encapsulated packet", packet: packets[1], srcIP: "10.1.2.4", dstIP: "10.1.1.3", port: 40000, expected: true},
+		{name: "encapsulated source port", packet: packets[1], srcIP: "10.1.2.4", dstIP: "10.1.1.3", port: 53, expected: false},
+		{name: "no hops", packet: &tracedPacket{}, srcIP: "10.1.1.3", dstIP: "10.1.2.4", expected: false},
+	}
+	for _, test := range tests {
+		Expect(test.packet.matches(test.srcIP, test.dstIP, test.port)).To(Equal(test.expected), test.name)
+	}
+}
+
+func TestTraceHopDescribe(t *testing.T) {
+	RegisterTestingT(t)
+
+	packets := parseVppTrace(showTrace)
+	Expect(packets).To(HaveLen(2))
+
+	tests := []struct {
+		hop    *traceHop
+		step   string
+		detail string
+	}{
+		{hop: findHop(packets[0], "virtio-input"),
+			step: "pod TAP (rx)", detail: "virtio: hw_if_index 5 next-index 4 vring 0 len 74"},
+		{hop: findHop(packets[0], "ethernet-input"),
+			step: "", detail: "frame: flags 0x1, hw-if-index 5, sw-if-index 5"},
+		{hop: findHop(packets[0], "acl-plugin-in-ip4-fa"),
+			step: "ACL", detail: "permit+reflect (acl 3 rule 1)"},
+		{hop: findHop(packets[0], "ip4-lookup"),
+			step: "VRF lookup", detail: "fib 1"},
+		{hop: findHop(packets[0], "vxlan4-encap"),
+			step: "VXLAN encap", detail: "VXLAN encap to vxlan_tunnel0 vni 10"},
+		{hop: findHop(packets[1], "dpdk-input"),
+			step: "uplink (rx)", detail: "GigabitEthernet0/8/0 rx queue 0"},
+		{hop: findHop(packets[1], "vxlan4-input"),
+			step: "VXLAN decap", detail: "VXLAN decap from vxlan_tunnel0 vni 10 next 1 error 0"},
+		{hop: findHop(packets[1], "acl-plugin-out-ip4-fa"),
+			step: "ACL", detail: "deny (acl 4 rule 0)"},
+		{hop: findHop(packets[1], "error-drop"),
+			step: "DROP", detail: "rx:tap1"},
+		{hop: &traceHop{vppNode: "acl-plugin-in-ip6-fa", details: []string{
+			"acl-plugin: lc_index: 2, sw_if_index 7, next index 1, action: 1, trace_bits 80000000"}},
+			step: "ACL", detail: "permit"},
+		{hop: &traceHop{vppNode: "tap2-output"}, step: "pod TAP (tx)"},
+		{hop: &traceHop{vppNode: "tapcli-rx"}, step: "pod TAP (rx)"},
+		{hop: &traceHop{vppNode: "ip6-lookup", details: []string{"fib 2 dpo-idx 12 flow hash: 0x00000000"}},
+			step: "VRF lookup", detail: "fib 2"},
+		{hop: &traceHop{vppNode: "sr-pl-rewrite-encaps-v4", details: []string{"src 2001::1 dst 6666:0:0:1::"}},
+			step: "SRv6 encap", detail: "src 2001::1 dst 6666:0:0:1::"},
+		{hop: &traceHop{vppNode: "sr-localsid-d"}, step: "SRv6 localsid"},
+		{hop: &traceHop{vppNode: "nat44-in2out-slowpath"}, step: "NAT"},
+	}
+	for _, test := range tests {
+		Expect(test.hop).ToNot(BeNil())
+		step, detail := test.hop.describe()
+		Expect(step).To(Equal(test.step), test.hop.vppNode)
+		Expect(detail).To(Equal(test.detail), test.hop.vppNode)
+	}
+}
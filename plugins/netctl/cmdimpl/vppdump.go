@@ -26,7 +26,7 @@ import (
 // dumpIndex defines index page for kvscheduler Dump REST API to be un-marshalled
 // from JSON data.
 type dumpIndex struct {
-	Descriptors []string
+	Descriptors []string `json:"descriptors"`
 }
 
 // DumpCmd executes the specified vpp dump operation on the specified node.
@@ -37,13 +37,16 @@ func DumpCmd(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd, nodeName s
 	if nodeName == "" || dumpType == "" {
 		b, err := getNodeInfo(client, "", vppDumpCommand(""))
 		if err != nil {
-			fmt.Println(err)
+			printError("%v\n", err)
 			return
 		}
 		index := dumpIndex{}
 		err = json.Unmarshal(b, &index)
 		if err != nil {
-			fmt.Println(err)
+			printError("%v\n", err)
+			return
+		}
+		if printStructured(index) {
 			return
 		}
 		fmt.Printf("Command usage: netctl vppdump %s <cmd>:\n", nodeName)
@@ -53,20 +56,30 @@ func DumpCmd(client *remote.HTTPClient, db *etcd.BytesConnectionEtcd, nodeName s
 		return
 	}
 
-	fmt.Printf("vppdump %s %s\n", nodeName, dumpType)
 	ipAdr := resolveNodeOrIP(db, nodeName)
 	if ipAdr == "" {
-		fmt.Printf("Unknown node name %s", nodeName)
+		printError("Unknown node name %s\n", nodeName)
 		return
 	}
 
 	cmd := vppDumpCommand(dumpType)
 	b, err := getNodeInfo(client, ipAdr, cmd)
 	if err != nil {
-		fmt.Println(err)
+		printError("%v\n", err)
 		return
 	}
 
+	if structuredOutput() {
+		// the dump is re-encoded, which requires a JSON body
+		var dump interface{}
+		if err := json.Unmarshal(b, &dump); err != nil {
+			printError("%v\n", err)
+			return
+		}
+		printStructured(dump)
+		return
+	}
+	fmt.Printf("vppdump %s %s\n", nodeName, dumpType)
 	fmt.Printf("%s", b)
 }
 
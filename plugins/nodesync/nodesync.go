@@ -42,6 +42,12 @@ const (
 	maxAllocationAttempts = 10
 )
 
+// topologyLabelPrefixes lists prefixes of node labels describing the node topology.
+var topologyLabelPrefixes = []string{
+	"topology.kubernetes.io/",
+	"failure-domain.beta.kubernetes.io/",
+}
+
 // NodeSync plugin implements synchronization between Kubernetes nodes running
 // VPP vswitch using a key-value database (by default etcd). Specifically,
 // it allocates the first free positive integer, starting with 1, as a cluster-wide
@@ -235,6 +241,7 @@ func (ns *NodeSync) Resync(event controller.Event, kubeStateData controller.Kube
 		node := ns.nodes[k8sNode.Name]
 		node.MgmtIPAddresses = ns.nodeMgmtAddresses(k8sNode)
 		node.PodCIDR = ns.nodePodCIDR(k8sNode)
+		node.TopologyLabels = ns.nodeTopologyLabels(k8sNode)
 	}
 
 	// allocate ID for this node if it is not already
@@ -299,6 +306,27 @@ func (ns *NodeSync) nodePodCIDR(nodeProto proto.Message) *net.IPNet {
 	return nil
 }
 
+// nodeTopologyLabels returns topology labels of the given node.
+func (ns *NodeSync) nodeTopologyLabels(nodeProto proto.Message) map[string]string {
+	if nodeProto == nil {
+		return nil
+	}
+	node := nodeProto.(*nodemodel.Node)
+	var labels map[string]string
+	for key, value := range node.Labels {
+		for _, prefix := range topologyLabelPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				if labels == nil {
+					labels = make(map[string]string)
+				}
+				labels[key] = value
+				break
+			}
+		}
+	}
+	return labels
+}
+
 // nodeVPPAddresses returns a list of node IP addresses on the VPP side.
 func (ns *NodeSync) nodeVPPAddresses(vppNode *vppnode.VppNode) (addresses contivconf.IPsWithNetworks) {
 	var err error
@@ -430,6 +458,7 @@ func (ns *NodeSync) Update(event controller.Event, txn controller.UpdateOperatio
 		if node != nil {
 			node.MgmtIPAddresses = mgmtAddrs
 			node.PodCIDR = ns.nodePodCIDR(kubeStateChange.NewValue)
+			node.TopologyLabels = ns.nodeTopologyLabels(kubeStateChange.NewValue)
 			if node.ID != 0 {
 				ns.EventLoop.PushEvent(&NodeUpdate{
 					NodeName:  nodeName,
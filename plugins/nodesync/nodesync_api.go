@@ -40,6 +40,22 @@ type API interface {
 	GetAllNodes() Nodes // node name -> node info
 }
 
+const (
+	// ZoneLabel is the well-known node label with the name of the availability
+	// zone the node is deployed in.
+	ZoneLabel = "topology.kubernetes.io/zone"
+
+	// RegionLabel is the well-known node label with the name of the region
+	// the node is deployed in.
+	RegionLabel = "topology.kubernetes.io/region"
+
+	// LegacyZoneLabel is the deprecated variant of ZoneLabel.
+	LegacyZoneLabel = "failure-domain.beta.kubernetes.io/zone"
+
+	// LegacyRegionLabel is the deprecated variant of RegionLabel.
+	LegacyRegionLabel = "failure-domain.beta.kubernetes.io/region"
+)
+
 // Node represents a single node in the cluster.
 type Node struct {
 	ID              uint32
@@ -47,6 +63,29 @@ type Node struct {
 	VppIPAddresses  contivconf.IPsWithNetworks
 	MgmtIPAddresses []net.IP
 	PodCIDR         *net.IPNet
+	TopologyLabels  map[string]string
+}
+
+// Zone returns the availability zone of the node (empty if unknown).
+func (n *Node) Zone() string {
+	if n == nil {
+		return ""
+	}
+	if zone := n.TopologyLabels[ZoneLabel]; zone != "" {
+		return zone
+	}
+	return n.TopologyLabels[LegacyZoneLabel]
+}
+
+// Region returns the region of the node (empty if unknown).
+func (n *Node) Region() string {
+	if n == nil {
+		return ""
+	}
+	if region := n.TopologyLabels[RegionLabel]; region != "" {
+		return region
+	}
+	return n.TopologyLabels[LegacyRegionLabel]
 }
 
 // Nodes is a map of node-name -> Node info.
@@ -57,8 +96,8 @@ func (n *Node) String() string {
 	if n == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("<ID: %d, Name: %s, VPP-IPs: %v, Mgmt-IPs: %v",
-		n.ID, n.Name, n.VppIPAddresses.String(), n.MgmtIPAddresses)
+	return fmt.Sprintf("<ID: %d, Name: %s, VPP-IPs: %v, Mgmt-IPs: %v, Zone: %s",
+		n.ID, n.Name, n.VppIPAddresses.String(), n.MgmtIPAddresses, n.Zone())
 }
 
 // String returns a string representation of nodes.
@@ -80,9 +119,9 @@ func (ns Nodes) String() string {
 // For other nodes, the event is triggered when:
 //   - node joins the cluster
 //   - node leaves the cluster
-//   - VPP or management IP addresses of the node are updated
+//   - VPP or management IP addresses or topology labels of the node are updated
 // For this node, the event is triggered only when:
-//   - the management IP addresses are updated
+//   - the management IP addresses or topology labels are updated
 // For update of this node VPP IP addresses, there is already resync event NodeIPv*Change.
 type NodeUpdate struct {
 	NodeName  string
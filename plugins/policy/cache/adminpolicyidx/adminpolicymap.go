@@ -0,0 +1,168 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adminpolicyidx
+
+import (
+	adminpolicymodel "github.com/contiv/vpp/plugins/ksr/model/adminpolicy"
+	"go.ligato.io/cn-infra/v2/idxmap"
+	"go.ligato.io/cn-infra/v2/idxmap/mem"
+	"go.ligato.io/cn-infra/v2/logging"
+)
+
+const (
+	// admin policies with rules referencing peers by namespace selectors
+	// (either of all pods from the namespaces or of the pod selector)
+	adminPeerNSLabelKey = "adminPeerNSLabelKey"
+	adminPeerAnyNSKey   = "adminPeerAnyNSKey"
+
+	// admin policies with rules referencing peers by pod selectors
+	adminPeerPodLabelKey = "adminPeerPodLabelKey"
+	adminPeerAnyPodKey   = "adminPeerAnyPodKey"
+
+	// admin policies with subjects selected by namespace selectors
+	// (either all pods from the namespaces or the pods of the pod selector)
+	adminSubjectNSLabelKey = "adminSubjectNSLabelKey"
+	adminSubjectAnyNSKey   = "adminSubjectAnyNSKey"
+
+	// anySelector is the only value of the adminPeerAny*Key indexes
+	anySelector = "*"
+)
+
+// ConfigIndex implements a cache for configured admin policies.
+// Primary index is the policy name.
+type ConfigIndex struct {
+	mapping idxmap.NamedMappingRW
+}
+
+// NewConfigIndex creates new instance of ConfigIndex
+func NewConfigIndex(logger logging.Logger, title string) *ConfigIndex {
+	return &ConfigIndex{mapping: mem.NewNamedMapping(logger, title, IndexFunction)}
+}
+
+// RegisterPolicy adds new admin policy entry into the mapping.
+func (ci *ConfigIndex) RegisterPolicy(name string, data *adminpolicymodel.AdminPolicy) {
+	ci.mapping.Put(name, data)
+}
+
+// UnregisterPolicy removes an admin policy entry from the mapping.
+func (ci *ConfigIndex) UnregisterPolicy(name string) (found bool, data *adminpolicymodel.AdminPolicy) {
+	d, found := ci.mapping.Delete(name)
+	if found {
+		if data, ok := d.(*adminpolicymodel.AdminPolicy); ok {
+			return found, data
+		}
+	}
+	return false, nil
+}
+
+// LookupPolicy looks up an admin policy entry given the policy name.
+func (ci *ConfigIndex) LookupPolicy(name string) (found bool, data *adminpolicymodel.AdminPolicy) {
+	d, found := ci.mapping.GetValue(name)
+	if found {
+		if data, ok := d.(*adminpolicymodel.AdminPolicy); ok {
+			return found, data
+		}
+	}
+	return false, nil
+}
+
+// LookupPolicyByPeerNSLabel performs lookup of admin policies with a peer
+// namespace selector containing the match label given as key/value.
+func (ci *ConfigIndex) LookupPolicyByPeerNSLabel(peerNSLabelSelector string) (names []string) {
+	return ci.mapping.ListNames(adminPeerNSLabelKey, peerNSLabelSelector)
+}
+
+// LookupPolicyByPeerAnyNamespace returns admin policies with a peer namespace
+// selector without any match label (may select any namespace).
+func (ci *ConfigIndex) LookupPolicyByPeerAnyNamespace() (names []string) {
+	return ci.mapping.ListNames(adminPeerAnyNSKey, anySelector)
+}
+
+// LookupPolicyByPeerPodLabel performs lookup of admin policies with a peer
+// pod selector containing the match label given as key/value.
+func (ci *ConfigIndex) LookupPolicyByPeerPodLabel(peerPodLabelSelector string) (names []string) {
+	return ci.mapping.ListNames(adminPeerPodLabelKey, peerPodLabelSelector)
+}
+
+// LookupPolicyByPeerAnyPod returns admin policies with a peer pod selector
+// without any match label (may select any pod).
+func (ci *ConfigIndex) LookupPolicyByPeerAnyPod() (names []string) {
+	return ci.mapping.ListNames(adminPeerAnyPodKey, anySelector)
+}
+
+// LookupPolicyBySubjectNSLabel performs lookup of admin policies with a subject
+// namespace selector containing the match label given as key/value.
+func (ci *ConfigIndex) LookupPolicyBySubjectNSLabel(subjectNSLabelSelector string) (names []string) {
+	return ci.mapping.ListNames(adminSubjectNSLabelKey, subjectNSLabelSelector)
+}
+
+// LookupPolicyBySubjectAnyNamespace returns admin policies with a subject
+// namespace selector without any match label (may select any namespace).
+func (ci *ConfigIndex) LookupPolicyBySubjectAnyNamespace() (names []string) {
+	return ci.mapping.ListNames(adminSubjectAnyNSKey, anySelector)
+}
+
+// ListAll returns all registered names in the mapping.
+func (ci *ConfigIndex) ListAll() (names []string) {
+	return ci.mapping.ListAllNames()
+}
+
+// IndexFunction creates secondary indexes.
+func IndexFunction(data interface{}) map[string][]string {
+	res := map[string][]string{}
+
+	indexNamespaces := func(selector *adminpolicymodel.AdminPolicy_LabelSelector, labelKey, anyKey string) {
+		if len(selector.GetMatchLabel()) == 0 {
+			res[anyKey] = []string{anySelector}
+		}
+		for _, v := range selector.GetMatchLabel() {
+			res[labelKey] = append(res[labelKey], v.Key+"/"+v.Value)
+		}
+	}
+
+	if config, ok := data.(*adminpolicymodel.AdminPolicy); ok && config != nil {
+		// index namespaces of the subject
+		if subject := config.GetSubject(); subject != nil {
+			if subject.Namespaces != nil {
+				indexNamespaces(subject.Namespaces, adminSubjectNSLabelKey, adminSubjectAnyNSKey)
+			}
+			if subject.Pods != nil {
+				indexNamespaces(subject.Pods.Namespaces, adminSubjectNSLabelKey, adminSubjectAnyNSKey)
+			}
+		}
+
+		// index peers of all rules
+		rules := append([]*adminpolicymodel.AdminPolicy_Rule{}, config.IngressRule...)
+		rules = append(rules, config.EgressRule...)
+		for _, rule := range rules {
+			for _, peer := range rule.Peers {
+				if peer.Namespaces != nil {
+					indexNamespaces(peer.Namespaces, adminPeerNSLabelKey, adminPeerAnyNSKey)
+				}
+				if peer.Pods != nil {
+					indexNamespaces(peer.Pods.Namespaces, adminPeerNSLabelKey, adminPeerAnyNSKey)
+					if len(peer.Pods.Pods.GetMatchLabel()) == 0 {
+						res[adminPeerAnyPodKey] = []string{anySelector}
+					}
+					for _, v := range peer.Pods.Pods.GetMatchLabel() {
+						res[adminPeerPodLabelKey] = append(res[adminPeerPodLabelKey], v.Key+"/"+v.Value)
+					}
+				}
+			}
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adminpolicyidx
+
+import (
+	"testing"
+
+	adminpolicymodel "github.com/contiv/vpp/plugins/ksr/model/adminpolicy"
+	"github.com/onsi/gomega"
+	"go.ligato.io/cn-infra/v2/logging/logrus"
+)
+
+func labelSelector(key, value string) *adminpolicymodel.AdminPolicy_LabelSelector {
+	return &adminpolicymodel.AdminPolicy_LabelSelector{
+		MatchLabel: []*adminpolicymodel.AdminPolicy_Label{{Key: key, Value: value}},
+	}
+}
+
+func TestRegisterUnregister(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	idx := NewConfigIndex(logrus.DefaultLogger(), "title")
+	gomega.Expect(idx).NotTo(gomega.BeNil())
+
+	const (
+		policyOne = "deny-quarantine"
+		policyTwo = "allow-monitoring"
+	)
+
+	res := idx.ListAll()
+	gomega.Expect(res).To(gomega.BeNil())
+
+	idx.RegisterPolicy(policyOne, &adminpolicymodel.AdminPolicy{Name: policyOne})
+	idx.RegisterPolicy(policyTwo, &adminpolicymodel.AdminPolicy{Name: policyTwo})
+	gomega.Expect(idx.ListAll()).To(gomega.ConsistOf(policyOne, policyTwo))
+
+	found, data := idx.LookupPolicy(policyOne)
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(data.Name).To(gomega.Equal(policyOne))
+
+	found, data = idx.UnregisterPolicy(policyOne)
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(data.Name).To(gomega.Equal(policyOne))
+	found, _ = idx.LookupPolicy(policyOne)
+	gomega.Expect(found).To(gomega.BeFalse())
+
+	// unregistering of non-existing item does nothing
+	found, _ = idx.UnregisterPolicy(policyOne)
+	gomega.Expect(found).To(gomega.BeFalse())
+	gomega.Expect(idx.ListAll()).To(gomega.ConsistOf(policyTwo))
+}
+
+func TestSecondaryIndexLookup(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	idx := NewConfigIndex(logrus.DefaultLogger(), "title")
+	gomega.Expect(idx).NotTo(gomega.BeNil())
+
+	const (
+		policyOne   = "deny-quarantine"
+		policyTwo   = "allow-monitoring"
+		policyThree = "allow-dns"
+		policyFour  = "deny-all"
+	)
+
+	// peers selected by namespaces
+	idx.RegisterPolicy(policyOne, &adminpolicymodel.AdminPolicy{
+		Name: policyOne,
+		IngressRule: []*adminpolicymodel.AdminPolicy_Rule{{
+			Action: adminpolicymodel.AdminPolicy_DENY,
+			Peers:  []*adminpolicymodel.AdminPolicy_Peer{{Namespaces: labelSelector("quarantine", "true")}},
+		}},
+	})
+	// peers selected by pods inside selected namespaces
+	idx.RegisterPolicy(policyTwo, &adminpolicymodel.AdminPolicy{
+		Name: policyTwo,
+		EgressRule: []*adminpolicymodel.AdminPolicy_Rule{{
+			Peers: []*adminpolicymodel.AdminPolicy_Peer{{
+				Pods: &adminpolicymodel.AdminPolicy_PodSelector{
+					Namespaces: labelSelector("team", "monitoring"),
+					Pods:       labelSelector("app", "prometheus"),
+				},
+			}},
+		}},
+	})
+	// peers selected by pods from any namespace
+	idx.RegisterPolicy(policyThree, &adminpolicymodel.AdminPolicy{
+		Name: policyThree,
+		EgressRule: []*adminpolicymodel.AdminPolicy_Rule{{
+			Peers: []*adminpolicymodel.AdminPolicy_Peer{{
+				Pods: &adminpolicymodel.AdminPolicy_PodSelector{
+					Pods: labelSelector("k8s-app", "kube-dns"),
+				},
+			}},
+		}},
+	})
+	// peers selected by networks only, and any pod of any namespace
+	idx.RegisterPolicy(policyFour, &adminpolicymodel.AdminPolicy{
+		Name: policyFour,
+		EgressRule: []*adminpolicymodel.AdminPolicy_Rule{
+			{
+				Action: adminpolicymodel.AdminPolicy_DENY,
+				Peers:  []*adminpolicymodel.AdminPolicy_Peer{{Networks: []string{"10.0.0.0/8"}}},
+			},
+			{
+				Action: adminpolicymodel.AdminPolicy_DENY,
+				Peers: []*adminpolicymodel.AdminPolicy_Peer{{
+					Pods: &adminpolicymodel.AdminPolicy_PodSelector{},
+				}},
+			},
+		},
+	})
+
+	gomega.Expect(idx.LookupPolicyByPeerNSLabel("quarantine/true")).To(gomega.ConsistOf(policyOne))
+	gomega.Expect(idx.LookupPolicyByPeerNSLabel("team/monitoring")).To(gomega.ConsistOf(policyTwo))
+	gomega.Expect(idx.LookupPolicyByPeerNSLabel("app/prometheus")).To(gomega.BeEmpty())
+	gomega.Expect(idx.LookupPolicyByPeerAnyNamespace()).To(gomega.ConsistOf(policyThree, policyFour))
+
+	gomega.Expect(idx.LookupPolicyByPeerPodLabel("app/prometheus")).To(gomega.ConsistOf(policyTwo))
+	gomega.Expect(idx.LookupPolicyByPeerPodLabel("k8s-app/kube-dns")).To(gomega.ConsistOf(policyThree))
+	gomega.Expect(idx.LookupPolicyByPeerPodLabel("quarantine/true")).To(gomega.BeEmpty())
+	gomega.Expect(idx.LookupPolicyByPeerAnyPod()).To(gomega.ConsistOf(policyFour))
+
+	// indexes are updated with the policy
+	idx.RegisterPolicy(policyOne, &adminpolicymodel.AdminPolicy{Name: policyOne})
+	gomega.Expect(idx.LookupPolicyByPeerNSLabel("quarantine/true")).To(gomega.BeEmpty())
+	idx.UnregisterPolicy(policyFour)
+	gomega.Expect(idx.LookupPolicyByPeerAnyNamespace()).To(gomega.ConsistOf(policyThree))
+	gomega.Expect(idx.LookupPolicyByPeerAnyPod()).To(gomega.BeEmpty())
+}
+
+func TestSubjectIndexLookup(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	idx := NewConfigIndex(logrus.DefaultLogger(), "title")
+	gomega.Expect(idx).NotTo(gomega.BeNil())
+
+	const (
+		policyOne   = "isolate-quarantine"
+		policyTwo   = "isolate-monitoring"
+		policyThree = "deny-all"
+		policyFour  = "allow-from-quarantine"
+	)
+
+	// subject selected by namespaces
+	idx.RegisterPolicy(policyOne, &adminpolicymodel.AdminPolicy{
+		Name:    policyOne,
+		Subject: &adminpolicymodel.AdminPolicy_Subject{Namespaces: labelSelector("quarantine", "true")},
+	})
+	// subject selected by pods inside selected namespaces
+	idx.RegisterPolicy(policyTwo, &adminpolicymodel.AdminPolicy{
+		Name: policyTwo,
+		Subject: &adminpolicymodel.AdminPolicy_Subject{
+			Pods: &adminpolicymodel.AdminPolicy_PodSelector{
+				Namespaces: labelSelector("team", "monitoring"),
+				Pods:       labelSelector("app", "prometheus"),
+			},
+		},
+	})
+	// subject selected by pods from any namespace
+	idx.RegisterPolicy(policyThree, &adminpolicymodel.AdminPolicy{
+		Name: policyThree,
+		Subject: &adminpolicymodel.AdminPolicy_Subject{
+			Pods: &adminpolicymodel.AdminPolicy_PodSelector{},
+		},
+	})
+	// namespace selected only as a peer
+	idx.RegisterPolicy(policyFour, &adminpolicymodel.AdminPolicy{
+		Name: policyFour,
+		IngressRule: []*adminpolicymodel.AdminPolicy_Rule{{
+			Peers: []*adminpolicymodel.AdminPolicy_Peer{{Namespaces: labelSelector("quarantine", "true")}},
+		}},
+	})
+
+	gomega.Expect(idx.LookupPolicyBySubjectNSLabel("quarantine/true")).To(gomega.ConsistOf(policyOne))
+	gomega.Expect(idx.LookupPolicyBySubjectNSLabel("team/monitoring")).To(gomega.ConsistOf(policyTwo))
+	gomega.Expect(idx.LookupPolicyBySubjectNSLabel("app/prometheus")).To(gomega.BeEmpty())
+	gomega.Expect(idx.LookupPolicyBySubjectAnyNamespace()).To(gomega.ConsistOf(policyThree))
+	gomega.Expect(idx.LookupPolicyByPeerNSLabel("quarantine/true")).To(gomega.ConsistOf(policyFour))
+
+	// indexes are updated with the policy
+	idx.RegisterPolicy(policyOne, &adminpolicymodel.AdminPolicy{Name: policyOne})
+	gomega.Expect(idx.LookupPolicyBySubjectNSLabel("quarantine/true")).To(gomega.BeEmpty())
+	idx.UnregisterPolicy(policyThree)
+	gomega.Expect(idx.LookupPolicyBySubjectAnyNamespace()).To(gomega.BeEmpty())
+}
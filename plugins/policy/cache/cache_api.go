@@ -18,7 +18,10 @@ package cache
 
 import (
 	controller "github.com/contiv/vpp/plugins/controller/api"
+	hostpolicymodel "github.com/contiv/vpp/plugins/crd/handler/hostpolicy/model"
+	adminpolicymodel "github.com/contiv/vpp/plugins/ksr/model/adminpolicy"
 	nsmodel "github.com/contiv/vpp/plugins/ksr/model/namespace"
+	nodemodel "github.com/contiv/vpp/plugins/ksr/model/node"
 	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
 	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
 )
@@ -70,14 +73,63 @@ type PolicyCacheAPI interface {
 	// LookupPoliciesByPod returns IDs of all policies assigned to a given pod.
 	LookupPoliciesByPod(pod podmodel.ID) (policies []policymodel.ID)
 
+	// LookupPoliciesByPeerPod returns IDs of all policies with a rule whose
+	// peer pod selector selects a pod with the given data.
+	LookupPoliciesByPeerPod(pod *podmodel.Pod) (policies []policymodel.ID)
+
+	// LookupPoliciesByPeerNamespace returns IDs of all policies with a rule
+	// whose peer namespace selector selects a namespace with the given data.
+	LookupPoliciesByPeerNamespace(ns *nsmodel.Namespace) (policies []policymodel.ID)
+
 	// ListAllPolicies returns IDs of all policies.
 	ListAllPolicies() (policies []policymodel.ID)
 
+	// LookupPodsByAdminSelector evaluates selector of an admin policy subject
+	// or peer (either namespaces or pods inside namespaces) and returns IDs
+	// of matching pods.
+	LookupPodsByAdminSelector(namespaces *adminpolicymodel.AdminPolicy_LabelSelector,
+		pods *adminpolicymodel.AdminPolicy_PodSelector) []podmodel.ID
+
+	// LookupAdminPolicy returns data of a given admin policy.
+	LookupAdminPolicy(name string) (found bool, data *adminpolicymodel.AdminPolicy)
+
+	// LookupAdminPoliciesByPod returns names of all admin policies whose subject
+	// selects a given pod.
+	LookupAdminPoliciesByPod(pod podmodel.ID) (policies []string)
+
+	// LookupAdminPoliciesByPeerPod returns names of all admin policies with
+	// a rule whose peer selects a pod with the given data.
+	LookupAdminPoliciesByPeerPod(pod *podmodel.Pod) (policies []string)
+
+	// LookupAdminPoliciesByPeerNamespace returns names of all admin policies
+	// with a rule whose peer selects pods from a namespace with the given data.
+	LookupAdminPoliciesByPeerNamespace(ns *nsmodel.Namespace) (policies []string)
+
+	// LookupAdminPoliciesBySubjectNamespace returns names of all admin policies
+	// whose subject selects pods from a namespace with the given data.
+	LookupAdminPoliciesBySubjectNamespace(ns *nsmodel.Namespace) (policies []string)
+
+	// ListAllAdminPolicies returns names of all admin policies.
+	ListAllAdminPolicies() (policies []string)
+
 	// LookupNamespace returns data of a given namespace.
 	LookupNamespace(namespace nsmodel.ID) (found bool, data *nsmodel.Namespace)
 
 	// ListAllNamespaces returns IDs of all known namespaces.
 	ListAllNamespaces() (namespaces []nsmodel.ID)
+
+	// LookupHostPolicy returns data of a given host policy.
+	LookupHostPolicy(name string) (found bool, data *hostpolicymodel.HostPolicy)
+
+	// LookupHostPoliciesByNode returns names of all host policies whose node
+	// selector selects a given node.
+	LookupHostPoliciesByNode(node string) (policies []string)
+
+	// ListAllHostPolicies returns names of all host policies.
+	ListAllHostPolicies() (policies []string)
+
+	// LookupNode returns data of a given K8s node.
+	LookupNode(name string) (found bool, data *nodemodel.Node)
 }
 
 // PolicyCacheWatcher defines interface that a PolicyCache watcher must implement.
@@ -104,6 +156,16 @@ type PolicyCacheWatcher interface {
 	// modified.
 	UpdatePolicy(oldPolicy, newPolicy *policymodel.Policy) error
 
+	// AddAdminPolicy is called by Policy Cache when a new admin policy is created.
+	AddAdminPolicy(policy *adminpolicymodel.AdminPolicy) error
+
+	// DelAdminPolicy is called by Policy Cache after an admin policy was removed.
+	DelAdminPolicy(policy *adminpolicymodel.AdminPolicy) error
+
+	// UpdateAdminPolicy is called by Policy Cache when data of an admin policy
+	// were modified.
+	UpdateAdminPolicy(oldPolicy, newPolicy *adminpolicymodel.AdminPolicy) error
+
 	// AddNamespace is called by Policy Cache when a new namespace is created.
 	AddNamespace(ns *nsmodel.Namespace) error
 
@@ -113,4 +175,24 @@ type PolicyCacheWatcher interface {
 	// UpdateNamespace is called by Policy Cache when data of a namespace were
 	// modified.
 	UpdateNamespace(oldNs, newNs *nsmodel.Namespace) error
+
+	// AddHostPolicy is called by Policy Cache when a new host policy is created.
+	AddHostPolicy(policy *hostpolicymodel.HostPolicy) error
+
+	// DelHostPolicy is called by Policy Cache after a host policy was removed.
+	DelHostPolicy(policy *hostpolicymodel.HostPolicy) error
+
+	// UpdateHostPolicy is called by Policy Cache when data of a host policy
+	// were modified.
+	UpdateHostPolicy(oldPolicy, newPolicy *hostpolicymodel.HostPolicy) error
+
+	// AddNode is called by Policy Cache when a new K8s node is created.
+	AddNode(node *nodemodel.Node) error
+
+	// DelNode is called by Policy Cache after a K8s node was removed.
+	DelNode(node *nodemodel.Node) error
+
+	// UpdateNode is called by Policy Cache when data of a K8s node were
+	// modified.
+	UpdateNode(oldNode, newNode *nodemodel.Node) error
 }
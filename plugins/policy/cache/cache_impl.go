@@ -1,12 +1,18 @@
 package cache
 
 import (
+	"sort"
+
 	"go.ligato.io/cn-infra/v2/logging"
 
 	controller "github.com/contiv/vpp/plugins/controller/api"
+	hostpolicymodel "github.com/contiv/vpp/plugins/crd/handler/hostpolicy/model"
+	adminpolicymodel "github.com/contiv/vpp/plugins/ksr/model/adminpolicy"
 	nsmodel "github.com/contiv/vpp/plugins/ksr/model/namespace"
+	nodemodel "github.com/contiv/vpp/plugins/ksr/model/node"
 	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
 	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
+	"github.com/contiv/vpp/plugins/policy/cache/adminpolicyidx"
 	"github.com/contiv/vpp/plugins/policy/cache/namespaceidx"
 	"github.com/contiv/vpp/plugins/policy/cache/podidx"
 	"github.com/contiv/vpp/plugins/policy/cache/policyidx"
@@ -24,10 +30,14 @@ import (
 type PolicyCache struct {
 	Deps
 
-	configuredPolicies   *policyidx.ConfigIndex
-	configuredPods       *podidx.ConfigIndex
-	configuredNamespaces *namespaceidx.ConfigIndex
-	watchers             []PolicyCacheWatcher
+	configuredPolicies      *policyidx.ConfigIndex
+	configuredPods          *podidx.ConfigIndex
+	configuredNamespaces    *namespaceidx.ConfigIndex
+	configuredAdminPolicies *adminpolicyidx.ConfigIndex
+	// host policies and nodes are few and only looked up by name, no need for an index
+	configuredHostPolicies map[string]*hostpolicymodel.HostPolicy
+	configuredNodes        map[string]*nodemodel.Node
+	watchers               []PolicyCacheWatcher
 }
 
 // Deps lists dependencies of PolicyCache.
@@ -48,6 +58,9 @@ func (pc *PolicyCache) reset() {
 	pc.configuredPolicies = policyidx.NewConfigIndex(pc.Log, "policies")
 	pc.configuredPods = podidx.NewConfigIndex(pc.Log, "pods")
 	pc.configuredNamespaces = namespaceidx.NewConfigIndex(pc.Log, "namespaces")
+	pc.configuredAdminPolicies = adminpolicyidx.NewConfigIndex(pc.Log, "admin-policies")
+	pc.configuredHostPolicies = make(map[string]*hostpolicymodel.HostPolicy)
+	pc.configuredNodes = make(map[string]*nodemodel.Node)
 }
 
 // Update processes a K8s state data change event.
@@ -177,33 +190,63 @@ func (pc *PolicyCache) LookupPolicy(policy policymodel.ID) (found bool, data *po
 }
 
 // LookupPoliciesByPod returns the IDs of all policies assigned to a given pod.
+// Candidates are obtained from the index by the pod labels and namespace and
+// only those are evaluated against the pod.
 func (pc *PolicyCache) LookupPoliciesByPod(pod podmodel.ID) (policyIDs []policymodel.ID) {
-	policyIDs = []policymodel.ID{}
-	policyData := []*policymodel.Policy{}
-
-	// Get and store the data from all the policies in cache.
-	policies := pc.ListAllPolicies()
-	for _, policy := range policies {
-		found, data := pc.LookupPolicy(policy)
-		if !found {
-			continue
-		}
-		policyData = append(policyData, data)
+	found, podData := pc.LookupPod(pod)
+	if !found {
+		return []policymodel.ID{}
 	}
 
-	// For every policy data if podID matches any of policy's attached pods
-	// save and return the policyID
-	for _, pData := range policyData {
-		podIDs := pc.LookupPodsByLabelSelectorInsideNs(pData.Namespace, pData.Pods)
-		for _, podID := range podIDs {
-			if podID == pod {
-				policyID := policymodel.GetID(pData)
-				policyIDs = append(policyIDs, policyID)
+	candidates := pc.configuredPolicies.LookupPolicyBySubjectNamespace(pod.Namespace)
+	for _, label := range podData.Label {
+		nsLabelSelector := pod.Namespace + "/" + label.Key + "/" + label.Value
+		candidates = append(candidates, pc.configuredPolicies.LookupPolicyByNSLabelSelector(nsLabelSelector)...)
+	}
+	labels := podLabels(podData)
+	return pc.verifyPolicies(candidates, func(policy *policymodel.Policy) bool {
+		return policy.Namespace == pod.Namespace && selectorMatches(policy.Pods, labels)
+	})
+}
+
+// LookupPoliciesByPeerPod returns the IDs of all policies with a rule whose
+// peer pod selector selects the given pod (policies referencing the pod
+// by the namespace are returned by LookupPoliciesByPeerNamespace).
+func (pc *PolicyCache) LookupPoliciesByPeerPod(pod *podmodel.Pod) (policyIDs []policymodel.ID) {
+	candidates := pc.configuredPolicies.LookupPolicyByPeerPodNamespace(pod.Namespace)
+	for _, label := range pod.Label {
+		nsLabelSelector := pod.Namespace + "/" + label.Key + "/" + label.Value
+		candidates = append(candidates, pc.configuredPolicies.LookupPolicyByPeerPodNSLabel(nsLabelSelector)...)
+	}
+	labels := podLabels(pod)
+	return pc.verifyPolicies(candidates, func(policy *policymodel.Policy) bool {
+		if policy.Namespace != pod.Namespace {
+			return false
+		}
+		for _, peer := range policyPeers(policy) {
+			if peer.Pods != nil && selectorMatches(peer.Pods, labels) {
+				return true
 			}
 		}
-	}
+		return false
+	})
+}
 
-	return policyIDs
+// LookupPoliciesByPeerNamespace returns the IDs of all policies with a rule
+// whose peer namespace selector selects the given namespace.
+func (pc *PolicyCache) LookupPoliciesByPeerNamespace(ns *nsmodel.Namespace) (policyIDs []policymodel.ID) {
+	candidates := pc.configuredPolicies.LookupPolicyByPeerAnyNamespace()
+	for _, label := range ns.Label {
+		candidates = append(candidates, pc.configuredPolicies.LookupPolicyByPeerNSLabel(label.Key+"/"+label.Value)...)
+	}
+	return pc.verifyPolicies(candidates, func(policy *policymodel.Policy) bool {
+		for _, peer := range policyPeers(policy) {
+			if peer.Namespaces != nil && namespaceSelected(peer.Namespaces, ns) {
+				return true
+			}
+		}
+		return false
+	})
 }
 
 // ListAllPolicies returns IDs of all policies.
@@ -214,6 +257,137 @@ func (pc *PolicyCache) ListAllPolicies() (policyIDs []policymodel.ID) {
 	return policyIDs
 }
 
+// LookupPodsByAdminSelector evaluates selector of an admin policy subject
+// or peer (either namespaces or pods inside namespaces) and returns IDs
+// of matching pods.
+func (pc *PolicyCache) LookupPodsByAdminSelector(namespaces *adminpolicymodel.AdminPolicy_LabelSelector,
+	pods *adminpolicymodel.AdminPolicy_PodSelector) []podmodel.ID {
+
+	if pods == nil {
+		if namespaces == nil {
+			return []podmodel.ID{}
+		}
+		return pc.LookupPodsByNsLabelSelector(adminToPolicyLabelSelector(namespaces))
+	}
+
+	// Find namespaces with at least one pod selected by the namespace selector
+	// and evaluate the pod selector inside each of them.
+	selectedNs := make(map[string]struct{})
+	for _, pod := range pc.LookupPodsByNsLabelSelector(adminToPolicyLabelSelector(pods.Namespaces)) {
+		selectedNs[pod.Namespace] = struct{}{}
+	}
+	podSelector := adminToPolicyLabelSelector(pods.Pods)
+	selectedPods := []podmodel.ID{}
+	for namespace := range selectedNs {
+		selectedPods = append(selectedPods, pc.LookupPodsByLabelSelectorInsideNs(namespace, podSelector)...)
+	}
+	return selectedPods
+}
+
+// LookupAdminPolicy returns data of a given admin policy.
+func (pc *PolicyCache) LookupAdminPolicy(name string) (found bool, data *adminpolicymodel.AdminPolicy) {
+	return pc.configuredAdminPolicies.LookupPolicy(name)
+}
+
+// LookupAdminPoliciesByPod returns names of all admin policies whose subject
+// selects a given pod.
+func (pc *PolicyCache) LookupAdminPoliciesByPod(pod podmodel.ID) (policies []string) {
+	policies = []string{}
+	for _, name := range pc.ListAllAdminPolicies() {
+		_, policy := pc.configuredAdminPolicies.LookupPolicy(name)
+		if policy.GetSubject() == nil {
+			continue
+		}
+		for _, podID := range pc.LookupPodsByAdminSelector(policy.Subject.Namespaces, policy.Subject.Pods) {
+			if podID == pod {
+				policies = append(policies, name)
+				break
+			}
+		}
+	}
+	return policies
+}
+
+// LookupAdminPoliciesByPeerPod returns names of all admin policies with a rule
+// whose peer selects the given pod (either by the namespace or by the pod
+// selector). Candidates are obtained from the index by the pod labels and
+// the labels of the pod namespace.
+func (pc *PolicyCache) LookupAdminPoliciesByPeerPod(pod *podmodel.Pod) (policies []string) {
+	found, ns := pc.LookupNamespace(nsmodel.ID(pod.Namespace))
+	if !found {
+		// namespace not yet known, evaluated as a namespace without labels
+		ns = &nsmodel.Namespace{Name: pod.Namespace}
+	}
+	candidates := pc.adminPolicyCandidatesByNamespace(ns)
+	candidates = append(candidates, pc.configuredAdminPolicies.LookupPolicyByPeerAnyPod()...)
+	for _, label := range pod.Label {
+		candidates = append(candidates, pc.configuredAdminPolicies.LookupPolicyByPeerPodLabel(label.Key+"/"+label.Value)...)
+	}
+	labels := podLabels(pod)
+	return pc.verifyAdminPolicies(candidates, func(peer *adminpolicymodel.AdminPolicy_Peer) bool {
+		if peer.Namespaces != nil && namespaceSelected(adminToPolicyLabelSelector(peer.Namespaces), ns) {
+			return true
+		}
+		return peer.Pods != nil &&
+			namespaceSelected(adminToPolicyLabelSelector(peer.Pods.Namespaces), ns) &&
+			selectorMatches(adminToPolicyLabelSelector(peer.Pods.Pods), labels)
+	})
+}
+
+// LookupAdminPoliciesByPeerNamespace returns names of all admin policies with
+// a rule whose peer selects pods of the given namespace (either all of them
+// or by the pod selector).
+func (pc *PolicyCache) LookupAdminPoliciesByPeerNamespace(ns *nsmodel.Namespace) (policies []string) {
+	candidates := pc.adminPolicyCandidatesByNamespace(ns)
+	return pc.verifyAdminPolicies(candidates, func(peer *adminpolicymodel.AdminPolicy_Peer) bool {
+		if peer.Namespaces != nil && namespaceSelected(adminToPolicyLabelSelector(peer.Namespaces), ns) {
+			return true
+		}
+		return peer.Pods != nil && namespaceSelected(adminToPolicyLabelSelector(peer.Pods.Namespaces), ns)
+	})
+}
+
+// LookupAdminPoliciesBySubjectNamespace returns names of all admin policies
+// whose subject selects pods of the given namespace (either all of them or by
+// the pod selector).
+func (pc *PolicyCache) LookupAdminPoliciesBySubjectNamespace(ns *nsmodel.Namespace) (policies []string) {
+	candidates := pc.configuredAdminPolicies.LookupPolicyBySubjectAnyNamespace()
+	for _, label := range ns.GetLabel() {
+		candidates = append(candidates, pc.configuredAdminPolicies.LookupPolicyBySubjectNSLabel(label.Key+"/"+label.Value)...)
+	}
+	policies = []string{}
+	for _, candidate := range utils.RemoveDuplicates(candidates) {
+		found, policy := pc.configuredAdminPolicies.LookupPolicy(candidate)
+		if !found || policy.GetSubject() == nil {
+			continue
+		}
+		subject := policy.Subject
+		if (subject.Namespaces != nil && namespaceSelected(adminToPolicyLabelSelector(subject.Namespaces), ns)) ||
+			(subject.Pods != nil && namespaceSelected(adminToPolicyLabelSelector(subject.Pods.Namespaces), ns)) {
+			policies = append(policies, candidate)
+		}
+	}
+	sort.Strings(policies)
+	return policies
+}
+
+// adminPolicyCandidatesByNamespace returns names of admin policies with a peer
+// namespace selector that may select the given namespace.
+func (pc *PolicyCache) adminPolicyCandidatesByNamespace(ns *nsmodel.Namespace) (candidates []string) {
+	candidates = pc.configuredAdminPolicies.LookupPolicyByPeerAnyNamespace()
+	for _, label := range ns.GetLabel() {
+		candidates = append(candidates, pc.configuredAdminPolicies.LookupPolicyByPeerNSLabel(label.Key+"/"+label.Value)...)
+	}
+	return candidates
+}
+
+// ListAllAdminPolicies returns names of all admin policies (sorted).
+func (pc *PolicyCache) ListAllAdminPolicies() (policies []string) {
+	policies = append([]string{}, pc.configuredAdminPolicies.ListAll()...)
+	sort.Strings(policies)
+	return policies
+}
+
 // LookupNamespace returns data of a given namespace.
 func (pc *PolicyCache) LookupNamespace(namespace nsmodel.ID) (found bool, data *nsmodel.Namespace) {
 	found, data = pc.configuredNamespaces.LookupNamespace(namespace.String())
@@ -232,3 +406,71 @@ func (pc *PolicyCache) ListAllNamespaces() (namespaces []nsmodel.ID) {
 
 	return namespaces
 }
+
+// LookupHostPolicy returns data of a given host policy.
+func (pc *PolicyCache) LookupHostPolicy(name string) (found bool, data *hostpolicymodel.HostPolicy) {
+	data, found = pc.configuredHostPolicies[name]
+	return found, data
+}
+
+// LookupHostPoliciesByNode returns names of all host policies whose node
+// selector selects a given node (sorted).
+func (pc *PolicyCache) LookupHostPoliciesByNode(node string) (policies []string) {
+	policies = []string{}
+	var nodeLabels map[string]string
+	if nodeData, found := pc.configuredNodes[node]; found {
+		nodeLabels = nodeData.Labels
+	}
+	for _, name := range pc.ListAllHostPolicies() {
+		selected := true
+		for key, value := range pc.configuredHostPolicies[name].NodeSelector {
+			if nodeValue, hasLabel := nodeLabels[key]; !hasLabel || nodeValue != value {
+				selected = false
+				break
+			}
+		}
+		if selected {
+			policies = append(policies, name)
+		}
+	}
+	return policies
+}
+
+// ListAllHostPolicies returns names of all host policies (sorted).
+func (pc *PolicyCache) ListAllHostPolicies() (policies []string) {
+	policies = []string{}
+	for name := range pc.configuredHostPolicies {
+		policies = append(policies, name)
+	}
+	sort.Strings(policies)
+	return policies
+}
+
+// LookupNode returns data of a given K8s node.
+func (pc *PolicyCache) LookupNode(name string) (found bool, data *nodemodel.Node) {
+	data, found = pc.configuredNodes[name]
+	return found, data
+}
+
+// adminToPolicyLabelSelector converts label selector of an admin policy into
+// the label selector of a namespaced policy, so that the same lookup methods
+// can be used.
+func adminToPolicyLabelSelector(selector *adminpolicymodel.AdminPolicy_LabelSelector) *policymodel.Policy_LabelSelector {
+	converted := &policymodel.Policy_LabelSelector{}
+	if selector == nil {
+		return converted
+	}
+	for _, label := range selector.MatchLabel {
+		converted.MatchLabel = append(converted.MatchLabel,
+			&policymodel.Policy_Label{Key: label.Key, Value: label.Value})
+	}
+	for _, expr := range selector.MatchExpression {
+		converted.MatchExpression = append(converted.MatchExpression,
+			&policymodel.Policy_LabelSelector_LabelExpression{
+				Key:      expr.Key,
+				Operator: policymodel.Policy_LabelSelector_LabelExpression_Operator(expr.Operator),
+				Value:    expr.Value,
+			})
+	}
+	return converted
+}
@@ -17,7 +17,10 @@ package cache
 import (
 	"testing"
 
+	hostpolicymodel "github.com/contiv/vpp/plugins/crd/handler/hostpolicy/model"
+	adminpolicymodel "github.com/contiv/vpp/plugins/ksr/model/adminpolicy"
 	"github.com/contiv/vpp/plugins/ksr/model/namespace"
+	nodemodel "github.com/contiv/vpp/plugins/ksr/model/node"
 	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
 	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
 	"github.com/contiv/vpp/plugins/policy/cache/testdata"
@@ -360,6 +363,120 @@ func TestLookupPoliciesByPod(t *testing.T) {
 	gomega.Expect(expectParam).To(gomega.BeEmpty())
 }
 
+func TestLookupPoliciesByPeer(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestLookupPoliciesByPeer")
+
+	// Create an instance of PolicyCache
+	pc := &PolicyCache{
+		Deps: Deps{
+			Log: logger,
+		},
+	}
+	pc.Init()
+
+	monitoring := &namespace.Namespace{
+		Name:  "monitoring",
+		Label: []*namespace.Namespace_Label{{Key: "team", Value: "monitoring"}},
+	}
+	kubeSystem := &namespace.Namespace{Name: "kube-system"}
+	pc.configuredNamespaces.RegisterNamespace(monitoring.Name, monitoring)
+	pc.configuredNamespaces.RegisterNamespace(kubeSystem.Name, kubeSystem)
+
+	// allows access to db from frontend pods (except for canary) and from monitoring
+	dbAccess := &policymodel.Policy{
+		Name:      "db-access",
+		Namespace: "default",
+		Pods: &policymodel.Policy_LabelSelector{
+			MatchLabel: []*policymodel.Policy_Label{{Key: "app", Value: "db"}},
+		},
+		IngressRule: []*policymodel.Policy_IngressRule{{
+			From: []*policymodel.Policy_Peer{
+				{
+					Pods: &policymodel.Policy_LabelSelector{
+						MatchLabel: []*policymodel.Policy_Label{{Key: "role", Value: "frontend"}},
+						MatchExpression: []*policymodel.Policy_LabelSelector_LabelExpression{{
+							Key:      "track",
+							Operator: policymodel.Policy_LabelSelector_LabelExpression_NOT_IN,
+							Value:    []string{"canary"},
+						}},
+					},
+				},
+				{
+					Namespaces: &policymodel.Policy_LabelSelector{
+						MatchLabel: []*policymodel.Policy_Label{{Key: "team", Value: "monitoring"}},
+					},
+				},
+			},
+		}},
+	}
+	// allows egress to all pods of the namespace and to all namespaces
+	allowAll := &policymodel.Policy{
+		Name:      "allow-all",
+		Namespace: "default",
+		Pods:      &policymodel.Policy_LabelSelector{},
+		EgressRule: []*policymodel.Policy_EgressRule{{
+			To: []*policymodel.Policy_Peer{
+				{Pods: &policymodel.Policy_LabelSelector{}},
+				{Namespaces: &policymodel.Policy_LabelSelector{}},
+			},
+		}},
+	}
+	pc.configuredPolicies.RegisterPolicy(policymodel.GetID(dbAccess).String(), dbAccess)
+	pc.configuredPolicies.RegisterPolicy(policymodel.GetID(allowAll).String(), allowAll)
+
+	frontend := &podmodel.Pod{
+		Name:      "frontend",
+		Namespace: "default",
+		Label:     []*podmodel.Pod_Label{{Key: "role", Value: "frontend"}},
+	}
+	canary := &podmodel.Pod{
+		Name:      "canary",
+		Namespace: "default",
+		Label: []*podmodel.Pod_Label{
+			{Key: "role", Value: "frontend"},
+			{Key: "track", Value: "canary"},
+		},
+	}
+	db := &podmodel.Pod{
+		Name:      "db",
+		Namespace: "default",
+		Label:     []*podmodel.Pod_Label{{Key: "app", Value: "db"}},
+	}
+	other := &podmodel.Pod{
+		Name:      "frontend",
+		Namespace: "other",
+		Label:     []*podmodel.Pod_Label{{Key: "role", Value: "frontend"}},
+	}
+	for _, pod := range []*podmodel.Pod{frontend, canary, db, other} {
+		pc.configuredPods.RegisterPod(podmodel.GetID(pod).String(), pod)
+	}
+
+	// lookup by peer pod selectors
+	gomega.Expect(pc.LookupPoliciesByPeerPod(frontend)).To(gomega.Equal(
+		[]policymodel.ID{policymodel.GetID(allowAll), policymodel.GetID(dbAccess)}))
+	gomega.Expect(pc.LookupPoliciesByPeerPod(canary)).To(gomega.Equal(
+		[]policymodel.ID{policymodel.GetID(allowAll)}))
+	gomega.Expect(pc.LookupPoliciesByPeerPod(other)).To(gomega.BeEmpty())
+
+	// lookup by peer namespace selectors
+	gomega.Expect(pc.LookupPoliciesByPeerNamespace(monitoring)).To(gomega.Equal(
+		[]policymodel.ID{policymodel.GetID(allowAll), policymodel.GetID(dbAccess)}))
+	gomega.Expect(pc.LookupPoliciesByPeerNamespace(kubeSystem)).To(gomega.BeEmpty())
+	gomega.Expect(pc.LookupPoliciesByPeerNamespace(&namespace.Namespace{Name: "other"})).To(gomega.Equal(
+		[]policymodel.ID{policymodel.GetID(allowAll)}))
+
+	// lookup by the subject
+	gomega.Expect(pc.LookupPoliciesByPod(podmodel.GetID(db))).To(gomega.Equal(
+		[]policymodel.ID{policymodel.GetID(allowAll), policymodel.GetID(dbAccess)}))
+	gomega.Expect(pc.LookupPoliciesByPod(podmodel.GetID(frontend))).To(gomega.Equal(
+		[]policymodel.ID{policymodel.GetID(allowAll)}))
+	gomega.Expect(pc.LookupPoliciesByPod(podmodel.GetID(other))).To(gomega.BeEmpty())
+}
+
 func TestLookupNamespace(t *testing.T) {
 	gomega.RegisterTestingT(t)
 
@@ -616,3 +733,122 @@ func TestResync(t *testing.T) {
 	resyncEv, _ := datasnc.ResyncEvent(keyPrefixes...)
 	gomega.Expect(pc.Resync(resyncEv.KubeState)).To(gomega.BeNil())
 }
+
+func TestLookupHostPoliciesByNode(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestLookupHostPoliciesByNode")
+
+	// Create an instance of PolicyCache
+	pc := &PolicyCache{
+		Deps: Deps{
+			Log: logger,
+		},
+	}
+
+	pc.Init()
+
+	pc.configuredNodes["worker1"] = &nodemodel.Node{
+		Name:   "worker1",
+		Labels: map[string]string{"role": "worker", "zone": "a"},
+	}
+	pc.configuredNodes["master"] = &nodemodel.Node{
+		Name:   "master",
+		Labels: map[string]string{"role": "master"},
+	}
+	pc.configuredHostPolicies["workers"] = &hostpolicymodel.HostPolicy{
+		Name:         "workers",
+		NodeSelector: map[string]string{"role": "worker"},
+	}
+	pc.configuredHostPolicies["zone-a-workers"] = &hostpolicymodel.HostPolicy{
+		Name:         "zone-a-workers",
+		NodeSelector: map[string]string{"role": "worker", "zone": "a"},
+	}
+	pc.configuredHostPolicies["all"] = &hostpolicymodel.HostPolicy{
+		Name: "all",
+	}
+
+	gomega.Expect(pc.ListAllHostPolicies()).To(gomega.Equal([]string{"all", "workers", "zone-a-workers"}))
+	gomega.Expect(pc.LookupHostPoliciesByNode("worker1")).To(gomega.Equal([]string{"all", "workers", "zone-a-workers"}))
+	gomega.Expect(pc.LookupHostPoliciesByNode("master")).To(gomega.Equal([]string{"all"}))
+	// node not yet reflected - selected only by policies without node selector
+	gomega.Expect(pc.LookupHostPoliciesByNode("worker2")).To(gomega.Equal([]string{"all"}))
+
+	found, policy := pc.LookupHostPolicy("workers")
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(policy.NodeSelector).To(gomega.HaveKeyWithValue("role", "worker"))
+	found, _ = pc.LookupNode("worker2")
+	gomega.Expect(found).To(gomega.BeFalse())
+}
+
+func TestLookupAdminPoliciesBySubjectNamespace(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestLookupAdminPoliciesBySubjectNamespace")
+
+	// Create an instance of PolicyCache
+	pc := &PolicyCache{
+		Deps: Deps{
+			Log: logger,
+		},
+	}
+
+	pc.Init()
+
+	teamSelector := func(team string) *adminpolicymodel.AdminPolicy_LabelSelector {
+		return &adminpolicymodel.AdminPolicy_LabelSelector{
+			MatchLabel: []*adminpolicymodel.AdminPolicy_Label{{Key: "team", Value: team}},
+		}
+	}
+	// subject selected by namespaces
+	pc.configuredAdminPolicies.RegisterPolicy("isolate-red", &adminpolicymodel.AdminPolicy{
+		Name:    "isolate-red",
+		Subject: &adminpolicymodel.AdminPolicy_Subject{Namespaces: teamSelector("red")},
+	})
+	// subject selected by pods inside selected namespaces
+	pc.configuredAdminPolicies.RegisterPolicy("isolate-blue-db", &adminpolicymodel.AdminPolicy{
+		Name: "isolate-blue-db",
+		Subject: &adminpolicymodel.AdminPolicy_Subject{
+			Pods: &adminpolicymodel.AdminPolicy_PodSelector{
+				Namespaces: teamSelector("blue"),
+				Pods: &adminpolicymodel.AdminPolicy_LabelSelector{
+					MatchLabel: []*adminpolicymodel.AdminPolicy_Label{{Key: "app", Value: "db"}},
+				},
+			},
+		},
+	})
+	// subject selected by pods from any namespace
+	pc.configuredAdminPolicies.RegisterPolicy("deny-all", &adminpolicymodel.AdminPolicy{
+		Name: "deny-all",
+		Subject: &adminpolicymodel.AdminPolicy_Subject{
+			Pods: &adminpolicymodel.AdminPolicy_PodSelector{},
+		},
+	})
+	// namespace selected only as a peer
+	pc.configuredAdminPolicies.RegisterPolicy("allow-from-red", &adminpolicymodel.AdminPolicy{
+		Name: "allow-from-red",
+		IngressRule: []*adminpolicymodel.AdminPolicy_Rule{{
+			Peers: []*adminpolicymodel.AdminPolicy_Peer{{Namespaces: teamSelector("red")}},
+		}},
+	})
+
+	teamNamespace := func(name, team string) *namespace.Namespace {
+		return &namespace.Namespace{
+			Name:  name,
+			Label: []*namespace.Namespace_Label{{Key: "team", Value: team}},
+		}
+	}
+	gomega.Expect(pc.LookupAdminPoliciesBySubjectNamespace(teamNamespace("ns1", "red"))).To(
+		gomega.Equal([]string{"deny-all", "isolate-red"}))
+	gomega.Expect(pc.LookupAdminPoliciesBySubjectNamespace(teamNamespace("ns1", "blue"))).To(
+		gomega.Equal([]string{"deny-all", "isolate-blue-db"}))
+	gomega.Expect(pc.LookupAdminPoliciesBySubjectNamespace(teamNamespace("ns1", "green"))).To(
+		gomega.Equal([]string{"deny-all"}))
+	// empty namespace selector does not select kube-system
+	gomega.Expect(pc.LookupAdminPoliciesBySubjectNamespace(&namespace.Namespace{Name: "kube-system"})).To(
+		gomega.BeEmpty())
+}
@@ -18,7 +18,10 @@ package cache
 
 import (
 	controller "github.com/contiv/vpp/plugins/controller/api"
+	hostpolicymodel "github.com/contiv/vpp/plugins/crd/handler/hostpolicy/model"
+	adminpolicymodel "github.com/contiv/vpp/plugins/ksr/model/adminpolicy"
 	namespacemodel "github.com/contiv/vpp/plugins/ksr/model/namespace"
+	nodemodel "github.com/contiv/vpp/plugins/ksr/model/node"
 	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
 	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
 )
@@ -142,6 +145,111 @@ func (pc *PolicyCache) changePropagateEvent(kubeStateChange *controller.KubeStat
 				}
 			}
 		}
+
+	case adminpolicymodel.AdminPolicyKeyword:
+		if kubeStateChange.PrevValue == nil {
+			// add admin policy
+			policy := kubeStateChange.NewValue.(*adminpolicymodel.AdminPolicy)
+			pc.configuredAdminPolicies.RegisterPolicy(policy.Name, policy)
+
+			for _, watcher := range pc.watchers {
+				if err := watcher.AddAdminPolicy(policy); err != nil {
+					return err
+				}
+			}
+		} else if kubeStateChange.NewValue == nil {
+			// delete admin policy
+			oldPolicy := kubeStateChange.PrevValue.(*adminpolicymodel.AdminPolicy)
+			pc.configuredAdminPolicies.UnregisterPolicy(oldPolicy.Name)
+
+			for _, watcher := range pc.watchers {
+				if err := watcher.DelAdminPolicy(oldPolicy); err != nil {
+					return err
+				}
+			}
+		} else {
+			// update admin policy
+			oldPolicy := kubeStateChange.PrevValue.(*adminpolicymodel.AdminPolicy)
+			newPolicy := kubeStateChange.NewValue.(*adminpolicymodel.AdminPolicy)
+			pc.configuredAdminPolicies.UnregisterPolicy(oldPolicy.Name)
+			pc.configuredAdminPolicies.RegisterPolicy(newPolicy.Name, newPolicy)
+
+			for _, watcher := range pc.watchers {
+				if err := watcher.UpdateAdminPolicy(oldPolicy, newPolicy); err != nil {
+					return err
+				}
+			}
+		}
+
+	case hostpolicymodel.Keyword:
+		if kubeStateChange.PrevValue == nil {
+			// add host policy
+			policy := kubeStateChange.NewValue.(*hostpolicymodel.HostPolicy)
+			pc.configuredHostPolicies[policy.Name] = policy
+
+			for _, watcher := range pc.watchers {
+				if err := watcher.AddHostPolicy(policy); err != nil {
+					return err
+				}
+			}
+		} else if kubeStateChange.NewValue == nil {
+			// delete host policy
+			oldPolicy := kubeStateChange.PrevValue.(*hostpolicymodel.HostPolicy)
+			delete(pc.configuredHostPolicies, oldPolicy.Name)
+
+			for _, watcher := range pc.watchers {
+				if err := watcher.DelHostPolicy(oldPolicy); err != nil {
+					return err
+				}
+			}
+		} else {
+			// update host policy
+			oldPolicy := kubeStateChange.PrevValue.(*hostpolicymodel.HostPolicy)
+			newPolicy := kubeStateChange.NewValue.(*hostpolicymodel.HostPolicy)
+			delete(pc.configuredHostPolicies, oldPolicy.Name)
+			pc.configuredHostPolicies[newPolicy.Name] = newPolicy
+
+			for _, watcher := range pc.watchers {
+				if err := watcher.UpdateHostPolicy(oldPolicy, newPolicy); err != nil {
+					return err
+				}
+			}
+		}
+
+	case nodemodel.NodeKeyword:
+		if kubeStateChange.PrevValue == nil {
+			// add node
+			node := kubeStateChange.NewValue.(*nodemodel.Node)
+			pc.configuredNodes[node.Name] = node
+
+			for _, watcher := range pc.watchers {
+				if err := watcher.AddNode(node); err != nil {
+					return err
+				}
+			}
+		} else if kubeStateChange.NewValue == nil {
+			// delete node
+			oldNode := kubeStateChange.PrevValue.(*nodemodel.Node)
+			delete(pc.configuredNodes, oldNode.Name)
+
+			for _, watcher := range pc.watchers {
+				if err := watcher.DelNode(oldNode); err != nil {
+					return err
+				}
+			}
+		} else {
+			// update node
+			oldNode := kubeStateChange.PrevValue.(*nodemodel.Node)
+			newNode := kubeStateChange.NewValue.(*nodemodel.Node)
+			delete(pc.configuredNodes, oldNode.Name)
+			pc.configuredNodes[newNode.Name] = newNode
+
+			for _, watcher := range pc.watchers {
+				if err := watcher.UpdateNode(oldNode, newNode); err != nil {
+					return err
+				}
+			}
+		}
 	}
 
 	return nil
@@ -18,24 +18,33 @@ package cache
 
 import (
 	controller "github.com/contiv/vpp/plugins/controller/api"
+	hostpolicymodel "github.com/contiv/vpp/plugins/crd/handler/hostpolicy/model"
+	adminpolicymodel "github.com/contiv/vpp/plugins/ksr/model/adminpolicy"
 	namespacemodel "github.com/contiv/vpp/plugins/ksr/model/namespace"
+	nodemodel "github.com/contiv/vpp/plugins/ksr/model/node"
 	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
 	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
 )
 
 // DataResyncEvent wraps an entire state of K8s that should be reflected into VPP.
 type DataResyncEvent struct {
-	Namespaces []*namespacemodel.Namespace
-	Pods       []*podmodel.Pod
-	Policies   []*policymodel.Policy
+	Namespaces    []*namespacemodel.Namespace
+	Pods          []*podmodel.Pod
+	Policies      []*policymodel.Policy
+	AdminPolicies []*adminpolicymodel.AdminPolicy
+	HostPolicies  []*hostpolicymodel.HostPolicy
+	Nodes         []*nodemodel.Node
 }
 
 // NewDataResyncEvent creates an empty instance of DataResyncEvent.
 func NewDataResyncEvent() *DataResyncEvent {
 	return &DataResyncEvent{
-		Namespaces: []*namespacemodel.Namespace{},
-		Pods:       []*podmodel.Pod{},
-		Policies:   []*policymodel.Policy{},
+		Namespaces:    []*namespacemodel.Namespace{},
+		Pods:          []*podmodel.Pod{},
+		Policies:      []*policymodel.Policy{},
+		AdminPolicies: []*adminpolicymodel.AdminPolicy{},
+		HostPolicies:  []*hostpolicymodel.HostPolicy{},
+		Nodes:         []*nodemodel.Node{},
 	}
 }
 
@@ -67,5 +76,26 @@ func (pc *PolicyCache) resyncParseEvent(kubeStateData controller.KubeStateData)
 		policyID := policymodel.GetID(policy).String()
 		pc.configuredPolicies.RegisterPolicy(policyID, policy)
 	}
+
+	// collect admin policies
+	for _, policyProto := range kubeStateData[adminpolicymodel.AdminPolicyKeyword] {
+		policy := policyProto.(*adminpolicymodel.AdminPolicy)
+		event.AdminPolicies = append(event.AdminPolicies, policy)
+		pc.configuredAdminPolicies.RegisterPolicy(policy.Name, policy)
+	}
+
+	// collect host policies
+	for _, policyProto := range kubeStateData[hostpolicymodel.Keyword] {
+		policy := policyProto.(*hostpolicymodel.HostPolicy)
+		event.HostPolicies = append(event.HostPolicies, policy)
+		pc.configuredHostPolicies[policy.Name] = policy
+	}
+
+	// collect nodes
+	for _, nodeProto := range kubeStateData[nodemodel.NodeKeyword] {
+		node := nodeProto.(*nodemodel.Node)
+		event.Nodes = append(event.Nodes, node)
+		pc.configuredNodes[node.Name] = node
+	}
 	return event
 }
@@ -0,0 +1,177 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"sort"
+
+	adminpolicymodel "github.com/contiv/vpp/plugins/ksr/model/adminpolicy"
+	nsmodel "github.com/contiv/vpp/plugins/ksr/model/namespace"
+	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
+	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
+	"github.com/contiv/vpp/plugins/policy/utils"
+)
+
+// kubeSystemNamespace is never selected by an empty namespace selector.
+const kubeSystemNamespace = "kube-system"
+
+// The functions below evaluate selectors against a single pod or namespace.
+// They are used to verify candidates returned by the policy index, which
+// is only able to narrow down the set of policies possibly selecting
+// the object, without having to evaluate all the policies.
+
+// verifyPolicies returns IDs (sorted) of the candidate policies selected
+// by the index for which <matches> returns true.
+func (pc *PolicyCache) verifyPolicies(candidates []string,
+	matches func(policy *policymodel.Policy) bool) (policyIDs []policymodel.ID) {
+
+	policyIDs = []policymodel.ID{}
+	for _, candidate := range utils.RemoveDuplicates(candidates) {
+		found, policy := pc.configuredPolicies.LookupPolicy(candidate)
+		if !found || !matches(policy) {
+			continue
+		}
+		policyIDs = append(policyIDs, policymodel.GetID(policy))
+	}
+	sort.Slice(policyIDs, func(i, j int) bool {
+		return policyIDs[i].String() < policyIDs[j].String()
+	})
+	return policyIDs
+}
+
+// verifyAdminPolicies returns names (sorted) of the candidate admin policies
+// selected by the index with at least one peer for which <matches> returns true.
+func (pc *PolicyCache) verifyAdminPolicies(candidates []string,
+	matches func(peer *adminpolicymodel.AdminPolicy_Peer) bool) (policies []string) {
+
+	policies = []string{}
+	for _, candidate := range utils.RemoveDuplicates(candidates) {
+		found, policy := pc.configuredAdminPolicies.LookupPolicy(candidate)
+		if !found {
+			continue
+		}
+		for _, peer := range adminPolicyPeers(policy) {
+			if matches(peer) {
+				policies = append(policies, candidate)
+				break
+			}
+		}
+	}
+	sort.Strings(policies)
+	return policies
+}
+
+// adminPolicyPeers returns peers of all ingress and egress rules of the admin policy.
+func adminPolicyPeers(policy *adminpolicymodel.AdminPolicy) (peers []*adminpolicymodel.AdminPolicy_Peer) {
+	for _, rule := range policy.IngressRule {
+		peers = append(peers, rule.Peers...)
+	}
+	for _, rule := range policy.EgressRule {
+		peers = append(peers, rule.Peers...)
+	}
+	return peers
+}
+
+// policyPeers returns peers of all ingress and egress rules of the policy.
+func policyPeers(policy *policymodel.Policy) (peers []*policymodel.Policy_Peer) {
+	for _, rule := range policy.IngressRule {
+		peers = append(peers, rule.From...)
+	}
+	for _, rule := range policy.EgressRule {
+		peers = append(peers, rule.To...)
+	}
+	return peers
+}
+
+// namespaceSelected returns true if the namespace selector of a policy peer
+// selects the given namespace. Just like in LookupPodsByNsLabelSelector,
+// an empty selector selects all namespaces except for kube-system.
+func namespaceSelected(selector *policymodel.Policy_LabelSelector, ns *nsmodel.Namespace) bool {
+	if len(selector.GetMatchLabel()) == 0 && len(selector.GetMatchExpression()) == 0 {
+		return ns.Name != kubeSystemNamespace
+	}
+	return selectorMatches(selector, namespaceLabels(ns))
+}
+
+// selectorMatches returns true if the label selector selects an object with
+// the given labels (values for every key). Match labels and expressions are
+// ANDed, an empty selector selects everything.
+func selectorMatches(selector *policymodel.Policy_LabelSelector, labels map[string][]string) bool {
+	for _, label := range selector.GetMatchLabel() {
+		if !containsValue(labels[label.Key], label.Value) {
+			return false
+		}
+	}
+	for _, expression := range selector.GetMatchExpression() {
+		values := labels[expression.Key]
+		switch expression.Operator {
+		case in:
+			if !containsAnyValue(expression.Value, values) {
+				return false
+			}
+		case notIn:
+			if containsAnyValue(expression.Value, values) {
+				return false
+			}
+		case exists:
+			if len(values) == 0 {
+				return false
+			}
+		case doesNotExist:
+			if len(values) > 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// podLabels returns labels of the pod as a map key -> values.
+func podLabels(pod *podmodel.Pod) map[string][]string {
+	labels := make(map[string][]string, len(pod.Label))
+	for _, label := range pod.Label {
+		labels[label.Key] = append(labels[label.Key], label.Value)
+	}
+	return labels
+}
+
+// namespaceLabels returns labels of the namespace as a map key -> values.
+func namespaceLabels(ns *nsmodel.Namespace) map[string][]string {
+	labels := make(map[string][]string, len(ns.GetLabel()))
+	for _, label := range ns.GetLabel() {
+		labels[label.Key] = append(labels[label.Key], label.Value)
+	}
+	return labels
+}
+
+// containsValue returns true if the value is in the list.
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAnyValue returns true if any of the values is in the list.
+func containsAnyValue(list []string, values []string) bool {
+	for _, value := range values {
+		if containsValue(list, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -26,6 +26,18 @@ const (
 	policyIngressLabelKey = "policyIngressLabelKey"
 	policyEgressLabelKey  = "policyEgressLabelKey"
 	policyPodNSLabelKey   = "policyPodNSLabelKey"
+
+	// policies with a pod selector without match labels (indexed by namespace)
+	policySubjectNamespaceKey = "policySubjectNamespaceKey"
+
+	// policies with rules referencing peers by pod or namespace selectors
+	policyPeerPodNSLabelKey   = "policyPeerPodNSLabelKey"
+	policyPeerPodNamespaceKey = "policyPeerPodNamespaceKey"
+	policyPeerNSLabelKey      = "policyPeerNSLabelKey"
+	policyPeerAnyNSKey        = "policyPeerAnyNSKey"
+
+	// anyNamespace is the only value of the policyPeerAnyNSKey index
+	anyNamespace = "*"
 )
 
 // ConfigIndex implements a cache for configured policies. Primary index is policyID.
@@ -75,6 +87,37 @@ func (ci *ConfigIndex) LookupPolicyByNSLabelSelector(policyNSLabelSelector strin
 	return ci.mapping.ListNames(policyPodNSLabelKey, policyNSLabelSelector)
 }
 
+// LookupPolicyBySubjectNamespace returns policies from the given namespace
+// whose pod selector does not contain any match label (i.e. selects pods
+// only by expressions or selects all pods of the namespace).
+func (ci *ConfigIndex) LookupPolicyBySubjectNamespace(namespace string) (policyIDs []string) {
+	return ci.mapping.ListNames(policySubjectNamespaceKey, namespace)
+}
+
+// LookupPolicyByPeerPodNSLabel performs lookup of policies with a peer pod
+// selector containing the match label given as namespace/key/value.
+func (ci *ConfigIndex) LookupPolicyByPeerPodNSLabel(peerPodNSLabelSelector string) (policyIDs []string) {
+	return ci.mapping.ListNames(policyPeerPodNSLabelKey, peerPodNSLabelSelector)
+}
+
+// LookupPolicyByPeerPodNamespace performs lookup of policies from the given
+// namespace with a peer pod selector without any match label.
+func (ci *ConfigIndex) LookupPolicyByPeerPodNamespace(namespace string) (policyIDs []string) {
+	return ci.mapping.ListNames(policyPeerPodNamespaceKey, namespace)
+}
+
+// LookupPolicyByPeerNSLabel performs lookup of policies with a peer namespace
+// selector containing the match label given as key/value.
+func (ci *ConfigIndex) LookupPolicyByPeerNSLabel(peerNSLabelSelector string) (policyIDs []string) {
+	return ci.mapping.ListNames(policyPeerNSLabelKey, peerNSLabelSelector)
+}
+
+// LookupPolicyByPeerAnyNamespace returns policies with a peer namespace
+// selector without any match label (may select any namespace).
+func (ci *ConfigIndex) LookupPolicyByPeerAnyNamespace() (policyIDs []string) {
+	return ci.mapping.ListNames(policyPeerAnyNSKey, anyNamespace)
+}
+
 // ListAll returns all registered names in the mapping.
 func (ci *ConfigIndex) ListAll() (policyIDs []string) {
 	return ci.mapping.ListAllNames()
@@ -87,7 +130,7 @@ func IndexFunction(data interface{}) map[string][]string {
 	policyPodNSLabels := []string{}
 
 	if config, ok := data.(*policymodel.Policy); ok && config != nil {
-		for _, v := range config.Pods.MatchLabel {
+		for _, v := range config.GetPods().GetMatchLabel() {
 			labelSelector := v.Key + "/" + v.Value
 			nsLabelSelector := config.Namespace + "/" + labelSelector
 			policyPodLabels = append(policyPodLabels, labelSelector)
@@ -95,7 +138,37 @@ func IndexFunction(data interface{}) map[string][]string {
 		}
 		res[policyPodLabelKey] = policyPodLabels
 		res[policyPodNSLabelKey] = policyPodNSLabels
+		if len(config.GetPods().GetMatchLabel()) == 0 {
+			res[policySubjectNamespaceKey] = []string{config.Namespace}
+		}
 
+		// index peers of all rules
+		peers := []*policymodel.Policy_Peer{}
+		for _, rule := range config.IngressRule {
+			peers = append(peers, rule.From...)
+		}
+		for _, rule := range config.EgressRule {
+			peers = append(peers, rule.To...)
+		}
+		for _, peer := range peers {
+			if peer.Pods != nil {
+				if len(peer.Pods.MatchLabel) == 0 {
+					res[policyPeerPodNamespaceKey] = []string{config.Namespace}
+				}
+				for _, v := range peer.Pods.MatchLabel {
+					res[policyPeerPodNSLabelKey] = append(res[policyPeerPodNSLabelKey],
+						config.Namespace+"/"+v.Key+"/"+v.Value)
+				}
+			}
+			if peer.Namespaces != nil {
+				if len(peer.Namespaces.MatchLabel) == 0 {
+					res[policyPeerAnyNSKey] = []string{anyNamespace}
+				}
+				for _, v := range peer.Namespaces.MatchLabel {
+					res[policyPeerNSLabelKey] = append(res[policyPeerNSLabelKey], v.Key+"/"+v.Value)
+				}
+			}
+		}
 	}
 
 	return res
@@ -212,3 +212,92 @@ func TestSecondaryIndexLookup(t *testing.T) {
 	gomega.Expect(labelMatch).To(gomega.ContainElement(policyIDfive))
 
 }
+
+func TestPeerIndexLookup(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	idx := NewConfigIndex(logrus.DefaultLogger(), "title")
+	gomega.Expect(idx).NotTo(gomega.BeNil())
+
+	const (
+		policyIDone   = "default/allow-from-frontend"
+		policyIDtwo   = "default/allow-to-monitoring"
+		policyIDthree = "other/deny-all"
+	)
+
+	// selects pods by label, allows ingress from frontend pods and any namespace
+	policyDataOne := &policymodel.Policy{
+		Name:      "allow-from-frontend",
+		Namespace: "default",
+		Pods: &policymodel.Policy_LabelSelector{
+			MatchLabel: []*policymodel.Policy_Label{{Key: "role", Value: "db"}},
+		},
+		IngressRule: []*policymodel.Policy_IngressRule{
+			{
+				From: []*policymodel.Policy_Peer{
+					{
+						Pods: &policymodel.Policy_LabelSelector{
+							MatchLabel: []*policymodel.Policy_Label{{Key: "role", Value: "frontend"}},
+						},
+					},
+					{
+						Namespaces: &policymodel.Policy_LabelSelector{},
+					},
+				},
+			},
+		},
+	}
+
+	// selects all pods of the namespace, allows egress to labeled namespaces
+	// and to pods selected by an expression
+	policyDataTwo := &policymodel.Policy{
+		Name:      "allow-to-monitoring",
+		Namespace: "default",
+		Pods:      &policymodel.Policy_LabelSelector{},
+		EgressRule: []*policymodel.Policy_EgressRule{
+			{
+				To: []*policymodel.Policy_Peer{
+					{
+						Namespaces: &policymodel.Policy_LabelSelector{
+							MatchLabel: []*policymodel.Policy_Label{{Key: "team", Value: "monitoring"}},
+						},
+					},
+					{
+						Pods: &policymodel.Policy_LabelSelector{
+							MatchExpression: []*policymodel.Policy_LabelSelector_LabelExpression{
+								{Key: "app", Operator: policymodel.Policy_LabelSelector_LabelExpression_EXISTS},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// no peers
+	policyDataThree := &policymodel.Policy{
+		Name:       "deny-all",
+		Namespace:  "other",
+		PolicyType: policymodel.Policy_INGRESS,
+	}
+
+	idx.RegisterPolicy(policyIDone, policyDataOne)
+	idx.RegisterPolicy(policyIDtwo, policyDataTwo)
+	idx.RegisterPolicy(policyIDthree, policyDataThree)
+
+	gomega.Expect(idx.LookupPolicyBySubjectNamespace("default")).To(gomega.BeEquivalentTo([]string{policyIDtwo}))
+	gomega.Expect(idx.LookupPolicyBySubjectNamespace("other")).To(gomega.BeEquivalentTo([]string{policyIDthree}))
+
+	gomega.Expect(idx.LookupPolicyByPeerPodNSLabel("default/role/frontend")).To(gomega.BeEquivalentTo([]string{policyIDone}))
+	gomega.Expect(idx.LookupPolicyByPeerPodNSLabel("other/role/frontend")).To(gomega.BeEmpty())
+	gomega.Expect(idx.LookupPolicyByPeerPodNamespace("default")).To(gomega.BeEquivalentTo([]string{policyIDtwo}))
+	gomega.Expect(idx.LookupPolicyByPeerPodNamespace("other")).To(gomega.BeEmpty())
+
+	gomega.Expect(idx.LookupPolicyByPeerNSLabel("team/monitoring")).To(gomega.BeEquivalentTo([]string{policyIDtwo}))
+	gomega.Expect(idx.LookupPolicyByPeerAnyNamespace()).To(gomega.BeEquivalentTo([]string{policyIDone}))
+
+	// peers are no longer referenced after the policy is removed
+	idx.UnregisterPolicy(policyIDone)
+	gomega.Expect(idx.LookupPolicyByPeerPodNSLabel("default/role/frontend")).To(gomega.BeEmpty())
+	gomega.Expect(idx.LookupPolicyByPeerAnyNamespace()).To(gomega.BeEmpty())
+}
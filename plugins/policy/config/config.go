@@ -0,0 +1,80 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+const (
+	// by default rule counters are read every 10 seconds
+	defaultRuleCountersInterval = 10
+
+	// by default at most 10 hits of deny rules are logged per interval
+	defaultDenyLogMaxHits = 10
+
+	// by default 100 most recent hits of deny rules are available over REST
+	defaultDenyLogSize = 100
+
+	// by default addresses of DNS names used in policies are valid between 30 seconds
+	// and 5 minutes, depending on the TTL of the DNS records
+	defaultFQDNMinTTL = 30
+	defaultFQDNMaxTTL = 300
+)
+
+var (
+	// TCP ports of the node left open by host policies by default - etcd (incl. Contiv ETCD),
+	// kube-apiserver, Contiv agent REST API, kubelet and BGP
+	defaultHostAllowedTCPPorts = []uint16{179, 2379, 2380, 6443, 9999, 10250, 12379, 32379}
+
+	// UDP ports of the node left open by host policies by default - VXLAN
+	defaultHostAllowedUDPPorts = []uint16{4789}
+)
+
+// Config holds the Policy configuration.
+type Config struct {
+	// interval (in seconds) at which hit counters of the policy rules are read from the data plane,
+	// 0 disables the periodic collection (counters are then read only on REST requests)
+	RuleCountersInterval uint32 `json:"ruleCountersInterval"`
+
+	// maximum number of deny rules hit since the last collection that are logged (and kept
+	// for the REST API), the rules that denied the most packets first, 0 disables the logging
+	DenyLogMaxHits uint32 `json:"denyLogMaxHits"`
+
+	// number of the most recent deny rule hits kept for the REST API
+	DenyLogSize uint32 `json:"denyLogSize"`
+
+	// nameservers used to resolve DNS names referenced by policies,
+	// nameservers from /etc/resolv.conf are used if not set
+	FQDNNameservers []string `json:"fqdnNameservers"`
+
+	// lower and upper bound (in seconds) for the TTL of the resolved DNS records
+	FQDNMinTTL uint32 `json:"fqdnMinTTL"`
+	FQDNMaxTTL uint32 `json:"fqdnMaxTTL"`
+
+	// TCP and UDP ports of the node that are never blocked by host policies
+	// (to keep the control traffic of the cluster running)
+	HostAllowedTCPPorts []uint16 `json:"hostAllowedTCPPorts"`
+	HostAllowedUDPPorts []uint16 `json:"hostAllowedUDPPorts"`
+}
+
+// DefaultConfig returns configuration for policy plugin with default values.
+func DefaultConfig() *Config {
+	return &Config{
+		RuleCountersInterval: defaultRuleCountersInterval,
+		DenyLogMaxHits:       defaultDenyLogMaxHits,
+		DenyLogSize:          defaultDenyLogSize,
+		FQDNMinTTL:           defaultFQDNMinTTL,
+		FQDNMaxTTL:           defaultFQDNMaxTTL,
+		HostAllowedTCPPorts:  defaultHostAllowedTCPPorts,
+		HostAllowedUDPPorts:  defaultHostAllowedUDPPorts,
+	}
+}
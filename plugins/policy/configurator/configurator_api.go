@@ -51,6 +51,10 @@ type PolicyConfiguratorAPI interface {
 	// replace the existing one, otherwise pods not mentioned in the transaction
 	// are left unchanged.
 	NewTxn(resync bool) Txn
+
+	// GetPodPolicies returns IDs (sorted) of the policies currently applied
+	// for every configured pod.
+	GetPodPolicies() PodPolicies
 }
 
 // Txn defines the API of PolicyConfigurator transaction.
@@ -60,6 +64,11 @@ type Txn interface {
 	// The order of policies is not important (it is a set).
 	Configure(pod podmodel.ID, policies []*ContivPolicy) Txn
 
+	// ConfigureHost applies the set of host policies protecting this node.
+	// The existing host policies are replaced.
+	// Empty set of policies leaves the node not isolated.
+	ConfigureHost(policies []*ContivPolicy) Txn
+
 	// Commit proceeds with the reconfiguration.
 	Commit() error
 }
@@ -75,13 +84,31 @@ type Txn interface {
 // Traffic matched by a Contiv policy should by ALLOWED. Traffic not matched
 // by any policy from a **non-empty** set of policies assigned
 // to the source/destination pod should be DENIED.
+// Cluster-scoped admin network policies are also represented as ContivPolicy
+// (with Admin set to true). Their matches are evaluated in the given order before
+// all namespaced policies and each match carries explicit action. Admin policies
+// do not isolate pods.
+// Host policies protecting the node itself are represented as ContivPolicy
+// as well (with Host set to true). They contain only ingress matches
+// with IP blocks and ports.
 type ContivPolicy struct {
 	// ID should uniquely identify policy across all namespaces.
+	// Admin policies are cluster-scoped, i.e. with empty namespace.
 	ID policymodel.ID
 
 	// Type selects the rule types that the network policy relates to.
 	Type PolicyType
 
+	// Admin is true for cluster-scoped admin network policies.
+	Admin bool
+
+	// Host is true for cluster-scoped host policies.
+	Host bool
+
+	// Priority of an admin policy: policies with lower value are evaluated
+	// first. Not used for namespaced policies.
+	Priority int32
+
 	// Matches is an array of Match-es: predicates that select a subset of the
 	// traffic to be ALLOWED.
 	Matches []Match
@@ -96,6 +123,13 @@ func (cp ContivPolicy) String() string {
 			matches += ", "
 		}
 	}
+	if cp.Admin {
+		return fmt.Sprintf("ContivPolicy %s <Admin, Priority:%d, Type:%s, Matches:[%s]>",
+			cp.ID, cp.Priority, cp.Type, matches)
+	}
+	if cp.Host {
+		return fmt.Sprintf("ContivPolicy %s <Host, Matches:[%s]>", cp.ID, matches)
+	}
 	return fmt.Sprintf("ContivPolicy %s <Type:%s, Matches:[%s]>",
 		cp.ID, cp.Type, matches)
 }
@@ -105,6 +139,10 @@ type Match struct {
 	// Type selects the direction of the traffic.
 	Type MatchType
 
+	// Action to take for the matched traffic. Always ActionAllow
+	// for namespaced policies.
+	Action MatchAction
+
 	// Layer 3: destinations (egress) / sources (ingress)
 	// If both arrays are nils, then this predicate matches all
 	// sources(ingress) / destinations(egress). Otherwise, this predicate
@@ -158,6 +196,10 @@ func (m Match) String() string {
 		}
 		ports += "]"
 	}
+	if m.Action != ActionAllow {
+		return fmt.Sprintf("<Type:%s, Action:%s, Pods:%s, Blocks:%s, Ports:%s>",
+			m.Type, m.Action, pods, blocks, ports)
+	}
 	return fmt.Sprintf("<Type:%s, Pods:%s, Blocks:%s, Ports:%s>",
 		m.Type, pods, blocks, ports)
 }
@@ -213,7 +255,35 @@ func (mt MatchType) String() string {
 	return "INVALID"
 }
 
-// ProtocolType is either TCP or UDP.
+// MatchAction is the action taken for the traffic selected by a Match.
+type MatchAction int
+
+const (
+	// ActionAllow allows the matched traffic.
+	ActionAllow MatchAction = iota
+
+	// ActionDeny blocks the matched traffic (admin policies only).
+	ActionDeny
+
+	// ActionPass skips the remaining admin policies and delegates the decision
+	// for the matched traffic to the namespaced policies (admin policies only).
+	ActionPass
+)
+
+// String converts MatchAction into a human-readable string.
+func (ma MatchAction) String() string {
+	switch ma {
+	case ActionAllow:
+		return "ALLOW"
+	case ActionDeny:
+		return "DENY"
+	case ActionPass:
+		return "PASS"
+	}
+	return "INVALID"
+}
+
+// ProtocolType is either TCP, UDP or SCTP.
 type ProtocolType int
 
 const (
@@ -222,6 +292,9 @@ const (
 
 	// UDP protocol.
 	UDP
+
+	// SCTP protocol.
+	SCTP
 )
 
 // String converts ProtocolType into a human-readable string.
@@ -231,15 +304,19 @@ func (pt ProtocolType) String() string {
 		return "TCP"
 	case UDP:
 		return "UDP"
+	case SCTP:
+		return "SCTP"
 	}
 	return "INVALID"
 }
 
-// Port represent a TCP or UDP port.
+// Port represent a TCP, UDP or SCTP port or a range of ports.
 // Number=0 represents all ports for a given protocol.
+// EndNumber, if greater than Number, makes the port a range <Number, EndNumber>.
 type Port struct {
-	Protocol ProtocolType
-	Number   uint16
+	Protocol  ProtocolType
+	Number    uint16
+	EndNumber uint16
 }
 
 // String return a human-readable string representation of the Port.
@@ -247,6 +324,9 @@ func (port Port) String() string {
 	if port.Number == 0 {
 		return port.Protocol.String() + ":ANY"
 	}
+	if port.EndNumber > port.Number {
+		return port.Protocol.String() + ":" + strconv.Itoa(int(port.Number)) + "-" + strconv.Itoa(int(port.EndNumber))
+	}
 	return port.Protocol.String() + ":" + strconv.Itoa(int(port.Number))
 }
 
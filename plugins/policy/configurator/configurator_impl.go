@@ -19,10 +19,12 @@ package configurator
 import (
 	"net"
 	"sort"
+	"strings"
 
 	"go.ligato.io/cn-infra/v2/logging"
 
 	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
+	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
 	"github.com/contiv/vpp/plugins/policy/cache"
 	"github.com/contiv/vpp/plugins/policy/renderer"
 	"github.com/contiv/vpp/plugins/policy/utils"
@@ -39,9 +41,10 @@ import (
 type PolicyConfigurator struct {
 	Deps
 
-	renderers         []renderer.PolicyRendererAPI
+	rendererGroups    []*rendererGroup
 	parallelRendering bool
 	podIPAddresses    PodIPAddresses
+	podPolicies       PodPolicies
 }
 
 // Deps lists dependencies of PolicyConfigurator.
@@ -65,15 +68,29 @@ type PolicyConfiguratorTxn struct {
 	configurator   *PolicyConfigurator
 	resync         bool
 	config         map[podmodel.ID]ContivPolicies // config to render
-	podIPAddresses PodIPAddresses
+	podIPAddresses PodIPAddresses                 // changed IP addresses (nil = removed)
+	podPolicies    PodPolicies                    // changed applied policies (nil = removed)
+	hostConfig     ContivPolicies                 // host policies to render
+	withHostConfig bool                           // true if ConfigureHost() was called
 }
 
+// rendererGroup is a group of registered renderers with the same restrictions
+// on matching of L4 ports. The same rules are generated for all the renderers
+// of the group.
+type rendererGroup struct {
+	portMatching renderer.PortMatchingAPI // nil if all the ports can be matched
+	renderers    []renderer.PolicyRendererAPI
+}
+
+// matchedProtocols lists protocols with L4 ports that renderers may be unable to match.
+var matchedProtocols = []renderer.ProtocolType{renderer.TCP, renderer.UDP, renderer.SCTP}
+
 // ContivPolicies is a list of policies that can be ordered by policy ID.
 type ContivPolicies []*ContivPolicy
 
 // ProcessedPolicySet stores configuration already generated for a given
 // set of policies. It is used only temporarily for a duration of the commit
-// for a performance optimization.
+// for a performance optimization (indexed by the policy set key).
 type ProcessedPolicySet struct {
 	policies ContivPolicies // ordered
 	ingress  *ContivRules
@@ -89,11 +106,15 @@ type ContivRules struct {
 // PodIPAddresses is a map used to remember IP address for each configured pod.
 type PodIPAddresses map[podmodel.ID]*net.IPNet
 
+// PodPolicies is a map used to remember policies applied for each configured pod.
+type PodPolicies map[podmodel.ID][]policymodel.ID
+
 // Init initializes policy configurator.
 func (pc *PolicyConfigurator) Init(parallelRendering bool) error {
-	pc.renderers = []renderer.PolicyRendererAPI{}
+	pc.rendererGroups = []*rendererGroup{}
 	pc.parallelRendering = parallelRendering
 	pc.podIPAddresses = make(PodIPAddresses)
+	pc.podPolicies = make(PodPolicies)
 	return nil
 }
 
@@ -101,11 +122,39 @@ func (pc *PolicyConfigurator) Init(parallelRendering bool) error {
 // The renderer will be receiving rules for all pods in this K8s node.
 // It is up to the render to possibly filter out rules for pods without
 // an inter-connection in the destination network stack.
-func (pc *PolicyConfigurator) RegisterRenderer(renderer renderer.PolicyRendererAPI) error {
-	pc.renderers = append(pc.renderers, renderer)
+// Renderers implementing PortMatchingAPI receive rules generated with their
+// own port matching restrictions.
+func (pc *PolicyConfigurator) RegisterRenderer(r renderer.PolicyRendererAPI) error {
+	portMatching, _ := r.(renderer.PortMatchingAPI)
+	for _, group := range pc.rendererGroups {
+		if samePortMatching(group.portMatching, portMatching) {
+			group.renderers = append(group.renderers, r)
+			return nil
+		}
+	}
+	pc.rendererGroups = append(pc.rendererGroups, &rendererGroup{
+		portMatching: portMatching,
+		renderers:    []renderer.PolicyRendererAPI{r},
+	})
 	return nil
 }
 
+// samePortMatching returns true if the given port matching restrictions are the same.
+func samePortMatching(pm1, pm2 renderer.PortMatchingAPI) bool {
+	for _, protocol := range matchedProtocols {
+		if canMatchPorts(pm1, protocol) != canMatchPorts(pm2, protocol) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetPodPolicies returns IDs (sorted) of the policies currently applied
+// for every configured pod.
+func (pc *PolicyConfigurator) GetPodPolicies() PodPolicies {
+	return pc.podPolicies.Copy()
+}
+
 // Close deallocates resource held by the configurator.
 func (pc *PolicyConfigurator) Close() error {
 	return nil
@@ -116,18 +165,17 @@ func (pc *PolicyConfigurator) Close() error {
 // completely replace the existing one, otherwise pods not mentioned in the
 // transaction are left unchanged.
 func (pc *PolicyConfigurator) NewTxn(resync bool) Txn {
-	txn := &PolicyConfiguratorTxn{
-		Log:          pc.Log,
-		configurator: pc,
-		resync:       resync,
-		config:       make(map[podmodel.ID]ContivPolicies),
-	}
-	if resync {
-		txn.podIPAddresses = make(PodIPAddresses)
-	} else {
-		txn.podIPAddresses = pc.podIPAddresses.Copy()
+	// Only the changes of pod IP addresses and policies are recorded by the transaction,
+	// so that the cost of a small transaction does not depend on the number
+	// of configured pods.
+	return &PolicyConfiguratorTxn{
+		Log:            pc.Log,
+		configurator:   pc,
+		resync:         resync,
+		config:         make(map[podmodel.ID]ContivPolicies),
+		podIPAddresses: make(PodIPAddresses),
+		podPolicies:    make(PodPolicies),
 	}
-	return txn
 }
 
 // Configure applies the set of policies for a given pod. The existing policies
@@ -141,22 +189,48 @@ func (pct *PolicyConfiguratorTxn) Configure(pod podmodel.ID, policies []*ContivP
 	return pct
 }
 
+// ConfigureHost applies the set of host policies protecting this node.
+// The existing host policies are replaced.
+func (pct *PolicyConfiguratorTxn) ConfigureHost(policies []*ContivPolicy) Txn {
+	pct.Log.WithFields(logging.Fields{
+		"policies": policies,
+	}).Debug("PolicyConfigurator ConfigureHost()")
+	pct.hostConfig = policies
+	pct.withHostConfig = true
+	return pct
+}
+
 // Commit proceeds with the reconfiguration.
 func (pct *PolicyConfiguratorTxn) Commit() error {
+	groups := pct.configurator.rendererGroups
+
 	// Remember processed sets of policies between iterations so that the same
-	// set will not be processed more than once.
-	processed := []ProcessedPolicySet{}
+	// set will not be processed more than once (for every group of renderers).
+	processed := make([]map[string]ProcessedPolicySet, len(groups))
+	for i := range groups {
+		processed[i] = make(map[string]ProcessedPolicySet)
+	}
 
-	// Transactions of all registered renderers.
-	rendererTxns := []renderer.Txn{}
+	// Transactions of all registered renderers (per group).
+	var rendererTxns [][]renderer.Txn
+	startRendererTxns := func() {
+		if rendererTxns == nil {
+			rendererTxns = make([][]renderer.Txn, len(groups))
+			for i, group := range groups {
+				for _, renderer := range group.renderers {
+					rendererTxns[i] = append(rendererTxns[i], renderer.NewTxn(pct.resync))
+				}
+			}
+		}
+	}
 
 	for pod, unorderedPolicies := range pct.config {
-		ingress := &ContivRules{}
-		egress := &ContivRules{}
+		var policies ContivPolicies
+		var policiesKey string
 		var delPodConfig bool
 
 		// Get target pod configuration.
-		podIPNet, hadIPAddr := pct.podIPAddresses[pod]
+		podIPNet, hadIPAddr := pct.getPodIPAddress(pod)
 		found, podData := pct.configurator.Cache.LookupPod(pod)
 
 		// Handle removed pod.
@@ -164,7 +238,8 @@ func (pct *PolicyConfiguratorTxn) Commit() error {
 			if hadIPAddr {
 				pct.Log.WithField("pod", pod).Debug("Removing policies from the pod.")
 				delPodConfig = true
-				delete(pct.podIPAddresses, pod)
+				pct.podIPAddresses[pod] = nil
+				pct.podPolicies[pod] = nil
 			} else {
 				/* already un-configured */
 				continue
@@ -181,52 +256,72 @@ func (pct *PolicyConfiguratorTxn) Commit() error {
 			pct.podIPAddresses[pod] = podIPNet
 
 			// Sort policies to get the same outcome for the same set.
-			policies := unorderedPolicies.Copy()
+			policies = unorderedPolicies.Copy()
 			sort.Sort(policies)
+			pct.podPolicies[pod] = policies.ids()
+			policiesKey = policies.key()
+		}
+
+		// Start transaction on every renderer if they are not running already.
+		startRendererTxns()
 
-			// Check if this set was already processed.
-			alreadyProcessed := false
-			for _, policySet := range processed {
-				if policySet.policies.Equals(policies) {
+		for i, group := range groups {
+			ingress := &ContivRules{}
+			egress := &ContivRules{}
+			if !delPodConfig {
+				// Check if this set was already processed.
+				policySet, alreadyProcessed := processed[i][policiesKey]
+				if alreadyProcessed {
 					ingress = policySet.ingress
 					egress = policySet.egress
-					alreadyProcessed = true
 				}
-			}
 
-			// Generate rules for a set of policies not yet processed.
-			if !alreadyProcessed {
-				// Direction in policies is from the pod point of view, whereas rules
-				// are evaluated from the vswitch perspective.
-				egress = pct.generateRules(MatchIngress, policies)
-				ingress = pct.generateRules(MatchEgress, policies)
-				// Remember already processed set of policies.
-				processed = append(processed,
-					ProcessedPolicySet{
+				// Generate rules for a set of policies not yet processed.
+				if !alreadyProcessed {
+					// Direction in policies is from the pod point of view, whereas rules
+					// are evaluated from the vswitch perspective.
+					egress = pct.generateRules(MatchIngress, policies, group.portMatching)
+					ingress = pct.generateRules(MatchEgress, policies, group.portMatching)
+					// Remember already processed set of policies.
+					processed[i][policiesKey] = ProcessedPolicySet{
 						policies: policies,
 						ingress:  ingress,
 						egress:   egress,
-					})
+					}
+				}
 			}
-		}
 
-		// Start transaction on every renderer if they are not running already.
-		if len(rendererTxns) == 0 {
-			for _, renderer := range pct.configurator.renderers {
-				rendererTxns = append(rendererTxns, renderer.NewTxn(pct.resync))
+			// Add rules into the transactions.
+			for _, rTxn := range rendererTxns[i] {
+				rTxn.Render(pod, podIPNet, ingress.CopySlice(), egress.CopySlice(), delPodConfig)
 			}
 		}
+	}
 
-		// Add rules into the transactions.
-		for _, rTxn := range rendererTxns {
-			rTxn.Render(pod, podIPNet, ingress.CopySlice(), egress.CopySlice(), delPodConfig)
+	// Render rules protecting the node itself (only by renderers supporting it).
+	if pct.withHostConfig {
+		startRendererTxns()
+		for i, group := range groups {
+			var hostRules []*renderer.ContivRule
+			if len(pct.hostConfig) > 0 {
+				hostRules = pct.generateHostRules(pct.hostConfig, group.portMatching).CopySlice()
+			}
+			for _, rTxn := range rendererTxns[i] {
+				if hostTxn, supportsHost := rTxn.(renderer.HostTxn); supportsHost {
+					hostTxn.RenderHost(hostRules)
+				}
+			}
 		}
 	}
 
 	// Commit all renderer transactions.
+	var allTxns []renderer.Txn
+	for _, groupTxns := range rendererTxns {
+		allTxns = append(allTxns, groupTxns...)
+	}
 	var wasError error
 	rndrChan := make(chan error)
-	for _, rTxn := range rendererTxns {
+	for _, rTxn := range allTxns {
 		if pct.configurator.parallelRendering {
 			go func(txn renderer.Txn) {
 				err := txn.Commit()
@@ -240,7 +335,7 @@ func (pct *PolicyConfiguratorTxn) Commit() error {
 		}
 	}
 	if pct.configurator.parallelRendering {
-		for i := 0; i < len(rendererTxns); i++ {
+		for i := 0; i < len(allTxns); i++ {
 			err := <-rndrChan
 			if err != nil {
 				wasError = err
@@ -249,11 +344,42 @@ func (pct *PolicyConfiguratorTxn) Commit() error {
 	}
 
 	// Save changes to the configurator.
-	pct.configurator.podIPAddresses = pct.podIPAddresses.Copy()
+	if pct.resync {
+		pct.configurator.podIPAddresses = make(PodIPAddresses)
+		pct.configurator.podPolicies = make(PodPolicies)
+	}
+	for pod, podIPNet := range pct.podIPAddresses {
+		if podIPNet == nil {
+			delete(pct.configurator.podIPAddresses, pod)
+		} else {
+			pct.configurator.podIPAddresses[pod] = podIPNet
+		}
+	}
+	for pod, policies := range pct.podPolicies {
+		if policies == nil {
+			delete(pct.configurator.podPolicies, pod)
+		} else {
+			pct.configurator.podPolicies[pod] = policies
+		}
+	}
 
 	return wasError
 }
 
+// getPodIPAddress returns IP address of the pod as known before the Commit()
+// (or as updated by the transaction).
+func (pct *PolicyConfiguratorTxn) getPodIPAddress(pod podmodel.ID) (podIPNet *net.IPNet, hasIPAddr bool) {
+	if podIPNet, changed := pct.podIPAddresses[pod]; changed {
+		return podIPNet, podIPNet != nil
+	}
+	if pct.resync {
+		// configuration is built from scratch
+		return nil, false
+	}
+	podIPNet, hasIPAddr = pct.configurator.podIPAddresses[pod]
+	return podIPNet, hasIPAddr
+}
+
 // PeerPod represents the opposite pod in the policy rule.
 type PeerPod struct {
 	ID    podmodel.ID
@@ -261,183 +387,43 @@ type PeerPod struct {
 }
 
 // Generate a list of ingress or egress rules implementing a given list of policies.
-func (pct *PolicyConfiguratorTxn) generateRules(direction MatchType, policies ContivPolicies) *ContivRules {
-	rules := &ContivRules{}
+// Rules of admin policies are generated first, ordered by priorities, followed
+// by the rules of namespaced policies. Ports are restricted by the given port
+// matching of the renderers (nil if all ports can be matched).
+func (pct *PolicyConfiguratorTxn) generateRules(direction MatchType, policies ContivPolicies,
+	portMatching renderer.PortMatchingAPI) *ContivRules {
+	nsRules := &ContivRules{}
 	hasPolicy := false
 	allAllowed := false
+	var isolatedBy []string
 
 	for _, policy := range policies {
+		if policy.Admin {
+			continue
+		}
 		if (policy.Type == PolicyIngress && direction == MatchEgress) ||
 			(policy.Type == PolicyEgress && direction == MatchIngress) {
 			// Policy does not apply to this direction.
 			continue
 		}
 		hasPolicy = true
+		isolatedBy = append(isolatedBy, policyRef(policy))
 
 		for _, match := range policy.Matches {
 			if match.Type != direction {
 				continue
 			}
-
-			// Collect IP addresses of all pod peers.
-			peers := []PeerPod{}
-			for _, peer := range match.Pods {
-				found, peerData := pct.configurator.Cache.LookupPod(peer)
-				if !found {
-					pct.Log.WithField("peer", peer).Warn("Peer pod data not found in the cache")
-					continue
-				}
-				if peerData.IpAddress == "" {
-					pct.Log.WithField("peer", peer).Debug("Peer pod has no IP address assigned")
-					continue
-				}
-				peerIPNet := utils.GetOneHostSubnet(peerData.IpAddress)
-				if peerIPNet == nil {
-					pct.Log.WithFields(logging.Fields{
-						"peer": peer,
-						"ip":   peerData.IpAddress}).Warn("Peer pod has invalid IP address assigned")
-					continue
-				}
-				peers = append(peers, PeerPod{ID: peer, IPNet: peerIPNet})
-			}
-
-			// Collect all subnets from IPBlocks.
-			allSubnets := []*net.IPNet{}
-			for _, block := range match.IPBlocks {
-				subnets := []*net.IPNet{&block.Network}
-				for _, except := range block.Except {
-					subtracted := []*net.IPNet{}
-					for _, subnet := range subnets {
-						subtracted = append(subtracted, subtractSubnet(subnet, &except)...)
-					}
-					subnets = subtracted
-				}
-				allSubnets = append(allSubnets, subnets...)
-			}
-
-			// Handle undefined set of pods and IP blocks.
-			// = match anything on L3
-			if match.Pods == nil && match.IPBlocks == nil {
-				if len(match.Ports) == 0 {
-					// = match anything on L3 & L4
-					ruleAny := &renderer.ContivRule{
-						Action:      renderer.ActionPermit,
-						SrcNetwork:  &net.IPNet{},
-						DestNetwork: &net.IPNet{},
-						Protocol:    renderer.ANY,
-						SrcPort:     0,
-						DestPort:    0,
-					}
-					rules.Insert(ruleAny)
-					allAllowed = true
-				} else {
-					// = match by L4
-					for _, port := range match.Ports {
-						rule := &renderer.ContivRule{
-							Action:      renderer.ActionPermit,
-							SrcNetwork:  &net.IPNet{},
-							DestNetwork: &net.IPNet{},
-							SrcPort:     0,
-							DestPort:    port.Number,
-						}
-						if port.Protocol == TCP {
-							rule.Protocol = renderer.TCP
-						} else {
-							rule.Protocol = renderer.UDP
-						}
-						rules.Insert(rule)
-					}
-				}
+			match, valid := pct.checkMatchPorts(policy, match, portMatching)
+			if !valid {
+				continue
 			}
-
-			// Combine pod peers with ports.
-			for _, peer := range peers {
-				if len(match.Ports) == 0 {
-					// Match all ports.
-					// = match by L3
-					ruleAny := &renderer.ContivRule{
-						Action:      renderer.ActionPermit,
-						Protocol:    renderer.ANY,
-						SrcNetwork:  &net.IPNet{},
-						DestNetwork: &net.IPNet{},
-						SrcPort:     0,
-						DestPort:    0,
-					}
-					if direction == MatchIngress {
-						ruleAny.SrcNetwork = peer.IPNet
-					} else {
-						ruleAny.DestNetwork = peer.IPNet
-					}
-					rules.Insert(ruleAny)
-				} else {
-					// Combine each port with the peer.
-					// = match by L3 & L4
-					for _, port := range match.Ports {
-						rule := &renderer.ContivRule{
-							Action:      renderer.ActionPermit,
-							SrcNetwork:  &net.IPNet{},
-							DestNetwork: &net.IPNet{},
-							SrcPort:     0,
-							DestPort:    port.Number,
-						}
-						if direction == MatchIngress {
-							rule.SrcNetwork = peer.IPNet
-						} else {
-							rule.DestNetwork = peer.IPNet
-						}
-						if port.Protocol == TCP {
-							rule.Protocol = renderer.TCP
-						} else {
-							rule.Protocol = renderer.UDP
-						}
-						rules.Insert(rule)
-					}
-				}
+			matchRules, matchesAll := pct.generateMatchRules(direction, match)
+			for _, rule := range matchRules {
+				rule.Policies = []string{policyRef(policy)}
+				nsRules.Insert(rule)
 			}
-
-			// Combine IPBlocks with ports.
-			for _, subnet := range allSubnets {
-				if len(match.Ports) == 0 {
-					// Handle IPBlock with no ports.
-					// = match by L3
-					ruleAny := &renderer.ContivRule{
-						Action:      renderer.ActionPermit,
-						Protocol:    renderer.ANY,
-						SrcNetwork:  &net.IPNet{},
-						DestNetwork: &net.IPNet{},
-						SrcPort:     0,
-						DestPort:    0,
-					}
-					if direction == MatchIngress {
-						ruleAny.SrcNetwork = subnet
-					} else {
-						ruleAny.DestNetwork = subnet
-					}
-					rules.Insert(ruleAny)
-				} else {
-					// Combine each port with the block.
-					// = match by L3 & L4
-					for _, port := range match.Ports {
-						rule := &renderer.ContivRule{
-							Action:      renderer.ActionPermit,
-							SrcNetwork:  &net.IPNet{},
-							DestNetwork: &net.IPNet{},
-							SrcPort:     0,
-							DestPort:    port.Number,
-						}
-						if direction == MatchIngress {
-							rule.SrcNetwork = subnet
-						} else {
-							rule.DestNetwork = subnet
-						}
-						if port.Protocol == TCP {
-							rule.Protocol = renderer.TCP
-						} else {
-							rule.Protocol = renderer.UDP
-						}
-						rules.Insert(rule)
-					}
-				}
+			if matchesAll {
+				allAllowed = true
 			}
 		}
 	}
@@ -454,9 +440,9 @@ func (pct *PolicyConfiguratorTxn) generateRules(direction MatchType, policies Co
 				SrcPort:     0,
 				DestPort:    0,
 			}
-			rules.Insert(ruleAny)
+			nsRules.Insert(ruleAny)
 		}
-		// Deny the rest.
+		// Deny the rest (attributed to all the policies isolating the pod).
 		ruleNone := &renderer.ContivRule{
 			Action:      renderer.ActionDeny,
 			SrcNetwork:  &net.IPNet{},
@@ -464,13 +450,406 @@ func (pct *PolicyConfiguratorTxn) generateRules(direction MatchType, policies Co
 			Protocol:    renderer.ANY,
 			SrcPort:     0,
 			DestPort:    0,
+			Policies:    isolatedBy,
+		}
+		nsRules.Insert(ruleNone)
+	}
+
+	return pct.generateAdminRules(direction, policies, nsRules, portMatching)
+}
+
+// generateHostRules generates a list of rules implementing a given set of host
+// policies. Traffic destined to the node and not matched by any policy is denied
+// (attributed to all the host policies).
+func (pct *PolicyConfiguratorTxn) generateHostRules(unorderedPolicies ContivPolicies,
+	portMatching renderer.PortMatchingAPI) *ContivRules {
+	policies := unorderedPolicies.Copy()
+	sort.Sort(policies)
+
+	rules := &ContivRules{}
+	allAllowed := false
+	var isolatedBy []string
+	for _, policy := range policies {
+		isolatedBy = append(isolatedBy, policyRef(policy))
+		for _, match := range policy.Matches {
+			if match.Type != MatchIngress {
+				continue
+			}
+			match, valid := pct.checkMatchPorts(policy, match, portMatching)
+			if !valid {
+				continue
+			}
+			matchRules, matchesAll := pct.generateMatchRules(MatchIngress, match)
+			for _, rule := range matchRules {
+				rule.Policies = []string{policyRef(policy)}
+				rules.Insert(rule)
+			}
+			if matchesAll {
+				allAllowed = true
+			}
+		}
+	}
+	if !allAllowed {
+		ruleNone := &renderer.ContivRule{
+			Action:      renderer.ActionDeny,
+			SrcNetwork:  &net.IPNet{},
+			DestNetwork: &net.IPNet{},
+			Protocol:    renderer.ANY,
+			Policies:    isolatedBy,
 		}
 		rules.Insert(ruleNone)
 	}
+	return rules
+}
+
+// generateAdminRules generates rules of admin policies and combines them with
+// the rules of namespaced policies (nsRules) into a single list.
+// Admin policies are evaluated in the order of priorities (ties are broken by
+// policy names), matches of a policy in the given order. Every match is assigned
+// a distinct rule priority, decreasing with the order of evaluation and always
+// higher than the priority of the namespaced tier.
+// Traffic matched with the Pass action is evaluated by the namespaced rules,
+// which are therefore intersected with the match and inserted with its priority.
+func (pct *PolicyConfiguratorTxn) generateAdminRules(direction MatchType, policies ContivPolicies, nsRules *ContivRules,
+	portMatching renderer.PortMatchingAPI) *ContivRules {
+	var adminPolicies ContivPolicies
+	var adminMatches int
+	for _, policy := range policies {
+		if !policy.Admin {
+			continue
+		}
+		adminPolicies = append(adminPolicies, policy)
+		for _, match := range policy.Matches {
+			if match.Type == direction {
+				adminMatches++
+			}
+		}
+	}
+	if adminMatches == 0 {
+		return nsRules
+	}
+	sort.SliceStable(adminPolicies, func(i, j int) bool {
+		if adminPolicies[i].Priority != adminPolicies[j].Priority {
+			return adminPolicies[i].Priority < adminPolicies[j].Priority
+		}
+		return adminPolicies.Less(i, j)
+	})
+
+	rules := &ContivRules{}
+	priority := renderer.NamespacedTierPriority + uint32(adminMatches)
+	for _, policy := range adminPolicies {
+		for _, match := range policy.Matches {
+			if match.Type != direction {
+				continue
+			}
+			match, valid := pct.checkMatchPorts(policy, match, portMatching)
+			if !valid {
+				priority--
+				continue
+			}
+			matchRules, _ := pct.generateMatchRules(direction, match)
+			for _, rule := range matchRules {
+				rule.Priority = priority
+				rule.Policies = []string{policyRef(policy)}
+				switch match.Action {
+				case ActionAllow:
+					rules.Insert(rule)
+				case ActionDeny:
+					rule.Action = renderer.ActionDeny
+					rules.Insert(rule)
+				case ActionPass:
+					for _, passRule := range passRules(rule, nsRules) {
+						rules.Insert(passRule)
+					}
+				}
+			}
+			priority--
+		}
+	}
+
+	// Namespaced policies are evaluated last.
+	for _, rule := range nsRules.rules {
+		rules.Insert(rule)
+	}
+	return rules
+}
 
+// passRules returns rules implementing the evaluation of the traffic matched
+// by the given rule of an admin policy with the Pass action by the namespaced
+// rules. The returned rules inherit priority from the admin rule.
+func passRules(adminRule *renderer.ContivRule, nsRules *ContivRules) (rules []*renderer.ContivRule) {
+	if len(nsRules.rules) == 0 {
+		// not isolated by namespaced policies
+		return []*renderer.ContivRule{adminRule}
+	}
+	// namespaced rules are in the order of evaluation
+	for _, nsRule := range nsRules.rules {
+		rule := intersectRules(adminRule, nsRule)
+		if rule == nil {
+			continue
+		}
+		rule.Action = nsRule.Action
+		rule.Policies = joinPolicies(adminRule.Policies, nsRule.Policies)
+		var shadowed bool
+		for _, prevRule := range rules {
+			prevRule := prevRule.Copy()
+			prevRule.Action = rule.Action
+			if prevRule.Compare(rule) == 0 {
+				// the same traffic matched by a preceding rule
+				shadowed = true
+				break
+			}
+		}
+		if !shadowed {
+			rules = append(rules, rule)
+		}
+	}
 	return rules
 }
 
+// checkMatchPorts handles ports which cannot be matched with the given port
+// matching of the renderers. The affected ports are reported as errors of the policy.
+// For matches allowing the traffic, the ports are removed from the match and
+// valid is returned as false if all the ports of the match were removed, i.e.
+// the match should not be rendered at all (the traffic is not allowed).
+// Matches with the Deny or Pass action (admin policies) must not select less
+// traffic than requested, otherwise the traffic could be allowed by the policies
+// evaluated next. Such ports are therefore widened to all ports of the protocol.
+func (pct *PolicyConfiguratorTxn) checkMatchPorts(policy *ContivPolicy, match Match,
+	portMatching renderer.PortMatchingAPI) (checked Match, valid bool) {
+	var ports []Port
+	var changed bool
+	for _, port := range match.Ports {
+		if port.Number != 0 && !canMatchPorts(portMatching, rendererProtocol(port.Protocol)) {
+			changed = true
+			if match.Action == ActionAllow {
+				pct.Log.Errorf("Policy %s: port %s cannot be matched by the policy renderer, "+
+					"rejecting the port", policyRef(policy), port)
+				continue
+			}
+			pct.Log.Errorf("Policy %s: port %s cannot be matched by the policy renderer, "+
+				"applying %s action to all %s ports", policyRef(policy), port, match.Action, port.Protocol)
+			port = Port{Protocol: port.Protocol}
+			if containsPort(ports, port) {
+				continue
+			}
+		}
+		ports = append(ports, port)
+	}
+	if !changed {
+		return match, true
+	}
+	match.Ports = ports
+	return match, len(ports) > 0
+}
+
+// containsPort returns true if the port is in the list.
+func containsPort(ports []Port, port Port) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+// canMatchPorts returns true if renderers with the given port matching (nil
+// if not restricted) are able to match L4 ports of the given protocol.
+func canMatchPorts(portMatching renderer.PortMatchingAPI, protocol renderer.ProtocolType) bool {
+	return portMatching == nil || portMatching.CanMatchPorts(protocol)
+}
+
+// generateMatchRules generates permit rules implementing a given match.
+// Returns matchesAll as true if the match selects all the traffic.
+func (pct *PolicyConfiguratorTxn) generateMatchRules(direction MatchType, match Match) (rules []*renderer.ContivRule, matchesAll bool) {
+	// Collect IP addresses of all pod peers.
+	peers := []PeerPod{}
+	for _, peer := range match.Pods {
+		found, peerData := pct.configurator.Cache.LookupPod(peer)
+		if !found {
+			pct.Log.WithField("peer", peer).Warn("Peer pod data not found in the cache")
+			continue
+		}
+		if peerData.IpAddress == "" {
+			pct.Log.WithField("peer", peer).Debug("Peer pod has no IP address assigned")
+			continue
+		}
+		peerIPNet := utils.GetOneHostSubnet(peerData.IpAddress)
+		if peerIPNet == nil {
+			pct.Log.WithFields(logging.Fields{
+				"peer": peer,
+				"ip":   peerData.IpAddress}).Warn("Peer pod has invalid IP address assigned")
+			continue
+		}
+		peers = append(peers, PeerPod{ID: peer, IPNet: peerIPNet})
+	}
+
+	// Collect all subnets from IPBlocks.
+	allSubnets := []*net.IPNet{}
+	for _, block := range match.IPBlocks {
+		subnets := []*net.IPNet{&block.Network}
+		for _, except := range block.Except {
+			subtracted := []*net.IPNet{}
+			for _, subnet := range subnets {
+				subtracted = append(subtracted, subtractSubnet(subnet, &except)...)
+			}
+			subnets = subtracted
+		}
+		allSubnets = append(allSubnets, subnets...)
+	}
+
+	// Handle undefined set of pods and IP blocks.
+	// = match anything on L3
+	if match.Pods == nil && match.IPBlocks == nil {
+		if len(match.Ports) == 0 {
+			// = match anything on L3 & L4
+			ruleAny := &renderer.ContivRule{
+				Action:      renderer.ActionPermit,
+				SrcNetwork:  &net.IPNet{},
+				DestNetwork: &net.IPNet{},
+				Protocol:    renderer.ANY,
+				SrcPort:     0,
+				DestPort:    0,
+			}
+			rules = append(rules, ruleAny)
+			matchesAll = true
+		} else {
+			// = match by L4
+			for _, port := range match.Ports {
+				rule := &renderer.ContivRule{
+					Action:      renderer.ActionPermit,
+					SrcNetwork:  &net.IPNet{},
+					DestNetwork: &net.IPNet{},
+					SrcPort:     0,
+					DestPort:    port.Number,
+					DestPortEnd: port.EndNumber,
+				}
+				rule.Protocol = rendererProtocol(port.Protocol)
+				rules = append(rules, rule)
+			}
+		}
+	}
+
+	// Combine pod peers with ports.
+	for _, peer := range peers {
+		if len(match.Ports) == 0 {
+			// Match all ports.
+			// = match by L3
+			ruleAny := &renderer.ContivRule{
+				Action:      renderer.ActionPermit,
+				Protocol:    renderer.ANY,
+				SrcNetwork:  &net.IPNet{},
+				DestNetwork: &net.IPNet{},
+				SrcPort:     0,
+				DestPort:    0,
+			}
+			if direction == MatchIngress {
+				ruleAny.SrcNetwork = peer.IPNet
+			} else {
+				ruleAny.DestNetwork = peer.IPNet
+			}
+			rules = append(rules, ruleAny)
+		} else {
+			// Combine each port with the peer.
+			// = match by L3 & L4
+			for _, port := range match.Ports {
+				rule := &renderer.ContivRule{
+					Action:      renderer.ActionPermit,
+					SrcNetwork:  &net.IPNet{},
+					DestNetwork: &net.IPNet{},
+					SrcPort:     0,
+					DestPort:    port.Number,
+					DestPortEnd: port.EndNumber,
+				}
+				if direction == MatchIngress {
+					rule.SrcNetwork = peer.IPNet
+				} else {
+					rule.DestNetwork = peer.IPNet
+				}
+				rule.Protocol = rendererProtocol(port.Protocol)
+				rules = append(rules, rule)
+			}
+		}
+	}
+
+	// Combine IPBlocks with ports.
+	for _, subnet := range allSubnets {
+		if len(match.Ports) == 0 {
+			// Handle IPBlock with no ports.
+			// = match by L3
+			ruleAny := &renderer.ContivRule{
+				Action:      renderer.ActionPermit,
+				Protocol:    renderer.ANY,
+				SrcNetwork:  &net.IPNet{},
+				DestNetwork: &net.IPNet{},
+				SrcPort:     0,
+				DestPort:    0,
+			}
+			if direction == MatchIngress {
+				ruleAny.SrcNetwork = subnet
+			} else {
+				ruleAny.DestNetwork = subnet
+			}
+			rules = append(rules, ruleAny)
+		} else {
+			// Combine each port with the block.
+			// = match by L3 & L4
+			for _, port := range match.Ports {
+				rule := &renderer.ContivRule{
+					Action:      renderer.ActionPermit,
+					SrcNetwork:  &net.IPNet{},
+					DestNetwork: &net.IPNet{},
+					SrcPort:     0,
+					DestPort:    port.Number,
+					DestPortEnd: port.EndNumber,
+				}
+				if direction == MatchIngress {
+					rule.SrcNetwork = subnet
+				} else {
+					rule.DestNetwork = subnet
+				}
+				rule.Protocol = rendererProtocol(port.Protocol)
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return rules, matchesAll
+}
+
+// policyRef returns the reference to the policy used to attribute rules
+// to the policies they were generated from.
+// Admin and host policies are cluster-scoped and therefore referenced just by the name.
+func policyRef(policy *ContivPolicy) string {
+	if policy.Admin || policy.Host {
+		return policy.ID.Name
+	}
+	return policy.ID.String()
+}
+
+// joinPolicies returns union of two lists of policy references.
+func joinPolicies(policies1, policies2 []string) []string {
+	if len(policies2) == 0 {
+		return policies1
+	}
+	joined := make([]string, 0, len(policies1)+len(policies2))
+	joined = append(joined, policies1...)
+	joined = append(joined, policies2...)
+	joined = utils.RemoveDuplicates(joined)
+	sort.Strings(joined)
+	return joined
+}
+
+// rendererProtocol translates L4 protocol of a policy port into the renderer representation.
+func rendererProtocol(protocol ProtocolType) renderer.ProtocolType {
+	switch protocol {
+	case UDP:
+		return renderer.UDP
+	case SCTP:
+		return renderer.SCTP
+	}
+	return renderer.TCP
+}
+
 // Copy creates a shallow copy of ContivPolicies.
 func (cp ContivPolicies) Copy() ContivPolicies {
 	cpCopy := make(ContivPolicies, len(cp))
@@ -491,6 +870,25 @@ func (cp ContivPolicies) Equals(cp2 ContivPolicies) bool {
 	return true
 }
 
+// key returns a string identifying the (ordered) list of policies.
+// Lists equal by Equals() have the same key.
+func (cp ContivPolicies) key() string {
+	ids := make([]string, 0, len(cp))
+	for _, policy := range cp {
+		ids = append(ids, policy.ID.String())
+	}
+	return strings.Join(ids, ",")
+}
+
+// ids returns IDs of the policies from the list (never nil).
+func (cp ContivPolicies) ids() []policymodel.ID {
+	ids := make([]policymodel.ID, 0, len(cp))
+	for _, policy := range cp {
+		ids = append(ids, policy.ID)
+	}
+	return ids
+}
+
 // Len return the number of policies in the list.
 func (cp ContivPolicies) Len() int {
 	return len(cp)
@@ -516,7 +914,7 @@ func (cp ContivPolicies) Less(i, j int) bool {
 
 // Insert inserts the rule into the list.
 // Returns *true* if the rule was inserted, *false* if the same rule is already
-// in the list.
+// in the list (policies of the rule are then added to the existing one).
 func (cr *ContivRules) Insert(rule *renderer.ContivRule) bool {
 	// get the index at which the rule should be inserted to keep the order
 	idx := sort.Search(len(cr.orderedRules),
@@ -524,6 +922,8 @@ func (cr *ContivRules) Insert(rule *renderer.ContivRule) bool {
 			return rule.Compare(cr.orderedRules[i]) <= 0
 		})
 	if idx < len(cr.orderedRules) && rule.Compare(cr.orderedRules[idx]) == 0 {
+		existing := cr.orderedRules[idx]
+		existing.Policies = joinPolicies(existing.Policies, rule.Policies)
 		return false
 	}
 
@@ -558,6 +958,15 @@ func (pa PodIPAddresses) Copy() PodIPAddresses {
 	return paCopy
 }
 
+// Copy creates a shallow copy of PodPolicies.
+func (pp PodPolicies) Copy() PodPolicies {
+	ppCopy := make(PodPolicies, len(pp))
+	for pod, policies := range pp {
+		ppCopy[pod] = policies
+	}
+	return ppCopy
+}
+
 // Function returns a list of subnets with all IPs included in net1 and not included in net2.
 func subtractSubnet(net1, net2 *net.IPNet) []*net.IPNet {
 	result := []*net.IPNet{}
@@ -592,3 +1001,84 @@ func subtractSubnet(net1, net2 *net.IPNet) []*net.IPNet {
 
 	return result
 }
+
+// intersectRules returns a rule matching the traffic matched by both given rules,
+// or nil if the rules do not overlap. Action and priority are taken from <rule1>.
+func intersectRules(rule1, rule2 *renderer.ContivRule) *renderer.ContivRule {
+	srcNetwork := intersectNetworks(rule1.SrcNetwork, rule2.SrcNetwork)
+	destNetwork := intersectNetworks(rule1.DestNetwork, rule2.DestNetwork)
+	if srcNetwork == nil || destNetwork == nil {
+		return nil
+	}
+	intersection := rule1.Copy()
+	intersection.SrcNetwork = srcNetwork
+	intersection.DestNetwork = destNetwork
+
+	switch {
+	case rule2.Protocol == renderer.ANY:
+		// L4 given by rule1
+	case rule1.Protocol == renderer.ANY:
+		intersection.Protocol = rule2.Protocol
+		intersection.SrcPort = rule2.SrcPort
+		intersection.DestPort = rule2.DestPort
+		intersection.DestPortEnd = rule2.DestPortEnd
+	case rule1.Protocol != rule2.Protocol:
+		return nil
+	default:
+		if rule1.SrcPort == 0 {
+			intersection.SrcPort = rule2.SrcPort
+		} else if rule2.SrcPort != 0 && rule2.SrcPort != rule1.SrcPort {
+			return nil
+		}
+		start1, end1 := portRange(rule1)
+		start2, end2 := portRange(rule2)
+		if start2 > start1 {
+			start1 = start2
+		}
+		if end2 < end1 {
+			end1 = end2
+		}
+		if start1 > end1 {
+			return nil
+		}
+		intersection.DestPort, intersection.DestPortEnd = 0, 0
+		if start1 != 1 || end1 != ^uint16(0) {
+			intersection.DestPort = start1
+			if end1 > start1 {
+				intersection.DestPortEnd = end1
+			}
+		}
+	}
+	return intersection
+}
+
+// portRange returns the range of destination ports matched by the rule.
+func portRange(rule *renderer.ContivRule) (start, end uint16) {
+	if rule.DestPort == 0 {
+		return 1, ^uint16(0)
+	}
+	if rule.DestPortEnd > rule.DestPort {
+		return rule.DestPort, rule.DestPortEnd
+	}
+	return rule.DestPort, rule.DestPort
+}
+
+// intersectNetworks returns the intersection of two networks (empty network
+// matches all) or nil if the networks do not overlap.
+func intersectNetworks(net1, net2 *net.IPNet) *net.IPNet {
+	if len(net1.IP) == 0 {
+		return net2
+	}
+	if len(net2.IP) == 0 {
+		return net1
+	}
+	net1MaskSize, _ := net1.Mask.Size()
+	net2MaskSize, _ := net2.Mask.Size()
+	if net1MaskSize >= net2MaskSize && net2.Contains(net1.IP) {
+		return net1
+	}
+	if net2MaskSize >= net1MaskSize && net1.Contains(net2.IP) {
+		return net2
+	}
+	return nil
+}
@@ -132,6 +132,11 @@ func TestSinglePolicySinglePod(t *testing.T) {
 	gomega.Expect(masklen).To(gomega.BeEquivalentTo(net.IPv4len * 8))
 	gomega.Expect(ip).To(gomega.BeEquivalentTo(pod1IP))
 
+	// Test policies remembered for the pods.
+	gomega.Expect(configurator.GetPodPolicies()).To(gomega.Equal(PodPolicies{
+		pod1: []policymodel.ID{policy1.ID},
+	}))
+
 	// Test with fake traffic.
 
 	// Allowed by policy1.
@@ -178,6 +183,226 @@ func TestSinglePolicySinglePod(t *testing.T) {
 	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
 }
 
+func TestSinglePolicyWithSCTPAndPortRangeSinglePod(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestSinglePolicyWithSCTPAndPortRangeSinglePod")
+
+	// Prepare input data.
+	const (
+		namespace = "default"
+		pod1Name  = "pod1"
+		pod2Name  = "pod2"
+		pod1IP    = "192.168.1.1"
+		pod2IP    = "192.168.1.2"
+	)
+	pod1 := podmodel.ID{Name: pod1Name, Namespace: namespace}
+	pod2 := podmodel.ID{Name: pod2Name, Namespace: namespace}
+
+	policy1 := &ContivPolicy{
+		ID:   policymodel.ID{Name: "policy1", Namespace: namespace},
+		Type: PolicyIngress,
+		Matches: []Match{
+			{
+				Type: MatchIngress,
+				Pods: []podmodel.ID{
+					pod2,
+				},
+				Ports: []Port{
+					{Protocol: SCTP, Number: 3868},
+					{Protocol: UDP, Number: 10000, EndNumber: 20000},
+				},
+			},
+		},
+	}
+	pod1Policies := []*ContivPolicy{policy1}
+
+	// Initialize mocks.
+	cache := NewMockPolicyCache()
+	cache.AddPodConfig(pod1, pod1IP)
+	cache.AddPodConfig(pod2, pod2IP)
+
+	ipam := &ipamMock{}
+	ipam.SetNatLoopbackIP(natLoopbackIP)
+
+	renderer := NewMockRenderer("A", logger)
+
+	// Initialize configurator.
+	configurator := &PolicyConfigurator{
+		Deps: Deps{
+			Log:   logger,
+			Cache: cache,
+			IPAM:  ipam,
+		},
+	}
+	configurator.Init(false)
+
+	// Register one renderer.
+	err := configurator.RegisterRenderer(renderer)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// Run single transaction.
+	txn := configurator.NewTxn(false)
+	txn.Configure(pod1, pod1Policies)
+	err = txn.Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// Test with fake traffic.
+
+	// Allowed by policy1 - SCTP:3868.
+	action := renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.SCTP, 123, 3868)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+
+	// Allowed by policy1 - UDP port range, both boundaries included.
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.UDP, 123, 10000)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.UDP, 123, 15000)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.UDP, 123, 20000)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+
+	// Blocked by policy1 - UDP port outside of the range.
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.UDP, 123, 20001)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+
+	// Blocked by policy1 - SCTP port not allowed.
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.SCTP, 123, 3869)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+
+	// Blocked by policy1 - TCP not allowed.
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.TCP, 123, 3868)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+}
+
+func TestRejectedSCTPPorts(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestRejectedSCTPPorts")
+
+	// Prepare input data.
+	const (
+		namespace = "default"
+		pod1Name  = "pod1"
+		pod2Name  = "pod2"
+		pod3Name  = "pod3"
+		pod1IP    = "192.168.1.1"
+		pod2IP    = "192.168.1.2"
+		pod3IP    = "192.168.1.3"
+	)
+	pod1 := podmodel.ID{Name: pod1Name, Namespace: namespace}
+	pod2 := podmodel.ID{Name: pod2Name, Namespace: namespace}
+	pod3 := podmodel.ID{Name: pod3Name, Namespace: namespace}
+
+	policy1 := &ContivPolicy{
+		ID:   policymodel.ID{Name: "policy1", Namespace: namespace},
+		Type: PolicyIngress,
+		Matches: []Match{
+			{
+				Type: MatchIngress,
+				Pods: []podmodel.ID{
+					pod2,
+				},
+				Ports: []Port{
+					{Protocol: SCTP, Number: 3868},
+					{Protocol: UDP, Number: 10000, EndNumber: 20000},
+				},
+			},
+			{
+				Type: MatchIngress,
+				Pods: []podmodel.ID{
+					pod3,
+				},
+				Ports: []Port{
+					{Protocol: SCTP, Number: 3868},
+				},
+			},
+		},
+	}
+	pod1Policies := []*ContivPolicy{policy1}
+
+	// Initialize mocks.
+	cache := NewMockPolicyCache()
+	cache.AddPodConfig(pod1, pod1IP)
+	cache.AddPodConfig(pod2, pod2IP)
+	cache.AddPodConfig(pod3, pod3IP)
+
+	ipam := &ipamMock{}
+	ipam.SetNatLoopbackIP(natLoopbackIP)
+
+	// Renderer unable to match SCTP ports.
+	renderer := NewMockRenderer("A", logger)
+	renderer.DisablePortMatching(rendererAPI.SCTP)
+
+	// Renderer able to match all ports.
+	rendererB := NewMockRenderer("B", logger)
+
+	// Initialize configurator.
+	configurator := &PolicyConfigurator{
+		Deps: Deps{
+			Log:   logger,
+			Cache: cache,
+			IPAM:  ipam,
+		},
+	}
+	configurator.Init(false)
+
+	// Register both renderers.
+	err := configurator.RegisterRenderer(renderer)
+	gomega.Expect(err).To(gomega.BeNil())
+	err = configurator.RegisterRenderer(rendererB)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// Run single transaction.
+	txn := configurator.NewTxn(false)
+	txn.Configure(pod1, pod1Policies)
+	err = txn.Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// Test with fake traffic.
+
+	// Allowed by policy1 - UDP port range.
+	action := renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.UDP, 123, 15000)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+
+	// Blocked - SCTP port rejected, the rest of the match is kept.
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.SCTP, 123, 3868)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+
+	// Blocked - the only port of the match was rejected, the match does not
+	// turn into allowing all ports.
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod3IP), parseIP(pod1IP), rendererAPI.SCTP, 123, 3868)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod3IP), parseIP(pod1IP), rendererAPI.TCP, 123, 80)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+
+	// SCTP ports are not rejected for the renderer able to match them.
+	action = rendererB.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.SCTP, 123, 3868)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+	action = rendererB.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.SCTP, 123, 3869)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+	action = rendererB.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod3IP), parseIP(pod1IP), rendererAPI.SCTP, 123, 3868)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+	action = rendererB.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod3IP), parseIP(pod1IP), rendererAPI.TCP, 123, 80)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+}
+
 func TestSinglePolicyWithIPBlockSinglePod(t *testing.T) {
 	gomega.RegisterTestingT(t)
 	logger := logrus.DefaultLogger()
@@ -1750,3 +1975,495 @@ func TestMultiplePodsSpecialCases(t *testing.T) {
 		parseIP(natLoopbackIP), parseIP(pod1IP), rendererAPI.OTHER, 0, 0)
 	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
 }
+
+func TestAdminPoliciesSinglePod(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestAdminPoliciesSinglePod")
+
+	// Prepare input data.
+	const (
+		namespace = "default"
+		pod1Name  = "pod1"
+		pod2Name  = "pod2"
+		pod3Name  = "pod3"
+		pod4Name  = "pod4"
+		pod1IP    = "192.168.1.1"
+		pod2IP    = "192.168.1.2"
+		pod3IP    = "192.168.1.3"
+		pod4IP    = "192.168.1.4"
+	)
+	pod1 := podmodel.ID{Name: pod1Name, Namespace: namespace}
+	pod2 := podmodel.ID{Name: pod2Name, Namespace: namespace}
+	pod3 := podmodel.ID{Name: pod3Name, Namespace: namespace}
+	pod4 := podmodel.ID{Name: pod4Name, Namespace: namespace}
+
+	// Namespaced policy allowing pod2 to access pod1 on ports 80 and 443.
+	policy1 := &ContivPolicy{
+		ID:   policymodel.ID{Name: "policy1", Namespace: namespace},
+		Type: PolicyIngress,
+		Matches: []Match{
+			{
+				Type: MatchIngress,
+				Pods: []podmodel.ID{
+					pod2,
+				},
+				Ports: []Port{
+					{Protocol: TCP, Number: 80},
+					{Protocol: TCP, Number: 443},
+				},
+			},
+		},
+	}
+
+	// Admin policy denying access from pod2 on port 443, passing everything
+	// from pod3 to namespaced policies and allowing everything from pod4.
+	adminPolicy1 := &ContivPolicy{
+		ID:       policymodel.ID{Name: "admin-policy1"},
+		Type:     PolicyAll,
+		Admin:    true,
+		Priority: 10,
+		Matches: []Match{
+			{
+				Type:     MatchIngress,
+				Action:   ActionDeny,
+				Pods:     []podmodel.ID{pod2},
+				IPBlocks: []IPBlock{},
+				Ports: []Port{
+					{Protocol: TCP, Number: 443},
+				},
+			},
+			{
+				Type:     MatchIngress,
+				Action:   ActionPass,
+				Pods:     []podmodel.ID{pod3},
+				IPBlocks: []IPBlock{},
+			},
+			{
+				Type:     MatchIngress,
+				Action:   ActionAllow,
+				Pods:     []podmodel.ID{pod4},
+				IPBlocks: []IPBlock{},
+			},
+		},
+	}
+
+	// Admin policy with lower priority (higher value) allowing access from pod3
+	// to port 22 (never reached because of the Pass action) and denying access
+	// from pod1 to 10.0.0.0/8.
+	adminPolicy2 := &ContivPolicy{
+		ID:       policymodel.ID{Name: "admin-policy2"},
+		Type:     PolicyAll,
+		Admin:    true,
+		Priority: 20,
+		Matches: []Match{
+			{
+				Type:     MatchIngress,
+				Action:   ActionAllow,
+				Pods:     []podmodel.ID{pod3},
+				IPBlocks: []IPBlock{},
+				Ports: []Port{
+					{Protocol: TCP, Number: 22},
+				},
+			},
+			{
+				Type:   MatchEgress,
+				Action: ActionDeny,
+				Pods:   []podmodel.ID{},
+				IPBlocks: []IPBlock{
+					{Network: parseIPNet("10.0.0.0/8")},
+				},
+			},
+		},
+	}
+	pod1Policies := []*ContivPolicy{adminPolicy2, policy1, adminPolicy1}
+
+	// Initialize mocks.
+	cache := NewMockPolicyCache()
+	cache.AddPodConfig(pod1, pod1IP)
+	cache.AddPodConfig(pod2, pod2IP)
+	cache.AddPodConfig(pod3, pod3IP)
+	cache.AddPodConfig(pod4, pod4IP)
+
+	ipam := &ipamMock{}
+	ipam.SetNatLoopbackIP(natLoopbackIP)
+
+	renderer := NewMockRenderer("A", logger)
+
+	// Initialize configurator.
+	configurator := &PolicyConfigurator{
+		Deps: Deps{
+			Log:   logger,
+			Cache: cache,
+			IPAM:  ipam,
+		},
+	}
+	configurator.Init(false)
+
+	// Register one renderer.
+	err := configurator.RegisterRenderer(renderer)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// Run single transaction.
+	txn := configurator.NewTxn(false)
+	txn.Configure(pod1, pod1Policies)
+	err = txn.Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// Test with fake traffic.
+
+	// Allowed by policy1.
+	action := renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.TCP, 123, 80)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+
+	// Allowed by policy1 but denied by admin-policy1.
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.TCP, 123, 443)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+
+	// Passed by admin-policy1 and denied by policy1 (allow from admin-policy2
+	// is never reached).
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod3IP), parseIP(pod1IP), rendererAPI.TCP, 123, 22)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod3IP), parseIP(pod1IP), rendererAPI.TCP, 123, 80)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+
+	// Denied by policy1 but allowed by admin-policy1.
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod4IP), parseIP(pod1IP), rendererAPI.TCP, 123, 8080)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod4IP), parseIP(pod1IP), rendererAPI.OTHER, 0, 0)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+
+	// Denied by policy1.
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP("10.5.10.10"), parseIP(pod1IP), rendererAPI.TCP, 123, 80)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+
+	// Denied by admin-policy2 (egress is not isolated by namespaced policies).
+	action = renderer.TestTraffic(pod1, IngressTraffic,
+		parseIP(pod1IP), parseIP("10.5.10.10"), rendererAPI.TCP, 123, 80)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+	action = renderer.TestTraffic(pod1, IngressTraffic,
+		parseIP(pod1IP), parseIP(pod2IP), rendererAPI.TCP, 123, 80)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(UnmatchedTraffic))
+
+	// Always allowed.
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(natLoopbackIP), parseIP(pod1IP), rendererAPI.TCP, 123, 9000)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+}
+
+func TestAdminPassWithoutNamespacedPolicies(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestAdminPassWithoutNamespacedPolicies")
+
+	// Prepare input data.
+	const (
+		namespace = "default"
+		pod1Name  = "pod1"
+		pod2Name  = "pod2"
+		pod1IP    = "192.168.1.1"
+		pod2IP    = "192.168.1.2"
+	)
+	pod1 := podmodel.ID{Name: pod1Name, Namespace: namespace}
+	pod2 := podmodel.ID{Name: pod2Name, Namespace: namespace}
+
+	// Pass traffic from pod2 on port 80, deny the rest from pod2.
+	adminPolicy := &ContivPolicy{
+		ID:       policymodel.ID{Name: "admin-policy"},
+		Type:     PolicyAll,
+		Admin:    true,
+		Priority: 0,
+		Matches: []Match{
+			{
+				Type:     MatchIngress,
+				Action:   ActionPass,
+				Pods:     []podmodel.ID{pod2},
+				IPBlocks: []IPBlock{},
+				Ports: []Port{
+					{Protocol: TCP, Number: 80},
+				},
+			},
+			{
+				Type:     MatchIngress,
+				Action:   ActionDeny,
+				Pods:     []podmodel.ID{pod2},
+				IPBlocks: []IPBlock{},
+			},
+		},
+	}
+	pod1Policies := []*ContivPolicy{adminPolicy}
+
+	// Initialize mocks.
+	cache := NewMockPolicyCache()
+	cache.AddPodConfig(pod1, pod1IP)
+	cache.AddPodConfig(pod2, pod2IP)
+
+	ipam := &ipamMock{}
+	ipam.SetNatLoopbackIP(natLoopbackIP)
+
+	renderer := NewMockRenderer("A", logger)
+
+	// Initialize configurator.
+	configurator := &PolicyConfigurator{
+		Deps: Deps{
+			Log:   logger,
+			Cache: cache,
+			IPAM:  ipam,
+		},
+	}
+	configurator.Init(false)
+
+	// Register one renderer.
+	err := configurator.RegisterRenderer(renderer)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// Run single transaction.
+	txn := configurator.NewTxn(false)
+	txn.Configure(pod1, pod1Policies)
+	err = txn.Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// Test with fake traffic.
+
+	// Passed to namespaced policies, but pod1 is not isolated.
+	action := renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.TCP, 123, 80)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+
+	// Denied by the admin policy.
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.TCP, 123, 443)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.UDP, 123, 80)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+
+	// Not matched by any rule.
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP("10.5.10.10"), parseIP(pod1IP), rendererAPI.TCP, 123, 80)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(UnmatchedTraffic))
+}
+
+func TestAdminDenyRejectedSCTPPorts(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestAdminDenyRejectedSCTPPorts")
+
+	// Prepare input data.
+	const (
+		namespace = "default"
+		pod1Name  = "pod1"
+		pod2Name  = "pod2"
+		pod1IP    = "192.168.1.1"
+		pod2IP    = "192.168.1.2"
+	)
+	pod1 := podmodel.ID{Name: pod1Name, Namespace: namespace}
+	pod2 := podmodel.ID{Name: pod2Name, Namespace: namespace}
+
+	// Namespaced policy allowing everything from pod2.
+	policy1 := &ContivPolicy{
+		ID:   policymodel.ID{Name: "policy1", Namespace: namespace},
+		Type: PolicyIngress,
+		Matches: []Match{
+			{
+				Type: MatchIngress,
+				Pods: []podmodel.ID{
+					pod2,
+				},
+			},
+		},
+	}
+
+	// Admin policy denying access from pod2 on SCTP port 3868 and TCP port 443.
+	adminPolicy := &ContivPolicy{
+		ID:       policymodel.ID{Name: "admin-policy"},
+		Type:     PolicyAll,
+		Admin:    true,
+		Priority: 10,
+		Matches: []Match{
+			{
+				Type:     MatchIngress,
+				Action:   ActionDeny,
+				Pods:     []podmodel.ID{pod2},
+				IPBlocks: []IPBlock{},
+				Ports: []Port{
+					{Protocol: SCTP, Number: 3868},
+					{Protocol: SCTP, Number: 3869},
+					{Protocol: TCP, Number: 443},
+				},
+			},
+		},
+	}
+	pod1Policies := []*ContivPolicy{policy1, adminPolicy}
+
+	// Initialize mocks.
+	cache := NewMockPolicyCache()
+	cache.AddPodConfig(pod1, pod1IP)
+	cache.AddPodConfig(pod2, pod2IP)
+
+	ipam := &ipamMock{}
+	ipam.SetNatLoopbackIP(natLoopbackIP)
+
+	// Renderer unable to match SCTP ports.
+	renderer := NewMockRenderer("A", logger)
+	renderer.DisablePortMatching(rendererAPI.SCTP)
+
+	// Initialize configurator.
+	configurator := &PolicyConfigurator{
+		Deps: Deps{
+			Log:   logger,
+			Cache: cache,
+			IPAM:  ipam,
+		},
+	}
+	configurator.Init(false)
+
+	// Register one renderer.
+	err := configurator.RegisterRenderer(renderer)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// Run single transaction.
+	txn := configurator.NewTxn(false)
+	txn.Configure(pod1, pod1Policies)
+	err = txn.Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// Test with fake traffic.
+
+	// Denied by the admin policy - the SCTP port cannot be matched, the deny
+	// is widened to all SCTP ports instead of falling through to policy1.
+	action := renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.SCTP, 123, 3868)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.SCTP, 123, 5000)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+
+	// Denied by the admin policy - TCP port is matched as requested.
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.TCP, 123, 443)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+
+	// Allowed by policy1.
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.TCP, 123, 80)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+	action = renderer.TestTraffic(pod1, EgressTraffic,
+		parseIP(pod2IP), parseIP(pod1IP), rendererAPI.UDP, 123, 3868)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+}
+
+func TestHostPolicies(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestHostPolicies")
+
+	// Prepare input data.
+	const (
+		nodeIP  = "10.20.0.2"
+		adminIP = "172.16.1.10"
+		otherIP = "172.16.2.10"
+	)
+
+	// Host policy allowing SSH from the admin network.
+	hostPolicy1 := &ContivPolicy{
+		ID:   policymodel.ID{Name: "ssh"},
+		Type: PolicyIngress,
+		Host: true,
+		Matches: []Match{
+			{
+				Type:     MatchIngress,
+				IPBlocks: []IPBlock{{Network: parseIPNet("172.16.1.0/24")}},
+				Ports: []Port{
+					{Protocol: TCP, Number: 22},
+				},
+			},
+		},
+	}
+
+	// Host policy allowing a range of UDP ports from anywhere.
+	hostPolicy2 := &ContivPolicy{
+		ID:   policymodel.ID{Name: "syslog"},
+		Type: PolicyIngress,
+		Host: true,
+		Matches: []Match{
+			{
+				Type: MatchIngress,
+				Ports: []Port{
+					{Protocol: UDP, Number: 514, EndNumber: 516},
+				},
+			},
+		},
+	}
+
+	// Initialize mocks.
+	cache := NewMockPolicyCache()
+	ipam := &ipamMock{}
+	ipam.SetNatLoopbackIP(natLoopbackIP)
+	renderer := NewMockRenderer("A", logger)
+
+	// Initialize configurator.
+	configurator := &PolicyConfigurator{
+		Deps: Deps{
+			Log:   logger,
+			Cache: cache,
+			IPAM:  ipam,
+		},
+	}
+	configurator.Init(false)
+
+	// Register one renderer.
+	err := configurator.RegisterRenderer(renderer)
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// Run transaction with host policies.
+	txn := configurator.NewTxn(false)
+	txn.ConfigureHost([]*ContivPolicy{hostPolicy1, hostPolicy2})
+	err = txn.Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(renderer.IsHostIsolated()).To(gomega.BeTrue())
+
+	// Test with fake traffic.
+
+	// Allowed by the host policies.
+	action := renderer.TestHostTraffic(parseIP(adminIP), parseIP(nodeIP), rendererAPI.TCP, 123, 22)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+	action = renderer.TestHostTraffic(parseIP(otherIP), parseIP(nodeIP), rendererAPI.UDP, 123, 515)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(AllowedTraffic))
+
+	// Denied - the node is isolated.
+	action = renderer.TestHostTraffic(parseIP(otherIP), parseIP(nodeIP), rendererAPI.TCP, 123, 22)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+	action = renderer.TestHostTraffic(parseIP(adminIP), parseIP(nodeIP), rendererAPI.TCP, 123, 80)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+	action = renderer.TestHostTraffic(parseIP(otherIP), parseIP(nodeIP), rendererAPI.UDP, 123, 517)
+	gomega.Expect(action).To(gomega.BeEquivalentTo(DeniedTraffic))
+
+	// Remove the host policies.
+	txn = configurator.NewTxn(false)
+	txn.ConfigureHost(nil)
+	err = txn.Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(renderer.IsHostIsolated()).To(gomega.BeFalse())
+
+	// Transaction without host configuration leaves the host rules unchanged.
+	txn = configurator.NewTxn(false)
+	txn.ConfigureHost([]*ContivPolicy{hostPolicy1})
+	err = txn.Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	txn = configurator.NewTxn(false)
+	err = txn.Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(renderer.IsHostIsolated()).To(gomega.BeTrue())
+}
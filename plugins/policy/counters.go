@@ -0,0 +1,295 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/unrolled/render"
+
+	"github.com/contiv/vpp/plugins/policy/renderer"
+	"github.com/contiv/vpp/plugins/policy/restapi"
+)
+
+const (
+	// names of the prometheus gauges with rule counters aggregated per pod and policy
+	permittedPacketsMetric = "policyPermittedPackets"
+	deniedPacketsMetric    = "policyDeniedPackets"
+
+	podNameLabel      = "podName"
+	podNamespaceLabel = "podNamespace"
+	policyLabel       = "policy"
+
+	matchAny = "ANY"
+)
+
+// initRuleCounters prepares collection of the policy rule counters.
+func (p *Plugin) initRuleCounters() {
+	if p.aclRenderer != nil && p.aclRenderer.ACLCounters != nil {
+		p.ruleCounters = p.aclRenderer
+	}
+	if p.ruleCounters == nil {
+		p.Log.Warn("Policy rule counters are not supported by the renderer")
+		return
+	}
+	p.prevPackets = make(map[string]uint64)
+
+	if p.Stats != nil {
+		var err error
+		p.permittedPackets, err = p.Stats.RegisterGaugeVec(permittedPacketsMetric,
+			"Number of packets permitted by network policies for pod",
+			[]string{podNameLabel, podNamespaceLabel, policyLabel})
+		if err != nil {
+			p.Log.Warnf("Failed to register %s gauges: %v", permittedPacketsMetric, err)
+		}
+		p.deniedPackets, err = p.Stats.RegisterGaugeVec(deniedPacketsMetric,
+			"Number of packets denied by network policies for pod",
+			[]string{podNameLabel, podNamespaceLabel, policyLabel})
+		if err != nil {
+			p.Log.Warnf("Failed to register %s gauges: %v", deniedPacketsMetric, err)
+		}
+	}
+
+	if p.config.RuleCountersInterval > 0 {
+		p.closeCh = make(chan struct{})
+		go p.collectRuleCountersPeriodically()
+	}
+}
+
+// collectRuleCountersPeriodically reads rule counters in the configured interval.
+func (p *Plugin) collectRuleCountersPeriodically() {
+	interval := time.Duration(p.config.RuleCountersInterval) * time.Second
+	for {
+		select {
+		case <-p.closeCh:
+			return
+		case <-time.After(interval):
+			if _, err := p.collectRuleCounters(); err != nil {
+				p.Log.Debugf("Failed to read policy rule counters: %v", err)
+			}
+		}
+	}
+}
+
+// collectRuleCounters reads the current rule counters from the renderer,
+// publishes them into prometheus and records the recent hits of deny rules.
+func (p *Plugin) collectRuleCounters() ([]*renderer.RuleCounter, error) {
+	if p.ruleCounters == nil {
+		return nil, errors.New("policy rule counters are not supported by the renderer")
+	}
+	counters, err := p.ruleCounters.GetRuleCounters()
+	if err != nil {
+		return nil, err
+	}
+
+	p.countersLock.Lock()
+	defer p.countersLock.Unlock()
+
+	// aggregate counters per pod and policy
+	type aggregationKey struct {
+		podName, podNamespace, policy string
+	}
+	permitted := make(map[aggregationKey]uint64)
+	denied := make(map[aggregationKey]uint64)
+	for _, counter := range counters {
+		for _, podID := range counter.Pods {
+			for _, policy := range counter.Policies {
+				key := aggregationKey{podName: podID.Name, podNamespace: podID.Namespace, policy: policy}
+				if counter.Rule.Action == renderer.ActionDeny {
+					denied[key] += counter.Packets
+				} else {
+					permitted[key] += counter.Packets
+				}
+			}
+		}
+	}
+	for vec, values := range map[*prometheus.GaugeVec]map[aggregationKey]uint64{
+		p.permittedPackets: permitted,
+		p.deniedPackets:    denied,
+	} {
+		if vec == nil {
+			continue
+		}
+		// gauges of removed pods and policies are dropped
+		vec.Reset()
+		for key, packets := range values {
+			vec.With(prometheus.Labels{
+				podNameLabel:      key.podName,
+				podNamespaceLabel: key.podNamespace,
+				policyLabel:       key.policy,
+			}).Set(float64(packets))
+		}
+	}
+
+	// record deny rules hit since the last collection
+	now := time.Now()
+	prevPackets := p.prevPackets
+	p.prevPackets = make(map[string]uint64)
+	var hits []*restapi.DeniedRuleHit
+	for _, counter := range counters {
+		counterKey := counter.Table + counter.Rule.String()
+		p.prevPackets[counterKey] = counter.Packets
+		if counter.Rule.Action != renderer.ActionDeny {
+			continue
+		}
+		delta := counter.Packets
+		if prev := prevPackets[counterKey]; prev <= counter.Packets {
+			// otherwise the counter was reset (ACL re-created)
+			delta -= prev
+		}
+		if delta == 0 {
+			continue
+		}
+		hits = append(hits, deniedRuleHit(now, counter, delta))
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Packets > hits[j].Packets
+	})
+	if len(hits) > int(p.config.DenyLogMaxHits) {
+		hits = hits[:p.config.DenyLogMaxHits]
+	}
+	for _, hit := range hits {
+		p.Log.Infof("Deny rule %s -> %s (%s:%s) of policies %v matched %d packet(s) (pods %v, egress=%t)",
+			hit.SrcNetwork, hit.DestNetwork, hit.Protocol, hit.Port, hit.Policies,
+			hit.Packets, hit.Pods, hit.Egress)
+	}
+	p.deniedRuleHits = append(p.deniedRuleHits, hits...)
+	if len(p.deniedRuleHits) > int(p.config.DenyLogSize) {
+		p.deniedRuleHits = p.deniedRuleHits[len(p.deniedRuleHits)-int(p.config.DenyLogSize):]
+	}
+	return counters, nil
+}
+
+// deniedRuleHit describes packets matched by the deny rule from the counter.
+// Only the rule is known, not the actual flows that were denied.
+func deniedRuleHit(now time.Time, counter *renderer.RuleCounter, packets uint64) *restapi.DeniedRuleHit {
+	rule := counter.Rule
+	hit := &restapi.DeniedRuleHit{
+		Time:        now,
+		Pods:        podNames(counter),
+		Egress:      counter.Egress,
+		SrcNetwork:  networkString(rule.SrcNetwork),
+		DestNetwork: networkString(rule.DestNetwork),
+		Protocol:    rule.Protocol.String(),
+		Port:        matchAny,
+		Policies:    counter.Policies,
+		Packets:     packets,
+	}
+	if rule.Protocol != renderer.ANY && rule.DestPort != 0 {
+		hit.Port = strconv.Itoa(int(rule.DestPort))
+		if rule.DestPortEnd > rule.DestPort {
+			hit.Port += "-" + strconv.Itoa(int(rule.DestPortEnd))
+		}
+	}
+	return hit
+}
+
+// registerRESTHandlers registers REST handlers exposing the rule counters,
+// the policy dry-run and the policies of the pods.
+func (p *Plugin) registerRESTHandlers() {
+	if p.HTTPHandlers == nil {
+		p.Log.Warnf("No http handler provided, skipping registration of policy REST handlers")
+		return
+	}
+
+	p.HTTPHandlers.RegisterHTTPHandler(restapi.RestURLPolicyCounters, p.countersGetHandler, "GET")
+	p.Log.Infof("Policy counters REST handler registered: GET %v", restapi.RestURLPolicyCounters)
+	p.HTTPHandlers.RegisterHTTPHandler(restapi.RestURLPolicyCheck, p.checkPostHandler, "POST")
+	p.Log.Infof("Policy dry-run REST handler registered: POST %v", restapi.RestURLPolicyCheck)
+	p.HTTPHandlers.RegisterHTTPHandler(restapi.RestURLPolicyPods, p.podsGetHandler, "GET")
+	p.Log.Infof("Pod policies REST handler registered: GET %v", restapi.RestURLPolicyPods)
+}
+
+func (p *Plugin) countersGetHandler(formatter *render.Render) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		p.Log.Debug("Getting policy rule counters")
+		counters, err := p.collectRuleCounters()
+		if err != nil {
+			p.Log.Errorf("Error reading policy rule counters: %v", err)
+			formatter.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// optional filtering by pod
+		podNamespace := req.URL.Query().Get(restapi.PodNamespaceParam)
+		podName := req.URL.Query().Get(restapi.PodNameParam)
+		podFilter := func(pods []string) bool {
+			if podName == "" && podNamespace == "" {
+				return true
+			}
+			for _, pod := range pods {
+				ns := strings.SplitN(pod, "/", 2)
+				if (podNamespace == "" || ns[0] == podNamespace) &&
+					(podName == "" || (len(ns) == 2 && ns[1] == podName)) {
+					return true
+				}
+			}
+			return false
+		}
+
+		output := restapi.PolicyCounters{
+			NodeName:       p.ServiceLabel.GetAgentLabel(),
+			Rules:          []*restapi.RuleCounter{},
+			DeniedRuleHits: []*restapi.DeniedRuleHit{},
+		}
+		for _, counter := range counters {
+			pods := podNames(counter)
+			if !podFilter(pods) {
+				continue
+			}
+			output.Rules = append(output.Rules, &restapi.RuleCounter{
+				Table:    counter.Table,
+				Rule:     counter.Rule.String(),
+				Action:   counter.Rule.Action.String(),
+				Pods:     pods,
+				Egress:   counter.Egress,
+				Policies: counter.Policies,
+				Packets:  counter.Packets,
+			})
+		}
+		p.countersLock.Lock()
+		for _, hit := range p.deniedRuleHits {
+			if podFilter(hit.Pods) {
+				output.DeniedRuleHits = append(output.DeniedRuleHits, hit)
+			}
+		}
+		p.countersLock.Unlock()
+		formatter.JSON(w, http.StatusOK, output)
+	}
+}
+
+// podNames returns pods of the counter as a list of strings.
+func podNames(counter *renderer.RuleCounter) []string {
+	pods := []string{}
+	for _, podID := range counter.Pods {
+		pods = append(pods, podID.String())
+	}
+	return pods
+}
+
+// networkString returns string representation of the network matched by a rule.
+func networkString(network *net.IPNet) string {
+	if network == nil || len(network.IP) == 0 {
+		return matchAny
+	}
+	return network.String()
+}
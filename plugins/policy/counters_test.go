@@ -0,0 +1,193 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"go.ligato.io/cn-infra/v2/logging/logrus"
+
+	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
+	"github.com/contiv/vpp/plugins/policy/config"
+	"github.com/contiv/vpp/plugins/policy/renderer"
+)
+
+// ruleCountersMock returns the configured counters as if read from the renderer.
+type ruleCountersMock struct {
+	counters []*renderer.RuleCounter
+}
+
+// GetRuleCounters returns the configured counters.
+func (m *ruleCountersMock) GetRuleCounters() ([]*renderer.RuleCounter, error) {
+	return m.counters, nil
+}
+
+func ipNetwork(addr string) *net.IPNet {
+	if addr == "" {
+		return &net.IPNet{}
+	}
+	_, network, _ := net.ParseCIDR(addr)
+	return network
+}
+
+func newCountersTestPlugin(counters *ruleCountersMock, denyLogMaxHits, denyLogSize uint32) *Plugin {
+	p := &Plugin{}
+	p.Log = logrus.DefaultLogger()
+	p.config = config.DefaultConfig()
+	p.config.DenyLogMaxHits = denyLogMaxHits
+	p.config.DenyLogSize = denyLogSize
+	p.ruleCounters = counters
+	p.prevPackets = make(map[string]uint64)
+	return p
+}
+
+func TestDeniedRuleHit(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	pod := podmodel.ID{Name: "pod1", Namespace: "default"}
+	now := time.Now()
+	counter := &renderer.RuleCounter{
+		Table:    "table1",
+		Pods:     []podmodel.ID{pod},
+		Egress:   true,
+		Policies: []string{"default/deny-db"},
+		Rule: &renderer.ContivRule{
+			Action:      renderer.ActionDeny,
+			SrcNetwork:  ipNetwork("10.1.1.1/32"),
+			DestNetwork: ipNetwork(""),
+			Protocol:    renderer.TCP,
+			DestPort:    5432,
+		},
+		Packets: 100,
+	}
+
+	// single port
+	hit := deniedRuleHit(now, counter, 10)
+	gomega.Expect(hit.Time).To(gomega.Equal(now))
+	gomega.Expect(hit.Pods).To(gomega.Equal([]string{pod.String()}))
+	gomega.Expect(hit.Egress).To(gomega.BeTrue())
+	gomega.Expect(hit.SrcNetwork).To(gomega.Equal("10.1.1.1/32"))
+	gomega.Expect(hit.DestNetwork).To(gomega.Equal(matchAny))
+	gomega.Expect(hit.Protocol).To(gomega.Equal(renderer.TCP.String()))
+	gomega.Expect(hit.Port).To(gomega.Equal("5432"))
+	gomega.Expect(hit.Policies).To(gomega.Equal([]string{"default/deny-db"}))
+	gomega.Expect(hit.Packets).To(gomega.BeEquivalentTo(10))
+
+	// port range
+	counter.Rule.DestPortEnd = 5440
+	hit = deniedRuleHit(now, counter, 10)
+	gomega.Expect(hit.Port).To(gomega.Equal("5432-5440"))
+
+	// any port
+	counter.Rule.DestPort = 0
+	counter.Rule.DestPortEnd = 0
+	hit = deniedRuleHit(now, counter, 10)
+	gomega.Expect(hit.Port).To(gomega.Equal(matchAny))
+
+	// ports are not matched for protocol ANY
+	counter.Rule.Protocol = renderer.ANY
+	counter.Rule.DestPort = 80
+	hit = deniedRuleHit(now, counter, 10)
+	gomega.Expect(hit.Port).To(gomega.Equal(matchAny))
+}
+
+func TestCollectRuleCounters(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	pod := podmodel.ID{Name: "pod1", Namespace: "default"}
+	permitRule := &renderer.ContivRule{
+		Action:      renderer.ActionPermit,
+		SrcNetwork:  ipNetwork("10.1.0.0/16"),
+		DestNetwork: ipNetwork(""),
+		Protocol:    renderer.TCP,
+		DestPort:    80,
+	}
+	denyTCPRule := &renderer.ContivRule{
+		Action:      renderer.ActionDeny,
+		SrcNetwork:  ipNetwork("10.1.0.0/16"),
+		DestNetwork: ipNetwork(""),
+		Protocol:    renderer.TCP,
+	}
+	denyAllRule := &renderer.ContivRule{
+		Action:      renderer.ActionDeny,
+		SrcNetwork:  ipNetwork(""),
+		DestNetwork: ipNetwork(""),
+		Protocol:    renderer.ANY,
+	}
+	newCounter := func(table string, rule *renderer.ContivRule, packets uint64) *renderer.RuleCounter {
+		return &renderer.RuleCounter{
+			Table:    table,
+			Rule:     rule,
+			Pods:     []podmodel.ID{pod},
+			Policies: []string{"default/policy1"},
+			Packets:  packets,
+		}
+	}
+
+	counters := &ruleCountersMock{}
+	p := newCountersTestPlugin(counters, 2, 3)
+
+	// first collection - all the packets are new
+	counters.counters = []*renderer.RuleCounter{
+		newCounter("table1", permitRule, 50),
+		newCounter("table1", denyTCPRule, 5),
+		newCounter("table1", denyAllRule, 20),
+		newCounter("table2", denyAllRule, 0),
+	}
+	collected, err := p.collectRuleCounters()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(collected).To(gomega.Equal(counters.counters))
+	gomega.Expect(p.deniedRuleHits).To(gomega.HaveLen(2))
+	// -> sorted by the number of packets, permit rules are not recorded
+	gomega.Expect(p.deniedRuleHits[0].Packets).To(gomega.BeEquivalentTo(20))
+	gomega.Expect(p.deniedRuleHits[0].Protocol).To(gomega.Equal(renderer.ANY.String()))
+	gomega.Expect(p.deniedRuleHits[1].Packets).To(gomega.BeEquivalentTo(5))
+	gomega.Expect(p.deniedRuleHits[1].SrcNetwork).To(gomega.Equal("10.1.0.0/16"))
+
+	// second collection - only the packets since the previous collection are recorded,
+	// the same rule is counted separately for each table
+	counters.counters = []*renderer.RuleCounter{
+		newCounter("table1", permitRule, 80),
+		newCounter("table1", denyTCPRule, 5),
+		newCounter("table1", denyAllRule, 23),
+		newCounter("table2", denyAllRule, 7),
+	}
+	_, err = p.collectRuleCounters()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(p.deniedRuleHits).To(gomega.HaveLen(3))
+	gomega.Expect(p.deniedRuleHits[0].Packets).To(gomega.BeEquivalentTo(5))
+	gomega.Expect(p.deniedRuleHits[1].Packets).To(gomega.BeEquivalentTo(7))
+	gomega.Expect(p.deniedRuleHits[2].Packets).To(gomega.BeEquivalentTo(3))
+
+	// third collection - counter reset (ACL re-created), the number of logged hits
+	// and the size of the log are limited
+	counters.counters = []*renderer.RuleCounter{
+		newCounter("table1", denyTCPRule, 2),
+		newCounter("table1", denyAllRule, 25),
+		newCounter("table2", denyAllRule, 17),
+	}
+	_, err = p.collectRuleCounters()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(p.deniedRuleHits).To(gomega.HaveLen(3))
+	gomega.Expect(p.deniedRuleHits[0].Packets).To(gomega.BeEquivalentTo(3))
+	gomega.Expect(p.deniedRuleHits[1].Packets).To(gomega.BeEquivalentTo(10))
+	gomega.Expect(p.deniedRuleHits[2].Packets).To(gomega.BeEquivalentTo(2))
+	gomega.Expect(p.deniedRuleHits[2].Protocol).To(gomega.Equal(renderer.TCP.String()))
+	gomega.Expect(p.prevPackets).To(gomega.HaveLen(3))
+}
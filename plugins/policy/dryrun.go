@@ -0,0 +1,47 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/unrolled/render"
+
+	"github.com/contiv/vpp/plugins/policy/restapi"
+)
+
+// checkPostHandler evaluates the traffic from the request against the current
+// policies combined with the candidate policies, without changing the data plane.
+func (p *Plugin) checkPostHandler(formatter *render.Render) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		var checkReq restapi.PolicyCheckRequest
+		if err := json.NewDecoder(req.Body).Decode(&checkReq); err != nil {
+			formatter.JSON(w, http.StatusBadRequest, "failed to parse request: "+err.Error())
+			return
+		}
+		p.Log.Debugf("Policy dry-run: %+v", checkReq)
+
+		p.stateLock.Lock()
+		resp, err := p.dryRun.Check(&checkReq)
+		p.stateLock.Unlock()
+		if err != nil {
+			p.Log.Errorf("Policy dry-run failed: %v", err)
+			formatter.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		formatter.JSON(w, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,333 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dryrun
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"go.ligato.io/cn-infra/v2/logging"
+
+	controller "github.com/contiv/vpp/plugins/controller/api"
+	"github.com/contiv/vpp/plugins/ksr/model/adminpolicy"
+	nsmodel "github.com/contiv/vpp/plugins/ksr/model/namespace"
+	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
+	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
+	"github.com/contiv/vpp/plugins/policy/cache"
+	"github.com/contiv/vpp/plugins/policy/configurator"
+	"github.com/contiv/vpp/plugins/policy/processor"
+	"github.com/contiv/vpp/plugins/policy/renderer"
+	"github.com/contiv/vpp/plugins/policy/restapi"
+)
+
+// DryRun evaluates network policies for a given traffic without touching
+// the data plane. For every check, the policy processor and configurator
+// are instantiated in a sandbox, fed with a snapshot of the current K8s state
+// extended with candidate policies, and the rules they would render for
+// the source and the destination pod are evaluated with the first-match
+// semantic of the renderers.
+type DryRun struct {
+	Deps
+}
+
+// Deps lists dependencies of DryRun.
+type Deps struct {
+	Log logging.Logger
+
+	// Cache with the current K8s state, must not be modified while Check()
+	// is running.
+	Cache cache.PolicyCacheAPI
+
+	IPAM         configurator.IPAM
+	FQDNResolver processor.FQDNResolver   /* optional, needed for egress rules with DNS names */
+	PortMatching renderer.PortMatchingAPI /* optional, port matching restrictions of the renderer */
+}
+
+// Check evaluates the traffic described by the request.
+func (d *DryRun) Check(req *restapi.PolicyCheckRequest) (*restapi.PolicyCheckResponse, error) {
+	protocol, err := parseProtocol(req.Protocol)
+	if err != nil {
+		return nil, err
+	}
+	state := d.snapshot(req.Candidates)
+
+	srcPod, srcIP, err := resolveEndpoint(state, req.Source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source: %v", err)
+	}
+	dstPod, dstIP, err := resolveEndpoint(state, req.Destination)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination: %v", err)
+	}
+
+	rules, err := d.renderRules(state)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &restapi.PolicyCheckResponse{Allowed: true}
+	if srcPod != nil {
+		// traffic sent by the pod enters the vswitch (ingress rules)
+		resp.Egress = evaluate(*srcPod, rules[*srcPod].ingress, dstIP, protocol, req.Port,
+			func(rule *renderer.ContivRule) *net.IPNet { return rule.DestNetwork })
+		resp.Allowed = resp.Egress.Allowed
+	}
+	if dstPod != nil {
+		// traffic received by the pod leaves the vswitch (egress rules)
+		resp.Ingress = evaluate(*dstPod, rules[*dstPod].egress, srcIP, protocol, req.Port,
+			func(rule *renderer.ContivRule) *net.IPNet { return rule.SrcNetwork })
+		resp.Allowed = resp.Allowed && resp.Ingress.Allowed
+	}
+	return resp, nil
+}
+
+// snapshot returns the current K8s state with the candidate policies applied.
+func (d *DryRun) snapshot(candidates []*policymodel.Policy) controller.KubeStateData {
+	pods := make(controller.KeyValuePairs)
+	for _, podID := range d.Cache.ListAllPods() {
+		if found, pod := d.Cache.LookupPod(podID); found {
+			pods[podmodel.Key(podID.Name, podID.Namespace)] = pod
+		}
+	}
+	namespaces := make(controller.KeyValuePairs)
+	for _, nsID := range d.Cache.ListAllNamespaces() {
+		if found, ns := d.Cache.LookupNamespace(nsID); found {
+			namespaces[nsmodel.Key(nsID.String())] = ns
+		}
+	}
+	policies := make(controller.KeyValuePairs)
+	for _, policyID := range d.Cache.ListAllPolicies() {
+		if found, policy := d.Cache.LookupPolicy(policyID); found {
+			policies[policymodel.Key(policyID.Name, policyID.Namespace)] = policy
+		}
+	}
+	for _, policy := range candidates {
+		policies[policymodel.Key(policy.Name, policy.Namespace)] = policy
+	}
+	adminPolicies := make(controller.KeyValuePairs)
+	for _, name := range d.Cache.ListAllAdminPolicies() {
+		if found, policy := d.Cache.LookupAdminPolicy(name); found {
+			adminPolicies[adminpolicy.Key(name)] = policy
+		}
+	}
+	return controller.KubeStateData{
+		podmodel.PodKeyword:            pods,
+		nsmodel.NamespaceKeyword:       namespaces,
+		policymodel.PolicyKeyword:      policies,
+		adminpolicy.AdminPolicyKeyword: adminPolicies,
+	}
+}
+
+// renderRules runs the policy processor and configurator in a sandbox
+// and returns the rules rendered for every pod.
+func (d *DryRun) renderRules(state controller.KubeStateData) (map[podmodel.ID]podRules, error) {
+	sandboxCache := &cache.PolicyCache{
+		Deps: cache.Deps{
+			Log: d.Log,
+		},
+	}
+	sandboxConfigurator := &configurator.PolicyConfigurator{
+		Deps: configurator.Deps{
+			Log:   d.Log,
+			Cache: sandboxCache,
+			IPAM:  d.IPAM,
+		},
+	}
+	sandboxProcessor := &processor.PolicyProcessor{
+		Deps: processor.Deps{
+			Log:          d.Log,
+			Cache:        sandboxCache,
+			Configurator: sandboxConfigurator,
+			AllNodes:     true,
+		},
+	}
+	if d.FQDNResolver != nil {
+		sandboxProcessor.FQDNResolver = readOnlyResolver{d.FQDNResolver}
+	}
+	recorder := &ruleRecorder{
+		rules:        make(map[podmodel.ID]podRules),
+		portMatching: d.PortMatching,
+	}
+
+	sandboxCache.Init()
+	sandboxProcessor.Init()
+	sandboxConfigurator.Init(false)
+	sandboxConfigurator.RegisterRenderer(recorder)
+
+	if err := sandboxCache.Resync(state); err != nil {
+		return nil, err
+	}
+	return recorder.rules, nil
+}
+
+// resolveEndpoint returns pod identified by the given endpoint (nil if the endpoint
+// is not a pod) and its IP address.
+func resolveEndpoint(state controller.KubeStateData, endpoint string) (*podmodel.ID, net.IP, error) {
+	if ip := net.ParseIP(endpoint); ip != nil {
+		// IP address of a pod is evaluated as the pod
+		for _, podProto := range state[podmodel.PodKeyword] {
+			pod := podProto.(*podmodel.Pod)
+			if podIP := net.ParseIP(pod.IpAddress); podIP != nil && podIP.Equal(ip) {
+				podID := podmodel.GetID(pod)
+				return &podID, ip, nil
+			}
+		}
+		return nil, ip, nil
+	}
+	nsName := strings.SplitN(endpoint, "/", 2)
+	if len(nsName) != 2 || nsName[0] == "" || nsName[1] == "" {
+		return nil, nil, fmt.Errorf("%q is neither IP address nor pod (namespace/name)", endpoint)
+	}
+	podProto, found := state[podmodel.PodKeyword][podmodel.Key(nsName[1], nsName[0])]
+	if !found {
+		return nil, nil, fmt.Errorf("pod %s not found", endpoint)
+	}
+	pod := podProto.(*podmodel.Pod)
+	ip := net.ParseIP(pod.IpAddress)
+	if ip == nil {
+		return nil, nil, fmt.Errorf("pod %s does not have an IP address assigned", endpoint)
+	}
+	podID := podmodel.GetID(pod)
+	return &podID, ip, nil
+}
+
+// evaluate finds the first rule matching the traffic exchanged with the given
+// peer. Rules are evaluated in the same order as by the renderers. Traffic
+// not matched by any rule is allowed (the renderers append an allow-all rule),
+// i.e. it is only denied by the deny-the-rest rule of namespaced policies
+// isolating the pod.
+func evaluate(pod podmodel.ID, rules []*renderer.ContivRule, peer net.IP, protocol renderer.ProtocolType,
+	port uint16, peerNetwork func(rule *renderer.ContivRule) *net.IPNet) *restapi.PolicyCheckVerdict {
+
+	verdict := &restapi.PolicyCheckVerdict{
+		Pod:     pod.String(),
+		Allowed: true,
+	}
+	for _, rule := range rules {
+		if isDenyTheRest(rule) {
+			verdict.Isolated = true
+			break
+		}
+	}
+	ordered := make([]*renderer.ContivRule, len(rules))
+	copy(ordered, rules)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Compare(ordered[j]) < 0
+	})
+	for _, rule := range ordered {
+		if peerNet := peerNetwork(rule); peerNet != nil && len(peerNet.IP) > 0 && !peerNet.Contains(peer) {
+			continue
+		}
+		if rule.Protocol != renderer.ANY {
+			if rule.Protocol != protocol || rule.SrcPort != 0 {
+				continue
+			}
+			if rule.DestPort != 0 {
+				portEnd := rule.DestPort
+				if rule.DestPortEnd > portEnd {
+					portEnd = rule.DestPortEnd
+				}
+				if port < rule.DestPort || port > portEnd {
+					continue
+				}
+			}
+		}
+		verdict.Allowed = rule.Action == renderer.ActionPermit
+		verdict.Rule = rule.String()
+		verdict.Policies = rule.Policies
+		return verdict
+	}
+	// not matched by any rule
+	return verdict
+}
+
+// isDenyTheRest returns true if the given rule is the deny-the-rest rule
+// of namespaced policies isolating a pod.
+func isDenyTheRest(rule *renderer.ContivRule) bool {
+	return rule.Priority == renderer.NamespacedTierPriority && rule.Action == renderer.ActionDeny &&
+		rule.Protocol == renderer.ANY && rule.SrcPort == 0 && rule.DestPort == 0 &&
+		(rule.SrcNetwork == nil || len(rule.SrcNetwork.IP) == 0) &&
+		(rule.DestNetwork == nil || len(rule.DestNetwork.IP) == 0)
+}
+
+// parseProtocol converts protocol name into the renderer protocol type.
+func parseProtocol(protocol string) (renderer.ProtocolType, error) {
+	switch strings.ToUpper(protocol) {
+	case "", "TCP":
+		return renderer.TCP, nil
+	case "UDP":
+		return renderer.UDP, nil
+	case "SCTP":
+		return renderer.SCTP, nil
+	}
+	return renderer.TCP, fmt.Errorf("unsupported protocol %q", protocol)
+}
+
+// podRules are rules rendered for a single pod (directions from the vswitch
+// point of view).
+type podRules struct {
+	ingress []*renderer.ContivRule
+	egress  []*renderer.ContivRule
+}
+
+// ruleRecorder is a renderer which only remembers the rules.
+type ruleRecorder struct {
+	rules        map[podmodel.ID]podRules
+	portMatching renderer.PortMatchingAPI
+}
+
+// CanMatchPorts applies the port matching restrictions of the real renderer,
+// so that the rules rejected by the configurator are rejected also in the sandbox.
+func (r *ruleRecorder) CanMatchPorts(protocol renderer.ProtocolType) bool {
+	if r.portMatching == nil {
+		return true
+	}
+	return r.portMatching.CanMatchPorts(protocol)
+}
+
+// NewTxn returns transaction recording the rendered rules.
+func (r *ruleRecorder) NewTxn(resync bool) renderer.Txn {
+	return r
+}
+
+// Render remembers the rules of the pod.
+func (r *ruleRecorder) Render(pod podmodel.ID, podIP *net.IPNet, ingress []*renderer.ContivRule,
+	egress []*renderer.ContivRule, removed bool) renderer.Txn {
+	if removed {
+		delete(r.rules, pod)
+	} else {
+		r.rules[pod] = podRules{ingress: ingress, egress: egress}
+	}
+	return r
+}
+
+// Commit does nothing.
+func (r *ruleRecorder) Commit() error {
+	return nil
+}
+
+// readOnlyResolver provides addresses of the running resolver without
+// changing the set of resolved DNS names.
+type readOnlyResolver struct {
+	processor.FQDNResolver
+}
+
+// SetPolicyFQDNs does nothing.
+func (readOnlyResolver) SetPolicyFQDNs(policy policymodel.ID, fqdns []string) {}
+
+// ResyncPolicyFQDNs does nothing.
+func (readOnlyResolver) ResyncPolicyFQDNs(fqdns map[policymodel.ID][]string) {}
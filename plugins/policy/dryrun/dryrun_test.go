@@ -0,0 +1,302 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dryrun
+
+import (
+	"net"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"go.ligato.io/cn-infra/v2/logging"
+	"go.ligato.io/cn-infra/v2/logging/logrus"
+
+	controller "github.com/contiv/vpp/plugins/controller/api"
+	"github.com/contiv/vpp/plugins/ksr/model/adminpolicy"
+	nsmodel "github.com/contiv/vpp/plugins/ksr/model/namespace"
+	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
+	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
+	"github.com/contiv/vpp/plugins/policy/cache"
+	"github.com/contiv/vpp/plugins/policy/restapi"
+)
+
+const (
+	namespace = "default"
+	natLoopIP = "10.1.1.254"
+)
+
+// ipamMock implements the IPAM methods needed by the configurator.
+type ipamMock struct{}
+
+func (ipamMock) NatLoopbackIP() net.IP {
+	return net.ParseIP(natLoopIP)
+}
+
+func pod(name, ip, app string) *podmodel.Pod {
+	return &podmodel.Pod{
+		Name:      name,
+		Namespace: namespace,
+		IpAddress: ip,
+		Label:     []*podmodel.Pod_Label{{Key: "app", Value: app}},
+	}
+}
+
+func selectApp(app string) *policymodel.Policy_LabelSelector {
+	return &policymodel.Policy_LabelSelector{
+		MatchLabel: []*policymodel.Policy_Label{{Key: "app", Value: app}},
+	}
+}
+
+func tcpPort(port int32) *policymodel.Policy_Port {
+	return &policymodel.Policy_Port{
+		Protocol: policymodel.Policy_Port_TCP,
+		Port: &policymodel.Policy_Port_PortNameOrNumber{
+			Type:   policymodel.Policy_Port_PortNameOrNumber_NUMBER,
+			Number: port,
+		},
+	}
+}
+
+// allowFrom returns policy allowing access to <app> only from <peerApp> on the given TCP port.
+func allowFrom(name, app, peerApp string, port int32) *policymodel.Policy {
+	return &policymodel.Policy{
+		Name:       name,
+		Namespace:  namespace,
+		Pods:       selectApp(app),
+		PolicyType: policymodel.Policy_INGRESS,
+		IngressRule: []*policymodel.Policy_IngressRule{
+			{
+				Port: []*policymodel.Policy_Port{tcpPort(port)},
+				From: []*policymodel.Policy_Peer{{Pods: selectApp(peerApp)}},
+			},
+		},
+	}
+}
+
+// denyFrom returns admin policy denying access to <app> from <peerApp> on the given TCP port.
+func denyFrom(name, app, peerApp string, port int32) *adminpolicy.AdminPolicy {
+	selectAdminApp := func(app string) *adminpolicy.AdminPolicy_PodSelector {
+		return &adminpolicy.AdminPolicy_PodSelector{
+			Namespaces: &adminpolicy.AdminPolicy_LabelSelector{},
+			Pods: &adminpolicy.AdminPolicy_LabelSelector{
+				MatchLabel: []*adminpolicy.AdminPolicy_Label{{Key: "app", Value: app}},
+			},
+		}
+	}
+	return &adminpolicy.AdminPolicy{
+		Name:     name,
+		Priority: 10,
+		Subject:  &adminpolicy.AdminPolicy_Subject{Pods: selectAdminApp(app)},
+		IngressRule: []*adminpolicy.AdminPolicy_Rule{
+			{
+				Action: adminpolicy.AdminPolicy_DENY,
+				Peers:  []*adminpolicy.AdminPolicy_Peer{{Pods: selectAdminApp(peerApp)}},
+				Ports:  []*adminpolicy.AdminPolicy_Port{{Protocol: adminpolicy.AdminPolicy_Port_TCP, Port: port}},
+			},
+		},
+	}
+}
+
+func newTestDryRun(policies ...*policymodel.Policy) *DryRun {
+	return newTestDryRunWithAdminPolicies(nil, policies...)
+}
+
+func newTestDryRunWithAdminPolicies(adminPolicies []*adminpolicy.AdminPolicy, policies ...*policymodel.Policy) *DryRun {
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+
+	state := controller.KubeStateData{
+		podmodel.PodKeyword: controller.KeyValuePairs{
+			podmodel.Key("web", namespace): pod("web", "10.1.1.1", "web"),
+			podmodel.Key("db", namespace):  pod("db", "10.1.1.2", "db"),
+			// pod deployed on other node
+			podmodel.Key("client", namespace): pod("client", "10.1.2.1", "client"),
+		},
+		nsmodel.NamespaceKeyword: controller.KeyValuePairs{
+			nsmodel.Key(namespace): &nsmodel.Namespace{Name: namespace},
+		},
+		policymodel.PolicyKeyword:      controller.KeyValuePairs{},
+		adminpolicy.AdminPolicyKeyword: controller.KeyValuePairs{},
+	}
+	for _, policy := range policies {
+		state[policymodel.PolicyKeyword][policymodel.Key(policy.Name, policy.Namespace)] = policy
+	}
+	for _, policy := range adminPolicies {
+		state[adminpolicy.AdminPolicyKeyword][adminpolicy.Key(policy.Name)] = policy
+	}
+	liveCache := &cache.PolicyCache{Deps: cache.Deps{Log: logger}}
+	liveCache.Init()
+	gomega.Expect(liveCache.Resync(state)).To(gomega.Succeed())
+
+	return &DryRun{
+		Deps: Deps{
+			Log:   logger,
+			Cache: liveCache,
+			IPAM:  ipamMock{},
+		},
+	}
+}
+
+func TestNoPolicies(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	dryRun := newTestDryRun()
+
+	resp, err := dryRun.Check(&restapi.PolicyCheckRequest{
+		Source:      "default/web",
+		Destination: "default/db",
+		Port:        5432,
+	})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(resp.Allowed).To(gomega.BeTrue())
+	gomega.Expect(resp.Egress.Isolated).To(gomega.BeFalse())
+	gomega.Expect(resp.Ingress.Isolated).To(gomega.BeFalse())
+}
+
+func TestCandidatePolicy(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	dryRun := newTestDryRun(allowFrom("db-access", "db", "web", 5432))
+
+	// allowed by the current policy
+	resp, err := dryRun.Check(&restapi.PolicyCheckRequest{
+		Source:      "default/web",
+		Destination: "10.1.1.2",
+		Protocol:    "tcp",
+		Port:        5432,
+	})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(resp.Allowed).To(gomega.BeTrue())
+	gomega.Expect(resp.Egress.Isolated).To(gomega.BeFalse())
+	gomega.Expect(resp.Ingress.Pod).To(gomega.Equal("default/db"))
+	gomega.Expect(resp.Ingress.Isolated).To(gomega.BeTrue())
+	gomega.Expect(resp.Ingress.Policies).To(gomega.Equal([]string{"default/db-access"}))
+
+	// other port and other client (from any node) are denied
+	resp, err = dryRun.Check(&restapi.PolicyCheckRequest{
+		Source:      "default/web",
+		Destination: "default/db",
+		Port:        22,
+	})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(resp.Allowed).To(gomega.BeFalse())
+	resp, err = dryRun.Check(&restapi.PolicyCheckRequest{
+		Source:      "default/client",
+		Destination: "default/db",
+		Port:        5432,
+	})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(resp.Allowed).To(gomega.BeFalse())
+
+	// candidate policy replacing the current one
+	resp, err = dryRun.Check(&restapi.PolicyCheckRequest{
+		Source:      "default/client",
+		Destination: "default/db",
+		Port:        5432,
+		Candidates:  []*policymodel.Policy{allowFrom("db-access", "db", "client", 5432)},
+	})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(resp.Allowed).To(gomega.BeTrue())
+	gomega.Expect(resp.Ingress.Rule).ToNot(gomega.BeEmpty())
+	resp, err = dryRun.Check(&restapi.PolicyCheckRequest{
+		Source:      "default/web",
+		Destination: "default/db",
+		Port:        5432,
+		Candidates:  []*policymodel.Policy{allowFrom("db-access", "db", "client", 5432)},
+	})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(resp.Allowed).To(gomega.BeFalse())
+
+	// the live state is not changed by the candidates
+	found, policy := dryRun.Cache.LookupPolicy(policymodel.ID{Namespace: namespace, Name: "db-access"})
+	gomega.Expect(found).To(gomega.BeTrue())
+	gomega.Expect(policy.IngressRule[0].From[0].Pods.MatchLabel[0].Value).To(gomega.Equal("web"))
+
+	// traffic from outside of the cluster
+	resp, err = dryRun.Check(&restapi.PolicyCheckRequest{
+		Source:      "192.168.1.1",
+		Destination: "default/db",
+		Port:        5432,
+	})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(resp.Egress).To(gomega.BeNil())
+	gomega.Expect(resp.Allowed).To(gomega.BeFalse())
+}
+
+func TestAdminPolicyOnly(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	dryRun := newTestDryRunWithAdminPolicies(
+		[]*adminpolicy.AdminPolicy{denyFrom("deny-ssh", "db", "web", 22)})
+
+	// denied by the admin policy
+	resp, err := dryRun.Check(&restapi.PolicyCheckRequest{
+		Source:      "default/web",
+		Destination: "default/db",
+		Port:        22,
+	})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(resp.Allowed).To(gomega.BeFalse())
+	gomega.Expect(resp.Ingress.Isolated).To(gomega.BeFalse())
+	gomega.Expect(resp.Ingress.Policies).To(gomega.Equal([]string{"deny-ssh"}))
+
+	// traffic not matched by the admin policy is allowed - admin policies
+	// alone do not isolate the pod
+	resp, err = dryRun.Check(&restapi.PolicyCheckRequest{
+		Source:      "default/web",
+		Destination: "default/db",
+		Port:        5432,
+	})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(resp.Allowed).To(gomega.BeTrue())
+	gomega.Expect(resp.Ingress.Isolated).To(gomega.BeFalse())
+	gomega.Expect(resp.Ingress.Rule).To(gomega.BeEmpty())
+	resp, err = dryRun.Check(&restapi.PolicyCheckRequest{
+		Source:      "default/client",
+		Destination: "default/db",
+		Port:        22,
+	})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(resp.Allowed).To(gomega.BeTrue())
+
+	// namespaced policy isolates the pod, the admin policy still takes precedence
+	resp, err = dryRun.Check(&restapi.PolicyCheckRequest{
+		Source:      "default/web",
+		Destination: "default/db",
+		Port:        22,
+		Candidates:  []*policymodel.Policy{allowFrom("db-access", "db", "web", 22)},
+	})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(resp.Allowed).To(gomega.BeFalse())
+	gomega.Expect(resp.Ingress.Isolated).To(gomega.BeTrue())
+	gomega.Expect(resp.Ingress.Policies).To(gomega.Equal([]string{"deny-ssh"}))
+	resp, err = dryRun.Check(&restapi.PolicyCheckRequest{
+		Source:      "default/web",
+		Destination: "default/db",
+		Port:        5432,
+		Candidates:  []*policymodel.Policy{allowFrom("db-access", "db", "web", 22)},
+	})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	gomega.Expect(resp.Allowed).To(gomega.BeFalse())
+}
+
+func TestInvalidRequest(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	dryRun := newTestDryRun()
+
+	_, err := dryRun.Check(&restapi.PolicyCheckRequest{Source: "default/unknown", Destination: "default/db"})
+	gomega.Expect(err).To(gomega.HaveOccurred())
+	_, err = dryRun.Check(&restapi.PolicyCheckRequest{Source: "web", Destination: "default/db"})
+	gomega.Expect(err).To(gomega.HaveOccurred())
+	_, err = dryRun.Check(&restapi.PolicyCheckRequest{Source: "default/web", Destination: "default/db", Protocol: "icmp"})
+	gomega.Expect(err).To(gomega.HaveOccurred())
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fqdn
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// DefaultResolvConf is the file from which the nameservers are read
+	// if not configured explicitly.
+	DefaultResolvConf = "/etc/resolv.conf"
+
+	// dnsPort is the default port of the nameservers.
+	dnsPort = "53"
+
+	// maxMessageSize is the maximum size of DNS messages sent over UDP.
+	maxMessageSize = 512
+)
+
+// Address is an IP address resolved for a DNS name, valid for the given TTL.
+type Address struct {
+	IP  net.IP
+	TTL time.Duration
+}
+
+// DNSClient is used by the resolver to query nameservers.
+type DNSClient interface {
+	// Resolve returns all IPv4 and IPv6 addresses of the given DNS name.
+	Resolve(fqdn string) ([]Address, error)
+}
+
+// UDPClient implements DNSClient by sending A and AAAA queries over UDP
+// to the given nameservers, until one of them answers.
+type UDPClient struct {
+	// Servers are addresses of the nameservers, optionally with port.
+	Servers []string
+
+	// Timeout of a single query.
+	Timeout time.Duration
+}
+
+// Resolve returns all IPv4 and IPv6 addresses of the given DNS name.
+func (c *UDPClient) Resolve(fqdn string) ([]Address, error) {
+	if len(c.Servers) == 0 {
+		return nil, errors.New("no nameserver configured")
+	}
+	name, err := dnsmessage.NewName(fqdn + ".")
+	if err != nil {
+		return nil, err
+	}
+	var lastErr error
+	for _, server := range c.Servers {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, dnsPort)
+		}
+		var addrs []Address
+		for _, qtype := range []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA} {
+			var qAddrs []Address
+			qAddrs, lastErr = c.query(server, name, qtype)
+			if lastErr != nil {
+				break
+			}
+			addrs = append(addrs, qAddrs...)
+		}
+		if lastErr == nil {
+			return addrs, nil
+		}
+	}
+	return nil, lastErr
+}
+
+// query sends a single query to the given nameserver.
+func (c *UDPClient) query(server string, name dnsmessage.Name, qtype dnsmessage.Type) ([]Address, error) {
+	id := uint16(rand.Uint32())
+	builder := dnsmessage.NewBuilder(make([]byte, 0, maxMessageSize),
+		dnsmessage.Header{ID: id, RecursionDesired: true})
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := builder.Question(dnsmessage.Question{Name: name, Type: qtype, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, err
+	}
+	req, err := builder.Finish()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout("udp", server, c.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(c.Timeout))
+	if _, err := conn.Write(req); err != nil {
+		return nil, err
+	}
+	resp := make([]byte, maxMessageSize)
+	for {
+		n, err := conn.Read(resp)
+		if err != nil {
+			return nil, err
+		}
+		var parser dnsmessage.Parser
+		header, err := parser.Start(resp[:n])
+		if err != nil || header.ID != id || !header.Response {
+			// not a response to our query
+			continue
+		}
+		if header.RCode == dnsmessage.RCodeNameError {
+			return nil, nil
+		}
+		if header.RCode != dnsmessage.RCodeSuccess {
+			return nil, fmt.Errorf("nameserver %s failed to resolve %s: %v", server, name, header.RCode)
+		}
+		return parseAnswers(&parser)
+	}
+}
+
+// parseAnswers returns addresses from the answer section of a DNS response.
+// Records of the whole CNAME chain are included.
+func parseAnswers(parser *dnsmessage.Parser) ([]Address, error) {
+	if err := parser.SkipAllQuestions(); err != nil {
+		return nil, err
+	}
+	var addrs []Address
+	for {
+		header, err := parser.AnswerHeader()
+		if err == dnsmessage.ErrSectionDone {
+			return addrs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		ttl := time.Duration(header.TTL) * time.Second
+		switch header.Type {
+		case dnsmessage.TypeA:
+			record, err := parser.AResource()
+			if err != nil {
+				return nil, err
+			}
+			addrs = append(addrs, Address{IP: net.IP(record.A[:]), TTL: ttl})
+		case dnsmessage.TypeAAAA:
+			record, err := parser.AAAAResource()
+			if err != nil {
+				return nil, err
+			}
+			addrs = append(addrs, Address{IP: net.IP(record.AAAA[:]), TTL: ttl})
+		default:
+			if err := parser.SkipAnswer(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// ReadNameservers returns nameservers listed in the given resolv.conf file.
+func ReadNameservers(resolvConf string) ([]string, error) {
+	file, err := os.Open(resolvConf)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var servers []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			servers = append(servers, fields[1])
+		}
+	}
+	return servers, scanner.Err()
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fqdn
+
+import (
+	"fmt"
+
+	controller "github.com/contiv/vpp/plugins/controller/api"
+)
+
+// AddressChange is triggered when addresses resolved for some of the DNS names
+// referenced by network policies change.
+type AddressChange struct {
+	FQDNs []string
+}
+
+// GetName returns name of the AddressChange event.
+func (ev *AddressChange) GetName() string {
+	return "FQDN Address Change"
+}
+
+// String describes AddressChange event.
+func (ev *AddressChange) String() string {
+	return fmt.Sprintf("%s\n"+
+		"* FQDNs: %v",
+		ev.GetName(), ev.FQDNs)
+}
+
+// Method is Update.
+func (ev *AddressChange) Method() controller.EventMethodType {
+	return controller.Update
+}
+
+// TransactionType is BestEffort.
+func (ev *AddressChange) TransactionType() controller.UpdateTransactionType {
+	return controller.BestEffort
+}
+
+// Direction is Forward.
+func (ev *AddressChange) Direction() controller.UpdateDirectionType {
+	return controller.Forward
+}
+
+// IsBlocking returns false.
+func (ev *AddressChange) IsBlocking() bool {
+	return false
+}
+
+// Done is NOOP.
+func (ev *AddressChange) Done(error) {
+	return
+}
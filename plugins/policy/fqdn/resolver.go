@@ -0,0 +1,283 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fqdn
+
+import (
+	"bytes"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"go.ligato.io/cn-infra/v2/logging"
+
+	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
+)
+
+// Resolver periodically resolves DNS names referenced by network policies.
+// Every resolved address is remembered until the TTL of its DNS record
+// expires, names are re-resolved before the expiration so that addresses
+// still returned by nameservers remain valid without interruption.
+// Whenever the set of valid addresses of some names changes, the resolver
+// reports the names via the OnChange callback (called from a go routine
+// of the resolver).
+type Resolver struct {
+	Deps
+
+	sync.Mutex
+	entries  map[string]*entry           // fqdn -> resolved addresses
+	policies map[policymodel.ID][]string // policy -> referenced names
+
+	wakeCh  chan struct{}
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+}
+
+// Deps lists dependencies of the Resolver.
+type Deps struct {
+	Log       logging.Logger
+	DNSClient DNSClient
+
+	// MinTTL and MaxTTL bound the TTLs of the DNS records.
+	// MinTTL is also the interval at which failed queries are retried.
+	MinTTL time.Duration
+	MaxTTL time.Duration
+
+	// OnChange is called with the names whose addresses have changed.
+	OnChange func(fqdns []string)
+}
+
+// entry holds addresses resolved for a single DNS name.
+type entry struct {
+	addrs       map[string]time.Time // IP -> expiration
+	nextRefresh time.Time
+}
+
+// Init initializes the resolver and starts the refresh loop.
+func (r *Resolver) Init() {
+	r.entries = make(map[string]*entry)
+	r.policies = make(map[policymodel.ID][]string)
+	r.wakeCh = make(chan struct{}, 1)
+	r.closeCh = make(chan struct{})
+	r.wg.Add(1)
+	go r.refreshLoop()
+}
+
+// Close stops the refresh loop.
+func (r *Resolver) Close() error {
+	if r.closeCh == nil {
+		// not initialized
+		return nil
+	}
+	close(r.closeCh)
+	r.wg.Wait()
+	return nil
+}
+
+// SetPolicyFQDNs updates the set of DNS names referenced by a given policy.
+// Empty set means that the policy was removed or that it does not reference
+// any names.
+func (r *Resolver) SetPolicyFQDNs(policy policymodel.ID, fqdns []string) {
+	r.Lock()
+	defer r.Unlock()
+	if len(fqdns) == 0 {
+		delete(r.policies, policy)
+	} else {
+		r.policies[policy] = fqdns
+	}
+	r.syncEntries()
+}
+
+// ResyncPolicyFQDNs replaces DNS names referenced by all policies.
+func (r *Resolver) ResyncPolicyFQDNs(fqdns map[policymodel.ID][]string) {
+	r.Lock()
+	defer r.Unlock()
+	r.policies = make(map[policymodel.ID][]string)
+	for policy, policyFQDNs := range fqdns {
+		if len(policyFQDNs) > 0 {
+			r.policies[policy] = policyFQDNs
+		}
+	}
+	r.syncEntries()
+}
+
+// LookupFQDN returns addresses currently resolved for a given DNS name
+// (sorted to get the same policy rules for the same set of addresses).
+func (r *Resolver) LookupFQDN(fqdn string) (addrs []net.IP) {
+	r.Lock()
+	defer r.Unlock()
+	entry, known := r.entries[fqdn]
+	if !known {
+		return nil
+	}
+	for addr := range entry.addrs {
+		addrs = append(addrs, net.ParseIP(addr))
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i].To16(), addrs[j].To16()) < 0
+	})
+	return addrs
+}
+
+// syncEntries adds entries for newly referenced names and removes entries
+// of names no longer referenced by any policy.
+// The method is called with the lock acquired.
+func (r *Resolver) syncEntries() {
+	referenced := make(map[string]struct{})
+	for _, fqdns := range r.policies {
+		for _, fqdn := range fqdns {
+			referenced[fqdn] = struct{}{}
+		}
+	}
+	var added bool
+	for fqdn := range referenced {
+		if _, known := r.entries[fqdn]; !known {
+			r.entries[fqdn] = &entry{addrs: make(map[string]time.Time)}
+			added = true
+		}
+	}
+	for fqdn := range r.entries {
+		if _, isReferenced := referenced[fqdn]; !isReferenced {
+			delete(r.entries, fqdn)
+		}
+	}
+	if added {
+		// resolve new names right away
+		select {
+		case r.wakeCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// refreshLoop resolves the names whenever needed.
+func (r *Resolver) refreshLoop() {
+	defer r.wg.Done()
+	for {
+		changed, nextRefresh := r.refresh(time.Now())
+		if len(changed) > 0 && r.OnChange != nil {
+			r.OnChange(changed)
+		}
+		timeout := r.MaxTTL
+		if !nextRefresh.IsZero() {
+			timeout = time.Until(nextRefresh)
+		}
+		select {
+		case <-r.closeCh:
+			return
+		case <-r.wakeCh:
+		case <-time.After(timeout):
+		}
+	}
+}
+
+// refresh re-resolves names due for refresh and removes expired addresses.
+// Returns names with changed addresses and the time of the next refresh
+// or expiration.
+func (r *Resolver) refresh(now time.Time) (changed []string, nextRefresh time.Time) {
+	// collect names to resolve
+	r.Lock()
+	var toResolve []string
+	for fqdn, entry := range r.entries {
+		if !entry.nextRefresh.After(now) {
+			toResolve = append(toResolve, fqdn)
+		}
+	}
+	r.Unlock()
+
+	// resolve names without holding the lock
+	resolved := make(map[string][]Address)
+	failed := make(map[string]struct{})
+	for _, fqdn := range toResolve {
+		addrs, err := r.DNSClient.Resolve(fqdn)
+		if err != nil {
+			r.Log.Warnf("Failed to resolve %s: %v", fqdn, err)
+			failed[fqdn] = struct{}{}
+			continue
+		}
+		r.Log.Debugf("Resolved %s: %v", fqdn, addrs)
+		resolved[fqdn] = addrs
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	changedSet := make(map[string]struct{})
+	for fqdn := range failed {
+		if entry, known := r.entries[fqdn]; known {
+			entry.nextRefresh = now.Add(r.MinTTL)
+		}
+	}
+	for fqdn, addrs := range resolved {
+		entry, known := r.entries[fqdn]
+		if !known {
+			// no longer referenced
+			continue
+		}
+		minTTL := r.MaxTTL
+		for _, addr := range addrs {
+			ttl := r.boundTTL(addr.TTL)
+			if ttl < minTTL {
+				minTTL = ttl
+			}
+			ip := addr.IP.String()
+			if _, hasAddr := entry.addrs[ip]; !hasAddr {
+				changedSet[fqdn] = struct{}{}
+			}
+			if expiration := now.Add(ttl); expiration.After(entry.addrs[ip]) {
+				entry.addrs[ip] = expiration
+			}
+		}
+		if len(addrs) == 0 {
+			minTTL = r.MinTTL
+		}
+		// refresh before the addresses expire
+		entry.nextRefresh = now.Add(minTTL * 3 / 4)
+	}
+
+	// remove expired addresses
+	for fqdn, entry := range r.entries {
+		for ip, expiration := range entry.addrs {
+			if !expiration.After(now) {
+				r.Log.Debugf("Address %s of %s has expired", ip, fqdn)
+				delete(entry.addrs, ip)
+				changedSet[fqdn] = struct{}{}
+				continue
+			}
+			if nextRefresh.IsZero() || expiration.Before(nextRefresh) {
+				nextRefresh = expiration
+			}
+		}
+		if nextRefresh.IsZero() || entry.nextRefresh.Before(nextRefresh) {
+			nextRefresh = entry.nextRefresh
+		}
+	}
+
+	for fqdn := range changedSet {
+		changed = append(changed, fqdn)
+	}
+	sort.Strings(changed)
+	return changed, nextRefresh
+}
+
+// boundTTL returns TTL limited to the configured range.
+func (r *Resolver) boundTTL(ttl time.Duration) time.Duration {
+	if ttl < r.MinTTL {
+		return r.MinTTL
+	}
+	if ttl > r.MaxTTL {
+		return r.MaxTTL
+	}
+	return ttl
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fqdn
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"go.ligato.io/cn-infra/v2/logging"
+	"go.ligato.io/cn-infra/v2/logging/logrus"
+
+	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
+)
+
+const (
+	minTTL = 30 * time.Second
+	maxTTL = 300 * time.Second
+)
+
+// mockDNSClient returns pre-defined addresses for DNS names.
+type mockDNSClient struct {
+	records map[string][]Address
+	queries []string
+}
+
+func (c *mockDNSClient) Resolve(fqdn string) ([]Address, error) {
+	c.queries = append(c.queries, fqdn)
+	addrs, known := c.records[fqdn]
+	if !known {
+		return nil, errors.New("nameserver not available")
+	}
+	return addrs, nil
+}
+
+func address(ip string, ttl time.Duration) Address {
+	return Address{IP: net.ParseIP(ip), TTL: ttl}
+}
+
+func ips(addrs ...string) (ips []net.IP) {
+	for _, addr := range addrs {
+		ips = append(ips, net.ParseIP(addr))
+	}
+	return ips
+}
+
+func newTestResolver(dnsClient DNSClient) *Resolver {
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	// refresh loop is not started, the tests call refresh() directly
+	return &Resolver{
+		Deps: Deps{
+			Log:       logger,
+			DNSClient: dnsClient,
+			MinTTL:    minTTL,
+			MaxTTL:    maxTTL,
+		},
+		entries:  make(map[string]*entry),
+		policies: make(map[policymodel.ID][]string),
+		wakeCh:   make(chan struct{}, 1),
+	}
+}
+
+func TestResolveAndExpire(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	dnsClient := &mockDNSClient{records: map[string][]Address{
+		"api.example.com": {address("192.0.2.20", 60*time.Second), address("192.0.2.10", 60*time.Second)},
+		"ntp.example.com": {address("2001:db8::1", time.Second) /* bounded by min TTL */},
+	}}
+	resolver := newTestResolver(dnsClient)
+	policy1 := policymodel.ID{Namespace: "default", Name: "policy1"}
+	policy2 := policymodel.ID{Namespace: "default", Name: "policy2"}
+
+	resolver.SetPolicyFQDNs(policy1, []string{"api.example.com"})
+	resolver.SetPolicyFQDNs(policy2, []string{"api.example.com", "ntp.example.com"})
+	gomega.Expect(resolver.LookupFQDN("api.example.com")).To(gomega.BeEmpty())
+
+	// initial resolution
+	now := time.Now()
+	changed, nextRefresh := resolver.refresh(now)
+	gomega.Expect(changed).To(gomega.Equal([]string{"api.example.com", "ntp.example.com"}))
+	gomega.Expect(nextRefresh).To(gomega.Equal(now.Add(minTTL * 3 / 4)))
+	gomega.Expect(resolver.LookupFQDN("api.example.com")).To(gomega.Equal(ips("192.0.2.10", "192.0.2.20")))
+	gomega.Expect(resolver.LookupFQDN("ntp.example.com")).To(gomega.Equal(ips("2001:db8::1")))
+
+	// nothing to refresh yet
+	dnsClient.queries = nil
+	changed, _ = resolver.refresh(now.Add(time.Second))
+	gomega.Expect(changed).To(gomega.BeEmpty())
+	gomega.Expect(dnsClient.queries).To(gomega.BeEmpty())
+
+	// address rotation: the old address stays valid until it expires
+	dnsClient.records["api.example.com"] = []Address{address("192.0.2.30", 60*time.Second)}
+	changed, _ = resolver.refresh(now.Add(45 * time.Second))
+	gomega.Expect(changed).To(gomega.Equal([]string{"api.example.com"}))
+	gomega.Expect(dnsClient.queries).To(gomega.ConsistOf("api.example.com", "ntp.example.com"))
+	gomega.Expect(resolver.LookupFQDN("api.example.com")).To(gomega.Equal(ips("192.0.2.10", "192.0.2.20", "192.0.2.30")))
+
+	changed, _ = resolver.refresh(now.Add(61 * time.Second))
+	gomega.Expect(changed).To(gomega.Equal([]string{"api.example.com"}))
+	gomega.Expect(resolver.LookupFQDN("api.example.com")).To(gomega.Equal(ips("192.0.2.30")))
+
+	// failed queries keep the addresses until they expire
+	delete(dnsClient.records, "ntp.example.com")
+	changed, _ = resolver.refresh(now.Add(70 * time.Second))
+	gomega.Expect(changed).To(gomega.BeEmpty())
+	gomega.Expect(resolver.LookupFQDN("ntp.example.com")).To(gomega.Equal(ips("2001:db8::1")))
+	changed, _ = resolver.refresh(now.Add(100 * time.Second))
+	gomega.Expect(changed).To(gomega.Equal([]string{"ntp.example.com"}))
+	gomega.Expect(resolver.LookupFQDN("ntp.example.com")).To(gomega.BeEmpty())
+}
+
+func TestUnreferencedNames(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	dnsClient := &mockDNSClient{records: map[string][]Address{
+		"api.example.com": {address("192.0.2.10", time.Hour) /* bounded by max TTL */},
+		"ntp.example.com": {address("192.0.2.20", time.Minute)},
+	}}
+	resolver := newTestResolver(dnsClient)
+	policy1 := policymodel.ID{Namespace: "default", Name: "policy1"}
+	policy2 := policymodel.ID{Namespace: "default", Name: "policy2"}
+
+	resolver.ResyncPolicyFQDNs(map[policymodel.ID][]string{
+		policy1: {"api.example.com"},
+		policy2: {"ntp.example.com"},
+	})
+	now := time.Now()
+	changed, nextRefresh := resolver.refresh(now)
+	gomega.Expect(changed).To(gomega.HaveLen(2))
+	gomega.Expect(nextRefresh).To(gomega.Equal(now.Add(45 * time.Second)))
+
+	// name of the removed policy is forgotten
+	resolver.SetPolicyFQDNs(policy2, nil)
+	gomega.Expect(resolver.LookupFQDN("ntp.example.com")).To(gomega.BeEmpty())
+	gomega.Expect(resolver.LookupFQDN("api.example.com")).To(gomega.Equal(ips("192.0.2.10")))
+	_, nextRefresh = resolver.refresh(now)
+	gomega.Expect(nextRefresh).To(gomega.Equal(now.Add(maxTTL * 3 / 4)))
+
+	// resync without the policy
+	resolver.ResyncPolicyFQDNs(map[policymodel.ID][]string{})
+	gomega.Expect(resolver.LookupFQDN("api.example.com")).To(gomega.BeEmpty())
+	gomega.Expect(resolver.entries).To(gomega.BeEmpty())
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"net"
+
+	"github.com/contiv/vpp/plugins/policy/renderer"
+	"github.com/contiv/vpp/plugins/policy/utils"
+	svcrenderer "github.com/contiv/vpp/plugins/service/renderer"
+)
+
+// ServiceFrontends is implemented by the service plugin, which keeps track
+// of the service frontends restricted by loadBalancerSourceRanges on behalf
+// of the service renderers. The restrictions are rendered by the policy plugin,
+// which is the only owner of the ACLs installed on the node interfaces.
+type ServiceFrontends interface {
+	// GetRestrictedFrontends returns frontends of services (LB ingress IPs and node
+	// ports) with access restricted to the loadBalancerSourceRanges.
+	GetRestrictedFrontends() []*svcrenderer.RestrictedFrontend
+}
+
+// getFrontendRules converts service frontends with access restricted
+// to loadBalancerSourceRanges into rules for the ACL renderer: traffic destined
+// to a restricted frontend is permitted only from the allowed source ranges
+// (of the same IP version) and denied otherwise.
+// The rules are installed by the ACL renderer into the same ACLs as the rules
+// protecting the node, therefore the order of evaluation is well-defined.
+func (p *Plugin) getFrontendRules() (rules []*renderer.ContivRule) {
+	for _, frontend := range p.Service.GetRestrictedFrontends() {
+		protocol := frontendProtocol(frontend.Protocol)
+		port := frontend.Port
+		if !p.aclRenderer.CanMatchPorts(protocol) {
+			// restrict all the traffic of the protocol destined to the frontend IP
+			port = 0
+		}
+		dstNetwork := utils.GetOneHostSubnetFromIP(frontend.IP)
+		dstIsIPv4 := frontend.IP.To4() != nil
+		for _, sourceRange := range frontend.SourceRanges {
+			if (sourceRange.IP.To4() != nil) != dstIsIPv4 {
+				continue
+			}
+			rules = append(rules, &renderer.ContivRule{
+				Action:      renderer.ActionPermit,
+				SrcNetwork:  sourceRange,
+				DestNetwork: dstNetwork,
+				Protocol:    protocol,
+				DestPort:    port,
+			})
+		}
+		rules = append(rules, &renderer.ContivRule{
+			Action:      renderer.ActionDeny,
+			SrcNetwork:  &net.IPNet{},
+			DestNetwork: dstNetwork,
+			Protocol:    protocol,
+			DestPort:    port,
+		})
+	}
+	return rules
+}
+
+// updateFrontends re-renders ACLs restricting access to the service frontends.
+// Called for events that may change the set of restricted frontends - the service
+// plugin handles these events before the policy plugin.
+func (p *Plugin) updateFrontends() error {
+	return p.aclRenderer.NewTxn(false).Commit()
+}
+
+// frontendProtocol converts protocol of a service port into the protocol
+// type of Contiv rules.
+func frontendProtocol(protocol svcrenderer.ProtocolType) renderer.ProtocolType {
+	switch protocol {
+	case svcrenderer.UDP:
+		return renderer.UDP
+	case svcrenderer.SCTP:
+		return renderer.SCTP
+	}
+	return renderer.TCP
+}
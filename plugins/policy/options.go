@@ -15,7 +15,13 @@
 package policy
 
 import (
+	"go.ligato.io/cn-infra/v2/config"
 	"go.ligato.io/cn-infra/v2/logging"
+	"go.ligato.io/cn-infra/v2/rpc/rest"
+	"go.ligato.io/cn-infra/v2/servicelabel"
+	"go.ligato.io/vpp-agent/v3/plugins/govppmux"
+
+	"github.com/contiv/vpp/plugins/statscollector"
 )
 
 // NewPlugin creates a new Plugin with the provides Options
@@ -23,6 +29,10 @@ func NewPlugin(opts ...Option) *Plugin {
 	p := &Plugin{}
 
 	p.PluginName = "policy"
+	p.ServiceLabel = &servicelabel.DefaultPlugin
+	p.GoVPP = &govppmux.DefaultPlugin
+	p.Stats = &statscollector.DefaultPlugin
+	p.HTTPHandlers = &rest.DefaultPlugin
 
 	for _, o := range opts {
 		o(p)
@@ -31,6 +41,9 @@ func NewPlugin(opts ...Option) *Plugin {
 	if p.Deps.Log == nil {
 		p.Deps.Log = logging.ForPlugin(p.String())
 	}
+	if p.Cfg == nil {
+		p.Cfg = config.ForPlugin(p.String())
+	}
 
 	return p
 }
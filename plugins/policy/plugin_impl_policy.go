@@ -15,21 +15,45 @@
 package policy
 
 import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
 	"go.ligato.io/cn-infra/v2/infra"
+	"go.ligato.io/cn-infra/v2/rpc/rest"
+	"go.ligato.io/cn-infra/v2/servicelabel"
+	"go.ligato.io/vpp-agent/v3/plugins/govppmux"
 
 	"github.com/contiv/vpp/plugins/contivconf"
 	controller "github.com/contiv/vpp/plugins/controller/api"
+	hostpolicymodel "github.com/contiv/vpp/plugins/crd/handler/hostpolicy/model"
 	"github.com/contiv/vpp/plugins/ipam"
 	"github.com/contiv/vpp/plugins/ipnet"
+	"github.com/contiv/vpp/plugins/ksr/model/adminpolicy"
 	"github.com/contiv/vpp/plugins/ksr/model/namespace"
+	"github.com/contiv/vpp/plugins/ksr/model/node"
 	"github.com/contiv/vpp/plugins/ksr/model/pod"
 	"github.com/contiv/vpp/plugins/ksr/model/policy"
+	svcmodel "github.com/contiv/vpp/plugins/ksr/model/service"
+	"github.com/contiv/vpp/plugins/nodesync"
 	"github.com/contiv/vpp/plugins/podmanager"
 	"github.com/contiv/vpp/plugins/policy/cache"
+	"github.com/contiv/vpp/plugins/policy/config"
 	"github.com/contiv/vpp/plugins/policy/configurator"
+	"github.com/contiv/vpp/plugins/policy/dryrun"
+	"github.com/contiv/vpp/plugins/policy/fqdn"
 	"github.com/contiv/vpp/plugins/policy/processor"
+	"github.com/contiv/vpp/plugins/policy/renderer"
 	"github.com/contiv/vpp/plugins/policy/renderer/acl"
 	"github.com/contiv/vpp/plugins/policy/renderer/iptables"
+	"github.com/contiv/vpp/plugins/policy/restapi"
+	"github.com/contiv/vpp/plugins/statscollector"
+)
+
+const (
+	// timeout of DNS queries used to resolve DNS names referenced by policies
+	dnsQueryTimeout = 5 * time.Second
 )
 
 // Plugin watches configuration of K8s resources (as reflected by KSR into ETCD)
@@ -38,6 +62,8 @@ import (
 type Plugin struct {
 	Deps
 
+	config *config.Config
+
 	// ongoing transaction
 	resyncTxn  controller.ResyncOperations
 	updateTxn  controller.UpdateOperations
@@ -49,32 +75,68 @@ type Plugin struct {
 	// Policy Cache: layers 1-3
 	policyCache *cache.PolicyCache
 
+	// protects the cache against concurrent access from the dry-run REST handler
+	stateLock sync.Mutex
+
 	// Policy Processor: layer 2
 	processor *processor.PolicyProcessor
 
+	// Resolver of DNS names used in egress rules
+	fqdnResolver *fqdn.Resolver
+
 	// Policy Configurator: layer 3
 	configurator *configurator.PolicyConfigurator
 
 	// Policy Renderers: layer 4
-	//  -> ACL Renderer
+	//  -> ACL Renderer (with IPv6 used only to restrict access to service frontends,
+	//     if enabled by the configuration)
 	aclRenderer *acl.Renderer
 	//  -> iptables Renderer
 	iptablesRenderer *iptables.Renderer
 
 	// New renderers should come here ...
+
+	// Evaluation of policies in a sandbox (see dryrun.go)
+	dryRun *dryrun.DryRun
+
+	// Tables rendered for the pods (see pods.go)
+	podTables renderer.PodTablesAPI
+
+	// Rule counters (see counters.go)
+	ruleCounters     renderer.RuleCountersAPI
+	countersLock     sync.Mutex
+	prevPackets      map[string]uint64 // table+rule -> packets
+	deniedRuleHits   []*restapi.DeniedRuleHit
+	permittedPackets *prometheus.GaugeVec
+	deniedPackets    *prometheus.GaugeVec
+	closeCh          chan struct{}
 }
 
 // Deps defines dependencies of policy plugin.
 type Deps struct {
 	infra.PluginDeps
-	ContivConf contivconf.API
-	IPAM       ipam.API
-	IPNet      ipnet.API
-	PodManager podmanager.API
+	ServiceLabel servicelabel.ReaderAPI
+	ContivConf   contivconf.API
+	IPAM         ipam.API
+	IPNet        ipnet.API
+	PodManager   podmanager.API
+	EventLoop    controller.EventLoop
+	GoVPP        govppmux.API       /* used to read ACL counters */
+	Stats        statscollector.API /* used for exporting the rule counters */
+	HTTPHandlers rest.HTTPHandlers  /* used for exposing the rule counters, the dry-run and pod policies */
+	Service      ServiceFrontends   /* used to restrict access to service frontends */
 }
 
 // Init initializes policy layers and caches and starts watching ETCD for K8s configuration.
 func (p *Plugin) Init() error {
+	// Load configuration.
+	p.config = config.DefaultConfig()
+	_, err := p.Cfg.LoadValue(p.config)
+	if err != nil {
+		return err
+	}
+	p.Log.Infof("Policy plugin configuration: %+v", *p.config)
+
 	// Inject dependencies between layers.
 	p.policyCache = &cache.PolicyCache{
 		Deps: cache.Deps{
@@ -90,6 +152,26 @@ func (p *Plugin) Init() error {
 		},
 	}
 
+	nameservers := p.config.FQDNNameservers
+	if len(nameservers) == 0 {
+		nameservers, err = fqdn.ReadNameservers(fqdn.DefaultResolvConf)
+		if err != nil {
+			p.Log.Warnf("Failed to read nameservers for policy DNS names: %v", err)
+		}
+	}
+	p.fqdnResolver = &fqdn.Resolver{
+		Deps: fqdn.Deps{
+			Log: p.Log.NewLogger("-fqdnResolver"),
+			DNSClient: &fqdn.UDPClient{
+				Servers: nameservers,
+				Timeout: dnsQueryTimeout,
+			},
+			MinTTL:   time.Duration(p.config.FQDNMinTTL) * time.Second,
+			MaxTTL:   time.Duration(p.config.FQDNMaxTTL) * time.Second,
+			OnChange: p.onFQDNChange,
+		},
+	}
+
 	p.processor = &processor.PolicyProcessor{
 		Deps: processor.Deps{
 			Log:          p.Log.NewLogger("-policyProcessor"),
@@ -97,25 +179,46 @@ func (p *Plugin) Init() error {
 			ContivConf:   p.ContivConf,
 			Cache:        p.policyCache,
 			Configurator: p.configurator,
+			FQDNResolver: p.fqdnResolver,
+			ServiceLabel: p.ServiceLabel,
 		},
 	}
 
-	if !p.ContivConf.GetIPAMConfig().UseIPv6 {
-
-		p.aclRenderer = &acl.Renderer{
-			Deps: acl.Deps{
-				Log:        p.Log.NewLogger("-aclRenderer"),
-				LogFactory: p.Log,
-				ContivConf: p.ContivConf,
-				IPNet:      p.IPNet,
-				UpdateTxnFactory: func() controller.UpdateOperations {
-					p.withChange = true
-					return p.updateTxn
-				},
-				ResyncTxnFactory: func() controller.ResyncOperations {
-					return p.resyncTxn
-				},
+	// The ACL renderer also restricts access to service frontends (LB ingress IPs
+	// and node ports) by loadBalancerSourceRanges - the service renderers rely
+	// on it, therefore it is always created. With IPv6, where policies are
+	// rendered by iptables, it renders only the frontend restrictions.
+	if p.Service == nil {
+		return errors.New("service plugin is required to enforce loadBalancerSourceRanges of services")
+	}
+	useIPv6 := p.ContivConf.GetIPAMConfig().UseIPv6
+	p.aclRenderer = &acl.Renderer{
+		Deps: acl.Deps{
+			Log:        p.Log.NewLogger("-aclRenderer"),
+			LogFactory: p.Log,
+			ContivConf: p.ContivConf,
+			IPNet:      p.IPNet,
+			UpdateTxnFactory: func() controller.UpdateOperations {
+				p.withChange = true
+				return p.updateTxn
+			},
+			ResyncTxnFactory: func() controller.ResyncOperations {
+				return p.resyncTxn
 			},
+			FrontendRules:       p.getFrontendRules,
+			HostAllowedTCPPorts: p.config.HostAllowedTCPPorts,
+			HostAllowedUDPPorts: p.config.HostAllowedUDPPorts,
+		},
+	}
+	if !useIPv6 {
+		goVppCh, err := p.GoVPP.NewAPIChannel()
+		if err != nil {
+			return err
+		}
+		p.aclRenderer.ACLCounters = &acl.VPPACLCounters{
+			Log:       p.Log.NewLogger("-aclCounters"),
+			GoVPPChan: goVppCh,
+			Stats:     p.GoVPP,
 		}
 	} else {
 		p.iptablesRenderer = &iptables.Renderer{
@@ -136,6 +239,18 @@ func (p *Plugin) Init() error {
 		}
 	}
 
+	p.dryRun = &dryrun.DryRun{
+		Deps: dryrun.Deps{
+			Log:          p.Log.NewLogger("-policyDryRun"),
+			Cache:        p.policyCache,
+			IPAM:         p.IPAM,
+			FQDNResolver: p.fqdnResolver,
+		},
+	}
+	if !useIPv6 {
+		p.dryRun.PortMatching = p.aclRenderer
+	}
+
 	/* Note: L4 was removed from Contiv but may be re-added in the future
 	const goVPPChanBufSize = 1 << 12
 	goVppCh, err := p.GoVPP.NewAPIChannelBuffered(goVPPChanBufSize, goVPPChanBufSize)
@@ -155,16 +270,21 @@ func (p *Plugin) Init() error {
 
 	// Initialize layers.
 	p.policyCache.Init()
+	p.fqdnResolver.Init()
 	p.processor.Init()
 	p.configurator.Init(false) // Do not render in parallel while we do lot of debugging.
 
-	if !p.ContivConf.GetIPAMConfig().UseIPv6 {
-		p.aclRenderer.Init()
+	p.aclRenderer.Init()
+	if !useIPv6 {
 		p.configurator.RegisterRenderer(p.aclRenderer)
+		p.podTables = p.aclRenderer
 	} else {
 		p.iptablesRenderer.Init()
 		p.configurator.RegisterRenderer(p.iptablesRenderer)
 	}
+
+	p.initRuleCounters()
+	p.registerRESTHandlers()
 	return nil
 }
 
@@ -173,6 +293,9 @@ func (p *Plugin) HandlesEvent(event controller.Event) bool {
 	if event.Method() != controller.Update {
 		return true
 	}
+	if _, isFQDNChange := event.(*fqdn.AddressChange); isFQDNChange {
+		return true
+	}
 	if ksChange, isKSChange := event.(*controller.KubeStateChange); isKSChange {
 		switch ksChange.Resource {
 		case namespace.NamespaceKeyword:
@@ -181,12 +304,26 @@ func (p *Plugin) HandlesEvent(event controller.Event) bool {
 			return true
 		case policy.PolicyKeyword:
 			return true
+		case adminpolicy.AdminPolicyKeyword:
+			return true
+		case hostpolicymodel.Keyword:
+			return true
+		case node.NodeKeyword:
+			return true
+		case svcmodel.ServiceKeyword:
+			// LB ingress IPs and source ranges of services
+			return true
 		default:
 			// unhandled Kubernetes state change
 			return false
 		}
 	}
 
+	if _, isNodeUpdate := event.(*nodesync.NodeUpdate); isNodeUpdate {
+		// node IPs with restricted node ports
+		return true
+	}
+
 	// unhandled event
 	return false
 }
@@ -198,18 +335,39 @@ func (p *Plugin) HandlesEvent(event controller.Event) bool {
 func (p *Plugin) Resync(event controller.Event, kubeStateData controller.KubeStateData,
 	resyncCount int, txn controller.ResyncOperations) error {
 
+	p.stateLock.Lock()
+	defer p.stateLock.Unlock()
 	p.resyncTxn = txn
 	p.updateTxn = nil
-	return p.policyCache.Resync(kubeStateData)
+	if err := p.policyCache.Resync(kubeStateData); err != nil {
+		return err
+	}
+	if p.ContivConf.GetIPAMConfig().UseIPv6 {
+		// the ACL renderer is not resynced by the configurator
+		return p.aclRenderer.NewTxn(true).Commit()
+	}
+	return nil
 }
 
-// Update is called for KubeStateChange.
+// Update is called for KubeStateChange, FQDN address change and NodeUpdate.
 func (p *Plugin) Update(event controller.Event, txn controller.UpdateOperations) (changeDescription string, err error) {
+	p.stateLock.Lock()
+	defer p.stateLock.Unlock()
 	p.resyncTxn = nil
 	p.updateTxn = txn
 	p.withChange = false
-	kubeStateChange := event.(*controller.KubeStateChange)
-	err = p.policyCache.Update(kubeStateChange)
+	switch ev := event.(type) {
+	case *fqdn.AddressChange:
+		err = p.processor.UpdateFQDNs(ev.FQDNs)
+	case *nodesync.NodeUpdate:
+		err = p.updateFrontends()
+	case *controller.KubeStateChange:
+		if ev.Resource == svcmodel.ServiceKeyword {
+			err = p.updateFrontends()
+		} else {
+			err = p.policyCache.Update(ev)
+		}
+	}
 	if p.withChange {
 		changeDescription = "refresh policies"
 	}
@@ -221,7 +379,24 @@ func (p *Plugin) Revert(event controller.Event) error {
 	return nil
 }
 
-// Close is NOOP.
+// onFQDNChange is called by the resolver when addresses of DNS names change.
+func (p *Plugin) onFQDNChange(fqdns []string) {
+	if p.EventLoop == nil {
+		return
+	}
+	if err := p.EventLoop.PushEvent(&fqdn.AddressChange{FQDNs: fqdns}); err != nil {
+		p.Log.Errorf("Failed to push FQDN address change event: %v", err)
+	}
+}
+
+// Close stops the collection of the rule counters and the resolution
+// of DNS names.
 func (p *Plugin) Close() error {
+	if p.closeCh != nil {
+		close(p.closeCh)
+	}
+	if p.fqdnResolver != nil {
+		p.fqdnResolver.Close()
+	}
 	return nil
 }
@@ -0,0 +1,100 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/unrolled/render"
+
+	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
+	"github.com/contiv/vpp/plugins/policy/renderer"
+	"github.com/contiv/vpp/plugins/policy/restapi"
+)
+
+// podsGetHandler describes policies applied to the pods of this node together
+// with the tables rendered for the pods (if supported by the renderer).
+func (p *Plugin) podsGetHandler(formatter *render.Render) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		p.Log.Debug("Getting policies of the pods")
+
+		// tables are read first, the renderer is synchronized on its own
+		podTables := make(map[podmodel.ID]*renderer.PodTables)
+		if p.podTables != nil {
+			tables, err := p.podTables.GetPodTables()
+			if err != nil {
+				p.Log.Errorf("Error reading rendered policy tables: %v", err)
+				formatter.JSON(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			for _, podTable := range tables {
+				podTables[podTable.Pod] = podTable
+			}
+		}
+		p.stateLock.Lock()
+		podPolicies := p.configurator.GetPodPolicies()
+		p.stateLock.Unlock()
+
+		// optional filtering by pod
+		podNamespace := req.URL.Query().Get(restapi.PodNamespaceParam)
+		podName := req.URL.Query().Get(restapi.PodNameParam)
+
+		output := restapi.NodePodPolicies{
+			NodeName: p.ServiceLabel.GetAgentLabel(),
+			Pods:     []*restapi.PodPolicies{},
+		}
+		for podID, policies := range podPolicies {
+			if (podNamespace != "" && podID.Namespace != podNamespace) ||
+				(podName != "" && podID.Name != podName) {
+				continue
+			}
+			pod := &restapi.PodPolicies{
+				Pod:      podID.String(),
+				Policies: []string{},
+				Tables:   []*restapi.RuleTable{},
+			}
+			for _, policy := range policies {
+				pod.Policies = append(pod.Policies, policy.String())
+			}
+			if tables, hasTables := podTables[podID]; hasTables {
+				pod.Interface = tables.Interface
+				for _, table := range tables.Tables {
+					pod.Tables = append(pod.Tables, ruleTable(table))
+				}
+			}
+			output.Pods = append(output.Pods, pod)
+		}
+		sort.Slice(output.Pods, func(i, j int) bool {
+			return output.Pods[i].Pod < output.Pods[j].Pod
+		})
+		formatter.JSON(w, http.StatusOK, output)
+	}
+}
+
+// ruleTable converts table installed by the renderer into its REST representation.
+func ruleTable(table *renderer.InstalledTable) *restapi.RuleTable {
+	restTable := &restapi.RuleTable{
+		Name:     table.Name,
+		Index:    table.Index,
+		Attached: table.Attached,
+		Egress:   table.Egress,
+		Rules:    []string{},
+	}
+	for _, rule := range table.Rules {
+		restTable.Rules = append(restTable.Rules, rule.String())
+	}
+	return restTable
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processor
+
+import (
+	"net"
+
+	adminpolicymodel "github.com/contiv/vpp/plugins/ksr/model/adminpolicy"
+	nsmodel "github.com/contiv/vpp/plugins/ksr/model/namespace"
+	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
+	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
+	config "github.com/contiv/vpp/plugins/policy/configurator"
+)
+
+// AddAdminPolicy processes the event of newly added admin policy.
+// The policy re-processing is triggered for all pods selected by the policy subject.
+func (pp *PolicyProcessor) AddAdminPolicy(policy *adminpolicymodel.AdminPolicy) error {
+	if policy == nil {
+		pp.Log.WithField("policy", policy).Error("Error reading Admin Policy")
+		return nil
+	}
+	return pp.Process(false, pp.getPodsAssignedToAdminPolicy(policy))
+}
+
+// DelAdminPolicy processes the event of a removed admin policy.
+// The policy re-processing is triggered for all pods that used to be selected
+// by the policy subject.
+func (pp *PolicyProcessor) DelAdminPolicy(policy *adminpolicymodel.AdminPolicy) error {
+	if policy == nil {
+		pp.Log.WithField("policy", policy).Error("Error reading Admin Policy")
+		return nil
+	}
+	return pp.Process(false, pp.getPodsAssignedToAdminPolicy(policy))
+}
+
+// UpdateAdminPolicy processes the event of changed admin policy data.
+// The policy re-processing is triggered for all pods selected by the policy
+// subject before the change and now.
+func (pp *PolicyProcessor) UpdateAdminPolicy(oldPolicy, newPolicy *adminpolicymodel.AdminPolicy) error {
+	if oldPolicy == nil || newPolicy == nil {
+		pp.Log.WithFields(map[string]interface{}{"old-policy": oldPolicy, "new-policy": newPolicy}).
+			Error("Error reading Admin Policy")
+		return nil
+	}
+	pods := []podmodel.ID{}
+	pods = append(pods, pp.getPodsAssignedToAdminPolicy(oldPolicy)...)
+	pods = append(pods, pp.getPodsAssignedToAdminPolicy(newPolicy)...)
+	return pp.Process(false, pods)
+}
+
+// getPodsAssignedToAdminPolicy returns all pods selected by the subject
+// of the given admin policy.
+func (pp *PolicyProcessor) getPodsAssignedToAdminPolicy(policy *adminpolicymodel.AdminPolicy) (pods []podmodel.ID) {
+	if policy.Subject == nil {
+		return nil
+	}
+	return pp.Cache.LookupPodsByAdminSelector(policy.Subject.Namespaces, policy.Subject.Pods)
+}
+
+// getPodsWithAdminPoliciesReferencingPod returns all pods with assigned admin
+// policies that have a rule whose peer selects the given pod.
+func (pp *PolicyProcessor) getPodsWithAdminPoliciesReferencingPod(pod *podmodel.Pod) (pods []podmodel.ID) {
+	return pp.getPodsAssignedToAdminPolicies(pp.Cache.LookupAdminPoliciesByPeerPod(pod))
+}
+
+// getPodsWithAdminPoliciesReferencingNamespace returns all pods with assigned
+// admin policies that have a rule whose peer selects pods of the given namespace.
+func (pp *PolicyProcessor) getPodsWithAdminPoliciesReferencingNamespace(ns *nsmodel.Namespace) (pods []podmodel.ID) {
+	return pp.getPodsAssignedToAdminPolicies(pp.Cache.LookupAdminPoliciesByPeerNamespace(ns))
+}
+
+// getPodsAssignedToAdminPolicies returns all pods selected by the subject
+// of any of the given admin policies.
+func (pp *PolicyProcessor) getPodsAssignedToAdminPolicies(names []string) (pods []podmodel.ID) {
+	for _, name := range names {
+		if found, policy := pp.Cache.LookupAdminPolicy(name); found {
+			pods = append(pods, pp.getPodsAssignedToAdminPolicy(policy)...)
+		}
+	}
+	return pods
+}
+
+// convertAdminPolicy converts admin policy from the Kubernetes data model
+// to an instance of ContivPolicy.
+func (pp *PolicyProcessor) convertAdminPolicy(policy *adminpolicymodel.AdminPolicy) *config.ContivPolicy {
+	matches := []config.Match{}
+	for _, rule := range policy.IngressRule {
+		matches = append(matches, pp.calculateAdminMatch(config.MatchIngress, rule))
+	}
+	for _, rule := range policy.EgressRule {
+		matches = append(matches, pp.calculateAdminMatch(config.MatchEgress, rule))
+	}
+	return &config.ContivPolicy{
+		ID:       policymodel.ID{Name: policy.Name},
+		Type:     config.PolicyAll,
+		Admin:    true,
+		Priority: policy.Priority,
+		Matches:  matches,
+	}
+}
+
+// calculateAdminMatch translates rule of an admin policy into a Match.
+// Unlike with namespaced policies, Pods and IPBlocks are never nil - a rule
+// of an admin policy without peers matches no traffic.
+func (pp *PolicyProcessor) calculateAdminMatch(matchType config.MatchType, rule *adminpolicymodel.AdminPolicy_Rule) config.Match {
+	match := config.Match{
+		Type:     matchType,
+		Pods:     []podmodel.ID{},
+		IPBlocks: []config.IPBlock{},
+		Ports:    []config.Port{},
+	}
+	switch rule.Action {
+	case adminpolicymodel.AdminPolicy_DENY:
+		match.Action = config.ActionDeny
+	case adminpolicymodel.AdminPolicy_PASS:
+		match.Action = config.ActionPass
+	default:
+		match.Action = config.ActionAllow
+	}
+
+	for _, peer := range rule.Peers {
+		if peer.Namespaces != nil || peer.Pods != nil {
+			match.Pods = append(match.Pods, pp.Cache.LookupPodsByAdminSelector(peer.Namespaces, peer.Pods)...)
+		}
+		if matchType != config.MatchEgress {
+			// networks are supported for egress only
+			continue
+		}
+		for _, network := range peer.Networks {
+			_, ipNet, err := net.ParseCIDR(network)
+			if err != nil {
+				pp.Log.WithField("network", network).Warnf("Invalid network in admin policy rule: %v", err)
+				continue
+			}
+			match.IPBlocks = append(match.IPBlocks, config.IPBlock{Network: *ipNet})
+		}
+	}
+
+	for _, port := range rule.Ports {
+		if port.Port <= 0 || port.Port > 65535 {
+			continue
+		}
+		matchPort := config.Port{
+			Protocol: config.TCP,
+			Number:   uint16(port.Port),
+		}
+		switch port.Protocol {
+		case adminpolicymodel.AdminPolicy_Port_UDP:
+			matchPort.Protocol = config.UDP
+		case adminpolicymodel.AdminPolicy_Port_SCTP:
+			matchPort.Protocol = config.SCTP
+		}
+		if port.EndPort > port.Port && port.EndPort <= 65535 {
+			matchPort.EndNumber = uint16(port.EndPort)
+		}
+		match.Ports = append(match.Ports, matchPort)
+	}
+	return match
+}
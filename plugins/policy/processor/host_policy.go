@@ -0,0 +1,172 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processor
+
+import (
+	"net"
+	"reflect"
+
+	hostpolicymodel "github.com/contiv/vpp/plugins/crd/handler/hostpolicy/model"
+	nodemodel "github.com/contiv/vpp/plugins/ksr/model/node"
+	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
+	config "github.com/contiv/vpp/plugins/policy/configurator"
+)
+
+// AddHostPolicy processes the event of newly added host policy.
+// Host policies of this node are re-processed.
+func (pp *PolicyProcessor) AddHostPolicy(policy *hostpolicymodel.HostPolicy) error {
+	if policy == nil {
+		pp.Log.WithField("policy", policy).Error("Error reading Host Policy")
+		return nil
+	}
+	return pp.processHost()
+}
+
+// DelHostPolicy processes the event of a removed host policy.
+// Host policies of this node are re-processed.
+func (pp *PolicyProcessor) DelHostPolicy(policy *hostpolicymodel.HostPolicy) error {
+	if policy == nil {
+		pp.Log.WithField("policy", policy).Error("Error reading Host Policy")
+		return nil
+	}
+	return pp.processHost()
+}
+
+// UpdateHostPolicy processes the event of changed host policy data.
+// Host policies of this node are re-processed.
+func (pp *PolicyProcessor) UpdateHostPolicy(oldPolicy, newPolicy *hostpolicymodel.HostPolicy) error {
+	if oldPolicy == nil || newPolicy == nil {
+		pp.Log.WithFields(map[string]interface{}{"old-policy": oldPolicy, "new-policy": newPolicy}).
+			Error("Error reading Host Policy")
+		return nil
+	}
+	return pp.processHost()
+}
+
+// AddNode processes the event of newly added K8s node.
+// Host policies are re-processed if the node is this node.
+func (pp *PolicyProcessor) AddNode(node *nodemodel.Node) error {
+	if !pp.isThisNode(node) {
+		return nil
+	}
+	return pp.processHost()
+}
+
+// DelNode processes the event of a removed K8s node.
+// Host policies are re-processed if the node is this node.
+func (pp *PolicyProcessor) DelNode(node *nodemodel.Node) error {
+	if !pp.isThisNode(node) {
+		return nil
+	}
+	return pp.processHost()
+}
+
+// UpdateNode processes the event of changed K8s node data.
+// Host policies are re-processed if labels of this node have changed.
+func (pp *PolicyProcessor) UpdateNode(oldNode, newNode *nodemodel.Node) error {
+	if !pp.isThisNode(newNode) || reflect.DeepEqual(oldNode.GetLabels(), newNode.GetLabels()) {
+		return nil
+	}
+	return pp.processHost()
+}
+
+// processHost re-calculates the set of host policies assigned to this node.
+func (pp *PolicyProcessor) processHost() error {
+	if pp.ServiceLabel == nil {
+		return nil
+	}
+	txn := pp.Configurator.NewTxn(false)
+	txn.ConfigureHost(pp.getHostPolicies())
+	return txn.Commit()
+}
+
+// isThisNode returns true if the given node is the node this agent runs on.
+func (pp *PolicyProcessor) isThisNode(node *nodemodel.Node) bool {
+	return pp.ServiceLabel != nil && node != nil && node.Name == pp.ServiceLabel.GetAgentLabel()
+}
+
+// getHostPolicies returns host policies selecting this node converted
+// to ContivPolicies.
+func (pp *PolicyProcessor) getHostPolicies() (policies []*config.ContivPolicy) {
+	if pp.ServiceLabel == nil {
+		return nil
+	}
+	for _, name := range pp.Cache.LookupHostPoliciesByNode(pp.ServiceLabel.GetAgentLabel()) {
+		found, policyData := pp.Cache.LookupHostPolicy(name)
+		if !found {
+			continue
+		}
+		policies = append(policies, pp.convertHostPolicy(policyData))
+	}
+	return policies
+}
+
+// convertHostPolicy converts host policy from the CRD data model
+// to an instance of ContivPolicy.
+func (pp *PolicyProcessor) convertHostPolicy(policy *hostpolicymodel.HostPolicy) *config.ContivPolicy {
+	matches := []config.Match{}
+	for _, rule := range policy.Ingress {
+		matches = append(matches, pp.calculateHostMatch(rule))
+	}
+	return &config.ContivPolicy{
+		ID:      policymodel.ID{Name: policy.Name},
+		Type:    config.PolicyIngress,
+		Host:    true,
+		Matches: matches,
+	}
+}
+
+// calculateHostMatch translates rule of a host policy into a Match.
+// Rule without sources matches traffic from anywhere, rule without ports
+// matches all ports and protocols.
+func (pp *PolicyProcessor) calculateHostMatch(rule *hostpolicymodel.HostPolicy_Rule) config.Match {
+	match := config.Match{
+		Type:   config.MatchIngress,
+		Action: config.ActionAllow,
+	}
+	for _, from := range rule.From {
+		_, ipNet, err := net.ParseCIDR(from)
+		if err != nil {
+			pp.Log.WithField("network", from).Warnf("Invalid network in host policy rule: %v", err)
+			continue
+		}
+		match.IPBlocks = append(match.IPBlocks, config.IPBlock{Network: *ipNet})
+	}
+	if len(rule.From) > 0 && match.IPBlocks == nil {
+		// all sources were invalid - match nothing rather than anything
+		match.IPBlocks = []config.IPBlock{}
+	}
+
+	for _, port := range rule.Ports {
+		if port.Port == 0 || port.Port > 65535 {
+			continue
+		}
+		matchPort := config.Port{
+			Protocol: config.TCP,
+			Number:   uint16(port.Port),
+		}
+		switch port.Protocol {
+		case hostpolicymodel.HostPolicy_Port_UDP:
+			matchPort.Protocol = config.UDP
+		case hostpolicymodel.HostPolicy_Port_SCTP:
+			matchPort.Protocol = config.SCTP
+		}
+		if port.EndPort > port.Port && port.EndPort <= 65535 {
+			matchPort.EndNumber = uint16(port.EndPort)
+		}
+		match.Ports = append(match.Ports, matchPort)
+	}
+	return match
+}
@@ -6,6 +6,7 @@ import (
 	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
 	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
 	config "github.com/contiv/vpp/plugins/policy/configurator"
+	"github.com/contiv/vpp/plugins/policy/utils"
 )
 
 // calculateMatches finds the returns a predicate that selects a subset of the traffic by calculating
@@ -68,16 +69,14 @@ func (pp *PolicyProcessor) calculateMatches(policyData *policymodel.Policy, podI
 
 			ingressRulePorts := ingressRule.Port
 			for _, ingressRulePort := range ingressRulePorts {
-				ingressPortProtocol := config.TCP
-				if ingressRulePort.Protocol == policymodel.Policy_Port_UDP {
-					ingressPortProtocol = config.UDP
-				}
+				ingressPortProtocol := portProtocol(ingressRulePort)
 				// A port in kubernetes network policy is either a name (1) or a port number (0)
 				if ingressRulePort.Port.Type == 0 {
 					ingressPortNumber := uint16(ingressRulePort.Port.Number)
 					ingressPorts = append(ingressPorts, config.Port{
-						Protocol: ingressPortProtocol,
-						Number:   ingressPortNumber,
+						Protocol:  ingressPortProtocol,
+						Number:    ingressPortNumber,
+						EndNumber: portRangeEnd(ingressRulePort),
 					})
 				} else {
 					// Obtain data for pod that matches are calculated
@@ -135,6 +134,10 @@ func (pp *PolicyProcessor) calculateMatches(policyData *policymodel.Policy, podI
 					policyPods := pp.Cache.LookupPodsByNsLabelSelector(namespaceLabels)
 					egressPods = append(egressPods, policyPods...)
 				}
+				// Translate DNS names to the currently resolved addresses
+				for _, fqdn := range egressRuleTo.Fqdn {
+					egressIPBlocks = append(egressIPBlocks, pp.fqdnToIPBlocks(fqdn)...)
+				}
 				egressIPBlock := egressRuleTo.IpBlock
 				if egressIPBlock == nil {
 					continue
@@ -157,16 +160,14 @@ func (pp *PolicyProcessor) calculateMatches(policyData *policymodel.Policy, podI
 			egressRulePorts := egressRule.Port
 			// Egress ports to appropriate type
 			for _, egressRulePort := range egressRulePorts {
-				egressPortProtocol := config.TCP
-				if egressRulePort.Protocol == policymodel.Policy_Port_UDP {
-					egressPortProtocol = config.UDP
-				}
+				egressPortProtocol := portProtocol(egressRulePort)
 
 				if egressRulePort.Port.Type == 0 {
 					egressPortNumber := uint16(egressRulePort.Port.Number)
 					egressPorts = append(egressPorts, config.Port{
-						Protocol: egressPortProtocol,
-						Number:   egressPortNumber,
+						Protocol:  egressPortProtocol,
+						Number:    egressPortNumber,
+						EndNumber: portRangeEnd(egressRulePort),
 					})
 				} else {
 					// if there are egressPods then map the portName to portNumber for every each one of them
@@ -219,3 +220,35 @@ func (pp *PolicyProcessor) portNameToNumber(pods []podmodel.ID, portProtocol con
 	}
 	return matches
 }
+
+// fqdnToIPBlocks returns one-host IP blocks for all addresses currently resolved
+// for a given DNS name. Unresolved name does not match any traffic.
+func (pp *PolicyProcessor) fqdnToIPBlocks(fqdn string) (ipBlocks []config.IPBlock) {
+	if pp.FQDNResolver == nil {
+		return nil
+	}
+	for _, addr := range pp.FQDNResolver.LookupFQDN(fqdn) {
+		ipBlocks = append(ipBlocks, config.IPBlock{Network: *utils.GetOneHostSubnetFromIP(addr)})
+	}
+	return ipBlocks
+}
+
+// portProtocol translates the protocol of a policy port into the configurator representation.
+func portProtocol(rulePort *policymodel.Policy_Port) config.ProtocolType {
+	switch rulePort.Protocol {
+	case policymodel.Policy_Port_UDP:
+		return config.UDP
+	case policymodel.Policy_Port_SCTP:
+		return config.SCTP
+	}
+	return config.TCP
+}
+
+// portRangeEnd returns the end of the port range selected by a numerical policy port,
+// or 0 if the port does not define a range.
+func portRangeEnd(rulePort *policymodel.Policy_Port) uint16 {
+	if rulePort.EndPort <= rulePort.Port.Number || rulePort.EndPort > 65535 {
+		return 0
+	}
+	return uint16(rulePort.EndPort)
+}
@@ -18,8 +18,10 @@ package processor
 
 import (
 	"net"
+	"reflect"
 
 	"go.ligato.io/cn-infra/v2/logging"
+	"go.ligato.io/cn-infra/v2/servicelabel"
 
 	"github.com/contiv/vpp/plugins/contivconf"
 	"github.com/contiv/vpp/plugins/ipnet"
@@ -54,6 +56,13 @@ type Deps struct {
 	IPAM         IPAM
 	ContivConf   contivconf.API
 	Configurator config.PolicyConfiguratorAPI
+	FQDNResolver FQDNResolver           /* optional, needed for egress rules with DNS names */
+	ServiceLabel servicelabel.ReaderAPI /* optional, needed for host policies */
+
+	// AllNodes makes the processor to process pods of all nodes, not only those
+	// deployed on this node (e.g. for the policy dry-run). IPAM and ContivConf
+	// are not needed then.
+	AllNodes bool
 }
 
 // IPAM interface lists IPAM methods needed by Policy Processor.
@@ -63,6 +72,18 @@ type IPAM interface {
 	PodSubnetThisNode(network string) *net.IPNet
 }
 
+// FQDNResolver interface lists methods of the DNS resolver needed by Policy Processor.
+type FQDNResolver interface {
+	// SetPolicyFQDNs updates the set of DNS names referenced by a given policy.
+	SetPolicyFQDNs(policy policymodel.ID, fqdns []string)
+
+	// ResyncPolicyFQDNs replaces DNS names referenced by all policies.
+	ResyncPolicyFQDNs(fqdns map[policymodel.ID][]string)
+
+	// LookupFQDN returns addresses currently resolved for a given DNS name.
+	LookupFQDN(fqdn string) []net.IP
+}
+
 // Init initializes the Policy Processor.
 func (pp *PolicyProcessor) Init() error {
 	pp.podIPAddressMap = make(map[podmodel.ID]net.IP)
@@ -81,16 +102,20 @@ func (pp *PolicyProcessor) Process(resync bool, pods []podmodel.ID) error {
 	pods = utils.RemoveDuplicatePodIDs(pods)
 
 	// In case of ipv6 take into account all pods
-	if !pp.ContivConf.GetIPAMConfig().UseIPv6 {
+	if !pp.AllNodes && !pp.ContivConf.GetIPAMConfig().UseIPv6 {
 		// Re-configure only pods that belong to the current node.
 		pods = pp.filterHostPods(pods)
 	}
-	if len(pods) == 0 {
+	if len(pods) == 0 && !resync {
 		return nil
 	}
 
 	txn := pp.Configurator.NewTxn(resync)
+	if resync {
+		txn.ConfigureHost(pp.getHostPolicies())
+	}
 	processedPolicies := make(map[policymodel.ID]*config.ContivPolicy)
+	processedAdminPolicies := make(map[string]*config.ContivPolicy)
 	pp.Log.Debugf("Pods selected for policy pre-processing: %v", pods)
 
 	for _, pod := range pods {
@@ -98,10 +123,6 @@ func (pp *PolicyProcessor) Process(resync bool, pods []podmodel.ID) error {
 
 		// Find the policies the pod in the slice is associated with.
 		policiesByPod := pp.Cache.LookupPoliciesByPod(pod)
-		if len(policiesByPod) == 0 {
-			txn.Configure(pod, policies)
-			continue
-		}
 
 		// Convert each policy from the Kubernetes data model to an instance of ContivPolicy.
 		for _, policy := range policiesByPod {
@@ -145,6 +166,19 @@ func (pp *PolicyProcessor) Process(resync bool, pods []podmodel.ID) error {
 			policies = append(policies, contivPolicy)
 		}
 
+		// Add admin policies whose subject selects the pod.
+		for _, adminPolicy := range pp.Cache.LookupAdminPoliciesByPod(pod) {
+			if contivPolicy, alreadyProcessed = processedAdminPolicies[adminPolicy]; !alreadyProcessed {
+				found, policyData := pp.Cache.LookupAdminPolicy(adminPolicy)
+				if !found {
+					continue
+				}
+				contivPolicy = pp.convertAdminPolicy(policyData)
+				processedAdminPolicies[adminPolicy] = contivPolicy
+			}
+			policies = append(policies, contivPolicy)
+		}
+
 		// Re-configure policies for the pod.
 		pp.Log.WithField("process-resync", resync).
 			Infof("Pod sent to Configurator: %+v, w/ Policies: %+v", pod, policies)
@@ -166,6 +200,15 @@ func (pp *PolicyProcessor) Resync(data *cache.DataResyncEvent) error {
 		pp.podIPAddressMap[podmodel.GetID(pod)] = net.ParseIP(pod.IpAddress)
 	}
 
+	// resync DNS names referenced by policies
+	if pp.FQDNResolver != nil {
+		fqdns := make(map[policymodel.ID][]string)
+		for _, policy := range data.Policies {
+			fqdns[policymodel.GetID(policy)] = getPolicyFQDNs(policy)
+		}
+		pp.FQDNResolver.ResyncPolicyFQDNs(fqdns)
+	}
+
 	return pp.Process(true, pp.Cache.ListAllPods())
 }
 
@@ -181,11 +224,14 @@ func (pp *PolicyProcessor) AddPod(podID podmodel.ID, pod *podmodel.Pod) error {
 
 	// For every matched policy, find all the pods that have the policy attached.
 	pods := []podmodel.ID{}
-	podPolicies := pp.getPoliciesReferencingPod(pod)
+	podPolicies := pp.getPoliciesReferencingPod(pod, true)
 	for _, policy := range podPolicies {
 		pods = append(pods, pp.getPodsAssignedToPolicy(policy)...)
 	}
 
+	// Update pods with admin policies that select the new pod as a peer.
+	pods = append(pods, pp.getPodsWithAdminPoliciesReferencingPod(pod)...)
+
 	// Update newly added pod as well.
 	pods = append(pods, podID)
 
@@ -196,11 +242,14 @@ func (pp *PolicyProcessor) AddPod(podID podmodel.ID, pod *podmodel.Pod) error {
 func (pp *PolicyProcessor) DelPod(podID podmodel.ID, pod *podmodel.Pod) error {
 	// For every matched policy (before removal), find all the pods that have the policy attached.
 	pods := []podmodel.ID{}
-	podPolicies := pp.getPoliciesReferencingPod(pod)
+	podPolicies := pp.getPoliciesReferencingPod(pod, true)
 	for _, policy := range podPolicies {
 		pods = append(pods, pp.getPodsAssignedToPolicy(policy)...)
 	}
 
+	// Update pods with admin policies that selected the pod as a peer.
+	pods = append(pods, pp.getPodsWithAdminPoliciesReferencingPod(pod)...)
+
 	// Update deleted pod as well.
 	pods = append(pods, podID)
 
@@ -229,22 +278,33 @@ func (pp *PolicyProcessor) UpdatePod(podID podmodel.ID, oldPod, newPod *podmodel
 	}
 
 	// For every matched policy (before and now), find all the pods that have the policy attached.
+	// Policies referencing the pod by the namespace are affected only by the change
+	// of the IP address (the namespace itself is not changed).
 	pods := []podmodel.ID{}
+	ipChanged := newPod.IpAddress != oldPod.IpAddress
 	if oldPod.IpAddress != "" {
-		oldPolicies := pp.getPoliciesReferencingPod(oldPod)
+		oldPolicies := pp.getPoliciesReferencingPod(oldPod, ipChanged)
 		for _, policy := range oldPolicies {
 			pods = append(pods, pp.getPodsAssignedToPolicy(policy)...)
 		}
 	}
 	if newPod.IpAddress != "" {
-		newPolicies := pp.getPoliciesReferencingPod(newPod)
+		newPolicies := pp.getPoliciesReferencingPod(newPod, ipChanged)
 		for _, policy := range newPolicies {
 			pods = append(pods, pp.getPodsAssignedToPolicy(policy)...)
 		}
 	}
 
-	// Process this pod also in case the IP address has changed.
-	if newPod.IpAddress != oldPod.IpAddress {
+	// Process this pod and pods with admin policies selecting the pod as a peer
+	// (before and now) in case the IP address has changed, or labels have changed
+	// and the pod may be now selected by a different set of admin policies.
+	if newPod.IpAddress != oldPod.IpAddress || !reflect.DeepEqual(oldPod.Label, newPod.Label) {
+		if oldPod.IpAddress != "" {
+			pods = append(pods, pp.getPodsWithAdminPoliciesReferencingPod(oldPod)...)
+		}
+		if newPod.IpAddress != "" {
+			pods = append(pods, pp.getPodsWithAdminPoliciesReferencingPod(newPod)...)
+		}
 		pods = append(pods, podID)
 	}
 
@@ -261,6 +321,8 @@ func (pp *PolicyProcessor) AddPolicy(policy *policymodel.Policy) error {
 		return nil
 	}
 
+	pp.updatePolicyFQDNs(policymodel.GetID(policy), getPolicyFQDNs(policy))
+
 	// Find all the pods that match the newly added policy.
 	pods := pp.getPodsAssignedToPolicy(policy)
 	return pp.Process(false, pods)
@@ -276,6 +338,8 @@ func (pp *PolicyProcessor) DelPolicy(policy *policymodel.Policy) error {
 		return nil
 	}
 
+	pp.updatePolicyFQDNs(policymodel.GetID(policy), nil)
+
 	// Find all the pods that used to match the removed policy.
 	pods := pp.getPodsAssignedToPolicy(policy)
 	return pp.Process(false, pods)
@@ -295,6 +359,8 @@ func (pp *PolicyProcessor) UpdatePolicy(oldPolicy, newPolicy *policymodel.Policy
 		return nil
 	}
 
+	pp.updatePolicyFQDNs(policymodel.GetID(newPolicy), getPolicyFQDNs(newPolicy))
+
 	// Get all matching pods before the change and now.
 	pods := []podmodel.ID{}
 	pods = append(pods, pp.getPodsAssignedToPolicy(oldPolicy)...)
@@ -303,6 +369,30 @@ func (pp *PolicyProcessor) UpdatePolicy(oldPolicy, newPolicy *policymodel.Policy
 	return pp.Process(false, pods)
 }
 
+// UpdateFQDNs processes the change of addresses resolved for the given DNS names.
+// Policies referencing the names are re-processed for all the pods they are
+// assigned to.
+func (pp *PolicyProcessor) UpdateFQDNs(fqdns []string) error {
+	changed := make(map[string]struct{})
+	for _, fqdn := range fqdns {
+		changed[fqdn] = struct{}{}
+	}
+	pods := []podmodel.ID{}
+	for _, policyID := range pp.Cache.ListAllPolicies() {
+		found, policy := pp.Cache.LookupPolicy(policyID)
+		if !found {
+			continue
+		}
+		for _, fqdn := range getPolicyFQDNs(policy) {
+			if _, isChanged := changed[fqdn]; isChanged {
+				pods = append(pods, pp.getPodsAssignedToPolicy(policy)...)
+				break
+			}
+		}
+	}
+	return pp.Process(false, pods)
+}
+
 // AddNamespace processes the event of newly added namespace (no action needed).
 func (pp *PolicyProcessor) AddNamespace(ns *nsmodel.Namespace) error {
 	return nil
@@ -336,6 +426,17 @@ func (pp *PolicyProcessor) UpdateNamespace(oldNs, newNs *nsmodel.Namespace) erro
 		pods = append(pods, pp.getPodsAssignedToPolicy(policy)...)
 	}
 
+	// Admin policies may select pods by namespace labels either as peers
+	// (before and now) or as subjects - pods of the namespace need to be
+	// re-processed only if the set of admin policies applied to them has changed.
+	pods = append(pods, pp.getPodsWithAdminPoliciesReferencingNamespace(oldNs)...)
+	pods = append(pods, pp.getPodsWithAdminPoliciesReferencingNamespace(newNs)...)
+	oldSubjectOf := pp.Cache.LookupAdminPoliciesBySubjectNamespace(oldNs)
+	newSubjectOf := pp.Cache.LookupAdminPoliciesBySubjectNamespace(newNs)
+	if !reflect.DeepEqual(oldSubjectOf, newSubjectOf) {
+		pods = append(pods, pp.Cache.LookupPodsByNamespace(newNs.Name)...)
+	}
+
 	return pp.Process(false, pods)
 }
 
@@ -372,6 +473,24 @@ func (pp *PolicyProcessor) filterHostPods(pods []podmodel.ID) []podmodel.ID {
 	return hostPods
 }
 
+// updatePolicyFQDNs updates the set of DNS names referenced by a given policy
+// in the resolver.
+func (pp *PolicyProcessor) updatePolicyFQDNs(policy policymodel.ID, fqdns []string) {
+	if pp.FQDNResolver != nil {
+		pp.FQDNResolver.SetPolicyFQDNs(policy, fqdns)
+	}
+}
+
+// getPolicyFQDNs returns all DNS names referenced by egress rules of a policy.
+func getPolicyFQDNs(policy *policymodel.Policy) (fqdns []string) {
+	for _, egressRule := range policy.EgressRule {
+		for _, egressRuleTo := range egressRule.To {
+			fqdns = append(fqdns, egressRuleTo.Fqdn...)
+		}
+	}
+	return utils.RemoveDuplicates(fqdns)
+}
+
 // getPodsAssignedToPolicy returns all pods that have the given policy assigned.
 func (pp *PolicyProcessor) getPodsAssignedToPolicy(policy *policymodel.Policy) (pods []podmodel.ID) {
 	namespace := policy.Namespace
@@ -380,153 +499,37 @@ func (pp *PolicyProcessor) getPodsAssignedToPolicy(policy *policymodel.Policy) (
 	return pods
 }
 
-// getPoliciesAssignedToPod returns all policies currently assigned to a given pod.
-func (pp *PolicyProcessor) getPoliciesReferencingPod(pod *podmodel.Pod) (policies map[policymodel.ID]*policymodel.Policy) {
-	policies = make(map[policymodel.ID]*policymodel.Policy)
-
-	// Fetch data of all policies from the cache.
-	allPolicies := pp.Cache.ListAllPolicies()
-	dataPolicies := []*policymodel.Policy{}
-	for _, policy := range allPolicies {
-		found, policyData := pp.Cache.LookupPolicy(policy)
-
+// getPoliciesReferencingPod returns all policies with a rule whose peer
+// selects the given pod (i.e. policies whose rules depend on the pod).
+// Policies selecting the pod by the namespace are included only if <byNamespace>
+// is true.
+func (pp *PolicyProcessor) getPoliciesReferencingPod(pod *podmodel.Pod, byNamespace bool) (policies map[policymodel.ID]*policymodel.Policy) {
+	policies = pp.lookupPolicies(pp.Cache.LookupPoliciesByPeerPod(pod))
+	if byNamespace {
+		found, ns := pp.Cache.LookupNamespace(nsmodel.ID(pod.Namespace))
 		if !found {
-			continue
+			// namespace not yet known, evaluated as a namespace without labels
+			ns = &nsmodel.Namespace{Name: pod.Namespace}
 		}
-
-		dataPolicies = append(dataPolicies, policyData)
-	}
-
-	// Select policies that match pod's labels.
-	for _, dataPolicy := range dataPolicies {
-		dataPolicyID := policymodel.GetID(dataPolicy)
-		if len(dataPolicy.IngressRule) == 0 {
-			// If Ingress Rule is an empty array, policy matches the PodSelector.
-			policies[dataPolicyID] = dataPolicy
-		} else {
-			for _, ingressRules := range dataPolicy.IngressRule {
-				for _, ingressRule := range ingressRules.From {
-					matchPodSelectorLabels := []*policymodel.Policy_Label{}
-					matchPodSelectorExpressions := []*policymodel.Policy_LabelSelector_LabelExpression{}
-					matchNsSelectorLabels := []*policymodel.Policy_Label{}
-					matchNsSelectorExpressions := []*policymodel.Policy_LabelSelector_LabelExpression{}
-
-					// Only one resource of a Policy Peer can exist (pod or namespace label selectors)
-					// If resource equals to nil then policy is not a match
-					if ingressRule.Pods != nil {
-						matchPodSelectorLabels = ingressRule.Pods.MatchLabel
-						matchPodSelectorExpressions = ingressRule.Pods.MatchExpression
-						if pp.isPodLabelSelectorMatch(pod, matchPodSelectorLabels, matchPodSelectorExpressions, dataPolicy.Namespace) {
-							policies[dataPolicyID] = dataPolicy
-						}
-					} else if ingressRule.Namespaces != nil {
-						matchNsSelectorLabels = ingressRule.Namespaces.MatchLabel
-						matchNsSelectorExpressions = ingressRule.Namespaces.MatchExpression
-						if pp.isNsLabelSelectorMatch(pod, matchNsSelectorLabels, matchNsSelectorExpressions) {
-							policies[dataPolicyID] = dataPolicy
-						}
-					} else {
-						continue
-					}
-				}
-			}
-		}
-
-		if len(dataPolicy.EgressRule) == 0 {
-			// If Egress Rule is an empty array, policy matches the PodSelector.
-			policies[dataPolicyID] = dataPolicy
-		} else {
-			for _, egressRules := range dataPolicy.EgressRule {
-				for _, egressRule := range egressRules.To {
-					matchPodSelectorLabels := []*policymodel.Policy_Label{}
-					matchPodSelectorExpressions := []*policymodel.Policy_LabelSelector_LabelExpression{}
-					matchNsSelectorLabels := []*policymodel.Policy_Label{}
-					matchNsSelectorExpressions := []*policymodel.Policy_LabelSelector_LabelExpression{}
-					// Only one resource of a Policy Peer can exist (Pod or Namespace)
-					// If resource equals to nil then policy is not a match
-					if egressRule.Pods != nil {
-						matchPodSelectorLabels = egressRule.Pods.MatchLabel
-						matchPodSelectorExpressions = egressRule.Pods.MatchExpression
-						if pp.isPodLabelSelectorMatch(pod, matchPodSelectorLabels, matchPodSelectorExpressions, dataPolicy.Namespace) {
-							policies[dataPolicyID] = dataPolicy
-						}
-					} else if egressRule.Namespaces != nil {
-						matchNsSelectorLabels = egressRule.Namespaces.MatchLabel
-						matchNsSelectorExpressions = egressRule.Namespaces.MatchExpression
-						if pp.isNsLabelSelectorMatch(pod, matchNsSelectorLabels, matchNsSelectorExpressions) {
-							policies[dataPolicyID] = dataPolicy
-						}
-					} else {
-						continue
-					}
-				}
-			}
+		for policyID, policy := range pp.getPoliciesReferencingNamespace(ns) {
+			policies[policyID] = policy
 		}
 	}
 	return policies
 }
 
-// getPoliciesAssignedToNamespace returns all policies currently assigned to a namespace.
+// getPoliciesReferencingNamespace returns all policies with a rule whose peer
+// namespace selector selects the given namespace.
 func (pp *PolicyProcessor) getPoliciesReferencingNamespace(ns *nsmodel.Namespace) (policies map[policymodel.ID]*policymodel.Policy) {
-	policies = make(map[policymodel.ID]*policymodel.Policy)
-
-	// Fetch data of all policies from the cache.
-	allPolicies := pp.Cache.ListAllPolicies()
-	dataPolicies := []*policymodel.Policy{}
-	for _, policy := range allPolicies {
-		found, policyData := pp.Cache.LookupPolicy(policy)
-
-		if !found {
-			continue
-		}
-		dataPolicies = append(dataPolicies, policyData)
-	}
-
-	// Select policies that match namespace's labels.
-	for _, dataPolicy := range dataPolicies {
-		dataPolicyID := policymodel.GetID(dataPolicy)
-		if len(dataPolicy.IngressRule) == 0 {
-			// If Ingress Rule is an empty array, policy matches the PodSelector.
-			policies[dataPolicyID] = dataPolicy
-		} else {
-			for _, ingressRules := range dataPolicy.IngressRule {
-				for _, ingressRule := range ingressRules.From {
-					matchNsSelectorLabels := []*policymodel.Policy_Label{}
-					matchNsSelectorExpressions := []*policymodel.Policy_LabelSelector_LabelExpression{}
-					// If resource equals to nil then policy is not a match
-					if ingressRule.Namespaces != nil {
-						matchNsSelectorLabels = ingressRule.Namespaces.MatchLabel
-						matchNsSelectorExpressions = ingressRule.Namespaces.MatchExpression
-						if pp.isNsUpdateLabelSelectorMatch(ns, matchNsSelectorLabels, matchNsSelectorExpressions) {
-							policies[dataPolicyID] = dataPolicy
-						}
-					} else {
-						continue
-					}
-				}
-			}
-		}
+	return pp.lookupPolicies(pp.Cache.LookupPoliciesByPeerNamespace(ns))
+}
 
-		if len(dataPolicy.EgressRule) == 0 {
-			// If Ingress Rule is an empty array, policy matches the PodSelector.
-			policies[dataPolicyID] = dataPolicy
-		} else {
-			for _, egressRules := range dataPolicy.EgressRule {
-				for _, egressRule := range egressRules.To {
-					matchNsSelectorLabels := []*policymodel.Policy_Label{}
-					matchNsSelectorExpressions := []*policymodel.Policy_LabelSelector_LabelExpression{}
-					// If resource equals to nil then policy is not a match
-					if egressRule.Namespaces != nil {
-						matchNsSelectorLabels = egressRule.Namespaces.MatchLabel
-						matchNsSelectorExpressions = egressRule.Namespaces.MatchExpression
-						if pp.isNsUpdateLabelSelectorMatch(ns, matchNsSelectorLabels, matchNsSelectorExpressions) {
-							policies[dataPolicyID] = dataPolicy
-						}
-					} else {
-						continue
-					}
-				}
-			}
+// lookupPolicies returns data of the given policies.
+func (pp *PolicyProcessor) lookupPolicies(policyIDs []policymodel.ID) (policies map[policymodel.ID]*policymodel.Policy) {
+	policies = make(map[policymodel.ID]*policymodel.Policy)
+	for _, policyID := range policyIDs {
+		if found, policyData := pp.Cache.LookupPolicy(policyID); found {
+			policies[policyID] = policyData
 		}
 	}
 	return policies
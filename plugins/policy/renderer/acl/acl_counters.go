@@ -0,0 +1,159 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acl
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"git.fd.io/govpp.git/adapter"
+	govpp "git.fd.io/govpp.git/api"
+	"go.ligato.io/cn-infra/v2/logging"
+	acl_api "go.ligato.io/vpp-agent/v3/plugins/vpp/binapi/vpp1908/acl"
+)
+
+const (
+	// aclMatchesStatPattern matches names of the VPP stats segment entries
+	// with per-rule match counters of ACLs.
+	aclMatchesStatPattern = "^/acl/[0-9]+/matches$"
+
+	// aclMatchesStat is the name of the stats segment entry with per-rule
+	// match counters of the ACL with the given index.
+	aclMatchesStat = "/acl/%d/matches"
+)
+
+// ACLCounters is used by the renderer to read hit counters and indexes
+// of the installed ACLs.
+type ACLCounters interface {
+	// GetRuleHits returns the number of packets matched by each rule
+	// of every ACL installed in the destination network stack.
+	// The map is keyed by ACL names, hits are indexed by the rule position.
+	GetRuleHits() (map[string][]uint64, error)
+
+	// GetACLIndexes returns indexes of all the ACLs installed in the destination
+	// network stack, keyed by ACL names.
+	GetACLIndexes() (map[string]uint32, error)
+}
+
+// StatsReader is used to read the VPP stats segment.
+type StatsReader interface {
+	// DumpStats returns all the stats entries matching the given patterns.
+	DumpStats(patterns ...string) ([]adapter.StatEntry, error)
+}
+
+// VPPACLCounters implements ACLCounters by reading per-rule match counters
+// of the VPP ACL plugin from the stats segment.
+type VPPACLCounters struct {
+	Log       logging.Logger
+	GoVPPChan govpp.Channel /* used to enable the counters and to dump ACL indexes */
+	Stats     StatsReader
+
+	sync.Mutex
+	enabled bool
+}
+
+// GetRuleHits returns the number of packets matched by each rule of every
+// ACL installed in VPP.
+func (c *VPPACLCounters) GetRuleHits() (map[string][]uint64, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.enabled {
+		// VPP counts ACL matches only if explicitly requested
+		req := &acl_api.ACLStatsIntfCountersEnable{Enable: true}
+		reply := &acl_api.ACLStatsIntfCountersEnableReply{}
+		if err := c.GoVPPChan.SendRequest(req).ReceiveReply(reply); err != nil {
+			return nil, fmt.Errorf("failed to enable ACL counters: %v", err)
+		}
+		c.enabled = true
+	}
+
+	// ACLs are re-created with new indexes as policies change,
+	// the mapping has to be therefore refreshed for every read
+	aclNames, err := c.dumpACLNames()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := c.Stats.DumpStats(aclMatchesStatPattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump ACL counters: %v", err)
+	}
+	statNames := make(map[string]string, len(aclNames))
+	for aclIndex, aclName := range aclNames {
+		statNames[fmt.Sprintf(aclMatchesStat, aclIndex)] = aclName
+	}
+	ruleHits := make(map[string][]uint64)
+	for _, entry := range entries {
+		aclName, isInstalled := statNames[string(entry.Name)]
+		if !isInstalled {
+			continue
+		}
+		counters, isCombined := entry.Data.(adapter.CombinedCounterStat)
+		if !isCombined {
+			continue
+		}
+		// sum counters from all the worker threads
+		hits := ruleHits[aclName]
+		for _, threadCounters := range counters {
+			for ruleIdx, counter := range threadCounters {
+				for len(hits) <= ruleIdx {
+					hits = append(hits, 0)
+				}
+				hits[ruleIdx] += counter.Packets()
+			}
+		}
+		ruleHits[aclName] = hits
+	}
+	return ruleHits, nil
+}
+
+// GetACLIndexes returns indexes of all the ACLs installed in VPP, keyed by ACL names.
+func (c *VPPACLCounters) GetACLIndexes() (map[string]uint32, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	aclNames, err := c.dumpACLNames()
+	if err != nil {
+		return nil, err
+	}
+	aclIndexes := make(map[string]uint32, len(aclNames))
+	for aclIndex, aclName := range aclNames {
+		aclIndexes[aclName] = aclIndex
+	}
+	return aclIndexes, nil
+}
+
+// dumpACLNames returns names of all ACLs installed in VPP, keyed by ACL indexes.
+func (c *VPPACLCounters) dumpACLNames() (map[uint32]string, error) {
+	aclNames := make(map[uint32]string)
+	req := &acl_api.ACLDump{ACLIndex: ^uint32(0)}
+	reqCtx := c.GoVPPChan.SendMultiRequest(req)
+	for {
+		msg := &acl_api.ACLDetails{}
+		stop, err := reqCtx.ReceiveReply(msg)
+		if stop {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to dump ACLs: %v", err)
+		}
+		// vpp-agent installs ACLs with the name stored as the tag
+		aclNames[msg.ACLIndex] = strings.TrimRight(string(msg.Tag), "\x00")
+	}
+	c.Log.Debugf("Dumped %d ACLs", len(aclNames))
+	return aclNames, nil
+}
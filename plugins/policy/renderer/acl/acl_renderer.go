@@ -17,8 +17,11 @@
 package acl
 
 import (
+	"errors"
 	"net"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/golang/protobuf/proto"
 
@@ -45,6 +48,9 @@ const (
 
 	ipv4AddrAny = "0.0.0.0/0"
 	ipv6AddrAny = "::/0"
+
+	// IANA-assigned IP protocol number of SCTP
+	sctpProtocolNumber = 132
 )
 
 // Renderer renders Contiv Rules into VPP ACLs.
@@ -53,8 +59,21 @@ const (
 type Renderer struct {
 	Deps
 
+	// access to the cache is synchronized between the transactions
+	// and the reading of the rule counters
+	access sync.Mutex
+
 	cache         *cache.RendererCache
 	podInterfaces PodInterfaces
+
+	// rules for the traffic destined to this node (nil = node not isolated)
+	hostRules []*renderer.ContivRule
+
+	// number of ACL rules preceding the global table in the host interconnect ACL
+	globalTableOffset int
+
+	// rules restricting access to service frontends
+	frontendRules []*renderer.ContivRule
 }
 
 // Deps lists dependencies of Renderer.
@@ -65,6 +84,15 @@ type Deps struct {
 	ContivConf       ContivConf
 	UpdateTxnFactory func() (txn controller.UpdateOperations)
 	ResyncTxnFactory func() (txn controller.ResyncOperations)
+	ACLCounters      ACLCounters /* optional, needed for GetRuleCounters() and ACL indexes */
+
+	// FrontendRules returns rules restricting access to service frontends
+	// (optional, loaded on every commit)
+	FrontendRules func() []*renderer.ContivRule
+
+	// TCP and UDP ports of the node never blocked by host rules
+	HostAllowedTCPPorts []uint16
+	HostAllowedUDPPorts []uint16
 }
 
 // ContivConf interface lists methods from ContivConf plugin which are needed
@@ -86,6 +114,14 @@ type RendererTxn struct {
 	cacheTxn cache.Txn
 	renderer *Renderer
 	resync   bool
+
+	// host rules set by RenderHost()
+	hostRules     []*renderer.ContivRule
+	withHostRules bool
+
+	// frontend rules loaded by Commit()
+	frontendRules     []*renderer.ContivRule
+	withFrontendRules bool
 }
 
 // PodInterfaces is a map used to remember interface of each (configured) pod.
@@ -119,6 +155,12 @@ func (r *Renderer) NewTxn(resync bool) renderer.Txn {
 	return txn
 }
 
+// CanMatchPorts returns false for SCTP - VPP ACL matches L4 ports only for TCP
+// and UDP. Rules restricting SCTP ports are therefore rejected by the configurator.
+func (r *Renderer) CanMatchPorts(protocol renderer.ProtocolType) bool {
+	return protocol != renderer.SCTP
+}
+
 // Render applies the set of ingress & egress rules for a given pod.
 // The existing rules are replaced.
 // Te actual change is performed only after the commit.
@@ -138,6 +180,10 @@ func (art *RendererTxn) Render(pod podmodel.ID, podIP *net.IPNet, ingress []*ren
 // calculated using RendererCache and applied as one transaction via the
 // localclient.
 func (art *RendererTxn) Commit() error {
+	art.renderer.access.Lock()
+	defer art.renderer.access.Unlock()
+
+	art.loadFrontendRules()
 	if art.resync {
 		return art.commitResync()
 	}
@@ -147,14 +193,25 @@ func (art *RendererTxn) Commit() error {
 		hasReflectiveACL bool
 	)
 
-	if art.renderer.cache.GetGlobalTable().NumOfRules != 0 ||
-		len(art.renderer.cache.GetIsolatedPods()) > 0 {
+	wasHostIsolated := art.renderer.hostRules != nil
+	hadFrontendRules := len(art.renderer.frontendRules) > 0
+	if len(art.renderer.cache.GetIsolatedPods()) > 0 || (!hadFrontendRules &&
+		(art.renderer.cache.GetGlobalTable().NumOfRules != 0 || wasHostIsolated)) {
 		hasReflectiveACL = true
 	}
+	hostIsolationChanged := wasHostIsolated != art.isHostIsolated()
+	hostRulesChanged := art.hostRulesChanged()
+	if art.withHostRules {
+		art.renderer.hostRules = art.hostRules
+	}
+	frontendRulesChanged := art.frontendRulesChanged()
+	if art.withFrontendRules {
+		art.renderer.frontendRules = art.frontendRules
+	}
 
 	// Get the minimalistic diff to be rendered.
 	changes := art.cacheTxn.GetChanges()
-	if len(changes) == 0 {
+	if len(changes) == 0 && !hostRulesChanged && !frontendRulesChanged {
 		// Still need to commit the configuration updates from the transaction.
 		return art.cacheTxn.Commit()
 	}
@@ -186,6 +243,10 @@ func (art *RendererTxn) Commit() error {
 
 	// Render the global table.
 	var gtAddedOrDeleted bool // will be true if global table is being added / removed (not updated)
+	if globalTable == nil && hostIsolationChanged && art.cacheTxn.GetGlobalTable().NumOfRules > 0 {
+		// interfaces with the global table installed have changed
+		globalTable = art.cacheTxn.GetGlobalTable()
+	}
 	if globalTable != nil {
 		globalACL := art.renderACL(globalTable, false)
 		if globalTable.NumOfRules == 0 {
@@ -194,7 +255,7 @@ func (art *RendererTxn) Commit() error {
 			gtAddedOrDeleted = true
 		} else {
 			// Update content of the global table.
-			globalACL.Interfaces.Egress = art.getNodeOutputInterfaces()
+			globalACL.Interfaces.Egress = art.getGlobalTableInterfaces()
 			txn.Put(vpp_acl.Key(globalACL.Name), globalACL)
 			if art.renderer.cache.GetGlobalTable().NumOfRules == 0 {
 				gtAddedOrDeleted = true
@@ -203,7 +264,8 @@ func (art *RendererTxn) Commit() error {
 	}
 
 	// Render the reflective ACL
-	if gtAddedOrDeleted || !art.cacheTxn.GetIsolatedPods().Equals(art.renderer.cache.GetIsolatedPods()) {
+	if gtAddedOrDeleted || hostIsolationChanged || hadFrontendRules != art.hasFrontendRules() ||
+		!art.cacheTxn.GetIsolatedPods().Equals(art.renderer.cache.GetIsolatedPods()) {
 		reflectiveACL := art.reflectiveACL()
 		if len(reflectiveACL.Interfaces.Ingress) == 0 {
 			if hasReflectiveACL {
@@ -214,6 +276,34 @@ func (art *RendererTxn) Commit() error {
 		}
 	}
 
+	// Render the frontend ACL.
+	if art.hasFrontendRules() {
+		if frontendRulesChanged || hostIsolationChanged {
+			frontendACL := art.frontendACL()
+			txn.Put(vpp_acl.Key(frontendACL.Name), frontendACL)
+		}
+	} else if hadFrontendRules {
+		txn.Delete(vpp_acl.Key(ACLNamePrefix + FrontendACLName))
+	}
+
+	// Render the host ACLs (the ACL on the host interconnect includes the global table,
+	// the ACL on physical interfaces includes the frontend rules).
+	if art.isHostIsolated() {
+		if hostRulesChanged || globalTable != nil || frontendRulesChanged {
+			hostACL := art.hostACL()
+			if len(hostACL.Interfaces.Ingress) > 0 {
+				txn.Put(vpp_acl.Key(hostACL.Name), hostACL)
+			}
+			hostInterconnectACL := art.hostInterconnectACL()
+			txn.Put(vpp_acl.Key(hostInterconnectACL.Name), hostInterconnectACL)
+		}
+	} else if wasHostIsolated {
+		if len(art.getNodePhysicalInterfaces()) > 0 {
+			txn.Delete(vpp_acl.Key(ACLNamePrefix + HostACLName))
+		}
+		txn.Delete(vpp_acl.Key(ACLNamePrefix + HostInterconnectACLName))
+	}
+
 	// Save changes into the cache.
 	return art.cacheTxn.Commit()
 }
@@ -225,6 +315,12 @@ func (art *RendererTxn) commitResync() error {
 	// reset the cache and the renderer internal state first
 	art.renderer.cache.Flush()
 	art.renderer.podInterfaces = make(PodInterfaces)
+	if art.withHostRules {
+		art.renderer.hostRules = art.hostRules
+	}
+	if art.withFrontendRules {
+		art.renderer.frontendRules = art.frontendRules
+	}
 
 	// after the flush, changes == all newly created
 	changes := art.cacheTxn.GetChanges()
@@ -232,7 +328,7 @@ func (art *RendererTxn) commitResync() error {
 		if change.Table.Type == cache.Global {
 			// global table
 			globalACL := art.renderACL(change.Table, false)
-			globalACL.Interfaces.Egress = art.getNodeOutputInterfaces()
+			globalACL.Interfaces.Egress = art.getGlobalTableInterfaces()
 			txn.Put(vpp_acl.Key(globalACL.Name), globalACL)
 		} else {
 			// local table
@@ -247,10 +343,177 @@ func (art *RendererTxn) commitResync() error {
 		txn.Put(vpp_acl.Key(reflectiveACL.Name), reflectiveACL)
 	}
 
+	// frontend ACL
+	if art.hasFrontendRules() {
+		frontendACL := art.frontendACL()
+		txn.Put(vpp_acl.Key(frontendACL.Name), frontendACL)
+	}
+
+	// host ACLs
+	if art.isHostIsolated() {
+		hostACL := art.hostACL()
+		if len(hostACL.Interfaces.Ingress) > 0 {
+			txn.Put(vpp_acl.Key(hostACL.Name), hostACL)
+		}
+		hostInterconnectACL := art.hostInterconnectACL()
+		txn.Put(vpp_acl.Key(hostInterconnectACL.Name), hostInterconnectACL)
+	}
+
 	// save changes into the cache.
 	return art.cacheTxn.Commit()
 }
 
+// GetRuleCounters returns hit counters of all the rules from the installed
+// ACLs (except for the reflective ACL), mapped back to the policies which
+// the rules were generated from.
+// Counters of a local table shared by multiple pods are aggregated for all
+// the pods.
+func (r *Renderer) GetRuleCounters() ([]*renderer.RuleCounter, error) {
+	if r.ACLCounters == nil {
+		return nil, errors.New("ACL counters are not available")
+	}
+	ruleHits, err := r.ACLCounters.GetRuleHits()
+	if err != nil {
+		return nil, err
+	}
+
+	r.access.Lock()
+	defer r.access.Unlock()
+
+	// collect all the installed tables
+	tables := []*cache.ContivRuleTable{}
+	tableIDs := make(map[string]struct{})
+	for podID := range r.cache.GetIsolatedPods() {
+		table := r.cache.GetLocalTableByPod(podID)
+		if table == nil {
+			continue
+		}
+		if _, duplicate := tableIDs[table.GetID()]; duplicate {
+			continue
+		}
+		tableIDs[table.GetID()] = struct{}{}
+		tables = append(tables, table)
+	}
+	if globalTable := r.cache.GetGlobalTable(); globalTable.NumOfRules > 0 {
+		tables = append(tables, globalTable)
+	}
+
+	var counters []*renderer.RuleCounter
+	for _, table := range tables {
+		acl, isRendered := table.Private.(*vpp_acl.ACL)
+		if !isRendered {
+			continue
+		}
+		hits := ruleHits[acl.Name]
+		var hostHits []uint64
+		if table.Type == cache.Global && r.hostRules != nil {
+			// with the node isolated, the global table is also included in the ACL
+			// on the host interconnect, preceded by the host rules
+			hostHits = ruleHits[ACLNamePrefix+HostInterconnectACLName]
+		}
+		aclRuleIdx := 0
+		for i := 0; i < table.NumOfRules; i++ {
+			rule := table.Rules[i]
+			counter := &renderer.RuleCounter{
+				Table:    acl.Name,
+				Rule:     rule,
+				Policies: r.cache.GetRulePolicies(table, rule),
+				// the global table is applied on the traffic leaving the node
+				Egress: table.Type == cache.Global,
+			}
+			for podID := range r.cache.GetRulePods(table, rule) {
+				counter.Pods = append(counter.Pods, podID)
+			}
+			sort.Slice(counter.Pods, func(i, j int) bool {
+				return counter.Pods[i].String() < counter.Pods[j].String()
+			})
+			// one contiv rule may be rendered as multiple ACL rules (see expandAnyAddr)
+			for j := 0; j < numOfACLRules(rule); j++ {
+				counter.Packets += ruleHit(hits, aclRuleIdx)
+				counter.Packets += ruleHit(hostHits, r.globalTableOffset+aclRuleIdx)
+				aclRuleIdx++
+			}
+			counters = append(counters, counter)
+		}
+	}
+	return counters, nil
+}
+
+// ruleHit returns the number of hits of the ACL rule with the given index
+// (zero if the counter is not available).
+func ruleHit(hits []uint64, aclRuleIdx int) uint64 {
+	if aclRuleIdx < len(hits) {
+		return hits[aclRuleIdx]
+	}
+	return 0
+}
+
+// GetPodTables returns ACLs applied to the traffic of every pod configured
+// by the renderer, with the ACL indexes assigned by VPP (if ACLCounters
+// are available).
+// Isolated pods have the local table installed in the egress direction
+// of the pod interface and the reflective ACL in the ingress direction.
+// The global table is installed on the interfaces connecting the node
+// with the outside world and applies to the traffic of all the pods.
+func (r *Renderer) GetPodTables() ([]*renderer.PodTables, error) {
+	var aclIndexes map[string]uint32
+	if r.ACLCounters != nil {
+		var err error
+		aclIndexes, err = r.ACLCounters.GetACLIndexes()
+		if err != nil {
+			return nil, err
+		}
+	}
+	installedTable := func(aclName string, rules []*renderer.ContivRule, attached, egress bool) *renderer.InstalledTable {
+		table := &renderer.InstalledTable{
+			Name:     aclName,
+			Index:    -1,
+			Attached: attached,
+			Egress:   egress,
+			Rules:    rules,
+		}
+		if aclIndex, installed := aclIndexes[aclName]; installed {
+			table.Index = int(aclIndex)
+		}
+		return table
+	}
+
+	r.access.Lock()
+	defer r.access.Unlock()
+
+	var globalTable *renderer.InstalledTable
+	if table := r.cache.GetGlobalTable(); table.NumOfRules > 0 {
+		globalTable = installedTable(ACLNamePrefix+table.GetID(), table.Rules[:table.NumOfRules], false, true)
+	}
+	isolatedPods := r.cache.GetIsolatedPods()
+
+	var podTables []*renderer.PodTables
+	for podID := range r.cache.GetAllPods() {
+		tables := &renderer.PodTables{
+			Pod:       podID,
+			Interface: r.podInterfaces[podID],
+		}
+		if localTable := r.cache.GetLocalTableByPod(podID); localTable != nil && isolatedPods.Has(podID) {
+			tables.Tables = append(tables.Tables,
+				installedTable(ACLNamePrefix+localTable.GetID(), localTable.Rules[:localTable.NumOfRules], true, false),
+				installedTable(ACLNamePrefix+ReflectiveACLName, []*renderer.ContivRule{{
+					Action:      renderer.ActionPermit,
+					SrcNetwork:  &net.IPNet{},
+					DestNetwork: &net.IPNet{},
+					Protocol:    renderer.ANY,
+				}}, true, true))
+		}
+		if globalTable != nil {
+			tables.Tables = append(tables.Tables, globalTable)
+		}
+		podTables = append(podTables, tables)
+	}
+	sort.Slice(podTables, func(i, j int) bool {
+		return podTables[i].Pod.String() < podTables[j].Pod.String()
+	})
+	return podTables, nil
+}
+
 // reflectiveACL returns the configuration of the reflective ACL.
 func (art *RendererTxn) reflectiveACL() *vpp_acl.ACL {
 	// Prepare table to render the ACL from.
@@ -268,12 +531,50 @@ func (art *RendererTxn) reflectiveACL() *vpp_acl.ACL {
 	table.Pods = art.cacheTxn.GetIsolatedPods()
 	// Render the ACL.
 	acl := art.renderACL(table, true)
+	if art.hasFrontendRules() {
+		// interfaces connecting the node with the outside world have the frontend
+		// ACL (or the host ACL) installed instead
+		return acl
+	}
 	if art.cacheTxn.GetGlobalTable().NumOfRules > 0 {
-		acl.Interfaces.Ingress = append(acl.Interfaces.Ingress, art.getNodeOutputInterfaces()...)
+		if art.isHostIsolated() {
+			// physical interfaces have the host ACL installed instead
+			acl.Interfaces.Ingress = append(acl.Interfaces.Ingress, art.getNodeVirtualOutputInterfaces()...)
+		} else {
+			acl.Interfaces.Ingress = append(acl.Interfaces.Ingress, art.getNodeOutputInterfaces()...)
+		}
+	} else if art.isHostIsolated() {
+		// responses to connections opened by the host are delivered only via
+		// the sessions reflected on the host interconnect (see hostInterconnectACL)
+		acl.Interfaces.Ingress = append(acl.Interfaces.Ingress, art.renderer.IPNet.GetHostInterconnectIfName())
 	}
 	return acl
 }
 
+// getGlobalTableInterfaces returns the list of interfaces with the global table
+// installed in the egress direction.
+func (art *RendererTxn) getGlobalTableInterfaces() []string {
+	if !art.isHostIsolated() {
+		return art.getNodeOutputInterfaces()
+	}
+	// the host interconnect has the global table included in the host ACL
+	interfaces := art.getNodePhysicalInterfaces()
+	if vxlanBVI := art.renderer.IPNet.GetVxlanBVIIfName(); vxlanBVI != "" {
+		interfaces = append(interfaces, vxlanBVI)
+	}
+	return interfaces
+}
+
+// getNodeVirtualOutputInterfaces returns the list of non-physical interfaces that
+// connect this K8s node with the outside world.
+func (art *RendererTxn) getNodeVirtualOutputInterfaces() []string {
+	interfaces := []string{art.renderer.IPNet.GetHostInterconnectIfName()}
+	if vxlanBVI := art.renderer.IPNet.GetVxlanBVIIfName(); vxlanBVI != "" {
+		interfaces = append(interfaces, vxlanBVI)
+	}
+	return interfaces
+}
+
 // getNodeOutputInterfaces returns the list of interfaces that connect this K8s node
 // with the outside world.
 func (art *RendererTxn) getNodeOutputInterfaces() []string {
@@ -329,6 +630,16 @@ func expandAnyAddr(rule *vpp_acl.ACL_Rule) []*vpp_acl.ACL_Rule {
 
 }
 
+// numOfACLRules returns the number of ACL rules that the given Contiv rule
+// is rendered as.
+func numOfACLRules(rule *renderer.ContivRule) int {
+	if len(rule.SrcNetwork.IP) == 0 && len(rule.DestNetwork.IP) == 0 {
+		// expanded into IPv4 and IPv6 rule
+		return 2
+	}
+	return 1
+}
+
 // renderACL renders ContivRuleTable into the equivalent ACL configuration.
 func (art *RendererTxn) renderACL(table *cache.ContivRuleTable, isReflectiveACL bool) *vpp_acl.ACL {
 	const maxPortNum = ^uint16(0)
@@ -369,11 +680,7 @@ func (art *RendererTxn) renderACL(table *cache.ContivRuleTable, isReflectiveACL
 			}
 			aclRule.IpRule.Tcp.DestinationPortRange = &vpp_acl.ACL_Rule_IpRule_PortRange{}
 			aclRule.IpRule.Tcp.DestinationPortRange.LowerPort = uint32(rule.DestPort)
-			if rule.DestPort == 0 {
-				aclRule.IpRule.Tcp.DestinationPortRange.UpperPort = uint32(maxPortNum)
-			} else {
-				aclRule.IpRule.Tcp.DestinationPortRange.UpperPort = uint32(rule.DestPort)
-			}
+			aclRule.IpRule.Tcp.DestinationPortRange.UpperPort = uint32(destPortUpperBound(rule))
 		}
 		if rule.Protocol == renderer.UDP {
 			aclRule.IpRule.Udp = &vpp_acl.ACL_Rule_IpRule_Udp{}
@@ -386,10 +693,19 @@ func (art *RendererTxn) renderACL(table *cache.ContivRuleTable, isReflectiveACL
 			}
 			aclRule.IpRule.Udp.DestinationPortRange = &vpp_acl.ACL_Rule_IpRule_PortRange{}
 			aclRule.IpRule.Udp.DestinationPortRange.LowerPort = uint32(rule.DestPort)
-			if rule.DestPort == 0 {
-				aclRule.IpRule.Udp.DestinationPortRange.UpperPort = uint32(maxPortNum)
-			} else {
-				aclRule.IpRule.Udp.DestinationPortRange.UpperPort = uint32(rule.DestPort)
+			aclRule.IpRule.Udp.DestinationPortRange.UpperPort = uint32(destPortUpperBound(rule))
+		}
+		if rule.Protocol == renderer.SCTP {
+			// VPP ACL matches L4 ports only for TCP and UDP, SCTP is therefore
+			// matched by the IP protocol number.
+			aclRule.IpRule.Ip.Protocol = sctpProtocolNumber
+			if rule.SrcPort != 0 || rule.DestPort != 0 {
+				// Should not happen - such rules are rejected by the configurator
+				// (see CanMatchPorts). Rendering the rule without the ports would
+				// allow all SCTP traffic, deny it instead.
+				art.renderer.Log.Errorf("SCTP ports cannot be matched by VPP ACL, "+
+					"rendering %s as deny of all SCTP traffic", rule)
+				aclRule.Action = vpp_acl.ACL_Rule_DENY
 			}
 		}
 		acl.Rules = append(acl.Rules, expandAnyAddr(aclRule)...)
@@ -399,6 +715,17 @@ func (art *RendererTxn) renderACL(table *cache.ContivRuleTable, isReflectiveACL
 	return acl
 }
 
+// destPortUpperBound returns the upper bound of the destination port range matched by the rule.
+func destPortUpperBound(rule *renderer.ContivRule) uint16 {
+	if rule.DestPort == 0 {
+		return ^uint16(0)
+	}
+	if rule.DestPortEnd > rule.DestPort {
+		return rule.DestPortEnd
+	}
+	return rule.DestPort
+}
+
 // renderInterfaces renders a set of Interface names into the corresponding
 // instance of AccessLists_Acl_Interfaces.
 func (art *RendererTxn) renderInterfaces(pods cache.PodSet, ingress bool) *vpp_acl.ACL_Interfaces {
@@ -18,6 +18,7 @@ package acl
 
 import (
 	"context"
+	"net"
 	"testing"
 
 	"github.com/onsi/gomega"
@@ -249,6 +250,20 @@ func TestEgressRulesOnePod(t *testing.T) {
 	verifyReflectiveACL(aclEngine, ipNet, contivConf, Pod1IfName, false, true)
 	verifyGlobalTable(aclEngine, ipNet, contivConf, false)
 
+	// Test tables reported for the pod (ACL indexes are not available without ACLCounters).
+	podTables, err := aclRenderer.GetPodTables()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(podTables).To(gomega.HaveLen(1))
+	gomega.Expect(podTables[0].Pod).To(gomega.Equal(Pod1))
+	gomega.Expect(podTables[0].Interface).To(gomega.Equal(Pod1IfName))
+	gomega.Expect(podTables[0].Tables).To(gomega.HaveLen(2))
+	gomega.Expect(podTables[0].Tables[0].Attached).To(gomega.BeTrue())
+	gomega.Expect(podTables[0].Tables[0].Egress).To(gomega.BeFalse())
+	gomega.Expect(podTables[0].Tables[0].Index).To(gomega.Equal(-1))
+	gomega.Expect(podTables[0].Tables[0].Rules).ToNot(gomega.BeEmpty())
+	gomega.Expect(podTables[0].Tables[1].Name).To(gomega.Equal(ACLNamePrefix + ReflectiveACLName))
+	gomega.Expect(podTables[0].Tables[1].Egress).To(gomega.BeTrue())
+
 	// Test connections (Pod1 can receive connection only from 10.10.0.0/16:[TCP:ANY]).
 	gomega.Expect(aclEngine.ConnectionPodToPod(Pod1, Pod6, renderer.TCP, somePort, 80)).To(gomega.Equal(ConnActionAllow))
 	gomega.Expect(aclEngine.ConnectionPodToPod(Pod6, Pod1, renderer.TCP, somePort, 80)).To(gomega.Equal(ConnActionAllow))
@@ -1123,3 +1138,691 @@ func TestCombinedRulesWithRemovedPods(t *testing.T) {
 	verifyReflectiveACL(aclEngine, ipNet, contivConf, "", false, false)
 	verifyGlobalTable(aclEngine, ipNet, contivConf, false)
 }
+
+func TestHostRules(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestHostRules")
+
+	// Prepare input data
+	ingress := []*renderer.ContivRule{Ts6.Rule1 /* UDP, OTHER not allowed */, Ts6.Rule2}
+	egress := []*renderer.ContivRule{}
+	hostRules := []*renderer.ContivRule{
+		{
+			Action:      renderer.ActionPermit,
+			SrcNetwork:  IpNetwork("172.16.1.0/24"),
+			DestNetwork: IpNetwork(""),
+			Protocol:    renderer.TCP,
+			DestPort:    22,
+		},
+		DenyAll(),
+	}
+	const (
+		nodeIP = "10.20.0.2"
+		hostIP = "192.168.16.2"
+	)
+
+	// Prepare mocks.
+	//  -> ContivConf plugin
+	contivConf := &contivConfMock{}
+	contivConf.SetMainInterfaceName(mainIfName)
+
+	//  -> IPNet plugin
+	ipNet := NewMockIPNet()
+	ipNet.SetVxlanBVIIfName(vxlanIfName)
+	ipNet.SetHostInterconnectIfName(hostInterIfName)
+	ipNet.SetPodIfName(Pod1, Pod1IfName)
+	ipNet.SetNodeIP(&net.IPNet{IP: net.ParseIP(nodeIP), Mask: net.CIDRMask(24, 32)})
+	ipNet.SetHostIPs([]net.IP{net.ParseIP(hostIP)})
+
+	// -> ACL engine
+	aclEngine := NewMockACLEngine(logger, ipNet, contivConf)
+	aclEngine.RegisterPod(Pod1, Pod1IP, false)
+	aclEngine.RegisterPod(Pod6, Pod6IP, true)
+
+	// -> localclient
+	txnTracker := localclient.NewTxnTracker(aclEngine.ApplyTxn)
+
+	// Prepare ACL Renderer.
+	aclRenderer := &Renderer{
+		Deps: Deps{
+			Log:                 logger,
+			ContivConf:          contivConf,
+			IPNet:               ipNet,
+			ResyncTxnFactory:    resyncTxnFactory(txnTracker),
+			UpdateTxnFactory:    updateTxnFactory(txnTracker),
+			HostAllowedTCPPorts: []uint16{6443},
+		},
+	}
+	aclRenderer.Init()
+
+	// Execute Renderer transaction.
+	err := aclRenderer.NewTxn(true).Render(Pod1, GetOneHostSubnet(Pod1IP), ingress, egress, false).Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commitTxn()).To(gomega.BeNil())
+	gomega.Expect(aclEngine.GetNumOfACLs()).To(gomega.Equal(3))
+
+	// Isolate the node.
+	txn := aclRenderer.NewTxn(false).(*RendererTxn)
+	err = txn.RenderHost(hostRules).Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commitTxn()).To(gomega.BeNil())
+	gomega.Expect(txnTracker.CommittedTxns).To(gomega.HaveLen(2))
+
+	// Test ACLs.
+	gomega.Expect(aclEngine.GetNumOfACLs()).To(gomega.Equal(5))
+
+	// -> physical interfaces are filtered by the host ACL (instead of the reflective ACL)
+	hostACL := aclEngine.GetInboundACL(mainIfName)
+	gomega.Expect(hostACL).ToNot(gomega.BeNil())
+	gomega.Expect(hostACL.Name).To(gomega.Equal(ACLNamePrefix + HostACLName))
+	gomega.Expect(hostACL.Interfaces.Egress).To(gomega.HaveLen(0))
+	for _, rule := range hostACL.Rules {
+		gomega.Expect(rule.Action).ToNot(gomega.Equal(vpp_acl.ACL_Rule_PERMIT))
+	}
+	reflectiveACL := aclEngine.GetACLByName(ACLNamePrefix + ReflectiveACLName)
+	gomega.Expect(reflectiveACL).ToNot(gomega.BeNil())
+	gomega.Expect(reflectiveACL.Interfaces.Ingress).ToNot(gomega.ContainElement(mainIfName))
+	gomega.Expect(reflectiveACL.Interfaces.Ingress).To(gomega.ContainElement(hostInterIfName))
+
+	// -> host interconnect is filtered by the host ACL including the global table
+	hostInterconnectACL := aclEngine.GetOutboundACL(hostInterIfName)
+	gomega.Expect(hostInterconnectACL).ToNot(gomega.BeNil())
+	gomega.Expect(hostInterconnectACL.Name).To(gomega.Equal(ACLNamePrefix + HostInterconnectACLName))
+	globalACL := aclEngine.GetACLByName(ACLNamePrefix + cache.GlobalTableID)
+	gomega.Expect(globalACL).ToNot(gomega.BeNil())
+	gomega.Expect(globalACL.Interfaces.Egress).ToNot(gomega.ContainElement(hostInterIfName))
+	gomega.Expect(globalACL.Interfaces.Egress).To(gomega.ContainElement(mainIfName))
+	gomega.Expect(len(hostInterconnectACL.Rules)).To(gomega.BeNumerically(">", len(globalACL.Rules)))
+
+	// -> host rules are restricted to the node addresses and preceded by the safe defaults
+	var sshRules, denyRules, apiServerRules []string
+	for _, rule := range hostACL.Rules {
+		ip := rule.IpRule.Ip
+		if rule.Action == vpp_acl.ACL_Rule_DENY {
+			denyRules = append(denyRules, ip.DestinationNetwork)
+			continue
+		}
+		if tcp := rule.IpRule.Tcp; tcp != nil && tcp.DestinationPortRange.LowerPort == 22 {
+			gomega.Expect(ip.SourceNetwork).To(gomega.Equal("172.16.1.0/24"))
+			sshRules = append(sshRules, ip.DestinationNetwork)
+		}
+		if tcp := rule.IpRule.Tcp; tcp != nil && tcp.DestinationPortRange.LowerPort == 6443 {
+			gomega.Expect(denyRules).To(gomega.BeEmpty())
+			apiServerRules = append(apiServerRules, ip.DestinationNetwork)
+		}
+	}
+	gomega.Expect(sshRules).To(gomega.ConsistOf(nodeIP+"/32", hostIP+"/32"))
+	gomega.Expect(denyRules).To(gomega.ConsistOf(nodeIP+"/32", hostIP+"/32"))
+	gomega.Expect(apiServerRules).To(gomega.ConsistOf(nodeIP+"/32", hostIP+"/32"))
+	lastRule := hostACL.Rules[len(hostACL.Rules)-1]
+	gomega.Expect(lastRule.Action).To(gomega.Equal(vpp_acl.ACL_Rule_REFLECT))
+	gomega.Expect(lastRule.IpRule.Tcp).To(gomega.BeNil())
+
+	// -> ephemeral ports are open on physical interfaces, but the host stack only
+	//    receives responses matching sessions reflected on the host interconnect
+	hasEphemeralRule := func(acl *vpp_acl.ACL) bool {
+		for _, rule := range acl.Rules {
+			if tcp := rule.IpRule.Tcp; tcp != nil && tcp.DestinationPortRange.LowerPort == minEphemeralPort &&
+				tcp.DestinationPortRange.UpperPort == maxEphemeralPort {
+				return true
+			}
+		}
+		return false
+	}
+	gomega.Expect(hasEphemeralRule(hostACL)).To(gomega.BeTrue())
+	gomega.Expect(hasEphemeralRule(hostInterconnectACL)).To(gomega.BeFalse())
+
+	// Pods are not affected.
+	gomega.Expect(aclEngine.ConnectionInternetToPod(googleDNS, Pod1, renderer.TCP, somePort, 80)).To(gomega.Equal(ConnActionAllow))
+	gomega.Expect(aclEngine.ConnectionPodToInternet(Pod1, "10.10.50.1", renderer.TCP, somePort, 80)).To(gomega.Equal(ConnActionAllow))
+	gomega.Expect(aclEngine.ConnectionPodToInternet(Pod1, "10.10.50.1", renderer.UDP, somePort, 53)).To(gomega.Equal(ConnActionDenySyn))
+
+	// Try to execute the same change again.
+	txn = aclRenderer.NewTxn(false).(*RendererTxn)
+	err = txn.RenderHost(hostRules).Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commitTxn()).To(gomega.BeNil())
+	gomega.Expect(txnTracker.CommittedTxns).To(gomega.HaveLen(2))
+
+	// Remove the isolation.
+	txn = aclRenderer.NewTxn(false).(*RendererTxn)
+	err = txn.RenderHost(nil).Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commitTxn()).To(gomega.BeNil())
+	gomega.Expect(aclEngine.GetNumOfACLs()).To(gomega.Equal(3))
+	verifyReflectiveACL(aclEngine, ipNet, contivConf, Pod1IfName, true, true)
+	verifyGlobalTable(aclEngine, ipNet, contivConf, true)
+}
+
+func TestHostRulesWithEmptyGlobalTable(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestHostRulesWithEmptyGlobalTable")
+
+	// Prepare input data (no pod policies)
+	hostRules := []*renderer.ContivRule{
+		{
+			Action:      renderer.ActionPermit,
+			SrcNetwork:  IpNetwork("172.16.1.0/24"),
+			DestNetwork: IpNetwork(""),
+			Protocol:    renderer.TCP,
+			DestPort:    22,
+		},
+		DenyAll(),
+	}
+	const (
+		nodeIP = "10.20.0.2"
+		hostIP = "192.168.16.2"
+	)
+
+	// Prepare mocks.
+	//  -> ContivConf plugin
+	contivConf := &contivConfMock{}
+	contivConf.SetMainInterfaceName(mainIfName)
+
+	//  -> IPNet plugin
+	ipNet := NewMockIPNet()
+	ipNet.SetVxlanBVIIfName(vxlanIfName)
+	ipNet.SetHostInterconnectIfName(hostInterIfName)
+	ipNet.SetPodIfName(Pod1, Pod1IfName)
+	ipNet.SetNodeIP(&net.IPNet{IP: net.ParseIP(nodeIP), Mask: net.CIDRMask(24, 32)})
+	ipNet.SetHostIPs([]net.IP{net.ParseIP(hostIP)})
+
+	// -> ACL engine
+	aclEngine := NewMockACLEngine(logger, ipNet, contivConf)
+	aclEngine.RegisterPod(Pod1, Pod1IP, false)
+
+	// -> localclient
+	txnTracker := localclient.NewTxnTracker(aclEngine.ApplyTxn)
+
+	// Prepare ACL Renderer.
+	aclRenderer := &Renderer{
+		Deps: Deps{
+			Log:                 logger,
+			ContivConf:          contivConf,
+			IPNet:               ipNet,
+			ResyncTxnFactory:    resyncTxnFactory(txnTracker),
+			UpdateTxnFactory:    updateTxnFactory(txnTracker),
+			HostAllowedTCPPorts: []uint16{6443},
+		},
+	}
+	aclRenderer.Init()
+
+	// Execute Renderer transaction.
+	err := aclRenderer.NewTxn(true).Render(Pod1, GetOneHostSubnet(Pod1IP), nil, nil, false).Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commitTxn()).To(gomega.BeNil())
+	gomega.Expect(aclEngine.GetNumOfACLs()).To(gomega.Equal(0))
+
+	// Isolate the node.
+	txn := aclRenderer.NewTxn(false).(*RendererTxn)
+	err = txn.RenderHost(hostRules).Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commitTxn()).To(gomega.BeNil())
+
+	// Test ACLs.
+	gomega.Expect(aclEngine.GetNumOfACLs()).To(gomega.Equal(3))
+
+	// -> physical interfaces are filtered by the host ACL
+	hostACL := aclEngine.GetInboundACL(mainIfName)
+	gomega.Expect(hostACL).ToNot(gomega.BeNil())
+	gomega.Expect(hostACL.Name).To(gomega.Equal(ACLNamePrefix + HostACLName))
+
+	// -> traffic sent to the host ends with the host deny-all (no global table to follow)
+	hostInterconnectACL := aclEngine.GetOutboundACL(hostInterIfName)
+	gomega.Expect(hostInterconnectACL).ToNot(gomega.BeNil())
+	gomega.Expect(hostInterconnectACL.Name).To(gomega.Equal(ACLNamePrefix + HostInterconnectACLName))
+	gomega.Expect(aclEngine.GetACLByName(ACLNamePrefix + cache.GlobalTableID)).To(gomega.BeNil())
+
+	// -> connections opened by the host are reflected on the host interconnect,
+	//    so that the responses are not dropped by the host interconnect ACL
+	reflectiveACL := aclEngine.GetInboundACL(hostInterIfName)
+	gomega.Expect(reflectiveACL).ToNot(gomega.BeNil())
+	gomega.Expect(reflectiveACL.Name).To(gomega.Equal(ACLNamePrefix + ReflectiveACLName))
+	gomega.Expect(reflectiveACL.Interfaces.Ingress).To(gomega.Equal([]string{hostInterIfName}))
+	for _, rule := range reflectiveACL.Rules {
+		gomega.Expect(rule.Action).To(gomega.Equal(vpp_acl.ACL_Rule_REFLECT))
+	}
+
+	// Pods are not affected.
+	gomega.Expect(aclEngine.GetInboundACL(Pod1IfName)).To(gomega.BeNil())
+	gomega.Expect(aclEngine.GetOutboundACL(Pod1IfName)).To(gomega.BeNil())
+
+	// Remove the isolation.
+	txn = aclRenderer.NewTxn(false).(*RendererTxn)
+	err = txn.RenderHost(nil).Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commitTxn()).To(gomega.BeNil())
+	gomega.Expect(aclEngine.GetNumOfACLs()).To(gomega.Equal(0))
+}
+
+func TestFrontendRules(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestFrontendRules")
+
+	// Prepare input data
+	const (
+		lbIP   = "30.30.30.30"
+		nodeIP = "10.20.0.2"
+	)
+	restrictedFrontend := []*renderer.ContivRule{
+		{
+			Action:      renderer.ActionPermit,
+			SrcNetwork:  IpNetwork("100.64.0.0/16"),
+			DestNetwork: IpNetwork(lbIP + "/32"),
+			Protocol:    renderer.TCP,
+			DestPort:    80,
+		},
+		{
+			Action:      renderer.ActionDeny,
+			SrcNetwork:  IpNetwork(""),
+			DestNetwork: IpNetwork(lbIP + "/32"),
+			Protocol:    renderer.TCP,
+			DestPort:    80,
+		},
+	}
+	frontendRules := restrictedFrontend
+	hostRules := []*renderer.ContivRule{DenyAll()}
+
+	// Prepare mocks.
+	//  -> ContivConf plugin
+	contivConf := &contivConfMock{}
+	contivConf.SetMainInterfaceName(mainIfName)
+
+	//  -> IPNet plugin
+	ipNet := NewMockIPNet()
+	ipNet.SetVxlanBVIIfName(vxlanIfName)
+	ipNet.SetHostInterconnectIfName(hostInterIfName)
+	ipNet.SetPodIfName(Pod1, Pod1IfName)
+	ipNet.SetNodeIP(&net.IPNet{IP: net.ParseIP(nodeIP), Mask: net.CIDRMask(24, 32)})
+
+	// -> ACL engine
+	aclEngine := NewMockACLEngine(logger, ipNet, contivConf)
+	aclEngine.RegisterPod(Pod1, Pod1IP, false)
+
+	// -> localclient
+	txnTracker := localclient.NewTxnTracker(aclEngine.ApplyTxn)
+
+	// Prepare ACL Renderer.
+	aclRenderer := &Renderer{
+		Deps: Deps{
+			Log:              logger,
+			ContivConf:       contivConf,
+			IPNet:            ipNet,
+			ResyncTxnFactory: resyncTxnFactory(txnTracker),
+			UpdateTxnFactory: updateTxnFactory(txnTracker),
+			FrontendRules: func() []*renderer.ContivRule {
+				return frontendRules
+			},
+		},
+	}
+	aclRenderer.Init()
+
+	// verifyFrontendRules verifies that the ACL starts with the rendered frontend rules.
+	verifyFrontendRules := func(acl *vpp_acl.ACL, offset int) {
+		gomega.Expect(len(acl.Rules)).To(gomega.BeNumerically(">", offset+2))
+		permitRule := acl.Rules[offset]
+		gomega.Expect(permitRule.Action).To(gomega.Equal(vpp_acl.ACL_Rule_REFLECT))
+		gomega.Expect(permitRule.IpRule.Ip.SourceNetwork).To(gomega.Equal("100.64.0.0/16"))
+		gomega.Expect(permitRule.IpRule.Ip.DestinationNetwork).To(gomega.Equal(lbIP + "/32"))
+		gomega.Expect(permitRule.IpRule.Tcp.DestinationPortRange.LowerPort).To(gomega.BeEquivalentTo(80))
+		gomega.Expect(permitRule.IpRule.Tcp.DestinationPortRange.UpperPort).To(gomega.BeEquivalentTo(80))
+		denyRule := acl.Rules[offset+1]
+		gomega.Expect(denyRule.Action).To(gomega.Equal(vpp_acl.ACL_Rule_DENY))
+		gomega.Expect(denyRule.IpRule.Ip.SourceNetwork).To(gomega.Equal("0.0.0.0/0"))
+		gomega.Expect(denyRule.IpRule.Ip.DestinationNetwork).To(gomega.Equal(lbIP + "/32"))
+		gomega.Expect(denyRule.IpRule.Tcp.DestinationPortRange.LowerPort).To(gomega.BeEquivalentTo(80))
+	}
+
+	// Execute Renderer transaction (the pod is isolated).
+	egress := []*renderer.ContivRule{DenyAll()}
+	err := aclRenderer.NewTxn(true).Render(Pod1, GetOneHostSubnet(Pod1IP), nil, egress, false).Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commitTxn()).To(gomega.BeNil())
+
+	// -> interfaces connecting the node with the outside world have only the frontend ACL
+	frontendACL := aclEngine.GetACLByName(ACLNamePrefix + FrontendACLName)
+	gomega.Expect(frontendACL).ToNot(gomega.BeNil())
+	gomega.Expect(frontendACL.Interfaces.Ingress).To(gomega.ConsistOf(mainIfName, vxlanIfName, hostInterIfName))
+	gomega.Expect(frontendACL.Interfaces.Egress).To(gomega.BeEmpty())
+	verifyFrontendRules(frontendACL, 0)
+	gomega.Expect(frontendACL.Rules).To(gomega.HaveLen(4))
+	for _, rule := range frontendACL.Rules[2:] {
+		// the rest of the traffic is permitted and reflected
+		gomega.Expect(rule.Action).To(gomega.Equal(vpp_acl.ACL_Rule_REFLECT))
+		gomega.Expect(rule.IpRule.Tcp).To(gomega.BeNil())
+	}
+
+	// -> the reflective ACL is installed only on the pod interface
+	reflectiveACL := aclEngine.GetACLByName(ACLNamePrefix + ReflectiveACLName)
+	gomega.Expect(reflectiveACL).ToNot(gomega.BeNil())
+	gomega.Expect(reflectiveACL.Interfaces.Ingress).To(gomega.Equal([]string{Pod1IfName}))
+
+	// Remove the frontend restrictions.
+	frontendRules = nil
+	err = aclRenderer.NewTxn(false).Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commitTxn()).To(gomega.BeNil())
+	gomega.Expect(aclEngine.GetACLByName(ACLNamePrefix + FrontendACLName)).To(gomega.BeNil())
+	gomega.Expect(aclEngine.GetInboundACL(mainIfName)).To(gomega.BeNil())
+	gomega.Expect(aclEngine.GetInboundACL(Pod1IfName).Name).To(gomega.Equal(ACLNamePrefix + ReflectiveACLName))
+
+	// Restore the frontend restrictions.
+	frontendRules = restrictedFrontend
+	err = aclRenderer.NewTxn(false).Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commitTxn()).To(gomega.BeNil())
+	frontendACL = aclEngine.GetInboundACL(mainIfName)
+	gomega.Expect(frontendACL).ToNot(gomega.BeNil())
+	gomega.Expect(frontendACL.Name).To(gomega.Equal(ACLNamePrefix + FrontendACLName))
+
+	// Isolate the node.
+	txn := aclRenderer.NewTxn(false).(*RendererTxn)
+	err = txn.RenderHost(hostRules).Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commitTxn()).To(gomega.BeNil())
+
+	// -> physical interfaces are filtered by the host ACL, starting (after ICMP)
+	//    with the frontend rules
+	hostACL := aclEngine.GetInboundACL(mainIfName)
+	gomega.Expect(hostACL).ToNot(gomega.BeNil())
+	gomega.Expect(hostACL.Name).To(gomega.Equal(ACLNamePrefix + HostACLName))
+	verifyFrontendRules(hostACL, 2)
+
+	// -> the frontend ACL remains on the virtual interfaces
+	frontendACL = aclEngine.GetACLByName(ACLNamePrefix + FrontendACLName)
+	gomega.Expect(frontendACL).ToNot(gomega.BeNil())
+	gomega.Expect(frontendACL.Interfaces.Ingress).To(gomega.ConsistOf(vxlanIfName, hostInterIfName))
+	verifyFrontendRules(frontendACL, 0)
+	reflectiveACL = aclEngine.GetACLByName(ACLNamePrefix + ReflectiveACLName)
+	gomega.Expect(reflectiveACL.Interfaces.Ingress).To(gomega.Equal([]string{Pod1IfName}))
+
+	// Resync the same state.
+	txn = aclRenderer.NewTxn(true).(*RendererTxn)
+	txn.Render(Pod1, GetOneHostSubnet(Pod1IP), nil, egress, false)
+	err = txn.RenderHost(hostRules).Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commitTxn()).To(gomega.BeNil())
+	verifyFrontendRules(aclEngine.GetInboundACL(mainIfName), 2)
+	verifyFrontendRules(aclEngine.GetInboundACL(hostInterIfName), 0)
+	gomega.Expect(aclEngine.GetInboundACL(Pod1IfName).Name).To(gomega.Equal(ACLNamePrefix + ReflectiveACLName))
+}
+
+func TestRenderSCTPRules(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestRenderSCTPRules")
+
+	// Prepare mocks.
+	contivConf := &contivConfMock{}
+	contivConf.SetMainInterfaceName(mainIfName)
+	ipNet := NewMockIPNet()
+	ipNet.SetPodIfName(Pod1, Pod1IfName)
+
+	// Prepare ACL Renderer.
+	aclRenderer := &Renderer{
+		Deps: Deps{
+			Log:        logger,
+			ContivConf: contivConf,
+			IPNet:      ipNet,
+		},
+	}
+	aclRenderer.Init()
+
+	// SCTP ports cannot be matched by VPP ACL.
+	gomega.Expect(aclRenderer.CanMatchPorts(renderer.SCTP)).To(gomega.BeFalse())
+	gomega.Expect(aclRenderer.CanMatchPorts(renderer.TCP)).To(gomega.BeTrue())
+	gomega.Expect(aclRenderer.CanMatchPorts(renderer.UDP)).To(gomega.BeTrue())
+
+	// Prepare table with SCTP rules.
+	_, peerNet, _ := net.ParseCIDR("10.10.0.0/16")
+	sctpAny := &renderer.ContivRule{
+		Action:      renderer.ActionPermit,
+		SrcNetwork:  peerNet,
+		DestNetwork: &net.IPNet{},
+		Protocol:    renderer.SCTP,
+	}
+	sctpPort := &renderer.ContivRule{
+		Action:      renderer.ActionPermit,
+		SrcNetwork:  &net.IPNet{},
+		DestNetwork: &net.IPNet{},
+		Protocol:    renderer.SCTP,
+		DestPort:    3868,
+	}
+	table := cache.NewContivRuleTable(cache.Local)
+	table.Pods.Add(Pod1)
+	table.InsertRule(sctpAny)
+	table.InsertRule(sctpPort)
+
+	// Render the table.
+	txn := aclRenderer.NewTxn(false).(*RendererTxn)
+	acl := txn.renderACL(table, false)
+	gomega.Expect(acl.Interfaces.Egress).To(gomega.Equal([]string{Pod1IfName}))
+	gomega.Expect(acl.Rules).To(gomega.HaveLen(3))
+
+	var permitRules, denyRules []*vpp_acl.ACL_Rule
+	for _, aclRule := range acl.Rules {
+		gomega.Expect(aclRule.IpRule.Ip.Protocol).To(gomega.BeEquivalentTo(sctpProtocolNumber))
+		gomega.Expect(aclRule.IpRule.Tcp).To(gomega.BeNil())
+		gomega.Expect(aclRule.IpRule.Udp).To(gomega.BeNil())
+		if aclRule.Action == vpp_acl.ACL_Rule_PERMIT {
+			permitRules = append(permitRules, aclRule)
+		} else {
+			denyRules = append(denyRules, aclRule)
+		}
+	}
+
+	// SCTP traffic of any port is allowed as is.
+	gomega.Expect(permitRules).To(gomega.HaveLen(1))
+	gomega.Expect(permitRules[0].IpRule.Ip.SourceNetwork).To(gomega.Equal("10.10.0.0/16"))
+
+	// SCTP port cannot be matched - the rule (rejected by the configurator if generated
+	// from a policy) is rendered as deny of all SCTP traffic (expanded into IPv4 and IPv6 rule).
+	gomega.Expect(denyRules).To(gomega.HaveLen(2))
+	for _, aclRule := range denyRules {
+		gomega.Expect(aclRule.Action).To(gomega.Equal(vpp_acl.ACL_Rule_DENY))
+	}
+}
+
+// aclCountersMock simulates the stats dump with the given hits of ACL rules.
+type aclCountersMock struct {
+	ruleHits map[string][]uint64
+}
+
+// GetRuleHits returns the simulated hits of ACL rules.
+func (m *aclCountersMock) GetRuleHits() (map[string][]uint64, error) {
+	return m.ruleHits, nil
+}
+
+// GetACLIndexes returns no indexes - not used by the test.
+func (m *aclCountersMock) GetACLIndexes() (map[string]uint32, error) {
+	return map[string]uint32{}, nil
+}
+
+// simulateRuleHits assigns a unique bit to every rule of the given ACL (shifted
+// by the given offset), so that the ACL rules counted by each RuleCounter can be
+// read back from the packet counts.
+func simulateRuleHits(aclCounters *aclCountersMock, acl *vpp_acl.ACL, shift uint) {
+	gomega.Expect(len(acl.Rules)).To(gomega.BeNumerically("<=", 32))
+	hits := make([]uint64, len(acl.Rules))
+	for i := range acl.Rules {
+		hits[i] = uint64(1) << (uint(i) + shift)
+	}
+	aclCounters.ruleHits[acl.Name] = hits
+}
+
+// countedACLRules returns ACL rules with hits included in the given packet count.
+func countedACLRules(acl *vpp_acl.ACL, packets uint64, shift uint) (rules []*vpp_acl.ACL_Rule) {
+	for i, rule := range acl.Rules {
+		if packets&(uint64(1)<<(uint(i)+shift)) != 0 {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// verifyRuleCounters checks that every rule of the given ACL is counted exactly once
+// by the counters of the table and that the counted ACL rules match the contiv rules.
+func verifyRuleCounters(counters []*renderer.RuleCounter, acl *vpp_acl.ACL, shift uint) {
+	var counted uint64
+	for _, counter := range counters {
+		gomega.Expect(counted & counter.Packets).To(gomega.BeZero())
+		counted |= counter.Packets
+
+		aclRules := countedACLRules(acl, counter.Packets, shift)
+		if len(counter.Rule.SrcNetwork.IP) == 0 && len(counter.Rule.DestNetwork.IP) == 0 {
+			// rendered as one rule for IPv4 and one for IPv6
+			gomega.Expect(aclRules).To(gomega.HaveLen(2))
+		} else {
+			gomega.Expect(aclRules).To(gomega.HaveLen(1))
+		}
+		for _, aclRule := range aclRules {
+			if counter.Rule.Action == renderer.ActionDeny {
+				gomega.Expect(aclRule.Action).To(gomega.Equal(vpp_acl.ACL_Rule_DENY))
+			} else {
+				gomega.Expect(aclRule.Action).To(gomega.Equal(vpp_acl.ACL_Rule_PERMIT))
+			}
+			if len(counter.Rule.SrcNetwork.IP) > 0 {
+				gomega.Expect(aclRule.IpRule.Ip.SourceNetwork).To(gomega.Equal(counter.Rule.SrcNetwork.String()))
+			}
+			if len(counter.Rule.DestNetwork.IP) > 0 {
+				gomega.Expect(aclRule.IpRule.Ip.DestinationNetwork).To(gomega.Equal(counter.Rule.DestNetwork.String()))
+			}
+			gomega.Expect(aclRule.IpRule.Tcp != nil).To(gomega.Equal(counter.Rule.Protocol == renderer.TCP))
+		}
+	}
+	gomega.Expect(countedACLRules(acl, counted, shift)).To(gomega.HaveLen(len(acl.Rules)))
+}
+
+func TestRuleCounters(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestRuleCounters")
+
+	// Prepare input data
+	ingress := []*renderer.ContivRule{Ts6.Rule1, Ts6.Rule2}
+	egress := []*renderer.ContivRule{Ts5.Rule1, Ts5.Rule2}
+	hostRules := []*renderer.ContivRule{
+		{
+			Action:      renderer.ActionPermit,
+			SrcNetwork:  IpNetwork("172.16.1.0/24"),
+			DestNetwork: IpNetwork(""),
+			Protocol:    renderer.TCP,
+			DestPort:    22,
+		},
+		DenyAll(),
+	}
+
+	// Prepare mocks.
+	//  -> ContivConf plugin
+	contivConf := &contivConfMock{}
+	contivConf.SetMainInterfaceName(mainIfName)
+
+	//  -> IPNet plugin
+	ipNet := NewMockIPNet()
+	ipNet.SetVxlanBVIIfName(vxlanIfName)
+	ipNet.SetHostInterconnectIfName(hostInterIfName)
+	ipNet.SetPodIfName(Pod1, Pod1IfName)
+	ipNet.SetNodeIP(&net.IPNet{IP: net.ParseIP("10.20.0.2"), Mask: net.CIDRMask(24, 32)})
+	ipNet.SetHostIPs([]net.IP{net.ParseIP("192.168.16.2")})
+
+	// -> ACL engine
+	aclEngine := NewMockACLEngine(logger, ipNet, contivConf)
+	aclEngine.RegisterPod(Pod1, Pod1IP, false)
+
+	// -> localclient
+	txnTracker := localclient.NewTxnTracker(aclEngine.ApplyTxn)
+
+	// -> stats dump
+	aclCounters := &aclCountersMock{ruleHits: make(map[string][]uint64)}
+
+	// Prepare ACL Renderer.
+	aclRenderer := &Renderer{
+		Deps: Deps{
+			Log:              logger,
+			ContivConf:       contivConf,
+			IPNet:            ipNet,
+			ACLCounters:      aclCounters,
+			ResyncTxnFactory: resyncTxnFactory(txnTracker),
+			UpdateTxnFactory: updateTxnFactory(txnTracker),
+		},
+	}
+	aclRenderer.Init()
+
+	// Execute Renderer transaction.
+	err := aclRenderer.NewTxn(true).Render(Pod1, GetOneHostSubnet(Pod1IP), ingress, egress, false).Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commitTxn()).To(gomega.BeNil())
+
+	localACL := aclEngine.GetOutboundACL(Pod1IfName)
+	gomega.Expect(localACL).ToNot(gomega.BeNil())
+	globalACL := aclEngine.GetACLByName(ACLNamePrefix + cache.GlobalTableID)
+	gomega.Expect(globalACL).ToNot(gomega.BeNil())
+	simulateRuleHits(aclCounters, localACL, 0)
+	simulateRuleHits(aclCounters, globalACL, 0)
+
+	// Test counters of the local and the global table.
+	splitCounters := func() (local, global []*renderer.RuleCounter) {
+		counters, err := aclRenderer.GetRuleCounters()
+		gomega.Expect(err).To(gomega.BeNil())
+		for _, counter := range counters {
+			switch counter.Table {
+			case localACL.Name:
+				gomega.Expect(counter.Egress).To(gomega.BeFalse())
+				local = append(local, counter)
+			case globalACL.Name:
+				gomega.Expect(counter.Egress).To(gomega.BeTrue())
+				global = append(global, counter)
+			default:
+				t.Fatalf("unexpected table: %s", counter.Table)
+			}
+		}
+		return local, global
+	}
+	localCounters, globalCounters := splitCounters()
+	verifyRuleCounters(localCounters, localACL, 0)
+	verifyRuleCounters(globalCounters, globalACL, 0)
+
+	// Isolate the node.
+	txn := aclRenderer.NewTxn(false).(*RendererTxn)
+	err = txn.RenderHost(hostRules).Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(commitTxn()).To(gomega.BeNil())
+
+	// Hits of the global table rules are also counted on the host interconnect,
+	// where the rules follow the ICMP and the host rules (upper 32 bits).
+	hostInterconnectACL := aclEngine.GetOutboundACL(hostInterIfName)
+	gomega.Expect(hostInterconnectACL).ToNot(gomega.BeNil())
+	gomega.Expect(hostInterconnectACL.Name).To(gomega.Equal(ACLNamePrefix + HostInterconnectACLName))
+	globalACL = aclEngine.GetACLByName(ACLNamePrefix + cache.GlobalTableID)
+	gomega.Expect(globalACL).ToNot(gomega.BeNil())
+	simulateRuleHits(aclCounters, globalACL, 0)
+	simulateRuleHits(aclCounters, hostInterconnectACL, 32)
+
+	localCounters, globalCounters = splitCounters()
+	verifyRuleCounters(localCounters, localACL, 0)
+	verifyRuleCounters(globalCounters, globalACL, 0)
+	offset := len(hostInterconnectACL.Rules) - len(globalACL.Rules)
+	gomega.Expect(offset).To(gomega.BeNumerically(">", 0))
+	var counted uint64
+	for _, counter := range globalCounters {
+		globalRules := countedACLRules(globalACL, counter.Packets, 0)
+		hostACLRules := countedACLRules(hostInterconnectACL, counter.Packets, 32)
+		gomega.Expect(hostACLRules).To(gomega.HaveLen(len(globalRules)))
+		for i := range hostACLRules {
+			gomega.Expect(hostACLRules[i].String()).To(gomega.Equal(globalRules[i].String()))
+		}
+		counted |= counter.Packets >> 32
+	}
+	// -> rules preceding the global table are not counted
+	for i := range hostInterconnectACL.Rules {
+		gomega.Expect(counted&(uint64(1)<<uint(i)) != 0).To(gomega.Equal(i >= offset))
+	}
+}
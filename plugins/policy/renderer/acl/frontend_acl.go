@@ -0,0 +1,114 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acl
+
+import (
+	"net"
+
+	vpp_acl "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/acl"
+
+	"github.com/contiv/vpp/plugins/policy/renderer"
+	"github.com/contiv/vpp/plugins/policy/renderer/cache"
+)
+
+const (
+	// FrontendACLName is the name of the ACL (full name prefixed with ACLNamePrefix)
+	// restricting access to service frontends (LB ingress IPs and node ports)
+	// on the interfaces connecting the node with the outside world.
+	// The ACL replaces the reflective ACL on these interfaces. On physical
+	// interfaces of an isolated node the frontend rules are included in the host
+	// ACL instead.
+	FrontendACLName = "FRONTENDS"
+)
+
+// loadFrontendRules loads the rules restricting access to service frontends
+// into the transaction.
+func (art *RendererTxn) loadFrontendRules() {
+	if art.renderer.FrontendRules == nil {
+		return
+	}
+	art.frontendRules = art.renderer.FrontendRules()
+	art.withFrontendRules = true
+}
+
+// getFrontendRules returns the frontend rules as they will be after the commit.
+func (art *RendererTxn) getFrontendRules() []*renderer.ContivRule {
+	if art.withFrontendRules {
+		return art.frontendRules
+	}
+	return art.renderer.frontendRules
+}
+
+// hasFrontendRules returns true if access to some service frontends will be
+// restricted after the commit.
+func (art *RendererTxn) hasFrontendRules() bool {
+	return len(art.getFrontendRules()) > 0
+}
+
+// frontendRulesChanged returns true if the transaction changes the frontend rules.
+func (art *RendererTxn) frontendRulesChanged() bool {
+	if !art.withFrontendRules {
+		return false
+	}
+	return !equalRules(art.frontendRules, art.renderer.frontendRules)
+}
+
+// frontendACL returns the configuration of the ACL applied on the traffic entering
+// the node via interfaces not filtered by the host ACL.
+// Traffic not destined to a restricted frontend is permitted and all the permitted
+// sessions are reflected (i.e. the ACL also serves as the reflective ACL).
+func (art *RendererTxn) frontendACL() *vpp_acl.ACL {
+	ruleAny := &renderer.ContivRule{
+		Action:      renderer.ActionPermit,
+		SrcNetwork:  &net.IPNet{},
+		DestNetwork: &net.IPNet{},
+		Protocol:    renderer.ANY,
+	}
+	table := cache.NewContivRuleTable(cache.Local)
+	table.Rules = append(table.Rules, art.getFrontendRules()...)
+	table.Rules = append(table.Rules, ruleAny)
+	table.NumOfRules = len(table.Rules)
+
+	acl := art.renderACL(table, true)
+	acl.Name = ACLNamePrefix + FrontendACLName
+	acl.Interfaces = &vpp_acl.ACL_Interfaces{
+		Ingress: art.getFrontendACLInterfaces(),
+	}
+	return acl
+}
+
+// getFrontendACLInterfaces returns the list of interfaces with the frontend ACL
+// installed in the ingress direction.
+func (art *RendererTxn) getFrontendACLInterfaces() []string {
+	if art.isHostIsolated() {
+		// physical interfaces have the host ACL installed instead
+		return art.getNodeVirtualOutputInterfaces()
+	}
+	return art.getNodeOutputInterfaces()
+}
+
+// equalRules returns true if the given lists contain the same rules
+// in the same order.
+func equalRules(rules1, rules2 []*renderer.ContivRule) bool {
+	if len(rules1) != len(rules2) {
+		return false
+	}
+	for i := range rules1 {
+		if rules1[i].Compare(rules2[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
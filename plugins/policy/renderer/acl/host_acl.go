@@ -0,0 +1,283 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acl
+
+import (
+	"net"
+
+	"go.ligato.io/cn-infra/v2/logging"
+
+	vpp_acl "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/acl"
+
+	"github.com/contiv/vpp/plugins/policy/renderer"
+	"github.com/contiv/vpp/plugins/policy/renderer/cache"
+)
+
+const (
+	// HostACLName is the name of the ACL (full name prefixed with ACLNamePrefix)
+	// filtering traffic entering the node via physical interfaces. The ACL
+	// replaces the reflective ACL on these interfaces.
+	HostACLName = "HOST"
+
+	// HostInterconnectACLName is the name of the ACL (full name prefixed with
+	// ACLNamePrefix) filtering traffic sent from VPP into the host stack.
+	// The ACL replaces the global ACL on the host interconnect.
+	HostInterconnectACLName = "HOST-INTERCONNECT"
+
+	// IANA-assigned IP protocol numbers of ICMP and ICMPv6
+	icmpProtocolNumber   = 1
+	icmpv6ProtocolNumber = 58
+
+	// client port of DHCP
+	dhcpClientPort = 68
+
+	// ephemeral ports are permitted on physical interfaces to let the responses
+	// to connections opened by the host (and by SNATed pods) enter VPP, the host
+	// stack receives only those matching the reflected sessions
+	minEphemeralPort = 32768
+	maxEphemeralPort = 65535
+)
+
+// RenderHost applies the set of rules for the traffic destined to this node.
+// The existing rules are replaced, nil rules remove the isolation.
+// The actual change is performed only after the commit.
+func (art *RendererTxn) RenderHost(rules []*renderer.ContivRule) renderer.Txn {
+	art.renderer.Log.WithFields(logging.Fields{
+		"rules-count": len(rules),
+		"isolated":    rules != nil,
+	}).Debug("ACL RendererTxn RenderHost()")
+
+	art.hostRules = rules
+	art.withHostRules = true
+	return art
+}
+
+// getHostRules returns the host rules as they will be after the commit.
+func (art *RendererTxn) getHostRules() []*renderer.ContivRule {
+	if art.withHostRules {
+		return art.hostRules
+	}
+	return art.renderer.hostRules
+}
+
+// isHostIsolated returns true if the node will have host rules installed
+// after the commit.
+func (art *RendererTxn) isHostIsolated() bool {
+	return art.getHostRules() != nil
+}
+
+// hostRulesChanged returns true if the transaction changes the host rules.
+func (art *RendererTxn) hostRulesChanged() bool {
+	if !art.withHostRules {
+		return false
+	}
+	if (art.hostRules == nil) != (art.renderer.hostRules == nil) {
+		return true
+	}
+	return !equalRules(art.hostRules, art.renderer.hostRules)
+}
+
+// hostACL returns the configuration of the ACL applied on the traffic entering
+// the node via physical interfaces.
+// Traffic destined to service frontends with restricted access is filtered first
+// (see frontendACL), the rest of the traffic not destined to the node is permitted
+// and all the permitted sessions are reflected (i.e. the ACL also serves
+// as the reflective ACL).
+// Responses to connections opened by the host are permitted here statelessly
+// (ephemeral ports), but only those matching a session reflected on the host
+// interconnect are delivered to the host stack (see hostInterconnectACL).
+func (art *RendererTxn) hostACL() *vpp_acl.ACL {
+	ruleAny := &renderer.ContivRule{
+		Action:      renderer.ActionPermit,
+		SrcNetwork:  &net.IPNet{},
+		DestNetwork: &net.IPNet{},
+		Protocol:    renderer.ANY,
+	}
+	table := cache.NewContivRuleTable(cache.Local)
+	table.Rules = append(table.Rules, art.getFrontendRules()...)
+	table.Rules = append(table.Rules, art.hostTableRules(true)...)
+	table.Rules = append(table.Rules, ruleAny)
+	table.NumOfRules = len(table.Rules)
+
+	acl := art.renderACL(table, true)
+	acl.Name = ACLNamePrefix + HostACLName
+	acl.Rules = append(art.hostICMPRules(vpp_acl.ACL_Rule_REFLECT), acl.Rules...)
+	acl.Interfaces = &vpp_acl.ACL_Interfaces{
+		Ingress: art.getNodePhysicalInterfaces(),
+	}
+	return acl
+}
+
+// hostInterconnectACL returns the configuration of the ACL applied on the traffic
+// sent from VPP into the host stack.
+// Traffic accepted by the host rules is further filtered by the global table
+// (which is otherwise installed on the host interconnect).
+// Responses to connections opened by the host are not permitted by any rule,
+// they bypass the ACL by matching the sessions created by the reflective ACL
+// on the ingress side of the host interconnect.
+func (art *RendererTxn) hostInterconnectACL() *vpp_acl.ACL {
+	table := cache.NewContivRuleTable(cache.Local)
+	hostRules := art.hostTableRules(false)
+	table.Rules = append(table.Rules, hostRules...)
+	if globalTable := art.cacheTxn.GetGlobalTable(); globalTable.NumOfRules > 0 {
+		table.Rules = append(table.Rules, globalTable.Rules[:globalTable.NumOfRules]...)
+	} else {
+		table.Rules = append(table.Rules, &renderer.ContivRule{
+			Action:      renderer.ActionPermit,
+			SrcNetwork:  &net.IPNet{},
+			DestNetwork: &net.IPNet{},
+			Protocol:    renderer.ANY,
+		})
+	}
+	table.NumOfRules = len(table.Rules)
+
+	acl := art.renderACL(table, false)
+	acl.Name = ACLNamePrefix + HostInterconnectACLName
+	icmpRules := art.hostICMPRules(vpp_acl.ACL_Rule_PERMIT)
+	acl.Rules = append(icmpRules, acl.Rules...)
+	acl.Interfaces = &vpp_acl.ACL_Interfaces{
+		Egress: []string{art.renderer.IPNet.GetHostInterconnectIfName()},
+	}
+
+	// remember where the global table starts for the rule counters
+	art.renderer.globalTableOffset = len(icmpRules)
+	for _, rule := range hostRules {
+		art.renderer.globalTableOffset += numOfACLRules(rule)
+	}
+	return acl
+}
+
+// hostTableRules returns the host rules restricted to the addresses of the node,
+// preceded by the rules that keep the control traffic of the node running.
+// Set <withEphemeralPorts> to permit (statelessly) traffic destined to the ephemeral
+// ports of the node.
+func (art *RendererTxn) hostTableRules(withEphemeralPorts bool) (rules []*renderer.ContivRule) {
+	for _, nodeNet := range art.getNodeNetworks() {
+		// traffic sent by the node itself
+		rules = append(rules, &renderer.ContivRule{
+			Action:      renderer.ActionPermit,
+			SrcNetwork:  nodeNet,
+			DestNetwork: nodeNet,
+			Protocol:    renderer.ANY,
+		})
+		// responses to connections opened by the host and DHCP
+		if withEphemeralPorts {
+			for _, protocol := range []renderer.ProtocolType{renderer.TCP, renderer.UDP} {
+				rules = append(rules, &renderer.ContivRule{
+					Action:      renderer.ActionPermit,
+					SrcNetwork:  &net.IPNet{},
+					DestNetwork: nodeNet,
+					Protocol:    protocol,
+					DestPort:    minEphemeralPort,
+					DestPortEnd: maxEphemeralPort,
+				})
+			}
+		}
+		rules = append(rules, &renderer.ContivRule{
+			Action:      renderer.ActionPermit,
+			SrcNetwork:  &net.IPNet{},
+			DestNetwork: nodeNet,
+			Protocol:    renderer.UDP,
+			DestPort:    dhcpClientPort,
+		})
+		// control traffic of the cluster
+		for _, port := range art.renderer.HostAllowedTCPPorts {
+			rules = append(rules, &renderer.ContivRule{
+				Action:      renderer.ActionPermit,
+				SrcNetwork:  &net.IPNet{},
+				DestNetwork: nodeNet,
+				Protocol:    renderer.TCP,
+				DestPort:    port,
+			})
+		}
+		for _, port := range art.renderer.HostAllowedUDPPorts {
+			rules = append(rules, &renderer.ContivRule{
+				Action:      renderer.ActionPermit,
+				SrcNetwork:  &net.IPNet{},
+				DestNetwork: nodeNet,
+				Protocol:    renderer.UDP,
+				DestPort:    port,
+			})
+		}
+	}
+	// rules of host policies
+	for _, rule := range art.getHostRules() {
+		for _, nodeNet := range art.getNodeNetworks() {
+			nodeRule := rule.Copy()
+			nodeRule.DestNetwork = nodeNet
+			rules = append(rules, nodeRule)
+		}
+	}
+	return rules
+}
+
+// hostICMPRules returns ACL rules permitting ICMP and ICMPv6 (not expressible
+// with Contiv rules), which are never blocked by host rules.
+func (art *RendererTxn) hostICMPRules(action vpp_acl.ACL_Rule_Action) (rules []*vpp_acl.ACL_Rule) {
+	for _, protocol := range []uint32{icmpProtocolNumber, icmpv6ProtocolNumber} {
+		addrAny := ipv4AddrAny
+		if protocol == icmpv6ProtocolNumber {
+			addrAny = ipv6AddrAny
+		}
+		rules = append(rules, &vpp_acl.ACL_Rule{
+			Action: action,
+			IpRule: &vpp_acl.ACL_Rule_IpRule{
+				Ip: &vpp_acl.ACL_Rule_IpRule_Ip{
+					SourceNetwork:      addrAny,
+					DestinationNetwork: addrAny,
+					Protocol:           protocol,
+				},
+			},
+		})
+	}
+	return rules
+}
+
+// getNodeNetworks returns one-host networks of all the addresses of this node
+// (in VPP and in the host stack).
+func (art *RendererTxn) getNodeNetworks() (networks []*net.IPNet) {
+	addNetwork := func(ip net.IP) {
+		if len(ip) == 0 {
+			return
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			networks = append(networks, &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)})
+		} else {
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)})
+		}
+	}
+	nodeIP, _ := art.renderer.IPNet.GetNodeIP()
+	addNetwork(nodeIP)
+	for _, hostIP := range art.renderer.IPNet.GetHostIPs() {
+		if hostIP.Equal(nodeIP) {
+			continue
+		}
+		addNetwork(hostIP)
+	}
+	return networks
+}
+
+// getNodePhysicalInterfaces returns the list of physical interfaces of this node.
+func (art *RendererTxn) getNodePhysicalInterfaces() []string {
+	interfaces := []string{}
+	mainIface := art.renderer.ContivConf.GetMainInterfaceName()
+	if mainIface != "" {
+		interfaces = append(interfaces, mainIface)
+	}
+	for _, otherIface := range art.renderer.ContivConf.GetOtherVPPInterfaces() {
+		interfaces = append(interfaces, otherIface.InterfaceName)
+	}
+	return interfaces
+}
@@ -60,20 +60,133 @@ type Txn interface {
 	Commit() error
 }
 
+// HostTxn is an optional interface of the renderer transaction, implemented
+// by renderers able to protect the network endpoints of the node itself
+// (host firewall).
+type HostTxn interface {
+	// RenderHost applies the set of rules for the traffic destined to this node.
+	// The existing rules are replaced.
+	// The destination IP is unset in all the rules - it is up to the renderer
+	// to restrict the rules to the addresses of the node. The renderer should
+	// always allow the control traffic of the vswitch, regardless of the rules.
+	// Nil set of rules means that the node is not isolated (all traffic allowed).
+	RenderHost(rules []*ContivRule) Txn
+}
+
+// PortMatchingAPI is an optional interface of Policy Renderer, implemented
+// by renderers unable to match L4 ports of some of the protocols.
+// Rules restricting ports of such protocols are rejected by the configurator
+// and not rendered at all.
+type PortMatchingAPI interface {
+	// CanMatchPorts returns false if the renderer is unable to match L4 ports
+	// of the given protocol.
+	CanMatchPorts(protocol ProtocolType) bool
+}
+
+// RuleCountersAPI is an optional interface of Policy Renderer, implemented
+// by renderers able to read hit counters of the installed rules from
+// the destination network stack.
+type RuleCountersAPI interface {
+	// GetRuleCounters returns hit counters of all the rules currently installed
+	// by the renderer.
+	GetRuleCounters() ([]*RuleCounter, error)
+}
+
+// RuleCounter represents the number of packets matched by a rule installed
+// by a renderer.
+type RuleCounter struct {
+	// Table is the name under which the table with the rule was installed
+	// into the destination network stack.
+	Table string
+
+	// Rule as installed by the renderer.
+	Rule *ContivRule
+
+	// Pods which the rule applies to.
+	Pods []podmodel.ID
+
+	// Egress is true if the rule matches traffic sent by the pods, false
+	// if it matches traffic destined to the pods (pods point of view).
+	Egress bool
+
+	// Policies lists IDs of the policies that the rule was generated from.
+	// Empty for rules allowing traffic not restricted by any policy.
+	Policies []string
+
+	// Packets is the number of packets matched by the rule.
+	Packets uint64
+}
+
+// PodTablesAPI is an optional interface of Policy Renderer, implemented
+// by renderers able to describe the tables installed for the pods.
+type PodTablesAPI interface {
+	// GetPodTables returns tables of all the pods currently configured
+	// by the renderer.
+	GetPodTables() ([]*PodTables, error)
+}
+
+// PodTables lists tables installed by a renderer that apply to the traffic
+// of a pod.
+type PodTables struct {
+	// Pod which the tables apply to.
+	Pod podmodel.ID
+
+	// Interface connecting the pod with the destination network stack
+	// (empty if not known).
+	Interface string
+
+	// Tables applied to the traffic of the pod.
+	Tables []*InstalledTable
+}
+
+// InstalledTable is a table of rules as installed by a renderer.
+type InstalledTable struct {
+	// Name under which the table was installed into the destination
+	// network stack.
+	Name string
+
+	// Index assigned to the table by the destination network stack
+	// (-1 if not available).
+	Index int
+
+	// Attached is true if the table is installed on the interface of the pod,
+	// false for tables shared by all the pods of the node.
+	Attached bool
+
+	// Egress is true if the table matches traffic sent by the pod, false
+	// if it matches traffic destined to the pod (pod point of view).
+	Egress bool
+
+	// Rules of the table in the order of evaluation.
+	Rules []*ContivRule
+}
+
 // ContivRule is an n-tuple with the most basic policy rule definition that the
 // destination network stack must support.
 type ContivRule struct {
 	// Action to perform when traffic matches.
 	Action ActionType
 
+	// Priority orders rules of different policy tiers: rules with a higher
+	// priority are matched first, regardless of their specificity.
+	// Rules generated from namespaced K8s network policies have the lowest
+	// priority 0, rules of cluster-scoped admin network policies are assigned
+	// priorities above zero (see NamespacedTierPriority).
+	Priority uint32
+
 	// L3
 	SrcNetwork  *net.IPNet // empty = match all
 	DestNetwork *net.IPNet // empty = match all
 
 	// L4
-	Protocol ProtocolType
-	SrcPort  uint16 // 0 = match all
-	DestPort uint16 // 0 = match all
+	Protocol    ProtocolType
+	SrcPort     uint16 // 0 = match all
+	DestPort    uint16 // 0 = match all
+	DestPortEnd uint16 // 0 = match DestPort only, otherwise match range <DestPort, DestPortEnd>
+
+	// Policies lists IDs of the policies that the rule was generated from.
+	// The attribute is informative only - it is not considered by Compare().
+	Policies []string
 }
 
 // String converts Contiv Rule (pointer) into a human-readable string
@@ -95,6 +208,13 @@ func (cr *ContivRule) String() string {
 	}
 	if cr.DestPort != 0 {
 		dstPort = strconv.Itoa(int(cr.DestPort))
+		if cr.DestPortEnd > cr.DestPort {
+			dstPort += "-" + strconv.Itoa(int(cr.DestPortEnd))
+		}
+	}
+	if cr.Priority != NamespacedTierPriority {
+		return fmt.Sprintf("Rule <%s(%d) %s[%s:%s] -> %s[%s:%s]>",
+			cr.Action, cr.Priority, srcNet, cr.Protocol, srcPort, dstNet, cr.Protocol, dstPort)
 	}
 	return fmt.Sprintf("Rule <%s %s[%s:%s] -> %s[%s:%s]>",
 		cr.Action, srcNet, cr.Protocol, srcPort, dstNet, cr.Protocol, dstPort)
@@ -109,8 +229,16 @@ func (cr *ContivRule) Copy() *ContivRule {
 
 // Compare returns -1, 0, 1 if this<cr2 or this==cr2 or this>cr2, respectively.
 // Contiv rules have a total order defined on them.
-// It holds that if cr matches subset of the traffic matched by cr2, then cr<cr2.
+// Rules with higher priority precede rules with lower priority.
+// For rules of the same priority it holds that if cr matches subset of the traffic
+// matched by cr2, then cr<cr2.
 func (cr *ContivRule) Compare(cr2 *ContivRule) int {
+	if cr.Priority != cr2.Priority {
+		if cr.Priority > cr2.Priority {
+			return -1
+		}
+		return 1
+	}
 	srcIPOrder := utils.CompareIPNets(cr.SrcNetwork, cr2.SrcNetwork)
 	if srcIPOrder != 0 {
 		return srcIPOrder
@@ -128,7 +256,7 @@ func (cr *ContivRule) Compare(cr2 *ContivRule) int {
 		if srcPortOrder != 0 {
 			return srcPortOrder
 		}
-		dstPortOrder := utils.ComparePorts(cr.DestPort, cr2.DestPort)
+		dstPortOrder := utils.ComparePortRanges(cr.DestPort, cr.DestPortEnd, cr2.DestPort, cr2.DestPortEnd)
 		if dstPortOrder != 0 {
 			return dstPortOrder
 		}
@@ -136,6 +264,15 @@ func (cr *ContivRule) Compare(cr2 *ContivRule) int {
 	return utils.CompareInts(int(cr.Action), int(cr2.Action))
 }
 
+const (
+	// NamespacedTierPriority is the priority of rules implementing namespaced
+	// K8s network policies (evaluated after all the admin network policies).
+	NamespacedTierPriority uint32 = 0
+
+	// MaxPriority is the highest priority a rule can be assigned.
+	MaxPriority = ^uint32(0)
+)
+
 // ActionType is either DENY or PERMIT.
 type ActionType int
 
@@ -158,7 +295,7 @@ func (at ActionType) String() string {
 	return "INVALID"
 }
 
-// ProtocolType is either TCP or UDP or OTHER.
+// ProtocolType is either TCP or UDP or SCTP or OTHER.
 type ProtocolType int
 
 const (
@@ -168,6 +305,9 @@ const (
 	// UDP protocol.
 	UDP
 
+	// SCTP protocol.
+	SCTP
+
 	// OTHER is some NON-UDP, NON-TCP traffic (used ONLY in unit tests).
 	OTHER
 
@@ -182,6 +322,8 @@ func (at ProtocolType) String() string {
 		return "TCP"
 	case UDP:
 		return "UDP"
+	case SCTP:
+		return "SCTP"
 	case OTHER:
 		return "OTHER"
 	case ANY:
@@ -71,6 +71,14 @@ type RendererCacheAPI interface {
 	// instead follow the resync with a transaction that updates the configuration
 	// of still present pods and removes the rest (Cache.GetAllPods() \ Txn.GetUpdatedPods()).
 	Resync(tables []*ContivRuleTable) error
+
+	// GetRulePods returns the set of pods that the given rule of a table
+	// installed by the cache applies to.
+	GetRulePods(table *ContivRuleTable, rule *renderer.ContivRule) PodSet
+
+	// GetRulePolicies returns IDs of the policies that the given rule of a table
+	// installed by the cache was derived from (see ContivRule.Policies).
+	GetRulePolicies(table *ContivRuleTable, rule *renderer.ContivRule) []string
 }
 
 // View allows to read the cache content
@@ -518,21 +518,24 @@ func (rct *RendererCacheTxn) buildLocalTable(dstPodID podmodel.ID, dstPodCfg *Po
 // and the destination pod is maintained.
 func (rct *RendererCacheTxn) installLocalRules(dstTable *ContivRuleTable, dstPodCfg *PodConfig, srcPodCfg *PodConfig) {
 	// Determine the set of accessible ports from the source pod point of view.
-	var srcTCP, srcUDP Ports
-	var srcAny bool
+	var srcTCP, srcUDP, srcSCTP Ports
+	var srcOther, srcAny bool
 	if rct.cache.orientation == EgressOrientation {
-		srcTCP, srcUDP, srcAny = getAllowedIngressPorts(dstPodCfg.PodIP, srcPodCfg.Ingress)
+		srcTCP, srcUDP, srcSCTP, srcOther, srcAny = getAllowedIngressPorts(dstPodCfg.PodIP, srcPodCfg.Ingress)
 	} else {
-		srcTCP, srcUDP, srcAny = getAllowedEgressPorts(dstPodCfg.PodIP, srcPodCfg.Egress)
+		srcTCP, srcUDP, srcSCTP, srcOther, srcAny = getAllowedEgressPorts(dstPodCfg.PodIP, srcPodCfg.Egress)
 	}
 
 	// Determine the set of accessible ports from the destination pod point of view.
-	var dstTCP, dstUDP Ports
-	var dstAny bool
+	var dstRules []*renderer.ContivRule
+	var dstTCP, dstUDP, dstSCTP Ports
+	var dstOther, dstAny bool
 	if rct.cache.orientation == EgressOrientation {
-		dstTCP, dstUDP, dstAny = getAllowedEgressPorts(srcPodCfg.PodIP, dstPodCfg.Egress)
+		dstRules = dstPodCfg.Egress
+		dstTCP, dstUDP, dstSCTP, dstOther, dstAny = getAllowedEgressPorts(srcPodCfg.PodIP, dstRules)
 	} else {
-		dstTCP, dstUDP, dstAny = getAllowedIngressPorts(srcPodCfg.PodIP, dstPodCfg.Ingress)
+		dstRules = dstPodCfg.Ingress
+		dstTCP, dstUDP, dstSCTP, dstOther, dstAny = getAllowedIngressPorts(srcPodCfg.PodIP, dstRules)
 	}
 
 	if srcAny {
@@ -540,7 +543,8 @@ func (rct *RendererCacheTxn) installLocalRules(dstTable *ContivRuleTable, dstPod
 	}
 
 	// Intersect allowed traffic
-	if dstAny || !dstTCP.IsSubsetOf(srcTCP) || !dstUDP.IsSubsetOf(srcUDP) {
+	if dstAny || !dstTCP.IsSubsetOf(srcTCP) || !dstUDP.IsSubsetOf(srcUDP) || !dstSCTP.IsSubsetOf(srcSCTP) ||
+		(dstOther && !srcOther) {
 		// cleanup rule subtree with the root node:
 		// 	(egress orientation)  srcIP:ANY:0 -> 0/0:ANY:0
 		// 	(ingress orientation) 0/0:ANY:0   -> srcIP:ANY:0
@@ -560,15 +564,29 @@ func (rct *RendererCacheTxn) installLocalRules(dstTable *ContivRuleTable, dstPod
 			}
 			return true
 		})
+		// The combined rules fully decide the traffic between the pods, therefore
+		// they have to precede rules of admin policies matching the source pod
+		// less specifically.
+		priority := renderer.NamespacedTierPriority
+		for _, rule := range dstRules {
+			if rule.Priority != renderer.NamespacedTierPriority {
+				priority = renderer.MaxPriority
+				break
+			}
+		}
 		// Intersect TCP.
 		allowedTCP := dstTCP.Intersection(srcTCP)
-		rct.installAllowedPorts(dstTable, srcPodCfg.PodIP, allowedTCP, renderer.TCP)
+		rct.installAllowedPorts(dstTable, srcPodCfg.PodIP, allowedTCP, renderer.TCP, priority)
 		// Intersect UDP.
 		allowedUDP := dstUDP.Intersection(srcUDP)
-		rct.installAllowedPorts(dstTable, srcPodCfg.PodIP, allowedUDP, renderer.UDP)
+		rct.installAllowedPorts(dstTable, srcPodCfg.PodIP, allowedUDP, renderer.UDP, priority)
+		// Intersect SCTP.
+		allowedSCTP := dstSCTP.Intersection(srcSCTP)
+		rct.installAllowedPorts(dstTable, srcPodCfg.PodIP, allowedSCTP, renderer.SCTP, priority)
 		// Add the "deny-the-rest" rule.
 		newRule := &renderer.ContivRule{
 			Action:      renderer.ActionDeny,
+			Priority:    priority,
 			SrcNetwork:  &net.IPNet{},
 			DestNetwork: &net.IPNet{},
 			SrcPort:     AnyPort,
@@ -580,6 +598,14 @@ func (rct *RendererCacheTxn) installLocalRules(dstTable *ContivRuleTable, dstPod
 		} else {
 			newRule.DestNetwork = srcPodCfg.PodIP
 		}
+		if dstOther && srcOther {
+			// Traffic of other protocols is allowed by both sides (possible only
+			// with admin policies) - deny only the blocked ports explicitly.
+			rct.installBlockedPorts(dstTable, srcPodCfg.PodIP, allowedTCP, renderer.TCP, priority)
+			rct.installBlockedPorts(dstTable, srcPodCfg.PodIP, allowedUDP, renderer.UDP, priority)
+			rct.installBlockedPorts(dstTable, srcPodCfg.PodIP, allowedSCTP, renderer.SCTP, priority)
+			newRule.Action = renderer.ActionPermit
+		}
 		dstTable.InsertRule(newRule)
 	}
 }
@@ -587,9 +613,11 @@ func (rct *RendererCacheTxn) installLocalRules(dstTable *ContivRuleTable, dstPod
 // installAllowedPorts modifies the table content such that the source pod will
 // be able to communicate with the table owner only on the selected allowed ports
 // of a given protocol with the rest being blocked.
-func (rct *RendererCacheTxn) installAllowedPorts(dstTable *ContivRuleTable, srcPodIP *net.IPNet, allowedPorts Ports, protocol renderer.ProtocolType) {
+func (rct *RendererCacheTxn) installAllowedPorts(dstTable *ContivRuleTable, srcPodIP *net.IPNet, allowedPorts Ports,
+	protocol renderer.ProtocolType, priority uint32) {
 	ruleTemplate := &renderer.ContivRule{
 		Action:      renderer.ActionPermit,
+		Priority:    priority,
 		SrcNetwork:  &net.IPNet{},
 		DestNetwork: &net.IPNet{},
 		SrcPort:     AnyPort,
@@ -608,11 +636,40 @@ func (rct *RendererCacheTxn) installAllowedPorts(dstTable *ContivRuleTable, srcP
 		return
 	}
 
-	// Add explicit rule for each allowed port from the intersection
+	// Add explicit rule for each allowed port (or port range) from the intersection
 	// of ingress with egress.
-	for port := range allowedPorts {
+	for portRange := range allowedPorts {
 		newRule := ruleTemplate.Copy()
-		newRule.DestPort = port
+		newRule.DestPort = portRange.Start
+		if portRange.End > portRange.Start {
+			newRule.DestPortEnd = portRange.End
+		}
+		dstTable.InsertRule(newRule)
+	}
+}
+
+// installBlockedPorts inserts rules denying traffic from the source pod to the
+// table owner on all ports of a given protocol except for the allowed ones.
+func (rct *RendererCacheTxn) installBlockedPorts(dstTable *ContivRuleTable, srcPodIP *net.IPNet, allowedPorts Ports,
+	protocol renderer.ProtocolType, priority uint32) {
+	for portRange := range allowedPorts.Complement() {
+		newRule := &renderer.ContivRule{
+			Action:      renderer.ActionDeny,
+			Priority:    priority,
+			SrcNetwork:  &net.IPNet{},
+			DestNetwork: &net.IPNet{},
+			SrcPort:     AnyPort,
+			DestPort:    portRange.Start,
+			Protocol:    protocol,
+		}
+		if portRange.End > portRange.Start {
+			newRule.DestPortEnd = portRange.End
+		}
+		if rct.cache.orientation == EgressOrientation {
+			newRule.SrcNetwork = srcPodIP
+		} else {
+			newRule.DestNetwork = srcPodIP
+		}
 		dstTable.InsertRule(newRule)
 	}
 }
@@ -19,6 +19,7 @@ package cache
 import (
 	"fmt"
 	"net"
+	"sort"
 	"testing"
 
 	"github.com/onsi/gomega"
@@ -1823,3 +1824,230 @@ func TestResyncIngressOrientation(t *testing.T) {
 	verifyCachedPods(ruleCache, pods, pods)
 	verifyGlobalTable(ruleCache.GetGlobalTable(), globalTableTxn2, globalTable, globalRulesTxn2)
 }
+
+func findRule(table *ContivRuleTable, rule *renderer.ContivRule) *renderer.ContivRule {
+	for _, tableRule := range table.Rules[:table.NumOfRules] {
+		if tableRule.Compare(rule) == 0 {
+			return tableRule
+		}
+	}
+	return nil
+}
+
+func TestRuleOriginEgressOrientation(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestRuleOriginEgressOrientation")
+
+	// Prepare test data
+	allowWeb := &renderer.ContivRule{
+		Action:      renderer.ActionPermit,
+		SrcNetwork:  GetOneHostSubnet(Pod2IP),
+		DestNetwork: &net.IPNet{},
+		SrcPort:     AnyPort,
+		DestPort:    80,
+		Protocol:    renderer.TCP,
+		Policies:    []string{"default/allow-web"},
+	}
+	denyEgress := DenyAll()
+	denyEgress.Policies = []string{"default/allow-web"}
+	allowSubnet := &renderer.ContivRule{
+		Action:      renderer.ActionPermit,
+		SrcNetwork:  &net.IPNet{},
+		DestNetwork: IpNetwork("10.10.0.0/16"),
+		SrcPort:     AnyPort,
+		DestPort:    AnyPort,
+		Protocol:    renderer.ANY,
+		Policies:    []string{"default/allow-subnet"},
+	}
+	denyIngress := DenyAll()
+	denyIngress.Policies = []string{"default/allow-subnet"}
+
+	pod1Cfg := &PodConfig{
+		PodIP:   GetOneHostSubnet(Pod1IP),
+		Egress:  []*renderer.ContivRule{allowWeb, denyEgress},
+		Ingress: []*renderer.ContivRule{allowSubnet, denyIngress},
+	}
+	pod2Cfg := &PodConfig{
+		PodIP: GetOneHostSubnet(Pod2IP),
+	}
+
+	// Create an instance of RendererCache
+	ruleCache := &RendererCache{
+		Deps: Deps{
+			Log: logger,
+		},
+	}
+	ruleCache.Init(EgressOrientation)
+
+	// Run single transaction.
+	txn := ruleCache.NewTxn()
+	txn.Update(Pod1, pod1Cfg)
+	txn.Update(Pod2, pod2Cfg)
+	err := txn.Commit()
+	gomega.Expect(err).To(gomega.BeNil())
+
+	// Verify origin of the local rules.
+	localTable := ruleCache.GetLocalTableByPod(Pod1)
+	gomega.Expect(localTable).ToNot(gomega.BeNil())
+	rule := findRule(localTable, allowWeb)
+	gomega.Expect(rule).ToNot(gomega.BeNil())
+	gomega.Expect(ruleCache.GetRulePods(localTable, rule)).To(gomega.BeEquivalentTo(NewPodSet(Pod1)))
+	gomega.Expect(ruleCache.GetRulePolicies(localTable, rule)).To(gomega.Equal([]string{"default/allow-web"}))
+	rule = findRule(localTable, DenyAll())
+	gomega.Expect(rule).ToNot(gomega.BeNil())
+	gomega.Expect(ruleCache.GetRulePolicies(localTable, rule)).To(gomega.Equal([]string{"default/allow-web"}))
+
+	// Verify origin of the global rules.
+	globalTable := ruleCache.GetGlobalTable()
+	rule = findRule(globalTable, modifySrc(Pod1IP, allowSubnet)[0])
+	gomega.Expect(rule).ToNot(gomega.BeNil())
+	gomega.Expect(ruleCache.GetRulePods(globalTable, rule)).To(gomega.BeEquivalentTo(NewPodSet(Pod1)))
+	gomega.Expect(ruleCache.GetRulePolicies(globalTable, rule)).To(gomega.Equal([]string{"default/allow-subnet"}))
+	rule = findRule(globalTable, blockPodEgress(Pod1IP))
+	gomega.Expect(rule).ToNot(gomega.BeNil())
+	gomega.Expect(ruleCache.GetRulePolicies(globalTable, rule)).To(gomega.Equal([]string{"default/allow-subnet"}))
+}
+
+func TestAdminTierLocalRulesEgressOrientation(t *testing.T) {
+	gomega.RegisterTestingT(t)
+	logger := logrus.DefaultLogger()
+	logger.SetLevel(logging.DebugLevel)
+	logger.Debug("TestAdminTierLocalRulesEgressOrientation")
+
+	const adminPriority1 = renderer.NamespacedTierPriority + 1
+	const adminPriority2 = renderer.NamespacedTierPriority + 2
+	permit, deny := renderer.ActionPermit, renderer.ActionDeny
+
+	// localRule returns a rule of the local table matching traffic from the given source.
+	localRule := func(action renderer.ActionType, priority uint32, srcIP string,
+		protocol renderer.ProtocolType, ports ...uint16) *renderer.ContivRule {
+		rule := portRule(action, priority, "", protocol, ports...)
+		if srcIP != "" {
+			rule.SrcNetwork = GetOneHostSubnet(srcIP)
+		}
+		return rule
+	}
+
+	tests := []struct {
+		name       string
+		dstIP      string                 // IP address of Pod1 (the owner of the verified table)
+		srcIP      string                 // IP address of Pod3
+		dstEgress  []*renderer.ContivRule // egress of Pod1 (matched by source)
+		srcIngress []*renderer.ContivRule // ingress of Pod3 (matched by destination)
+		expected   []*renderer.ContivRule // local table of Pod1
+	}{
+		{
+			name:  "namespaced rules only",
+			dstIP: Pod1IP,
+			srcIP: Pod3IP,
+			dstEgress: []*renderer.ContivRule{
+				localRule(permit, renderer.NamespacedTierPriority, "", renderer.TCP),
+				localRule(deny, renderer.NamespacedTierPriority, "", renderer.ANY),
+			},
+			srcIngress: []*renderer.ContivRule{
+				portRule(permit, renderer.NamespacedTierPriority, "", renderer.TCP, 80, 90),
+				portRule(permit, renderer.NamespacedTierPriority, "", renderer.UDP, 53),
+				portRule(deny, renderer.NamespacedTierPriority, "", renderer.ANY),
+			},
+			expected: []*renderer.ContivRule{
+				localRule(permit, renderer.NamespacedTierPriority, "", renderer.TCP),
+				localRule(deny, renderer.NamespacedTierPriority, "", renderer.ANY),
+				localRule(permit, renderer.NamespacedTierPriority, Pod3IP, renderer.TCP, 80, 90),
+				localRule(deny, renderer.NamespacedTierPriority, Pod3IP, renderer.ANY),
+			},
+		},
+		{
+			// combined rules have to precede the admin rules of Pod1
+			name:  "admin rules raise priority of combined rules",
+			dstIP: Pod1IP,
+			srcIP: Pod3IP,
+			dstEgress: []*renderer.ContivRule{
+				localRule(deny, adminPriority1, "", renderer.TCP, 22),
+			},
+			srcIngress: []*renderer.ContivRule{
+				portRule(permit, renderer.NamespacedTierPriority, "", renderer.TCP, 80),
+				portRule(deny, renderer.NamespacedTierPriority, "", renderer.ANY),
+			},
+			expected: []*renderer.ContivRule{
+				localRule(deny, adminPriority1, "", renderer.TCP, 22),
+				localRule(permit, renderer.MaxPriority, Pod3IP, renderer.TCP, 80),
+				localRule(deny, renderer.MaxPriority, Pod3IP, renderer.ANY),
+				AllowAll(),
+			},
+		},
+		{
+			// other protocols are allowed by both pods - only the blocked
+			// ports are denied explicitly
+			name:  "blocked ports with other protocols allowed",
+			dstIP: Pod1IP,
+			srcIP: Pod3IP,
+			dstEgress: []*renderer.ContivRule{
+				localRule(deny, adminPriority2, "", renderer.TCP, 22),
+				localRule(permit, adminPriority1, "", renderer.ANY),
+			},
+			srcIngress: []*renderer.ContivRule{
+				portRule(deny, adminPriority1, "", renderer.TCP, 8000, 9000),
+			},
+			expected: []*renderer.ContivRule{
+				localRule(deny, adminPriority2, "", renderer.TCP, 22),
+				localRule(permit, adminPriority1, "", renderer.ANY),
+				localRule(permit, renderer.MaxPriority, Pod3IP, renderer.TCP, 1, 21),
+				localRule(permit, renderer.MaxPriority, Pod3IP, renderer.TCP, 23, 7999),
+				localRule(permit, renderer.MaxPriority, Pod3IP, renderer.TCP, 9001, 65535),
+				localRule(permit, renderer.MaxPriority, Pod3IP, renderer.UDP),
+				localRule(permit, renderer.MaxPriority, Pod3IP, renderer.SCTP),
+				localRule(deny, renderer.MaxPriority, Pod3IP, renderer.TCP, 22),
+				localRule(deny, renderer.MaxPriority, Pod3IP, renderer.TCP, 8000, 9000),
+				localRule(permit, renderer.MaxPriority, Pod3IP, renderer.ANY),
+			},
+		},
+		{
+			name:  "admin rules with IPv6 pods",
+			dstIP: "2001:db8::1",
+			srcIP: "2001:db8::2",
+			dstEgress: []*renderer.ContivRule{
+				localRule(deny, adminPriority1, "", renderer.TCP, 22),
+			},
+			srcIngress: []*renderer.ContivRule{
+				portRule(permit, renderer.NamespacedTierPriority, "", renderer.TCP, 80),
+				portRule(deny, renderer.NamespacedTierPriority, "", renderer.ANY),
+			},
+			expected: []*renderer.ContivRule{
+				localRule(deny, adminPriority1, "", renderer.TCP, 22),
+				localRule(permit, renderer.MaxPriority, "2001:db8::2", renderer.TCP, 80),
+				localRule(deny, renderer.MaxPriority, "2001:db8::2", renderer.ANY),
+				AllowAll(),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		logger.Debug(test.name)
+		ruleCache := &RendererCache{
+			Deps: Deps{
+				Log: logger,
+			},
+		}
+		ruleCache.Init(EgressOrientation)
+
+		txn := ruleCache.NewTxn()
+		txn.Update(Pod1, &PodConfig{
+			PodIP:  GetOneHostSubnet(test.dstIP),
+			Egress: test.dstEgress,
+		})
+		txn.Update(Pod3, &PodConfig{
+			PodIP:   GetOneHostSubnet(test.srcIP),
+			Ingress: test.srcIngress,
+		})
+		gomega.Expect(txn.Commit()).To(gomega.BeNil(), test.name)
+
+		// rules of the table are in the order of evaluation
+		expected := test.expected
+		sort.Slice(expected, func(i, j int) bool {
+			return expected[i].Compare(expected[j]) < 0
+		})
+		verifyPodLocalTable(ruleCache, Pod1, nil, expected, NewPodSet(Pod1))
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"net"
+	"sort"
+
+	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
+	"github.com/contiv/vpp/plugins/policy/renderer"
+	"github.com/contiv/vpp/plugins/policy/utils"
+)
+
+// GetRulePods returns the set of pods that the given rule of a table
+// installed by the cache applies to.
+// For local table these are all the pods with the table assigned, rules
+// of the global table apply to the pod with the IP address matched
+// by the rule.
+func (rc *RendererCache) GetRulePods(table *ContivRuleTable, rule *renderer.ContivRule) PodSet {
+	if table.Type == Local {
+		return table.Pods.Copy()
+	}
+	pods := NewPodSet()
+	if podID, found := rc.lookupPodByIP(rc.remoteNetwork(rule)); found {
+		pods.Add(podID)
+	}
+	return pods
+}
+
+// GetRulePolicies returns IDs of the policies that the given rule of a table
+// installed by the cache was derived from.
+// The rules of the tables are built by combining the rules received for
+// the pods, the policies are therefore collected from the received rules
+// that match the traffic of the table rule, with the action of the table
+// rule being preferred.
+func (rc *RendererCache) GetRulePolicies(table *ContivRuleTable, rule *renderer.ContivRule) []string {
+	var policies []string
+	if table.Type == Global {
+		// Global rules are copied from the rules with the opposite orientation.
+		for podID := range rc.GetRulePods(table, rule) {
+			podCfg := rc.config[podID]
+			policies = append(policies, matchingRulePolicies(rule, rc.oppositeRules(podCfg))...)
+		}
+	} else {
+		for podID := range table.Pods {
+			podCfg, hasConfig := rc.config[podID]
+			if !hasConfig {
+				continue
+			}
+			policies = append(policies, matchingRulePolicies(rule, rc.orientedRules(podCfg))...)
+
+			// Rule may be combined with the rules of the peer pod.
+			peerID, isPod := rc.lookupPodByIP(rc.remoteNetwork(rule))
+			if !isPod || podCfg.PodIP == nil {
+				continue
+			}
+			peerRule := rule.Copy()
+			if rc.orientation == EgressOrientation {
+				peerRule.SrcNetwork = &net.IPNet{}
+				peerRule.DestNetwork = podCfg.PodIP
+			} else {
+				peerRule.SrcNetwork = podCfg.PodIP
+				peerRule.DestNetwork = &net.IPNet{}
+			}
+			policies = append(policies, matchingRulePolicies(peerRule, rc.oppositeRules(rc.config[peerID]))...)
+		}
+	}
+	policies = utils.RemoveDuplicates(policies)
+	sort.Strings(policies)
+	return policies
+}
+
+// orientedRules returns rules of the pod with the same orientation as the cache.
+func (rc *RendererCache) orientedRules(podCfg *PodConfig) []*renderer.ContivRule {
+	if rc.orientation == EgressOrientation {
+		return podCfg.Egress
+	}
+	return podCfg.Ingress
+}
+
+// oppositeRules returns rules of the pod with the opposite orientation wrt. the cache.
+func (rc *RendererCache) oppositeRules(podCfg *PodConfig) []*renderer.ContivRule {
+	if rc.orientation == EgressOrientation {
+		return podCfg.Ingress
+	}
+	return podCfg.Egress
+}
+
+// remoteNetwork returns the network of the rule which is matched against
+// the opposite side of the traffic than the one where the table is applied,
+// i.e. the pod for the global table and the peer for a local table.
+func (rc *RendererCache) remoteNetwork(rule *renderer.ContivRule) *net.IPNet {
+	if rc.orientation == EgressOrientation {
+		return rule.SrcNetwork
+	}
+	return rule.DestNetwork
+}
+
+// lookupPodByIP returns ID of the pod with the given IP address expressed
+// as one-host subnet.
+func (rc *RendererCache) lookupPodByIP(ipNet *net.IPNet) (podID podmodel.ID, found bool) {
+	if ipNet == nil || len(ipNet.IP) == 0 {
+		return podID, false
+	}
+	ones, bits := ipNet.Mask.Size()
+	if ones != bits {
+		return podID, false
+	}
+	for podID, podCfg := range rc.config {
+		if podCfg.PodIP != nil && podCfg.PodIP.IP.Equal(ipNet.IP) {
+			return podID, true
+		}
+	}
+	return podID, false
+}
+
+// matchingRulePolicies returns policies of the first (in the order of evaluation)
+// of the given rules matching all the traffic of <rule>. Rules with the same
+// action as <rule> are preferred.
+func matchingRulePolicies(rule *renderer.ContivRule, rules []*renderer.ContivRule) []string {
+	var matching []*renderer.ContivRule
+	for _, candidate := range rules {
+		if ruleCovers(candidate, rule) {
+			matching = append(matching, candidate)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		return matching[i].Compare(matching[j]) < 0
+	})
+	for _, candidate := range matching {
+		if candidate.Action == rule.Action {
+			return candidate.Policies
+		}
+	}
+	if len(matching) > 0 {
+		return matching[0].Policies
+	}
+	return nil
+}
+
+// ruleCovers returns true if <rule1> matches all the traffic matched by <rule2>.
+func ruleCovers(rule1, rule2 *renderer.ContivRule) bool {
+	if !networkCovers(rule1.SrcNetwork, rule2.SrcNetwork) ||
+		!networkCovers(rule1.DestNetwork, rule2.DestNetwork) {
+		return false
+	}
+	if rule1.Protocol == renderer.ANY {
+		return true
+	}
+	if rule1.Protocol != rule2.Protocol {
+		return false
+	}
+	if rule1.SrcPort != AnyPort && rule1.SrcPort != rule2.SrcPort {
+		return false
+	}
+	if rule1.DestPort == AnyPort {
+		return true
+	}
+	if rule2.DestPort == AnyPort {
+		return false
+	}
+	end1 := rule1.DestPort
+	if rule1.DestPortEnd > end1 {
+		end1 = rule1.DestPortEnd
+	}
+	end2 := rule2.DestPort
+	if rule2.DestPortEnd > end2 {
+		end2 = rule2.DestPortEnd
+	}
+	return rule1.DestPort <= rule2.DestPort && end2 <= end1
+}
+
+// networkCovers returns true if <net1> includes all addresses of <net2>.
+// Empty network stands for all addresses.
+func networkCovers(net1, net2 *net.IPNet) bool {
+	if net1 == nil || len(net1.IP) == 0 {
+		return true
+	}
+	if net2 == nil || len(net2.IP) == 0 {
+		return false
+	}
+	ones1, bits1 := net1.Mask.Size()
+	ones2, bits2 := net2.Mask.Size()
+	return bits1 == bits2 && ones1 <= ones2 && net1.Contains(net2.IP)
+}
@@ -16,13 +16,34 @@ package cache
 
 import (
 	"net"
+	"sort"
 
 	"fmt"
 	"github.com/contiv/vpp/plugins/policy/renderer"
 )
 
-// Ports is a set of port numbers.
-type Ports map[uint16]struct{}
+// PortRange is an inclusive range of port numbers <Start, End>.
+// A single port is represented as a range with Start equal to End.
+type PortRange struct {
+	Start uint16
+	End   uint16
+}
+
+// Contains returns true if the range includes the given port.
+func (pr PortRange) Contains(port uint16) bool {
+	return pr.Start <= port && port <= pr.End
+}
+
+// String converts PortRange into a human-readable string.
+func (pr PortRange) String() string {
+	if pr.Start == pr.End {
+		return fmt.Sprintf("%d", pr.Start)
+	}
+	return fmt.Sprintf("%d-%d", pr.Start, pr.End)
+}
+
+// Ports is a set of port numbers and port ranges.
+type Ports map[PortRange]struct{}
 
 // AnyPort is a constant that represents any port.
 const AnyPort uint16 = 0
@@ -38,21 +59,61 @@ func NewPorts(portNums ...uint16) Ports {
 
 // Add port number into the set
 func (p Ports) Add(port uint16) {
-	p[port] = struct{}{}
+	p[PortRange{Start: port, End: port}] = struct{}{}
+}
+
+// AddRange adds range of ports <start, end> into the set.
+// If <end> is not greater than <start>, only the start port is added.
+func (p Ports) AddRange(start, end uint16) {
+	if start == AnyPort || end <= start {
+		p.Add(start)
+		return
+	}
+	p[PortRange{Start: start, End: end}] = struct{}{}
 }
 
 // Has returns true if the given port is in the set.
 func (p Ports) Has(port uint16) bool {
-	return p.HasExplicit(0) || p.HasExplicit(port)
+	if p.HasExplicit(AnyPort) || p.HasExplicit(port) {
+		return true
+	}
+	for portRange := range p {
+		if portRange.Contains(port) {
+			return true
+		}
+	}
+	return false
 }
 
 // HasExplicit returns true if the given port is in the set regardless of AnyPort
-// presence.
+// presence (and regardless of port ranges).
 func (p Ports) HasExplicit(port uint16) bool {
-	_, has := p[port]
+	_, has := p[PortRange{Start: port, End: port}]
 	return has
 }
 
+// covers returns true if the given range is fully covered by the union of the port
+// ranges from this set.
+func (p Ports) covers(portRange PortRange) bool {
+	if p.HasExplicit(AnyPort) {
+		return true
+	}
+	port := uint32(portRange.Start)
+	for port <= uint32(portRange.End) {
+		var next uint32
+		for pr := range p {
+			if pr.Contains(uint16(port)) && uint32(pr.End)+1 > next {
+				next = uint32(pr.End) + 1
+			}
+		}
+		if next == 0 {
+			return false
+		}
+		port = next
+	}
+	return true
+}
+
 // IsSubsetOf returns true if this set is a subset of <p2>.
 func (p Ports) IsSubsetOf(p2 Ports) bool {
 	if p2.Has(AnyPort) {
@@ -61,8 +122,8 @@ func (p Ports) IsSubsetOf(p2 Ports) bool {
 	if p.Has(AnyPort) {
 		return false
 	}
-	for port := range p {
-		if !p2.Has(port) {
+	for portRange := range p {
+		if !p2.covers(portRange) {
 			return false
 		}
 	}
@@ -78,9 +139,18 @@ func (p Ports) Intersection(p2 Ports) Ports {
 		return p
 	}
 	intersection := NewPorts()
-	for port := range p {
-		if p2.Has(port) {
-			intersection.Add(port)
+	for pr := range p {
+		for pr2 := range p2 {
+			start, end := pr.Start, pr.End
+			if pr2.Start > start {
+				start = pr2.Start
+			}
+			if pr2.End < end {
+				end = pr2.End
+			}
+			if start <= end {
+				intersection.AddRange(start, end)
+			}
 		}
 	}
 	return intersection
@@ -91,8 +161,8 @@ func (p Ports) Intersection(p2 Ports) Ports {
 func (p Ports) String() string {
 	ports := "{"
 	count := 0
-	for port := range p {
-		ports += fmt.Sprintf("%d", port)
+	for portRange := range p {
+		ports += portRange.String()
 		count++
 		if count < len(p) {
 			ports += ","
@@ -102,70 +172,164 @@ func (p Ports) String() string {
 	return ports
 }
 
-// getAllowedEgressPorts returns allowed destination UDP and TCP ports for a given
-// source pod IP wrt. egress rules.
-func getAllowedEgressPorts(srcIP *net.IPNet, egress []*renderer.ContivRule) (tcp, udp Ports, any bool) {
-	tcp = NewPorts()
-	udp = NewPorts()
-	hasDeny := false
-	for _, rule := range egress {
-		if rule.Action == renderer.ActionDeny {
-			// This implementation assumes there is only the single default deny-all rule (for ANY protocol),
-			// or no deny rule at all.
-			hasDeny = true
-			continue
-		}
-		if len(rule.SrcNetwork.IP) > 0 && !rule.SrcNetwork.Contains(srcIP.IP) {
-			continue
+// Join merges <p2> into this set.
+func (p Ports) Join(p2 Ports) Ports {
+	for portRange := range p2 {
+		p[portRange] = struct{}{}
+	}
+	return p
+}
+
+// Subtract returns a new set with ports from this set which are not in <p2>.
+func (p Ports) Subtract(p2 Ports) Ports {
+	var result []PortRange
+	subtrahend := p2.ranges()
+	for _, portRange := range p.ranges() {
+		pieces := []PortRange{portRange}
+		for _, sub := range subtrahend {
+			var remaining []PortRange
+			for _, piece := range pieces {
+				if sub.End < piece.Start || sub.Start > piece.End {
+					remaining = append(remaining, piece)
+					continue
+				}
+				if sub.Start > piece.Start {
+					remaining = append(remaining, PortRange{Start: piece.Start, End: sub.Start - 1})
+				}
+				if sub.End < piece.End {
+					remaining = append(remaining, PortRange{Start: sub.End + 1, End: piece.End})
+				}
+			}
+			pieces = remaining
 		}
-		/* matching ALLOW rule */
-		switch rule.Protocol {
-		case renderer.TCP:
-			tcp.Add(rule.DestPort)
-		case renderer.UDP:
-			udp.Add(rule.DestPort)
-		case renderer.ANY:
-			tcp.Add(AnyPort)
-			udp.Add(AnyPort)
-			any = true
+		result = append(result, pieces...)
+	}
+	return newPortsFromRanges(result)
+}
+
+// Complement returns a new set with all the ports not included in this set.
+func (p Ports) Complement() Ports {
+	return NewPorts(AnyPort).Subtract(p)
+}
+
+// ranges returns the port ranges of the set with AnyPort expanded to the full
+// range of port numbers.
+func (p Ports) ranges() (ranges []PortRange) {
+	if p.HasExplicit(AnyPort) {
+		return []PortRange{allPorts}
+	}
+	for portRange := range p {
+		ranges = append(ranges, portRange)
+	}
+	return ranges
+}
+
+// newPortsFromRanges builds a set of ports from the given ranges, using AnyPort
+// to represent the full range of port numbers.
+func newPortsFromRanges(ranges []PortRange) Ports {
+	ports := NewPorts()
+	for _, portRange := range ranges {
+		if portRange == allPorts {
+			return NewPorts(AnyPort)
 		}
+		ports.AddRange(portRange.Start, portRange.End)
 	}
-	if !hasDeny {
-		return NewPorts(AnyPort), NewPorts(AnyPort), true
+	if len(ports) > 0 && ports.covers(allPorts) {
+		return NewPorts(AnyPort)
 	}
-	return tcp, udp, any
+	return ports
 }
 
-// getAllowedIngressPorts returns allowed destination UDP and TCP ports for a given
-// destination pod IP wrt. ingress rules.
-func getAllowedIngressPorts(dstIP *net.IPNet, ingress []*renderer.ContivRule) (tcp, udp Ports, any bool) {
-	tcp = NewPorts()
-	udp = NewPorts()
-	hasDeny := false
-	for _, rule := range ingress {
-		if rule.Action == renderer.ActionDeny {
-			// This implementation assumes there is only the single default deny-all rule (for ANY protocol),
-			// or no deny rule at all.
-			hasDeny = true
-			continue
+// allPorts is the range of all valid port numbers.
+var allPorts = PortRange{Start: 1, End: ^uint16(0)}
+
+// getAllowedEgressPorts returns allowed destination UDP, TCP and SCTP ports for a given
+// source pod IP wrt. egress rules. The flag <other> tells whether traffic of other
+// protocols is allowed, <any> is true if all the traffic is allowed.
+func getAllowedEgressPorts(srcIP *net.IPNet, egress []*renderer.ContivRule) (tcp, udp, sctp Ports, other, any bool) {
+	return getAllowedPorts(srcIP, egress, func(rule *renderer.ContivRule) *net.IPNet {
+		return rule.SrcNetwork
+	})
+}
+
+// getAllowedIngressPorts returns allowed destination UDP, TCP and SCTP ports for a given
+// destination pod IP wrt. ingress rules. The flag <other> tells whether traffic of other
+// protocols is allowed, <any> is true if all the traffic is allowed.
+func getAllowedIngressPorts(dstIP *net.IPNet, ingress []*renderer.ContivRule) (tcp, udp, sctp Ports, other, any bool) {
+	return getAllowedPorts(dstIP, ingress, func(rule *renderer.ContivRule) *net.IPNet {
+		return rule.DestNetwork
+	})
+}
+
+// getAllowedPorts evaluates the rules with the first-match semantic for the traffic
+// exchanged with the given peer and returns the set of allowed destination ports
+// for TCP, UDP and SCTP.
+// Rules of the namespaced tier are expected to be only permits, followed by a single
+// default deny-all rule (or no deny rule at all). Rules of the higher priorities
+// (admin tier) may combine actions arbitrarily.
+func getAllowedPorts(peerIP *net.IPNet, rules []*renderer.ContivRule,
+	peerNetwork func(rule *renderer.ContivRule) *net.IPNet) (tcp, udp, sctp Ports, other, any bool) {
+
+	// evaluate the rules in the order of the table
+	ordered := make([]*renderer.ContivRule, len(rules))
+	copy(ordered, rules)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Compare(ordered[j]) < 0
+	})
+
+	protocols := []renderer.ProtocolType{renderer.TCP, renderer.UDP, renderer.SCTP}
+	allowed := make(map[renderer.ProtocolType]Ports)
+	decided := make(map[renderer.ProtocolType]Ports)
+	for _, protocol := range protocols {
+		allowed[protocol] = NewPorts()
+		decided[protocol] = NewPorts()
+	}
+	var otherDecided, isolated bool
+	for _, rule := range ordered {
+		if rule.Action == renderer.ActionDeny && rule.Priority == renderer.NamespacedTierPriority {
+			// pod isolated by a namespaced policy
+			isolated = true
 		}
-		if len(rule.DestNetwork.IP) > 0 && !rule.DestNetwork.Contains(dstIP.IP) {
+		peerNet := peerNetwork(rule)
+		if len(peerNet.IP) > 0 && !peerNet.Contains(peerIP.IP) {
 			continue
 		}
-		/* matching ALLOW rule */
-		switch rule.Protocol {
-		case renderer.TCP:
-			tcp.Add(rule.DestPort)
-		case renderer.UDP:
-			udp.Add(rule.DestPort)
-		case renderer.ANY:
-			tcp.Add(AnyPort)
-			udp.Add(AnyPort)
-			any = true
+		/* matching rule */
+		for _, protocol := range protocols {
+			if rule.Protocol != renderer.ANY && rule.Protocol != protocol {
+				continue
+			}
+			rulePorts := NewPorts()
+			if rule.Protocol == renderer.ANY {
+				rulePorts.Add(AnyPort)
+			} else {
+				rulePorts.AddRange(rule.DestPort, rule.DestPortEnd)
+			}
+			if rule.Action == renderer.ActionPermit {
+				allowed[protocol].Join(rulePorts.Subtract(decided[protocol]))
+			}
+			decided[protocol].Join(rulePorts)
+		}
+		if rule.Protocol == renderer.ANY && !otherDecided {
+			otherDecided = true
+			other = rule.Action == renderer.ActionPermit
+		}
+	}
+
+	// traffic not matched by any rule is allowed unless the pod is isolated
+	if !isolated {
+		for _, protocol := range protocols {
+			allowed[protocol].Join(decided[protocol].Complement())
+		}
+		if !otherDecided {
+			other = true
 		}
 	}
-	if !hasDeny {
-		return NewPorts(AnyPort), NewPorts(AnyPort), true
+
+	for _, protocol := range protocols {
+		allowed[protocol] = newPortsFromRanges(allowed[protocol].ranges())
 	}
-	return tcp, udp, any
+	tcp, udp, sctp = allowed[renderer.TCP], allowed[renderer.UDP], allowed[renderer.SCTP]
+	any = other && tcp.HasExplicit(AnyPort) && udp.HasExplicit(AnyPort) && sctp.HasExplicit(AnyPort)
+	return tcp, udp, sctp, other, any
 }
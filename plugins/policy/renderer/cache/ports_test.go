@@ -0,0 +1,405 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/contiv/vpp/plugins/policy/renderer"
+	. "github.com/contiv/vpp/plugins/policy/renderer/testdata"
+	. "github.com/contiv/vpp/plugins/policy/utils"
+)
+
+// portRanges builds a set of ports from pairs of range boundaries.
+func portRanges(bounds ...uint16) Ports {
+	ports := NewPorts()
+	for i := 0; i+1 < len(bounds); i += 2 {
+		ports.AddRange(bounds[i], bounds[i+1])
+	}
+	return ports
+}
+
+// samePorts returns true if both sets include the same port numbers
+// (regardless of how the ports are split into ranges).
+func samePorts(p1, p2 Ports) bool {
+	return p1.IsSubsetOf(p2) && p2.IsSubsetOf(p1)
+}
+
+// portRule returns a rule matching traffic of the given protocol with the given
+// network (used as destination) and destination ports (none, single port or a range).
+func portRule(action renderer.ActionType, priority uint32, network string,
+	protocol renderer.ProtocolType, ports ...uint16) *renderer.ContivRule {
+	rule := &renderer.ContivRule{
+		Action:      action,
+		Priority:    priority,
+		SrcNetwork:  IpNetwork(""),
+		DestNetwork: IpNetwork(network),
+		SrcPort:     AnyPort,
+		DestPort:    AnyPort,
+		Protocol:    protocol,
+	}
+	if len(ports) > 0 {
+		rule.DestPort = ports[0]
+	}
+	if len(ports) > 1 {
+		rule.DestPortEnd = ports[1]
+	}
+	return rule
+}
+
+func TestPortsSubtract(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	tests := []struct {
+		name     string
+		ports    Ports
+		subtract Ports
+		expected Ports
+	}{
+		{
+			name:     "port from a range",
+			ports:    portRanges(80, 90),
+			subtract: NewPorts(85),
+			expected: portRanges(80, 84, 86, 90),
+		},
+		{
+			name:     "disjoint ports",
+			ports:    NewPorts(80),
+			subtract: NewPorts(443),
+			expected: NewPorts(80),
+		},
+		{
+			name:     "overlapping ranges",
+			ports:    portRanges(1000, 2000),
+			subtract: portRanges(900, 1100, 1900, 2100),
+			expected: portRanges(1101, 1899),
+		},
+		{
+			name:     "covering range",
+			ports:    portRanges(80, 90),
+			subtract: portRanges(1, 100),
+			expected: NewPorts(),
+		},
+		{
+			name:     "range from any port",
+			ports:    NewPorts(AnyPort),
+			subtract: portRanges(1, 1023),
+			expected: portRanges(1024, 65535),
+		},
+		{
+			name:     "any port from any port",
+			ports:    NewPorts(AnyPort),
+			subtract: NewPorts(AnyPort),
+			expected: NewPorts(),
+		},
+		{
+			name:     "any port from a range",
+			ports:    portRanges(80, 90),
+			subtract: NewPorts(AnyPort),
+			expected: NewPorts(),
+		},
+		{
+			name:     "nothing to subtract",
+			ports:    portRanges(22, 22, 80, 90),
+			subtract: NewPorts(),
+			expected: portRanges(22, 22, 80, 90),
+		},
+		{
+			name:     "remainder covering all ports",
+			ports:    portRanges(1, 100, 101, 65535),
+			subtract: NewPorts(),
+			expected: NewPorts(AnyPort),
+		},
+	}
+
+	for _, test := range tests {
+		gomega.Expect(test.ports.Subtract(test.subtract)).To(gomega.Equal(test.expected), test.name)
+	}
+}
+
+func TestPortsComplement(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	tests := []struct {
+		name     string
+		ports    Ports
+		expected Ports
+	}{
+		{
+			name:     "empty set",
+			ports:    NewPorts(),
+			expected: NewPorts(AnyPort),
+		},
+		{
+			name:     "any port",
+			ports:    NewPorts(AnyPort),
+			expected: NewPorts(),
+		},
+		{
+			name:     "range",
+			ports:    portRanges(1, 1023),
+			expected: portRanges(1024, 65535),
+		},
+		{
+			name:     "single ports",
+			ports:    NewPorts(80, 443),
+			expected: portRanges(1, 79, 81, 442, 444, 65535),
+		},
+		{
+			name:     "ranges covering all ports",
+			ports:    portRanges(1, 100, 101, 65535),
+			expected: NewPorts(),
+		},
+	}
+
+	for _, test := range tests {
+		gomega.Expect(test.ports.Complement()).To(gomega.Equal(test.expected), test.name)
+	}
+}
+
+func TestPortsJoin(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	tests := []struct {
+		name     string
+		ports    Ports
+		join     Ports
+		expected Ports
+	}{
+		{
+			name:     "single ports",
+			ports:    NewPorts(80),
+			join:     NewPorts(443),
+			expected: NewPorts(80, 443),
+		},
+		{
+			name:     "overlapping ranges are kept",
+			ports:    portRanges(80, 90),
+			join:     NewPorts(85),
+			expected: portRanges(80, 90, 85, 85),
+		},
+		{
+			name:     "any port",
+			ports:    NewPorts(22),
+			join:     NewPorts(AnyPort),
+			expected: NewPorts(22, AnyPort),
+		},
+		{
+			name:     "empty sets",
+			ports:    NewPorts(),
+			join:     NewPorts(),
+			expected: NewPorts(),
+		},
+	}
+
+	for _, test := range tests {
+		joined := test.ports.Join(test.join)
+		gomega.Expect(joined).To(gomega.Equal(test.expected), test.name)
+		// the set is merged in-place
+		gomega.Expect(test.ports).To(gomega.Equal(test.expected), test.name)
+	}
+}
+
+func TestGetAllowedPorts(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	const (
+		adminPriority1 = renderer.NamespacedTierPriority + 1
+		adminPriority2 = renderer.NamespacedTierPriority + 2
+		adminPriority3 = renderer.NamespacedTierPriority + 3
+	)
+	permit, deny := renderer.ActionPermit, renderer.ActionDeny
+
+	tests := []struct {
+		name   string
+		peerIP string
+		egress bool // rules match the peer as source instead of destination
+		rules  []*renderer.ContivRule
+		tcp    Ports
+		udp    Ports
+		sctp   Ports
+		other  bool
+		any    bool
+	}{
+		{
+			name:   "no rules",
+			peerIP: Pod1IP,
+			tcp:    NewPorts(AnyPort),
+			udp:    NewPorts(AnyPort),
+			sctp:   NewPorts(AnyPort),
+			other:  true,
+			any:    true,
+		},
+		{
+			name:   "namespaced permits with default deny",
+			peerIP: Pod1IP,
+			rules: []*renderer.ContivRule{
+				portRule(permit, renderer.NamespacedTierPriority, "", renderer.TCP, 80),
+				portRule(permit, renderer.NamespacedTierPriority, "10.10.1.0/24", renderer.UDP, 53),
+				portRule(permit, renderer.NamespacedTierPriority, "10.10.2.0/24", renderer.TCP, 443),
+				portRule(deny, renderer.NamespacedTierPriority, "", renderer.ANY),
+			},
+			tcp:  NewPorts(80),
+			udp:  NewPorts(53),
+			sctp: NewPorts(),
+		},
+		{
+			name:   "admin deny over namespaced allow",
+			peerIP: Pod1IP,
+			rules: []*renderer.ContivRule{
+				portRule(permit, renderer.NamespacedTierPriority, "", renderer.TCP),
+				portRule(deny, renderer.NamespacedTierPriority, "", renderer.ANY),
+				portRule(deny, adminPriority2, Pod1IP+"/32", renderer.TCP, 80),
+			},
+			tcp:  portRanges(1, 79, 81, 65535),
+			udp:  NewPorts(),
+			sctp: NewPorts(),
+		},
+		{
+			// admin Pass for TCP 80-90 evaluated by the namespaced rules (permit TCP 80,
+			// deny the rest), followed by a lower-priority admin Allow for all TCP
+			name:   "pass falls through to namespaced rules",
+			peerIP: Pod1IP,
+			rules: []*renderer.ContivRule{
+				portRule(permit, adminPriority3, Pod1IP+"/32", renderer.TCP, 80),
+				portRule(deny, adminPriority3, Pod1IP+"/32", renderer.TCP, 80, 90),
+				portRule(permit, adminPriority2, "", renderer.TCP),
+				portRule(permit, renderer.NamespacedTierPriority, "", renderer.TCP, 80),
+				portRule(deny, renderer.NamespacedTierPriority, "", renderer.ANY),
+			},
+			tcp:  portRanges(1, 80, 91, 65535),
+			udp:  NewPorts(),
+			sctp: NewPorts(),
+		},
+		{
+			name:   "port ranges subtracted across tiers",
+			peerIP: Pod1IP,
+			rules: []*renderer.ContivRule{
+				portRule(deny, adminPriority2, "", renderer.TCP, 1000, 2000),
+				portRule(permit, adminPriority1, "", renderer.TCP, 500, 1500),
+				portRule(permit, adminPriority1, "", renderer.TCP, 1800, 2500),
+				portRule(permit, adminPriority1, "", renderer.SCTP, 3868),
+				portRule(deny, renderer.NamespacedTierPriority, "", renderer.ANY),
+			},
+			tcp:  portRanges(500, 999, 2001, 2500),
+			udp:  NewPorts(),
+			sctp: NewPorts(3868),
+		},
+		{
+			name:   "admin deny of another peer",
+			peerIP: Pod1IP,
+			rules: []*renderer.ContivRule{
+				portRule(deny, adminPriority1, "10.10.2.0/24", renderer.TCP),
+			},
+			tcp:   NewPorts(AnyPort),
+			udp:   NewPorts(AnyPort),
+			sctp:  NewPorts(AnyPort),
+			other: true,
+			any:   true,
+		},
+		{
+			name:   "admin deny without namespaced isolation",
+			peerIP: Pod1IP,
+			rules: []*renderer.ContivRule{
+				portRule(deny, adminPriority1, "", renderer.TCP, 22),
+			},
+			tcp:   portRanges(1, 21, 23, 65535),
+			udp:   NewPorts(AnyPort),
+			sctp:  NewPorts(AnyPort),
+			other: true,
+		},
+		{
+			name:   "other protocols allowed by admin tier",
+			peerIP: Pod1IP,
+			rules: []*renderer.ContivRule{
+				portRule(deny, adminPriority2, "", renderer.UDP, 53),
+				portRule(permit, adminPriority1, "", renderer.ANY),
+				portRule(deny, renderer.NamespacedTierPriority, "", renderer.ANY),
+			},
+			tcp:   NewPorts(AnyPort),
+			udp:   portRanges(1, 52, 54, 65535),
+			sctp:  NewPorts(AnyPort),
+			other: true,
+		},
+		{
+			name:   "everything allowed by admin tier",
+			peerIP: Pod1IP,
+			rules: []*renderer.ContivRule{
+				portRule(permit, adminPriority1, Pod1IP+"/32", renderer.ANY),
+				portRule(deny, renderer.NamespacedTierPriority, "", renderer.ANY),
+			},
+			tcp:   NewPorts(AnyPort),
+			udp:   NewPorts(AnyPort),
+			sctp:  NewPorts(AnyPort),
+			other: true,
+			any:   true,
+		},
+		{
+			name:   "IPv6 peer",
+			peerIP: "2001:db8::1",
+			rules: []*renderer.ContivRule{
+				portRule(deny, adminPriority2, "2001:db8::/64", renderer.TCP, 80),
+				portRule(deny, adminPriority2, "10.10.1.0/24", renderer.UDP),
+				portRule(permit, renderer.NamespacedTierPriority, "2001:db8::1/128", renderer.TCP),
+				portRule(deny, renderer.NamespacedTierPriority, "", renderer.ANY),
+			},
+			tcp:  portRanges(1, 79, 81, 65535),
+			udp:  NewPorts(),
+			sctp: NewPorts(),
+		},
+		{
+			name:   "IPv6 peer as source",
+			peerIP: "2001:db8::1",
+			egress: true,
+			rules: []*renderer.ContivRule{
+				portRule(permit, adminPriority1, "2001:db8::/64", renderer.UDP, 53),
+				portRule(deny, adminPriority1, "2001:db8::/64", renderer.ANY),
+				portRule(permit, renderer.NamespacedTierPriority, "", renderer.TCP, 8080),
+			},
+			tcp:  NewPorts(),
+			udp:  NewPorts(53),
+			sctp: NewPorts(),
+		},
+	}
+
+	for _, test := range tests {
+		var (
+			tcp, udp, sctp Ports
+			other, any     bool
+		)
+		peerIP := GetOneHostSubnet(test.peerIP)
+		if test.egress {
+			var rules []*renderer.ContivRule
+			for _, rule := range test.rules {
+				rule = rule.Copy()
+				rule.SrcNetwork, rule.DestNetwork = rule.DestNetwork, rule.SrcNetwork
+				rules = append(rules, rule)
+			}
+			tcp, udp, sctp, other, any = getAllowedEgressPorts(peerIP, rules)
+		} else {
+			tcp, udp, sctp, other, any = getAllowedIngressPorts(peerIP, test.rules)
+		}
+		gomega.Expect(samePorts(tcp, test.tcp)).To(gomega.BeTrue(),
+			"%s: TCP ports %s, expected %s", test.name, tcp, test.tcp)
+		gomega.Expect(samePorts(udp, test.udp)).To(gomega.BeTrue(),
+			"%s: UDP ports %s, expected %s", test.name, udp, test.udp)
+		gomega.Expect(samePorts(sctp, test.sctp)).To(gomega.BeTrue(),
+			"%s: SCTP ports %s, expected %s", test.name, sctp, test.sctp)
+		gomega.Expect(other).To(gomega.Equal(test.other), test.name)
+		gomega.Expect(any).To(gomega.Equal(test.any), test.name)
+	}
+}
@@ -31,6 +31,7 @@ import (
 	linux_namespace "go.ligato.io/vpp-agent/v3/proto/ligato/linux/namespace"
 
 	"net"
+	"sort"
 	"strings"
 )
 
@@ -224,6 +225,11 @@ func (rt *RendererTxn) renderRuleChains(podID podmodel.ID, podIP *net.IPNet, ing
 		c.ChainType = linux_iptables.RuleChain_OUTPUT
 		c.Rules = append(c.Rules, reflexiveRule)
 		c.Rules = append(c.Rules, fmt.Sprintf(allowSvcRule, rt.renderer.IPAM.ServiceNetwork().String()))
+		// rules are applied AS-IS, only ordered by priority and specificity
+		ingress = append([]*renderer.ContivRule{}, ingress...)
+		sort.SliceStable(ingress, func(i, j int) bool {
+			return ingress[i].Compare(ingress[j]) < 0
+		})
 		for _, i := range ingress {
 			c.Rules = append(c.Rules, rt.contivRuleToIPtables(i))
 		}
@@ -241,6 +247,11 @@ func (rt *RendererTxn) renderRuleChains(podID podmodel.ID, podIP *net.IPNet, ing
 		c.Table = linux_iptables.RuleChain_FILTER
 		c.ChainType = linux_iptables.RuleChain_INPUT
 		c.Rules = append(c.Rules, reflexiveRule)
+		// rules are applied AS-IS, only ordered by priority and specificity
+		egress = append([]*renderer.ContivRule{}, egress...)
+		sort.SliceStable(egress, func(i, j int) bool {
+			return egress[i].Compare(egress[j]) < 0
+		})
 		for _, e := range egress {
 			c.Rules = append(c.Rules, rt.contivRuleToIPtables(e))
 		}
@@ -266,7 +277,11 @@ func (rt *RendererTxn) contivRuleToIPtables(rule *renderer.ContivRule) string {
 		parts = append(parts, fmt.Sprintf("-d %v", rule.DestNetwork.String()))
 	}
 	if rule.DestPort != 0 {
-		parts = append(parts, fmt.Sprintf("--dport %v", rule.DestPort))
+		if rule.DestPortEnd > rule.DestPort {
+			parts = append(parts, fmt.Sprintf("--dport %v:%v", rule.DestPort, rule.DestPortEnd))
+		} else {
+			parts = append(parts, fmt.Sprintf("--dport %v", rule.DestPort))
+		}
 	}
 	parts = append(parts, fmt.Sprintf("-j %v", rt.actionToStr(rule.Action)))
 	return strings.Join(parts, " ")
@@ -291,6 +306,9 @@ func (rt *RendererTxn) protocolToStr(p renderer.ProtocolType) string {
 	if p == renderer.UDP {
 		return "udp"
 	}
+	if p == renderer.SCTP {
+		return "sctp"
+	}
 	return "all"
 }
 
@@ -13,3 +13,68 @@
 // limitations under the License.
 
 package iptables
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.ligato.io/cn-infra/v2/logging/logrus"
+
+	linux_iptables "go.ligato.io/vpp-agent/v3/proto/ligato/linux/iptables"
+
+	. "github.com/contiv/vpp/mock/podmanager"
+	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
+	"github.com/contiv/vpp/plugins/podmanager"
+	"github.com/contiv/vpp/plugins/policy/renderer"
+)
+
+func TestEgressRulesOrderedByPriority(t *testing.T) {
+	RegisterTestingT(t)
+
+	podID := podmodel.ID{Name: "pod1", Namespace: "default"}
+	podManager := NewMockPodManager()
+	podManager.AddPod(&podmanager.LocalPod{ID: podID, NetworkNamespace: "/proc/1234/ns/net"})
+
+	logger := logrus.DefaultLogger()
+	r := &Renderer{Deps: Deps{Log: logger, PodManager: podManager}}
+	rt := &RendererTxn{Log: logger, renderer: r}
+
+	_, podIP, _ := net.ParseCIDR("10.1.1.1/32")
+	_, adminNet, _ := net.ParseCIDR("192.168.1.0/24")
+
+	// namespaced rule first, admin rules in the reverse order of priorities
+	namespaced := &renderer.ContivRule{
+		Action:   renderer.ActionPermit,
+		Protocol: renderer.ANY,
+		Priority: renderer.NamespacedTierPriority,
+	}
+	adminDeny := &renderer.ContivRule{
+		Action:     renderer.ActionDeny,
+		SrcNetwork: adminNet,
+		Protocol:   renderer.TCP,
+		DestPort:   80,
+		Priority:   1000,
+	}
+	adminAllow := &renderer.ContivRule{
+		Action:     renderer.ActionPermit,
+		SrcNetwork: adminNet,
+		Protocol:   renderer.TCP,
+		DestPort:   80,
+		Priority:   2000,
+	}
+	egress := []*renderer.ContivRule{namespaced, adminDeny, adminAllow}
+
+	chains := rt.renderRuleChains(podID, podIP, nil, egress)
+	chain, hasChain := chains[linux_iptables.RuleChainKey(rt.chainName(podID, podIP, false))]
+	Expect(hasChain).To(BeTrue())
+	Expect(chain.(*linux_iptables.RuleChain).Rules).To(Equal([]string{
+		reflexiveRule,
+		rt.contivRuleToIPtables(adminAllow),
+		rt.contivRuleToIPtables(adminDeny),
+		rt.contivRuleToIPtables(namespaced),
+	}))
+
+	// the input slice is left intact
+	Expect(egress).To(Equal([]*renderer.ContivRule{namespaced, adminDeny, adminAllow}))
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restapi
+
+import (
+	"time"
+
+	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
+)
+
+const (
+	// RESTPrefix is versioned prefix for REST urls.
+	RESTPrefix = "/contiv/v1/"
+
+	// RestURLPolicyCounters is versioned URL for the policy rule counters REST endpoint.
+	// The output can be limited to a single pod with the query parameters
+	// PodNamespaceParam and PodNameParam.
+	RestURLPolicyCounters = RESTPrefix + "policy/counters"
+
+	// RestURLPolicyCheck is versioned URL for the policy dry-run REST endpoint.
+	// PolicyCheckRequest is expected in the body of the POST request.
+	RestURLPolicyCheck = RESTPrefix + "policy/check"
+
+	// RestURLPolicyPods is versioned URL for the REST endpoint describing policies
+	// applied to the pods of the current node together with the rendered rule tables.
+	// The output can be limited to a single pod with the query parameters
+	// PodNamespaceParam and PodNameParam.
+	RestURLPolicyPods = RESTPrefix + "policy/pods"
+
+	// PodNamespaceParam is the name of the query parameter selecting namespace of the pod.
+	PodNamespaceParam = "namespace"
+
+	// PodNameParam is the name of the query parameter selecting name of the pod.
+	PodNameParam = "pod"
+)
+
+// PolicyCounters represents hit counters of the policy rules installed
+// on the current node together with the recent hits of deny rules.
+// It is exposed by the policy counters REST handler.
+type PolicyCounters struct {
+	NodeName       string           `json:"nodeName"`
+	Rules          []*RuleCounter   `json:"rules"`
+	DeniedRuleHits []*DeniedRuleHit `json:"deniedRuleHits"`
+}
+
+// RuleCounter is the number of packets matched by a policy rule.
+type RuleCounter struct {
+	Table    string   `json:"table"`
+	Rule     string   `json:"rule"`
+	Action   string   `json:"action"`
+	Pods     []string `json:"pods,omitempty"`
+	Egress   bool     `json:"egress"` // true if the rule matches traffic sent by the pods
+	Policies []string `json:"policies,omitempty"`
+	Packets  uint64   `json:"packets"`
+}
+
+// DeniedRuleHit reports packets matched by a deny rule since the previous collection
+// of the rule counters. Networks, protocol and port are those of the rule
+// (VPP counts packets per rule, not per flow), not of the actual denied flows.
+type DeniedRuleHit struct {
+	Time        time.Time `json:"time"`
+	Pods        []string  `json:"pods"`
+	Egress      bool      `json:"egress"` // true if the rule matches traffic sent by the pods
+	SrcNetwork  string    `json:"srcNetwork"`
+	DestNetwork string    `json:"destNetwork"`
+	Protocol    string    `json:"protocol"`
+	Port        string    `json:"port"`
+	Policies    []string  `json:"policies"`
+	Packets     uint64    `json:"packets"` // packets denied since the previous collection
+}
+
+// NodePodPolicies lists policies and rule tables of the pods deployed
+// on the current node. It is exposed by the policy pods REST handler.
+type NodePodPolicies struct {
+	NodeName string         `json:"nodeName"`
+	Pods     []*PodPolicies `json:"pods"`
+}
+
+// PodPolicies describes policies applied to a pod and the rule tables rendered
+// for the pod.
+type PodPolicies struct {
+	Pod       string       `json:"pod"`
+	Interface string       `json:"interface,omitempty"`
+	Policies  []string     `json:"policies"`
+	Tables    []*RuleTable `json:"tables"`
+}
+
+// RuleTable is a table of rules installed by the renderer which applies
+// to the traffic of a pod.
+type RuleTable struct {
+	Name     string   `json:"name"`
+	Index    int      `json:"index"`    // e.g. VPP ACL index, -1 if not available
+	Attached bool     `json:"attached"` // true if installed on the pod interface
+	Egress   bool     `json:"egress"`   // true if the table matches traffic sent by the pod
+	Rules    []string `json:"rules"`
+}
+
+// PolicyCheckRequest asks whether the given traffic would be allowed by the network
+// policies currently reflected from K8s, optionally combined with candidate
+// policies that are not yet applied.
+type PolicyCheckRequest struct {
+	// Source and Destination are either pods ("namespace/name") or IP addresses.
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+
+	// Protocol is one of TCP, UDP, SCTP (TCP if empty).
+	Protocol string `json:"protocol"`
+	Port     uint16 `json:"port"`
+
+	// Candidates are added to the current policies, replacing policies
+	// with the same namespace and name.
+	Candidates []*policymodel.Policy `json:"candidates,omitempty"`
+}
+
+// PolicyCheckResponse is the verdict of the policy dry-run.
+// The traffic is allowed if it passes the policies of both the source
+// (egress) and the destination (ingress) pod.
+type PolicyCheckResponse struct {
+	Allowed bool                `json:"allowed"`
+	Egress  *PolicyCheckVerdict `json:"egress,omitempty"`  // nil if the source is not a pod
+	Ingress *PolicyCheckVerdict `json:"ingress,omitempty"` // nil if the destination is not a pod
+}
+
+// PolicyCheckVerdict is the verdict of the policies of a single pod.
+type PolicyCheckVerdict struct {
+	Pod     string `json:"pod"`
+	Allowed bool   `json:"allowed"`
+
+	// Isolated is true if namespaced policies isolate the pod in this direction
+	// (traffic not allowed by any rule is denied). Admin policies alone do not
+	// isolate the pod.
+	Isolated bool `json:"isolated"`
+
+	// Rule is the first ContivRule matching the traffic (empty if none).
+	Rule string `json:"rule,omitempty"`
+
+	// Policies lists the policies that the matching rule was generated from.
+	Policies []string `json:"policies,omitempty"`
+}
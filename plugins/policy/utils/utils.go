@@ -256,6 +256,34 @@ func ComparePorts(a, b uint16) int {
 	return 1
 }
 
+// ComparePortRanges is a comparison function for two port ranges <aStart, aEnd>
+// and <bStart, bEnd>. End=0 (or End <= Start) means that the range consists
+// of the start port only. Start=0 means "all-ports".
+// Narrower ranges are lower in the order than wider ranges, hence if range
+// a is a subset of b, then a is lower than b. For single ports the order
+// is the same as of ComparePorts.
+func ComparePortRanges(aStart, aEnd, bStart, bEnd uint16) int {
+	aWidth, bWidth := portRangeWidth(aStart, aEnd), portRangeWidth(bStart, bEnd)
+	if aWidth != bWidth {
+		if aWidth < bWidth {
+			return -1
+		}
+		return 1
+	}
+	return ComparePorts(aStart, bStart)
+}
+
+// portRangeWidth returns the number of ports in the range minus one.
+func portRangeWidth(start, end uint16) uint32 {
+	if start == 0 {
+		return uint32(^uint16(0))
+	}
+	if end <= start {
+		return 0
+	}
+	return uint32(end - start)
+}
+
 // CompareIPNetsBytes returns an integer comparing two IP network addresses
 // represented as raw bytes lexicographically.
 func CompareIPNetsBytes(aPrefixLen uint8, aIP [16]byte, bPrefixLen uint8, bIP [16]byte) int {
@@ -0,0 +1,200 @@
+/*
+ * // Copyright (c) 2018 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"go.ligato.io/cn-infra/v2/logging"
+
+	svcmodel "github.com/contiv/vpp/plugins/ksr/model/service"
+)
+
+// API defines the interface of the health-check server as used by the service
+// processor.
+type API interface {
+	// SyncServices updates the set of services with externalTrafficPolicy=Local
+	// and a health-check node port, together with the number of their node-local
+	// endpoints. Servers of services not present in the map are stopped.
+	SyncServices(services map[svcmodel.ID]*ServiceHealth) error
+}
+
+// ServiceHealth describes the node-local health of a single service.
+type ServiceHealth struct {
+	// NodePort is the health-check node port allocated for the service.
+	NodePort uint16
+
+	// LocalEndpoints is the number of service endpoints deployed on this node.
+	LocalEndpoints int
+}
+
+// Response is the JSON body served on the health-check node port,
+// compatible with the response of kube-proxy.
+type Response struct {
+	Service        ResponseServiceID `json:"service"`
+	LocalEndpoints int               `json:"localEndpoints"`
+}
+
+// ResponseServiceID identifies the service in the health-check response.
+type ResponseServiceID struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// Server answers health-check requests (the Kubernetes-standard /healthz) sent
+// by external load-balancers to health-check node ports of services with
+// externalTrafficPolicy=Local. The response reports the number of node-local
+// endpoints and the status code is 200 if there is at least one local endpoint,
+// 503 otherwise. Like with kube-proxy, the response does not depend on the URL
+// path of the request.
+type Server struct {
+	Log logging.Logger
+
+	// Listen is used to open the listener for a given port.
+	// If nil, the server listens on all host addresses.
+	Listen func(port uint16) (net.Listener, error)
+
+	lock     sync.Mutex
+	services map[uint16]*hcService // health-check node port -> service
+}
+
+// hcService represents a single service with a running health-check listener.
+type hcService struct {
+	id             svcmodel.ID
+	localEndpoints int
+	server         *http.Server
+}
+
+// Init initializes the server.
+func (s *Server) Init() error {
+	s.services = make(map[uint16]*hcService)
+	if s.Listen == nil {
+		s.Listen = listenOnAllAddresses
+	}
+	return nil
+}
+
+// SyncServices updates the set of served health-check node ports and the reported
+// endpoint counts.
+// Failure to open a listener is only logged, the port is re-tried with the next
+// update.
+func (s *Server) SyncServices(services map[svcmodel.ID]*ServiceHealth) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// stop listeners no longer needed
+	for port, hcSvc := range s.services {
+		health, exists := services[hcSvc.id]
+		if exists && health.NodePort == port {
+			continue
+		}
+		s.Log.WithFields(logging.Fields{
+			"service": hcSvc.id,
+			"port":    port,
+		}).Info("Closing health-check node port")
+		if err := hcSvc.server.Close(); err != nil {
+			s.Log.Warnf("Failed to close health-check server on port %d: %v", port, err)
+		}
+		delete(s.services, port)
+	}
+
+	// start new listeners and update endpoint counts
+	for svcID, health := range services {
+		if health.NodePort == 0 {
+			continue
+		}
+		if hcSvc, running := s.services[health.NodePort]; running {
+			if hcSvc.id != svcID {
+				s.Log.Warnf("Health-check node port %d is already used by service %v",
+					health.NodePort, hcSvc.id)
+				continue
+			}
+			hcSvc.localEndpoints = health.LocalEndpoints
+			continue
+		}
+		listener, err := s.Listen(health.NodePort)
+		if err != nil {
+			s.Log.WithFields(logging.Fields{
+				"service": svcID,
+				"port":    health.NodePort,
+			}).Errorf("Failed to open health-check node port: %v", err)
+			continue
+		}
+		hcSvc := &hcService{
+			id:             svcID,
+			localEndpoints: health.LocalEndpoints,
+		}
+		hcSvc.server = &http.Server{Handler: s.handler(health.NodePort)}
+		s.services[health.NodePort] = hcSvc
+		s.Log.WithFields(logging.Fields{
+			"service": svcID,
+			"port":    health.NodePort,
+		}).Info("Serving health-check node port")
+		go func(server *http.Server, listener net.Listener) {
+			if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+				s.Log.Errorf("Health-check server failed: %v", err)
+			}
+		}(hcSvc.server, listener)
+	}
+	return nil
+}
+
+// Close stops all health-check listeners.
+func (s *Server) Close() error {
+	return s.SyncServices(nil)
+}
+
+// handler returns HTTP handler for the health-check node port.
+func (s *Server) handler(port uint16) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		hcSvc, exists := s.services[port]
+		var resp Response
+		if exists {
+			resp.Service = ResponseServiceID{Namespace: hcSvc.id.Namespace, Name: hcSvc.id.Name}
+			resp.LocalEndpoints = hcSvc.localEndpoints
+		}
+		s.lock.Unlock()
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+
+		body, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if resp.LocalEndpoints == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+		w.Write(body)
+	})
+}
+
+// listenOnAllAddresses opens TCP listener for the given port on all host addresses.
+func listenOnAllAddresses(port uint16) (net.Listener, error) {
+	return net.Listen("tcp", fmt.Sprintf(":%d", port))
+}
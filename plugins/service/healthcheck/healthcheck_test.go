@@ -0,0 +1,102 @@
+// Copyright (c) 2018 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.ligato.io/cn-infra/v2/logging/logrus"
+
+	svcmodel "github.com/contiv/vpp/plugins/ksr/model/service"
+)
+
+// testListener opens listeners on random loopback ports and remembers
+// their addresses under the requested node ports.
+type testListener struct {
+	addrs map[uint16]string
+}
+
+func (tl *testListener) listen(port uint16) (net.Listener, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	tl.addrs[port] = listener.Addr().String()
+	return listener, nil
+}
+
+func getHealth(addr string) (statusCode int, resp *Response) {
+	httpResp, err := http.Get("http://" + addr + "/healthz")
+	Expect(err).To(BeNil())
+	defer httpResp.Body.Close()
+	Expect(httpResp.Header.Get("Content-Type")).To(Equal("application/json"))
+	resp = &Response{}
+	Expect(json.NewDecoder(httpResp.Body).Decode(resp)).To(BeNil())
+	return httpResp.StatusCode, resp
+}
+
+func TestHealthCheckServer(t *testing.T) {
+	RegisterTestingT(t)
+
+	tl := &testListener{addrs: make(map[uint16]string)}
+	server := &Server{
+		Log:    logrus.DefaultLogger(),
+		Listen: tl.listen,
+	}
+	Expect(server.Init()).To(BeNil())
+
+	svc1 := svcmodel.ID{Namespace: "default", Name: "svc1"}
+	svc2 := svcmodel.ID{Namespace: "default", Name: "svc2"}
+
+	// start serving two services
+	Expect(server.SyncServices(map[svcmodel.ID]*ServiceHealth{
+		svc1: {NodePort: 32001, LocalEndpoints: 2},
+		svc2: {NodePort: 32002, LocalEndpoints: 0},
+	})).To(BeNil())
+	Expect(tl.addrs).To(HaveLen(2))
+
+	status, resp := getHealth(tl.addrs[32001])
+	Expect(status).To(Equal(http.StatusOK))
+	Expect(resp.Service.Namespace).To(Equal("default"))
+	Expect(resp.Service.Name).To(Equal("svc1"))
+	Expect(resp.LocalEndpoints).To(Equal(2))
+
+	status, resp = getHealth(tl.addrs[32002])
+	Expect(status).To(Equal(http.StatusServiceUnavailable))
+	Expect(resp.Service.Name).To(Equal("svc2"))
+	Expect(resp.LocalEndpoints).To(Equal(0))
+
+	// update endpoint counts, remove svc1
+	addr1 := tl.addrs[32001]
+	Expect(server.SyncServices(map[svcmodel.ID]*ServiceHealth{
+		svc2: {NodePort: 32002, LocalEndpoints: 1},
+	})).To(BeNil())
+
+	status, resp = getHealth(tl.addrs[32002])
+	Expect(status).To(Equal(http.StatusOK))
+	Expect(resp.LocalEndpoints).To(Equal(1))
+
+	_, err := http.Get("http://" + addr1 + "/healthz")
+	Expect(err).ToNot(BeNil())
+
+	// close the server
+	Expect(server.Close()).To(BeNil())
+	_, err = http.Get("http://" + tl.addrs[32002] + "/healthz")
+	Expect(err).ToNot(BeNil())
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/contiv/vpp/plugins/statscollector"
 	"go.ligato.io/cn-infra/v2/config"
 	"go.ligato.io/cn-infra/v2/logging"
+	"go.ligato.io/cn-infra/v2/rpc/rest"
 	"go.ligato.io/cn-infra/v2/servicelabel"
 	"go.ligato.io/vpp-agent/v3/plugins/govppmux"
 )
@@ -30,6 +31,7 @@ func NewPlugin(opts ...Option) *Plugin {
 	p.ServiceLabel = &servicelabel.DefaultPlugin
 	p.GoVPP = &govppmux.DefaultPlugin
 	p.Stats = &statscollector.DefaultPlugin
+	p.HTTPHandlers = &rest.DefaultPlugin
 
 	for _, o := range opts {
 		o(p)
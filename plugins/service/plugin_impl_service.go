@@ -16,26 +16,32 @@ package service
 
 import (
 	"strings"
+	"sync"
 
 	"git.fd.io/govpp.git/api"
 	"github.com/contiv/vpp/plugins/ipam/ipalloc"
 
 	"github.com/contiv/vpp/plugins/statscollector"
 	"go.ligato.io/cn-infra/v2/infra"
+	"go.ligato.io/cn-infra/v2/rpc/rest"
 	"go.ligato.io/cn-infra/v2/servicelabel"
 
 	"go.ligato.io/vpp-agent/v3/plugins/govppmux"
 
 	"github.com/contiv/vpp/plugins/contivconf"
 	controller "github.com/contiv/vpp/plugins/controller/api"
+	egressgwmodel "github.com/contiv/vpp/plugins/crd/handler/egressgateway/model"
 	"github.com/contiv/vpp/plugins/ipam"
 	"github.com/contiv/vpp/plugins/ipnet"
 	epmodel "github.com/contiv/vpp/plugins/ksr/model/endpoints"
+	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
 	svcmodel "github.com/contiv/vpp/plugins/ksr/model/service"
 	"github.com/contiv/vpp/plugins/nodesync"
 	"github.com/contiv/vpp/plugins/podmanager"
 	"github.com/contiv/vpp/plugins/service/config"
+	"github.com/contiv/vpp/plugins/service/healthcheck"
 	"github.com/contiv/vpp/plugins/service/processor"
+	"github.com/contiv/vpp/plugins/service/renderer"
 	"github.com/contiv/vpp/plugins/service/renderer/ipv6route"
 	"github.com/contiv/vpp/plugins/service/renderer/nat44"
 	"github.com/contiv/vpp/plugins/service/renderer/srv6"
@@ -54,11 +60,19 @@ type Plugin struct {
 	updateTxn controller.UpdateOperations
 	changes   []string
 
+	// protects the state of the processor and the renderers against concurrent
+	// access from the REST handlers
+	stateLock sync.Mutex
+
 	// layers of the service plugin
 	processor         *processor.ServiceProcessor
+	healthCheck       *healthcheck.Server
 	nat44Renderer     *nat44.Renderer
 	ipv6RouteRenderer *ipv6route.Renderer
 	srv6Renderer      *srv6.Renderer
+
+	// services with loadBalancerSourceRanges, updated by the renderers
+	lbSourceRanges *renderer.LBSourceRanges
 }
 
 // Deps defines dependencies of the service plugin.
@@ -73,6 +87,7 @@ type Deps struct {
 	GoVPP           govppmux.API       /* used for direct NAT binary API calls */
 	Stats           statscollector.API /* used for exporting the statistics */
 	ConfigRetriever controller.ConfigRetriever
+	HTTPHandlers    rest.HTTPHandlers /* used for exposing the state of services */
 }
 
 func (p *Plugin) useNat44Renderer(goVppCh api.Channel) {
@@ -91,7 +106,8 @@ func (p *Plugin) useNat44Renderer(goVppCh api.Channel) {
 			ResyncTxnFactory: func() controller.ResyncOperations {
 				return p.resyncTxn
 			},
-			Stats: p.Stats,
+			Stats:          p.Stats,
+			LBSourceRanges: p.lbSourceRanges,
 		},
 	}
 
@@ -117,6 +133,7 @@ func (p *Plugin) useSRv6Renderer() {
 			ResyncTxnFactory: func() controller.ResyncOperations {
 				return p.resyncTxn
 			},
+			LBSourceRanges: p.lbSourceRanges,
 		},
 	}
 
@@ -168,6 +185,11 @@ func (p *Plugin) Init() error {
 		return err
 	}
 
+	p.healthCheck = &healthcheck.Server{
+		Log: p.Log.NewLogger("-healthCheck"),
+	}
+	p.healthCheck.Init()
+
 	p.processor = &processor.ServiceProcessor{
 		Deps: processor.Deps{
 			Log:          p.Log.NewLogger("-serviceProcessor"),
@@ -177,10 +199,12 @@ func (p *Plugin) Init() error {
 			IPNet:        p.IPNet,
 			NodeSync:     p.NodeSync,
 			PodManager:   p.PodManager,
+			HealthCheck:  p.healthCheck,
 		},
 	}
 	p.processor.Init()
 
+	p.lbSourceRanges = renderer.NewLBSourceRanges()
 	if !p.ContivConf.GetIPAMConfig().UseIPv6 {
 		if p.ContivConf.GetRoutingConfig().UseSRv6ForServices {
 			// use SRv6 renderer
@@ -197,6 +221,7 @@ func (p *Plugin) Init() error {
 		}
 	}
 
+	p.registerRESTHandlers()
 	return nil
 }
 
@@ -227,6 +252,11 @@ func (p *Plugin) HandlesEvent(event controller.Event) bool {
 			return true
 		case ipalloc.Keyword:
 			return true
+		case podmodel.PodKeyword:
+			// pod annotations may set load-balancing weights
+			return true
+		case egressgwmodel.Keyword:
+			return true
 		default:
 			// unhandled Kubernetes state change
 			return false
@@ -253,6 +283,8 @@ func (p *Plugin) HandlesEvent(event controller.Event) bool {
 func (p *Plugin) Resync(event controller.Event, kubeStateData controller.KubeStateData,
 	resyncCount int, txn controller.ResyncOperations) error {
 
+	p.stateLock.Lock()
+	defer p.stateLock.Unlock()
 	p.resyncTxn = txn
 	p.updateTxn = nil
 	return p.processor.Resync(kubeStateData)
@@ -263,6 +295,8 @@ func (p *Plugin) Resync(event controller.Event, kubeStateData controller.KubeSta
 //  - AddPod & DeletePod
 //  - NodeUpdate event
 func (p *Plugin) Update(event controller.Event, txn controller.UpdateOperations) (changeDescription string, err error) {
+	p.stateLock.Lock()
+	defer p.stateLock.Unlock()
 	p.resyncTxn = nil
 	p.updateTxn = txn
 	p.changes = []string{}
@@ -271,12 +305,22 @@ func (p *Plugin) Update(event controller.Event, txn controller.UpdateOperations)
 	return changeDescription, err
 }
 
+// GetRestrictedFrontends returns frontends of services (LB ingress IPs and node
+// ports) with access restricted to the loadBalancerSourceRanges.
+func (p *Plugin) GetRestrictedFrontends() []*renderer.RestrictedFrontend {
+	p.stateLock.Lock()
+	defer p.stateLock.Unlock()
+	return p.lbSourceRanges.GetRestrictedFrontends()
+}
+
 // Revert is called for failed AddPod event.
 func (p *Plugin) Revert(event controller.Event) error {
+	p.stateLock.Lock()
+	defer p.stateLock.Unlock()
 	return p.processor.Revert(event)
 }
 
-// Close is NOOP.
+// Close stops the health-check node port listeners.
 func (p *Plugin) Close() error {
-	return nil
+	return p.healthCheck.Close()
 }
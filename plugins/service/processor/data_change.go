@@ -18,8 +18,10 @@ package processor
 
 import (
 	controller "github.com/contiv/vpp/plugins/controller/api"
+	egressgwmodel "github.com/contiv/vpp/plugins/crd/handler/egressgateway/model"
 	"github.com/contiv/vpp/plugins/ipam/ipalloc"
 	epmodel "github.com/contiv/vpp/plugins/ksr/model/endpoints"
+	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
 	svcmodel "github.com/contiv/vpp/plugins/ksr/model/service"
 )
 
@@ -53,6 +55,28 @@ func (sp *ServiceProcessor) propagateDataChangeEv(event *controller.KubeStateCha
 			return sp.processCustomIPAlloc(alloc)
 		}
 		// no-op for delete, handled in ProcessDeletingPod
+
+	case podmodel.PodKeyword:
+		if event.NewValue != nil && event.PrevValue != nil {
+			newPod := event.NewValue.(*podmodel.Pod)
+			prevPod := event.PrevValue.(*podmodel.Pod)
+			if newPod.Annotations[backendWeightAnnotation] != prevPod.Annotations[backendWeightAnnotation] {
+				if err := sp.processUpdatedBackendWeight(podmodel.GetID(newPod)); err != nil {
+					return err
+				}
+			}
+		}
+		// added/removed pods are reflected via endpoints into services,
+		// pod IPs and labels may change the set of pods selected by egress gateways
+		return sp.renderEgressGateways()
+
+	case egressgwmodel.Keyword:
+		if event.NewValue != nil {
+			egressGw := event.NewValue.(*egressgwmodel.EgressGateway)
+			return sp.processEgressGatewayChange(egressGw.Name, egressGw)
+		}
+		egressGw := event.PrevValue.(*egressgwmodel.EgressGateway)
+		return sp.processEgressGatewayChange(egressGw.Name, nil)
 	}
 	return nil
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/contiv/vpp/plugins/ipam/ipalloc"
 
 	controller "github.com/contiv/vpp/plugins/controller/api"
+	egressgwmodel "github.com/contiv/vpp/plugins/crd/handler/egressgateway/model"
 	epmodel "github.com/contiv/vpp/plugins/ksr/model/endpoints"
 	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
 	svcmodel "github.com/contiv/vpp/plugins/ksr/model/service"
@@ -29,10 +30,11 @@ import (
 // ResyncEventData wraps an entire state of K8s services that should be reflected
 // into VPP.
 type ResyncEventData struct {
-	Pods          []podmodel.ID
-	Endpoints     []*epmodel.Endpoints
-	Services      []*svcmodel.Service
-	IPAllocations []*ipalloc.CustomIPAllocation
+	Pods           []podmodel.ID
+	Endpoints      []*epmodel.Endpoints
+	Services       []*svcmodel.Service
+	IPAllocations  []*ipalloc.CustomIPAllocation
+	EgressGateways []*egressgwmodel.EgressGateway
 }
 
 // NewResyncEventData creates an empty instance of ResyncEventData.
@@ -97,5 +99,11 @@ func (sp *ServiceProcessor) parseResyncEv(kubeStateData controller.KubeStateData
 		event.IPAllocations = append(event.IPAllocations, alloc)
 	}
 
+	// collect egress gateways
+	for _, egressGwProto := range kubeStateData[egressgwmodel.Keyword] {
+		egressGw := egressGwProto.(*egressgwmodel.EgressGateway)
+		event.EgressGateways = append(event.EgressGateways, egressGw)
+	}
+
 	return event
 }
@@ -0,0 +1,227 @@
+/*
+ * // Copyright (c) 2019 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+package processor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.ligato.io/cn-infra/v2/logging"
+
+	egressgwmodel "github.com/contiv/vpp/plugins/crd/handler/egressgateway/model"
+	"github.com/contiv/vpp/plugins/podmanager"
+	"github.com/contiv/vpp/plugins/service/renderer"
+)
+
+const (
+	// maxEgressPoolSize limits the number of egress IPs expanded from subnets
+	// of a single egress gateway.
+	maxEgressPoolSize = 1024
+)
+
+// processEgressGatewayChange updates the processor's view of egress gateways
+// and re-renders them.
+func (sp *ServiceProcessor) processEgressGatewayChange(name string, egressGw *egressgwmodel.EgressGateway) error {
+	sp.Log.WithFields(logging.Fields{
+		"name":          name,
+		"egressGateway": egressGw,
+	}).Debug("ServiceProcessor - processEgressGatewayChange()")
+
+	if egressGw == nil {
+		delete(sp.egressGws, name)
+	} else {
+		sp.egressGws[name] = egressGw
+	}
+	return sp.renderEgressGateways()
+}
+
+// renderEgressGateways (re)builds the list of egress gateways and passes it
+// to renderers supporting egress gateways, but only if something has changed.
+func (sp *ServiceProcessor) renderEgressGateways() error {
+	egressGws := sp.buildEgressGateways()
+	if reflect.DeepEqual(egressGws, sp.renderedEgressGws) {
+		return nil
+	}
+	for _, svcRenderer := range sp.renderers {
+		if egressGwRenderer, supported := svcRenderer.(renderer.EgressGatewayRendererAPI); supported {
+			if err := egressGwRenderer.UpdateEgressGateways(egressGws); err != nil {
+				return err
+			}
+		}
+	}
+	sp.renderedEgressGws = egressGws
+	return nil
+}
+
+// buildEgressGateways converts egress gateways from the CRD representation into
+// the form used by renderers: selects pods and expands the pool of egress IPs
+// shared by the selected pods.
+func (sp *ServiceProcessor) buildEgressGateways() []*renderer.EgressGateway {
+	var egressGws []*renderer.EgressGateway
+
+	// process gateways in the order of names for determinism
+	var names []string
+	for name := range sp.egressGws {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	allNodes := sp.NodeSync.GetAllNodes()
+	thisNode := sp.ServiceLabel.GetAgentLabel()
+	localPods := sp.PodManager.GetLocalPods()
+	pods := sp.PodManager.GetPods()
+	podSubnet := sp.IPAM.PodSubnetAllNodes(defaultPodNetwork)
+
+	for _, name := range names {
+		crd := sp.egressGws[name]
+		egressGw := &renderer.EgressGateway{
+			Name:  name,
+			Local: crd.Node == thisNode,
+		}
+		if node, hasNode := allNodes[crd.Node]; hasNode {
+			egressGw.NodeID = node.ID
+			egressGw.GatewayIP = sp.getGatewayIP(node.ID)
+		} else {
+			sp.Log.Warnf("Node %s of the egress gateway %s is not (yet) known", crd.Node, name)
+		}
+
+		// select pods
+		var selected []*podmanager.Pod
+		for _, pod := range pods {
+			if !sp.isEgressGatewayPod(crd, pod) {
+				continue
+			}
+			podIP := net.ParseIP(pod.IPAddress).To4()
+			if podIP == nil || (podSubnet != nil && !podSubnet.Contains(podIP)) {
+				// pod without IP, IPv6 pod or pod in the host network
+				continue
+			}
+			selected = append(selected, pod)
+		}
+		sort.Slice(selected, func(i, j int) bool {
+			return selected[i].ID.String() < selected[j].ID.String()
+		})
+
+		for _, egressIP := range expandEgressIPs(crd.EgressIps) {
+			egressGw.EgressIPs = append(egressGw.EgressIPs, net.ParseIP(egressIP).To4())
+		}
+		if len(egressGw.EgressIPs) == 0 {
+			sp.Log.Warnf("Egress gateway %s has no valid egress IP", name)
+		}
+
+		for _, pod := range selected {
+			egressPod := &renderer.EgressGatewayPod{
+				ID: pod.ID,
+				IP: net.ParseIP(pod.IPAddress).To4(),
+			}
+			if _, isLocal := localPods[pod.ID]; isLocal {
+				egressPod.Local = true
+				egressPod.IfName, _, _, _ = sp.IPNet.GetPodIfNames(pod.ID.Namespace, pod.ID.Name)
+			}
+			egressGw.Pods = append(egressGw.Pods, egressPod)
+		}
+		egressGws = append(egressGws, egressGw)
+	}
+	return egressGws
+}
+
+// isEgressGatewayPod returns true if the given pod is selected by the egress gateway.
+func (sp *ServiceProcessor) isEgressGatewayPod(egressGw *egressgwmodel.EgressGateway, pod *podmanager.Pod) bool {
+	if len(egressGw.Namespaces) > 0 {
+		var nsMatch bool
+		for _, namespace := range egressGw.Namespaces {
+			if namespace == pod.ID.Namespace {
+				nsMatch = true
+				break
+			}
+		}
+		if !nsMatch {
+			return false
+		}
+	}
+	for label, value := range egressGw.PodSelector {
+		if podValue, hasLabel := pod.Labels[label]; !hasLabel || podValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// getGatewayIP returns IP address of the given node through which other nodes
+// reach its VPP.
+func (sp *ServiceProcessor) getGatewayIP(nodeID uint32) net.IP {
+	var (
+		gwIP net.IP
+		err  error
+	)
+	if sp.IPNet.GetVxlanBVIIfName() != "" {
+		gwIP, _, err = sp.IPAM.VxlanIPAddress(nodeID)
+	} else {
+		gwIP, _, err = sp.IPAM.NodeIPAddress(nodeID)
+	}
+	if err != nil {
+		sp.Log.Warnf("Failed to compute gateway IP of the node with ID %d: %v", nodeID, err)
+		return nil
+	}
+	return gwIP
+}
+
+// expandEgressIPs expands the egress IP addresses and subnets into a sorted
+// list of IP addresses.
+func expandEgressIPs(egressIPs []string) (pool []string) {
+	seen := make(map[string]bool)
+	add := func(ip net.IP) {
+		if !seen[ip.String()] && len(pool) < maxEgressPoolSize {
+			seen[ip.String()] = true
+			pool = append(pool, ip.String())
+		}
+	}
+	for _, egressIP := range egressIPs {
+		egressIP = strings.TrimSpace(egressIP)
+		if !strings.Contains(egressIP, "/") {
+			if ip := net.ParseIP(egressIP).To4(); ip != nil {
+				add(ip)
+			}
+			continue
+		}
+		_, subnet, err := net.ParseCIDR(egressIP)
+		if err != nil || subnet.IP.To4() == nil {
+			continue
+		}
+		ones, bits := subnet.Mask.Size()
+		first := binary.BigEndian.Uint32(subnet.IP.To4())
+		last := first + uint32(1<<uint(bits-ones)) - 1
+		if ones < 31 {
+			// exclude network and broadcast address
+			first++
+			last--
+		}
+		for ipNum := first; ipNum <= last && ipNum >= first && len(pool) < maxEgressPoolSize; ipNum++ {
+			ip := make(net.IP, net.IPv4len)
+			binary.BigEndian.PutUint32(ip, ipNum)
+			add(ip)
+		}
+	}
+	sort.Slice(pool, func(i, j int) bool {
+		return bytes.Compare(net.ParseIP(pool[i]).To4(), net.ParseIP(pool[j]).To4()) < 0
+	})
+	return pool
+}
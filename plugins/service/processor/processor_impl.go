@@ -17,6 +17,7 @@
 package processor
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/contiv/vpp/plugins/ipam/ipalloc"
@@ -26,6 +27,7 @@ import (
 
 	"github.com/contiv/vpp/plugins/contivconf"
 	controller "github.com/contiv/vpp/plugins/controller/api"
+	egressgwmodel "github.com/contiv/vpp/plugins/crd/handler/egressgateway/model"
 	"github.com/contiv/vpp/plugins/ipam"
 	"github.com/contiv/vpp/plugins/ipnet"
 	epmodel "github.com/contiv/vpp/plugins/ksr/model/endpoints"
@@ -33,6 +35,7 @@ import (
 	svcmodel "github.com/contiv/vpp/plugins/ksr/model/service"
 	"github.com/contiv/vpp/plugins/nodesync"
 	"github.com/contiv/vpp/plugins/podmanager"
+	"github.com/contiv/vpp/plugins/service/healthcheck"
 	"github.com/contiv/vpp/plugins/service/renderer"
 )
 
@@ -54,6 +57,10 @@ type ServiceProcessor struct {
 	/* local frontend and backend interfaces */
 	frontendIfs renderer.Interfaces
 	backendIfs  renderer.Interfaces
+
+	/* egress gateways */
+	egressGws         map[string]*egressgwmodel.EgressGateway
+	renderedEgressGws []*renderer.EgressGateway
 }
 
 // Deps lists dependencies of ServiceProcessor.
@@ -65,6 +72,7 @@ type Deps struct {
 	PodManager   podmanager.API
 	IPAM         ipam.API
 	IPNet        ipnet.API
+	HealthCheck  healthcheck.API /* optional, serves health-check node ports */
 }
 
 // LocalEndpoint represents a node-local endpoint.
@@ -92,11 +100,14 @@ func (sp *ServiceProcessor) reset() error {
 	sp.epRedirects = make(map[string]string)
 	sp.frontendIfs = renderer.NewInterfaces()
 	sp.backendIfs = renderer.NewInterfaces()
+	sp.egressGws = make(map[string]*egressgwmodel.EgressGateway)
+	sp.renderedEgressGws = nil
 	return nil
 }
 
 // Update is called for:
-//  - KubeStateChange for service-related data
+//  - KubeStateChange for service-related data (including pod weight annotations
+//    and egress gateways)
 //  - AddPod & DeletePod
 //  - NodeUpdate event
 func (sp *ServiceProcessor) Update(event controller.Event) error {
@@ -105,14 +116,29 @@ func (sp *ServiceProcessor) Update(event controller.Event) error {
 	}
 
 	if addPod, isAddPod := event.(*podmanager.AddPod); isAddPod {
-		return sp.ProcessNewPod(addPod.Pod.Namespace, addPod.Pod.Name)
+		if err := sp.ProcessNewPod(addPod.Pod.Namespace, addPod.Pod.Name); err != nil {
+			return err
+		}
+		return sp.renderEgressGateways()
 	}
 	if deletePod, isDeletePod := event.(*podmanager.DeletePod); isDeletePod {
-		return sp.ProcessDeletingPod(deletePod.Pod.Namespace, deletePod.Pod.Name)
+		if err := sp.ProcessDeletingPod(deletePod.Pod.Namespace, deletePod.Pod.Name); err != nil {
+			return err
+		}
+		return sp.renderEgressGateways()
 	}
 
-	if _, isNodeUpdate := event.(*nodesync.NodeUpdate); isNodeUpdate {
-		return sp.renderNodePorts()
+	if nodeUpdate, isNodeUpdate := event.(*nodesync.NodeUpdate); isNodeUpdate {
+		if err := sp.renderNodePorts(); err != nil {
+			return err
+		}
+		if err := sp.renderEgressGateways(); err != nil {
+			return err
+		}
+		if nodeUpdate.PrevState.Zone() != nodeUpdate.NewState.Zone() {
+			return sp.processTopologyChange()
+		}
+		return nil
 	}
 
 	return nil
@@ -410,6 +436,48 @@ func (sp *ServiceProcessor) processDeletedService(serviceID svcmodel.ID) error {
 	return sp.renderService(svc, oldContivSvc, oldBackends)
 }
 
+// processUpdatedBackendWeight re-renders services with weighted load-balancing
+// backed by the given pod after its weight has changed.
+func (sp *ServiceProcessor) processUpdatedBackendWeight(podID podmodel.ID) error {
+	sp.Log.WithFields(logging.Fields{
+		"pod": podID,
+	}).Debug("ServiceProcessor - processUpdatedBackendWeight()")
+
+	var wasErr error
+	for _, svc := range sp.services {
+		oldContivSvc := svc.GetContivService()
+		if oldContivSvc == nil || oldContivSvc.LBMode == renderer.RandomLB || !svc.HasBackendPod(podID) {
+			continue
+		}
+		oldBackends := svc.GetLocalBackends()
+		svc.SetEndpoints(svc.endpoints) // refresh with the new weight
+		if err := sp.renderService(svc, oldContivSvc, oldBackends); err != nil {
+			wasErr = err
+		}
+	}
+	return wasErr
+}
+
+// processTopologyChange re-renders topology-aware services after a node has changed
+// its zone (or has joined/left the cluster).
+func (sp *ServiceProcessor) processTopologyChange() error {
+	sp.Log.Debug("ServiceProcessor - processTopologyChange()")
+
+	var wasErr error
+	for _, svc := range sp.services {
+		oldContivSvc := svc.GetContivService()
+		if oldContivSvc == nil || !oldContivSvc.TopologyAware {
+			continue
+		}
+		oldBackends := svc.GetLocalBackends()
+		svc.SetEndpoints(svc.endpoints) // refresh backend tiers
+		if err := sp.renderService(svc, oldContivSvc, oldBackends); err != nil {
+			wasErr = err
+		}
+	}
+	return wasErr
+}
+
 // renderService reacts to added/removed/changed service.
 func (sp *ServiceProcessor) renderService(svc *Service, oldContivSvc *renderer.ContivService,
 	oldBackends []podmodel.ID) error {
@@ -494,7 +562,29 @@ func (sp *ServiceProcessor) renderService(svc *Service, oldContivSvc *renderer.C
 		sp.backendIfs = newBackendIfs
 	}
 
-	return err
+	// Update health-check node ports.
+	return sp.syncHealthChecks()
+}
+
+// syncHealthChecks updates the health-check server with the current set
+// of services with node-local traffic policy and a health-check node port,
+// together with the number of their local endpoints.
+func (sp *ServiceProcessor) syncHealthChecks() error {
+	if sp.HealthCheck == nil {
+		return nil
+	}
+	services := make(map[svcmodel.ID]*healthcheck.ServiceHealth)
+	for svcID, svc := range sp.services {
+		contivSvc := svc.GetContivService()
+		if contivSvc == nil || contivSvc.HealthCheckNodePort == 0 {
+			continue
+		}
+		services[svcID] = &healthcheck.ServiceHealth{
+			NodePort:       contivSvc.HealthCheckNodePort,
+			LocalEndpoints: svc.GetLocalEndpointCount(),
+		}
+	}
+	return sp.HealthCheck.SyncServices(services)
 }
 
 // otherContivServices retrieves all existing ContivService-s except the one given as parameter <exceludeService>
@@ -639,15 +729,43 @@ func (sp *ServiceProcessor) processResyncEvent(resyncEv *ResyncEventData) error
 		}
 	}
 
+	// Build egress gateways.
+	for _, egressGw := range resyncEv.EgressGateways {
+		sp.egressGws[egressGw.Name] = egressGw
+	}
+	sp.renderedEgressGws = sp.buildEgressGateways()
+
 	// Build resync data for service renderers.
 	confResyncEv.FrontendIfs = sp.frontendIfs
 	confResyncEv.BackendIfs = sp.backendIfs
+	confResyncEv.EgressGateways = sp.renderedEgressGws
 	for _, renderer := range sp.renderers {
 		if err := renderer.Resync(confResyncEv); err != nil {
 			return err
 		}
 	}
-	return nil
+	return sp.syncHealthChecks()
+}
+
+// GetContivServices returns all services with enough data to be rendered,
+// as passed to the renderers (sorted by service ID).
+func (sp *ServiceProcessor) GetContivServices() []*renderer.ContivService {
+	var services []*renderer.ContivService
+	for _, service := range sp.services {
+		if contivSvc := service.GetContivService(); contivSvc != nil {
+			services = append(services, contivSvc)
+		}
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].ID.String() < services[j].ID.String()
+	})
+	return services
+}
+
+// GetLocalInterfaces returns the current sets of local frontend and backend
+// interfaces.
+func (sp *ServiceProcessor) GetLocalInterfaces() (frontendIfs, backendIfs renderer.Interfaces) {
+	return sp.frontendIfs.Copy(), sp.backendIfs.Copy()
 }
 
 // Close deallocates resource held by the processor.
@@ -18,6 +18,8 @@ package processor
 
 import (
 	"net"
+	"strconv"
+	"strings"
 
 	"go.ligato.io/cn-infra/v2/logging"
 
@@ -28,6 +30,39 @@ import (
 	"github.com/contiv/vpp/plugins/service/renderer"
 )
 
+const (
+	contivAnnotationPrefix = "contivpp.io/"
+
+	// lbModeAnnotation is a k8s service annotation used to select the load-balancing
+	// mode of the service ("random" (default), "weighted" or "consistent-hash").
+	lbModeAnnotation = contivAnnotationPrefix + "lb-mode"
+
+	// topologyAwareAnnotation is a k8s service annotation used to enable topology-aware
+	// routing for the service ("true" to prefer same-node, then same-zone backends).
+	topologyAwareAnnotation = contivAnnotationPrefix + "topology-aware"
+
+	// sessionAffinityTimeoutAnnotation is a k8s service annotation used to enable
+	// client IP based session affinity with the given timeout (in seconds), either
+	// for all ports ("600") or per service port ("http=600,diameter=3600").
+	sessionAffinityTimeoutAnnotation = contivAnnotationPrefix + "session-affinity-timeout"
+
+	// sessionAffinityPrefixAnnotation is a k8s service annotation used to key
+	// the session affinity on the client IP prefix of the given length instead
+	// of the full client address, either for all ports ("24") or per service
+	// port ("http=24").
+	sessionAffinityPrefixAnnotation = contivAnnotationPrefix + "session-affinity-prefix-length"
+
+	// maxSessionAffinityTimeout is the maximum session affinity timeout (as in k8s).
+	maxSessionAffinityTimeout = 86400
+
+	// maxAffinityPrefixLength is the maximum length of the client IP prefix.
+	maxAffinityPrefixLength = 128
+
+	// backendWeightAnnotation is a k8s pod annotation used to set the load-balancing
+	// weight of the pod for all services that it backs.
+	backendWeightAnnotation = contivAnnotationPrefix + "service-weight"
+)
+
 // Service is used to combine data from the service model with the endpoints.
 type Service struct {
 	sp            *ServiceProcessor
@@ -35,6 +70,7 @@ type Service struct {
 	endpoints     *epmodel.Endpoints
 	contivSvc     *renderer.ContivService
 	localBackends []podmodel.ID
+	localEpCount  int
 	refreshed     bool
 }
 
@@ -76,12 +112,37 @@ func (s *Service) GetLocalBackends() []podmodel.ID {
 	return s.localBackends
 }
 
+// GetLocalEndpointCount returns the number of endpoint addresses of this service
+// deployed on this node (including host-network endpoints).
+// Returns zero if there are not enough available data.
+func (s *Service) GetLocalEndpointCount() int {
+	if !s.refreshed {
+		s.Refresh()
+	}
+	return s.localEpCount
+}
+
+// HasBackendPod returns true if the given pod is one of the endpoints of this service.
+func (s *Service) HasBackendPod(podID podmodel.ID) bool {
+	for _, epSubSet := range s.endpoints.GetEndpointSubsets() {
+		for _, epAddr := range epSubSet.GetAddresses() {
+			targetRef := epAddr.GetTargetRef()
+			if targetRef.GetKind() == "Pod" &&
+				targetRef.GetName() == podID.Name && targetRef.GetNamespace() == podID.Namespace {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // Refresh combines metadata with endpoints to get ContivService representation
 // and the list of local backends.
 func (s *Service) Refresh() {
 	if s.meta == nil || s.endpoints == nil {
 		s.contivSvc = nil
 		s.localBackends = []podmodel.ID{}
+		s.localEpCount = 0
 		s.refreshed = true
 		return
 	}
@@ -92,6 +153,7 @@ func (s *Service) Refresh() {
 	s.contivSvc.ID = svcmodel.GetID(s.meta)
 	if s.meta.ExternalTrafficPolicy == "Local" {
 		s.contivSvc.TrafficPolicy = renderer.NodeLocal
+		s.contivSvc.HealthCheckNodePort = uint16(s.meta.HealthCheckNodePort)
 	} else {
 		s.contivSvc.TrafficPolicy = renderer.ClusterWide
 	}
@@ -100,6 +162,27 @@ func (s *Service) Refresh() {
 		s.contivSvc.SessionAffinityTimeout = s.meta.SessionAffinityTimeout
 	}
 
+	if lbMode, hasLBMode := s.meta.Annotations[lbModeAnnotation]; hasLBMode {
+		switch strings.ToLower(strings.TrimSpace(lbMode)) {
+		case "random", "":
+			s.contivSvc.LBMode = renderer.RandomLB
+		case "weighted":
+			s.contivSvc.LBMode = renderer.WeightedLB
+		case "consistent-hash":
+			s.contivSvc.LBMode = renderer.ConsistentHashLB
+		default:
+			s.sp.Log.WithFields(logging.Fields{
+				"service": s.contivSvc.ID,
+				"lbMode":  lbMode,
+			}).Warn("Unsupported load-balancing mode, using random")
+		}
+	}
+
+	if topologyAware, err := strconv.ParseBool(s.meta.Annotations[topologyAwareAnnotation]); err == nil {
+		s.contivSvc.TopologyAware = topologyAware
+	}
+	thisZone := s.sp.NodeSync.GetAllNodes()[s.sp.ServiceLabel.GetAgentLabel()].Zone()
+
 	// Collect all IP addresses on which the service should be exposed.
 	if s.meta.ClusterIp != "" && s.meta.ClusterIp != "None" {
 		clusterIP := net.ParseIP(s.meta.ClusterIp)
@@ -130,6 +213,7 @@ func (s *Service) Refresh() {
 			lbIngressIP := net.ParseIP(lbIngressIPStr)
 			if lbIngressIP != nil {
 				s.contivSvc.ExternalIPs.Add(lbIngressIP)
+				s.contivSvc.LBIngressIPs.Add(lbIngressIP)
 			} else {
 				s.sp.Log.WithFields(logging.Fields{
 					"service":     s.contivSvc.ID,
@@ -137,18 +221,47 @@ func (s *Service) Refresh() {
 				}).Warn("Failed to parse LB Ingress IP")
 			}
 		}
+		for _, sourceRangeStr := range s.meta.LoadbalancerSourceRanges {
+			_, sourceRange, err := net.ParseCIDR(strings.TrimSpace(sourceRangeStr))
+			if err == nil {
+				s.contivSvc.LBSourceRanges = append(s.contivSvc.LBSourceRanges, sourceRange)
+			} else {
+				s.sp.Log.WithFields(logging.Fields{
+					"service":       s.contivSvc.ID,
+					"LBSourceRange": sourceRangeStr,
+				}).Warn("Failed to parse LB source range")
+			}
+		}
 	}
 
 	// Fill up the map of service ports.
+	affinityTimeouts := s.perPortAnnotation(sessionAffinityTimeoutAnnotation, maxSessionAffinityTimeout)
+	affinityPrefixes := s.perPortAnnotation(sessionAffinityPrefixAnnotation, maxAffinityPrefixLength)
 	for _, port := range s.meta.Port {
 		sp := &renderer.ServicePort{
 			Port:     uint16(port.GetPort()),
 			NodePort: uint16(port.GetNodePort()),
 		}
-		if port.GetProtocol() == "TCP" {
+		if timeout, hasTimeout := perPortValue(affinityTimeouts, port.GetName()); hasTimeout {
+			sp.SessionAffinityTimeout = uint32(timeout)
+		}
+		if prefixLen, hasPrefix := perPortValue(affinityPrefixes, port.GetName()); hasPrefix {
+			sp.AffinityPrefixLength = uint8(prefixLen)
+		}
+		switch port.GetProtocol() {
+		case "TCP", "": // TCP is the default in k8s
 			sp.Protocol = renderer.TCP
-		} else {
+		case "UDP":
 			sp.Protocol = renderer.UDP
+		case "SCTP":
+			sp.Protocol = renderer.SCTP
+		default:
+			s.sp.Log.WithFields(logging.Fields{
+				"service":  s.contivSvc.ID,
+				"port":     port.GetName(),
+				"protocol": port.GetProtocol(),
+			}).Warn("Unsupported service port protocol")
+			continue
 		}
 		s.contivSvc.Ports[port.Name] = sp
 	}
@@ -157,6 +270,7 @@ func (s *Service) Refresh() {
 	for port := range s.contivSvc.Ports {
 		s.contivSvc.Backends[port] = []*renderer.ServiceBackend{}
 	}
+	localEpIPs := make(map[string]struct{})
 	for _, epSubSet := range s.endpoints.GetEndpointSubsets() {
 		epPorts := epSubSet.GetPorts()
 		epAddrs := epSubSet.GetAddresses()
@@ -184,6 +298,19 @@ func (s *Service) Refresh() {
 				}
 			}
 
+			tier := renderer.ClusterTier
+			if local {
+				tier = renderer.NodeTier
+			} else if thisZone != "" &&
+				s.sp.NodeSync.GetAllNodes()[epAddr.GetNodeName()].Zone() == thisZone {
+				tier = renderer.ZoneTier
+			}
+
+			var weight uint32
+			if s.contivSvc.LBMode != renderer.RandomLB {
+				weight = s.getBackendWeight(epAddr.GetTargetRef())
+			}
+
 			for _, epPort := range epPorts {
 				port := epPort.GetName()
 				if _, exposedPort := s.contivSvc.Ports[port]; exposedPort {
@@ -192,10 +319,13 @@ func (s *Service) Refresh() {
 					sb.Port = uint16(epPort.GetPort())
 					sb.Local = local
 					sb.HostNetwork = hostNetwork
+					sb.Weight = weight
+					sb.Tier = tier
 					s.contivSvc.Backends[port] = append(s.contivSvc.Backends[port], sb)
 				}
 			}
 			if local {
+				localEpIPs[epIP.String()] = struct{}{}
 				// Get target pod and add it to the set of local backends.
 				targetPod := epAddr.GetTargetRef()
 				if targetPod.GetKind() == "Pod" {
@@ -205,10 +335,95 @@ func (s *Service) Refresh() {
 			}
 		}
 	}
+	s.localEpCount = len(localEpIPs)
+
+	if s.contivSvc.LBMode == renderer.ConsistentHashLB {
+		for port, backends := range s.contivSvc.Backends {
+			if len(backends) > renderer.ConsistentHashTableSize {
+				// backends beyond the table size would receive no traffic
+				s.sp.Log.WithFields(logging.Fields{
+					"service":  s.contivSvc.ID,
+					"port":     port,
+					"backends": len(backends),
+				}).Warnf("Too many backends for consistent-hash load-balancing (max %d), using weighted",
+					renderer.ConsistentHashTableSize)
+				s.contivSvc.LBMode = renderer.WeightedLB
+				break
+			}
+		}
+	}
 
 	s.refreshed = true
 }
 
+// getBackendWeight returns the load-balancing weight configured for the given
+// target of an endpoint address (0 if not set).
+func (s *Service) getBackendWeight(targetRef *epmodel.ObjectReference) uint32 {
+	if targetRef.GetKind() != "Pod" || s.sp.PodManager == nil {
+		return 0
+	}
+	podID := podmodel.ID{Name: targetRef.GetName(), Namespace: targetRef.GetNamespace()}
+	pod, hasPod := s.sp.PodManager.GetPods()[podID]
+	if !hasPod {
+		return 0
+	}
+	weightStr, hasWeight := pod.Annotations[backendWeightAnnotation]
+	if !hasWeight {
+		return 0
+	}
+	weight, err := strconv.ParseUint(strings.TrimSpace(weightStr), 10, 32)
+	if err != nil || weight == 0 || uint32(weight) > renderer.MaxBackendWeight {
+		s.sp.Log.WithFields(logging.Fields{
+			"service": s.contivSvc.ID,
+			"pod":     podID,
+			"weight":  weightStr,
+		}).Warnf("Invalid backend weight (expected integer from 1 to %d)", renderer.MaxBackendWeight)
+		return 0
+	}
+	return uint32(weight)
+}
+
+// perPortAnnotation parses value of the given service annotation, which is either
+// a single value applied to all service ports, or a comma-separated list
+// of <port-name>=<value> pairs. Values must be integers from 1 to <max>,
+// invalid values are logged and skipped. The value for all ports is returned
+// under the empty port name.
+func (s *Service) perPortAnnotation(annotation string, max uint64) map[string]uint64 {
+	annotationStr, hasAnnotation := s.meta.Annotations[annotation]
+	if !hasAnnotation {
+		return nil
+	}
+	values := make(map[string]uint64)
+	for _, item := range strings.Split(annotationStr, ",") {
+		var portName string
+		valueStr := item
+		if idx := strings.Index(item, "="); idx >= 0 {
+			portName = strings.TrimSpace(item[:idx])
+			valueStr = item[idx+1:]
+		}
+		value, err := strconv.ParseUint(strings.TrimSpace(valueStr), 10, 32)
+		if err != nil || value == 0 || value > max {
+			s.sp.Log.WithFields(logging.Fields{
+				"service":    s.contivSvc.ID,
+				"annotation": annotation,
+				"value":      item,
+			}).Warnf("Invalid annotation value (expected integer from 1 to %d)", max)
+			continue
+		}
+		values[portName] = value
+	}
+	return values
+}
+
+// perPortValue returns value of a per-port annotation for the given port.
+func perPortValue(values map[string]uint64, portName string) (value uint64, hasValue bool) {
+	if value, hasValue = values[portName]; hasValue {
+		return value, true
+	}
+	value, hasValue = values[""]
+	return value, hasValue
+}
+
 // isLocalNodeOrHostIP returns true if the given IP is current node's node (VPP) or host (mgmt) IP, false otherwise.
 func (s *Service) isLocalNodeOrHostIP(ip net.IP) bool {
 	nodeIP, _ := s.sp.IPNet.GetNodeIP()
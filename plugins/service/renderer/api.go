@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net"
 
+	controller "github.com/contiv/vpp/plugins/controller/api"
 	svcmodel "github.com/contiv/vpp/plugins/ksr/model/service"
 )
 
@@ -106,6 +107,16 @@ type ServiceRendererAPI interface {
 	Resync(resyncEv *ResyncEventData) error
 }
 
+// RenderedConfigAPI is an optional interface of Service Renderer, implemented
+// by renderers able to describe the configuration rendered for a service.
+// It is used only for inspection (e.g. by netctl) - the returned configuration
+// is not applied.
+type RenderedConfigAPI interface {
+	// GetServiceConfig returns the configuration of the destination network
+	// stack rendered for the given service (on this node).
+	GetServiceConfig(service *ContivService) controller.KeyValuePairs
+}
+
 // ContivService is a less-abstract, free of indirect references representation
 // of K8s Service.
 // It has:
@@ -119,8 +130,20 @@ type ContivService struct {
 	// TrafficPolicy decides if traffic is routed cluster-wide or node-local only.
 	TrafficPolicy TrafficPolicyType
 
+	// HealthCheckNodePort is the node port on which external load-balancers can
+	// check if the service has endpoints deployed on the node
+	// (only with node-local traffic policy, 0 if none).
+	HealthCheckNodePort uint16
+
+	// LBMode selects how the traffic is distributed across the backends.
+	LBMode LBModeType
+
+	// TopologyAware enables topology-aware routing - traffic is routed only
+	// to the nearest non-empty tier of backends (see ServiceBackend.Tier).
+	TopologyAware bool
+
 	// SessionAffinityTimeout max session sticky time (in seconds) if client IP based session affinity
-	// is enabled, 0 if disabled
+	// is enabled, 0 if disabled (can be overridden per port - see ServicePort)
 	SessionAffinityTimeout uint32
 
 	// ClusterIPs is a set of all IP addresses on which the service
@@ -133,6 +156,14 @@ type ContivService struct {
 	// should be exposed outside of the cluster (e.g. external load-balancer IPs).
 	ExternalIPs *IPAddresses
 
+	// LBIngressIPs is a subset of ExternalIPs, containing only IP addresses
+	// assigned to the service by the external load-balancer.
+	LBIngressIPs *IPAddresses
+
+	// LBSourceRanges, if not empty, restricts access to the service via
+	// the LB ingress IPs and node ports to clients from the listed IP prefixes.
+	LBSourceRanges []*net.IPNet
+
 	// Ports is a map of all ports exposed for this service.
 	Ports map[string] /* service port name */ *ServicePort
 
@@ -154,10 +185,11 @@ const (
 // NewContivService is a constructor for ContivService.
 func NewContivService() *ContivService {
 	return &ContivService{
-		ClusterIPs:  NewIPAddresses(),
-		ExternalIPs: NewIPAddresses(),
-		Ports:       make(map[string]*ServicePort),
-		Backends:    make(map[string][]*ServiceBackend),
+		ClusterIPs:   NewIPAddresses(),
+		ExternalIPs:  NewIPAddresses(),
+		LBIngressIPs: NewIPAddresses(),
+		Ports:        make(map[string]*ServicePort),
+		Backends:     make(map[string][]*ServiceBackend),
 	}
 }
 
@@ -193,8 +225,15 @@ func (cs ContivService) String() string {
 		}
 		idx++
 	}
-	return fmt.Sprintf("ContivService %s <Traffic-Policy:%s ClusterIPs:[%s] ExternalIPs:[%s] Backends:{%s}>",
-		cs.ID.String(), cs.TrafficPolicy.String(), clusterIPs, externalIPs, allBackends)
+	lbSourceRanges := ""
+	for idx, ipNet := range cs.LBSourceRanges {
+		lbSourceRanges += ipNet.String()
+		if idx < len(cs.LBSourceRanges)-1 {
+			lbSourceRanges += ", "
+		}
+	}
+	return fmt.Sprintf("ContivService %s <Traffic-Policy:%s LB-Mode:%s ClusterIPs:[%s] ExternalIPs:[%s] LBSourceRanges:[%s] Backends:{%s}>",
+		cs.ID.String(), cs.TrafficPolicy.String(), cs.LBMode.String(), clusterIPs, externalIPs, lbSourceRanges, allBackends)
 }
 
 // String converts TrafficPolicyType into a human-readable string.
@@ -218,11 +257,43 @@ func (cs ContivService) HasNodePort() bool {
 	return false
 }
 
+// HasLBSourceRanges returns true if access to the service via LB ingress IPs
+// and node ports is restricted to a set of client IP prefixes.
+func (cs ContivService) HasLBSourceRanges() bool {
+	return len(cs.LBSourceRanges) > 0
+}
+
 // ServicePort contains information on service's port.
 type ServicePort struct {
 	Protocol ProtocolType /* protocol type */
 	Port     uint16       /* port that will be exposed by this service */
 	NodePort uint16       /* port on which this service is exposed for Node IP (0 if none) */
+
+	// SessionAffinityTimeout overrides the session affinity timeout of the service
+	// for this port (0 to use ContivService.SessionAffinityTimeout).
+	SessionAffinityTimeout uint32
+
+	// AffinityPrefixLength is the length of the client IP prefix on which
+	// the session affinity is keyed (0 for the full client address).
+	AffinityPrefixLength uint8
+}
+
+// PortSessionAffinity returns the session affinity of the given service port:
+// the max session sticky time (in seconds, 0 if disabled) and the length of the
+// client IP prefix on which the affinity is keyed (0 for the full client address).
+func (cs ContivService) PortSessionAffinity(portName string) (timeout uint32, prefixLen uint8) {
+	timeout = cs.SessionAffinityTimeout
+	port, hasPort := cs.Ports[portName]
+	if !hasPort {
+		return timeout, 0
+	}
+	if port.SessionAffinityTimeout != 0 {
+		timeout = port.SessionAffinityTimeout
+	}
+	if timeout == 0 {
+		return 0, 0
+	}
+	return timeout, port.AffinityPrefixLength
 }
 
 // String converts ServicePort into a human-readable string.
@@ -233,7 +304,7 @@ func (sp ServicePort) String() string {
 	return fmt.Sprintf("%d:%d/%s", sp.Port, sp.NodePort, sp.Protocol.String())
 }
 
-// ProtocolType is either TCP or UDP.
+// ProtocolType is either TCP, UDP or SCTP.
 type ProtocolType int
 
 const (
@@ -242,6 +313,9 @@ const (
 
 	// UDP protocol.
 	UDP ProtocolType = 17
+
+	// SCTP protocol.
+	SCTP ProtocolType = 132
 )
 
 // String converts ProtocolType into a human-readable string.
@@ -251,23 +325,42 @@ func (pt ProtocolType) String() string {
 		return "TCP"
 	case UDP:
 		return "UDP"
+	case SCTP:
+		return "SCTP"
 	}
 	return "INVALID"
 }
 
 // ServiceBackend represents a single service backend (= endpoint).
 type ServiceBackend struct {
-	IP          net.IP /* internal IP address of the backend */
-	Port        uint16 /* backend-local port on which the service listens */
-	Local       bool   /* true if the backend is deployed on this node (can be leveraged for smart load-balancing) */
-	HostNetwork bool   /* true if the backend uses host networking */
+	IP          net.IP       /* internal IP address of the backend */
+	Port        uint16       /* backend-local port on which the service listens */
+	Local       bool         /* true if the backend is deployed on this node (can be leveraged for smart load-balancing) */
+	HostNetwork bool         /* true if the backend uses host networking */
+	Weight      uint32       /* relative load-balancing weight of the backend (0 = default) */
+	Tier        TopologyTier /* topological distance of the backend from this node */
 }
 
 // String converts Backend into a human-readable string.
 func (sb ServiceBackend) String() string {
+	if sb.Weight != 0 {
+		return fmt.Sprintf("<IP:%s Port:%d, Local:%t, Weight:%d>", sb.IP, sb.Port, sb.Local, sb.Weight)
+	}
 	return fmt.Sprintf("<IP:%s Port:%d, Local:%t>", sb.IP, sb.Port, sb.Local)
 }
 
+// GetWeight returns the load-balancing weight of the backend, with the default
+// weight used if none was configured and the maximum weight enforced.
+func (sb ServiceBackend) GetWeight() uint32 {
+	if sb.Weight == 0 {
+		return DefaultBackendWeight
+	}
+	if sb.Weight > MaxBackendWeight {
+		return MaxBackendWeight
+	}
+	return sb.Weight
+}
+
 // IPAddresses is a set of IP addresses.
 type IPAddresses struct {
 	list []net.IP
@@ -408,6 +501,10 @@ type ResyncEventData struct {
 	// BackendIfs is a set of all interfaces connecting service backends with VPP
 	// (VPP specific).
 	BackendIfs Interfaces
+
+	// EgressGateways is a list of all egress gateways (for renderers implementing
+	// EgressGatewayRendererAPI).
+	EgressGateways []*EgressGateway
 }
 
 // NewResyncEventData is a constructor for ResyncEventData.
@@ -429,6 +526,13 @@ func (red ResyncEventData) String() string {
 			services += ", "
 		}
 	}
-	return fmt.Sprintf("ResyncEventData <NodeIPs:[%s] Services:[%s], FrontendIfs:%s BackendIfs:%s>",
-		red.NodeIPs.String(), services, red.FrontendIfs.String(), red.BackendIfs.String())
+	egressGws := ""
+	for idx, egressGw := range red.EgressGateways {
+		egressGws += egressGw.String()
+		if idx < len(red.EgressGateways)-1 {
+			egressGws += ", "
+		}
+	}
+	return fmt.Sprintf("ResyncEventData <NodeIPs:[%s] Services:[%s], FrontendIfs:%s BackendIfs:%s EgressGateways:[%s]>",
+		red.NodeIPs.String(), services, red.FrontendIfs.String(), red.BackendIfs.String(), egressGws)
 }
@@ -0,0 +1,95 @@
+/*
+ * // Copyright (c) 2019 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+package renderer
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
+)
+
+// EgressGatewayRendererAPI is an optional extension of ServiceRendererAPI,
+// implemented by renderers able to pin the source IP of the traffic leaving
+// the cluster from selected pods (see EgressGateway CRD).
+type EgressGatewayRendererAPI interface {
+	// UpdateEgressGateways is called with the complete list of egress gateways
+	// whenever any of them changes (incl. the set of selected pods).
+	UpdateEgressGateways(gateways []*EgressGateway) error
+}
+
+// EgressGateway is a processed representation of the EgressGateway CRD.
+type EgressGateway struct {
+	// Name of the egress gateway.
+	Name string
+
+	// NodeID is the ID of the node through which the egress traffic leaves
+	// the cluster (0 if the node is not known (yet)).
+	NodeID uint32
+
+	// Local is true if this node is the gateway node.
+	Local bool
+
+	// GatewayIP is the IP address of the gateway node to which the egress
+	// traffic of the selected pods is forwarded from other nodes.
+	GatewayIP net.IP
+
+	// EgressIPs is the pool of (sorted) IP addresses shared by the selected pods
+	// as the source of their egress traffic.
+	EgressIPs []net.IP
+
+	// Pods selected for this gateway.
+	Pods []*EgressGatewayPod
+}
+
+// EgressGatewayPod is a pod whose egress traffic is SNATed to the pool
+// of egress IPs of the gateway.
+type EgressGatewayPod struct {
+	// ID of the pod.
+	ID podmodel.ID
+
+	// IP address of the pod.
+	IP net.IP
+
+	// Local is true if the pod is deployed on this node.
+	Local bool
+
+	// IfName is the name of the VPP interface connecting the pod
+	// (only for local pods).
+	IfName string
+}
+
+// String converts EgressGateway into a human-readable string.
+func (eg *EgressGateway) String() string {
+	pods := []string{}
+	for _, pod := range eg.Pods {
+		pods = append(pods, pod.String())
+	}
+	egressIPs := []string{}
+	for _, egressIP := range eg.EgressIPs {
+		egressIPs = append(egressIPs, egressIP.String())
+	}
+	return fmt.Sprintf("EgressGateway %s <nodeID:%d local:%t gatewayIP:%s egressIPs:[%s] pods:[%s]>",
+		eg.Name, eg.NodeID, eg.Local, eg.GatewayIP, strings.Join(egressIPs, ", "), strings.Join(pods, ", "))
+}
+
+// String converts EgressGatewayPod into a human-readable string.
+func (egp *EgressGatewayPod) String() string {
+	return fmt.Sprintf("<%s ip:%s local:%t ifName:%s>",
+		egp.ID.String(), egp.IP, egp.Local, egp.IfName)
+}
@@ -199,6 +199,19 @@ func (rndr *Renderer) Resync(resyncEv *renderer.ResyncEventData) error {
 	return nil
 }
 
+// GetServiceConfig returns routes and the supporting configuration rendered
+// for the given service.
+func (rndr *Renderer) GetServiceConfig(service *renderer.ContivService) controller.KeyValuePairs {
+	if rndr.snatOnly {
+		return nil
+	}
+	addDelConfig, updateConfig := rndr.renderService(service, serviceAdd)
+	for key, value := range updateConfig {
+		addDelConfig[key] = value
+	}
+	return addDelConfig
+}
+
 // Close deallocates resources held by the renderer.
 func (rndr *Renderer) Close() error {
 	return nil
@@ -509,8 +522,11 @@ func (rndr *Renderer) getPodPFRuleChain(
 // getServicePortForwardRule returns iptables port forward rule for specified service IP and port forward data.
 func (rndr *Renderer) getServicePortForwardRule(serviceIP net.IP, pf *portForward) string {
 	proto := "tcp"
-	if pf.proto == renderer.UDP {
+	switch pf.proto {
+	case renderer.UDP:
 		proto = "udp"
+	case renderer.SCTP:
+		proto = "sctp"
 	}
 	return fmt.Sprintf("-d %s -p %s -m %s --dport %d -j REDIRECT --to-ports %d",
 		serviceIP.String()+ipv6HostPrefix, proto, proto, pf.from, pf.to)
@@ -0,0 +1,157 @@
+/*
+ * // Copyright (c) 2018 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+package renderer
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// LBModeType selects the method used to distribute service traffic
+// across the service backends.
+type LBModeType int
+
+const (
+	// RandomLB distributes traffic randomly with equal share for every backend
+	// (local backends may be preferred - see ServiceLocalEndpointWeight).
+	RandomLB LBModeType = 0
+
+	// WeightedLB distributes traffic randomly, proportionally to the weights
+	// of the backends.
+	WeightedLB LBModeType = 1
+
+	// ConsistentHashLB hashes flows into a lookup table populated from the (weighted)
+	// set of backends following the permutations of the Maglev algorithm.
+	// A change in the set of backends re-assigns only a small part of the table
+	// entries, flows hashed into the other entries stay with their backend.
+	// Renderers unable to hash flows into the lookup table fall back to WeightedLB.
+	ConsistentHashLB LBModeType = 2
+)
+
+const (
+	// DefaultBackendWeight is the weight of backends without weight configured.
+	DefaultBackendWeight uint32 = 1
+
+	// MaxBackendWeight is the maximum supported weight of a backend
+	// (VPP limits load-balancing probabilities to 8 bits).
+	MaxBackendWeight uint32 = 255
+
+	// ConsistentHashTableSize is the size of the lookup table used by ConsistentHashLB
+	// (must be prime). It is also the maximum number of backends supported
+	// by ConsistentHashLB.
+	ConsistentHashTableSize = 251
+)
+
+// String converts LBModeType into a human-readable string.
+func (lbm LBModeType) String() string {
+	switch lbm {
+	case RandomLB:
+		return "random"
+	case WeightedLB:
+		return "weighted"
+	case ConsistentHashLB:
+		return "consistent-hash"
+	}
+	return "INVALID"
+}
+
+// BackendWeights returns the load-balancing weight for each of the given backends
+// (in the same order) as requested by the given LB mode.
+// For RandomLB, all backends get the default weight. ConsistentHashLB is weighted
+// with the weights of the backends (for renderers without the lookup table).
+func BackendWeights(lbMode LBModeType, backends []*ServiceBackend) []uint32 {
+	weights := make([]uint32, len(backends))
+	switch lbMode {
+	case WeightedLB, ConsistentHashLB:
+		for idx, backend := range backends {
+			weights[idx] = backend.GetWeight()
+		}
+	default:
+		for idx := range backends {
+			weights[idx] = DefaultBackendWeight
+		}
+	}
+	return weights
+}
+
+// ConsistentHashTable builds the lookup table of ConsistentHashLB for the given
+// backends. Every entry of the returned table (of size ConsistentHashTableSize)
+// contains index of the backend (in the input slice) owning the entry.
+// The table depends only on the set of backends (not on their order) - backends
+// are identified by IP address and port. Weighted backends take correspondingly
+// more turns when the table is being populated, which otherwise follows
+// the permutations of the Maglev algorithm. Backends beyond ConsistentHashTableSize
+// may not own any entry. Returns nil for an empty set of backends.
+func ConsistentHashTable(backends []*ServiceBackend) []int {
+	if len(backends) == 0 {
+		return nil
+	}
+
+	// order backends by their identity to make the table deterministic
+	order := make([]int, len(backends))
+	keys := make([]string, len(backends))
+	for idx, backend := range backends {
+		order[idx] = idx
+		keys[idx] = backend.IP.String() + ":" + strconv.Itoa(int(backend.Port))
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return keys[order[i]] < keys[order[j]]
+	})
+
+	// compute permutation parameters of every backend
+	offsets := make([]uint32, len(backends))
+	skips := make([]uint32, len(backends))
+	for _, idx := range order {
+		offsets[idx] = permutationHash(keys[idx], "offset") % ConsistentHashTableSize
+		skips[idx] = permutationHash(keys[idx], "skip")%(ConsistentHashTableSize-1) + 1
+	}
+
+	// populate the lookup table
+	table := make([]int, ConsistentHashTableSize)
+	for entry := range table {
+		table[entry] = -1
+	}
+	next := make([]uint32, len(backends))
+	filled := 0
+	for {
+		for _, idx := range order {
+			for turn := uint32(0); turn < backends[idx].GetWeight(); turn++ {
+				// find the next preferred entry not taken yet
+				entry := (offsets[idx] + next[idx]*skips[idx]) % ConsistentHashTableSize
+				for table[entry] != -1 {
+					next[idx]++
+					entry = (offsets[idx] + next[idx]*skips[idx]) % ConsistentHashTableSize
+				}
+				table[entry] = idx
+				next[idx]++
+				filled++
+				if filled == ConsistentHashTableSize {
+					return table
+				}
+			}
+		}
+	}
+}
+
+// permutationHash returns 32-bit FNV-1a hash of the backend key salted with the given string.
+func permutationHash(key, salt string) uint32 {
+	hash := fnv.New32a()
+	hash.Write([]byte(salt))
+	hash.Write([]byte(key))
+	return hash.Sum32()
+}
@@ -0,0 +1,418 @@
+/*
+ * // Copyright (c) 2019 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+package nat44
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+
+	"go.ligato.io/vpp-agent/v3/pkg/models"
+	vpp_abf "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/abf"
+	vpp_acl "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/acl"
+	vpp_interfaces "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/interfaces"
+	vpp_l3 "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/l3"
+	vpp_nat "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/nat"
+
+	controller "github.com/contiv/vpp/plugins/controller/api"
+	"github.com/contiv/vpp/plugins/ipnet"
+	"github.com/contiv/vpp/plugins/service/renderer"
+)
+
+const (
+	// egressGatewayABFIndexBase + ID of the gateway node = index of the ABF
+	// steering egress traffic of selected local pods towards the gateway node.
+	egressGatewayABFIndexBase = 10000
+
+	// egressGatewayACLPrefix + ID of the gateway node = name of the ACL selecting
+	// traffic for the ABF.
+	egressGatewayACLPrefix = "contiv-egress-gateway-node-"
+
+	// egressGatewayVrfABFIndexBase + VRF of the gateway = index of the ABF steering
+	// egress traffic of the selected pods into the VRF of the gateway (on the gateway node).
+	egressGatewayVrfABFIndexBase = 20000
+
+	// egressGatewayVrfACLPrefix + VRF of the gateway = name of the ACL selecting
+	// traffic for the ABF steering into the VRF of the gateway.
+	egressGatewayVrfACLPrefix = "contiv-egress-gateway-vrf-"
+
+	// egressGatewayNetworkPrefix + gateway name = name under which the VRF
+	// of the gateway is allocated (k8s names cannot contain "_").
+	egressGatewayNetworkPrefix = "_egress-gateway-"
+
+	// egressGatewayLoopPrefix + gateway name = name of the loopback through
+	// which the egress traffic enters the VRF of the gateway.
+	egressGatewayLoopPrefix = "egress-gw-"
+
+	// egressGatewayVrfLabelPrefix + gateway name = label of the VRF of the gateway.
+	egressGatewayVrfLabelPrefix = "VRF-egress-gateway-"
+
+	// addresses of the loopback and of the (fake) next hop behind it - local
+	// to the VRF of the gateway, therefore the same for all gateways
+	egressGatewayLoopIP  = "169.254.254.1/30"
+	egressGatewayNextHop = "169.254.254.2"
+
+	ipv4AddrAny    = "0.0.0.0"
+	ipv4AnyNetwork = "0.0.0.0/0"
+	ipv4HostPrefix = "/32"
+)
+
+// UpdateEgressGateways re-renders the configuration of egress gateways.
+//
+// On the gateway node, every gateway gets its own VRF with a NAT44 address pool
+// of the egress IPs. The egress traffic of the selected pods (local or arriving
+// from other nodes) is steered using ACL-based forwarding into a loopback
+// placed in that VRF, from where it is routed to the main VRF and SNATed
+// by the post-routing NAT on the default interface. VPP-NAT selects the address
+// pool by the VRF in which the traffic was received, i.e. the selected pods share
+// the egress IPs of their gateway (with port overloading), while the traffic
+// of other pods is SNATed to the node IP as usual. No static mapping is installed,
+// the egress IPs therefore do not accept connections from outside.
+// On other nodes, the cluster-outbound traffic of the selected local pods
+// is steered towards the gateway node using ACL-based forwarding.
+func (rndr *Renderer) UpdateEgressGateways(gateways []*renderer.EgressGateway) error {
+	rndr.egressGws = gateways
+	txn := rndr.UpdateTxnFactory("update egress gateways")
+	rndr.updateEgressGatewayConfig(txn)
+	return nil
+}
+
+// updateEgressGatewayConfig adds (re-rendered) configuration of egress gateways
+// into the transaction, and removes configuration that is no longer needed.
+func (rndr *Renderer) updateEgressGatewayConfig(txn controller.UpdateOperations) {
+	config := rndr.renderEgressGateways()
+	obsolete := make(controller.KeyValuePairs)
+	for key, value := range rndr.egressGwConfig {
+		if _, stillUsed := config[key]; !stillUsed {
+			obsolete[key] = value
+		}
+	}
+	controller.PutAll(txn, config)
+	controller.DeleteAll(txn, obsolete)
+	rndr.egressGwConfig = config
+}
+
+// renderEgressGateways renders the configuration of egress gateways.
+// Returns key-value pairs to be installed.
+// VRFs are allocated for gateways deployed on this node and released
+// for gateways which are no longer local.
+func (rndr *Renderer) renderEgressGateways() (config controller.KeyValuePairs) {
+	config = make(controller.KeyValuePairs)
+
+	// NAT pools on the gateway node
+	gatewayVrfs := make(map[string]uint32)
+	usedEgressIPs := make(map[string]string) // egress IP -> gateway
+	for _, gateway := range rndr.egressGws {
+		if !gateway.Local {
+			continue
+		}
+		var egressIPs []net.IP
+		for _, egressIP := range gateway.EgressIPs {
+			if otherGw, used := usedEgressIPs[egressIP.String()]; used {
+				rndr.Log.Warnf("Egress IP %s is used by egress gateways %s and %s, skipping it for %s",
+					egressIP, otherGw, gateway.Name, gateway.Name)
+				continue
+			}
+			usedEgressIPs[egressIP.String()] = gateway.Name
+			egressIPs = append(egressIPs, egressIP)
+		}
+		if len(egressIPs) == 0 {
+			continue
+		}
+		if rndr.defaultIfName == "" {
+			rndr.Log.Warnf("This node is the egress gateway %s, but the dynamic SNAT for cluster-outbound "+
+				"traffic is not enabled - egress IPs will not be applied", gateway.Name)
+		}
+		vrf, err := rndr.IPNet.GetOrAllocateVrfID(egressGatewayNetworkPrefix + gateway.Name)
+		if err != nil {
+			rndr.Log.Warnf("Failed to allocate VRF for the egress gateway %s: %v", gateway.Name, err)
+			continue
+		}
+		gatewayVrfs[gateway.Name] = vrf
+		for key, value := range rndr.renderEgressGatewayVrf(gateway, vrf, egressIPs) {
+			config[key] = value
+		}
+	}
+	for gwName := range rndr.egressGwVrfs {
+		if _, stillLocal := gatewayVrfs[gwName]; !stillLocal {
+			if err := rndr.IPNet.ReleaseVrfID(egressGatewayNetworkPrefix + gwName); err != nil {
+				rndr.Log.Warnf("Failed to release VRF of the egress gateway %s: %v", gwName, err)
+			}
+		}
+	}
+	rndr.egressGwVrfs = gatewayVrfs
+
+	// steering of the egress traffic from other nodes
+	nextHopIf := rndr.egressGatewayInterconnectIf()
+	abfs := make(map[uint32]*vpp_abf.ABF)               // gateway node ID -> ABF
+	acls := make(map[uint32]*vpp_acl.ACL)               // gateway node ID -> ACL
+	attachedIfs := make(map[uint32]map[string]struct{}) // gateway node ID -> pod interfaces
+	for _, gateway := range rndr.egressGws {
+		if gateway.Local || gateway.NodeID == 0 || gateway.GatewayIP == nil || len(gateway.EgressIPs) == 0 {
+			continue
+		}
+		for _, pod := range gateway.Pods {
+			if !pod.Local || pod.IfName == "" {
+				continue
+			}
+			acl, hasACL := acls[gateway.NodeID]
+			if !hasACL {
+				acl = &vpp_acl.ACL{
+					Name:  fmt.Sprintf("%s%d", egressGatewayACLPrefix, gateway.NodeID),
+					Rules: rndr.egressGatewayExcludeRules(),
+				}
+				acls[gateway.NodeID] = acl
+				abfs[gateway.NodeID] = &vpp_abf.ABF{
+					Index:   egressGatewayABFIndexBase + gateway.NodeID,
+					AclName: acl.Name,
+					ForwardingPaths: []*vpp_abf.ABF_ForwardingPath{
+						{
+							NextHopIp:     gateway.GatewayIP.String(),
+							InterfaceName: nextHopIf,
+						},
+					},
+				}
+				attachedIfs[gateway.NodeID] = make(map[string]struct{})
+			}
+			acl.Rules = append(acl.Rules, egressGatewayPodRule(pod))
+			attachedIfs[gateway.NodeID][pod.IfName] = struct{}{}
+		}
+	}
+	for nodeID, abf := range abfs {
+		abf.AttachedInterfaces = sortedABFInterfaces(attachedIfs[nodeID])
+		acl := acls[nodeID]
+		config[vpp_acl.Key(acl.Name)] = acl
+		config[vpp_abf.Key(abf.Index)] = abf
+	}
+	return config
+}
+
+// renderEgressGatewayVrf renders the VRF of an egress gateway deployed on this
+// node, with the NAT44 address pool of the egress IPs and the steering
+// of the egress traffic of the selected pods into the VRF.
+func (rndr *Renderer) renderEgressGatewayVrf(gateway *renderer.EgressGateway, vrf uint32,
+	egressIPs []net.IP) (config controller.KeyValuePairs) {
+
+	config = make(controller.KeyValuePairs)
+	routingCfg := rndr.ContivConf.GetRoutingConfig()
+
+	// VRF with the loopback through which the egress traffic enters the VRF
+	vrfTable := &vpp_l3.VrfTable{
+		Id:       vrf,
+		Protocol: vpp_l3.VrfTable_IPV4,
+		Label:    egressGatewayVrfLabelPrefix + gateway.Name,
+	}
+	config[vpp_l3.VrfTableKey(vrfTable.Id, vrfTable.Protocol)] = vrfTable
+	loop := &vpp_interfaces.Interface{
+		Name:        egressGatewayLoopPrefix + gateway.Name,
+		Type:        vpp_interfaces.Interface_SOFTWARE_LOOPBACK,
+		Enabled:     true,
+		IpAddresses: []string{egressGatewayLoopIP},
+		PhysAddress: egressGatewayLoopMAC(vrf),
+		Vrf:         vrf,
+	}
+	config[vpp_interfaces.InterfaceKey(loop.Name)] = loop
+	// packets sent to the next hop are looped back and received by the loopback
+	arp := &vpp_l3.ARPEntry{
+		Interface:   loop.Name,
+		IpAddress:   egressGatewayNextHop,
+		PhysAddress: loop.PhysAddress,
+		Static:      true,
+	}
+	config[vpp_l3.ArpEntryKey(arp.Interface, arp.IpAddress)] = arp
+
+	// routes towards the outside world (via main VRF) and back to pods (via pod VRF)
+	routes := []*vpp_l3.Route{
+		{
+			Type:        vpp_l3.Route_INTER_VRF,
+			DstNetwork:  ipv4AnyNetwork,
+			VrfId:       vrf,
+			ViaVrfId:    routingCfg.MainVRFID,
+			NextHopAddr: ipv4AddrAny,
+		},
+	}
+	if podSubnet := rndr.IPAM.PodSubnetAllNodes(ipnet.DefaultPodNetworkName); podSubnet != nil {
+		routes = append(routes, &vpp_l3.Route{
+			Type:        vpp_l3.Route_INTER_VRF,
+			DstNetwork:  podSubnet.String(),
+			VrfId:       vrf,
+			ViaVrfId:    routingCfg.PodVRFID,
+			NextHopAddr: ipv4AddrAny,
+		})
+	}
+	for _, route := range routes {
+		config[models.Key(route)] = route
+	}
+
+	// NAT44 address pool - one entry per range of consecutive egress IPs
+	for _, ipRange := range egressIPRanges(egressIPs) {
+		pool := &vpp_nat.Nat44AddressPool{
+			VrfId:   vrf,
+			FirstIp: ipRange[0].String(),
+			LastIp:  ipRange[1].String(),
+		}
+		config[vpp_nat.Nat44AddressPoolKey(pool.VrfId, pool.FirstIp, pool.LastIp)] = pool
+	}
+
+	// steering of the egress traffic of the selected pods into the VRF
+	acl := &vpp_acl.ACL{
+		Name:  fmt.Sprintf("%s%d", egressGatewayVrfACLPrefix, vrf),
+		Rules: rndr.egressGatewayExcludeRules(),
+	}
+	attachedIfs := make(map[string]struct{})
+	for _, pod := range gateway.Pods {
+		if pod.Local && pod.IfName == "" {
+			continue
+		}
+		acl.Rules = append(acl.Rules, egressGatewayPodRule(pod))
+		if pod.Local {
+			attachedIfs[pod.IfName] = struct{}{}
+		} else {
+			// traffic steered here by other nodes
+			attachedIfs[rndr.egressGatewayInterconnectIf()] = struct{}{}
+		}
+	}
+	if len(attachedIfs) > 0 {
+		abf := &vpp_abf.ABF{
+			Index:   egressGatewayVrfABFIndexBase + vrf,
+			AclName: acl.Name,
+			ForwardingPaths: []*vpp_abf.ABF_ForwardingPath{
+				{
+					NextHopIp:     egressGatewayNextHop,
+					InterfaceName: loop.Name,
+				},
+			},
+			AttachedInterfaces: sortedABFInterfaces(attachedIfs),
+		}
+		config[vpp_acl.Key(acl.Name)] = acl
+		config[vpp_abf.Key(abf.Index)] = abf
+	}
+	return config
+}
+
+// egressGatewayInterconnectIf returns the name of the interface through which
+// the egress traffic is exchanged between nodes.
+func (rndr *Renderer) egressGatewayInterconnectIf() string {
+	if vxlanBVI := rndr.IPNet.GetVxlanBVIIfName(); vxlanBVI != "" {
+		return vxlanBVI
+	}
+	return rndr.ContivConf.GetMainInterfaceName()
+}
+
+// egressGatewayPodRule returns ACL rule selecting the egress traffic of the given pod.
+func egressGatewayPodRule(pod *renderer.EgressGatewayPod) *vpp_acl.ACL_Rule {
+	return &vpp_acl.ACL_Rule{
+		Action: vpp_acl.ACL_Rule_PERMIT,
+		IpRule: &vpp_acl.ACL_Rule_IpRule{
+			Ip: &vpp_acl.ACL_Rule_IpRule_Ip{
+				SourceNetwork:      pod.IP.String() + ipv4HostPrefix,
+				DestinationNetwork: ipv4AnyNetwork,
+			},
+		},
+	}
+}
+
+// sortedABFInterfaces returns the given interfaces as a list sorted by name
+// (to get the ABF content stable across re-renderings).
+func sortedABFInterfaces(ifNames map[string]struct{}) (attachedIfs []*vpp_abf.ABF_AttachedInterface) {
+	var names []string
+	for ifName := range ifNames {
+		names = append(names, ifName)
+	}
+	sort.Strings(names)
+	for _, ifName := range names {
+		attachedIfs = append(attachedIfs, &vpp_abf.ABF_AttachedInterface{
+			InputInterface: ifName,
+		})
+	}
+	return attachedIfs
+}
+
+// egressIPRanges groups the given egress IPs into ranges of consecutive IPs
+// (returned as pairs of the first and the last IP).
+func egressIPRanges(egressIPs []net.IP) (ranges [][2]net.IP) {
+	sorted := make([]net.IP, 0, len(egressIPs))
+	for _, egressIP := range egressIPs {
+		sorted = append(sorted, egressIP.To4())
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+	for _, egressIP := range sorted {
+		if len(ranges) > 0 {
+			last := ranges[len(ranges)-1][1]
+			if next := nextIPv4(last); next.Equal(egressIP) {
+				ranges[len(ranges)-1][1] = egressIP
+				continue
+			}
+		}
+		ranges = append(ranges, [2]net.IP{egressIP, egressIP})
+	}
+	return ranges
+}
+
+// nextIPv4 returns the IPv4 address following the given one.
+func nextIPv4(ip net.IP) net.IP {
+	next := make(net.IP, net.IPv4len)
+	copy(next, ip.To4())
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+// egressGatewayLoopMAC returns the MAC address of the loopback in the given
+// VRF of an egress gateway.
+func egressGatewayLoopMAC(vrf uint32) string {
+	return fmt.Sprintf("02:fe:ee:%02x:%02x:%02x", byte(vrf>>16), byte(vrf>>8), byte(vrf))
+}
+
+// egressGatewayExcludeRules returns ACL rules excluding intra-cluster traffic
+// from being steered towards egress gateways.
+func (rndr *Renderer) egressGatewayExcludeRules() (rules []*vpp_acl.ACL_Rule) {
+	var clusterNets []*net.IPNet
+	clusterNets = append(clusterNets,
+		rndr.IPAM.PodSubnetAllNodes(ipnet.DefaultPodNetworkName),
+		rndr.IPAM.ServiceNetwork(),
+		rndr.IPAM.HostInterconnectSubnetAllNodes())
+	if rndr.nodeIPs != nil {
+		for _, nodeIP := range rndr.nodeIPs.List() {
+			if nodeIP.To4() != nil {
+				clusterNets = append(clusterNets, &net.IPNet{IP: nodeIP.To4(), Mask: net.CIDRMask(32, 32)})
+			}
+		}
+	}
+	for _, clusterNet := range clusterNets {
+		if clusterNet == nil || clusterNet.IP.To4() == nil {
+			continue
+		}
+		rules = append(rules, &vpp_acl.ACL_Rule{
+			Action: vpp_acl.ACL_Rule_DENY,
+			IpRule: &vpp_acl.ACL_Rule_IpRule{
+				Ip: &vpp_acl.ACL_Rule_IpRule_Ip{
+					SourceNetwork:      ipv4AnyNetwork,
+					DestinationNetwork: clusterNet.String(),
+				},
+			},
+		})
+	}
+	return rules
+}
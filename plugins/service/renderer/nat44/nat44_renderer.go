@@ -19,6 +19,7 @@ package nat44
 import (
 	"fmt"
 	"net"
+	"sort"
 	"sync/atomic"
 	"time"
 
@@ -95,6 +96,16 @@ var (
 // the NAT main address pool and the interface itself is switched into
 // the post-routing NAT mode (`output` feature) - both during Resync.
 //
+// Services with loadBalancerSourceRanges are tracked in the shared LBSourceRanges
+// (including their node ports exposed on the node IPs). The source ranges are
+// not enforced by the renderer - access to the restricted frontends is filtered
+// by the policy plugin as part of the ACLs installed for policies.
+//
+// The renderer also implements EgressGatewayRendererAPI - traffic of pods
+// selected by an egress gateway is steered (using ABF) into a separate VRF
+// on the gateway node, where it is SNATed to the NAT44 address pool
+// of the gateway's egress IPs.
+//
 // For more implementation details, please study the developer's guide for
 // services: `docs/dev-guide/SERVICES.md` from the top directory.
 type Renderer struct {
@@ -107,6 +118,14 @@ type Renderer struct {
 	/* dynamic SNAT */
 	defaultIfName string
 	defaultIfIP   net.IP
+
+	/* service ports that cannot be rendered (service ID -> errors) */
+	serviceErrors map[string][]string
+
+	/* egress gateways */
+	egressGws      []*renderer.EgressGateway
+	egressGwConfig controller.KeyValuePairs /* rendered configuration */
+	egressGwVrfs   map[string]uint32        /* gateway name -> allocated VRF (local gateways) */
 }
 
 // Deps lists dependencies of the Renderer.
@@ -118,8 +137,9 @@ type Deps struct {
 	IPNet            ipnet.API
 	UpdateTxnFactory func(change string) (txn controller.UpdateOperations)
 	ResyncTxnFactory func() (txn controller.ResyncOperations)
-	GoVPPChan        govpp.Channel      /* used for direct NAT binary API calls */
-	Stats            statscollector.API /* used for exporting the statistics */
+	GoVPPChan        govpp.Channel            /* used for direct NAT binary API calls */
+	Stats            statscollector.API       /* used for exporting the statistics */
+	LBSourceRanges   *renderer.LBSourceRanges /* services with loadBalancerSourceRanges */
 }
 
 // Init initializes the renderer.
@@ -130,6 +150,11 @@ func (rndr *Renderer) Init(snatOnly bool) error {
 	rndr.natGlobalCfg = &vpp_nat.Nat44Global{
 		Forwarding: true,
 	}
+	if rndr.LBSourceRanges == nil {
+		rndr.LBSourceRanges = renderer.NewLBSourceRanges()
+	}
+	rndr.serviceErrors = make(map[string][]string)
+	rndr.egressGwConfig = make(controller.KeyValuePairs)
 	if rndr.Config == nil {
 		rndr.Config = config.DefaultConfig()
 	}
@@ -149,9 +174,12 @@ func (rndr *Renderer) AddService(service *renderer.ContivService) error {
 		return nil
 	}
 
+	rndr.warnUnsupportedLBMode(service)
+	rndr.warnAffinityPrefixes(service)
 	dnat := rndr.contivServiceToDNat(service)
 	txn := rndr.UpdateTxnFactory(fmt.Sprintf("add service '%v'", service.ID))
 	txn.Put(vpp_nat.DNAT44Key(dnat.Label), dnat)
+	rndr.LBSourceRanges.Update(service, false)
 	return nil
 }
 
@@ -160,9 +188,12 @@ func (rndr *Renderer) UpdateService(oldService, newService *renderer.ContivServi
 	if rndr.snatOnly {
 		return nil
 	}
+	rndr.warnUnsupportedLBMode(newService)
+	rndr.warnAffinityPrefixes(newService)
 	newDNAT := rndr.contivServiceToDNat(newService)
 	txn := rndr.UpdateTxnFactory(fmt.Sprintf("update service '%v'", newService.ID))
 	txn.Put(vpp_nat.DNAT44Key(newDNAT.Label), newDNAT)
+	rndr.LBSourceRanges.Update(newService, false)
 	return nil
 }
 
@@ -175,6 +206,8 @@ func (rndr *Renderer) DeleteService(service *renderer.ContivService, otherExisti
 
 	txn := rndr.UpdateTxnFactory(fmt.Sprintf("delete service '%v'", service.ID))
 	txn.Delete(vpp_nat.DNAT44Key(service.ID.String()))
+	delete(rndr.serviceErrors, service.ID.String())
+	rndr.LBSourceRanges.Update(service, true)
 	return nil
 }
 
@@ -188,6 +221,7 @@ func (rndr *Renderer) UpdateNodePortServices(nodeIPs *renderer.IPAddresses,
 	}
 	// Update cached internal node IPs.
 	rndr.nodeIPs = nodeIPs
+	rndr.LBSourceRanges.SetNodeIPs(nodeIPs)
 
 	// Update DNAT of all node-port services via ligato/vpp-agent.
 	txn := rndr.UpdateTxnFactory("update nodeport services")
@@ -276,6 +310,9 @@ func (rndr *Renderer) UpdateLocalBackendIfs(oldIfNames, newIfNames renderer.Inte
 func (rndr *Renderer) Resync(resyncEv *renderer.ResyncEventData) error {
 	txn := rndr.ResyncTxnFactory()
 
+	// Egress gateways are rendered even in the SNAT-only mode.
+	rndr.egressGws = resyncEv.EgressGateways
+
 	// In case the renderer is supposed to configure only the dynamic source-NAT,
 	// just pretend there are no services, frontends and backends to be configured.
 	if rndr.snatOnly {
@@ -311,6 +348,7 @@ func (rndr *Renderer) Resync(resyncEv *renderer.ResyncEventData) error {
 	rndr.nodeIPs = resyncEv.NodeIPs
 
 	// Resync DNAT configuration.
+	rndr.serviceErrors = make(map[string][]string)
 	for _, service := range resyncEv.Services {
 		dnat := rndr.contivServiceToDNat(service)
 		txn.Put(vpp_nat.DNAT44Key(dnat.Label), dnat)
@@ -374,6 +412,15 @@ func (rndr *Renderer) Resync(resyncEv *renderer.ResyncEventData) error {
 	}
 	// - add to the transaction
 	txn.Put(vpp_nat.GlobalNAT44Key(), rndr.natGlobalCfg)
+
+	// Resync services with LB source ranges.
+	if !rndr.snatOnly {
+		rndr.LBSourceRanges.Resync(resyncEv.Services, resyncEv.NodeIPs)
+	}
+
+	// Resync configuration of egress gateways.
+	rndr.egressGwConfig = rndr.renderEgressGateways()
+	controller.PutAll(txn, rndr.egressGwConfig)
 	return nil
 }
 
@@ -407,35 +454,92 @@ func (rndr *Renderer) getDefaultInterface() (ifName string, ifAddress net.IP) {
 	return "", nil
 }
 
+// GetServiceConfig returns the DNAT configuration (static mappings) rendered
+// for the given service.
+func (rndr *Renderer) GetServiceConfig(service *renderer.ContivService) controller.KeyValuePairs {
+	if rndr.snatOnly {
+		return nil
+	}
+	dnat := rndr.contivServiceToDNat(service)
+	return controller.KeyValuePairs{vpp_nat.DNAT44Key(dnat.Label): dnat}
+}
+
 // contivServiceToDNat returns DNAT configuration corresponding to a given service.
+// Ports of the service which cannot be rendered are remembered as service errors.
 func (rndr *Renderer) contivServiceToDNat(service *renderer.ContivService) *vpp_nat.DNat44 {
 	dnat := &vpp_nat.DNat44{}
 	dnat.Label = service.ID.String()
-	dnat.StMappings = rndr.exportDNATMappings(service)
+	errs := make(map[string]struct{})
+	dnat.StMappings = rndr.exportDNATMappings(service, errs)
+	rndr.setServiceErrors(service, errs)
 	return dnat
 }
 
 // exportDNATMappings exports the corresponding list of D-NAT mappings from a Contiv service.
-func (rndr *Renderer) exportDNATMappings(service *renderer.ContivService) []*vpp_nat.DNat44_StaticMapping {
+// Errors of ports that cannot be rendered are added into <errs>.
+func (rndr *Renderer) exportDNATMappings(service *renderer.ContivService,
+	errs map[string]struct{}) []*vpp_nat.DNat44_StaticMapping {
 	mappings := []*vpp_nat.DNat44_StaticMapping{}
 
 	// Export NAT mappings for NodePort services.
 	if service.HasNodePort() {
-		mappings = append(mappings, rndr.exportServiceIPMappings(service, rndr.nodeIPs, nodeIP)...)
+		mappings = append(mappings, rndr.exportServiceIPMappings(service, rndr.nodeIPs, nodeIP, errs)...)
 	}
 
 	// Export NAT mappings for cluster & external IPs.
-	mappings = append(mappings, rndr.exportServiceIPMappings(service, service.ClusterIPs, clusterIP)...)
-	mappings = append(mappings, rndr.exportServiceIPMappings(service, service.ExternalIPs, externalIP)...)
+	mappings = append(mappings, rndr.exportServiceIPMappings(service, service.ClusterIPs, clusterIP, errs)...)
+	mappings = append(mappings, rndr.exportServiceIPMappings(service, service.ExternalIPs, externalIP, errs)...)
+
+	// Export NAT mapping for the health-check node port.
+	if hcMapping := rndr.exportHealthCheckMapping(service); hcMapping != nil {
+		mappings = append(mappings, hcMapping)
+	}
 
 	return mappings
 }
 
+// exportHealthCheckMapping exports D-NAT mapping which exposes the health-check
+// node port of the service, served by the agent in the host network stack,
+// on the IP address of this node.
+func (rndr *Renderer) exportHealthCheckMapping(service *renderer.ContivService) *vpp_nat.DNat44_StaticMapping {
+	if service.HealthCheckNodePort == 0 {
+		return nil
+	}
+	nodeIP, _ := rndr.IPNet.GetNodeIP()
+	if nodeIP == nil || nodeIP.To4() == nil {
+		return nil
+	}
+	var hostIP net.IP
+	for _, ip := range rndr.IPNet.GetHostIPs() {
+		if ip.To4() != nil && !ip.Equal(nodeIP) {
+			hostIP = ip
+			break
+		}
+	}
+	if hostIP == nil {
+		// node IP is assigned to the host (e.g. STN mode), no need to NAT
+		return nil
+	}
+	return &vpp_nat.DNat44_StaticMapping{
+		ExternalIp:   nodeIP.To4().String(),
+		ExternalPort: uint32(service.HealthCheckNodePort),
+		Protocol:     vpp_nat.DNat44_TCP,
+		TwiceNat:     vpp_nat.DNat44_StaticMapping_SELF,
+		LocalIps: []*vpp_nat.DNat44_StaticMapping_LocalIP{
+			{
+				LocalIp:   hostIP.To4().String(),
+				LocalPort: uint32(service.HealthCheckNodePort),
+				VrfId:     rndr.ContivConf.GetRoutingConfig().MainVRFID,
+			},
+		},
+	}
+}
+
 // exportServiceIPMappings exports the corresponding list of D-NAT mappings from a list of service IPs of the given service.
+// Errors of ports that cannot be rendered are added into <errs>.
 func (rndr *Renderer) exportServiceIPMappings(service *renderer.ContivService,
-	serviceIPs *renderer.IPAddresses, ipType serviceIPType) (mappings []*vpp_nat.DNat44_StaticMapping) {
+	serviceIPs *renderer.IPAddresses, ipType serviceIPType, errs map[string]struct{}) (mappings []*vpp_nat.DNat44_StaticMapping) {
 
-	routingCfg := rndr.ContivConf.GetRoutingConfig()
 	for _, ip := range serviceIPs.List() {
 		if ip.To4() != nil {
 			ip = ip.To4()
@@ -444,12 +548,29 @@ func (rndr *Renderer) exportServiceIPMappings(service *renderer.ContivService,
 			continue
 		}
 		// Add one mapping for each port.
-		for portName, port := range service.Ports {
+		for _, portName := range sortedPortNames(service) {
+			port := service.Ports[portName]
 			if ipType == nodeIP && port.NodePort == 0 {
 				continue
 			} else if ipType != nodeIP && port.Port == 0 {
 				continue
 			}
+			if port.Protocol == renderer.SCTP {
+				// VPP-NAT translates only TCP and UDP ports - an address-only
+				// mapping would NAT all traffic of the service IP and of the backend.
+				errs[fmt.Sprintf("port %s: SCTP is not supported by NAT44 "+
+					"(enable UseSRv6ForServices or UseIPv6)", portName)] = struct{}{}
+				continue
+			}
+			var backends []*renderer.ServiceBackend
+			for _, backend := range service.Backends[portName] {
+				if service.TrafficPolicy != renderer.ClusterWide && !backend.Local {
+					// Do not NAT+LB remote backends.
+					continue
+				}
+				backends = append(backends, backend)
+			}
+			backends = renderer.NearestBackends(service, backends)
 			mapping := &vpp_nat.DNat44_StaticMapping{}
 			if ipType == externalIP && service.TrafficPolicy == renderer.ClusterWide {
 				mapping.TwiceNat = vpp_nat.DNat44_StaticMapping_ENABLED
@@ -462,43 +583,28 @@ func (rndr *Renderer) exportServiceIPMappings(service *renderer.ContivService,
 			} else {
 				mapping.ExternalPort = uint32(port.Port)
 			}
-			switch port.Protocol {
-			case renderer.TCP:
-				mapping.Protocol = vpp_nat.DNat44_TCP
-			case renderer.UDP:
+			if port.Protocol == renderer.UDP {
 				mapping.Protocol = vpp_nat.DNat44_UDP
+			} else {
+				mapping.Protocol = vpp_nat.DNat44_TCP
 			}
-			for _, backend := range service.Backends[portName] {
-				if service.TrafficPolicy != renderer.ClusterWide && !backend.Local {
-					// Do not NAT+LB remote backends.
-					continue
-				}
+			weights := renderer.BackendWeights(service.LBMode, backends)
+			for idx, backend := range backends {
 				local := &vpp_nat.DNat44_StaticMapping_LocalIP{
 					LocalIp:   backend.IP.String(),
 					LocalPort: uint32(backend.Port),
 				}
-				if backend.Local {
+				if service.LBMode != renderer.RandomLB {
+					local.Probability = weights[idx]
+				} else if backend.Local {
 					local.Probability = uint32(rndr.Config.ServiceLocalEndpointWeight)
 				} else {
 					local.Probability = 1
 				}
-				if rndr.isThisNodeOrHostIP(backend.IP) {
-					local.VrfId = routingCfg.MainVRFID
-				} else {
-					if (rndr.ContivConf.GetRoutingConfig().NodeToNodeTransport == contivconf.NoOverlayTransport ||
-						rndr.ContivConf.GetRoutingConfig().NodeToNodeTransport == contivconf.SRv6Transport) &&
-						(!rndr.isLocalPodIP(backend.IP)) {
-						// no overlay mode: use main VRF for non-local PODs and other node's IPs
-						local.VrfId = routingCfg.MainVRFID
-					} else {
-						// use POD VRF for local PODs (both no-overlay & VXLAN mode)
-						// and non-local PODs + non-local node IPs in VXLAN mode
-						local.VrfId = routingCfg.PodVRFID
-					}
-				}
+				local.VrfId = rndr.localIPVrfID(backend.IP)
 				mapping.LocalIps = append(mapping.LocalIps, local)
-				mapping.SessionAffinity = service.SessionAffinityTimeout
 			}
+			mapping.SessionAffinity, _ = service.PortSessionAffinity(portName)
 			if len(mapping.LocalIps) == 0 {
 				continue
 			}
@@ -513,6 +619,92 @@ func (rndr *Renderer) exportServiceIPMappings(service *renderer.ContivService,
 	return mappings
 }
 
+// setServiceErrors remembers errors of service ports that cannot be rendered.
+// New errors are logged.
+func (rndr *Renderer) setServiceErrors(service *renderer.ContivService, errs map[string]struct{}) {
+	svcID := service.ID.String()
+	if len(errs) == 0 {
+		delete(rndr.serviceErrors, svcID)
+		return
+	}
+	prevErrs := make(map[string]struct{})
+	for _, err := range rndr.serviceErrors[svcID] {
+		prevErrs[err] = struct{}{}
+	}
+	var svcErrs []string
+	for err := range errs {
+		svcErrs = append(svcErrs, err)
+		if _, known := prevErrs[err]; !known {
+			rndr.Log.WithFields(logging.Fields{
+				"service": svcID,
+			}).Errorf("Service port cannot be rendered by NAT44: %s", err)
+		}
+	}
+	sort.Strings(svcErrs)
+	rndr.serviceErrors[svcID] = svcErrs
+}
+
+// GetServiceErrors returns errors of the service ports that could not be rendered
+// (the ports are not exposed by VPP-NAT).
+func (rndr *Renderer) GetServiceErrors(service *renderer.ContivService) []string {
+	return rndr.serviceErrors[service.ID.String()]
+}
+
+// sortedPortNames returns names of the service ports in a deterministic order.
+func sortedPortNames(service *renderer.ContivService) []string {
+	portNames := make([]string, 0, len(service.Ports))
+	for portName := range service.Ports {
+		portNames = append(portNames, portName)
+	}
+	sort.Strings(portNames)
+	return portNames
+}
+
+// warnUnsupportedLBMode logs a warning if the service requests consistent hashing,
+// which VPP-NAT cannot provide - the backend of a new session is selected randomly,
+// the service is therefore load-balanced proportionally to the backend weights.
+func (rndr *Renderer) warnUnsupportedLBMode(service *renderer.ContivService) {
+	if service.LBMode == renderer.ConsistentHashLB {
+		rndr.Log.WithFields(logging.Fields{
+			"service": service.ID,
+		}).Warn("Consistent hashing is not supported by NAT44 (requires SRv6 for services), " +
+			"using weighted load-balancing")
+	}
+}
+
+// warnAffinityPrefixes logs a warning for every port of the service with session
+// affinity keyed on a client IP prefix - VPP-NAT always keys the affinity on the full
+// client address, which is therefore used instead.
+func (rndr *Renderer) warnAffinityPrefixes(service *renderer.ContivService) {
+	for _, portName := range sortedPortNames(service) {
+		if _, prefixLen := service.PortSessionAffinity(portName); prefixLen != 0 && prefixLen < net.IPv4len*8 {
+			rndr.Log.WithFields(logging.Fields{
+				"service":   service.ID,
+				"port":      portName,
+				"prefixLen": prefixLen,
+			}).Warn("NAT44 cannot key session affinity on a client prefix, using the full client address")
+		}
+	}
+}
+
+// localIPVrfID returns ID of the VRF in which the given local IP of a static
+// mapping is reachable.
+func (rndr *Renderer) localIPVrfID(ip net.IP) uint32 {
+	routingCfg := rndr.ContivConf.GetRoutingConfig()
+	if rndr.isThisNodeOrHostIP(ip) {
+		return routingCfg.MainVRFID
+	}
+	if (routingCfg.NodeToNodeTransport == contivconf.NoOverlayTransport ||
+		routingCfg.NodeToNodeTransport == contivconf.SRv6Transport) &&
+		(!rndr.isLocalPodIP(ip)) {
+		// no overlay mode: use main VRF for non-local PODs and other node's IPs
+		return routingCfg.MainVRFID
+	}
+	// use POD VRF for local PODs (both no-overlay & VXLAN mode)
+	// and non-local PODs + non-local node IPs in VXLAN mode
+	return routingCfg.PodVRFID
+}
+
 // isThisNodeOrHostIP returns true if the given IP is current node's node (VPP) or host (mgmt) IP, false otherwise.
 func (rndr *Renderer) isThisNodeOrHostIP(ip net.IP) bool {
 	nodeIP, _ := rndr.IPNet.GetNodeIP()
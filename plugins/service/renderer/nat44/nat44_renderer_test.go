@@ -25,16 +25,22 @@ import (
 	"github.com/contiv/vpp/mock/localclient"
 	"github.com/contiv/vpp/plugins/contivconf"
 	"github.com/contiv/vpp/plugins/contivconf/config"
+	egressgwmodel "github.com/contiv/vpp/plugins/crd/handler/egressgateway/model"
 	nodeconfigcrd "github.com/contiv/vpp/plugins/crd/pkg/apis/nodeconfig/v1"
 	epmodel "github.com/contiv/vpp/plugins/ksr/model/endpoints"
+	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
 	svcmodel "github.com/contiv/vpp/plugins/ksr/model/service"
 	"github.com/contiv/vpp/plugins/nodesync"
 	"github.com/contiv/vpp/plugins/podmanager"
 	svc_config "github.com/contiv/vpp/plugins/service/config"
+	"github.com/contiv/vpp/plugins/service/healthcheck"
 	svc_processor "github.com/contiv/vpp/plugins/service/processor"
 	svc_renderer "github.com/contiv/vpp/plugins/service/renderer"
 	"github.com/contiv/vpp/plugins/service/renderer/nat44"
 	renderer_testing "github.com/contiv/vpp/plugins/service/renderer/testing"
+
+	vpp_acl "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/acl"
+	vpp_l3 "go.ligato.io/vpp-agent/v3/proto/ligato/vpp/l3"
 )
 
 const (
@@ -1933,3 +1939,979 @@ func TestLocalServicePolicy(t *testing.T) {
 	Expect(data.SVCProcessor.Close()).To(BeNil())
 	Expect(data.renderer.Close()).To(BeNil())
 }
+
+func TestLBSourceRanges(t *testing.T) {
+	RegisterTestingT(t)
+	const localEndpointWeight uint8 = 1
+	config := defaultConfig(false)
+	data := initTest("TestLBSourceRanges", config, localEndpointWeight, false)
+
+	// Test resync with empty VPP configuration.
+	resyncEv, _ := data.Datasync.ResyncEvent(keyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.renderer.LBSourceRanges.GetRestrictedFrontends()).To(BeEmpty())
+
+	// Add pods.
+	updateEv1 := data.PodManager.AddPod(&podmanager.LocalPod{ID: renderer_testing.Pod1})
+	Expect(data.SVCProcessor.Update(updateEv1)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// Add LoadBalancer service with restricted source ranges.
+	service1 := &svcmodel.Service{
+		Name:                     "service1",
+		Namespace:                renderer_testing.Namespace1,
+		ServiceType:              "LoadBalancer",
+		ExternalTrafficPolicy:    "Cluster",
+		ClusterIp:                "10.96.0.1",
+		LbIngressIps:             []string{"30.30.30.30"},
+		LoadbalancerSourceRanges: []string{"100.64.0.0/16", "100.65.1.0/24"},
+		Port: []*svcmodel.Service_ServicePort{
+			{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     80,
+				NodePort: 30080,
+			},
+		},
+	}
+	eps1 := &epmodel.Endpoints{
+		Name:      "service1",
+		Namespace: renderer_testing.Namespace1,
+		EndpointSubsets: []*epmodel.EndpointSubset{
+			{
+				Addresses: []*epmodel.EndpointSubset_EndpointAddress{
+					{
+						Ip:       pod1IP.String(),
+						NodeName: renderer_testing.MasterLabel,
+						TargetRef: &epmodel.ObjectReference{
+							Kind:      "Pod",
+							Namespace: renderer_testing.Pod1.Namespace,
+							Name:      renderer_testing.Pod1.Name,
+						},
+					},
+				},
+				Ports: []*epmodel.EndpointSubset_EndpointPort{
+					{
+						Name:     "http",
+						Port:     8080,
+						Protocol: "TCP",
+					},
+				},
+			},
+		},
+	}
+
+	updateEv2 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv2)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	updateEv3 := data.Datasync.PutEvent(epmodel.Key(eps1.Name, eps1.Namespace), eps1)
+	Expect(data.SVCProcessor.Update(updateEv3)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// LB ingress IP is exposed via static mapping.
+	staticMapping := &StaticMapping{
+		ExternalIP:   net.ParseIP("30.30.30.30"),
+		ExternalPort: 80,
+		Protocol:     svc_renderer.TCP,
+		TwiceNAT:     true,
+		Locals: []*Local{
+			{
+				VrfID: renderer_testing.PodVrfID,
+				IP:    pod1IP,
+				Port:  8080,
+			},
+		},
+	}
+	Expect(data.natPlugin.HasStaticMapping(staticMapping)).To(BeTrue())
+
+	// LB ingress IP and node port are restricted (access is filtered by the policy plugin).
+	frontends := data.renderer.LBSourceRanges.GetRestrictedFrontends()
+	Expect(frontends).To(HaveLen(3))
+	// - LB ingress IP
+	Expect(frontends[0].IP.Equal(net.ParseIP("30.30.30.30"))).To(BeTrue())
+	Expect(frontends[0].Protocol).To(Equal(svc_renderer.TCP))
+	Expect(frontends[0].Port).To(BeEquivalentTo(80))
+	Expect(frontends[0].SourceRanges).To(HaveLen(2))
+	Expect(frontends[0].SourceRanges[0].String()).To(Equal("100.64.0.0/16"))
+	Expect(frontends[0].SourceRanges[1].String()).To(Equal("100.65.1.0/24"))
+	// - node port (on the VPP and the management IP of the node, cluster IP is not restricted)
+	Expect(frontends[1].IP.Equal(nodeIP.IP)).To(BeTrue())
+	Expect(frontends[2].IP.Equal(mgmtIP)).To(BeTrue())
+	for _, frontend := range frontends[1:] {
+		Expect(frontend.Protocol).To(Equal(svc_renderer.TCP))
+		Expect(frontend.Port).To(BeEquivalentTo(30080))
+		Expect(frontend.SourceRanges).To(HaveLen(2))
+	}
+
+	// Remove the source ranges - frontends are no longer restricted.
+	service1.LoadbalancerSourceRanges = nil
+	updateEv4 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv4)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.renderer.LBSourceRanges.GetRestrictedFrontends()).To(BeEmpty())
+	Expect(data.natPlugin.HasStaticMapping(staticMapping)).To(BeTrue())
+
+	// Restore the source ranges and resync.
+	service1.LoadbalancerSourceRanges = []string{"100.64.0.0/16"}
+	data.Datasync.Put(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	resyncEv2, _ := data.Datasync.ResyncEvent(keyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv2.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	frontends = data.renderer.LBSourceRanges.GetRestrictedFrontends()
+	Expect(frontends).To(HaveLen(3))
+	Expect(frontends[0].IP.Equal(net.ParseIP("30.30.30.30"))).To(BeTrue())
+	Expect(frontends[0].SourceRanges).To(HaveLen(1))
+	Expect(frontends[0].SourceRanges[0].String()).To(Equal("100.64.0.0/16"))
+	Expect(frontends[1].IP.Equal(nodeIP.IP)).To(BeTrue())
+	Expect(frontends[1].Port).To(BeEquivalentTo(30080))
+
+	// Finally remove the service.
+	updateEv5 := data.Datasync.DeleteEvent(svcmodel.Key(service1.Name, service1.Namespace))
+	Expect(data.SVCProcessor.Update(updateEv5)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.renderer.LBSourceRanges.GetRestrictedFrontends()).To(BeEmpty())
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(0))
+
+	// Cleanup
+	Expect(data.SVCProcessor.Close()).To(BeNil())
+	Expect(data.renderer.Close()).To(BeNil())
+}
+
+// healthCheckMock remembers the last state of health-check node ports
+// provided by the service processor.
+type healthCheckMock struct {
+	services map[svcmodel.ID]*healthcheck.ServiceHealth
+}
+
+func (m *healthCheckMock) SyncServices(services map[svcmodel.ID]*healthcheck.ServiceHealth) error {
+	m.services = services
+	return nil
+}
+
+func TestHealthCheckNodePort(t *testing.T) {
+	RegisterTestingT(t)
+	const localEndpointWeight uint8 = 1
+	config := defaultConfig(false)
+	data := initTest("TestHealthCheckNodePort", config, localEndpointWeight, false)
+	hcMock := &healthCheckMock{}
+	data.SVCProcessor.HealthCheck = hcMock
+
+	// Test resync with empty VPP configuration.
+	resyncEv, _ := data.Datasync.ResyncEvent(keyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(hcMock.services).To(BeEmpty())
+
+	// Add pods.
+	updateEv1 := data.PodManager.AddPod(&podmanager.LocalPod{ID: renderer_testing.Pod1})
+	Expect(data.SVCProcessor.Update(updateEv1)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// Add LoadBalancer service with node-local traffic policy.
+	service1 := &svcmodel.Service{
+		Name:                  "service1",
+		Namespace:             renderer_testing.Namespace1,
+		ServiceType:           "LoadBalancer",
+		ExternalTrafficPolicy: "Local",
+		ClusterIp:             "10.96.0.1",
+		LbIngressIps:          []string{"30.30.30.30"},
+		HealthCheckNodePort:   32000,
+		Port: []*svcmodel.Service_ServicePort{
+			{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     80,
+				NodePort: 30080,
+			},
+		},
+	}
+	updateEv2 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv2)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// No endpoints yet - nothing to report.
+	Expect(hcMock.services).To(BeEmpty())
+
+	// Add endpoints - one local, one remote.
+	eps1 := &epmodel.Endpoints{
+		Name:      "service1",
+		Namespace: renderer_testing.Namespace1,
+		EndpointSubsets: []*epmodel.EndpointSubset{
+			{
+				Addresses: []*epmodel.EndpointSubset_EndpointAddress{
+					{
+						Ip:       pod1IP.String(),
+						NodeName: renderer_testing.MasterLabel,
+						TargetRef: &epmodel.ObjectReference{
+							Kind:      "Pod",
+							Namespace: renderer_testing.Pod1.Namespace,
+							Name:      renderer_testing.Pod1.Name,
+						},
+					},
+					{
+						Ip:       pod3IP.String(),
+						NodeName: renderer_testing.WorkerLabel,
+						TargetRef: &epmodel.ObjectReference{
+							Kind:      "Pod",
+							Namespace: renderer_testing.Pod3.Namespace,
+							Name:      renderer_testing.Pod3.Name,
+						},
+					},
+				},
+				Ports: []*epmodel.EndpointSubset_EndpointPort{
+					{
+						Name:     "http",
+						Port:     8080,
+						Protocol: "TCP",
+					},
+				},
+			},
+		},
+	}
+	updateEv3 := data.Datasync.PutEvent(epmodel.Key(eps1.Name, eps1.Namespace), eps1)
+	Expect(data.SVCProcessor.Update(updateEv3)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	svcID := svcmodel.ID{Name: service1.Name, Namespace: service1.Namespace}
+	Expect(hcMock.services).To(HaveLen(1))
+	Expect(hcMock.services).To(HaveKey(svcID))
+	Expect(hcMock.services[svcID].NodePort).To(BeEquivalentTo(32000))
+	Expect(hcMock.services[svcID].LocalEndpoints).To(Equal(1))
+
+	// Health-check node port is exposed on the node IP and forwarded to the host.
+	hcMapping := &StaticMapping{
+		ExternalIP:   nodeIP.IP,
+		ExternalPort: 32000,
+		Protocol:     svc_renderer.TCP,
+		Locals: []*Local{
+			{
+				VrfID: renderer_testing.MainVrfID,
+				IP:    mgmtIP,
+				Port:  32000,
+			},
+		},
+	}
+	Expect(data.natPlugin.HasStaticMapping(hcMapping)).To(BeTrue())
+
+	// Remove the local endpoint.
+	eps1.EndpointSubsets[0].Addresses = eps1.EndpointSubsets[0].Addresses[1:]
+	updateEv4 := data.Datasync.PutEvent(epmodel.Key(eps1.Name, eps1.Namespace), eps1)
+	Expect(data.SVCProcessor.Update(updateEv4)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(hcMock.services).To(HaveKey(svcID))
+	Expect(hcMock.services[svcID].LocalEndpoints).To(Equal(0))
+	Expect(data.natPlugin.HasStaticMapping(hcMapping)).To(BeTrue())
+
+	// Switch to cluster-wide traffic policy - health-check is no longer served.
+	service1.ExternalTrafficPolicy = "Cluster"
+	updateEv5 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv5)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(hcMock.services).To(BeEmpty())
+	Expect(data.natPlugin.HasStaticMapping(hcMapping)).To(BeFalse())
+
+	// Cleanup
+	Expect(data.SVCProcessor.Close()).To(BeNil())
+	Expect(data.renderer.Close()).To(BeNil())
+}
+
+// getMappingProbabilities returns probabilities of backends (by IP) of the static
+// mapping installed for the given external IP and port, together with the session
+// affinity of the mapping.
+func getMappingProbabilities(natPlugin *MockNatPlugin, externalIP string, externalPort uint32) (
+	probabilities map[string]uint32, affinity uint32) {
+
+	for _, dnat := range natPlugin.DumpNat44DNat() {
+		for _, mapping := range dnat.StMappings {
+			if mapping.ExternalIp != externalIP || mapping.ExternalPort != externalPort {
+				continue
+			}
+			probabilities = make(map[string]uint32)
+			for _, local := range mapping.LocalIps {
+				probabilities[local.LocalIp] = local.Probability
+			}
+			return probabilities, mapping.SessionAffinity
+		}
+	}
+	return nil, 0
+}
+
+func TestWeightedLoadBalancing(t *testing.T) {
+	RegisterTestingT(t)
+	const localEndpointWeight uint8 = 1
+	config := defaultConfig(false)
+	data := initTest("TestWeightedLoadBalancing", config, localEndpointWeight, false)
+
+	// Test resync with empty VPP configuration.
+	resyncEv, _ := data.Datasync.ResyncEvent(keyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// Add pods, pod1 with weight 3.
+	updateEv1 := data.PodManager.AddPod(&podmanager.LocalPod{ID: renderer_testing.Pod1})
+	Expect(data.SVCProcessor.Update(updateEv1)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	updateEv2 := data.PodManager.AddPod(&podmanager.LocalPod{ID: renderer_testing.Pod2})
+	Expect(data.SVCProcessor.Update(updateEv2)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	pod1Weight := func(weight string) *podmodel.Pod {
+		pod := &podmodel.Pod{
+			Name:        renderer_testing.Pod1.Name,
+			Namespace:   renderer_testing.Pod1.Namespace,
+			IpAddress:   pod1IP.String(),
+			Annotations: map[string]string{"contivpp.io/service-weight": weight},
+		}
+		data.PodManager.AddRemotePod(&podmanager.Pod{
+			ID:          renderer_testing.Pod1,
+			IPAddress:   pod.IpAddress,
+			Annotations: pod.Annotations,
+		})
+		return pod
+	}
+	data.Datasync.PutEvent(podmodel.Key(renderer_testing.Pod1.Name, renderer_testing.Pod1.Namespace), pod1Weight("3"))
+
+	// Add service with weighted load-balancing.
+	service1 := &svcmodel.Service{
+		Name:                  "service1",
+		Namespace:             renderer_testing.Namespace1,
+		ClusterIp:             "10.96.0.1",
+		ExternalTrafficPolicy: "Cluster",
+		Annotations:           map[string]string{"contivpp.io/lb-mode": "weighted"},
+		Port: []*svcmodel.Service_ServicePort{
+			{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     80,
+			},
+		},
+	}
+	endpoint := func(ip net.IP, pod podmodel.ID, node string) *epmodel.EndpointSubset_EndpointAddress {
+		return &epmodel.EndpointSubset_EndpointAddress{
+			Ip:       ip.String(),
+			NodeName: node,
+			TargetRef: &epmodel.ObjectReference{
+				Kind:      "Pod",
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+			},
+		}
+	}
+	eps1 := &epmodel.Endpoints{
+		Name:      "service1",
+		Namespace: renderer_testing.Namespace1,
+		EndpointSubsets: []*epmodel.EndpointSubset{
+			{
+				Addresses: []*epmodel.EndpointSubset_EndpointAddress{
+					endpoint(pod1IP, renderer_testing.Pod1, renderer_testing.MasterLabel),
+					endpoint(pod2IP, renderer_testing.Pod2, renderer_testing.MasterLabel),
+					endpoint(pod3IP, renderer_testing.Pod3, renderer_testing.WorkerLabel),
+				},
+				Ports: []*epmodel.EndpointSubset_EndpointPort{
+					{
+						Name:     "http",
+						Port:     8080,
+						Protocol: "TCP",
+					},
+				},
+			},
+		},
+	}
+	updateEv3 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv3)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	updateEv4 := data.Datasync.PutEvent(epmodel.Key(eps1.Name, eps1.Namespace), eps1)
+	Expect(data.SVCProcessor.Update(updateEv4)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	probabilities, affinity := getMappingProbabilities(data.natPlugin, "10.96.0.1", 80)
+	Expect(probabilities).To(Equal(map[string]uint32{
+		pod1IP.String(): 3,
+		pod2IP.String(): 1,
+		pod3IP.String(): 1,
+	}))
+	Expect(affinity).To(BeZero())
+
+	// Change weight of pod1.
+	updateEv5 := data.Datasync.PutEvent(podmodel.Key(renderer_testing.Pod1.Name, renderer_testing.Pod1.Namespace), pod1Weight("5"))
+	Expect(data.SVCProcessor.Update(updateEv5)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	probabilities, _ = getMappingProbabilities(data.natPlugin, "10.96.0.1", 80)
+	Expect(probabilities[pod1IP.String()]).To(BeEquivalentTo(5))
+	Expect(probabilities[pod2IP.String()]).To(BeEquivalentTo(1))
+
+	// Consistent hashing is not supported by NAT44 - falls back to weighted.
+	service1.Annotations = map[string]string{"contivpp.io/lb-mode": "consistent-hash"}
+	updateEv6 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv6)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	probabilities, affinity = getMappingProbabilities(data.natPlugin, "10.96.0.1", 80)
+	Expect(probabilities).To(Equal(map[string]uint32{
+		pod1IP.String(): 5,
+		pod2IP.String(): 1,
+		pod3IP.String(): 1,
+	}))
+	Expect(affinity).To(BeZero())
+
+	// Cleanup
+	Expect(data.SVCProcessor.Close()).To(BeNil())
+	Expect(data.renderer.Close()).To(BeNil())
+}
+
+func TestPerPortSessionAffinity(t *testing.T) {
+	RegisterTestingT(t)
+	const localEndpointWeight uint8 = 1
+	data := initTest("TestPerPortSessionAffinity", defaultConfig(false), localEndpointWeight, false)
+
+	resyncEv, _ := data.Datasync.ResyncEvent(keyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// Add service with ClientIP affinity, overridden for the http port by annotation.
+	service1 := &svcmodel.Service{
+		Name:                   "service1",
+		Namespace:              renderer_testing.Namespace1,
+		ClusterIp:              "10.96.0.1",
+		ExternalTrafficPolicy:  "Cluster",
+		SessionAffinity:        "ClientIP",
+		SessionAffinityTimeout: 10800,
+		Annotations:            map[string]string{"contivpp.io/session-affinity-timeout": "http=600"},
+		Port: []*svcmodel.Service_ServicePort{
+			{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     80,
+			},
+			{
+				Name:     "dns",
+				Protocol: "UDP",
+				Port:     53,
+			},
+		},
+	}
+	eps1 := &epmodel.Endpoints{
+		Name:      "service1",
+		Namespace: renderer_testing.Namespace1,
+		EndpointSubsets: []*epmodel.EndpointSubset{
+			{
+				Addresses: []*epmodel.EndpointSubset_EndpointAddress{
+					{
+						Ip:       pod1IP.String(),
+						NodeName: renderer_testing.MasterLabel,
+					},
+					{
+						Ip:       pod2IP.String(),
+						NodeName: renderer_testing.MasterLabel,
+					},
+				},
+				Ports: []*epmodel.EndpointSubset_EndpointPort{
+					{
+						Name:     "http",
+						Port:     8080,
+						Protocol: "TCP",
+					},
+					{
+						Name:     "dns",
+						Port:     10053,
+						Protocol: "UDP",
+					},
+				},
+			},
+		},
+	}
+	updateEv1 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv1)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	updateEv2 := data.Datasync.PutEvent(epmodel.Key(eps1.Name, eps1.Namespace), eps1)
+	Expect(data.SVCProcessor.Update(updateEv2)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// -> every port gets its own static mapping with its own affinity
+	_, affinity := getMappingProbabilities(data.natPlugin, "10.96.0.1", 80)
+	Expect(affinity).To(BeEquivalentTo(600))
+	_, affinity = getMappingProbabilities(data.natPlugin, "10.96.0.1", 53)
+	Expect(affinity).To(BeEquivalentTo(10800))
+
+	// Enable affinity only by annotations, for all ports, keyed on a client /24
+	// (NAT44 keys the affinity on the full client address).
+	service1.SessionAffinity = "None"
+	service1.SessionAffinityTimeout = 0
+	service1.Annotations = map[string]string{
+		"contivpp.io/session-affinity-timeout":       "300",
+		"contivpp.io/session-affinity-prefix-length": "24",
+	}
+	updateEv3 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv3)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	probabilities, affinity := getMappingProbabilities(data.natPlugin, "10.96.0.1", 80)
+	Expect(probabilities).To(HaveLen(2))
+	Expect(affinity).To(BeEquivalentTo(300))
+	_, affinity = getMappingProbabilities(data.natPlugin, "10.96.0.1", 53)
+	Expect(affinity).To(BeEquivalentTo(300))
+
+	// Invalid values are ignored.
+	service1.Annotations = map[string]string{
+		"contivpp.io/session-affinity-timeout": "http=0,dns=86401,default=x",
+	}
+	updateEv4 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv4)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	_, affinity = getMappingProbabilities(data.natPlugin, "10.96.0.1", 80)
+	Expect(affinity).To(BeZero())
+	_, affinity = getMappingProbabilities(data.natPlugin, "10.96.0.1", 53)
+	Expect(affinity).To(BeZero())
+
+	// Cleanup
+	Expect(data.SVCProcessor.Close()).To(BeNil())
+	Expect(data.renderer.Close()).To(BeNil())
+}
+
+func TestTopologyAwareRouting(t *testing.T) {
+	RegisterTestingT(t)
+	const localEndpointWeight uint8 = 1
+	config := defaultConfig(false)
+	data := initTest("TestTopologyAwareRouting", config, localEndpointWeight, false)
+
+	// Put master and worker into the same zone, worker2 into another zone.
+	const worker2Label = "worker2"
+	pod4IP := net.ParseIP("10.2.1.2")
+	zoneLabels := func(zone string) map[string]string {
+		return map[string]string{nodesync.ZoneLabel: zone}
+	}
+	data.NodeSync.UpdateNode(&nodesync.Node{
+		Name:            renderer_testing.MasterLabel,
+		ID:              renderer_testing.MasterID,
+		VppIPAddresses:  contivconf.IPsWithNetworks{{Address: nodeIPAddr, Network: nodeIPNet}},
+		MgmtIPAddresses: []net.IP{mgmtIP},
+		TopologyLabels:  zoneLabels("zone-a"),
+	})
+	data.NodeSync.UpdateNode(&nodesync.Node{
+		Name:            renderer_testing.WorkerLabel,
+		ID:              renderer_testing.WorkerID,
+		VppIPAddresses:  contivconf.IPsWithNetworks{{Address: workerIPAddr, Network: workerIPNet}},
+		MgmtIPAddresses: []net.IP{workerMgmtIP},
+		TopologyLabels:  zoneLabels("zone-a"),
+	})
+	data.NodeSync.UpdateNode(&nodesync.Node{
+		Name:           worker2Label,
+		ID:             3,
+		TopologyLabels: zoneLabels("zone-b"),
+	})
+
+	// Test resync with empty VPP configuration.
+	resyncEv, _ := data.Datasync.ResyncEvent(keyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// Add pods.
+	updateEv1 := data.PodManager.AddPod(&podmanager.LocalPod{ID: renderer_testing.Pod1})
+	Expect(data.SVCProcessor.Update(updateEv1)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// Add topology-aware service with backends on this node, in the same zone and in another zone.
+	service1 := &svcmodel.Service{
+		Name:                  "service1",
+		Namespace:             renderer_testing.Namespace1,
+		ClusterIp:             "10.96.0.1",
+		ExternalTrafficPolicy: "Cluster",
+		Annotations:           map[string]string{"contivpp.io/topology-aware": "true"},
+		Port: []*svcmodel.Service_ServicePort{
+			{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     80,
+			},
+		},
+	}
+	endpoint := func(ip net.IP, node string) *epmodel.EndpointSubset_EndpointAddress {
+		return &epmodel.EndpointSubset_EndpointAddress{Ip: ip.String(), NodeName: node}
+	}
+	eps1 := &epmodel.Endpoints{
+		Name:      "service1",
+		Namespace: renderer_testing.Namespace1,
+		EndpointSubsets: []*epmodel.EndpointSubset{
+			{
+				Addresses: []*epmodel.EndpointSubset_EndpointAddress{
+					endpoint(pod1IP, renderer_testing.MasterLabel),
+					endpoint(pod3IP, renderer_testing.WorkerLabel),
+					endpoint(pod4IP, worker2Label),
+				},
+				Ports: []*epmodel.EndpointSubset_EndpointPort{
+					{
+						Name:     "http",
+						Port:     8080,
+						Protocol: "TCP",
+					},
+				},
+			},
+		},
+	}
+	updateEv2 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv2)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	updateEv3 := data.Datasync.PutEvent(epmodel.Key(eps1.Name, eps1.Namespace), eps1)
+	Expect(data.SVCProcessor.Update(updateEv3)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	backendIPs := func() []string {
+		probabilities, _ := getMappingProbabilities(data.natPlugin, "10.96.0.1", 80)
+		var ips []string
+		for ip := range probabilities {
+			ips = append(ips, ip)
+		}
+		return ips
+	}
+
+	// -> only the node-local backend is used
+	Expect(backendIPs()).To(ConsistOf(pod1IP.String()))
+
+	// Remove the local backend -> fallback to the same zone.
+	eps1.EndpointSubsets[0].Addresses = eps1.EndpointSubsets[0].Addresses[1:]
+	updateEv4 := data.Datasync.PutEvent(epmodel.Key(eps1.Name, eps1.Namespace), eps1)
+	Expect(data.SVCProcessor.Update(updateEv4)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(backendIPs()).To(ConsistOf(pod3IP.String()))
+
+	// Move worker into another zone -> fallback to the whole cluster.
+	updateEv5 := data.NodeSync.UpdateNode(&nodesync.Node{
+		Name:            renderer_testing.WorkerLabel,
+		ID:              renderer_testing.WorkerID,
+		VppIPAddresses:  contivconf.IPsWithNetworks{{Address: workerIPAddr, Network: workerIPNet}},
+		MgmtIPAddresses: []net.IP{workerMgmtIP},
+		TopologyLabels:  zoneLabels("zone-c"),
+	})
+	Expect(data.SVCProcessor.Update(updateEv5)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(backendIPs()).To(ConsistOf(pod3IP.String(), pod4IP.String()))
+
+	// Disable topology-aware routing and add the local backend back -> all backends are used.
+	service1.Annotations = nil
+	updateEv6 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv6)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	eps1.EndpointSubsets[0].Addresses = append(eps1.EndpointSubsets[0].Addresses,
+		endpoint(pod1IP, renderer_testing.MasterLabel))
+	updateEv7 := data.Datasync.PutEvent(epmodel.Key(eps1.Name, eps1.Namespace), eps1)
+	Expect(data.SVCProcessor.Update(updateEv7)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(backendIPs()).To(ConsistOf(pod1IP.String(), pod3IP.String(), pod4IP.String()))
+
+	// Cleanup
+	Expect(data.SVCProcessor.Close()).To(BeNil())
+	Expect(data.renderer.Close()).To(BeNil())
+}
+
+func TestSCTPServicePort(t *testing.T) {
+	RegisterTestingT(t)
+	const localEndpointWeight uint8 = 1
+	data := initTest("TestSCTPServicePort", defaultConfig(false), localEndpointWeight, false)
+
+	resyncEv, _ := data.Datasync.ResyncEvent(keyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// Add two services with SCTP ports, both served by the same pod.
+	service1 := &svcmodel.Service{
+		Name:                  "service1",
+		Namespace:             renderer_testing.Namespace1,
+		ServiceType:           "NodePort",
+		ClusterIp:             "10.96.0.1",
+		ExternalTrafficPolicy: "Cluster",
+		Port: []*svcmodel.Service_ServicePort{
+			{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     80,
+			},
+			{
+				Name:     "diameter",
+				Protocol: "SCTP",
+				Port:     3868,
+				NodePort: 30868,
+			},
+		},
+	}
+	service2 := &svcmodel.Service{
+		Name:                  "service2",
+		Namespace:             renderer_testing.Namespace1,
+		ClusterIp:             "10.96.0.2",
+		ExternalTrafficPolicy: "Cluster",
+		Port: []*svcmodel.Service_ServicePort{
+			{
+				Name:     "sigtran",
+				Protocol: "SCTP",
+				Port:     2905,
+			},
+		},
+	}
+	sctpEndpoints := func(name string, ports ...*epmodel.EndpointSubset_EndpointPort) *epmodel.Endpoints {
+		return &epmodel.Endpoints{
+			Name:      name,
+			Namespace: renderer_testing.Namespace1,
+			EndpointSubsets: []*epmodel.EndpointSubset{
+				{
+					Addresses: []*epmodel.EndpointSubset_EndpointAddress{
+						{
+							Ip:       pod1IP.String(),
+							NodeName: renderer_testing.MasterLabel,
+						},
+					},
+					Ports: ports,
+				},
+			},
+		}
+	}
+	eps1 := sctpEndpoints("service1",
+		&epmodel.EndpointSubset_EndpointPort{Name: "http", Port: 8080, Protocol: "TCP"},
+		&epmodel.EndpointSubset_EndpointPort{Name: "diameter", Port: 3868, Protocol: "SCTP"})
+	eps2 := sctpEndpoints("service2",
+		&epmodel.EndpointSubset_EndpointPort{Name: "sigtran", Port: 2905, Protocol: "SCTP"})
+
+	updateEv1 := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv1)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	updateEv2 := data.Datasync.PutEvent(epmodel.Key(eps1.Name, eps1.Namespace), eps1)
+	Expect(data.SVCProcessor.Update(updateEv2)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	updateEv3 := data.Datasync.PutEvent(svcmodel.Key(service2.Name, service2.Namespace), service2)
+	Expect(data.SVCProcessor.Update(updateEv3)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	updateEv4 := data.Datasync.PutEvent(epmodel.Key(eps2.Name, eps2.Namespace), eps2)
+	Expect(data.SVCProcessor.Update(updateEv4)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// The TCP port is exposed by a port mapping.
+	probabilities, _ := getMappingProbabilities(data.natPlugin, "10.96.0.1", 80)
+	Expect(probabilities).To(HaveKey(pod1IP.String()))
+
+	// SCTP ports are not exposed at all - every mapping translates a port:
+	//  - the backend's own traffic is not SNATed to a service IP (no bidirectional 1:1 NAT),
+	//  - traffic to other ports of the cluster IPs is not captured,
+	//  - the pod serving both services is not mapped twice (no colliding mappings).
+	var backendMappings int
+	for _, dnat := range data.natPlugin.DumpNat44DNat() {
+		for _, mapping := range dnat.StMappings {
+			Expect(mapping.ExternalPort).ToNot(BeZero())
+			Expect(mapping.ExternalPort).ToNot(BeEquivalentTo(30868))
+			Expect(mapping.ExternalIp).ToNot(Equal("10.96.0.2"))
+			for _, local := range mapping.LocalIps {
+				Expect(local.LocalPort).ToNot(BeZero())
+				if local.LocalIp == pod1IP.String() {
+					backendMappings++
+				}
+			}
+		}
+	}
+	Expect(backendMappings).To(Equal(1))
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(1))
+
+	// The SCTP ports are rejected with an explicit error of each service.
+	contivSvc1 := &svc_renderer.ContivService{
+		ID: svcmodel.ID{Name: service1.Name, Namespace: service1.Namespace},
+	}
+	contivSvc2 := &svc_renderer.ContivService{
+		ID: svcmodel.ID{Name: service2.Name, Namespace: service2.Namespace},
+	}
+	svcErrors := data.renderer.GetServiceErrors(contivSvc1)
+	Expect(svcErrors).To(HaveLen(1))
+	Expect(svcErrors[0]).To(ContainSubstring("port diameter"))
+	Expect(svcErrors[0]).To(ContainSubstring("SCTP is not supported by NAT44"))
+	svcErrors = data.renderer.GetServiceErrors(contivSvc2)
+	Expect(svcErrors).To(HaveLen(1))
+	Expect(svcErrors[0]).To(ContainSubstring("port sigtran"))
+
+	// Services removed - errors are forgotten.
+	updateEv5 := data.Datasync.DeleteEvent(svcmodel.Key(service1.Name, service1.Namespace))
+	Expect(data.SVCProcessor.Update(updateEv5)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	updateEv6 := data.Datasync.DeleteEvent(svcmodel.Key(service2.Name, service2.Namespace))
+	Expect(data.SVCProcessor.Update(updateEv6)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.renderer.GetServiceErrors(contivSvc1)).To(BeEmpty())
+	Expect(data.renderer.GetServiceErrors(contivSvc2)).To(BeEmpty())
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(0))
+
+	// Cleanup
+	Expect(data.SVCProcessor.Close()).To(BeNil())
+	Expect(data.renderer.Close()).To(BeNil())
+}
+
+func TestEgressGateway(t *testing.T) {
+	RegisterTestingT(t)
+	const (
+		localEndpointWeight uint8 = 1
+		egressGwVrf               = 10
+		egressGwLoop              = "egress-gw-gw1"
+	)
+	config := defaultConfig(false)
+	data := initTest("TestEgressGateway", config, localEndpointWeight, false)
+	data.IPNet.SetNetworkVrfID("_egress-gateway-gw1", egressGwVrf)
+
+	// remote pod deployed on the worker
+	remotePodIP := net.ParseIP("10.1.2.2")
+	data.NodeSync.UpdateNode(&nodesync.Node{
+		Name:           renderer_testing.WorkerLabel,
+		ID:             renderer_testing.WorkerID,
+		VppIPAddresses: contivconf.IPsWithNetworks{{Address: workerIPAddr, Network: workerIPNet}},
+	})
+
+	// Test resync with empty VPP configuration.
+	resyncEv, _ := data.Datasync.ResyncEvent(append(keyPrefixes, egressgwmodel.KeyPrefix())...)
+	Expect(data.SVCProcessor.Resync(resyncEv.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.NumOfEgressGatewayVrfItems()).To(Equal(0))
+
+	// Add pods.
+	webLabels := map[string]string{"app": "web"}
+	data.PodManager.AddRemotePod(&podmanager.Pod{
+		ID: renderer_testing.Pod1, IPAddress: pod1IP.String(), Labels: webLabels})
+	data.PodManager.AddRemotePod(&podmanager.Pod{
+		ID: renderer_testing.Pod2, IPAddress: pod2IP.String(), Labels: map[string]string{"app": "db"}})
+	data.PodManager.AddRemotePod(&podmanager.Pod{
+		ID: renderer_testing.Pod3, IPAddress: remotePodIP.String(), Labels: webLabels})
+	updateEv1 := data.PodManager.AddPod(&podmanager.LocalPod{ID: renderer_testing.Pod1})
+	Expect(data.SVCProcessor.Update(updateEv1)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	updateEv2 := data.PodManager.AddPod(&podmanager.LocalPod{ID: renderer_testing.Pod2})
+	Expect(data.SVCProcessor.Update(updateEv2)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// Add egress gateway on this node selecting web pods, with a single egress IP.
+	egressGw := &egressgwmodel.EgressGateway{
+		Name:        "gw1",
+		PodSelector: webLabels,
+		Node:        renderer_testing.MasterLabel,
+		EgressIps:   []string{"80.80.80.1"},
+	}
+	updateEv3 := data.Datasync.PutEvent(egressgwmodel.Key(egressGw.Name), egressGw)
+	Expect(data.SVCProcessor.Update(updateEv3)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// The egress IP is added into the NAT pool of the gateway VRF, no static mappings
+	// are installed (egress IPs do not accept connections from outside).
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(0))
+	pools := data.natPlugin.GetNat44AddressPools(egressGwVrf)
+	Expect(pools).To(HaveLen(1))
+	Expect(pools[0].FirstIp).To(Equal("80.80.80.1"))
+	Expect(pools[0].LastIp).To(Equal("80.80.80.1"))
+	loop := data.natPlugin.GetInterface(egressGwLoop)
+	Expect(loop).ToNot(BeNil())
+	Expect(loop.Vrf).To(BeEquivalentTo(egressGwVrf))
+	routes := data.natPlugin.GetRoutes(egressGwVrf)
+	Expect(routes).To(HaveLen(2))
+	for _, route := range routes {
+		Expect(route.Type).To(Equal(vpp_l3.Route_INTER_VRF))
+		switch route.DstNetwork {
+		case "0.0.0.0/0":
+			Expect(route.ViaVrfId).To(BeEquivalentTo(renderer_testing.MainVrfID))
+		case "10.1.0.0/16":
+			Expect(route.ViaVrfId).To(BeEquivalentTo(renderer_testing.PodVrfID))
+		default:
+			Fail("unexpected route: " + route.String())
+		}
+	}
+
+	// Both local and remote web pods share the single egress IP - their traffic
+	// is steered into the gateway VRF.
+	abf := data.natPlugin.GetABF(20000 + egressGwVrf)
+	Expect(abf).ToNot(BeNil())
+	Expect(abf.AttachedInterfaces).To(HaveLen(2))
+	Expect(abf.AttachedInterfaces[0].InputInterface).To(Equal(vxlanIfName))
+	Expect(abf.AttachedInterfaces[1].InputInterface).To(Equal(renderer_testing.Pod1If))
+	Expect(abf.ForwardingPaths).To(HaveLen(1))
+	Expect(abf.ForwardingPaths[0].InterfaceName).To(Equal(egressGwLoop))
+	acl := data.natPlugin.GetACL(abf.AclName)
+	Expect(acl).ToNot(BeNil())
+	Expect(hasIPRule(acl, vpp_acl.ACL_Rule_DENY, "0.0.0.0/0", "10.1.0.0/16")).To(BeTrue())
+	Expect(hasIPRule(acl, vpp_acl.ACL_Rule_PERMIT, pod1IP.String()+"/32", "0.0.0.0/0")).To(BeTrue())
+	Expect(hasIPRule(acl, vpp_acl.ACL_Rule_PERMIT, remotePodIP.String()+"/32", "0.0.0.0/0")).To(BeTrue())
+	Expect(hasIPRule(acl, vpp_acl.ACL_Rule_PERMIT, pod2IP.String()+"/32", "0.0.0.0/0")).To(BeFalse())
+	Expect(data.natPlugin.GetABF(10000 + renderer_testing.MasterID)).To(BeNil())
+
+	// Pod2 becomes a web pod - it shares the egress IP as well.
+	data.PodManager.AddRemotePod(&podmanager.Pod{
+		ID: renderer_testing.Pod2, IPAddress: pod2IP.String(), Labels: webLabels})
+	pod2 := &podmodel.Pod{
+		Name:      renderer_testing.Pod2.Name,
+		Namespace: renderer_testing.Pod2.Namespace,
+		IpAddress: pod2IP.String(),
+	}
+	updateEv4 := data.Datasync.PutEvent(podmodel.Key(pod2.Name, pod2.Namespace), pod2)
+	Expect(data.SVCProcessor.Update(updateEv4)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(0))
+	Expect(data.natPlugin.GetNat44AddressPools(egressGwVrf)).To(HaveLen(1))
+	abf = data.natPlugin.GetABF(20000 + egressGwVrf)
+	Expect(abf.AttachedInterfaces).To(HaveLen(3))
+	acl = data.natPlugin.GetACL(abf.AclName)
+	Expect(hasIPRule(acl, vpp_acl.ACL_Rule_PERMIT, pod1IP.String()+"/32", "0.0.0.0/0")).To(BeTrue())
+	Expect(hasIPRule(acl, vpp_acl.ACL_Rule_PERMIT, pod2IP.String()+"/32", "0.0.0.0/0")).To(BeTrue())
+	Expect(hasIPRule(acl, vpp_acl.ACL_Rule_PERMIT, remotePodIP.String()+"/32", "0.0.0.0/0")).To(BeTrue())
+
+	// Move the egress gateway to the worker node.
+	egressGw.Node = renderer_testing.WorkerLabel
+	updateEv5 := data.Datasync.PutEvent(egressgwmodel.Key(egressGw.Name), egressGw)
+	Expect(data.SVCProcessor.Update(updateEv5)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// The gateway VRF is now configured on the worker, traffic of the local
+	// web pods is steered towards the worker.
+	Expect(data.natPlugin.NumOfEgressGatewayVrfItems()).To(Equal(0))
+	Expect(data.natPlugin.GetABF(20000 + egressGwVrf)).To(BeNil())
+	abf = data.natPlugin.GetABF(10000 + renderer_testing.WorkerID)
+	Expect(abf).ToNot(BeNil())
+	Expect(abf.AttachedInterfaces).To(HaveLen(2))
+	Expect(abf.AttachedInterfaces[0].InputInterface).To(Equal(renderer_testing.Pod1If))
+	Expect(abf.AttachedInterfaces[1].InputInterface).To(Equal(renderer_testing.Pod2If))
+	Expect(abf.ForwardingPaths).To(HaveLen(1))
+	Expect(abf.ForwardingPaths[0].NextHopIp).To(Equal("192.168.30.2"))
+	Expect(abf.ForwardingPaths[0].InterfaceName).To(Equal(vxlanIfName))
+	acl = data.natPlugin.GetACL(abf.AclName)
+	Expect(acl).ToNot(BeNil())
+	Expect(acl.Interfaces).To(BeNil())
+	Expect(hasIPRule(acl, vpp_acl.ACL_Rule_DENY, "0.0.0.0/0", "10.1.0.0/16")).To(BeTrue())
+	Expect(hasIPRule(acl, vpp_acl.ACL_Rule_DENY, "0.0.0.0/0", "10.96.0.0/12")).To(BeTrue())
+	Expect(hasIPRule(acl, vpp_acl.ACL_Rule_DENY, "0.0.0.0/0", nodeIP.IP.String()+"/32")).To(BeTrue())
+	Expect(hasIPRule(acl, vpp_acl.ACL_Rule_PERMIT, pod2IP.String()+"/32", "0.0.0.0/0")).To(BeTrue())
+	lastRule := acl.Rules[len(acl.Rules)-1]
+	Expect(lastRule.Action).To(Equal(vpp_acl.ACL_Rule_PERMIT))
+
+	// Resync with the egress gateway.
+	resyncEv2, _ := data.Datasync.ResyncEvent(append(keyPrefixes, egressgwmodel.KeyPrefix())...)
+	Expect(data.SVCProcessor.Resync(resyncEv2.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.NumOfStaticMappings()).To(Equal(0))
+	Expect(data.natPlugin.NumOfEgressGatewayVrfItems()).To(Equal(0))
+	abf = data.natPlugin.GetABF(10000 + renderer_testing.WorkerID)
+	Expect(abf).ToNot(BeNil())
+	Expect(data.natPlugin.GetACL(abf.AclName)).ToNot(BeNil())
+
+	// Remove the egress gateway.
+	updateEv6 := data.Datasync.DeleteEvent(egressgwmodel.Key(egressGw.Name))
+	Expect(data.SVCProcessor.Update(updateEv6)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.natPlugin.GetABF(10000 + renderer_testing.WorkerID)).To(BeNil())
+	Expect(data.natPlugin.GetACL(abf.AclName)).To(BeNil())
+
+	// Cleanup
+	Expect(data.SVCProcessor.Close()).To(BeNil())
+	Expect(data.renderer.Close()).To(BeNil())
+}
+
+// hasIPRule returns true if the ACL contains L3-only rule with the given action,
+// source and destination network.
+func hasIPRule(acl *vpp_acl.ACL, action vpp_acl.ACL_Rule_Action, srcNet, dstNet string) bool {
+	for _, rule := range acl.Rules {
+		if rule.Action != action || rule.IpRule == nil || rule.IpRule.Ip == nil {
+			continue
+		}
+		if rule.IpRule.Ip.SourceNetwork == srcNet && rule.IpRule.Ip.DestinationNetwork == dstNet {
+			return true
+		}
+	}
+	return false
+}
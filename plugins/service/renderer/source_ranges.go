@@ -0,0 +1,133 @@
+/*
+ * // Copyright (c) 2018 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+package renderer
+
+import (
+	"fmt"
+	"net"
+	"sort"
+)
+
+// RestrictedFrontend is a frontend of a service (LB ingress IP or node IP with
+// the node port) accessible only from the loadBalancerSourceRanges of the service.
+type RestrictedFrontend struct {
+	IP           net.IP
+	Protocol     ProtocolType
+	Port         uint16
+	SourceRanges []*net.IPNet
+}
+
+// String converts RestrictedFrontend into a human-readable string.
+func (rf RestrictedFrontend) String() string {
+	return fmt.Sprintf("RestrictedFrontend <%s:%d/%s SourceRanges:%v>",
+		rf.IP, rf.Port, rf.Protocol, rf.SourceRanges)
+}
+
+// LBSourceRanges keeps track of services with loadBalancerSourceRanges on behalf
+// of the service renderers.
+// The renderers do not enforce the source ranges themselves - VPP evaluates ACLs
+// applied on the same interface in the first-match order, which an ACL installed
+// independently of the policy ACLs cannot rely on. The restricted frontends are
+// instead exposed by the service plugin and rendered by the policy plugin
+// together with the policies.
+// LBSourceRanges is not thread-safe, access is expected to be synchronized
+// by the service plugin.
+type LBSourceRanges struct {
+	services map[string]*ContivService /* service ID -> service with LB source ranges */
+	nodeIPs  *IPAddresses              /* nil if node ports are not restricted */
+}
+
+// NewLBSourceRanges is a constructor for LBSourceRanges.
+func NewLBSourceRanges() *LBSourceRanges {
+	return &LBSourceRanges{
+		services: make(map[string]*ContivService),
+	}
+}
+
+// Update updates the set of services with loadBalancerSourceRanges.
+func (sr *LBSourceRanges) Update(service *ContivService, removed bool) {
+	svcID := service.ID.String()
+	if removed || !service.HasLBSourceRanges() {
+		delete(sr.services, svcID)
+		return
+	}
+	sr.services[svcID] = service
+}
+
+// SetNodeIPs updates the set of node IPs on which the node ports of the services
+// are exposed (nil if node ports are not restricted).
+func (sr *LBSourceRanges) SetNodeIPs(nodeIPs *IPAddresses) {
+	sr.nodeIPs = nodeIPs
+}
+
+// Resync replaces the set of services with loadBalancerSourceRanges and the set
+// of node IPs (nil if node ports are not restricted).
+func (sr *LBSourceRanges) Resync(services []*ContivService, nodeIPs *IPAddresses) {
+	sr.services = make(map[string]*ContivService)
+	for _, service := range services {
+		sr.Update(service, false)
+	}
+	sr.nodeIPs = nodeIPs
+}
+
+// GetRestrictedFrontends returns the LB ingress IPs with the service ports
+// and the node IPs with the node ports of all services with restricted source
+// ranges. The list is sorted to get the rendered configuration stable.
+// SCTP node ports are not included - SCTP ports cannot be matched by VPP ACLs,
+// restricting SCTP node ports would therefore restrict all SCTP traffic destined
+// to the node.
+func (sr *LBSourceRanges) GetRestrictedFrontends() (frontends []*RestrictedFrontend) {
+	svcIDs := make([]string, 0, len(sr.services))
+	for svcID := range sr.services {
+		svcIDs = append(svcIDs, svcID)
+	}
+	sort.Strings(svcIDs)
+
+	for _, svcID := range svcIDs {
+		service := sr.services[svcID]
+		portNames := make([]string, 0, len(service.Ports))
+		for portName := range service.Ports {
+			portNames = append(portNames, portName)
+		}
+		sort.Strings(portNames)
+
+		for _, portName := range portNames {
+			port := service.Ports[portName]
+			if port.Port != 0 && service.LBIngressIPs != nil {
+				for _, lbIP := range service.LBIngressIPs.List() {
+					frontends = append(frontends, &RestrictedFrontend{
+						IP:           lbIP,
+						Protocol:     port.Protocol,
+						Port:         port.Port,
+						SourceRanges: service.LBSourceRanges,
+					})
+				}
+			}
+			if port.NodePort != 0 && sr.nodeIPs != nil && port.Protocol != SCTP {
+				for _, nodeIP := range sr.nodeIPs.List() {
+					frontends = append(frontends, &RestrictedFrontend{
+						IP:           nodeIP,
+						Protocol:     port.Protocol,
+						Port:         port.NodePort,
+						SourceRanges: service.LBSourceRanges,
+					})
+				}
+			}
+		}
+	}
+	return frontends
+}
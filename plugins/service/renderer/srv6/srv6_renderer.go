@@ -54,6 +54,10 @@ const (
 )
 
 // Renderer implements rendering of services for SRv6 in VPP.
+//
+// Services with loadBalancerSourceRanges are tracked in the shared LBSourceRanges
+// (LB ingress IPs and node ports on all node IPs), access to them is restricted
+// by the policy plugin.
 type Renderer struct {
 	Deps
 
@@ -73,6 +77,7 @@ type Deps struct {
 	ConfigRetriever  controller.ConfigRetriever
 	UpdateTxnFactory func(change string) (txn controller.UpdateOperations)
 	ResyncTxnFactory func() (txn controller.ResyncOperations)
+	LBSourceRanges   *renderer.LBSourceRanges // services with loadBalancerSourceRanges
 }
 
 // portForward represents a port forward entry from a service port to an application port in a pod.
@@ -87,6 +92,7 @@ type localBackend struct {
 	ip             net.IP
 	portForwards   []*portForward
 	useHostNetwork bool
+	weight         uint32
 }
 
 // remoteBackend holds information about a service backend located on remote node.
@@ -95,6 +101,7 @@ type remoteBackend struct {
 	nodeID         uint32
 	nodeIP         net.IP
 	useHostNetwork bool
+	weight         uint32
 }
 
 type localBackendKey [16]byte  // 16-byte IP
@@ -107,6 +114,9 @@ func (r *Renderer) Init(snatOnly bool) error {
 	r.snatOnly = snatOnly
 	r.policyBSIDs = make(map[string]net.IP)
 	r.backendSIDs = make(map[string]net.IP)
+	if r.LBSourceRanges == nil {
+		r.LBSourceRanges = renderer.NewLBSourceRanges()
+	}
 	return nil
 }
 
@@ -115,6 +125,19 @@ func (r *Renderer) AfterInit() error {
 	return nil
 }
 
+// GetServiceConfig returns SRv6 policies, steerings, localsids and the supporting
+// configuration rendered for the given service.
+func (r *Renderer) GetServiceConfig(service *renderer.ContivService) controller.KeyValuePairs {
+	if r.snatOnly {
+		return nil
+	}
+	addDelConfig, updateConfig := r.renderService(service, serviceAdd, nil)
+	for key, value := range updateConfig {
+		addDelConfig[key] = value
+	}
+	return addDelConfig
+}
+
 // Close deallocates resources held by the renderer.
 func (r *Renderer) Close() error {
 	return nil
@@ -126,12 +149,14 @@ func (r *Renderer) AddService(service *renderer.ContivService) error {
 		return nil
 	}
 
+	r.warnSessionAffinity(service)
 	txn := r.UpdateTxnFactory(fmt.Sprintf("add service '%v'", service.ID))
 
 	addDelConfig, updateConfig := r.renderService(service, serviceAdd, nil)
 	controller.PutAll(txn, addDelConfig)
 	controller.PutAll(txn, updateConfig)
 
+	r.LBSourceRanges.Update(service, false)
 	return nil
 }
 
@@ -141,6 +166,7 @@ func (r *Renderer) UpdateService(oldService, newService *renderer.ContivService,
 		return nil
 	}
 
+	r.warnSessionAffinity(newService)
 	txn := r.UpdateTxnFactory(fmt.Sprintf("update service '%v'", newService.ID))
 
 	addDelConfig, updateConfig := r.renderService(oldService, serviceDel, otherExistingServices)
@@ -151,6 +177,7 @@ func (r *Renderer) UpdateService(oldService, newService *renderer.ContivService,
 	controller.PutAll(txn, addDelConfig)
 	controller.PutAll(txn, updateConfig)
 
+	r.LBSourceRanges.Update(newService, false)
 	return nil
 }
 
@@ -166,12 +193,18 @@ func (r *Renderer) DeleteService(service *renderer.ContivService, otherExistingS
 	controller.DeleteAll(txn, addDelConfig)
 	controller.PutAll(txn, updateConfig)
 
+	r.LBSourceRanges.Update(service, true)
 	return nil
 }
 
 // UpdateNodePortServices is called whenever the set of node IPs in the cluster
-// changes.
+// changes. Node ports are not rendered by this renderer, only the node IPs
+// with restricted node ports are updated.
 func (r *Renderer) UpdateNodePortServices(nodeIPs *renderer.IPAddresses, npServices []*renderer.ContivService) error {
+	if r.snatOnly {
+		return nil
+	}
+	r.LBSourceRanges.SetNodeIPs(nodeIPs)
 	return nil
 }
 
@@ -199,6 +232,7 @@ func (r *Renderer) Resync(resyncEv *renderer.ResyncEventData) error {
 		controller.PutAll(txn, updateConfig)
 	}
 
+	r.LBSourceRanges.Resync(resyncEv.Services, resyncEv.NodeIPs)
 	return nil
 }
 
@@ -304,35 +338,7 @@ func (r *Renderer) renderService(service *renderer.ContivService, oper operation
 	}
 
 	// create Srv6 policy with segment list for each backend (loadbalancing and packet switching part)
-	segmentLists := make([]*vpp_srv6.Policy_SegmentList, 0)
-	for _, localBackend := range localBackends {
-		var segments []string
-		if localBackend.useHostNetwork {
-			segments = []string{r.IPAM.SidForServiceHostLocalsid().String()}
-		} else {
-			segments = []string{r.IPAM.SidForServicePodLocalsid(localBackend.ip).String()}
-		}
-		segmentLists = append(segmentLists,
-			&vpp_srv6.Policy_SegmentList{
-				Weight:   1,
-				Segments: segments,
-			})
-	}
-	if service.TrafficPolicy == renderer.ClusterWide { // use remote backends only if traffic policy allows it
-		for _, remoteBackend := range remoteBackends {
-			segments := []string{r.IPAM.SidForServiceNodeLocalsid(remoteBackend.nodeIP).String()}
-			if remoteBackend.useHostNetwork {
-				segments = append(segments, r.IPAM.SidForServiceHostLocalsid().String())
-			} else {
-				segments = append(segments, r.IPAM.SidForServicePodLocalsid(remoteBackend.ip).String())
-			}
-			segmentLists = append(segmentLists,
-				&vpp_srv6.Policy_SegmentList{
-					Weight:   1,
-					Segments: segments,
-				})
-		}
-	}
+	segmentLists := r.segmentLists(service, localBackends, remoteBackends)
 	policy := &vpp_srv6.Policy{
 		InstallationVrfId: r.ContivConf.GetRoutingConfig().MainVRFID,
 		Bsid:              bsid.String(),
@@ -483,7 +489,7 @@ func (r *Renderer) collectBackendInfo(service *renderer.ContivService) (map[loca
 	remoteBackends := make(map[remoteBackendKey]*remoteBackend, 0)
 
 	for servicePortName, servicePort := range service.Ports {
-		for _, backend := range service.Backends[servicePortName] {
+		for _, backend := range renderer.NearestBackends(service, service.Backends[servicePortName]) {
 			if backend.Local {
 				// collect local backend info
 				if backend.HostNetwork {
@@ -491,10 +497,11 @@ func (r *Renderer) collectBackendInfo(service *renderer.ContivService) (map[loca
 					lb := &localBackend{
 						useHostNetwork: true,
 						ip:             backend.IP,
+						weight:         backend.GetWeight(),
 					}
 					localBackends[lb.Key()] = lb
 				} else {
-					lb := &localBackend{ip: backend.IP}
+					lb := &localBackend{ip: backend.IP, weight: backend.GetWeight()}
 					if servicePort.Port != backend.Port {
 						previousForwards := lb.portForwards
 						if previousLB, exists := localBackends[lb.Key()]; exists {
@@ -534,6 +541,7 @@ func (r *Renderer) collectBackendInfo(service *renderer.ContivService) (map[loca
 						nodeID:         nodeID,
 						nodeIP:         nodeIP,
 						useHostNetwork: backend.HostNetwork,
+						weight:         backend.GetWeight(),
 					}
 					remoteBackends[rb.Key()] = rb
 				}
@@ -543,6 +551,81 @@ func (r *Renderer) collectBackendInfo(service *renderer.ContivService) (map[loca
 	return localBackends, remoteBackends, hasHostNetworkLocalBackend
 }
 
+// segmentLists returns segment lists of the SRv6 policy leading to the given local
+// and remote backends, weighted as requested by the load-balancing mode of the service.
+// VPP hashes flows into buckets of the SRv6 policy proportionally to the weights.
+// With consistent hashing, every entry of the lookup table gets its own segment list
+// (of weight 1) leading to the backend owning the entry, i.e. the number and the order
+// of the segment lists do not depend on the backends. A change in the set of backends
+// then modifies only segment lists of the entries that changed the owner, flows hashed
+// into the other entries stay with their backend.
+func (r *Renderer) segmentLists(service *renderer.ContivService, localBackends map[localBackendKey]*localBackend,
+	remoteBackends map[remoteBackendKey]*remoteBackend) []*vpp_srv6.Policy_SegmentList {
+
+	// collect backends that may receive traffic
+	var (
+		backends []*renderer.ServiceBackend
+		segments [][]string
+	)
+	for _, lb := range localBackends {
+		backends = append(backends, &renderer.ServiceBackend{IP: lb.ip, Local: true, Weight: lb.weight})
+		if lb.useHostNetwork {
+			segments = append(segments, []string{r.IPAM.SidForServiceHostLocalsid().String()})
+		} else {
+			segments = append(segments, []string{r.IPAM.SidForServicePodLocalsid(lb.ip).String()})
+		}
+	}
+	if service.TrafficPolicy == renderer.ClusterWide { // use remote backends only if traffic policy allows it
+		for _, rb := range remoteBackends {
+			backends = append(backends, &renderer.ServiceBackend{IP: rb.ip, Weight: rb.weight})
+			rbSegments := []string{r.IPAM.SidForServiceNodeLocalsid(rb.nodeIP).String()}
+			if rb.useHostNetwork {
+				rbSegments = append(rbSegments, r.IPAM.SidForServiceHostLocalsid().String())
+			} else {
+				rbSegments = append(rbSegments, r.IPAM.SidForServicePodLocalsid(rb.ip).String())
+			}
+			segments = append(segments, rbSegments)
+		}
+	}
+
+	segmentLists := make([]*vpp_srv6.Policy_SegmentList, 0)
+	if service.LBMode == renderer.ConsistentHashLB {
+		for _, backendIdx := range renderer.ConsistentHashTable(backends) {
+			segmentLists = append(segmentLists,
+				&vpp_srv6.Policy_SegmentList{
+					Weight:   1,
+					Segments: segments[backendIdx],
+				})
+		}
+		return segmentLists
+	}
+	weights := renderer.BackendWeights(service.LBMode, backends)
+	for idx := range backends {
+		segmentLists = append(segmentLists,
+			&vpp_srv6.Policy_SegmentList{
+				Weight:   weights[idx],
+				Segments: segments[idx],
+			})
+	}
+	return segmentLists
+}
+
+// warnSessionAffinity logs a warning for every port of the service with client IP
+// based session affinity - SRv6 policies load-balance every flow independently,
+// VPP cannot keep the sessions of a client (or a client prefix) on the same backend.
+func (r *Renderer) warnSessionAffinity(service *renderer.ContivService) {
+	for portName := range service.Ports {
+		if timeout, prefixLen := service.PortSessionAffinity(portName); timeout != 0 {
+			r.Log.WithFields(logging.Fields{
+				"service":   service.ID,
+				"port":      portName,
+				"timeout":   timeout,
+				"prefixLen": prefixLen,
+			}).Warn("Session affinity is not supported by SRv6, flows are load-balanced independently")
+		}
+	}
+}
+
 // getPodPFRuleChain returns the config of the pod-local iptables rule chain of given chain type -
 // At first it is looked up in currentConfig. If it is not found it's
 // retrieved from the controller (if it already exists), or an empty one.
@@ -581,8 +664,11 @@ func (r *Renderer) getPodPFRuleChain(
 // getServicePortForwardRule returns iptables port forward rule for specified service IP and port forward data.
 func (r *Renderer) getServicePortForwardRule(serviceIP net.IP, pf *portForward) string {
 	proto := "tcp"
-	if pf.proto == renderer.UDP {
+	switch pf.proto {
+	case renderer.UDP:
 		proto = "udp"
+	case renderer.SCTP:
+		proto = "sctp"
 	}
 	return fmt.Sprintf("-d %s -p %s -m %s --dport %d -j REDIRECT --to-ports %d",
 		serviceIP.String()+getHostPrefix(serviceIP), proto, proto, pf.from, pf.to)
@@ -35,6 +35,7 @@ import (
 	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
 	svcmodel "github.com/contiv/vpp/plugins/ksr/model/service"
 	"github.com/contiv/vpp/plugins/podmanager"
+	svc_renderer "github.com/contiv/vpp/plugins/service/renderer"
 	"github.com/contiv/vpp/plugins/service/renderer/srv6"
 	renderer_testing "github.com/contiv/vpp/plugins/service/renderer/testing"
 
@@ -177,6 +178,23 @@ func TestBasicService(t *testing.T) {
 	// check not used port forwarding (service port = backend port)
 	Expect(data.ruleChainHandler.RuleChains).To(BeEmpty())
 
+	// check configuration described for the service
+	contivSvcs := data.SVCProcessor.GetContivServices()
+	Expect(contivSvcs).To(HaveLen(1))
+	var describedPolicy *vpp_srv6.Policy
+	var describedLocalSids int
+	for _, value := range data.renderer.GetServiceConfig(contivSvcs[0]) {
+		switch item := value.(type) {
+		case *vpp_srv6.Policy:
+			describedPolicy = item
+		case *vpp_srv6.LocalSID:
+			describedLocalSids++
+		}
+	}
+	Expect(describedPolicy).ToNot(BeNil())
+	Expect(describedPolicy.Bsid).To(Equal(bsid))
+	Expect(describedLocalSids).To(Equal(2))
+
 	// finally remove the service.
 	removeService(data, service1)
 
@@ -288,6 +306,78 @@ func TestServiceWithBackendPortForwarding(t *testing.T) {
 	closeResources(data)
 }
 
+func TestSCTPServiceWithBackendPortForwarding(t *testing.T) {
+	RegisterTestingT(t)
+	retriever := configRetriever.NewMockConfigRetriever()
+	data := initTest("TestSCTPServiceWithBackendPortForwarding", defaultConfig(false), retriever, false)
+
+	// setup SCTP service
+	emptyResync(data)
+	service1 := &svcmodel.Service{
+		Name:                  service1Name,
+		Namespace:             renderer_testing.Namespace1,
+		ServiceType:           "ClusterIP",
+		ExternalTrafficPolicy: "Cluster",
+		ClusterIp:             "2096::eef9",
+		Port: []*svcmodel.Service_ServicePort{
+			{
+				Name:     "diameter",
+				Protocol: "SCTP",
+				Port:     3868,
+			},
+		},
+	}
+	updateEv := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	pod1IP := addLocalPod(renderer_testing.Pod1, data, retriever)
+	eps1 := &epmodel.Endpoints{
+		Name:      service1Name,
+		Namespace: renderer_testing.Namespace1,
+		EndpointSubsets: []*epmodel.EndpointSubset{
+			{
+				Addresses: []*epmodel.EndpointSubset_EndpointAddress{
+					{
+						Ip:       pod1IP.String(),
+						NodeName: renderer_testing.MasterLabel,
+						TargetRef: &epmodel.ObjectReference{
+							Kind:      "Pod",
+							Namespace: renderer_testing.Pod1.Namespace,
+							Name:      renderer_testing.Pod1.Name,
+						},
+					},
+				},
+				Ports: []*epmodel.EndpointSubset_EndpointPort{
+					{
+						Name:     "diameter",
+						Port:     3869,
+						Protocol: "SCTP",
+					},
+				},
+			},
+		},
+	}
+	updateEv = data.Datasync.PutEvent(epmodel.Key(eps1.Name, eps1.Namespace), eps1)
+	Expect(data.SVCProcessor.Update(updateEv)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+
+	// check SRv6 policy with steering towards the backend
+	bsid := assertPolicy(service1, [][]string{{data.IPAM.SidForServicePodLocalsid(pod1IP).String()}}, data)
+	assertSteering(service1, bsid, data)
+
+	// check port forwarding of the SCTP port
+	sctpRule := fmt.Sprintf("-d %s -p sctp -m sctp --dport %d -j REDIRECT --to-ports %d",
+		service1.ClusterIp+"/128", 3868, 3869)
+	Expect(data.ruleChainHandler.RuleChains).To(HaveLen(2))
+	for _, ruleChain := range data.ruleChainHandler.RuleChains {
+		Expect(ruleChain.Rules).To(Equal([]string{sctpRule}))
+	}
+
+	// cleanup
+	removeService(data, service1)
+	closeResources(data)
+}
+
 func TestServiceWithHostLocalBackend(t *testing.T) {
 	RegisterTestingT(t)
 	retriever := configRetriever.NewMockConfigRetriever()
@@ -376,6 +466,190 @@ func TestServiceWithRemoteBackendIPv4(t *testing.T) {
 	closeResources(data)
 }
 
+func TestWeightedService(t *testing.T) {
+	RegisterTestingT(t)
+	retriever := configRetriever.NewMockConfigRetriever()
+	data := initTest("TestWeightedService", defaultConfig(false), retriever, false)
+
+	// setup service with weighted load-balancing, pod1 having weight 4
+	emptyResync(data)
+	service1 := addServiceMetadata(data, service1Name, defaultPort)
+	service1.Annotations = map[string]string{"contivpp.io/lb-mode": "weighted"}
+	updateEv := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	pod1IP := addLocalPod(renderer_testing.Pod1, data, retriever)
+	pod2IP := addLocalPod(renderer_testing.Pod2, data, retriever)
+	data.PodManager.AddRemotePod(&podmanager.Pod{
+		ID:          renderer_testing.Pod1,
+		IPAddress:   pod1IP.String(),
+		Annotations: map[string]string{"contivpp.io/service-weight": "4"},
+	})
+	addServiceEndpoints(service1.Name,
+		[]podEndPoint{
+			newPodEndPoint(renderer_testing.Pod1, pod1IP, renderer_testing.MasterLabel),
+			newPodEndPoint(renderer_testing.Pod2, pod2IP, renderer_testing.MasterLabel),
+		},
+		defaultPort, data,
+	)
+
+	// check weights of segment lists
+	Expect(data.srv6Handler.Policies).To(HaveLen(1))
+	for _, policy := range data.srv6Handler.Policies {
+		Expect(policy.SegmentLists).To(ConsistOf(
+			&vpp_srv6.Policy_SegmentList{
+				Weight:   4,
+				Segments: []string{data.IPAM.SidForServicePodLocalsid(pod1IP).String()},
+			},
+			&vpp_srv6.Policy_SegmentList{
+				Weight:   1,
+				Segments: []string{data.IPAM.SidForServicePodLocalsid(pod2IP).String()},
+			},
+		))
+	}
+
+	// finally remove the service.
+	removeService(data, service1)
+	assertEmptyVPPAgentConfiguration(data)
+
+	// cleanup
+	closeResources(data)
+}
+
+func TestConsistentHashService(t *testing.T) {
+	RegisterTestingT(t)
+	retriever := configRetriever.NewMockConfigRetriever()
+	data := initTest("TestConsistentHashService", defaultConfig(false), retriever, false)
+
+	// setup service with consistent-hash load-balancing
+	emptyResync(data)
+	service1 := addServiceMetadata(data, service1Name, defaultPort)
+	service1.Annotations = map[string]string{"contivpp.io/lb-mode": "consistent-hash"}
+	updateEv := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	pod1IP := addLocalPod(renderer_testing.Pod1, data, retriever)
+	pod2IP := addLocalPod(renderer_testing.Pod2, data, retriever)
+	pod2SID := data.IPAM.SidForServicePodLocalsid(pod2IP).String()
+	allBackends := []podEndPoint{
+		newPodEndPoint(renderer_testing.Pod1, pod1IP, renderer_testing.MasterLabel),
+		newPodEndPoint(renderer_testing.Pod2, pod2IP, renderer_testing.MasterLabel),
+		newPodEndPoint(renderer_testing.Pod3, pod3IP, renderer_testing.WorkerLabel),
+	}
+	addServiceEndpoints(service1.Name, allBackends, defaultPort, data)
+
+	// every entry of the lookup table has its own segment list
+	lookupTable := func() []string {
+		Expect(data.srv6Handler.Policies).To(HaveLen(1))
+		var table []string
+		for _, policy := range data.srv6Handler.Policies {
+			Expect(policy.SegmentLists).To(HaveLen(svc_renderer.ConsistentHashTableSize))
+			for _, sl := range policy.SegmentLists {
+				Expect(sl.Weight).To(BeEquivalentTo(1))
+				table = append(table, strings.Join(sl.Segments, ","))
+			}
+		}
+		return table
+	}
+	table := lookupTable()
+	shares := make(map[string]int)
+	for _, segments := range table {
+		shares[segments]++
+	}
+	Expect(shares).To(HaveLen(3))
+	Expect(shares).To(HaveKey(pod2SID))
+
+	// remove pod2 - entries of pod2 are re-assigned to other backends,
+	// (almost) all the other entries keep their backend
+	addServiceEndpoints(service1.Name, []podEndPoint{allBackends[0], allBackends[2]}, defaultPort, data)
+	var moved int
+	for entry, segments := range lookupTable() {
+		Expect(segments).ToNot(Equal(pod2SID))
+		if table[entry] != pod2SID && segments != table[entry] {
+			moved++
+		}
+	}
+	Expect(moved).To(BeNumerically("<", svc_renderer.ConsistentHashTableSize/20))
+
+	// add pod2 back - the table depends only on the set of backends, not on their order
+	addServiceEndpoints(service1.Name, []podEndPoint{allBackends[2], allBackends[1], allBackends[0]}, defaultPort, data)
+	Expect(lookupTable()).To(Equal(table))
+
+	// finally remove the service.
+	removeService(data, service1)
+	assertEmptyVPPAgentConfiguration(data)
+
+	// cleanup
+	closeResources(data)
+}
+
+func TestLBSourceRanges(t *testing.T) {
+	RegisterTestingT(t)
+	retriever := configRetriever.NewMockConfigRetriever()
+	data := initTest("TestLBSourceRanges", defaultConfig(false), retriever, false)
+
+	emptyResync(data)
+	Expect(data.renderer.LBSourceRanges.GetRestrictedFrontends()).To(BeEmpty())
+
+	// setup LoadBalancer service with restricted source ranges
+	service1 := &svcmodel.Service{
+		Name:                     service1Name,
+		Namespace:                renderer_testing.Namespace1,
+		ServiceType:              "LoadBalancer",
+		ExternalTrafficPolicy:    "Cluster",
+		ClusterIp:                "2096::eef9",
+		LbIngressIps:             []string{"2096::30"},
+		LoadbalancerSourceRanges: []string{"2100::/64"},
+		Port: []*svcmodel.Service_ServicePort{
+			{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     defaultPort,
+				NodePort: 30080,
+			},
+		},
+	}
+	updateEv := data.Datasync.PutEvent(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	Expect(data.SVCProcessor.Update(updateEv)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	pod1IP := addLocalPod(renderer_testing.Pod1, data, retriever)
+	addServiceEndpoints(service1.Name,
+		[]podEndPoint{
+			newPodEndPoint(renderer_testing.Pod1, pod1IP, renderer_testing.MasterLabel),
+		},
+		defaultPort, data,
+	)
+
+	// both the LB ingress IP and the node port (on the node IPs) are restricted
+	frontends := data.renderer.LBSourceRanges.GetRestrictedFrontends()
+	Expect(frontends).To(HaveLen(3))
+	Expect(frontends[0].IP.Equal(net.ParseIP("2096::30"))).To(BeTrue())
+	Expect(frontends[0].Port).To(BeEquivalentTo(defaultPort))
+	Expect(frontends[1].IP.Equal(nodeIP.IP)).To(BeTrue())
+	Expect(frontends[2].IP.Equal(mgmtIP)).To(BeTrue())
+	for _, frontend := range frontends {
+		Expect(frontend.SourceRanges).To(HaveLen(1))
+		Expect(frontend.SourceRanges[0].String()).To(Equal("2100::/64"))
+	}
+	for _, frontend := range frontends[1:] {
+		Expect(frontend.Port).To(BeEquivalentTo(30080))
+	}
+
+	// node ports remain restricted after resync
+	data.Datasync.Put(svcmodel.Key(service1.Name, service1.Namespace), service1)
+	resyncEv, _ := data.Datasync.ResyncEvent(keyPrefixes...)
+	Expect(data.SVCProcessor.Resync(resyncEv.KubeState)).To(BeNil())
+	Expect(data.Txn.Commit()).To(BeNil())
+	Expect(data.renderer.LBSourceRanges.GetRestrictedFrontends()).To(HaveLen(3))
+
+	// finally remove the service.
+	removeService(data, service1)
+	Expect(data.renderer.LBSourceRanges.GetRestrictedFrontends()).To(BeEmpty())
+
+	// cleanup
+	closeResources(data)
+}
+
 func assertEmptyVPPAgentConfiguration(data *data) {
 	Expect(data.srv6Handler.LocalSids).To(BeEmpty())
 	Expect(data.srv6Handler.Policies).To(BeEmpty())
@@ -537,7 +811,7 @@ func addLocalPod(pod podmodel.ID, data *data, retriever *configRetriever.MockCon
 	Expect(data.Txn.Commit()).To(BeNil())
 
 	// allocate IPs for new pods
-	podIP, err := data.IPAM.AllocatePodIP(pod, "", "")
+	podIP, _, err := data.IPAM.AllocatePodIP(pod, "", "")
 	Expect(err).To(BeNil())
 
 	// add mock loop interfaces to pods (SRv6 renderer updates their ip addresses)
@@ -0,0 +1,278 @@
+/*
+ * // Copyright (c) 2018 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+package testing
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/contiv/vpp/mock/ipnet"
+	. "github.com/onsi/gomega"
+
+	"github.com/contiv/vpp/mock/configRetriever"
+	"github.com/contiv/vpp/plugins/contivconf/config"
+	nodeconfigcrd "github.com/contiv/vpp/plugins/crd/pkg/apis/nodeconfig/v1"
+	epmodel "github.com/contiv/vpp/plugins/ksr/model/endpoints"
+	svcmodel "github.com/contiv/vpp/plugins/ksr/model/service"
+	"github.com/contiv/vpp/plugins/podmanager"
+	"github.com/contiv/vpp/plugins/service/renderer"
+	"github.com/contiv/vpp/plugins/service/renderer/ipv6route"
+
+	linux_interfaces "go.ligato.io/vpp-agent/v3/proto/ligato/linux/interfaces"
+	linux_iptables "go.ligato.io/vpp-agent/v3/proto/ligato/linux/iptables"
+)
+
+// recordingRenderer is a service renderer which only remembers the last
+// rendered state of every service.
+type recordingRenderer struct {
+	services map[svcmodel.ID]*renderer.ContivService
+}
+
+func newRecordingRenderer() *recordingRenderer {
+	return &recordingRenderer{services: make(map[svcmodel.ID]*renderer.ContivService)}
+}
+
+func (r *recordingRenderer) AddService(service *renderer.ContivService) error {
+	r.services[service.ID] = service
+	return nil
+}
+
+func (r *recordingRenderer) UpdateService(oldService, newService *renderer.ContivService,
+	otherExistingServices []*renderer.ContivService) error {
+	r.services[newService.ID] = newService
+	return nil
+}
+
+func (r *recordingRenderer) DeleteService(service *renderer.ContivService,
+	otherExistingServices []*renderer.ContivService) error {
+	delete(r.services, service.ID)
+	return nil
+}
+
+func (r *recordingRenderer) UpdateNodePortServices(nodeIPs *renderer.IPAddresses,
+	npServices []*renderer.ContivService) error {
+	return nil
+}
+
+func (r *recordingRenderer) UpdateLocalFrontendIfs(oldIfNames, newIfNames renderer.Interfaces) error {
+	return nil
+}
+
+func (r *recordingRenderer) UpdateLocalBackendIfs(oldIfNames, newIfNames renderer.Interfaces) error {
+	return nil
+}
+
+func (r *recordingRenderer) Resync(resyncEv *renderer.ResyncEventData) error {
+	r.services = make(map[svcmodel.ID]*renderer.ContivService)
+	for _, service := range resyncEv.Services {
+		r.services[service.ID] = service
+	}
+	return nil
+}
+
+// newProtocolsFixture prepares a fixture for the given configuration
+// with the service processor passing services to the returned recorder.
+func newProtocolsFixture(testName string, cfg *config.Config, nodeIP string, mgmtIP net.IP) (
+	*Fixture, *recordingRenderer) {
+
+	nodeIPNet, nodeIPAddr, nodeNetwork := IPNet(nodeIP)
+	cfg.NodeConfig = []config.NodeConfig{
+		{
+			NodeName: MasterLabel,
+			NodeConfigSpec: nodeconfigcrd.NodeConfigSpec{
+				MainVPPInterface: nodeconfigcrd.InterfaceConfig{
+					InterfaceName: "GbE",
+					IP:            nodeIPNet.String(),
+				},
+			},
+		},
+	}
+	ipNet := NewMockIPNet()
+	ipNet.SetNodeIP(nodeIPNet)
+	ipNet.SetHostIPs([]net.IP{mgmtIP})
+	ipNet.SetPodIfName(Pod1, Pod1If)
+	fixture := NewFixture(testName, cfg, ipNet, nodeIPAddr, nodeNetwork, mgmtIP)
+
+	recorder := newRecordingRenderer()
+	Expect(fixture.SVCProcessor.RegisterRenderer(recorder)).To(BeNil())
+
+	resyncEv, _ := fixture.Datasync.ResyncEvent(epmodel.KeyPrefix(), svcmodel.KeyPrefix())
+	Expect(fixture.SVCProcessor.Resync(resyncEv.KubeState)).To(BeNil())
+	return fixture, recorder
+}
+
+// processServicePorts adds a service exposing ports of all supported protocols
+// + one unsupported, backed by Pod1 with the given IP, and returns the service
+// as passed to renderers.
+func processServicePorts(fixture *Fixture, recorder *recordingRenderer, clusterIP string,
+	backendIP net.IP) *renderer.ContivService {
+
+	service := &svcmodel.Service{
+		Name:      "service1",
+		Namespace: Namespace1,
+		ClusterIp: clusterIP,
+		Port: []*svcmodel.Service_ServicePort{
+			{Name: "http", Protocol: "TCP", Port: 80},
+			{Name: "dns", Protocol: "UDP", Port: 53},
+			{Name: "diameter", Protocol: "SCTP", Port: 3868},
+			{Name: "default", Port: 8000}, // protocol defaults to TCP
+			{Name: "unknown", Protocol: "DCCP", Port: 5004},
+		},
+	}
+	updateEv := fixture.Datasync.PutEvent(svcmodel.Key(service.Name, service.Namespace), service)
+	Expect(fixture.SVCProcessor.Update(updateEv)).To(BeNil())
+
+	eps := &epmodel.Endpoints{
+		Name:      "service1",
+		Namespace: Namespace1,
+		EndpointSubsets: []*epmodel.EndpointSubset{
+			{
+				Addresses: []*epmodel.EndpointSubset_EndpointAddress{
+					{
+						Ip:       backendIP.String(),
+						NodeName: MasterLabel,
+						TargetRef: &epmodel.ObjectReference{
+							Kind:      "Pod",
+							Namespace: Pod1.Namespace,
+							Name:      Pod1.Name,
+						},
+					},
+				},
+				Ports: []*epmodel.EndpointSubset_EndpointPort{
+					{Name: "http", Protocol: "TCP", Port: 8080},
+					{Name: "dns", Protocol: "UDP", Port: 5353},
+					{Name: "diameter", Protocol: "SCTP", Port: 3869},
+					{Name: "default", Protocol: "TCP", Port: 8000},
+					{Name: "unknown", Protocol: "DCCP", Port: 5004},
+				},
+			},
+		},
+	}
+	updateEv = fixture.Datasync.PutEvent(epmodel.Key(eps.Name, eps.Namespace), eps)
+	Expect(fixture.SVCProcessor.Update(updateEv)).To(BeNil())
+
+	contivSvc := recorder.services[svcmodel.ID{Name: "service1", Namespace: Namespace1}]
+	Expect(contivSvc).ToNot(BeNil())
+	return contivSvc
+}
+
+func TestServicePortProtocols(t *testing.T) {
+	RegisterTestingT(t)
+
+	cfg := &config.Config{
+		RoutingConfig: config.RoutingConfig{
+			MainVRFID:          MainVrfID,
+			PodVRFID:           PodVrfID,
+			UseSRv6ForServices: true,
+		},
+	}
+	fixture, recorder := newProtocolsFixture("TestServicePortProtocols", cfg,
+		"192.168.16.10/24", net.ParseIP("172.30.1.1"))
+	contivSvc := processServicePorts(fixture, recorder, "10.96.0.1", net.ParseIP("10.1.1.3"))
+
+	Expect(contivSvc.Ports).To(HaveLen(4))
+	Expect(contivSvc.Ports).To(HaveKeyWithValue("http", &renderer.ServicePort{Protocol: renderer.TCP, Port: 80}))
+	Expect(contivSvc.Ports).To(HaveKeyWithValue("dns", &renderer.ServicePort{Protocol: renderer.UDP, Port: 53}))
+	Expect(contivSvc.Ports).To(HaveKeyWithValue("diameter", &renderer.ServicePort{Protocol: renderer.SCTP, Port: 3868}))
+	Expect(contivSvc.Ports).To(HaveKeyWithValue("default", &renderer.ServicePort{Protocol: renderer.TCP, Port: 8000}))
+	Expect(contivSvc.Ports).ToNot(HaveKey("unknown"))
+
+	// SCTP backends are collected just like TCP and UDP backends.
+	Expect(contivSvc.Backends["diameter"]).To(HaveLen(1))
+	Expect(contivSvc.Backends["diameter"][0].IP.String()).To(Equal("10.1.1.3"))
+	Expect(contivSvc.Backends["diameter"][0].Port).To(BeEquivalentTo(3869))
+	Expect(contivSvc.Backends).ToNot(HaveKey("unknown"))
+	Expect(renderer.SCTP.String()).To(Equal("SCTP"))
+}
+
+func TestSCTPServicePortWithNAT44(t *testing.T) {
+	RegisterTestingT(t)
+
+	// services are rendered by NAT44 - SCTP ports are passed to the renderer,
+	// which rejects them (VPP-NAT cannot translate SCTP ports)
+	cfg := &config.Config{
+		RoutingConfig: config.RoutingConfig{
+			MainVRFID: MainVrfID,
+			PodVRFID:  PodVrfID,
+		},
+	}
+	fixture, recorder := newProtocolsFixture("TestSCTPServicePortWithNAT44", cfg,
+		"192.168.16.10/24", net.ParseIP("172.30.1.1"))
+	contivSvc := processServicePorts(fixture, recorder, "10.96.0.1", net.ParseIP("10.1.1.3"))
+
+	Expect(contivSvc.Ports).To(HaveLen(4))
+	Expect(contivSvc.Ports).To(HaveKey("http"))
+	Expect(contivSvc.Ports).To(HaveKey("dns"))
+	Expect(contivSvc.Ports).To(HaveKey("default"))
+	Expect(contivSvc.Ports).To(HaveKeyWithValue("diameter", &renderer.ServicePort{Protocol: renderer.SCTP, Port: 3868}))
+	Expect(contivSvc.Backends).To(HaveKey("diameter"))
+}
+
+func TestSCTPServicePortWithIPv6RouteRenderer(t *testing.T) {
+	RegisterTestingT(t)
+
+	cfg := &config.Config{
+		RoutingConfig: config.RoutingConfig{
+			MainVRFID: MainVrfID,
+			PodVRFID:  PodVrfID,
+		},
+		IPAMConfig: config.IPAMConfig{
+			PodSubnetCIDR:                 "2001::/48",
+			PodSubnetOneNodePrefixLen:     64,
+			VPPHostSubnetCIDR:             "2002::/64",
+			VPPHostSubnetOneNodePrefixLen: 112,
+			ServiceCIDR:                   "2096::/110",
+			NodeInterconnectCIDR:          "fe10:f00d::/90",
+		},
+	}
+	fixture, recorder := newProtocolsFixture("TestSCTPServicePortWithIPv6RouteRenderer", cfg,
+		"2005::16:10/112", net.ParseIP("2002::1:1"))
+	retriever := configRetriever.NewMockConfigRetriever()
+	rndr := &ipv6route.Renderer{
+		Deps: ipv6route.Deps{
+			Log:             fixture.Logger,
+			ContivConf:      fixture.ContivConf,
+			NodeSync:        fixture.NodeSync,
+			PodManager:      fixture.PodManager,
+			IPAM:            fixture.IPAM,
+			IPNet:           fixture.IPNet,
+			ConfigRetriever: retriever,
+		},
+	}
+	Expect(rndr.Init(false)).To(BeNil())
+
+	// local backend pod with loopback interface (service IPs are assigned to it)
+	fixture.PodManager.AddPod(&podmanager.LocalPod{ID: Pod1, ContainerID: "container1"})
+	podIP, _, err := fixture.IPAM.AllocatePodIP(Pod1, "", "")
+	Expect(err).To(BeNil())
+	retriever.AddConfig(linux_interfaces.InterfaceKey(fixture.IPNet.GetPodLoopIfName(Pod1.Namespace, Pod1.Name)),
+		&linux_interfaces.Interface{})
+
+	contivSvc := processServicePorts(fixture, recorder, "2096::1", podIP)
+	Expect(contivSvc.Ports).To(HaveKeyWithValue("diameter", &renderer.ServicePort{Protocol: renderer.SCTP, Port: 3868}))
+
+	// the SCTP service port is forwarded to the backend port inside the pod
+	sctpRule := "-d 2096::1/128 -p sctp -m sctp --dport 3868 -j REDIRECT --to-ports 3869"
+	var ruleChains int
+	for _, value := range rndr.GetServiceConfig(contivSvc) {
+		if ruleChain, isRuleChain := value.(*linux_iptables.RuleChain); isRuleChain {
+			Expect(ruleChain.Rules).To(ContainElement(sctpRule))
+			ruleChains++
+		}
+	}
+	Expect(ruleChains).To(Equal(2)) // PREROUTING + OUTPUT
+}
@@ -0,0 +1,69 @@
+/*
+ * // Copyright (c) 2018 Cisco and/or its affiliates.
+ * //
+ * // Licensed under the Apache License, Version 2.0 (the "License");
+ * // you may not use this file except in compliance with the License.
+ * // You may obtain a copy of the License at:
+ * //
+ * //     http://www.apache.org/licenses/LICENSE-2.0
+ * //
+ * // Unless required by applicable law or agreed to in writing, software
+ * // distributed under the License is distributed on an "AS IS" BASIS,
+ * // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * // See the License for the specific language governing permissions and
+ * // limitations under the License.
+ */
+
+package renderer
+
+// TopologyTier expresses how close (topologically) the backend is to this node.
+// Higher tier means nearer backend.
+type TopologyTier int
+
+const (
+	// ClusterTier contains backends deployed in other zones or on nodes with unknown zone.
+	ClusterTier TopologyTier = 0
+
+	// ZoneTier contains backends deployed on other nodes of the same zone as this node.
+	ZoneTier TopologyTier = 1
+
+	// NodeTier contains backends deployed on this node.
+	NodeTier TopologyTier = 2
+)
+
+// String converts TopologyTier into a human-readable string.
+func (tt TopologyTier) String() string {
+	switch tt {
+	case ClusterTier:
+		return "cluster"
+	case ZoneTier:
+		return "zone"
+	case NodeTier:
+		return "node"
+	}
+	return "INVALID"
+}
+
+// NearestBackends returns the subset of the given backends of the service
+// that the traffic should be routed to.
+// For topology-aware services, only backends from the nearest non-empty tier
+// are returned, falling back to more distant tiers only if nearer tiers have
+// no (ready) endpoints. Otherwise the input is returned unchanged.
+func NearestBackends(service *ContivService, backends []*ServiceBackend) []*ServiceBackend {
+	if !service.TopologyAware || len(backends) == 0 {
+		return backends
+	}
+	nearestTier := ClusterTier
+	for _, backend := range backends {
+		if backend.Tier > nearestTier {
+			nearestTier = backend.Tier
+		}
+	}
+	var nearest []*ServiceBackend
+	for _, backend := range backends {
+		if backend.Tier == nearestTier {
+			nearest = append(nearest, backend)
+		}
+	}
+	return nearest
+}
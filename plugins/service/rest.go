@@ -0,0 +1,152 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/unrolled/render"
+
+	"github.com/contiv/vpp/plugins/service/renderer"
+	"github.com/contiv/vpp/plugins/service/restapi"
+)
+
+// registerRESTHandlers registers REST handlers exposing the state of services.
+func (p *Plugin) registerRESTHandlers() {
+	if p.HTTPHandlers == nil {
+		p.Log.Warnf("No http handler provided, skipping registration of service REST handlers")
+		return
+	}
+
+	p.HTTPHandlers.RegisterHTTPHandler(restapi.RestURLServices, p.servicesGetHandler, "GET")
+	p.Log.Infof("Services REST handler registered: GET %v", restapi.RestURLServices)
+}
+
+func (p *Plugin) servicesGetHandler(formatter *render.Render) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		p.Log.Debug("Getting state of services")
+
+		// optional filtering by service
+		svcNamespace := req.URL.Query().Get(restapi.ServiceNamespaceParam)
+		svcName := req.URL.Query().Get(restapi.ServiceNameParam)
+
+		p.stateLock.Lock()
+		defer p.stateLock.Unlock()
+
+		output := restapi.NodeServices{
+			NodeName:    p.ServiceLabel.GetAgentLabel(),
+			FrontendIfs: []string{},
+			BackendIfs:  []string{},
+			Services:    []*restapi.Service{},
+		}
+		frontendIfs, backendIfs := p.processor.GetLocalInterfaces()
+		for ifName := range frontendIfs {
+			output.FrontendIfs = append(output.FrontendIfs, ifName)
+		}
+		for ifName := range backendIfs {
+			output.BackendIfs = append(output.BackendIfs, ifName)
+		}
+		sort.Strings(output.FrontendIfs)
+		sort.Strings(output.BackendIfs)
+
+		for _, service := range p.processor.GetContivServices() {
+			if (svcNamespace != "" && service.ID.Namespace != svcNamespace) ||
+				(svcName != "" && service.ID.Name != svcName) {
+				continue
+			}
+			output.Services = append(output.Services, p.restService(service))
+		}
+		formatter.JSON(w, http.StatusOK, output)
+	}
+}
+
+// restService converts service into its REST representation, including
+// the configuration rendered for the service by the active renderers.
+func (p *Plugin) restService(service *renderer.ContivService) *restapi.Service {
+	restSvc := &restapi.Service{
+		ID:            service.ID.String(),
+		TrafficPolicy: service.TrafficPolicy.String(),
+		LBMode:        service.LBMode.String(),
+		ClusterIPs:    ipStrings(service.ClusterIPs),
+		ExternalIPs:   ipStrings(service.ExternalIPs),
+		LBIngressIPs:  ipStrings(service.LBIngressIPs),
+		Ports:         []*restapi.ServicePort{},
+		Config:        []*restapi.RenderedConfig{},
+	}
+	for portName, port := range service.Ports {
+		restPort := &restapi.ServicePort{
+			Name:     portName,
+			Protocol: port.Protocol.String(),
+			Port:     port.Port,
+			NodePort: port.NodePort,
+			Backends: []*restapi.ServiceBackend{},
+		}
+		for _, backend := range service.Backends[portName] {
+			restPort.Backends = append(restPort.Backends, &restapi.ServiceBackend{
+				IP:          backend.IP.String(),
+				Port:        backend.Port,
+				Local:       backend.Local,
+				HostNetwork: backend.HostNetwork,
+				Weight:      backend.GetWeight(),
+			})
+		}
+		restSvc.Ports = append(restSvc.Ports, restPort)
+	}
+	sort.Slice(restSvc.Ports, func(i, j int) bool {
+		return restSvc.Ports[i].Name < restSvc.Ports[j].Name
+	})
+
+	for _, configRenderer := range p.configRenderers() {
+		for key, value := range configRenderer.GetServiceConfig(service) {
+			restSvc.Config = append(restSvc.Config, &restapi.RenderedConfig{
+				Key:   key,
+				Value: proto.CompactTextString(value),
+			})
+		}
+	}
+	sort.Slice(restSvc.Config, func(i, j int) bool {
+		return restSvc.Config[i].Key < restSvc.Config[j].Key
+	})
+	if p.nat44Renderer != nil {
+		restSvc.Errors = p.nat44Renderer.GetServiceErrors(service)
+	}
+	return restSvc
+}
+
+// configRenderers returns the active renderers able to describe the configuration
+// rendered for services.
+func (p *Plugin) configRenderers() (renderers []renderer.RenderedConfigAPI) {
+	if p.nat44Renderer != nil {
+		renderers = append(renderers, p.nat44Renderer)
+	}
+	if p.srv6Renderer != nil {
+		renderers = append(renderers, p.srv6Renderer)
+	}
+	if p.ipv6RouteRenderer != nil {
+		renderers = append(renderers, p.ipv6RouteRenderer)
+	}
+	return renderers
+}
+
+// ipStrings returns the set of IP addresses as a list of strings.
+func ipStrings(addrs *renderer.IPAddresses) []string {
+	ips := []string{}
+	for _, ip := range addrs.List() {
+		ips = append(ips, ip.String())
+	}
+	return ips
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restapi
+
+const (
+	// RESTPrefix is versioned prefix for REST urls.
+	RESTPrefix = "/contiv/v1/"
+
+	// RestURLServices is versioned URL for the REST endpoint describing services
+	// as processed and rendered on the current node.
+	// The output can be limited to a single service with the query parameters
+	// ServiceNamespaceParam and ServiceNameParam.
+	RestURLServices = RESTPrefix + "services"
+
+	// ServiceNamespaceParam is the name of the query parameter selecting namespace of the service.
+	ServiceNamespaceParam = "namespace"
+
+	// ServiceNameParam is the name of the query parameter selecting name of the service.
+	ServiceNameParam = "service"
+)
+
+// NodeServices represents the state of services on the current node.
+// It is exposed by the services REST handler.
+type NodeServices struct {
+	NodeName    string     `json:"nodeName"`
+	FrontendIfs []string   `json:"frontendIfs"` // interfaces connecting service clients
+	BackendIfs  []string   `json:"backendIfs"`  // interfaces connecting service endpoints
+	Services    []*Service `json:"services"`
+}
+
+// Service represents a service as processed by the service processor,
+// together with the configuration rendered for the service.
+type Service struct {
+	ID            string            `json:"id"`
+	TrafficPolicy string            `json:"trafficPolicy"`
+	LBMode        string            `json:"lbMode"`
+	ClusterIPs    []string          `json:"clusterIPs"`
+	ExternalIPs   []string          `json:"externalIPs,omitempty"`
+	LBIngressIPs  []string          `json:"lbIngressIPs,omitempty"`
+	Ports         []*ServicePort    `json:"ports"`
+	Config        []*RenderedConfig `json:"config"`
+	Errors        []string          `json:"errors,omitempty"` // ports that could not be rendered
+}
+
+// ServicePort is a port exposed by a service with its backends.
+type ServicePort struct {
+	Name     string            `json:"name"`
+	Protocol string            `json:"protocol"`
+	Port     uint16            `json:"port"`
+	NodePort uint16            `json:"nodePort,omitempty"`
+	Backends []*ServiceBackend `json:"backends"`
+}
+
+// ServiceBackend is a single backend (endpoint) of a service port.
+type ServiceBackend struct {
+	IP          string `json:"ip"`
+	Port        uint16 `json:"port"`
+	Local       bool   `json:"local"`
+	HostNetwork bool   `json:"hostNetwork,omitempty"`
+	Weight      uint32 `json:"weight"` // effective load-balancing weight
+}
+
+// RenderedConfig is an item of the configuration rendered for a service,
+// e.g. NAT44 static mappings or SRv6 policies and localsids.
+type RenderedConfig struct {
+	Key   string `json:"key"`
+	Value string `json:"value"` // configuration in the protobuf text format
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"github.com/contiv/vpp/plugins/service/renderer"
+)
+
+// API defines methods provided by the Service plugin for use by other plugins.
+type API interface {
+	// GetRestrictedFrontends returns frontends of services (LB ingress IPs and node
+	// ports) with access restricted to the loadBalancerSourceRanges.
+	// The access restriction itself is rendered by the policy plugin.
+	// The method is thread-safe.
+	GetRestrictedFrontends() []*renderer.RestrictedFrontend
+}
@@ -1,7 +1,15 @@
 package statscollector
 
-// API defines API of the stats collector plugin. Currently it only allows registering of a gauge.
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// API defines API of the stats collector plugin. It allows registering of gauges.
 type API interface {
 	// RegisterGaugeFunc registers a new gauge with specific name, help string and valueFunc to report status when invoked.
 	RegisterGaugeFunc(name string, help string, valueFunc func() float64)
+
+	// RegisterGaugeVec registers a new vector of gauges with specific name, help string and variable labels.
+	// The returned vector is nil if the statistics are not exported.
+	RegisterGaugeVec(name string, help string, labelNames []string) (*prometheus.GaugeVec, error)
 }
@@ -260,6 +260,31 @@ func (p *Plugin) RegisterGaugeFunc(name string, help string, valueFunc func() fl
 	}
 }
 
+// RegisterGaugeVec registers a new vector of gauges with specific name, help string and variable labels.
+// The returned vector is nil if the statistics are not exported.
+func (p *Plugin) RegisterGaugeVec(name string, help string, labelNames []string) (*prometheus.GaugeVec, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.Log.Debugf("Registering new gauge vector: %s", name)
+
+	if p.Prometheus == nil {
+		return nil, nil
+	}
+	vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: name,
+		Help: help,
+		ConstLabels: prometheus.Labels{
+			nodeLabel: p.ServiceLabel.GetAgentLabel(),
+		},
+	}, labelNames)
+	if err := p.Prometheus.Register(prometheusStatsPath, vec); err != nil {
+		p.Log.Errorf("failed to register %v metric %v", name, err)
+		return nil, err
+	}
+	return vec, nil
+}
+
 func (p *Plugin) addNewEntry(key string, data *vpp_interfaces.InterfaceState) (newEntry *stats, created bool) {
 	var (
 		err            error
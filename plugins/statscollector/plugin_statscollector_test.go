@@ -96,11 +96,28 @@ func TestStatsCollector(t *testing.T) {
 	t.Run("testPutExistingPodEntry", testPutExistingPodEntry)
 	t.Run("testPutNewContivEntry", testPutNewContivEntry)
 	t.Run("testIsContivSystemInterface", testIsContivSystemInterface)
+	t.Run("testRegisterGaugeVec", testRegisterGaugeVec)
 	//t.Run("testDeletePodEntry", testDeletePodEntry)
 
 	testVars.plugin.Close()
 }
 
+func testRegisterGaugeVec(t *testing.T) {
+	// check error handling if prometheus.Register returns error
+	testVars.pmts.injectRegisterFuncError(fmt.Errorf("%s", "Register Error"))
+	vec, err := testVars.plugin.RegisterGaugeVec("testGaugeVec", "Test gauge vector", []string{podNameLabel})
+	gomega.Expect(err).To(gomega.MatchError("Register Error"))
+	gomega.Expect(vec).To(gomega.BeNil())
+	testVars.pmts.injectRegisterFuncError(nil)
+
+	vec, err = testVars.plugin.RegisterGaugeVec("testGaugeVec", "Test gauge vector", []string{podNameLabel})
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(vec).ToNot(gomega.BeNil())
+	gauge, err := vec.GetMetricWith(prometheus.Labels{podNameLabel: "test-pod"})
+	gomega.Expect(err).To(gomega.BeNil())
+	gomega.Expect(gauge).ToNot(gomega.BeNil())
+}
+
 func testPutWithWrongArgumentType(t *testing.T) {
 	key := vpp_interfaces.StatePrefix + "stat1"
 
@@ -0,0 +1,26 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package perf contains, next to the scripts measuring the data-plane
+// performance with policies installed, benchmarks of the control-plane
+// policy processing in large clusters.
+//
+// The benchmarks run the policy cache, processor and configurator with
+// a renderer counting the rendered pods, and measure the cost of a single
+// pod or namespace label change for clusters of different sizes. With the
+// indexed lookups, the cost (and the number of re-rendered pods) depends
+// only on the size of the affected peer sets, not on the size of the cluster:
+//
+//	go test -run=^$ -bench=. ./tests/policy/perf/
+package perf
@@ -0,0 +1,326 @@
+// Copyright (c) 2019 Cisco and/or its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perf
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"go.ligato.io/cn-infra/v2/logging"
+	"go.ligato.io/cn-infra/v2/logging/logrus"
+
+	controller "github.com/contiv/vpp/plugins/controller/api"
+	nsmodel "github.com/contiv/vpp/plugins/ksr/model/namespace"
+	podmodel "github.com/contiv/vpp/plugins/ksr/model/pod"
+	policymodel "github.com/contiv/vpp/plugins/ksr/model/policy"
+	"github.com/contiv/vpp/plugins/policy/cache"
+	"github.com/contiv/vpp/plugins/policy/configurator"
+	"github.com/contiv/vpp/plugins/policy/processor"
+	"github.com/contiv/vpp/plugins/policy/renderer"
+)
+
+const (
+	// every application namespace has this many web and db pods
+	webPodsPerNamespace = 10
+	dbPodsPerNamespace  = 10
+
+	// application namespaces are grouped into tenants
+	namespacesPerTenant = 4
+
+	monitoringNamespace = "monitoring"
+	natLoopIP           = "10.255.255.254"
+)
+
+// clusterSizes are numbers of application namespaces used by the benchmarks.
+var clusterSizes = []int{10, 100, 1000}
+
+// cluster is an instance of the policy plugin (without the data-plane)
+// loaded with the state of a generated cluster.
+type cluster struct {
+	cache    *cache.PolicyCache
+	renderer *countingRenderer
+	pods     map[podmodel.ID]*podmodel.Pod
+	ns       map[string]*nsmodel.Namespace
+}
+
+// newCluster generates the state of a cluster with the given number of
+// application namespaces and loads it into the policy plugin (failing <tb>
+// if the state cannot be loaded):
+//   - every application namespace contains web and db pods
+//   - web pods accept connections from any pod of the namespace
+//   - db pods accept connections from web pods of the same namespace,
+//     from namespaces of the same tenant and from the monitoring namespace
+func newCluster(tb testing.TB, namespaces int) *cluster {
+	logger := logrus.NewLogger("perf")
+	logger.SetLevel(logging.ErrorLevel)
+
+	c := &cluster{
+		cache:    &cache.PolicyCache{Deps: cache.Deps{Log: logger}},
+		renderer: &countingRenderer{},
+		pods:     make(map[podmodel.ID]*podmodel.Pod),
+		ns:       make(map[string]*nsmodel.Namespace),
+	}
+	policyConfigurator := &configurator.PolicyConfigurator{
+		Deps: configurator.Deps{
+			Log:   logger,
+			Cache: c.cache,
+			IPAM:  ipamMock{},
+		},
+	}
+	policyProcessor := &processor.PolicyProcessor{
+		Deps: processor.Deps{
+			Log:          logger,
+			Cache:        c.cache,
+			Configurator: policyConfigurator,
+			AllNodes:     true,
+		},
+	}
+	c.cache.Init()
+	policyProcessor.Init()
+	policyConfigurator.Init(false)
+	policyConfigurator.RegisterRenderer(c.renderer)
+
+	state := controller.KubeStateData{
+		nsmodel.NamespaceKeyword:  controller.KeyValuePairs{},
+		podmodel.PodKeyword:       controller.KeyValuePairs{},
+		policymodel.PolicyKeyword: controller.KeyValuePairs{},
+	}
+	addNamespace := func(ns *nsmodel.Namespace) {
+		c.ns[ns.Name] = ns
+		state[nsmodel.NamespaceKeyword][nsmodel.Key(ns.Name)] = ns
+	}
+	addPod := func(pod *podmodel.Pod) {
+		pod.IpAddress = podIP(len(c.pods))
+		c.pods[podmodel.GetID(pod)] = pod
+		state[podmodel.PodKeyword][podmodel.Key(pod.Name, pod.Namespace)] = pod
+	}
+	addPolicy := func(policy *policymodel.Policy) {
+		state[policymodel.PolicyKeyword][policymodel.Key(policy.Name, policy.Namespace)] = policy
+	}
+
+	addNamespace(namespace(monitoringNamespace, "team", "monitoring"))
+	addPod(pod(monitoringNamespace, "prometheus", "app", "prometheus"))
+
+	for i := 0; i < namespaces; i++ {
+		nsName := fmt.Sprintf("app-%d", i)
+		tenant := fmt.Sprintf("tenant-%d", i/namespacesPerTenant)
+		addNamespace(namespace(nsName, "tenant", tenant))
+		for j := 0; j < webPodsPerNamespace; j++ {
+			addPod(pod(nsName, fmt.Sprintf("web-%d", j), "app", "web", "role", "frontend"))
+		}
+		for j := 0; j < dbPodsPerNamespace; j++ {
+			addPod(pod(nsName, fmt.Sprintf("db-%d", j), "app", "db"))
+		}
+		addPolicy(&policymodel.Policy{
+			Name:       "db-access",
+			Namespace:  nsName,
+			Pods:       selector("app", "db"),
+			PolicyType: policymodel.Policy_INGRESS,
+			IngressRule: []*policymodel.Policy_IngressRule{{
+				Port: []*policymodel.Policy_Port{tcpPort(5432)},
+				From: []*policymodel.Policy_Peer{
+					{Pods: selector("role", "frontend")},
+					{Namespaces: selector("tenant", tenant)},
+					{Namespaces: selector("team", "monitoring")},
+				},
+			}},
+		})
+		addPolicy(&policymodel.Policy{
+			Name:       "web-access",
+			Namespace:  nsName,
+			Pods:       selector("app", "web"),
+			PolicyType: policymodel.Policy_INGRESS,
+			IngressRule: []*policymodel.Policy_IngressRule{{
+				Port: []*policymodel.Policy_Port{tcpPort(80)},
+				From: []*policymodel.Policy_Peer{{Pods: &policymodel.Policy_LabelSelector{}}},
+			}},
+		})
+	}
+
+	if err := c.cache.Resync(state); err != nil {
+		tb.Fatal(err)
+	}
+	return c
+}
+
+// toggleFrontendLabel switches role of the web-0 pod from the app-0 namespace
+// between frontend and backend, changing the set of peers allowed to access
+// db pods of the namespace.
+func (c *cluster) toggleFrontendLabel() error {
+	podID := podmodel.ID{Name: "web-0", Namespace: "app-0"}
+	oldPod := c.pods[podID]
+	role := "backend"
+	if oldPod.Label[1].Value == role {
+		role = "frontend"
+	}
+	newPod := pod(podID.Namespace, podID.Name, "app", "web", "role", role)
+	newPod.IpAddress = oldPod.IpAddress
+	c.pods[podID] = newPod
+	return c.cache.Update(&controller.KubeStateChange{
+		Key:       podmodel.Key(podID.Name, podID.Namespace),
+		Resource:  podmodel.PodKeyword,
+		PrevValue: oldPod,
+		NewValue:  newPod,
+	})
+}
+
+// toggleTenantLabel moves the app-0 namespace out of its tenant and back,
+// changing the set of peers allowed to access db pods of the tenant.
+func (c *cluster) toggleTenantLabel() error {
+	oldNs := c.ns["app-0"]
+	tenant := "no-tenant"
+	if oldNs.Label[0].Value == tenant {
+		tenant = "tenant-0"
+	}
+	newNs := namespace(oldNs.Name, "tenant", tenant)
+	c.ns[oldNs.Name] = newNs
+	return c.cache.Update(&controller.KubeStateChange{
+		Key:       nsmodel.Key(oldNs.Name),
+		Resource:  nsmodel.NamespaceKeyword,
+		PrevValue: oldNs,
+		NewValue:  newNs,
+	})
+}
+
+// TestUpdateCost verifies that the number of pods re-rendered after a label
+// change does not depend on the size of the cluster.
+func TestUpdateCost(t *testing.T) {
+	gomega.RegisterTestingT(t)
+
+	var podLabelRenders, nsLabelRenders []int
+	for _, size := range []int{10, 100} {
+		c := newCluster(t, size)
+		gomega.Expect(c.renderer.rendered).To(gomega.Equal(size*(webPodsPerNamespace+dbPodsPerNamespace) + 1))
+
+		c.renderer.rendered = 0
+		gomega.Expect(c.toggleFrontendLabel()).To(gomega.Succeed())
+		podLabelRenders = append(podLabelRenders, c.renderer.rendered)
+
+		c.renderer.rendered = 0
+		gomega.Expect(c.toggleTenantLabel()).To(gomega.Succeed())
+		nsLabelRenders = append(nsLabelRenders, c.renderer.rendered)
+	}
+
+	// web pods including the pod itself (the pod is their peer) and db pods
+	// (the pod has been removed from their peers)
+	podsPerNamespace := webPodsPerNamespace + dbPodsPerNamespace
+	gomega.Expect(podLabelRenders).To(gomega.Equal([]int{podsPerNamespace, podsPerNamespace}))
+	// db pods of the tenant
+	tenantDBPods := namespacesPerTenant * dbPodsPerNamespace
+	gomega.Expect(nsLabelRenders).To(gomega.Equal([]int{tenantDBPods, tenantDBPods}))
+}
+
+func BenchmarkPodLabelChange(b *testing.B) {
+	for _, size := range clusterSizes {
+		b.Run(fmt.Sprintf("namespaces=%d", size), func(b *testing.B) {
+			c := newCluster(b, size)
+			c.renderer.rendered = 0
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := c.toggleFrontendLabel(); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(c.renderer.rendered)/float64(b.N), "renders/op")
+		})
+	}
+}
+
+func BenchmarkNamespaceLabelChange(b *testing.B) {
+	for _, size := range clusterSizes {
+		b.Run(fmt.Sprintf("namespaces=%d", size), func(b *testing.B) {
+			c := newCluster(b, size)
+			c.renderer.rendered = 0
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := c.toggleTenantLabel(); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(c.renderer.rendered)/float64(b.N), "renders/op")
+		})
+	}
+}
+
+// countingRenderer only counts the rendered pods.
+type countingRenderer struct {
+	rendered int
+}
+
+// NewTxn returns transaction counting the rendered pods.
+func (r *countingRenderer) NewTxn(resync bool) renderer.Txn {
+	return r
+}
+
+// Render increments the counter of rendered pods.
+func (r *countingRenderer) Render(pod podmodel.ID, podIP *net.IPNet, ingress []*renderer.ContivRule,
+	egress []*renderer.ContivRule, removed bool) renderer.Txn {
+	r.rendered++
+	return r
+}
+
+// Commit does nothing.
+func (r *countingRenderer) Commit() error {
+	return nil
+}
+
+// ipamMock implements the IPAM methods needed by the configurator.
+type ipamMock struct{}
+
+// NatLoopbackIP returns the IP address of the virtual NAT loopback.
+func (ipamMock) NatLoopbackIP() net.IP {
+	return net.ParseIP(natLoopIP)
+}
+
+func namespace(name string, labels ...string) *nsmodel.Namespace {
+	ns := &nsmodel.Namespace{Name: name}
+	for i := 0; i+1 < len(labels); i += 2 {
+		ns.Label = append(ns.Label, &nsmodel.Namespace_Label{Key: labels[i], Value: labels[i+1]})
+	}
+	return ns
+}
+
+func pod(namespace, name string, labels ...string) *podmodel.Pod {
+	pod := &podmodel.Pod{Name: name, Namespace: namespace}
+	for i := 0; i+1 < len(labels); i += 2 {
+		pod.Label = append(pod.Label, &podmodel.Pod_Label{Key: labels[i], Value: labels[i+1]})
+	}
+	return pod
+}
+
+// podIP returns unique IP address for the given pod index.
+func podIP(index int) string {
+	index++
+	return net.IPv4(10, byte(index>>16), byte(index>>8), byte(index)).String()
+}
+
+func selector(key, value string) *policymodel.Policy_LabelSelector {
+	return &policymodel.Policy_LabelSelector{
+		MatchLabel: []*policymodel.Policy_Label{{Key: key, Value: value}},
+	}
+}
+
+func tcpPort(port int32) *policymodel.Policy_Port {
+	return &policymodel.Policy_Port{
+		Protocol: policymodel.Policy_Port_TCP,
+		Port: &policymodel.Policy_Port_PortNameOrNumber{
+			Type:   policymodel.Policy_Port_PortNameOrNumber_NUMBER,
+			Number: port,
+		},
+	}
+}